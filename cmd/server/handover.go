@@ -0,0 +1,146 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+const (
+	// listenFDsStart is the first inherited descriptor in the systemd socket activation protocol
+	listenFDsStart = 3
+
+	adminFDName = "admin"
+
+	// handoverParentEnv tells a new process which old process to stop once it serves
+	handoverParentEnv = "EXRATE_HANDOVER_PARENT"
+)
+
+// handoverSignals trigger a graceful binary handover
+var handoverSignals = []os.Signal{syscall.SIGUSR2}
+
+// inheritedListeners returns sockets passed in by systemd socket activation or
+// by a previous process during a handover, split by LISTEN_FDNAMES into
+// public and admin listeners. ok is false when nothing was inherited.
+func inheritedListeners() (public, admin []net.Listener, ok bool, err error) {
+	if pid := os.Getenv("LISTEN_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil, false, nil
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil, nil, false, nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	// Children must not inherit the activation variables
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	for i := 0; i < count; i++ {
+		fd := listenFDsStart + i
+		syscall.CloseOnExec(fd)
+
+		file := os.NewFile(uintptr(fd), fmt.Sprintf("listen-fd-%d", fd))
+		l, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("inherited descriptor %d is not a listening socket: %w", fd, err)
+		}
+
+		if i < len(names) && names[i] == adminFDName {
+			admin = append(admin, l)
+		} else {
+			public = append(public, l)
+		}
+	}
+
+	return public, admin, true, nil
+}
+
+// handover starts a new copy of the binary that inherits all listeners using
+// the socket activation protocol. The old process keeps serving until the new
+// one is up and asks it to stop, so no connection is refused in between.
+func handover(public, admin []net.Listener, log *logger.Logger) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	files := make([]*os.File, 0, len(public)+len(admin))
+	names := make([]string, 0, len(public)+len(admin))
+	for _, group := range []struct {
+		name      string
+		listeners []net.Listener
+	}{{"public", public}, {adminFDName, admin}} {
+		for _, l := range group.listeners {
+			file, err := listenerFile(l)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			files = append(files, file)
+			names = append(names, group.name)
+		}
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		"LISTEN_FDS="+strconv.Itoa(len(files)),
+		"LISTEN_FDNAMES="+strings.Join(names, ":"),
+		handoverParentEnv+"="+strconv.Itoa(os.Getpid()),
+	)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// The sockets now belong to both processes; closing ours must not remove a Unix socket file
+	for _, l := range append(public, admin...) {
+		if unixListener, ok := l.(*net.UnixListener); ok {
+			unixListener.SetUnlinkOnClose(false)
+		}
+	}
+
+	log.Info("Started new process for handover", "pid", cmd.Process.Pid)
+	go cmd.Wait()
+	return nil
+}
+
+func listenerFile(l net.Listener) (*os.File, error) {
+	switch typed := l.(type) {
+	case *net.TCPListener:
+		return typed.File()
+	case *net.UnixListener:
+		return typed.File()
+	default:
+		return nil, fmt.Errorf("cannot hand over listener of type %T", l)
+	}
+}
+
+// finishHandover asks the previous process, if any, to shut down gracefully
+func finishHandover(log *logger.Logger) {
+	parent, err := strconv.Atoi(os.Getenv(handoverParentEnv))
+	os.Unsetenv(handoverParentEnv)
+	if err != nil || parent <= 1 {
+		return
+	}
+
+	if err := syscall.Kill(parent, syscall.SIGTERM); err != nil {
+		log.Error("Failed to stop previous process after handover", "pid", parent, "error", err)
+		return
+	}
+	log.Info("Handover complete, previous process is shutting down", "pid", parent)
+}
@@ -0,0 +1,23 @@
+//go:build !unix
+
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+var handoverSignals []os.Signal
+
+func inheritedListeners() (public, admin []net.Listener, ok bool, err error) {
+	return nil, nil, false, nil
+}
+
+func handover(public, admin []net.Listener, log *logger.Logger) error {
+	return errors.New("handover is not supported on this platform")
+}
+
+func finishHandover(log *logger.Logger) {}
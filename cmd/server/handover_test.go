@@ -0,0 +1,107 @@
+//go:build unix
+
+package main
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReusePortLetsTwoListenersShareAnAddress(t *testing.T) {
+	first, err := listen("127.0.0.1:0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := listen(first.Addr().String(), true)
+	if err != nil {
+		t.Fatalf("Expected SO_REUSEPORT to allow a second listener, got %v", err)
+	}
+	second.Close()
+
+	if _, err := listen(first.Addr().String(), false); err == nil {
+		t.Error("Expected a listener without SO_REUSEPORT to be refused")
+	}
+}
+
+// TestInheritedListeners passes a public and an admin socket to a copy of the
+// test binary the way handover does, and has it check what it inherited
+func TestInheritedListeners(t *testing.T) {
+	if os.Getenv("EXRATE_TEST_INHERIT") != "" {
+		inheritedListenersChild()
+		return
+	}
+
+	public, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer public.Close()
+	admin, err := net.Listen("unix", filepath.Join(t.TempDir(), "admin.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+
+	files := make([]*os.File, 0, 2)
+	for _, l := range []net.Listener{public, admin} {
+		file, err := listenerFile(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		files = append(files, file)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInheritedListeners$")
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		"EXRATE_TEST_INHERIT="+public.Addr().String()+" "+admin.Addr().String(),
+		"LISTEN_FDS=2",
+		"LISTEN_FDNAMES=public:"+adminFDName,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Child failed to inherit the listeners: %v\n%s", err, output)
+	}
+}
+
+func inheritedListenersChild() {
+	fail := func(message string) {
+		os.Stderr.WriteString(message + "\n")
+		os.Exit(1)
+	}
+
+	public, admin, ok, err := inheritedListeners()
+	if err != nil || !ok || len(public) != 1 || len(admin) != 1 {
+		fail("expected one public and one admin listener")
+	}
+	want := strings.Fields(os.Getenv("EXRATE_TEST_INHERIT"))
+	if public[0].Addr().String() != want[0] || admin[0].Addr().String() != want[1] {
+		fail("inherited listeners on the wrong addresses")
+	}
+	if os.Getenv("LISTEN_FDS") != "" {
+		fail("expected the activation variables to be cleared")
+	}
+}
+
+func TestInheritedListenersIgnoresOtherProcesses(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "2")
+	t.Setenv("LISTEN_PID", "1")
+
+	if _, _, ok, err := inheritedListeners(); ok || err != nil {
+		t.Errorf("Expected descriptors meant for another process to be ignored, got %v, %v", ok, err)
+	}
+}
+
+func TestListenerFileRejectsUnknownListeners(t *testing.T) {
+	if _, err := listenerFile(fakeListener{}); err == nil {
+		t.Error("Expected a listener without a descriptor to be refused")
+	}
+}
+
+type fakeListener struct{ net.Listener }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+// listen opens a listener for one configured address:
+//
+//	unix:/run/exrate.sock  Unix domain socket, a stale socket file is replaced
+//	tcp4:127.0.0.1:8080    IPv4 only
+//	tcp6:[::1]:8080        IPv6 only
+//	[::]:8080 or :8080     dual-stack TCP
+//
+// With reuse, TCP sockets set SO_REUSEPORT so another process can bind the
+// same address, e.g. a new release started next to the old one.
+func listen(addr string, reuse bool) (net.Listener, error) {
+	if path, found := strings.CutPrefix(addr, "unix:"); found {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+		return net.Listen("unix", path)
+	}
+
+	network := "tcp"
+	for _, prefix := range []string{"tcp4", "tcp6"} {
+		if rest, found := strings.CutPrefix(addr, prefix+":"); found {
+			network, addr = prefix, rest
+		}
+	}
+
+	var lc net.ListenConfig
+	if reuse {
+		lc.Control = reusePort
+	}
+	return lc.Listen(context.Background(), network, addr)
+}
+
+// listenAll opens every address, closing what was opened if one fails
+func listenAll(addrs []string, reuse bool) ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		l, err := listen(addr, reuse)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// serve runs server on every listener; server.Shutdown stops all of them
+func serve(name string, server *http.Server, listeners []net.Listener, log *logger.Logger) {
+	for _, l := range listeners {
+		go func(l net.Listener) {
+			log.Info("Starting "+name+" server", "network", l.Addr().Network(), "address", l.Addr().String())
+			if err := server.Serve(l); err != nil && err != http.ErrServerClosed {
+				log.Error(name+" server error", "error", err)
+				os.Exit(1)
+			}
+		}(l)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListen(t *testing.T) {
+	tests := []struct {
+		addr    string
+		network string
+	}{
+		{"127.0.0.1:0", "tcp"},
+		{"tcp4:127.0.0.1:0", "tcp"},
+		{"unix:" + filepath.Join(t.TempDir(), "exrate.sock"), "unix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			l, err := listen(tt.addr, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			if l.Addr().Network() != tt.network {
+				t.Errorf("Expected a %s listener, got %s", tt.network, l.Addr().Network())
+			}
+			if strings.HasPrefix(tt.addr, "tcp4:") && l.Addr().(*net.TCPAddr).IP.To4() == nil {
+				t.Errorf("Expected an IPv4 address, got %s", l.Addr())
+			}
+		})
+	}
+}
+
+func TestListenReplacesStaleUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exrate.sock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := listen("unix:"+path, false)
+	if err != nil {
+		t.Fatalf("Expected a stale socket file to be replaced, got %v", err)
+	}
+	defer l.Close()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Expected the socket to accept connections, got %v", err)
+	}
+	conn.Close()
+}
+
+func TestListenAllClosesOnFailure(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	path := filepath.Join(t.TempDir(), "exrate.sock")
+	if _, err := listenAll([]string{"unix:" + path, taken.Addr().String()}, false); err == nil {
+		t.Fatal("Expected an address in use to fail")
+	}
+	if _, err := net.Dial("unix", path); err == nil {
+		t.Error("Expected the listeners opened before the failure to be closed")
+	}
+}
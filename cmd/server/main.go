@@ -2,20 +2,47 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"exchange-rate-service/internal/adapter/amqp"
+	"exchange-rate-service/internal/adapter/auth"
 	"exchange-rate-service/internal/adapter/cache"
+	"exchange-rate-service/internal/adapter/chaos"
+	"exchange-rate-service/internal/adapter/cpi"
+	"exchange-rate-service/internal/adapter/fix"
 	httpRouter "exchange-rate-service/internal/adapter/http"
+	"exchange-rate-service/internal/adapter/mqtt"
 	"exchange-rate-service/internal/adapter/repository"
+	"exchange-rate-service/internal/adapter/resp"
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/adapter/webhook"
+	"exchange-rate-service/internal/adjust"
+	"exchange-rate-service/internal/anomaly"
+	"exchange-rate-service/internal/apply"
 	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/delivery"
+	"exchange-rate-service/internal/discrepancy"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
 	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/internal/refresh"
 	"exchange-rate-service/internal/service"
+	"exchange-rate-service/internal/simulation"
+	"exchange-rate-service/internal/watchdog"
+	"exchange-rate-service/pkg/clock"
 	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
 	
 	_ "github.com/prometheus/client_golang/prometheus"
 )
@@ -31,44 +58,757 @@ func main() {
 	}
 
 	appMetrics := metrics.NewMetrics()
+
+	// Chaos mode can run the service on a skewed clock to exercise cache
+	// expiry and date validation
+	appClock := clock.System
+	if cfg.Chaos.Enabled && cfg.Chaos.ClockSkew != 0 {
+		appClock = clock.Offset(clock.System, cfg.Chaos.ClockSkew)
+		log.Warn("Chaos: running on a skewed clock", "skew", cfg.Chaos.ClockSkew)
+	}
+
 	rateCache := cache.NewMemoryCache(cfg.Cache.TTL, log)
+	rateCache.UseClock(appClock)
+
+	// Snapshot entries are sealed with AES-GCM or signed when a key is configured
+	var cacheSnapshots interface {
+		LoadSnapshot(path string) (int, error)
+		RunSnapshots(ctx context.Context, path string, interval time.Duration)
+	} = rateCache
+	switch {
+	case cfg.Cache.EncryptionKey != nil:
+		encryptedCache, err := cache.NewEncryptedCache(rateCache, cfg.Cache.EncryptionKey)
+		if err != nil {
+			log.Error("Failed to set up cache encryption", "error", err)
+			os.Exit(1)
+		}
+		cacheSnapshots = encryptedCache
+		log.Info("Cache snapshot encryption enabled")
+	case cfg.Cache.SigningKey != "":
+		cacheSnapshots = cache.NewSignedCache(rateCache, []byte(cfg.Cache.SigningKey))
+		log.Info("Cache snapshot signing enabled")
+	}
+
+	// A fresh snapshot lets the service serve rates immediately without calling the provider
+	warmCache := false
+	if cfg.Cache.SnapshotFile != "" {
+		restored, err := cacheSnapshots.LoadSnapshot(cfg.Cache.SnapshotFile)
+		switch {
+		case errors.Is(err, cache.ErrSnapshotSignature) || errors.Is(err, cache.ErrSnapshotDecryption):
+			appMetrics.SnapshotVerificationFailures.Inc()
+			log.Error("Cache snapshot failed verification, starting with an empty cache", "path", cfg.Cache.SnapshotFile, "error", err)
+		case err != nil:
+			log.Error("Failed to load cache snapshot", "error", err)
+		}
+		warmCache = restored > 0
+	}
+
+	// A replica keeps what it records itself in memory only, so it never
+	// overwrites the files of the primary it shares them with
+	localFile := func(path string) string {
+		if cfg.Server.Replica() {
+			return ""
+		}
+		return path
+	}
+
+	slaStore, err := store.NewFileSLAStore(localFile(cfg.Storage.SLAFile), cfg.Storage.SLARetention, log)
+	if err != nil {
+		log.Error("Failed to load provider SLA data", "error", err)
+		os.Exit(1)
+	}
 
-	rateRepo := repository.NewExchangeAPI(
+	exchangeAPI := repository.NewExchangeAPI(
 		cfg.ExchangeAPI.BaseURL,
 		cfg.ExchangeAPI.APIKey,
 		cfg.ExchangeAPI.Timeout,
+		slaStore,
 		log,
 	)
+	exchangeAPI.UseClock(appClock)
+	if limit := cfg.ExchangeAPI.RateLimits[exchangeAPI.Name()]; limit.PerMinute > 0 {
+		exchangeAPI.UseRateLimit(newRateLimiter(limit, appClock))
+	}
+
+	// Regional endpoints of the primary provider are probed in the background
+	var endpointPool *repository.EndpointPool
+	if len(cfg.ExchangeAPI.Endpoints) > 0 || cfg.ExchangeAPI.EndpointsSRV != "" {
+		endpointPool = repository.NewEndpointPool(
+			exchangeAPI.Name(),
+			cfg.ExchangeAPI.Endpoints,
+			cfg.ExchangeAPI.EndpointsSRV,
+			cfg.ExchangeAPI.HealthCheckPath,
+			cfg.ExchangeAPI.Timeout,
+			log,
+		)
+		exchangeAPI.UseEndpoints(endpointPool)
+	}
+
+	// Historical responses are shared by all providers, keyed by URL
+	var responseCache *repository.ResponseCache
+	if cfg.ExchangeAPI.ResponseCacheSize > 0 {
+		responseCache = repository.NewResponseCache(cfg.ExchangeAPI.ResponseCacheSize)
+		exchangeAPI.UseResponseCache(responseCache)
+	}
+
+	// Providers are built from the registry in failover order, the primary
+	// first. The configured exchangerate.host is exchangeAPI itself, which
+	// backs the proxy whichever provider serves rates.
+	providers := make([]ports.RateRepository, 0, len(cfg.ExchangeAPI.Providers))
+	for _, provider := range cfg.ExchangeAPI.Providers {
+		if provider.Name == exchangeAPI.Name() && provider.BaseURL == cfg.ExchangeAPI.BaseURL {
+			providers = append(providers, exchangeAPI)
+			continue
+		}
+
+		rateProvider, err := repository.NewProvider(provider.Kind, repository.ProviderSettings{
+			Name:    provider.Name,
+			BaseURL: provider.BaseURL,
+			APIKey:  provider.APIKey,
+			Timeout: provider.Timeout,
+			Base:    provider.Base,
+
+			Simulation: repository.SimulationOptions{
+				Seed:              int64(provider.Simulation.Seed),
+				VolatilityPercent: provider.Simulation.VolatilityPercent,
+				Step:              provider.Simulation.Step,
+			},
+
+			RequestsPerMinute: provider.RateLimit.PerMinute,
+			Burst:             provider.RateLimit.Burst,
+
+			SLA:   slaStore,
+			Clock: appClock,
+			Log:   log,
+		})
+		if err != nil {
+			log.Error("Failed to set up provider", "provider", provider.Name, "error", err)
+			os.Exit(1)
+		}
+		if api, ok := rateProvider.(*repository.ExchangeAPI); ok && responseCache != nil {
+			api.UseResponseCache(responseCache)
+		}
+		providers = append(providers, rateProvider)
+	}
+
+	// The guardrail sits in the providers themselves, under any wrappers
+	guarded := slices.Clone(providers)
+
+	if cfg.Chaos.Enabled {
+		log.Warn("CHAOS MODE ENABLED: faults will be injected, do not use in production")
+		providers[0] = chaos.NewRepository(providers[0], chaos.Options{
+			Latency:       cfg.Chaos.UpstreamLatency,
+			ErrorRate:     cfg.Chaos.UpstreamErrorRate,
+			MalformedRate: cfg.Chaos.UpstreamMalformRate,
+		}, log)
+	}
+
+	// A provider that keeps failing is no longer called for a while, so
+	// requests fail at once or get the rates it last held. Breakers sit
+	// outside the chaos faults, which trip them like real ones.
+	if cfg.ExchangeAPI.Breaker.Failures > 0 {
+		for i, provider := range providers {
+			breaker := repository.NewCircuitBreaker(provider, repository.CircuitBreakerOptions{
+				Failures:          cfg.ExchangeAPI.Breaker.Failures,
+				OpenFor:           cfg.ExchangeAPI.Breaker.OpenFor,
+				HalfOpenSuccesses: cfg.ExchangeAPI.Breaker.HalfOpenSuccesses,
+			}, log)
+			breaker.UseClock(appClock)
+			breaker.OnChange(appMetrics.ObserveCircuitState)
+			appMetrics.ObserveCircuitState(provider.Name(), repository.BreakerClosed)
+			providers[i] = breaker
+		}
+	}
+
+	// Fallback providers take over, in order, when the primary one fails,
+	// unless the rates of all of them are combined. Chaos faults only hit the
+	// primary, so they exercise the failover or the aggregation.
+	rateRepo := providers[0]
+	if cfg.ExchangeAPI.Aggregation != "" {
+		aggregate, err := repository.NewProviderAggregate(providers, repository.ProviderAggregateOptions{
+			Strategy: cfg.ExchangeAPI.Aggregation,
+			Weights:  cfg.ExchangeAPI.ProviderWeights,
+			Timeout:  cfg.ExchangeAPI.Timeout,
+		}, log)
+		if err != nil {
+			log.Error("Failed to set up provider aggregation", "error", err)
+			os.Exit(1)
+		}
+		rateRepo = aggregate
+	} else if len(providers) > 1 {
+		chain, err := repository.NewProviderChain(providers, repository.ProviderChainOptions{
+			FailAfter: cfg.ExchangeAPI.FailoverAfter,
+			Cooldown:  cfg.ExchangeAPI.FailoverCooldown,
+			Timeout:   cfg.ExchangeAPI.Timeout,
+		}, log)
+		if err != nil {
+			log.Error("Failed to set up fallback providers", "error", err)
+			os.Exit(1)
+		}
+		chain.UseClock(appClock)
+		rateRepo = chain
+	}
+
+	// The secondary provider is only used to compare against the primary one
+	var secondaryRepo ports.RateRepository
+	if cfg.ExchangeAPI.SecondaryBaseURL != "" {
+		secondaryRepo = repository.NewNamedExchangeAPI(
+			cfg.ExchangeAPI.SecondaryName,
+			cfg.ExchangeAPI.SecondaryBaseURL,
+			cfg.ExchangeAPI.SecondaryAPIKey,
+			cfg.ExchangeAPI.Timeout,
+			slaStore,
+			log,
+		)
+		secondaryRepo.(*repository.ExchangeAPI).UseClock(appClock)
+		if limit := cfg.ExchangeAPI.RateLimits[cfg.ExchangeAPI.SecondaryName]; limit.PerMinute > 0 {
+			secondaryRepo.(*repository.ExchangeAPI).UseRateLimit(newRateLimiter(limit, appClock))
+		}
+		if responseCache != nil {
+			secondaryRepo.(*repository.ExchangeAPI).UseResponseCache(responseCache)
+		}
+	}
+
+	// A replica serves what the primary stores in the shared cache snapshot
+	// and history, and calls no provider, not even for comparisons
+	if cfg.Server.Replica() {
+		rateRepo = repository.NewReplica(rateRepo.Name())
+		secondaryRepo = nil
+		log.Info("Running as a read-only replica", "snapshot", cfg.Cache.SnapshotFile, "history", cfg.Storage.HistoryFile)
+	}
+
+	conversionStore := store.NewMemoryConversionStore(cfg.Conversion.ReceiptLimit, log)
+
+	annotationStore, err := store.NewFileAnnotationStore(cfg.Storage.AnnotationsFile, log)
+	if err != nil {
+		log.Error("Failed to load annotations", "error", err)
+		os.Exit(1)
+	}
+
+	historyStore, err := store.NewFileHistoryStore(cfg.Storage.HistoryFile, model.HistoryRetentionDays, log)
+	if err != nil {
+		log.Error("Failed to load historical rates", "error", err)
+		os.Exit(1)
+	}
+
+	tickStore, err := store.NewFileTickStore(localFile(cfg.Storage.TicksFile), cfg.Storage.TicksRetention, log)
+	if err != nil {
+		log.Error("Failed to load intraday ticks", "error", err)
+		os.Exit(1)
+	}
+
+	intradayPairs, err := parsePairs(cfg.Intraday.Pairs)
+	if err != nil {
+		log.Error("Invalid intraday pair configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Intraday sampling calls the provider too, so it counts against the quota
+	var intradayInterval time.Duration
+	if len(intradayPairs) > 0 {
+		intradayInterval = cfg.Intraday.SampleInterval
+	}
+	refreshFloor, err := refresh.QuotaFloor(cfg.ExchangeAPI.MonthlyQuota, intradayInterval)
+	if err != nil {
+		log.Error("Invalid provider quota configuration", "error", err)
+		os.Exit(1)
+	}
+	refreshSchedule, err := refresh.NewSchedule(cfg.ExchangeAPI.RefreshRate, refreshFloor, cfg.Storage.RefreshIntervalFile, log)
+	if err != nil {
+		log.Error("Invalid refresh interval", "error", err)
+		os.Exit(1)
+	}
+
+	currencyPolicy, err := newCurrencyPolicy(cfg.Currency)
+	if err != nil {
+		log.Error("Invalid currency pair configuration", "error", err)
+		os.Exit(1)
+	}
+
+	currencyAliases, err := newCurrencyAliases(cfg.Currency.AliasesFile)
+	if err != nil {
+		log.Error("Invalid currency alias configuration", "error", err)
+		os.Exit(1)
+	}
+
+	serviceOptions := []service.Option{
+		service.WithCache(rateCache),
+		service.WithLogger(log),
+		service.WithSecondary(secondaryRepo),
+		service.WithConversionStore(conversionStore),
+		service.WithAnnotationStore(annotationStore),
+		service.WithHistoryStore(historyStore),
+		service.WithSLAStore(slaStore),
+		service.WithTickStore(tickStore),
+		service.WithCurrencyPolicy(currencyPolicy),
+		service.WithClock(appClock),
+		service.WithCurrencyAliases(currencyAliases),
+		service.WithRateLimitCooldown(cfg.ExchangeAPI.RateLimitCooldown),
+		service.WithMaxRangeDays(cfg.ExchangeAPI.MaxRangeDays),
+	}
+
+	// The monthly quota is the primary provider's, so backfills can be held
+	// back before it runs out
+	if cfg.ExchangeAPI.MonthlyQuota > 0 {
+		serviceOptions = append(serviceOptions, service.WithUsageBudget(providers[0].Name(), cfg.ExchangeAPI.MonthlyQuota, cfg.ExchangeAPI.QuotaReservePercent))
+	}
+
+	providerLicenses, err := newProviderLicenses(cfg.ExchangeAPI.LicensesFile)
+	if err != nil {
+		log.Error("Invalid provider license configuration", "error", err)
+		os.Exit(1)
+	}
+	serviceOptions = append(serviceOptions, service.WithProviderLicenses(providerLicenses))
+
+	// Partial refreshes follow the pairs clients request most
+	var popularityStore *store.FilePopularityStore
+	if cfg.ExchangeAPI.FullRefreshEvery > 1 {
+		popularityStore, err = store.NewFilePopularityStore(localFile(cfg.Storage.PopularityFile), cfg.ExchangeAPI.PopularityHalfLife, log)
+		if err != nil {
+			log.Error("Failed to load pair popularity", "error", err)
+			os.Exit(1)
+		}
+		serviceOptions = append(serviceOptions, service.WithPairPopularity(popularityStore, cfg.ExchangeAPI.PopularPairs, cfg.ExchangeAPI.FullRefreshEvery))
+	}
+
+	var rateAdjuster *adjust.Adjuster
+	if cfg.Currency.AdjustmentsFile != "" {
+		rateAdjuster, err = adjust.NewAdjuster(cfg.Currency.AdjustmentsFile, log)
+		if err != nil {
+			log.Error("Failed to load rate adjustments", "error", err)
+			os.Exit(1)
+		}
+		serviceOptions = append(serviceOptions, service.WithRateAdjuster(rateAdjuster))
+	}
+	if cfg.Currency.CPIFile != "" {
+		cpiSource, err := cpi.NewFileSource(cfg.Currency.CPIFile, log)
+		if err != nil {
+			log.Error("Failed to load CPI data", "error", err)
+			os.Exit(1)
+		}
+		serviceOptions = append(serviceOptions, service.WithCPI(cpiSource))
+	}
+	// Cryptocurrencies are priced in USD by CoinGecko and crossed with the
+	// fiat rates of rateRepo
+	if cfg.ExchangeAPI.CryptoEnabled && !cfg.Server.Replica() {
+		coinGecko := repository.NewCoinGecko(cfg.ExchangeAPI.CoinGeckoBaseURL, cfg.ExchangeAPI.CoinGeckoAPIKey, cfg.ExchangeAPI.Timeout, slaStore, log)
+		coinGecko.UseClock(appClock)
+		if limit := cfg.ExchangeAPI.RateLimits[coinGecko.Name()]; limit.PerMinute > 0 {
+			coinGecko.UseRateLimit(newRateLimiter(limit, appClock))
+		}
+		serviceOptions = append(serviceOptions, service.WithCryptoRepository(coinGecko))
+	}
+	if cfg.Cache.SparklinePoints > 0 {
+		recentRates := cache.NewRingBuffer(cfg.Cache.SparklinePoints)
+		recentRates.UseClock(appClock)
+		serviceOptions = append(serviceOptions, service.WithRecentRates(recentRates))
+	}
+	serviceOptions = append(serviceOptions, service.WithPrecomputedAmounts(cfg.Conversion.PrecomputeAmounts))
+	if cfg.Conversion.CacheTTL > 0 {
+		serviceOptions = append(serviceOptions, service.WithConversionCache(cache.NewConversionCache(cfg.Conversion.CacheTTL, appMetrics)))
+	}
+	exchangeService := service.NewExchangeService(rateRepo, serviceOptions...)
+
+	// The FIX acceptor streams every rate the service publishes to subscribed sessions
+	var fixServer *fix.Server
+	if cfg.FIX.Listen != "" {
+		fixListener, err := listen(cfg.FIX.Listen, cfg.Server.ReusePort)
+		if err != nil {
+			log.Error("Failed to open FIX listener", "error", err)
+			os.Exit(1)
+		}
+		fixServer = fix.NewServer(exchangeService, cfg.FIX, appMetrics, log)
+		exchangeService.AddPublisher(fixServer)
+		go func() {
+			log.Info("Starting FIX acceptor", "address", fixListener.Addr().String(), "sender_comp_id", cfg.FIX.SenderCompID)
+			if err := fixServer.Serve(fixListener); err != nil {
+				log.Error("FIX acceptor error", "error", err)
+			}
+		}()
+	}
+
+	// Failed webhook and publisher deliveries are retried from the ledger
+	deliveryStore, err := store.NewFileDeliveryStore(localFile(cfg.Storage.DeliveriesFile), cfg.Delivery.Retention, log)
+	if err != nil {
+		log.Error("Failed to load delivery ledger", "error", err)
+		os.Exit(1)
+	}
+	deliveryLedger := delivery.NewLedger(deliveryStore, cfg.Delivery, appMetrics, log)
+
+	// The AMQP publisher connects lazily on the first publish
+	var amqpPublisher *amqp.Publisher
+	if cfg.AMQP.URL != "" {
+		amqpPublisher = amqp.NewPublisher(cfg.AMQP, log)
+		exchangeService.AddPublisher(deliveryLedger.Wrap("amqp", amqpPublisher))
+	}
+
+	var mqttPublisher *mqtt.Publisher
+	if cfg.MQTT.URL != "" {
+		mqttPublisher = mqtt.NewPublisher(cfg.MQTT, log)
+		exchangeService.AddPublisher(deliveryLedger.Wrap("mqtt", mqttPublisher))
+	}
+
+	handler := httpRouter.NewHandler(exchangeService, log, appMetrics, cfg.Server.TimestampFormat)
+	handler.MarkStaleAfter(cfg.Responses.StaleAfter)
+	if err := addResponseTransforms(handler, cfg.Responses); err != nil {
+		log.Error("Invalid response transform configuration", "error", err)
+		os.Exit(1)
+	}
+
+	var authenticator *auth.OIDCAuthenticator
+	if cfg.Auth.OIDC.Enabled {
+		authenticator = auth.NewOIDCAuthenticator(cfg.Auth.OIDC, log)
+		log.Info("OIDC authentication enabled", "issuer", cfg.Auth.OIDC.IssuerURL)
+	}
+
+	var apiKeys *auth.APIKeyAuthenticator
+	if len(cfg.Auth.APIKeys.Keys) > 0 {
+		apiKeys = auth.NewAPIKeyAuthenticator(cfg.Auth.APIKeys, log)
+		log.Info("API key authentication enabled", "keys", len(cfg.Auth.APIKeys.Keys), "widget_tokens", apiKeys.TokensEnabled())
+	}
+
+	// Outgoing webhooks are owned by API keys, so they need API key authentication
+	var webhookDispatcher *webhook.Dispatcher
+	if apiKeys != nil {
+		webhookStore, err := store.NewFileWebhookStore(cfg.Storage.WebhooksFile, cfg.Webhooks.DeliveryLogLimit, log)
+		if err != nil {
+			log.Error("Failed to load webhook subscriptions", "error", err)
+			os.Exit(1)
+		}
+		webhookDispatcher = webhook.NewDispatcher(webhookStore, cfg.Webhooks, appMetrics, log)
+		webhookDispatcher.UseLedger(deliveryLedger)
+		exchangeService.AddPublisher(webhookDispatcher)
+		if endpointPool != nil {
+			endpointPool.OnSwitch(webhookDispatcher.ProviderFailover)
+		}
+	}
+
+	// Abnormal moves are flagged on every publish and sent to webhook subscribers
+	var anomalyDetector *anomaly.Detector
+	if cfg.Anomalies.Threshold > 0 {
+		anomalyDetector = anomaly.NewDetector(cfg.Anomalies, appMetrics, log)
+		if webhookDispatcher != nil {
+			anomalyDetector.OnAnomaly(webhookDispatcher.AnomaliesDetected)
+		}
+		exchangeService.AddPublisher(anomalyDetector)
+	}
+
+	// Simulated orders belong to API keys too, and run on every published refresh
+	var simulator *simulation.Simulator
+	if apiKeys != nil {
+		simulationStore, err := store.NewFileSimulationStore(cfg.Storage.SimulationsFile, cfg.Storage.SimulationExecutionLimit, log)
+		if err != nil {
+			log.Error("Failed to load simulated orders", "error", err)
+			os.Exit(1)
+		}
+		simulator = simulation.NewSimulator(simulationStore, appMetrics, log)
+		exchangeService.AddPublisher(simulator)
+	}
+
+	// Baskets are stored per API key
+	if apiKeys != nil {
+		basketStore, err := store.NewFileBasketStore(cfg.Storage.BasketsFile, log)
+		if err != nil {
+			log.Error("Failed to load currency baskets", "error", err)
+			os.Exit(1)
+		}
+		exchangeService.UseBaskets(basketStore)
+	}
+
+	// Refreshes that move a quote too far are held back until the next one confirms them
+	if cfg.ExchangeAPI.MaxMovePercent > 0 {
+		onReject := func(moves []model.RateAlert) {
+			for _, move := range moves {
+				pair := model.CurrencyPair{BaseCurrency: move.BaseCurrency, TargetCurrency: move.TargetCurrency}
+				appMetrics.GuardrailRejections.WithLabelValues(pair.String()).Inc()
+				log.Warn("Rate move beyond the guardrail", "pair", pair.String(), "previous", move.PreviousRate, "rate", move.Rate, "change_percent", move.ChangePercent)
+			}
+			if webhookDispatcher != nil {
+				webhookDispatcher.GuardrailTripped(moves)
+			}
+		}
+		for _, provider := range guarded {
+			if guardrail, ok := provider.(interface {
+				GuardMoves(maxPercent float64, onReject func(moves []model.RateAlert))
+			}); ok {
+				guardrail.GuardMoves(cfg.ExchangeAPI.MaxMovePercent, onReject)
+			}
+		}
+	}
+
+	// The Redis protocol interface requires AUTH with an API key whenever the HTTP API does
+	var respServer *resp.Server
+	if cfg.RESP.Listen != "" {
+		respListener, err := listen(cfg.RESP.Listen, cfg.Server.ReusePort)
+		if err != nil {
+			log.Error("Failed to open RESP listener", "error", err)
+			os.Exit(1)
+		}
+		var keys resp.KeyValidator
+		if apiKeys != nil {
+			keys = apiKeys
+		}
+		respServer = resp.NewServer(exchangeService, keys, log)
+		go func() {
+			log.Info("Starting RESP server", "address", respListener.Addr().String())
+			if err := respServer.Serve(respListener); err != nil {
+				log.Error("RESP server error", "error", err)
+			}
+		}()
+	}
+
+	router := httpRouter.NewRouter(handler, log, appMetrics, authenticator, apiKeys)
+	if cfg.Server.Replica() {
+		router.ReadOnly()
+	}
+	router.AllowWidgetOrigins(cfg.Auth.APIKeys.WidgetOrigins)
+	router.AcceptProviderWebhooks(cfg.ExchangeAPI.WebhookSecrets)
+	if webhookDispatcher != nil {
+		router.ManageWebhooks(webhookDispatcher)
+		router.ManageAlerts(webhookDispatcher)
+	}
+	if simulator != nil {
+		router.SimulateOrders(simulator)
+	}
+
+	// Pairs, margins, alerts and API keys are managed together with
+	// declarative documents, each only when its feature is on
+	resources := apply.Resources{Pairs: exchangeService, MarginsChanged: rateCache.DropNamespaces}
+	if rateAdjuster != nil {
+		resources.Margins = rateAdjuster
+	}
+	if webhookDispatcher != nil {
+		resources.Alerts = webhookDispatcher
+	}
+	if apiKeys != nil {
+		resources.Keys = apiKeys
+	}
+	router.ApplyConfiguration(apply.NewApplier(resources, log))
+	if anomalyDetector != nil {
+		router.ReportAnomalies(anomalyDetector)
+	}
+	if cfg.Proxy.Enabled && !cfg.Server.Replica() {
+		router.ProxyProvider(exchangeAPI, cfg.Proxy)
+	}
 
-	exchangeService := service.NewExchangeService(rateRepo, rateCache, log)
-	handler := httpRouter.NewHandler(exchangeService, log, appMetrics)
+	var currentConfig atomic.Pointer[config.Config]
+	currentConfig.Store(cfg)
+	router.ExposeConfig(currentConfig.Load)
+	router.InspectDeliveries(deliveryLedger)
+	router.ScheduleRefreshes(refreshSchedule)
+	router.CacheResponses(cfg.ResponseCache)
+	if cfg.Chaos.Enabled {
+		router.Use(chaos.Middleware(chaos.Options{
+			Latency:   cfg.Chaos.HTTPLatency,
+			ErrorRate: cfg.Chaos.HTTPErrorRate,
+		}, log))
+	}
+
+	// Sockets passed by systemd or by a previous process take precedence over the configured addresses
+	listeners, adminListeners, inherited, err := inheritedListeners()
+	if err != nil {
+		log.Error("Failed to use inherited sockets", "error", err)
+		os.Exit(1)
+	}
+	if inherited {
+		log.Info("Using inherited sockets", "public", len(listeners), "admin", len(adminListeners))
+	} else {
+		listenAddrs := cfg.Server.Listen
+		if len(listenAddrs) == 0 {
+			listenAddrs = []string{fmt.Sprintf(":%d", cfg.Server.Port)}
+		}
+		listeners, err = listenAll(listenAddrs, cfg.Server.ReusePort)
+		if err != nil {
+			log.Error("Failed to open listeners", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if len(adminListeners) == 0 && len(cfg.Server.AdminListen) > 0 {
+		adminListeners, err = listenAll(cfg.Server.AdminListen, cfg.Server.ReusePort)
+		if err != nil {
+			log.Error("Failed to open admin listeners", "error", err)
+			os.Exit(1)
+		}
+	}
 
-	router := httpRouter.NewRouter(handler, log, appMetrics)
-	routes := router.SetupRoutes()
+	// With a dedicated admin listener the admin API is no longer served publicly
+	var adminServer *http.Server
+	if len(adminListeners) > 0 {
+		router.SeparateAdmin()
+		adminServer = &http.Server{
+			Handler:      router.SetupAdminRoutes(),
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		}
+	}
 
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      routes,
+		Handler:      router.SetupRoutes(),
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
 	ctx, cancelRefresh := context.WithCancel(context.Background())
-	go refreshRates(ctx, exchangeService, cfg.ExchangeAPI.RefreshRate, log)
+	if cfg.Server.Replica() {
+		go followPrimary(ctx, exchangeService, cacheSnapshots, historyStore, cfg.Cache.SnapshotFile, cfg.Cache.SnapshotInterval, appMetrics, log)
+	} else {
+		if endpointPool != nil {
+			go endpointPool.Run(ctx, cfg.ExchangeAPI.HealthCheckInterval)
+		}
+		go refreshRates(ctx, exchangeService, refreshSchedule, !warmCache, appMetrics, log)
+	}
 
+	// Background workers persist state; they get a final chance to flush after the server stops
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	workers.Add(1)
 	go func() {
-		log.Info("Starting HTTP server", "port", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error("HTTP server error", "error", err)
-			os.Exit(1)
-		}
+		defer workers.Done()
+		slaStore.Run(workersCtx, time.Minute)
+	}()
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		tickStore.Run(workersCtx, time.Minute)
+	}()
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		deliveryLedger.Run(workersCtx, 5*time.Second)
 	}()
 
-	// Wait for interrupt signal to gracefully shutdown the server
+	if popularityStore != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			popularityStore.Run(workersCtx, time.Minute)
+		}()
+	}
+
+	if len(intradayPairs) > 0 && !cfg.Server.Replica() {
+		go sampleIntraday(ctx, exchangeService, intradayPairs, cfg.Intraday.SampleInterval, log)
+	}
+
+	go observeCacheStats(ctx, exchangeService, appMetrics, 15*time.Second)
+	go observeProviderUsage(ctx, exchangeService, appMetrics, time.Minute, log)
+
+	if cfg.Memory.WatchdogEnabled {
+		limit := cfg.Memory.LimitBytes
+		if limit == 0 {
+			limit = watchdog.ContainerMemoryLimit()
+		}
+
+		if limit == 0 {
+			log.Info("Memory watchdog disabled, no memory limit configured or detected")
+		} else {
+			memoryWatchdog := watchdog.NewMemoryWatchdog(limit, cfg.Memory.HighWatermark, cfg.Memory.EvictFraction, appMetrics, log)
+			memoryWatchdog.Register("cache", rateCache)
+			memoryWatchdog.Register("history", historyStore)
+			memoryWatchdog.Register("conversions", conversionStore)
+			memoryWatchdog.Register("ticks", tickStore)
+			if responseCache != nil {
+				memoryWatchdog.Register("provider_responses", responseCache)
+			}
+
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				memoryWatchdog.Run(workersCtx, cfg.Memory.CheckInterval)
+			}()
+		}
+	}
+
+	if cfg.Discrepancies.Threshold > 0 && !cfg.Server.Replica() {
+		compared := providers
+		if secondaryRepo != nil {
+			compared = append(append([]ports.RateRepository{}, providers...), secondaryRepo)
+		}
+
+		if len(compared) < 2 {
+			log.Info("Provider discrepancy checks disabled, fewer than two providers configured")
+		} else {
+			checker := discrepancy.NewChecker(cfg.Discrepancies, compared, appMetrics, log)
+			workers.Add(1)
+			go func() {
+				defer workers.Done()
+				checker.Run(workersCtx, cfg.Discrepancies.Interval)
+			}()
+		}
+	}
+
+	if dirs := cfg.Dirs(); len(dirs) > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			err := config.Watch(workersCtx, dirs, func() {
+				reloadConfig(&currentConfig, cfg, apiKeys, log)
+			})
+			if err != nil {
+				log.Error("Failed to watch configuration directories", "error", err)
+			}
+		}()
+	}
+
+	// Rate adjustments are reloaded on change; an invalid edit keeps the previous rules.
+	// Rates adjusted with the old rules are dropped from the tenant cache namespaces.
+	if rateAdjuster != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			err := config.Watch(workersCtx, []string{filepath.Dir(cfg.Currency.AdjustmentsFile)}, func() {
+				if err := rateAdjuster.Reload(); err != nil {
+					log.Error("Ignoring invalid rate adjustments", "error", err)
+					return
+				}
+				rateCache.DropNamespaces()
+			})
+			if err != nil {
+				log.Error("Failed to watch rate adjustments", "error", err)
+			}
+		}()
+	}
+
+	// The snapshot of a replica is the primary's, which it only reads
+	if cfg.Cache.SnapshotFile != "" && !cfg.Server.Replica() {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			cacheSnapshots.RunSnapshots(workersCtx, cfg.Cache.SnapshotFile, cfg.Cache.SnapshotInterval)
+		}()
+	}
+
+	serve("HTTP", server, listeners, log)
+	if adminServer != nil {
+		serve("admin", adminServer, adminListeners, log)
+	}
+	finishHandover(log)
+
+	// Wait for interrupt signal to gracefully shutdown the server. A handover
+	// signal starts the new binary, which sends SIGTERM here once it serves.
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	restart := make(chan os.Signal, 1)
+	if len(handoverSignals) > 0 {
+		signal.Notify(restart, handoverSignals...)
+	}
+
+wait:
+	for {
+		select {
+		case <-restart:
+			if err := handover(listeners, adminListeners, log); err != nil {
+				log.Error("Handover failed, keeping the current process", "error", err)
+			}
+		case <-quit:
+			break wait
+		}
+	}
 	log.Info("Shutting down server...")
 
 	cancelRefresh()
@@ -76,24 +816,68 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	if fixServer != nil {
+		if err := fixServer.Close(); err != nil {
+			log.Error("Failed to close FIX acceptor", "error", err)
+		}
+	}
+
+	if respServer != nil {
+		if err := respServer.Close(); err != nil {
+			log.Error("Failed to close RESP server", "error", err)
+		}
+	}
+
+	if amqpPublisher != nil {
+		if err := amqpPublisher.Close(); err != nil {
+			log.Error("Failed to close AMQP connection", "error", err)
+		}
+	}
+
+	if mqttPublisher != nil {
+		if err := mqttPublisher.Close(); err != nil {
+			log.Error("Failed to close MQTT connection", "error", err)
+		}
+	}
+
+	if webhookDispatcher != nil {
+		webhookDispatcher.Close()
+	}
+
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			log.Error("Admin server forced to shutdown", "error", err)
+		}
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		log.Error("Server forced to shutdown", "error", err)
 		os.Exit(1)
 	}
 
+	stopWorkers()
+	workers.Wait()
+
 	log.Info("Server exited")
 }
 
-// refreshRates periodically refreshes exchange rates
-func refreshRates(ctx context.Context, service *service.ExchangeService, interval time.Duration, log *logger.Logger) {
-	// Refresh rates immediately at startup
-	if err := service.RefreshRates(ctx); err != nil {
-		log.Error("Failed to refresh rates at startup", "error", err)
+// refreshRates periodically refreshes exchange rates, following changes of
+// the schedule's interval
+func refreshRates(ctx context.Context, service *service.ExchangeService, schedule *refresh.Schedule, refreshNow bool, appMetrics *metrics.Metrics, log *logger.Logger) {
+	// Refresh rates immediately at startup unless the cache was restored warm
+	if refreshNow {
+		if err := service.RefreshRates(ctx); err != nil {
+			log.Error("Failed to refresh rates at startup", "error", err)
+		}
+	} else {
+		log.Info("Skipping startup refresh, cache restored from snapshot")
 	}
+	observeHistoryGaps(ctx, service, appMetrics, log)
 
 	// Create ticker for periodic refresh
-	ticker := time.NewTicker(interval)
+	ticker := time.NewTicker(schedule.Interval())
 	defer ticker.Stop()
+	paused := pauseWhileRateLimited(ctx, service, ticker, schedule, false, appMetrics)
 
 	for {
 		select {
@@ -101,9 +885,245 @@ func refreshRates(ctx context.Context, service *service.ExchangeService, interva
 			if err := service.RefreshRates(ctx); err != nil {
 				log.Error("Failed to refresh rates", "error", err)
 			}
+			observeHistoryGaps(ctx, service, appMetrics, log)
+			paused = pauseWhileRateLimited(ctx, service, ticker, schedule, paused, appMetrics)
+		case <-schedule.Changed():
+			if !paused {
+				ticker.Reset(schedule.Interval())
+			}
 		case <-ctx.Done():
 			log.Info("Stopping rate refresh goroutine")
 			return
 		}
 	}
 }
+
+// followPrimary reloads the cache snapshot and the history a primary saves,
+// every interval, in place of refreshing rates until ctx is cancelled
+func followPrimary(ctx context.Context, service *service.ExchangeService, snapshots interface {
+	LoadSnapshot(path string) (int, error)
+}, history *store.FileHistoryStore, path string, interval time.Duration, appMetrics *metrics.Metrics, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_, err := snapshots.LoadSnapshot(path)
+			if errors.Is(err, cache.ErrSnapshotSignature) || errors.Is(err, cache.ErrSnapshotDecryption) {
+				appMetrics.SnapshotVerificationFailures.Inc()
+			}
+			if err != nil {
+				log.Error("Failed to reload the primary's cache snapshot", "path", path, "error", err)
+			}
+			if err := history.Reload(); err != nil {
+				log.Error("Failed to reload the primary's historical rates", "error", err)
+			}
+			observeHistoryGaps(ctx, service, appMetrics, log)
+		case <-ctx.Done():
+			log.Info("Stopping replica reload goroutine")
+			return
+		}
+	}
+}
+
+// pauseWhileRateLimited holds the next refresh back until the provider's rate
+// limit cool-down ends, and goes back to the schedule's interval after it.
+// It reports whether the ticker is paused.
+func pauseWhileRateLimited(ctx context.Context, service *service.ExchangeService, ticker *time.Ticker, schedule *refresh.Schedule, paused bool, appMetrics *metrics.Metrics) bool {
+	limit := service.ProviderRateLimit(ctx)
+	appMetrics.ObserveRateLimit(limit)
+
+	switch {
+	case limit.Limited:
+		ticker.Reset(limit.Remaining)
+		return true
+	case paused:
+		ticker.Reset(schedule.Interval())
+	}
+	return false
+}
+
+// observeCacheStats keeps the rate cache metrics current until ctx is cancelled
+func observeCacheStats(ctx context.Context, service *service.ExchangeService, appMetrics *metrics.Metrics, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			appMetrics.ObserveCacheStats(service.CacheStats(ctx))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// observeProviderUsage keeps the provider usage metrics current until ctx is cancelled
+func observeProviderUsage(ctx context.Context, service *service.ExchangeService, appMetrics *metrics.Metrics, interval time.Duration, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			usage, err := service.ProviderUsage(ctx)
+			if err != nil {
+				log.Error("Failed to count provider usage", "error", err)
+				continue
+			}
+			appMetrics.ObserveProviderUsage(usage)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sampleIntraday records intraday ticks for pairs until ctx is cancelled
+func sampleIntraday(ctx context.Context, service *service.ExchangeService, pairs []model.CurrencyPair, interval time.Duration, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := service.SampleIntraday(ctx, pairs); err != nil {
+				log.Error("Failed to sample intraday rates", "error", err)
+			}
+		case <-ctx.Done():
+			log.Info("Stopping intraday sampling goroutine")
+			return
+		}
+	}
+}
+
+// parsePairs parses a list of pairs in the USD-INR form
+func parsePairs(values []string) ([]model.CurrencyPair, error) {
+	pairs := make([]model.CurrencyPair, 0, len(values))
+	for _, s := range values {
+		pair, err := model.ParseCurrencyPair(s)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// newRateLimiter paces the calls to a provider as limit configures
+func newRateLimiter(limit config.RateLimitConfig, c clock.Clock) *repository.RateLimiter {
+	limiter := repository.NewRateLimiter(limit.PerMinute, limit.Burst)
+	limiter.UseClock(c)
+	return limiter
+}
+
+// newCurrencyPolicy parses the configured allow and deny lists
+func newCurrencyPolicy(cfg config.CurrencyConfig) (model.CurrencyPolicy, error) {
+	policy := model.CurrencyPolicy{Matching: model.CurrencyMatching(cfg.Matching)}
+
+	var err error
+	if policy.AllowedPairs, err = parsePairs(cfg.AllowedPairs); err != nil {
+		return policy, err
+	}
+	if policy.DeniedPairs, err = parsePairs(cfg.DeniedPairs); err != nil {
+		return policy, err
+	}
+
+	return policy, nil
+}
+
+// addResponseTransforms applies the configured field transforms and then the
+// named ones, which are registered with httpRouter.RegisterTransform
+func addResponseTransforms(handler *httpRouter.Handler, cfg config.ResponseConfig) error {
+	if len(cfg.StripFields) > 0 {
+		handler.AddTransform(httpRouter.StripFields(cfg.StripFields))
+	}
+	if len(cfg.InjectFields) > 0 {
+		handler.AddTransform(httpRouter.InjectFields(cfg.InjectFields))
+	}
+
+	for _, name := range cfg.Transforms {
+		transform, err := httpRouter.LookupTransform(name)
+		if err != nil {
+			return err
+		}
+		handler.AddTransform(transform)
+	}
+	return nil
+}
+
+// newCurrencyAliases adds the aliases in path, if any, to the built-in ones.
+// Aliases from the file replace built-in aliases of the same code.
+func newCurrencyAliases(path string) (model.CurrencyAliases, error) {
+	aliases := maps.Clone(model.DefaultCurrencyAliases)
+	if path != "" {
+		configured := make(model.CurrencyAliases)
+		if err := utils.ReadJSONFile(path, &configured); err != nil {
+			return nil, err
+		}
+		maps.Copy(aliases, configured)
+	}
+
+	if err := aliases.Validate(); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+func newProviderLicenses(path string) (model.ProviderLicenses, error) {
+	licenses := make(model.ProviderLicenses)
+	if path != "" {
+		if err := utils.ReadJSONFile(path, &licenses); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := licenses.Validate(); err != nil {
+		return nil, err
+	}
+	return licenses, nil
+}
+
+// reloadConfig re-reads the configuration after a mounted ConfigMap or Secret
+// changed. API keys take effect immediately; anything else that differs from
+// the startup configuration is flagged as pending a restart.
+func reloadConfig(current *atomic.Pointer[config.Config], startup *config.Config, apiKeys *auth.APIKeyAuthenticator, log *logger.Logger) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Error("Ignoring invalid configuration update", "error", err)
+		return
+	}
+
+	previous := current.Load()
+	if len(cfg.ChangedSettings(previous)) == 0 {
+		return
+	}
+
+	if apiKeys != nil && cfg.Setting("API_KEYS") != previous.Setting("API_KEYS") {
+		apiKeys.ReloadKeys(cfg.Auth.APIKeys.Keys)
+		log.Info("Reloaded API keys", "keys", len(apiKeys.Keys()))
+	}
+
+	restartNeeded := make([]string, 0)
+	for _, key := range cfg.ChangedSettings(startup) {
+		if key != "API_KEYS" || apiKeys == nil {
+			restartNeeded = append(restartNeeded, key)
+		}
+	}
+	cfg.MarkPendingRestart(restartNeeded)
+	current.Store(cfg)
+
+	if len(restartNeeded) > 0 {
+		log.Warn("Configuration changed, restart required to apply", "keys", restartNeeded)
+	}
+}
+
+// observeHistoryGaps keeps the history completeness metrics current
+func observeHistoryGaps(ctx context.Context, service *service.ExchangeService, appMetrics *metrics.Metrics, log *logger.Logger) {
+	report, err := service.HistoryGaps(ctx, nil)
+	if err != nil {
+		log.Error("Failed to compute history gaps", "error", err)
+		return
+	}
+	appMetrics.ObserveHistoryGaps(report)
+}
@@ -0,0 +1,349 @@
+package amqp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// publishChannel is the only channel the publisher opens
+const publishChannel = 1
+
+// handshakeTimeout bounds the connection handshake after dialing
+const handshakeTimeout = 10 * time.Second
+
+var errConnectionClosed = errors.New("AMQP connection closed")
+
+type confirm struct {
+	tag      uint64
+	multiple bool
+	ack      bool
+}
+
+// connection is an AMQP 0-9-1 connection with one channel in confirm mode.
+// A reader goroutine delivers confirms; once the connection fails, done is
+// closed and err holds the cause.
+type connection struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	writeMu   sync.Mutex
+	frameMax  uint32
+	heartbeat time.Duration
+
+	// nextTag is the delivery tag of the next publish, guarded by the publisher
+	nextTag  uint64
+	confirms chan confirm
+
+	done     chan struct{}
+	failOnce sync.Once
+	err      error
+}
+
+// dial connects to an amqp:// or amqps:// URL, authenticating with PLAIN, and
+// declares the given exchanges as durable exchanges of kind
+func dial(ctx context.Context, rawURL string, exchanges []string, kind string) (*connection, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AMQP URL: %w", err)
+	}
+
+	port := "5672"
+	switch u.Scheme {
+	case "amqp":
+	case "amqps":
+		port = "5671"
+	default:
+		return nil, fmt.Errorf("unsupported AMQP URL scheme %q", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	username, password := "guest", "guest"
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	vhost := "/"
+	if path := strings.TrimPrefix(u.EscapedPath(), "/"); path != "" {
+		if vhost, err = url.PathUnescape(path); err != nil {
+			return nil, fmt.Errorf("invalid AMQP vhost: %w", err)
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "amqps" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	c := &connection{
+		conn:     conn,
+		reader:   bufio.NewReader(conn),
+		frameMax: frameMaxPreferred,
+		nextTag:  1,
+		confirms: make(chan confirm, 64),
+		done:     make(chan struct{}),
+	}
+
+	deadline := time.Now().Add(handshakeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	if err := c.handshake(username, password, vhost); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	for _, exchange := range exchanges {
+		if err := c.declare(exchange, kind); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to declare exchange %q: %w", exchange, err)
+		}
+	}
+	conn.SetDeadline(time.Time{})
+
+	go c.readLoop()
+	if c.heartbeat > 0 {
+		go c.heartbeatLoop()
+	}
+	return c, nil
+}
+
+func (c *connection) handshake(username, password, vhost string) error {
+	if _, err := c.conn.Write(protocolHeader); err != nil {
+		return err
+	}
+
+	if _, err := c.expect(0, connectionStart); err != nil {
+		return err
+	}
+	startOk := newMethod(connectionStartOk).
+		table(map[string]interface{}{
+			"product": "exchange-rate-service",
+			"capabilities": map[string]interface{}{
+				"publisher_confirms": true,
+			},
+		}).
+		shortstr("PLAIN").
+		longstr("\x00" + username + "\x00" + password).
+		shortstr("en_US")
+	if err := c.sendMethod(0, startOk); err != nil {
+		return err
+	}
+
+	d, err := c.expect(0, connectionTune)
+	if err != nil {
+		return err
+	}
+	channelMax, frameMax, heartbeat := d.short(), d.long(), d.short()
+	if frameMax != 0 && frameMax < c.frameMax {
+		c.frameMax = frameMax
+	}
+	c.heartbeat = time.Duration(heartbeat) * time.Second
+	tuneOk := newMethod(connectionTuneOk).short(channelMax).long(c.frameMax).short(heartbeat)
+	if err := c.sendMethod(0, tuneOk); err != nil {
+		return err
+	}
+
+	if err := c.sendMethod(0, newMethod(connectionOpen).shortstr(vhost).shortstr("").octet(0)); err != nil {
+		return err
+	}
+	if _, err := c.expect(0, connectionOpenOk); err != nil {
+		return err
+	}
+
+	if err := c.sendMethod(publishChannel, newMethod(channelOpen).shortstr("")); err != nil {
+		return err
+	}
+	if _, err := c.expect(publishChannel, channelOpenOk); err != nil {
+		return err
+	}
+
+	if err := c.sendMethod(publishChannel, newMethod(confirmSelect).octet(0)); err != nil {
+		return err
+	}
+	_, err = c.expect(publishChannel, confirmSelectOk)
+	return err
+}
+
+// declare declares a durable exchange. Like the handshake it reads the reply
+// directly, so it runs before the read loop starts.
+func (c *connection) declare(name, kind string) error {
+	// Bits: passive, durable, auto-delete, internal, no-wait
+	declare := newMethod(exchangeDeclare).short(0).shortstr(name).shortstr(kind).octet(1 << 1).table(nil)
+	if err := c.sendMethod(publishChannel, declare); err != nil {
+		return err
+	}
+	_, err := c.expect(publishChannel, exchangeDeclareOk)
+	return err
+}
+
+// expect reads the next method frame during the handshake and fails unless it
+// is want
+func (c *connection) expect(channel uint16, want methodID) (*decoder, error) {
+	for {
+		f, err := readFrame(c.reader, c.frameMax)
+		if err != nil {
+			return nil, err
+		}
+		if f.typ == frameHeartbeat {
+			continue
+		}
+		if f.typ != frameMethod {
+			return nil, fmt.Errorf("%w: unexpected frame type %d", errMalformed, f.typ)
+		}
+
+		id, d := method(f.payload)
+		switch {
+		case id == want && f.channel == channel:
+			return d, d.err
+		case id == connectionClose:
+			c.sendMethod(0, newMethod(connectionCloseOk))
+			return nil, closeError("connection", d)
+		case id == channelClose:
+			c.sendMethod(f.channel, newMethod(channelCloseOk))
+			return nil, closeError("channel", d)
+		default:
+			return nil, fmt.Errorf("%w: expected method %d.%d, got %d.%d", errMalformed, want.class, want.method, id.class, id.method)
+		}
+	}
+}
+
+func (c *connection) sendMethod(channel uint16, m *args) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeFrame(c.conn, frameMethod, channel, m.Bytes())
+}
+
+// publish sends one persistent message and returns its delivery tag. The
+// method, header and body frames are written under one lock so they are not
+// interleaved with heartbeats.
+func (c *connection) publish(exchange, routingKey, contentType string, body []byte) (uint64, error) {
+	publish := newMethod(basicPublish).short(0).shortstr(exchange).shortstr(routingKey).octet(0)
+
+	header := &args{}
+	header.short(classBasic).short(0).longlong(uint64(len(body))).
+		short(propContentType | propDeliveryMode | propTimestamp | propAppID).
+		shortstr(contentType).
+		octet(2).
+		longlong(uint64(time.Now().Unix())).
+		shortstr("exchange-rate-service")
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if err := writeFrame(c.conn, frameMethod, publishChannel, publish.Bytes()); err != nil {
+		return 0, err
+	}
+	if err := writeFrame(c.conn, frameHeader, publishChannel, header.Bytes()); err != nil {
+		return 0, err
+	}
+	chunk := int(c.frameMax) - 8
+	for len(body) > 0 {
+		n := min(chunk, len(body))
+		if err := writeFrame(c.conn, frameBody, publishChannel, body[:n]); err != nil {
+			return 0, err
+		}
+		body = body[n:]
+	}
+
+	tag := c.nextTag
+	c.nextTag++
+	return tag, nil
+}
+
+func (c *connection) readLoop() {
+	for {
+		if c.heartbeat > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(2 * c.heartbeat))
+		}
+		f, err := readFrame(c.reader, c.frameMax)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		if f.typ != frameMethod {
+			continue
+		}
+
+		id, d := method(f.payload)
+		switch id {
+		case basicAck, basicNack:
+			conf := confirm{tag: d.longlong(), multiple: d.octet()&1 != 0, ack: id == basicAck}
+			select {
+			case c.confirms <- conf:
+			case <-c.done:
+				return
+			}
+		case connectionClose:
+			c.sendMethod(0, newMethod(connectionCloseOk))
+			c.fail(closeError("connection", d))
+			return
+		case channelClose:
+			c.sendMethod(f.channel, newMethod(channelCloseOk))
+			c.fail(closeError("channel", d))
+			return
+		}
+	}
+}
+
+func (c *connection) heartbeatLoop() {
+	ticker := time.NewTicker(c.heartbeat / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.writeMu.Lock()
+			err := writeFrame(c.conn, frameHeartbeat, 0, nil)
+			c.writeMu.Unlock()
+			if err != nil {
+				c.fail(err)
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// fail records the first error, closes the socket and wakes up waiters
+func (c *connection) fail(err error) {
+	c.failOnce.Do(func() {
+		if err == nil {
+			err = errConnectionClosed
+		}
+		c.err = err
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// close asks the broker to close the connection, giving it a moment to flush
+// confirms, then drops the socket
+func (c *connection) close() {
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.sendMethod(0, newMethod(connectionClose).short(200).shortstr("shutdown").short(0).short(0))
+	select {
+	case <-c.done:
+	case <-time.After(time.Second):
+	}
+	c.fail(errConnectionClosed)
+}
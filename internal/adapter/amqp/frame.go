@@ -0,0 +1,226 @@
+package amqp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	frameMethod    = 1
+	frameHeader    = 2
+	frameBody      = 3
+	frameHeartbeat = 8
+	frameEnd       = 0xCE
+
+	// frameMaxPreferred is the largest frame we ask the broker for
+	frameMaxPreferred = 128 << 10
+)
+
+var protocolHeader = []byte{'A', 'M', 'Q', 'P', 0, 0, 9, 1}
+
+// Class and method IDs used by the publisher
+const (
+	classConnection = 10
+	classChannel    = 20
+	classExchange   = 40
+	classBasic      = 60
+	classConfirm    = 85
+)
+
+type methodID struct{ class, method uint16 }
+
+var (
+	connectionStart   = methodID{classConnection, 10}
+	connectionStartOk = methodID{classConnection, 11}
+	connectionTune    = methodID{classConnection, 30}
+	connectionTuneOk  = methodID{classConnection, 31}
+	connectionOpen    = methodID{classConnection, 40}
+	connectionOpenOk  = methodID{classConnection, 41}
+	connectionClose   = methodID{classConnection, 50}
+	connectionCloseOk = methodID{classConnection, 51}
+	channelOpen       = methodID{classChannel, 10}
+	channelOpenOk     = methodID{classChannel, 11}
+	channelClose      = methodID{classChannel, 40}
+	channelCloseOk    = methodID{classChannel, 41}
+	exchangeDeclare   = methodID{classExchange, 10}
+	exchangeDeclareOk = methodID{classExchange, 11}
+	basicPublish      = methodID{classBasic, 40}
+	basicAck          = methodID{classBasic, 80}
+	basicNack         = methodID{classBasic, 120}
+	confirmSelect     = methodID{classConfirm, 10}
+	confirmSelectOk   = methodID{classConfirm, 11}
+)
+
+// Basic content property flags, in the order the values are encoded
+const (
+	propContentType  = 1 << 15
+	propDeliveryMode = 1 << 12
+	propTimestamp    = 1 << 6
+	propAppID        = 1 << 3
+)
+
+var errMalformed = errors.New("malformed AMQP frame")
+
+type frame struct {
+	typ     byte
+	channel uint16
+	payload []byte
+}
+
+func writeFrame(w io.Writer, typ byte, channel uint16, payload []byte) error {
+	buf := make([]byte, 7, 8+len(payload))
+	buf[0] = typ
+	binary.BigEndian.PutUint16(buf[1:3], channel)
+	binary.BigEndian.PutUint32(buf[3:7], uint32(len(payload)))
+	buf = append(buf, payload...)
+	buf = append(buf, frameEnd)
+	_, err := w.Write(buf)
+	return err
+}
+
+// readFrame reads one frame, refusing payloads larger than frameMax
+func readFrame(r *bufio.Reader, frameMax uint32) (frame, error) {
+	var header [7]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return frame{}, err
+	}
+	size := binary.BigEndian.Uint32(header[3:7])
+	if size > frameMax {
+		return frame{}, fmt.Errorf("%w: frame of %d bytes exceeds frame-max %d", errMalformed, size, frameMax)
+	}
+
+	payload := make([]byte, size+1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return frame{}, err
+	}
+	if payload[size] != frameEnd {
+		return frame{}, fmt.Errorf("%w: missing frame end", errMalformed)
+	}
+
+	return frame{
+		typ:     header[0],
+		channel: binary.BigEndian.Uint16(header[1:3]),
+		payload: payload[:size],
+	}, nil
+}
+
+// args builds method arguments and content headers
+type args struct {
+	bytes.Buffer
+}
+
+func newMethod(id methodID) *args {
+	a := &args{}
+	a.short(id.class)
+	a.short(id.method)
+	return a
+}
+
+func (a *args) octet(v byte) *args {
+	a.WriteByte(v)
+	return a
+}
+
+func (a *args) short(v uint16) *args {
+	a.Write(binary.BigEndian.AppendUint16(nil, v))
+	return a
+}
+
+func (a *args) long(v uint32) *args {
+	a.Write(binary.BigEndian.AppendUint32(nil, v))
+	return a
+}
+
+func (a *args) longlong(v uint64) *args {
+	a.Write(binary.BigEndian.AppendUint64(nil, v))
+	return a
+}
+
+func (a *args) shortstr(s string) *args {
+	if len(s) > 255 {
+		s = s[:255]
+	}
+	a.WriteByte(byte(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+func (a *args) longstr(s string) *args {
+	a.long(uint32(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+// table encodes a field table holding strings, booleans and nested tables
+func (a *args) table(t map[string]interface{}) *args {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields args
+	for _, key := range keys {
+		fields.shortstr(key)
+		switch v := t[key].(type) {
+		case string:
+			fields.octet('S').longstr(v)
+		case bool:
+			b := byte(0)
+			if v {
+				b = 1
+			}
+			fields.octet('t').octet(b)
+		case map[string]interface{}:
+			fields.octet('F').table(v)
+		}
+	}
+
+	a.long(uint32(fields.Len()))
+	a.Write(fields.Bytes())
+	return a
+}
+
+// decoder reads method arguments. The first error sticks and later reads
+// return zero values.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil || n > len(d.data) {
+		if d.err == nil {
+			d.err = fmt.Errorf("%w: short method arguments", errMalformed)
+		}
+		return make([]byte, n)
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) octet() byte      { return d.take(1)[0] }
+func (d *decoder) short() uint16    { return binary.BigEndian.Uint16(d.take(2)) }
+func (d *decoder) long() uint32     { return binary.BigEndian.Uint32(d.take(4)) }
+func (d *decoder) longlong() uint64 { return binary.BigEndian.Uint64(d.take(8)) }
+func (d *decoder) shortstr() string { return string(d.take(int(d.octet()))) }
+
+// method splits a method frame payload into its ID and arguments
+func method(payload []byte) (methodID, *decoder) {
+	d := &decoder{data: payload}
+	id := methodID{d.short(), d.short()}
+	return id, d
+}
+
+// closeError describes a Connection.Close or Channel.Close sent by the broker
+func closeError(kind string, d *decoder) error {
+	code := d.short()
+	text := d.shortstr()
+	return fmt.Errorf("broker closed %s: %d %s", kind, code, text)
+}
@@ -0,0 +1,186 @@
+// Package amqp publishes fresh rates to a RabbitMQ exchange over AMQP 0-9-1.
+// Publishing uses confirm mode, so Publish only returns once the broker has
+// taken responsibility for every message.
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+type route struct {
+	exchange   string
+	routingKey string
+}
+
+// Publisher implements ports.RatePublisher. It connects lazily and reconnects
+// on the next publish after a failure.
+type Publisher struct {
+	cfg    config.AMQPConfig
+	routes map[string]route
+	log    *logger.Logger
+
+	mutex sync.Mutex
+	conn  *connection
+}
+
+func NewPublisher(cfg config.AMQPConfig, log *logger.Logger) *Publisher {
+	routes := make(map[string]route, len(cfg.PairRoutes))
+	for pair, value := range cfg.PairRoutes {
+		r := route{exchange: cfg.Exchange, routingKey: value}
+		if exchange, key, found := strings.Cut(value, "/"); found {
+			r = route{exchange: exchange, routingKey: key}
+		}
+		routes[strings.ToUpper(pair)] = r
+	}
+
+	return &Publisher{
+		cfg:    cfg,
+		routes: routes,
+		log:    log,
+	}
+}
+
+// route returns the exchange and routing key for a rate, with {base} and
+// {target} expanded
+func (p *Publisher) route(rate model.ExchangeRate) route {
+	r, exists := p.routes[string(rate.BaseCurrency)+"-"+string(rate.TargetCurrency)]
+	if !exists {
+		r = route{exchange: p.cfg.Exchange, routingKey: p.cfg.RoutingKey}
+	}
+
+	replacer := strings.NewReplacer("{base}", string(rate.BaseCurrency), "{target}", string(rate.TargetCurrency))
+	r.routingKey = replacer.Replace(r.routingKey)
+	return r
+}
+
+// exchanges lists every exchange a rate can be routed to
+func (p *Publisher) exchanges() []string {
+	seen := map[string]bool{p.cfg.Exchange: true}
+	for _, r := range p.routes {
+		seen[r.exchange] = true
+	}
+
+	exchanges := make([]string, 0, len(seen))
+	for exchange := range seen {
+		// The default exchange "" always exists and cannot be declared
+		if exchange != "" {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+	sort.Strings(exchanges)
+	return exchanges
+}
+
+// Publish sends each rate as a persistent JSON message and waits for the
+// broker to confirm them all
+func (p *Publisher) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	conn, err := p.connection(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to AMQP broker: %w", err)
+	}
+
+	pending := make(map[uint64]bool, len(rates))
+	for _, rate := range rates {
+		body, err := json.Marshal(rate)
+		if err != nil {
+			return err
+		}
+
+		r := p.route(rate)
+		tag, err := conn.publish(r.exchange, r.routingKey, "application/json", body)
+		if err != nil {
+			p.drop(err)
+			return fmt.Errorf("failed to publish to AMQP broker: %w", err)
+		}
+		pending[tag] = true
+	}
+
+	timer := time.NewTimer(p.cfg.ConfirmTimeout)
+	defer timer.Stop()
+
+	nacked := 0
+	for len(pending) > 0 {
+		select {
+		case conf := <-conn.confirms:
+			for tag := range pending {
+				if tag == conf.tag || (conf.multiple && tag < conf.tag) {
+					delete(pending, tag)
+					if !conf.ack {
+						nacked++
+					}
+				}
+			}
+		case <-conn.done:
+			p.conn = nil
+			return fmt.Errorf("AMQP connection lost with %d unconfirmed messages: %w", len(pending), conn.err)
+		case <-timer.C:
+			// Late confirms would be mistaken for the next batch's
+			p.drop(fmt.Errorf("confirm timeout"))
+			return fmt.Errorf("%d messages unconfirmed after %s", len(pending), p.cfg.ConfirmTimeout)
+		case <-ctx.Done():
+			p.drop(ctx.Err())
+			return ctx.Err()
+		}
+	}
+
+	if nacked > 0 {
+		return fmt.Errorf("AMQP broker rejected %d of %d messages", nacked, len(rates))
+	}
+	return nil
+}
+
+// connection returns the open connection, dialing a new one if there is none
+// or the last one failed. The caller holds the mutex.
+func (p *Publisher) connection(ctx context.Context) (*connection, error) {
+	if p.conn != nil {
+		select {
+		case <-p.conn.done:
+			p.log.Warn("AMQP connection lost, reconnecting", "error", p.conn.err)
+			p.conn = nil
+		default:
+			return p.conn, nil
+		}
+	}
+
+	conn, err := dial(ctx, p.cfg.URL, p.exchanges(), p.cfg.ExchangeType)
+	if err != nil {
+		return nil, err
+	}
+	p.log.Info("Connected to AMQP broker", "exchange", p.cfg.Exchange, "frame_max", conn.frameMax, "heartbeat", conn.heartbeat.String())
+	p.conn = conn
+	return conn, nil
+}
+
+// drop closes the connection after a publishing error. The caller holds the
+// mutex.
+func (p *Publisher) drop(err error) {
+	if p.conn != nil {
+		p.conn.fail(err)
+		p.conn = nil
+	}
+}
+
+// Close closes the broker connection, if any
+func (p *Publisher) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.conn != nil {
+		p.conn.close()
+		p.conn = nil
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package amqp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+type delivery struct {
+	exchange   string
+	routingKey string
+	body       []byte
+}
+
+// fakeBroker accepts one connection, completes the handshake and confirms
+// every publish, acking or nacking depending on ack
+func fakeBroker(t *testing.T, ack bool) (string, <-chan delivery) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	deliveries := make(chan delivery, 16)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+
+		header := make([]byte, len(protocolHeader))
+		if _, err := r.Read(header); err != nil {
+			return
+		}
+
+		next := func() (methodID, *decoder) {
+			f, err := readFrame(r, frameMaxPreferred)
+			if err != nil {
+				return methodID{}, &decoder{err: err}
+			}
+			return method(f.payload)
+		}
+		reply := func(channel uint16, m *args) {
+			writeFrame(conn, frameMethod, channel, m.Bytes())
+		}
+
+		reply(0, newMethod(connectionStart).octet(0).octet(9).table(nil).longstr("PLAIN").longstr("en_US"))
+		next()
+		reply(0, newMethod(connectionTune).short(2047).long(4096).short(0))
+		next()
+		next()
+		reply(0, newMethod(connectionOpenOk).shortstr(""))
+		next()
+		reply(publishChannel, newMethod(channelOpenOk).longstr(""))
+		next()
+		reply(publishChannel, newMethod(confirmSelectOk))
+
+		tag := uint64(0)
+		for {
+			id, d := next()
+			if d.err != nil {
+				return
+			}
+			switch id {
+			case exchangeDeclare:
+				reply(publishChannel, newMethod(exchangeDeclareOk))
+			case basicPublish:
+				d.short()
+				exchange, routingKey := d.shortstr(), d.shortstr()
+
+				f, _ := readFrame(r, frameMaxPreferred)
+				size := (&decoder{data: f.payload[4:]}).longlong()
+				body := make([]byte, 0, size)
+				for uint64(len(body)) < size {
+					f, err := readFrame(r, frameMaxPreferred)
+					if err != nil {
+						return
+					}
+					body = append(body, f.payload...)
+				}
+
+				tag++
+				deliveries <- delivery{exchange, routingKey, body}
+				confirm := basicAck
+				if !ack {
+					confirm = basicNack
+				}
+				reply(publishChannel, newMethod(confirm).longlong(tag).octet(0))
+			case connectionClose:
+				reply(0, newMethod(connectionCloseOk))
+				return
+			}
+		}
+	}()
+
+	return l.Addr().String(), deliveries
+}
+
+func TestPublish(t *testing.T) {
+	addr, deliveries := fakeBroker(t, true)
+
+	publisher := NewPublisher(config.AMQPConfig{
+		URL:            "amqp://guest:guest@" + addr + "/",
+		Exchange:       "exchange-rates",
+		ExchangeType:   "topic",
+		RoutingKey:     "rates.{base}.{target}",
+		PairRoutes:     map[string]string{"usd-inr": "india/fx.{target}"},
+		ConfirmTimeout: time.Second,
+	}, logger.NewLogger("error"))
+	defer publisher.Close()
+
+	rates := []model.ExchangeRate{
+		{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.12},
+		{BaseCurrency: model.EUR, TargetCurrency: model.USD, Rate: 1.08},
+	}
+	if err := publisher.Publish(context.Background(), rates); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	expected := []struct{ exchange, routingKey string }{
+		{"india", "fx.INR"},
+		{"exchange-rates", "rates.EUR.USD"},
+	}
+	for i, want := range expected {
+		got := <-deliveries
+		if got.exchange != want.exchange || got.routingKey != want.routingKey {
+			t.Errorf("Message %d: expected %s/%s, got %s/%s", i, want.exchange, want.routingKey, got.exchange, got.routingKey)
+		}
+		var rate model.ExchangeRate
+		if err := json.Unmarshal(got.body, &rate); err != nil || rate.Rate != rates[i].Rate {
+			t.Errorf("Message %d: unexpected body %s", i, got.body)
+		}
+	}
+}
+
+func TestPublishNack(t *testing.T) {
+	addr, _ := fakeBroker(t, false)
+
+	publisher := NewPublisher(config.AMQPConfig{
+		URL:            "amqp://" + addr,
+		Exchange:       "exchange-rates",
+		ExchangeType:   "topic",
+		RoutingKey:     "rates.{base}.{target}",
+		ConfirmTimeout: time.Second,
+	}, logger.NewLogger("error"))
+	defer publisher.Close()
+
+	err := publisher.Publish(context.Background(), []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.12}})
+	if err == nil {
+		t.Fatal("Expected an error for a rejected message")
+	}
+}
@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+const (
+	apiKeyHeader      = "X-API-Key"
+	widgetTokenPrefix = "wt_"
+)
+
+var ErrInvalidWidgetToken = errors.New("invalid widget token")
+
+// WidgetToken is a short-lived, read-only credential limited to a set of pairs.
+// It is signed, not stored, so it cannot be revoked before it expires.
+type WidgetToken struct {
+	Pairs     []string  `json:"pairs"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+func (t *WidgetToken) allowsPair(pair model.CurrencyPair) bool {
+	for _, p := range t.Pairs {
+		if p == pair.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyAuthenticator checks static API keys and the widget tokens minted with them
+type APIKeyAuthenticator struct {
+	// mutex guards keys, the accepted API keys, and configured, those last
+	// loaded from the configuration, which keys may no longer all hold once
+	// keys were applied
+	mutex      sync.RWMutex
+	keys       []string
+	configured []string
+	signer     *signer
+	maxTTL     time.Duration
+	log        *logger.Logger
+}
+
+func NewAPIKeyAuthenticator(cfg config.APIKeyConfig, log *logger.Logger) *APIKeyAuthenticator {
+	a := &APIKeyAuthenticator{
+		keys:       slices.Clone(cfg.Keys),
+		configured: slices.Clone(cfg.Keys),
+		maxTTL:     cfg.WidgetTokenMaxTTL,
+		log:        log,
+	}
+	if cfg.WidgetTokenSecret != "" {
+		a.signer = &signer{secret: []byte(cfg.WidgetTokenSecret)}
+	}
+	return a
+}
+
+// TokensEnabled reports whether widget tokens can be minted
+func (a *APIKeyAuthenticator) TokensEnabled() bool {
+	return a.signer != nil
+}
+
+// SetKeys replaces the accepted API keys, as the apply endpoint declares them
+func (a *APIKeyAuthenticator) SetKeys(keys []string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.keys = slices.Clone(keys)
+}
+
+// ReloadKeys takes in the keys configured after a mounted Secret changed.
+// Only the keys added to or removed from the configuration since it was last
+// loaded change, so keys installed or revoked with SetKeys in the meantime
+// stay as they are.
+func (a *APIKeyAuthenticator) ReloadKeys(configured []string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	keys := make([]string, 0, len(a.keys)+len(configured))
+	for _, key := range a.keys {
+		if slices.Contains(configured, key) || !slices.Contains(a.configured, key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range configured {
+		if !slices.Contains(a.configured, key) && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	a.keys = keys
+	a.configured = slices.Clone(configured)
+}
+
+// Keys returns the accepted API keys
+func (a *APIKeyAuthenticator) Keys() []string {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return slices.Clone(a.keys)
+}
+
+// ValidKey reports whether key is one of the accepted API keys
+func (a *APIKeyAuthenticator) ValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	for _, k := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Tenant identifies the holder of the API key on r without revealing the key,
+// or returns "" when r carries no valid one, so an unknown key can never
+// pass for a tenant
+func (a *APIKeyAuthenticator) Tenant(r *http.Request) string {
+	key := r.Header.Get(apiKeyHeader)
+	if !a.ValidKey(key) {
+		return ""
+	}
+	return TenantOf(key)
+}
+
+// TenantOf identifies the holder of key without revealing it
+func TenantOf(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RequireKey only lets requests carrying a valid API key through
+func (a *APIKeyAuthenticator) RequireKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.ValidKey(r.Header.Get(apiKeyHeader)) {
+			writeJSONError(w, http.StatusUnauthorized, "valid API key required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireKeyOrToken accepts a valid API key, or a widget token whose pairs
+// include the from/to pair of a GET request
+func (a *APIKeyAuthenticator) RequireKeyOrToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.ValidKey(r.Header.Get(apiKeyHeader)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		raw := widgetTokenFromRequest(r)
+		if raw == "" {
+			writeJSONError(w, http.StatusUnauthorized, "valid API key or widget token required")
+			return
+		}
+
+		token, err := a.verifyToken(raw)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		pair := model.CurrencyPair{
+			BaseCurrency:   model.Currency(r.URL.Query().Get("from")),
+			TargetCurrency: model.Currency(r.URL.Query().Get("to")),
+		}
+		if r.Method != http.MethodGet || !token.allowsPair(pair) {
+			writeJSONError(w, http.StatusForbidden, "widget token does not cover this request")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// widgetTokenFromRequest reads a bearer token, falling back to the token query
+// parameter for script tags that cannot set headers
+func widgetTokenFromRequest(r *http.Request) string {
+	if bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found {
+		return bearer
+	}
+	return r.URL.Query().Get("token")
+}
+
+func (a *APIKeyAuthenticator) verifyToken(raw string) (*WidgetToken, error) {
+	encoded, found := strings.CutPrefix(raw, widgetTokenPrefix)
+	if !found || a.signer == nil {
+		return nil, ErrInvalidWidgetToken
+	}
+
+	var token WidgetToken
+	if err := a.signer.verify(encoded, &token); err != nil {
+		return nil, ErrInvalidWidgetToken
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, errors.New("widget token expired")
+	}
+
+	return &token, nil
+}
+
+type mintTokenRequest struct {
+	Pairs []string `json:"pairs"`
+	TTL   string   `json:"ttl"`
+}
+
+type mintTokenResponse struct {
+	Token     string    `json:"token"`
+	Pairs     []string  `json:"pairs"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MintTokenHandler issues a widget token for the requested pairs. It must be
+// wrapped with RequireKey.
+func (a *APIKeyAuthenticator) MintTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var body mintTokenRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if len(body.Pairs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "at least one pair is required")
+		return
+	}
+
+	token := WidgetToken{Pairs: make([]string, 0, len(body.Pairs))}
+	for _, p := range body.Pairs {
+		pair, err := model.ParseCurrencyPair(p)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		token.Pairs = append(token.Pairs, pair.String())
+	}
+
+	ttl := a.maxTTL
+	if body.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(body.TTL)
+		if err != nil || ttl <= 0 {
+			writeJSONError(w, http.StatusBadRequest, "invalid ttl, use a duration such as 15m")
+			return
+		}
+		if ttl > a.maxTTL {
+			ttl = a.maxTTL
+		}
+	}
+	token.ExpiresAt = time.Now().Add(ttl).UTC().Truncate(time.Second)
+
+	signed, err := a.signer.sign(token)
+	if err != nil {
+		a.log.Error("Failed to sign widget token", "error", err)
+		writeJSONError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data": mintTokenResponse{
+			Token:     widgetTokenPrefix + signed,
+			Pairs:     token.Pairs,
+			ExpiresAt: token.ExpiresAt,
+		},
+	})
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/pkg/logger"
+)
+
+func newTestAPIKeys() *APIKeyAuthenticator {
+	return NewAPIKeyAuthenticator(config.APIKeyConfig{
+		Keys:              []string{"key-one", "key-two"},
+		WidgetTokenSecret: "widget-secret-of-at-least-32-chars!",
+		WidgetTokenMaxTTL: time.Hour,
+	}, logger.NewLogger("error"))
+}
+
+// widgetToken signs a token for pairs expiring at expiresAt
+func widgetToken(t *testing.T, a *APIKeyAuthenticator, expiresAt time.Time, pairs ...string) string {
+	t.Helper()
+	signed, err := a.signer.sign(WidgetToken{Pairs: pairs, ExpiresAt: expiresAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return widgetTokenPrefix + signed
+}
+
+func TestRequireKeyOrToken(t *testing.T) {
+	a := newTestAPIKeys()
+	valid := widgetToken(t, a, time.Now().Add(time.Minute), "USD-INR")
+	expired := widgetToken(t, a, time.Now().Add(-time.Minute), "USD-INR")
+	other := NewAPIKeyAuthenticator(config.APIKeyConfig{WidgetTokenSecret: "another-secret-of-at-least-32-chars"}, logger.NewLogger("error"))
+	foreign := widgetToken(t, other, time.Now().Add(time.Minute), "USD-INR")
+
+	payload, signature, _ := strings.Cut(strings.TrimPrefix(valid, widgetTokenPrefix), ".")
+	tampered := widgetTokenPrefix + payload + "x." + signature
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		key    string
+		bearer string
+		status int
+	}{
+		{"valid key", "GET", "/rates?from=USD&to=INR", "key-two", "", http.StatusOK},
+		{"valid key for any pair and method", "POST", "/rates?from=EUR&to=GBP", "key-one", "", http.StatusOK},
+		{"invalid key", "GET", "/rates?from=USD&to=INR", "key-three", "", http.StatusUnauthorized},
+		{"no credentials", "GET", "/rates?from=USD&to=INR", "", "", http.StatusUnauthorized},
+		{"valid token", "GET", "/rates?from=USD&to=INR", "", valid, http.StatusOK},
+		{"valid token as query parameter", "GET", "/rates?from=USD&to=INR&token=" + valid, "", "", http.StatusOK},
+		{"tampered token", "GET", "/rates?from=USD&to=INR", "", tampered, http.StatusUnauthorized},
+		{"token signed with another secret", "GET", "/rates?from=USD&to=INR", "", foreign, http.StatusUnauthorized},
+		{"token without prefix", "GET", "/rates?from=USD&to=INR", "", strings.TrimPrefix(valid, widgetTokenPrefix), http.StatusUnauthorized},
+		{"expired token", "GET", "/rates?from=USD&to=INR", "", expired, http.StatusUnauthorized},
+		{"token for the wrong pair", "GET", "/rates?from=USD&to=EUR", "", valid, http.StatusForbidden},
+		{"token for the inverse pair", "GET", "/rates?from=INR&to=USD", "", valid, http.StatusForbidden},
+		{"token with a non-GET method", "POST", "/rates?from=USD&to=INR", "", valid, http.StatusForbidden},
+	}
+
+	handler := a.RequireKeyOrToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestRequireKeyRejectsWidgetTokens(t *testing.T) {
+	a := newTestAPIKeys()
+	handler := a.RequireKey(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/rates?from=USD&to=INR", nil)
+	req.Header.Set("Authorization", "Bearer "+widgetToken(t, a, time.Now().Add(time.Minute), "USD-INR"))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a widget token to be refused where a key is required, got %d", recorder.Code)
+	}
+
+	a.SetKeys([]string{"rotated"})
+	req = httptest.NewRequest("GET", "/rates", nil)
+	req.Header.Set(apiKeyHeader, "key-one")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a replaced key to be refused, got %d", recorder.Code)
+	}
+}
+
+func TestTenantRequiresValidKey(t *testing.T) {
+	a := newTestAPIKeys()
+
+	req := httptest.NewRequest("GET", "/", nil)
+	if tenant := a.Tenant(req); tenant != "" {
+		t.Errorf("Expected no tenant without a key, got %q", tenant)
+	}
+
+	req.Header.Set(apiKeyHeader, "made-up")
+	if tenant := a.Tenant(req); tenant != "" {
+		t.Errorf("Expected no tenant for an invalid key, got %q", tenant)
+	}
+
+	req.Header.Set(apiKeyHeader, "key-one")
+	if tenant := a.Tenant(req); tenant != TenantOf("key-one") || tenant == TenantOf("key-two") {
+		t.Errorf("Expected the tenant of key-one, got %q", tenant)
+	}
+}
+
+func TestMintTokenHandler(t *testing.T) {
+	a := newTestAPIKeys()
+
+	recorder := httptest.NewRecorder()
+	a.MintTokenHandler(recorder, httptest.NewRequest("POST", "/api/v1/tokens", strings.NewReader(`{"pairs":["usd-inr"],"ttl":"48h"}`)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected a token, got %d: %s", recorder.Code, recorder.Body)
+	}
+	body := recorder.Body.String()
+	if !strings.Contains(body, `"pairs":["USD-INR"]`) || !strings.Contains(body, `"token":"`+widgetTokenPrefix) {
+		t.Errorf("Expected a token for USD-INR, got %s", body)
+	}
+
+	for _, invalid := range []string{`{"pairs":[]}`, `{"pairs":["USD"]}`, `{"pairs":["USD-INR"],"ttl":"-1m"}`, `not json`} {
+		recorder := httptest.NewRecorder()
+		a.MintTokenHandler(recorder, httptest.NewRequest("POST", "/api/v1/tokens", strings.NewReader(invalid)))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected %s to be rejected, got %d", invalid, recorder.Code)
+		}
+	}
+}
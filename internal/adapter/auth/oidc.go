@@ -0,0 +1,596 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/pkg/logger"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleViewer = "viewer"
+
+	sessionCookieName = "exrate_session"
+	stateCookieName   = "exrate_oidc_state"
+	stateTTL          = 10 * time.Minute
+
+	// jwksRefetchInterval is the least time between two JWKS fetches for
+	// unknown key ids, so forged tokens cannot make the service hammer the
+	// provider
+	jwksRefetchInterval = time.Minute
+)
+
+var ErrInvalidToken = errors.New("invalid id token")
+
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint,omitempty"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type loginState struct {
+	State     string    `json:"state"`
+	Nonce     string    `json:"nonce"`
+	ReturnTo  string    `json:"return_to"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+}
+
+// OIDCAuthenticator implements the authorization code flow against an
+// OpenID Connect provider and keeps the resulting identity in a signed cookie.
+type OIDCAuthenticator struct {
+	cfg        config.OIDCConfig
+	httpClient *http.Client
+	signer     *signer
+	log        *logger.Logger
+
+	mutex       sync.RWMutex
+	metadata    *providerMetadata
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+func NewOIDCAuthenticator(cfg config.OIDCConfig, log *logger.Logger) *OIDCAuthenticator {
+	return &OIDCAuthenticator{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		signer: &signer{secret: []byte(cfg.SessionSecret)},
+		log:    log,
+		keys:   make(map[string]*rsa.PublicKey),
+	}
+}
+
+// LoginHandler redirects the browser to the provider's authorization endpoint
+func (a *OIDCAuthenticator) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	metadata, err := a.discover(r.Context())
+	if err != nil {
+		a.log.Error("OIDC discovery failed", "error", err)
+		http.Error(w, "identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	state := loginState{
+		State:     randomString(),
+		Nonce:     randomString(),
+		ReturnTo:  safeReturnTo(r.URL.Query().Get("return_to")),
+		ExpiresAt: time.Now().Add(stateTTL),
+	}
+
+	value, err := a.signer.sign(state)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, stateCookieName, value, state.ExpiresAt)
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", a.cfg.ClientID)
+	params.Set("redirect_uri", a.cfg.RedirectURL)
+	params.Set("scope", strings.Join(a.cfg.Scopes, " "))
+	params.Set("state", state.State)
+	params.Set("nonce", state.Nonce)
+
+	http.Redirect(w, r, metadata.AuthorizationEndpoint+"?"+params.Encode(), http.StatusFound)
+}
+
+// CallbackHandler completes the code exchange and establishes the session
+func (a *OIDCAuthenticator) CallbackHandler(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(stateCookieName)
+	if err != nil {
+		http.Error(w, "missing login state", http.StatusBadRequest)
+		return
+	}
+	a.clearCookie(w, stateCookieName)
+
+	var state loginState
+	if err := a.signer.verify(cookie.Value, &state); err != nil || time.Now().After(state.ExpiresAt) {
+		http.Error(w, "invalid login state", http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Get("state") != state.State {
+		http.Error(w, "state mismatch", http.StatusBadRequest)
+		return
+	}
+
+	if errCode := r.URL.Query().Get("error"); errCode != "" {
+		a.log.Error("OIDC provider returned error", "error", errCode, "description", r.URL.Query().Get("error_description"))
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		http.Error(w, "missing authorization code", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	token, err := a.exchangeCode(ctx, code)
+	if err != nil {
+		a.log.Error("OIDC code exchange failed", "error", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := a.verifyIDToken(ctx, token.IDToken, state.Nonce)
+	if err != nil {
+		a.log.Error("OIDC token verification failed", "error", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	session := a.sessionFromClaims(claims)
+	if len(session.Roles) == 0 {
+		a.log.Info("OIDC user has no mapped roles", "subject", session.Subject)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	value, err := a.signer.sign(session)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(w, sessionCookieName, value, session.ExpiresAt)
+
+	a.log.Info("OIDC login succeeded", "subject", session.Subject, "roles", session.Roles)
+	http.Redirect(w, r, state.ReturnTo, http.StatusFound)
+}
+
+func (a *OIDCAuthenticator) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	a.clearCookie(w, sessionCookieName)
+
+	a.mutex.RLock()
+	metadata := a.metadata
+	a.mutex.RUnlock()
+
+	if metadata != nil && metadata.EndSessionEndpoint != "" {
+		http.Redirect(w, r, metadata.EndSessionEndpoint, http.StatusFound)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// SessionHandler reports the identity of the current session
+func (a *OIDCAuthenticator) SessionHandler(w http.ResponseWriter, r *http.Request) {
+	session, err := a.SessionFromRequest(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// Require wraps next so that only sessions holding role may reach it.
+// Unauthenticated browsers are sent through the login flow.
+func (a *OIDCAuthenticator) Require(role string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := a.SessionFromRequest(r)
+		if err != nil {
+			loginURL := "/auth/login?return_to=" + url.QueryEscape(r.URL.RequestURI())
+			http.Redirect(w, r, loginURL, http.StatusFound)
+			return
+		}
+
+		if !session.HasRole(role) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireAPI is the variant of Require for JSON endpoints: it answers 401/403
+// instead of redirecting to the login page.
+func (a *OIDCAuthenticator) RequireAPI(role string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := a.SessionFromRequest(r)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+
+		if !session.HasRole(role) {
+			writeJSONError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSONError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error":   message,
+	})
+}
+
+func (a *OIDCAuthenticator) SessionFromRequest(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+
+	var session Session
+	if err := a.signer.verify(cookie.Value, &session); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrSessionExpired
+	}
+
+	return &session, nil
+}
+
+func (a *OIDCAuthenticator) sessionFromClaims(claims map[string]interface{}) *Session {
+	session := &Session{
+		Subject:   stringClaim(claims, "sub"),
+		Email:     stringClaim(claims, "email"),
+		Name:      stringClaim(claims, "name"),
+		Roles:     make([]string, 0),
+		ExpiresAt: time.Now().Add(a.cfg.SessionTTL),
+	}
+
+	seen := make(map[string]bool)
+	for _, group := range groupsClaim(claims, a.cfg.GroupsClaim) {
+		role, mapped := a.cfg.RoleMapping[group]
+		if mapped && !seen[role] {
+			seen[role] = true
+			session.Roles = append(session.Roles, role)
+		}
+	}
+
+	return session
+}
+
+func (a *OIDCAuthenticator) discover(ctx context.Context) (*providerMetadata, error) {
+	a.mutex.RLock()
+	metadata := a.metadata
+	a.mutex.RUnlock()
+	if metadata != nil {
+		return metadata, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(a.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	metadata = &providerMetadata{}
+	if err := a.getJSON(ctx, discoveryURL, metadata); err != nil {
+		return nil, fmt.Errorf("failed to fetch discovery document: %w", err)
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(a.cfg.IssuerURL, "/") {
+		return nil, fmt.Errorf("issuer mismatch: %s", metadata.Issuer)
+	}
+
+	a.mutex.Lock()
+	a.metadata = metadata
+	a.mutex.Unlock()
+
+	return metadata, nil
+}
+
+func (a *OIDCAuthenticator) exchangeCode(ctx context.Context, code string) (*tokenResponse, error) {
+	metadata, err := a.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", a.cfg.RedirectURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(a.cfg.ClientID), url.QueryEscape(a.cfg.ClientSecret))
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned non-OK status: %d", resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+
+	return &token, nil
+}
+
+func (a *OIDCAuthenticator) verifyIDToken(ctx context.Context, rawToken, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %s", ErrInvalidToken, header.Alg)
+	}
+
+	key, err := a.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if strings.TrimSuffix(stringClaim(claims, "iss"), "/") != strings.TrimSuffix(a.cfg.IssuerURL, "/") {
+		return nil, fmt.Errorf("%w: issuer mismatch", ErrInvalidToken)
+	}
+	if !audienceContains(claims["aud"], a.cfg.ClientID) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrInvalidToken)
+	}
+	if exp, ok := claims["exp"].(float64); !ok || time.Now().After(time.Unix(int64(exp), 0)) {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if stringClaim(claims, "nonce") != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// publicKey returns the signing key for kid, refreshing the JWKS on a miss
+// so that provider key rotation is picked up without a restart. Misses within
+// jwksRefetchInterval of the last fetch fail without fetching again.
+func (a *OIDCAuthenticator) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	a.mutex.Lock()
+	key, found := a.keys[kid]
+	if found {
+		a.mutex.Unlock()
+		return key, nil
+	}
+	if !a.keysFetched.IsZero() && time.Since(a.keysFetched) < jwksRefetchInterval {
+		a.mutex.Unlock()
+		return nil, fmt.Errorf("%w: unknown key id %s", ErrInvalidToken, kid)
+	}
+	a.keysFetched = time.Now()
+	a.mutex.Unlock()
+
+	metadata, err := a.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := a.getJSON(ctx, metadata.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		pub, err := jwk.rsaPublicKey()
+		if err != nil {
+			a.log.Error("Skipping malformed JWK", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = pub
+	}
+
+	a.mutex.Lock()
+	a.keys = keys
+	a.mutex.Unlock()
+
+	key, found = keys[kid]
+	if !found {
+		return nil, fmt.Errorf("%w: unknown key id %s", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+func (a *OIDCAuthenticator) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("non-OK status: %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (a *OIDCAuthenticator) setCookie(w http.ResponseWriter, name, value string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(a.cfg.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (a *OIDCAuthenticator) clearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func stringClaim(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func groupsClaim(claims map[string]interface{}, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		groups := make([]string, 0, len(value))
+		for _, item := range value {
+			if group, ok := item.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == clientID
+	case []interface{}:
+		for _, item := range value {
+			if item == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// safeReturnTo only allows paths on this host so the login flow cannot be
+// used as an open redirect. Browsers read a backslash as a slash, so /\host
+// is as much another host as //host.
+func safeReturnTo(returnTo string) string {
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.ContainsAny(returnTo, "\\\r\n\t") {
+		return "/dashboard/"
+	}
+	if parsed, err := url.Parse(returnTo); err != nil || parsed.Host != "" || parsed.Scheme != "" {
+		return "/dashboard/"
+	}
+	return returnTo
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
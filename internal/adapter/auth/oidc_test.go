@@ -0,0 +1,361 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/pkg/logger"
+)
+
+// testProvider is an OpenID Connect provider issuing the ID token made by
+// idToken from the token endpoint
+type testProvider struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	jwksCalls atomic.Int32
+	idToken   func(nonce string) string
+	lastNonce string
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(providerMetadata{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.jwksCalls.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"keys": []jsonWebKey{{
+			Kid: "key-1",
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if clientID, _, _ := r.BasicAuth(); clientID != "dashboard" || r.FormValue("code") != "good-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(tokenResponse{IDToken: p.idToken(p.lastNonce), TokenType: "Bearer"})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// claims returns valid claims of an ID token for nonce, in the given groups
+func (p *testProvider) claims(nonce string, groups ...string) map[string]any {
+	return map[string]any{
+		"iss":    p.server.URL,
+		"aud":    "dashboard",
+		"sub":    "user-1",
+		"email":  "user@example.com",
+		"exp":    float64(time.Now().Add(time.Hour).Unix()),
+		"nonce":  nonce,
+		"groups": groups,
+	}
+}
+
+// sign makes a token of header and claims signed with key by RS256
+func sign(t *testing.T, key *rsa.PrivateKey, header, claims map[string]any) string {
+	t.Helper()
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(header) + "." + segment(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestAuthenticator(p *testProvider) *OIDCAuthenticator {
+	return NewOIDCAuthenticator(config.OIDCConfig{
+		Enabled:       true,
+		IssuerURL:     p.server.URL,
+		ClientID:      "dashboard",
+		ClientSecret:  "secret",
+		RedirectURL:   "http://localhost:8080/auth/callback",
+		Scopes:        []string{"openid", "email"},
+		GroupsClaim:   "groups",
+		RoleMapping:   map[string]string{"fx-admins": RoleAdmin, "fx-viewers": RoleViewer},
+		SessionSecret: "session-secret",
+		SessionTTL:    time.Hour,
+	}, logger.NewLogger("error"))
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	provider := newTestProvider(t)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs256 := map[string]any{"alg": "RS256", "kid": "key-1"}
+	with := func(name string, value any) map[string]any {
+		claims := provider.claims("nonce-1")
+		claims[name] = value
+		return claims
+	}
+
+	testCases := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "valid", token: sign(t, provider.key, rs256, provider.claims("nonce-1")), valid: true},
+		{name: "audience list", token: sign(t, provider.key, rs256, with("aud", []string{"other", "dashboard"})), valid: true},
+		{name: "alg none", token: sign(t, provider.key, map[string]any{"alg": "none", "kid": "key-1"}, provider.claims("nonce-1"))},
+		{name: "alg HS256", token: sign(t, provider.key, map[string]any{"alg": "HS256", "kid": "key-1"}, provider.claims("nonce-1"))},
+		{name: "signed by another key", token: sign(t, other, rs256, provider.claims("nonce-1"))},
+		{name: "tampered claims", token: func() string {
+			parts := strings.Split(sign(t, provider.key, rs256, provider.claims("nonce-1")), ".")
+			forged, _ := json.Marshal(with("sub", "admin"))
+			return parts[0] + "." + base64.RawURLEncoding.EncodeToString(forged) + "." + parts[2]
+		}()},
+		{name: "other issuer", token: sign(t, provider.key, rs256, with("iss", "https://evil.example"))},
+		{name: "other audience", token: sign(t, provider.key, rs256, with("aud", "someone-else"))},
+		{name: "expired", token: sign(t, provider.key, rs256, with("exp", float64(time.Now().Add(-time.Minute).Unix())))},
+		{name: "no expiry", token: sign(t, provider.key, rs256, with("exp", nil))},
+		{name: "other nonce", token: sign(t, provider.key, rs256, provider.claims("nonce-2"))},
+		{name: "not a token", token: "abc.def"},
+	}
+
+	authenticator := newTestAuthenticator(provider)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := authenticator.verifyIDToken(context.Background(), tc.token, "nonce-1")
+			if tc.valid && (err != nil || stringClaim(claims, "sub") != "user-1") {
+				t.Errorf("Expected the token to be accepted, got %v", err)
+			}
+			if !tc.valid && !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUnknownKeysRefetchJWKSAtMostOncePerInterval(t *testing.T) {
+	provider := newTestProvider(t)
+	authenticator := newTestAuthenticator(provider)
+	ctx := context.Background()
+
+	valid := sign(t, provider.key, map[string]any{"alg": "RS256", "kid": "key-1"}, provider.claims("n"))
+	if _, err := authenticator.verifyIDToken(ctx, valid, "n"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		forged := sign(t, provider.key, map[string]any{"alg": "RS256", "kid": "forged"}, provider.claims("n"))
+		if _, err := authenticator.verifyIDToken(ctx, forged, "n"); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("Expected an unknown key id to be refused, got %v", err)
+		}
+	}
+	if calls := provider.jwksCalls.Load(); calls != 1 {
+		t.Errorf("Expected a single JWKS fetch within the interval, got %d", calls)
+	}
+
+	// Once the interval has passed, a rotated key is picked up again
+	authenticator.keysFetched = time.Now().Add(-jwksRefetchInterval)
+	authenticator.verifyIDToken(ctx, sign(t, provider.key, map[string]any{"alg": "RS256", "kid": "rotated"}, provider.claims("n")), "n")
+	if calls := provider.jwksCalls.Load(); calls != 2 {
+		t.Errorf("Expected a new JWKS fetch after the interval, got %d", calls)
+	}
+}
+
+// login runs the login handler and returns its state cookie and the state and
+// nonce it sent to the provider
+func login(t *testing.T, authenticator *OIDCAuthenticator, returnTo string) (*http.Cookie, string, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	authenticator.LoginHandler(rec, httptest.NewRequest("GET", "/auth/login?return_to="+url.QueryEscape(returnTo), nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("Expected a redirect to the provider, got %d", rec.Code)
+	}
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != stateCookieName || !cookies[0].HttpOnly {
+		t.Fatalf("Expected an HTTP-only state cookie, got %v", cookies)
+	}
+	return cookies[0], location.Query().Get("state"), location.Query().Get("nonce")
+}
+
+func TestCallback(t *testing.T) {
+	provider := newTestProvider(t)
+	authenticator := newTestAuthenticator(provider)
+	groups := []string{"fx-viewers"}
+	provider.idToken = func(nonce string) string {
+		return sign(t, provider.key, map[string]any{"alg": "RS256", "kid": "key-1"}, provider.claims(nonce, groups...))
+	}
+	callback := func(cookie *http.Cookie, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/auth/callback?"+query, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		authenticator.CallbackHandler(rec, req)
+		return rec
+	}
+
+	cookie, state, nonce := login(t, authenticator, "/dashboard/rates")
+	provider.lastNonce = nonce
+
+	// Cross-site requests carry neither the state cookie nor its state
+	if rec := callback(nil, "state="+state+"&code=good-code"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a callback without the state cookie to be refused, got %d", rec.Code)
+	}
+	if rec := callback(cookie, "state=guessed&code=good-code"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a callback with another state to be refused, got %d", rec.Code)
+	}
+	forged := *cookie
+	forged.Value = strings.Replace(forged.Value, ".", "x.", 1)
+	if rec := callback(&forged, "state="+state+"&code=good-code"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a tampered state cookie to be refused, got %d", rec.Code)
+	}
+	if rec := callback(cookie, "state="+state+"&code=bad-code"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a failed code exchange to be refused, got %d", rec.Code)
+	}
+
+	rec := callback(cookie, "state="+state+"&code=good-code")
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/dashboard/rates" {
+		t.Fatalf("Expected a redirect to the page asked for, got %d to %s", rec.Code, rec.Header().Get("Location"))
+	}
+	var session *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == sessionCookieName {
+			session = c
+		}
+	}
+	if session == nil {
+		t.Fatal("Expected a session cookie")
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(session)
+	if s, err := authenticator.SessionFromRequest(req); err != nil || s.Subject != "user-1" || !s.HasRole(RoleViewer) || s.HasRole(RoleAdmin) {
+		t.Errorf("Expected a viewer session for user-1, got %+v, %v", s, err)
+	}
+
+	// A token issued for another login does not start a session
+	cookie, state, _ = login(t, authenticator, "/")
+	provider.lastNonce = "replayed"
+	if rec := callback(cookie, "state="+state+"&code=good-code"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a token with another nonce to be refused, got %d", rec.Code)
+	}
+
+	// Users in no mapped group get no session
+	groups = []string{"contractors"}
+	cookie, state, nonce = login(t, authenticator, "/")
+	provider.lastNonce = nonce
+	if rec := callback(cookie, "state="+state+"&code=good-code"); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected a user without roles to be refused, got %d", rec.Code)
+	}
+}
+
+func TestRequireAdminRole(t *testing.T) {
+	authenticator := newTestAuthenticator(newTestProvider(t))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	cookie := func(session Session) *http.Cookie {
+		value, err := authenticator.signer.sign(session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &http.Cookie{Name: sessionCookieName, Value: value}
+	}
+	expires := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name     string
+		cookie   *http.Cookie
+		expected int
+	}{
+		{name: "no session", expected: http.StatusUnauthorized},
+		{name: "admin", cookie: cookie(Session{Subject: "a", Roles: []string{RoleAdmin}, ExpiresAt: expires}), expected: http.StatusOK},
+		{name: "viewer", cookie: cookie(Session{Subject: "v", Roles: []string{RoleViewer}, ExpiresAt: expires}), expected: http.StatusForbidden},
+		{name: "expired admin", cookie: cookie(Session{Subject: "a", Roles: []string{RoleAdmin}, ExpiresAt: time.Now().Add(-time.Minute)}), expected: http.StatusUnauthorized},
+		{name: "forged admin", cookie: func() *http.Cookie {
+			viewer := cookie(Session{Subject: "v", Roles: []string{RoleViewer}, ExpiresAt: expires})
+			_, mac, _ := strings.Cut(viewer.Value, ".")
+			payload, _ := json.Marshal(Session{Subject: "v", Roles: []string{RoleAdmin}, ExpiresAt: expires})
+			viewer.Value = base64.RawURLEncoding.EncodeToString(payload) + "." + mac
+			return viewer
+		}(), expected: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/admin/cache/stats", nil)
+			if tc.cookie != nil {
+				req.AddCookie(tc.cookie)
+			}
+			rec := httptest.NewRecorder()
+			authenticator.RequireAPI(RoleAdmin, ok).ServeHTTP(rec, req)
+			if rec.Code != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, rec.Code)
+			}
+		})
+	}
+
+	// Pages send browsers without a session through the login flow
+	rec := httptest.NewRecorder()
+	authenticator.Require(RoleAdmin, ok).ServeHTTP(rec, httptest.NewRequest("GET", "/admin/?tab=keys", nil))
+	if want := "/auth/login?return_to=" + url.QueryEscape("/admin/?tab=keys"); rec.Code != http.StatusFound || rec.Header().Get("Location") != want {
+		t.Errorf("Expected a redirect to %s, got %d to %s", want, rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestSafeReturnTo(t *testing.T) {
+	testCases := map[string]string{
+		"/dashboard/rates?pair=USD-INR": "/dashboard/rates?pair=USD-INR",
+		"/admin/":                       "/admin/",
+		"":                              "/dashboard/",
+		"https://evil.example/":         "/dashboard/",
+		"//evil.example/":               "/dashboard/",
+		`/\evil.example/`:               "/dashboard/",
+		`/\/evil.example/`:              "/dashboard/",
+		"/\t/evil.example/":             "/dashboard/",
+		"javascript:alert(1)":           "/dashboard/",
+		"dashboard/":                    "/dashboard/",
+	}
+
+	for returnTo, expected := range testCases {
+		if got := safeReturnTo(returnTo); got != expected {
+			t.Errorf("safeReturnTo(%q) = %q, want %q", returnTo, got, expected)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSession = errors.New("invalid session")
+	ErrSessionExpired = errors.New("session expired")
+)
+
+type Session struct {
+	Subject   string    `json:"sub"`
+	Email     string    `json:"email,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Roles     []string  `json:"roles"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+func (s *Session) HasRole(role string) bool {
+	for _, r := range s.Roles {
+		if r == role || r == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// signer produces and verifies HMAC-signed cookie values of the form payload.signature
+type signer struct {
+	secret []byte
+}
+
+func (s *signer) sign(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.mac(encoded), nil
+}
+
+func (s *signer) verify(value string, v interface{}) error {
+	encoded, mac, found := strings.Cut(value, ".")
+	if !found {
+		return ErrInvalidSession
+	}
+
+	if !hmac.Equal([]byte(mac), []byte(s.mac(encoded))) {
+		return ErrInvalidSession
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidSession
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidSession
+	}
+
+	return nil
+}
+
+func (s *signer) mac(encoded string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
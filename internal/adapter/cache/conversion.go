@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+)
+
+// maxConversionEntries bounds the conversion cache; when full, expired
+// entries are dropped first and then the ones closest to expiring
+const maxConversionEntries = 10000
+
+type conversionEntry struct {
+	result  model.ConversionResult
+	expires time.Time
+}
+
+// ConversionCache keeps conversion results for a short TTL, so a client
+// retrying a conversion gets the original result and receipt back
+type ConversionCache struct {
+	ttl     time.Duration
+	metrics *metrics.Metrics
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]conversionEntry
+}
+
+func NewConversionCache(ttl time.Duration, metrics *metrics.Metrics) *ConversionCache {
+	return &ConversionCache{
+		ttl:     ttl,
+		metrics: metrics,
+		now:     time.Now,
+		entries: make(map[string]conversionEntry),
+	}
+}
+
+func (c *ConversionCache) Get(ctx context.Context, key string) (*model.ConversionResult, bool) {
+	c.mutex.Lock()
+	entry, found := c.entries[key]
+	if found && !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		found = false
+	}
+	c.mutex.Unlock()
+
+	if !found {
+		c.metrics.ConversionCacheLookups.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	c.metrics.ConversionCacheLookups.WithLabelValues("hit").Inc()
+	result := copyConversion(entry.result)
+	return &result, true
+}
+
+func (c *ConversionCache) Set(ctx context.Context, key string, result *model.ConversionResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= maxConversionEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	// Entries share one TTL, so the one expiring first is also the oldest
+	for len(c.entries) >= maxConversionEntries {
+		oldest := ""
+		for k, entry := range c.entries {
+			if oldest == "" || entry.expires.Before(c.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+
+	c.entries[key] = conversionEntry{result: copyConversion(*result), expires: now.Add(c.ttl)}
+}
+
+// copyConversion copies result so that neither the caller nor the cache can
+// change the other's rate snapshot or cash rounding
+func copyConversion(result model.ConversionResult) model.ConversionResult {
+	if result.RateSnapshot != nil {
+		snapshot := *result.RateSnapshot
+		result.RateSnapshot = &snapshot
+	}
+	if result.Cash != nil {
+		cash := *result.Cash
+		result.Cash = &cash
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestConversionCache(t *testing.T) {
+	ctx := context.Background()
+	lookups := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "conversion_cache_lookups_total"}, []string{"result"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(lookups)
+
+	now := time.Date(2025, 5, 15, 12, 0, 0, 0, time.UTC)
+	cache := NewConversionCache(10*time.Second, &metrics.Metrics{ConversionCacheLookups: lookups})
+	cache.now = func() time.Time { return now }
+
+	result := &model.ConversionResult{
+		ID:           "conv_1",
+		ToAmount:     8300,
+		RateSnapshot: &model.ExchangeRate{Rate: 83},
+	}
+	cache.Set(ctx, "key", result)
+	result.RateSnapshot.Rate = 0
+
+	cached, found := cache.Get(ctx, "key")
+	if !found || cached.ID != "conv_1" || cached.RateSnapshot.Rate != 83 {
+		t.Fatalf("Expected an unchanged copy of the result, got %+v", cached)
+	}
+	cached.RateSnapshot.Rate = 0
+	if again, _ := cache.Get(ctx, "key"); again.RateSnapshot.Rate != 83 {
+		t.Error("Expected callers not to share the cached snapshot")
+	}
+
+	if _, found := cache.Get(ctx, "other"); found {
+		t.Error("Expected a miss for another key")
+	}
+
+	now = now.Add(10 * time.Second)
+	if _, found := cache.Get(ctx, "key"); found {
+		t.Error("Expected the entry to expire after the TTL")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counted := map[string]float64{}
+	for _, m := range families[0].GetMetric() {
+		counted[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+	}
+	if counted["hit"] != 2 || counted["miss"] != 2 {
+		t.Errorf("Expected 2 hits and 2 misses, got %v", counted)
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/utils"
+)
+
+// snapshotContext binds sealed and signed entries to cache snapshots, so they
+// cannot be passed off as any other data protected with the same key
+var snapshotContext = []byte("exchange-rate-service/cache-snapshot/v1")
+
+var ErrSnapshotDecryption = errors.New("cache snapshot entry could not be decrypted")
+
+// EncryptedCache is a MemoryCache whose entries are sealed with AES-GCM
+// whenever they leave memory. Each entry of a snapshot is sealed on its own
+// with a random nonce, so the file reveals neither pairs nor rates, only how
+// many entries it holds. Lookups and everything else stay those of the
+// wrapped cache.
+type EncryptedCache struct {
+	*MemoryCache
+	aead cipher.AEAD
+}
+
+// NewEncryptedCache wraps cache with key, which must be 16, 24 or 32 bytes
+// for AES-128, AES-192 or AES-256
+func NewEncryptedCache(cache *MemoryCache, key []byte) (*EncryptedCache, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedCache{MemoryCache: cache, aead: aead}, nil
+}
+
+// SaveSnapshot writes all shared cache entries to path, each sealed
+func (c *EncryptedCache) SaveSnapshot(path string) error {
+	rates := c.snapshot()
+	sealed := make([][]byte, 0, len(rates))
+	for _, rate := range rates {
+		entry, err := c.seal(rate)
+		if err != nil {
+			return err
+		}
+		sealed = append(sealed, entry)
+	}
+
+	if err := utils.WriteJSONFile(path, sealed); err != nil {
+		return err
+	}
+
+	c.log.Debug("Encrypted cache snapshot saved", "path", path, "count", len(sealed))
+	return nil
+}
+
+// LoadSnapshot restores entries from a snapshot written by SaveSnapshot,
+// skipping those already expired. Nothing is restored if any entry fails to
+// decrypt, as happens with another key or a snapshot written in the clear.
+func (c *EncryptedCache) LoadSnapshot(path string) (int, error) {
+	var sealed [][]byte
+	if err := utils.ReadJSONFile(path, &sealed); err != nil {
+		return 0, err
+	}
+
+	rates := make([]*model.ExchangeRate, 0, len(sealed))
+	for _, entry := range sealed {
+		rate, err := c.open(entry)
+		if err != nil {
+			return 0, err
+		}
+		rates = append(rates, rate)
+	}
+
+	restored := c.restore(rates)
+	c.log.Info("Encrypted cache snapshot loaded", "path", path, "restored", restored, "skipped", len(rates)-restored)
+	return restored, nil
+}
+
+// RunSnapshots saves a sealed snapshot every interval and once more when ctx is cancelled
+func (c *EncryptedCache) RunSnapshots(ctx context.Context, path string, interval time.Duration) {
+	runSnapshots(ctx, interval, func() error { return c.SaveSnapshot(path) }, c.log)
+}
+
+// seal returns the nonce followed by the encrypted JSON of rate
+func (c *EncryptedCache) seal(rate *model.ExchangeRate) ([]byte, error) {
+	plaintext, err := json.Marshal(rate)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, snapshotContext), nil
+}
+
+func (c *EncryptedCache) open(entry []byte) (*model.ExchangeRate, error) {
+	if len(entry) < c.aead.NonceSize() {
+		return nil, ErrSnapshotDecryption
+	}
+
+	nonce, ciphertext := entry[:c.aead.NonceSize()], entry[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, snapshotContext)
+	if err != nil {
+		return nil, ErrSnapshotDecryption
+	}
+
+	var rate model.ExchangeRate
+	if err := json.Unmarshal(plaintext, &rate); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSnapshotDecryption, err)
+	}
+	return &rate, nil
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+func newEncryptedCache(t *testing.T, key string) *EncryptedCache {
+	t.Helper()
+	encrypted, err := NewEncryptedCache(NewMemoryCache(time.Hour, logger.NewLogger("error")), []byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encrypted
+}
+
+func TestEncryptedSnapshots(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := "0123456789abcdef0123456789abcdef"
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	source := newEncryptedCache(t, key)
+	source.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.25, Date: today, LastUpdated: time.Now()})
+	if err := source.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(content, []byte("INR")) || bytes.Contains(content, []byte("83.25")) {
+		t.Fatalf("Expected the snapshot to be sealed, got %s", content)
+	}
+
+	restored := newEncryptedCache(t, key)
+	if count, err := restored.LoadSnapshot(path); err != nil || count != 1 {
+		t.Fatalf("Expected one entry restored, got %d (%v)", count, err)
+	}
+	if rate, found := restored.Get(ctx, pair, today); !found || rate.Rate != 83.25 {
+		t.Errorf("Expected the restored rate, got %v", rate)
+	}
+
+	if count, err := newEncryptedCache(t, "fedcba9876543210fedcba9876543210").LoadSnapshot(path); !errors.Is(err, ErrSnapshotDecryption) || count != 0 {
+		t.Errorf("Expected another key to restore nothing, got %d (%v)", count, err)
+	}
+}
+
+func TestEncryptedCacheRejectsClearSnapshots(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	plain := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	plain.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: time.Now().UTC(), LastUpdated: time.Now()})
+	if err := plain.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if count, err := newEncryptedCache(t, "0123456789abcdef").LoadSnapshot(path); err == nil || count != 0 {
+		t.Errorf("Expected a snapshot in the clear to be refused, got %d (%v)", count, err)
+	}
+}
+
+func TestNewEncryptedCacheRejectsBadKeys(t *testing.T) {
+	if _, err := NewEncryptedCache(NewMemoryCache(time.Hour, logger.NewLogger("error")), []byte("short")); err == nil {
+		t.Error("Expected a 5 byte key to be refused")
+	}
+}
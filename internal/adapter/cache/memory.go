@@ -3,28 +3,45 @@ package cache
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
 	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
 )
 
 type MemoryCache struct {
 	cacheMap     map[string]*model.ExchangeRate
+	// namespaces keeps rates that must not be shared, such as those adjusted
+	// for a tenant, apart from cacheMap
+	namespaces   map[string]map[string]*model.ExchangeRate
 	mutex        sync.RWMutex
 	cacheTTL     time.Duration
+	clock        clock.Clock
 	log          *logger.Logger
+	counters     lookupCounters
 }
 
 func NewMemoryCache(cacheTTL time.Duration, log *logger.Logger) *MemoryCache {
 	return &MemoryCache{
 		cacheMap: make(map[string]*model.ExchangeRate),
+		namespaces: make(map[string]map[string]*model.ExchangeRate),
 		cacheTTL: cacheTTL,
+		clock:    clock.System,
 		log:      log,
 	}
 }
 
+// UseClock replaces the wall clock entries are expired against
+func (c *MemoryCache) UseClock(clock clock.Clock) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.clock = clock
+}
+
 func getCacheKey(pair model.CurrencyPair, date time.Time) string {
 	dateStr := date.Format("2006-01-02")
 	return fmt.Sprintf("%s-%s-%s", pair.BaseCurrency, pair.TargetCurrency, dateStr)
@@ -38,15 +55,18 @@ func (c *MemoryCache) Get(ctx context.Context, pair model.CurrencyPair, date tim
 	rate, found := c.cacheMap[key]
 	
 	if found {
-		if time.Since(rate.LastUpdated) > c.cacheTTL {
+		if c.clock.Now().Sub(rate.LastUpdated) > c.cacheTTL {
 			c.log.Debug("Cache entry expired", "key", key)
+			c.counters.recordLookup(c.clock.Now(), false)
 			return nil, false
 		}
 		c.log.Debug("Cache hit", "key", key)
+		c.counters.recordLookup(c.clock.Now(), true)
 		return rate, true
 	}
 	
 	c.log.Debug("Cache miss", "key", key)
+	c.counters.recordLookup(c.clock.Now(), false)
 	return nil, false
 }
 
@@ -70,20 +90,129 @@ func (c *MemoryCache) ClearExpired(ctx context.Context) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	
-	now := time.Now()
-	expiredKeys := make([]string, 0)
+	now := c.clock.Now()
+	expired := make([][2]string, 0)
 	
-	for key, rate := range c.cacheMap {
+	c.eachEntry(func(namespace, key string, rate *model.ExchangeRate) {
 		if now.Sub(rate.LastUpdated) > c.cacheTTL {
-			expiredKeys = append(expiredKeys, key)
+			expired = append(expired, [2]string{namespace, key})
 		}
-	}
+	})
 	
-	for _, key := range expiredKeys {
-		delete(c.cacheMap, key)
-		c.log.Debug("Removed expired cache entry", "key", key)
+	for _, entry := range expired {
+		c.remove(entry[0], entry[1])
+		c.log.Debug("Removed expired cache entry", "namespace", entry[0], "key", entry[1])
 	}
 	
-	c.log.Info("Cleared expired cache entries", "count", len(expiredKeys))
+	c.counters.recordEvictions(model.EvictionExpired, len(expired))
+	c.log.Info("Cleared expired cache entries", "count", len(expired))
+	return nil
+}
+
+// SaveSnapshot writes all shared cache entries to path
+func (c *MemoryCache) SaveSnapshot(path string) error {
+	rates := c.snapshot()
+	if err := utils.WriteJSONFile(path, rates); err != nil {
+		return err
+	}
+
+	c.log.Debug("Cache snapshot saved", "path", path, "count", len(rates))
 	return nil
 }
+
+// LoadSnapshot restores entries from path, skipping those already expired.
+// It returns the number of entries restored.
+func (c *MemoryCache) LoadSnapshot(path string) (int, error) {
+	var rates []*model.ExchangeRate
+	if err := utils.ReadJSONFile(path, &rates); err != nil {
+		return 0, err
+	}
+
+	restored := c.restore(rates)
+	c.log.Info("Cache snapshot loaded", "path", path, "restored", restored, "skipped", len(rates)-restored)
+	return restored, nil
+}
+
+// snapshot returns the shared entries; namespaced ones are never persisted
+func (c *MemoryCache) snapshot() []*model.ExchangeRate {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	rates := make([]*model.ExchangeRate, 0, len(c.cacheMap))
+	for _, rate := range c.cacheMap {
+		rates = append(rates, rate)
+	}
+	return rates
+}
+
+// restore adds the rates not yet expired and returns how many it added
+func (c *MemoryCache) restore(rates []*model.ExchangeRate) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	restored := 0
+	for _, rate := range rates {
+		if c.clock.Now().Sub(rate.LastUpdated) > c.cacheTTL {
+			continue
+		}
+
+		pair := model.CurrencyPair{
+			BaseCurrency:   rate.BaseCurrency,
+			TargetCurrency: rate.TargetCurrency,
+		}
+		c.cacheMap[getCacheKey(pair, rate.Date)] = rate
+		restored++
+	}
+	return restored
+}
+
+// RunSnapshots saves a snapshot every interval and once more when ctx is cancelled
+func (c *MemoryCache) RunSnapshots(ctx context.Context, path string, interval time.Duration) {
+	runSnapshots(ctx, interval, func() error { return c.SaveSnapshot(path) }, c.log)
+}
+
+func runSnapshots(ctx context.Context, interval time.Duration, save func() error, log *logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := save(); err != nil {
+				log.Error("Failed to save cache snapshot", "error", err)
+			}
+		case <-ctx.Done():
+			if err := save(); err != nil {
+				log.Error("Failed to save cache snapshot on shutdown", "error", err)
+			}
+			return
+		}
+	}
+}
+
+// EvictOldest removes the given fraction of entries, least recently updated
+// first, whether shared or namespaced
+func (c *MemoryCache) EvictOldest(fraction float64) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	type entry struct {
+		namespace, key string
+		updated        time.Time
+	}
+	entries := make([]entry, 0, len(c.cacheMap))
+	c.eachEntry(func(namespace, key string, rate *model.ExchangeRate) {
+		entries = append(entries, entry{namespace, key, rate.LastUpdated})
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].updated.Before(entries[j].updated)
+	})
+
+	count := int(float64(len(entries)) * fraction)
+	for _, e := range entries[:count] {
+		c.remove(e.namespace, e.key)
+	}
+	c.counters.recordEvictions(model.EvictionPressure, count)
+
+	return count
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(now)
+	cache := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	cache.UseClock(fake)
+
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	cache.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: now, LastUpdated: now})
+
+	fake.Advance(59 * time.Minute)
+	if _, found := cache.Get(ctx, pair, now); !found {
+		t.Fatal("Expected the rate to be served within its TTL")
+	}
+
+	fake.Advance(2 * time.Minute)
+	if _, found := cache.Get(ctx, pair, now); found {
+		t.Fatal("Expected the rate to expire after its TTL")
+	}
+
+	cache.ClearExpired(ctx)
+	if stats := cache.Stats(ctx); stats.Entries != 0 {
+		t.Errorf("Expected the expired rate to be cleared, %d entries left", stats.Entries)
+	}
+}
+
+func TestMemoryCacheEvictsOldestFirst(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	cache := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	cache.UseClock(clock.NewFake(now))
+
+	targets := []model.Currency{model.INR, model.EUR, model.GBP, model.JPY}
+	for i, target := range targets {
+		updated := now.Add(-time.Duration(len(targets)-i) * time.Minute)
+		cache.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: target, Rate: 1, Date: now, LastUpdated: updated})
+	}
+	// Namespaced entries are evicted alike; this one is the newest
+	cache.SetIn(ctx, "tenant:a", &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 2, Date: now, LastUpdated: now})
+
+	if evicted := cache.EvictOldest(0.4); evicted != 2 {
+		t.Fatalf("Expected 2 of 5 entries evicted, got %d", evicted)
+	}
+	for i, target := range targets {
+		_, found := cache.Get(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: target}, now)
+		if found != (i >= 2) {
+			t.Errorf("Expected USD-%s found: %v, got %v", target, i >= 2, found)
+		}
+	}
+	if _, found := cache.GetIn(ctx, "tenant:a", model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}, now); !found {
+		t.Error("Expected the newest namespaced entry to be kept")
+	}
+	if evictions := cache.Stats(ctx).Evictions[model.EvictionPressure]; evictions != 2 {
+		t.Errorf("Expected 2 pressure evictions counted, got %d", evictions)
+	}
+}
+
+func TestMemoryCacheSnapshot(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(now)
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	cache := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	cache.UseClock(fake)
+	cache.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: now, LastUpdated: now})
+	cache.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.EUR, Rate: 0.9, Date: now, LastUpdated: now.Add(-50 * time.Minute)})
+	cache.SetIn(ctx, "tenant:a", &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.GBP, Rate: 0.8, Date: now, LastUpdated: now})
+	if err := cache.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// USD-EUR expires before the restart
+	fake.Advance(20 * time.Minute)
+	restarted := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	restarted.UseClock(fake)
+	restored, err := restarted.LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != 1 {
+		t.Errorf("Expected only the unexpired shared rate to be restored, got %d", restored)
+	}
+	if rate, found := restarted.Get(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}, now); !found || rate.Rate != 83 {
+		t.Errorf("Expected USD-INR to survive the restart, got %+v", rate)
+	}
+	if _, found := restarted.GetIn(ctx, "tenant:a", model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.GBP}, now); found {
+		t.Error("Expected namespaced rates to be left out of snapshots")
+	}
+
+	if restored, err := NewMemoryCache(time.Hour, logger.NewLogger("error")).LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err != nil || restored != 0 {
+		t.Errorf("Expected a missing snapshot to start an empty cache, got %d, %v", restored, err)
+	}
+}
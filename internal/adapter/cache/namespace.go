@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// SharedNamespace names the unadjusted rates in CacheStats.Namespaces
+const SharedNamespace = "shared"
+
+// GetIn looks a rate up in namespace only, never in the shared rates. Lookups
+// are not counted in the hit ratios, which describe the shared rates.
+func (c *MemoryCache) GetIn(ctx context.Context, namespace string, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	rate, found := c.namespaces[namespace][getCacheKey(pair, date)]
+	if !found || c.clock.Now().Sub(rate.LastUpdated) > c.cacheTTL {
+		return nil, false
+	}
+	return rate, true
+}
+
+// SetIn stores rate in namespace, where only GetIn finds it. Namespaced rates
+// expire and are evicted like shared ones, but are never saved in snapshots.
+func (c *MemoryCache) SetIn(ctx context.Context, namespace string, rate *model.ExchangeRate) error {
+	if namespace == SharedNamespace {
+		return fmt.Errorf("cache namespace %q is reserved for shared rates", namespace)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries, exists := c.namespaces[namespace]
+	if !exists {
+		entries = make(map[string]*model.ExchangeRate)
+		c.namespaces[namespace] = entries
+	}
+
+	pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+	entries[getCacheKey(pair, rate.Date)] = rate
+	return nil
+}
+
+// DropNamespaces removes every namespaced rate, for example once the
+// adjustments they were computed with change. Shared rates stay.
+func (c *MemoryCache) DropNamespaces() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.namespaces = make(map[string]map[string]*model.ExchangeRate)
+}
+
+// eachEntry calls visit with every shared and namespaced entry, the shared
+// ones under SharedNamespace. The caller holds the mutex.
+func (c *MemoryCache) eachEntry(visit func(namespace, key string, rate *model.ExchangeRate)) {
+	for key, rate := range c.cacheMap {
+		visit(SharedNamespace, key, rate)
+	}
+	for namespace, entries := range c.namespaces {
+		for key, rate := range entries {
+			visit(namespace, key, rate)
+		}
+	}
+}
+
+// remove deletes an entry found by eachEntry. The caller holds the mutex.
+func (c *MemoryCache) remove(namespace, key string) {
+	if namespace == SharedNamespace {
+		delete(c.cacheMap, key)
+		return
+	}
+	delete(c.namespaces[namespace], key)
+	if len(c.namespaces[namespace]) == 0 {
+		delete(c.namespaces, namespace)
+	}
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+)
+
+// point is a compact RatePoint
+type point struct {
+	unix int64
+	rate float64
+}
+
+// ring holds the last len(points) values of a pair, overwriting the oldest
+type ring struct {
+	points []point
+	next   int
+	full   bool
+}
+
+// RingBuffer keeps a fixed number of recent rates per pair in memory, from
+// every refresh, provider push and intraday sample
+type RingBuffer struct {
+	capacity int
+	mutex    sync.RWMutex
+	rings    map[model.CurrencyPair]*ring
+	clock    clock.Clock
+}
+
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{
+		capacity: capacity,
+		rings:    make(map[model.CurrencyPair]*ring),
+		clock:    clock.System,
+	}
+}
+
+// UseClock replaces the wall clock rates without a LastUpdated are stamped with
+func (b *RingBuffer) UseClock(clock clock.Clock) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.clock = clock
+}
+
+// Publish records rates as the newest point of their pairs
+func (b *RingBuffer) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.clock.Now().Unix()
+	for _, rate := range rates {
+		pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+		r, exists := b.rings[pair]
+		if !exists {
+			r = &ring{points: make([]point, b.capacity)}
+			b.rings[pair] = r
+		}
+
+		p := point{unix: now, rate: rate.Rate}
+		if !rate.LastUpdated.IsZero() {
+			p.unix = rate.LastUpdated.Unix()
+		}
+		r.points[r.next] = p
+		r.next = (r.next + 1) % b.capacity
+		r.full = r.full || r.next == 0
+	}
+	return nil
+}
+
+func (b *RingBuffer) Recent(pair model.CurrencyPair, n int) []model.RatePoint {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	r, exists := b.rings[pair]
+	if !exists {
+		return []model.RatePoint{}
+	}
+
+	size := r.next
+	if r.full {
+		size = b.capacity
+	}
+	n = min(n, size)
+
+	points := make([]model.RatePoint, n)
+	for i := range points {
+		p := r.points[(r.next-n+i+b.capacity)%b.capacity]
+		points[i] = model.RatePoint{Rate: p.rate, Time: time.Unix(p.unix, 0).UTC()}
+	}
+	return points
+}
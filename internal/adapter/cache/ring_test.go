@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+)
+
+func TestRingBufferKeepsNewest(t *testing.T) {
+	buffer := NewRingBuffer(3)
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if points := buffer.Recent(pair, 5); len(points) != 0 {
+		t.Fatalf("Expected no points for an unknown pair, got %v", points)
+	}
+
+	for i := 0; i < 5; i++ {
+		buffer.Publish(context.Background(), []model.ExchangeRate{{
+			BaseCurrency:   model.USD,
+			TargetCurrency: model.INR,
+			Rate:           float64(80 + i),
+			LastUpdated:    start.Add(time.Duration(i) * time.Minute),
+		}})
+
+		points := buffer.Recent(pair, 10)
+		if len(points) != min(i+1, 3) || points[len(points)-1].Rate != float64(80+i) {
+			t.Fatalf("After %d publishes got %v", i+1, points)
+		}
+	}
+
+	points := buffer.Recent(pair, 2)
+	if len(points) != 2 || points[0].Rate != 83 || points[1].Rate != 84 || !points[1].Time.Equal(start.Add(4*time.Minute)) {
+		t.Errorf("Expected the two newest points oldest first, got %v", points)
+	}
+}
+
+func TestRingBufferStampsWithClock(t *testing.T) {
+	buffer := NewRingBuffer(2)
+	now := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	buffer.UseClock(clock.NewFake(now))
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+
+	buffer.Publish(context.Background(), []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83}})
+	if points := buffer.Recent(pair, 1); len(points) != 1 || !points[0].Time.Equal(now) {
+		t.Errorf("Expected a rate without LastUpdated stamped at %v, got %v", now, points)
+	}
+}
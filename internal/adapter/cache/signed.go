@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/utils"
+)
+
+var ErrSnapshotSignature = errors.New("cache snapshot signature verification failed")
+
+type signedEntry struct {
+	Rate      *model.ExchangeRate `json:"rate"`
+	Signature []byte              `json:"signature"`
+}
+
+// SignedCache is a MemoryCache whose snapshot entries carry an HMAC-SHA256
+// signature, so replicas sharing a snapshot only restore rates written by a
+// holder of the key. Unlike EncryptedCache the rates stay readable.
+type SignedCache struct {
+	*MemoryCache
+	key []byte
+}
+
+func NewSignedCache(cache *MemoryCache, key []byte) *SignedCache {
+	return &SignedCache{MemoryCache: cache, key: key}
+}
+
+// SaveSnapshot writes all shared cache entries to path, each signed
+func (c *SignedCache) SaveSnapshot(path string) error {
+	rates := c.snapshot()
+	entries := make([]signedEntry, 0, len(rates))
+	for _, rate := range rates {
+		signature, err := c.sign(rate)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, signedEntry{Rate: rate, Signature: signature})
+	}
+
+	if err := utils.WriteJSONFile(path, entries); err != nil {
+		return err
+	}
+
+	c.log.Debug("Signed cache snapshot saved", "path", path, "count", len(entries))
+	return nil
+}
+
+// LoadSnapshot restores entries from a snapshot written by SaveSnapshot,
+// skipping those already expired. A single entry failing verification means
+// the file was tampered with, so nothing is restored.
+func (c *SignedCache) LoadSnapshot(path string) (int, error) {
+	var entries []signedEntry
+	if err := utils.ReadJSONFile(path, &entries); err != nil {
+		return 0, err
+	}
+
+	rates := make([]*model.ExchangeRate, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Rate == nil {
+			return 0, ErrSnapshotSignature
+		}
+		expected, err := c.sign(entry.Rate)
+		if err != nil || !hmac.Equal(expected, entry.Signature) {
+			return 0, ErrSnapshotSignature
+		}
+		rates = append(rates, entry.Rate)
+	}
+
+	restored := c.restore(rates)
+	c.log.Info("Signed cache snapshot loaded", "path", path, "restored", restored, "skipped", len(rates)-restored)
+	return restored, nil
+}
+
+// RunSnapshots saves a signed snapshot every interval and once more when ctx is cancelled
+func (c *SignedCache) RunSnapshots(ctx context.Context, path string, interval time.Duration) {
+	runSnapshots(ctx, interval, func() error { return c.SaveSnapshot(path) }, c.log)
+}
+
+// sign covers the JSON encoding of rate, so every field is protected and a
+// decoded entry encodes back to the same bytes
+func (c *SignedCache) sign(rate *model.ExchangeRate) ([]byte, error) {
+	encoded, err := json.Marshal(rate)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(snapshotContext)
+	mac.Write(encoded)
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestSignedSnapshots(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := []byte("0123456789abcdef0123456789abcdef")
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	source := NewSignedCache(NewMemoryCache(time.Hour, logger.NewLogger("error")), key)
+	source.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.25, Date: today, LastUpdated: time.Now()})
+	if err := source.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewSignedCache(NewMemoryCache(time.Hour, logger.NewLogger("error")), key)
+	if count, err := restored.LoadSnapshot(path); err != nil || count != 1 {
+		t.Fatalf("Expected one entry restored, got %d (%v)", count, err)
+	}
+	if rate, found := restored.Get(ctx, pair, today); !found || rate.Rate != 83.25 {
+		t.Errorf("Expected the restored rate, got %v", rate)
+	}
+
+	other := NewSignedCache(NewMemoryCache(time.Hour, logger.NewLogger("error")), []byte("fedcba9876543210fedcba9876543210"))
+	if count, err := other.LoadSnapshot(path); !errors.Is(err, ErrSnapshotSignature) || count != 0 {
+		t.Errorf("Expected another key to restore nothing, got %d (%v)", count, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(content), "83.25", "93.25", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tampered := NewSignedCache(NewMemoryCache(time.Hour, logger.NewLogger("error")), key)
+	if count, err := tampered.LoadSnapshot(path); !errors.Is(err, ErrSnapshotSignature) || count != 0 {
+		t.Errorf("Expected a tampered rate to restore nothing, got %d (%v)", count, err)
+	}
+	if _, found := tampered.Get(ctx, pair, today); found {
+		t.Error("Expected no rate from a tampered snapshot")
+	}
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// lookupWindows are the trailing windows hit ratios are reported over. The
+// longest must fit in the minute buckets of lookupCounters.
+var lookupWindows = []struct {
+	name    string
+	minutes int64
+}{
+	{"1m", 1},
+	{"5m", 5},
+	{"15m", 15},
+	{"1h", 60},
+}
+
+// entryOverhead approximates what an entry costs besides its key: the rate
+// itself, the pointer to it and the map's string header for the key
+const entryOverhead = int64(unsafe.Sizeof(model.ExchangeRate{})) + 8 + 16
+
+type lookupBucket struct {
+	minute int64
+	hits   int64
+	misses int64
+}
+
+// lookupCounters counts cache lookups per minute for the last hour, and
+// evictions by reason since start
+type lookupCounters struct {
+	mutex     sync.Mutex
+	buckets   [60]lookupBucket
+	hits      int64
+	misses    int64
+	evictions map[string]int64
+}
+
+func (c *lookupCounters) recordLookup(now time.Time, hit bool) {
+	minute := now.Unix() / 60
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	bucket := &c.buckets[minute%int64(len(c.buckets))]
+	if bucket.minute != minute {
+		*bucket = lookupBucket{minute: minute}
+	}
+	if hit {
+		bucket.hits++
+		c.hits++
+	} else {
+		bucket.misses++
+		c.misses++
+	}
+}
+
+func (c *lookupCounters) recordEvictions(reason string, count int) {
+	if count == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.evictions == nil {
+		c.evictions = make(map[string]int64)
+	}
+	c.evictions[reason] += int64(count)
+}
+
+// fill copies the counters into stats, with hit ratios of the windows ending at now
+func (c *lookupCounters) fill(stats *model.CacheStats, now time.Time) {
+	minute := now.Unix() / 60
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats.Hits = c.hits
+	stats.Misses = c.misses
+	stats.Evictions = map[string]int64{
+		model.EvictionExpired:  c.evictions[model.EvictionExpired],
+		model.EvictionPressure: c.evictions[model.EvictionPressure],
+	}
+
+	stats.HitRatios = make([]model.CacheHitRatio, 0, len(lookupWindows))
+	for _, window := range lookupWindows {
+		ratio := model.CacheHitRatio{Window: window.name}
+		for _, bucket := range c.buckets {
+			if bucket.minute > minute-window.minutes && bucket.minute <= minute {
+				ratio.Hits += bucket.hits
+				ratio.Misses += bucket.misses
+			}
+		}
+		if total := ratio.Hits + ratio.Misses; total > 0 {
+			ratio.Ratio = float64(ratio.Hits) / float64(total)
+		}
+		stats.HitRatios = append(stats.HitRatios, ratio)
+	}
+}
+
+// Stats reports the cache contents and lookup counters. EstimatedBytes counts
+// the entries and their keys only, not the map's own bookkeeping. Namespaces
+// lists the shared rates first, then the other namespaces by name.
+func (c *MemoryCache) Stats(ctx context.Context) model.CacheStats {
+	now := c.clock.Now()
+	var stats model.CacheStats
+
+	namespaces := map[string]*model.CacheNamespaceStats{
+		SharedNamespace: {Namespace: SharedNamespace},
+	}
+	c.mutex.RLock()
+	c.eachEntry(func(namespace, key string, rate *model.ExchangeRate) {
+		usage, exists := namespaces[namespace]
+		if !exists {
+			usage = &model.CacheNamespaceStats{Namespace: namespace}
+			namespaces[namespace] = usage
+		}
+		usage.Entries++
+		usage.EstimatedBytes += entryOverhead + int64(len(key))
+
+		stats.Entries++
+		stats.EstimatedBytes += entryOverhead + int64(len(key))
+		if now.Sub(rate.LastUpdated) > c.cacheTTL {
+			stats.ExpiredEntries++
+		}
+	})
+	c.mutex.RUnlock()
+
+	stats.Namespaces = make([]model.CacheNamespaceStats, 0, len(namespaces))
+	for _, usage := range namespaces {
+		stats.Namespaces = append(stats.Namespaces, *usage)
+	}
+	sort.Slice(stats.Namespaces, func(i, j int) bool {
+		if (stats.Namespaces[i].Namespace == SharedNamespace) != (stats.Namespaces[j].Namespace == SharedNamespace) {
+			return stats.Namespaces[i].Namespace == SharedNamespace
+		}
+		return stats.Namespaces[i].Namespace < stats.Namespaces[j].Namespace
+	})
+
+	c.counters.fill(&stats, now)
+	return stats
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestMemoryCacheStats(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	today := time.Now().UTC()
+
+	cache.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: today, LastUpdated: time.Now()})
+	cache.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.EUR, Rate: 0.9, Date: today, LastUpdated: time.Now().Add(-2 * time.Hour)})
+
+	cache.Get(ctx, pair, today)
+	cache.Get(ctx, pair, today)
+	cache.Get(ctx, pair, today.AddDate(0, 0, -1))
+
+	stats := cache.Stats(ctx)
+	if stats.Entries != 2 || stats.ExpiredEntries != 1 || stats.EstimatedBytes <= 0 {
+		t.Errorf("Unexpected contents %+v", stats)
+	}
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %d and %d", stats.Hits, stats.Misses)
+	}
+	if len(stats.HitRatios) != len(lookupWindows) || stats.HitRatios[0].Ratio < 0.66 || stats.HitRatios[0].Ratio > 0.67 {
+		t.Errorf("Unexpected hit ratios %+v", stats.HitRatios)
+	}
+
+	cache.ClearExpired(ctx)
+	cache.EvictOldest(1)
+	stats = cache.Stats(ctx)
+	if stats.Entries != 0 || stats.Evictions[model.EvictionExpired] != 1 || stats.Evictions[model.EvictionPressure] != 1 {
+		t.Errorf("Unexpected evictions %+v", stats)
+	}
+}
+
+func TestLookupCountersWindows(t *testing.T) {
+	var counters lookupCounters
+	now := time.Date(2025, 1, 1, 12, 0, 30, 0, time.UTC)
+
+	counters.recordLookup(now.Add(-90*time.Minute), true)
+	counters.recordLookup(now.Add(-10*time.Minute), true)
+	counters.recordLookup(now.Add(-3*time.Minute), false)
+	counters.recordLookup(now, true)
+
+	var stats model.CacheStats
+	counters.fill(&stats, now)
+
+	want := map[string][2]int64{
+		"1m":  {1, 0},
+		"5m":  {1, 1},
+		"15m": {2, 1},
+		"1h":  {2, 1},
+	}
+	for _, ratio := range stats.HitRatios {
+		if got := [2]int64{ratio.Hits, ratio.Misses}; got != want[ratio.Window] {
+			t.Errorf("Window %s counted %v, want %v", ratio.Window, got, want[ratio.Window])
+		}
+	}
+	if stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("Expected lifetime totals of 3 hits and 1 miss, got %d and %d", stats.Hits, stats.Misses)
+	}
+}
+
+func TestMemoryCacheNamespaces(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	today := time.Now().UTC()
+
+	cache.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: today, LastUpdated: time.Now()})
+	cache.SetIn(ctx, "tenant:a", &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 85, Date: today, LastUpdated: time.Now()})
+	cache.SetIn(ctx, "tenant:b", &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 84, Date: today, LastUpdated: time.Now().Add(-2 * time.Hour)})
+
+	if rate, found := cache.Get(ctx, pair, today); !found || rate.Rate != 83 {
+		t.Errorf("Expected the shared rate, got %v", rate)
+	}
+	if rate, found := cache.GetIn(ctx, "tenant:a", pair, today); !found || rate.Rate != 85 {
+		t.Errorf("Expected tenant A's rate, got %v", rate)
+	}
+	if _, found := cache.GetIn(ctx, "tenant:c", pair, today); found {
+		t.Error("Expected nothing in an unused namespace")
+	}
+	if err := cache.SetIn(ctx, SharedNamespace, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 1, Date: today}); err == nil {
+		t.Error("Expected the shared namespace to be reserved")
+	}
+
+	stats := cache.Stats(ctx)
+	if stats.Entries != 3 || stats.ExpiredEntries != 1 || len(stats.Namespaces) != 3 {
+		t.Fatalf("Unexpected contents %+v", stats)
+	}
+	var total int64
+	for i, want := range []string{SharedNamespace, "tenant:a", "tenant:b"} {
+		usage := stats.Namespaces[i]
+		if usage.Namespace != want || usage.Entries != 1 || usage.EstimatedBytes <= 0 {
+			t.Errorf("Expected namespace %s with one entry, got %+v", want, usage)
+		}
+		total += usage.EstimatedBytes
+	}
+	if total != stats.EstimatedBytes {
+		t.Errorf("Expected namespaces to add up to %d bytes, got %d", stats.EstimatedBytes, total)
+	}
+
+	// Snapshots only hold shared rates
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := cache.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	restored := NewMemoryCache(time.Hour, logger.NewLogger("error"))
+	if count, err := restored.LoadSnapshot(path); err != nil || count != 1 {
+		t.Errorf("Expected one shared rate in the snapshot, got %d (%v)", count, err)
+	}
+
+	cache.ClearExpired(ctx)
+	if stats := cache.Stats(ctx); len(stats.Namespaces) != 2 || stats.Evictions[model.EvictionExpired] != 1 {
+		t.Errorf("Expected tenant B's expired rate cleared with its namespace, got %+v", stats)
+	}
+	cache.DropNamespaces()
+	if _, found := cache.GetIn(ctx, "tenant:a", pair, today); found {
+		t.Error("Expected namespaced rates to be dropped")
+	}
+	if _, found := cache.Get(ctx, pair, today); !found {
+		t.Error("Expected the shared rate to survive dropping namespaces")
+	}
+}
@@ -0,0 +1,38 @@
+// Package chaos injects faults for resilience testing. It must never be enabled in production.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+var ErrInjected = errors.New("chaos: injected failure")
+
+type Options struct {
+	Latency       time.Duration
+	ErrorRate     float64
+	MalformedRate float64
+}
+
+// delay sleeps for a random duration up to max, returning early when ctx is done
+func delay(ctx context.Context, max time.Duration) error {
+	if max <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(rand.N(max))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func chance(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
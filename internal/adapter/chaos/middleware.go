@@ -0,0 +1,38 @@
+package chaos
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+// Middleware injects latency and 503 responses in front of the /api/ endpoints
+func Middleware(opts Options, log *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := delay(r.Context(), opts.Latency); err != nil {
+				return
+			}
+
+			if chance(opts.ErrorRate) {
+				log.Warn("Chaos: injecting HTTP error", "path", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"success": false,
+					"error":   "chaos: injected failure",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package chaos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name   string
+		opts   Options
+		path   string
+		status int
+	}{
+		{"API error injected", Options{ErrorRate: 1}, "/api/v1/rates/latest", http.StatusServiceUnavailable},
+		{"API without faults", Options{}, "/api/v1/rates/latest", http.StatusOK},
+		{"health spared", Options{ErrorRate: 1}, "/health", http.StatusOK},
+		{"dashboard spared", Options{ErrorRate: 1}, "/dashboard", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			Middleware(tt.opts, logger.NewLogger("error"))(ok).ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+			if recorder.Code != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, recorder.Code)
+			}
+		})
+	}
+}
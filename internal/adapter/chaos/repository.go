@@ -0,0 +1,128 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+)
+
+// Repository wraps a RateRepository and injects latency, errors and malformed rates
+type Repository struct {
+	next ports.RateRepository
+	opts Options
+	log  *logger.Logger
+}
+
+func NewRepository(next ports.RateRepository, opts Options, log *logger.Logger) *Repository {
+	return &Repository{
+		next: next,
+		opts: opts,
+		log:  log,
+	}
+}
+
+func (r *Repository) Name() string {
+	return r.next.Name()
+}
+
+func (r *Repository) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	if err := r.inject(ctx, "FetchLatestRate"); err != nil {
+		return nil, err
+	}
+
+	rate, err := r.next.FetchLatestRate(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+	return r.corrupt(rate), nil
+}
+
+func (r *Repository) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	if err := r.inject(ctx, "FetchHistoricalRate"); err != nil {
+		return nil, err
+	}
+
+	rate, err := r.next.FetchHistoricalRate(ctx, pair, date)
+	if err != nil {
+		return nil, err
+	}
+	return r.corrupt(rate), nil
+}
+
+func (r *Repository) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	if err := r.inject(ctx, "FetchHistoricalRates"); err != nil {
+		return nil, err
+	}
+
+	rates, err := r.next.FetchHistoricalRates(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	for date, rate := range rates.Rates {
+		rates.Rates[date] = *r.corrupt(&rate)
+	}
+	return rates, nil
+}
+
+func (r *Repository) RefreshRates(ctx context.Context) error {
+	if err := r.inject(ctx, "RefreshRates"); err != nil {
+		return err
+	}
+	return r.next.RefreshRates(ctx)
+}
+
+// RefreshPairs passes a partial refresh on when the wrapped repository
+// supports it, and refreshes every pair otherwise
+func (r *Repository) RefreshPairs(ctx context.Context, pairs []model.CurrencyPair) error {
+	if err := r.inject(ctx, "RefreshPairs"); err != nil {
+		return err
+	}
+	if refresher, ok := r.next.(ports.PairRefresher); ok {
+		return refresher.RefreshPairs(ctx, pairs)
+	}
+	return r.next.RefreshRates(ctx)
+}
+
+func (r *Repository) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return r.next.LatestRates(ctx)
+}
+
+func (r *Repository) inject(ctx context.Context, operation string) error {
+	if err := delay(ctx, r.opts.Latency); err != nil {
+		return err
+	}
+
+	if chance(r.opts.ErrorRate) {
+		r.log.Warn("Chaos: injecting repository error", "operation", operation)
+		return fmt.Errorf("%w in %s", ErrInjected, operation)
+	}
+
+	return nil
+}
+
+// corrupt returns a copy of rate with an implausible value, at the configured rate
+func (r *Repository) corrupt(rate *model.ExchangeRate) *model.ExchangeRate {
+	if !chance(r.opts.MalformedRate) {
+		return rate
+	}
+
+	corrupted := *rate
+	switch rand.IntN(3) {
+	case 0:
+		corrupted.Rate = 0
+	case 1:
+		corrupted.Rate = -rate.Rate
+	default:
+		corrupted.Rate = rate.Rate * 1e6
+	}
+
+	pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+	r.log.Warn("Chaos: injecting malformed rate", "pair", pair.String(), "rate", corrupted.Rate)
+	return &corrupted
+}
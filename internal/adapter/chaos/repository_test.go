@@ -0,0 +1,108 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+var testPair = model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+
+func newTestUpstream() *mocks.RateRepositoryMock {
+	rate := func(date time.Time) *model.ExchangeRate {
+		return &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: date}
+	}
+	return &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "upstream" },
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return rate(time.Now()), nil
+		},
+		FetchHistoricalRateFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+			return rate(date), nil
+		},
+		FetchHistoricalRatesFunc: func(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+			return &model.HistoricalRates{Rates: map[string]model.ExchangeRate{"2025-01-02": *rate(request.StartDate)}}, nil
+		},
+		RefreshRatesFunc: func(ctx context.Context) error { return nil },
+	}
+}
+
+func TestRepositoryInjectsErrors(t *testing.T) {
+	ctx := context.Background()
+	upstream := newTestUpstream()
+	repository := NewRepository(upstream, Options{ErrorRate: 1}, logger.NewLogger("error"))
+
+	if _, err := repository.FetchLatestRate(ctx, testPair); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected an injected error from FetchLatestRate, got %v", err)
+	}
+	if _, err := repository.FetchHistoricalRate(ctx, testPair, time.Now()); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected an injected error from FetchHistoricalRate, got %v", err)
+	}
+	if _, err := repository.FetchHistoricalRates(ctx, model.HistoricalRateRequest{}); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected an injected error from FetchHistoricalRates, got %v", err)
+	}
+	if err := repository.RefreshRates(ctx); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected an injected error from RefreshRates, got %v", err)
+	}
+	if err := repository.RefreshPairs(ctx, []model.CurrencyPair{testPair}); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected an injected error from RefreshPairs, got %v", err)
+	}
+	if calls := len(upstream.FetchLatestRateCalls()) + len(upstream.RefreshRatesCalls()); calls != 0 {
+		t.Errorf("Expected injected errors to spare the upstream, got %d calls", calls)
+	}
+}
+
+func TestRepositoryPassesThroughWithoutFaults(t *testing.T) {
+	ctx := context.Background()
+	upstream := newTestUpstream()
+	repository := NewRepository(upstream, Options{}, logger.NewLogger("error"))
+
+	if repository.Name() != "upstream" {
+		t.Errorf("Expected the upstream name, got %s", repository.Name())
+	}
+	if rate, err := repository.FetchLatestRate(ctx, testPair); err != nil || rate.Rate != 83 {
+		t.Errorf("Expected the upstream rate, got %+v, %v", rate, err)
+	}
+	// A repository without partial refreshes refreshes every pair
+	if err := repository.RefreshPairs(ctx, []model.CurrencyPair{testPair}); err != nil || len(upstream.RefreshRatesCalls()) != 1 {
+		t.Errorf("Expected RefreshPairs to fall back to RefreshRates, got %v", err)
+	}
+}
+
+func TestRepositoryCorruptsRates(t *testing.T) {
+	ctx := context.Background()
+	repository := NewRepository(newTestUpstream(), Options{MalformedRate: 1}, logger.NewLogger("error"))
+
+	for range 10 {
+		rate, err := repository.FetchLatestRate(ctx, testPair)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rate.Rate > 0 && rate.Rate < 1e6 {
+			t.Fatalf("Expected an implausible rate, got %v", rate.Rate)
+		}
+	}
+
+	rates, err := repository.FetchHistoricalRates(ctx, model.HistoricalRateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate := rates.Rates["2025-01-02"].Rate; rate == 83 {
+		t.Errorf("Expected the historical rates to be corrupted, got %v", rate)
+	}
+}
+
+func TestRepositoryLatencyStopsWithContext(t *testing.T) {
+	repository := NewRepository(newTestUpstream(), Options{Latency: time.Hour}, logger.NewLogger("error"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := repository.FetchLatestRate(ctx, testPair); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the injected latency to end with the context, got %v", err)
+	}
+}
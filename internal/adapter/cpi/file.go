@@ -0,0 +1,84 @@
+package cpi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// periodLayouts are the accepted keys of a CPI file: annual, monthly or daily
+var periodLayouts = []string{"2006", "2006-01", "2006-01-02"}
+
+// FileSource serves consumer price indexes from a JSON file of index values
+// per currency and period, e.g. {"USD": {"2015-06": 238.638, "2024": 313.689}}.
+// An observation covers its period until the next one.
+type FileSource struct {
+	series map[model.Currency][]model.CPIObservation
+}
+
+func NewFileSource(path string, log *logger.Logger) (*FileSource, error) {
+	var raw map[model.Currency]map[string]float64
+	if err := utils.ReadJSONFile(path, &raw); err != nil {
+		return nil, err
+	}
+
+	s := &FileSource{series: make(map[model.Currency][]model.CPIObservation, len(raw))}
+	for currency, values := range raw {
+		series := make([]model.CPIObservation, 0, len(values))
+		for period, value := range values {
+			date, err := parsePeriod(period)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", path, currency, err)
+			}
+			if value <= 0 {
+				return nil, fmt.Errorf("%s: %s %s: index must be positive", path, currency, period)
+			}
+			series = append(series, model.CPIObservation{Currency: currency, Date: date, Value: value})
+		}
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].Date.Before(series[j].Date)
+		})
+		s.series[currency] = series
+	}
+
+	log.Info("Loaded CPI data", "path", path, "currencies", len(s.series))
+	return s, nil
+}
+
+func parsePeriod(period string) (time.Time, error) {
+	for _, layout := range periodLayouts {
+		if len(period) == len(layout) {
+			if date, err := time.Parse(layout, period); err == nil {
+				return date, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid period %q, use YYYY, YYYY-MM or YYYY-MM-DD", period)
+}
+
+func (s *FileSource) At(ctx context.Context, currency model.Currency, date time.Time) (*model.CPIObservation, error) {
+	series := s.series[currency]
+	i := sort.Search(len(series), func(i int) bool {
+		return series[i].Date.After(date)
+	})
+	if i == 0 {
+		return nil, fmt.Errorf("%w for %s on %s", ports.ErrCPINotFound, currency, date.Format("2006-01-02"))
+	}
+	observation := series[i-1]
+	return &observation, nil
+}
+
+func (s *FileSource) Latest(ctx context.Context, currency model.Currency) (*model.CPIObservation, error) {
+	series := s.series[currency]
+	if len(series) == 0 {
+		return nil, fmt.Errorf("%w for %s", ports.ErrCPINotFound, currency)
+	}
+	observation := series[len(series)-1]
+	return &observation, nil
+}
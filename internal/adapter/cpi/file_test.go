@@ -0,0 +1,69 @@
+package cpi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestFileSource(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "cpi.json")
+	data := `{"USD": {"2015": 237.0, "2015-06": 238.6, "2024-09-01": 315.3}, "INR": {"2020-01": 150.2}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileSource(path, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		name     string
+		date     time.Time
+		expected float64
+		notFound bool
+	}{
+		{name: "Annual Value", date: date(2015, 3, 10), expected: 237.0},
+		{name: "Monthly Value", date: date(2015, 6, 1), expected: 238.6},
+		{name: "Carried Forward", date: date(2020, 1, 1), expected: 238.6},
+		{name: "Daily Value", date: date(2025, 1, 1), expected: 315.3},
+		{name: "Before First Observation", date: date(2014, 12, 31), notFound: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			observation, err := source.At(context.Background(), "USD", tc.date)
+
+			if tc.notFound {
+				if !errors.Is(err, ports.ErrCPINotFound) {
+					t.Fatalf("Expected ErrCPINotFound, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if observation.Value != tc.expected {
+				t.Errorf("Expected CPI %v, got %v", tc.expected, observation.Value)
+			}
+		})
+	}
+
+	if latest, err := source.Latest(context.Background(), "INR"); err != nil || latest.Value != 150.2 {
+		t.Errorf("Expected the latest INR CPI to be 150.2, got %v, %v", latest, err)
+	}
+	if _, err := source.Latest(context.Background(), "JPY"); !errors.Is(err, ports.ErrCPINotFound) {
+		t.Errorf("Expected ErrCPINotFound for a currency without data, got %v", err)
+	}
+}
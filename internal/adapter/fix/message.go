@@ -0,0 +1,223 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	soh         = '\x01'
+	beginString = "FIX.4.4"
+
+	// maxBodyLength guards against a peer announcing an absurd BodyLength
+	maxBodyLength = 64 << 10
+)
+
+// Tags used by the market data session
+const (
+	tagBeginSeqNo              = 7
+	tagBeginString             = 8
+	tagBodyLength              = 9
+	tagCheckSum                = 10
+	tagMsgSeqNum               = 34
+	tagMsgType                 = 35
+	tagNewSeqNo                = 36
+	tagPossDupFlag             = 43
+	tagRefSeqNum               = 45
+	tagSenderCompID            = 49
+	tagSendingTime             = 52
+	tagSymbol                  = 55
+	tagTargetCompID            = 56
+	tagText                    = 58
+	tagEncryptMethod           = 98
+	tagHeartBtInt              = 108
+	tagTestReqID               = 112
+	tagGapFillFlag             = 123
+	tagResetSeqNumFlag         = 141
+	tagMDReqID                 = 262
+	tagSubscriptionRequestType = 263
+	tagMDUpdateType            = 265
+	tagNoMDEntries             = 268
+	tagMDEntryType             = 269
+	tagMDEntryPx               = 270
+	tagMDEntryDate             = 272
+	tagMDEntryTime             = 273
+	tagMDUpdateAction          = 279
+	tagMDReqRejReason          = 281
+	tagRefMsgType              = 372
+	tagSessionRejectReason     = 373
+	tagBusinessRejectReason    = 380
+)
+
+const (
+	msgHeartbeat          = "0"
+	msgTestRequest        = "1"
+	msgResendRequest      = "2"
+	msgReject             = "3"
+	msgSequenceReset      = "4"
+	msgLogout             = "5"
+	msgLogon              = "A"
+	msgMarketDataRequest  = "V"
+	msgMarketDataSnapshot = "W"
+	msgMarketDataIncr     = "X"
+	msgMarketDataReject   = "Y"
+	msgBusinessReject     = "j"
+)
+
+var errGarbled = errors.New("garbled FIX message")
+
+type field struct {
+	tag   int
+	value string
+}
+
+// message holds the body fields of a FIX message in wire order. BeginString,
+// BodyLength and CheckSum are added on encode and checked on decode.
+type message struct {
+	fields []field
+	// seqNumOverride is sent as MsgSeqNum instead of the next outgoing number
+	seqNumOverride string
+}
+
+func newMessage(msgType string) *message {
+	return &message{fields: []field{{tagMsgType, msgType}}}
+}
+
+func (m *message) add(tag int, value string) *message {
+	m.fields = append(m.fields, field{tag, value})
+	return m
+}
+
+func (m *message) withSeqNum(seq string) *message {
+	m.seqNumOverride = seq
+	return m
+}
+
+// get returns the first value of tag, or "" when it is absent
+func (m *message) get(tag int) string {
+	for _, f := range m.fields {
+		if f.tag == tag {
+			return f.value
+		}
+	}
+	return ""
+}
+
+// all returns every value of tag, as found in repeating groups
+func (m *message) all(tag int) []string {
+	values := make([]string, 0)
+	for _, f := range m.fields {
+		if f.tag == tag {
+			values = append(values, f.value)
+		}
+	}
+	return values
+}
+
+func (m *message) msgType() string {
+	return m.get(tagMsgType)
+}
+
+func (m *message) encode() []byte {
+	var body bytes.Buffer
+	for _, f := range m.fields {
+		body.WriteString(strconv.Itoa(f.tag))
+		body.WriteByte('=')
+		body.WriteString(f.value)
+		body.WriteByte(soh)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "8=%s%c9=%d%c", beginString, soh, body.Len(), soh)
+	out.Write(body.Bytes())
+	fmt.Fprintf(&out, "10=%03d%c", checksum(out.Bytes()), soh)
+	return out.Bytes()
+}
+
+func checksum(b []byte) int {
+	sum := 0
+	for _, c := range b {
+		sum += int(c)
+	}
+	return sum % 256
+}
+
+// readMessage reads one message, verifying the header, length and checksum
+func readMessage(r *bufio.Reader) (*message, error) {
+	var raw bytes.Buffer
+
+	begin, err := readField(r, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if begin.tag != tagBeginString || begin.value != beginString {
+		return nil, fmt.Errorf("%w: expected BeginString %s", errGarbled, beginString)
+	}
+
+	length, err := readField(r, &raw)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(length.value)
+	if length.tag != tagBodyLength || err != nil || n <= 0 || n > maxBodyLength {
+		return nil, fmt.Errorf("%w: bad BodyLength", errGarbled)
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	raw.Write(body)
+
+	trailer, err := readField(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	if sum, err := strconv.Atoi(trailer.value); trailer.tag != tagCheckSum || err != nil || sum != checksum(raw.Bytes()) {
+		return nil, fmt.Errorf("%w: bad CheckSum", errGarbled)
+	}
+
+	m := &message{}
+	for _, item := range strings.Split(strings.TrimSuffix(string(body), string(soh)), string(soh)) {
+		f, err := parseField(item)
+		if err != nil {
+			return nil, err
+		}
+		m.fields = append(m.fields, f)
+	}
+	if len(m.fields) == 0 || m.fields[0].tag != tagMsgType {
+		return nil, fmt.Errorf("%w: MsgType must follow BodyLength", errGarbled)
+	}
+
+	return m, nil
+}
+
+// readField reads a tag=value field up to SOH, copying the raw bytes to raw if
+// set. A field longer than the reader's buffer is treated as garbage.
+func readField(r *bufio.Reader, raw *bytes.Buffer) (field, error) {
+	item, err := r.ReadSlice(soh)
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return field{}, fmt.Errorf("%w: field too long", errGarbled)
+	}
+	if err != nil {
+		return field{}, err
+	}
+	if raw != nil {
+		raw.Write(item)
+	}
+	return parseField(string(item[:len(item)-1]))
+}
+
+func parseField(item string) (field, error) {
+	tagStr, value, found := strings.Cut(item, "=")
+	tag, err := strconv.Atoi(tagStr)
+	if !found || err != nil || tag <= 0 {
+		return field{}, fmt.Errorf("%w: bad field %q", errGarbled, item)
+	}
+	return field{tag, value}, nil
+}
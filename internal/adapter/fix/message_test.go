@@ -0,0 +1,64 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadMessage(t *testing.T) {
+
+	encoded := newMessage(msgMarketDataRequest).
+		add(tagMsgSeqNum, "2").
+		add(tagMDReqID, "req1").
+		add(tagSymbol, "USD/INR").
+		add(tagSymbol, "EUR/USD").
+		encode()
+
+	testCases := []struct {
+		name        string
+		input       string
+		expectError bool
+	}{
+		{name: "Valid", input: string(encoded)},
+		{name: "Bad CheckSum", input: strings.Replace(string(encoded), "10=", "10=9", 1), expectError: true},
+		{name: "Wrong Version", input: strings.Replace(string(encoded), "FIX.4.4", "FIX.4.2", 1), expectError: true},
+		{name: "Short Body", input: strings.Replace(string(encoded), "9=", "9=1", 1), expectError: true},
+		{name: "Field Too Long", input: "8=" + strings.Repeat("X", 5000) + "\x01", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			m, err := readMessage(bufio.NewReader(strings.NewReader(tc.input)))
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("Expected error, got message %+v", m)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if m.msgType() != msgMarketDataRequest || m.get(tagMDReqID) != "req1" {
+				t.Errorf("Unexpected message: %+v", m.fields)
+			}
+			if symbols := m.all(tagSymbol); len(symbols) != 2 || symbols[1] != "EUR/USD" {
+				t.Errorf("Expected both symbols, got %v", symbols)
+			}
+		})
+	}
+}
+
+func TestEncodeChecksum(t *testing.T) {
+	encoded := newMessage(msgHeartbeat).encode()
+
+	if !bytes.HasPrefix(encoded, []byte("8=FIX.4.4\x019=5\x0135=0\x01")) {
+		t.Fatalf("Unexpected encoding: %q", encoded)
+	}
+	if _, err := readMessage(bufio.NewReader(bytes.NewReader(encoded))); err != nil {
+		t.Errorf("Encoded message does not read back: %v", err)
+	}
+}
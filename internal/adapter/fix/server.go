@@ -0,0 +1,145 @@
+// Package fix implements a read-only FIX 4.4 market data acceptor. Clients
+// subscribe to pairs with MarketDataRequest and receive a snapshot followed by
+// updates whenever the service obtains fresh rates.
+package fix
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+// SlowConsumerDisconnect is the FIXConfig.SlowConsumerPolicy that closes
+// sessions instead of conflating their updates
+const SlowConsumerDisconnect = "disconnect"
+
+// QuoteSource provides the snapshot sent when a pair is first requested
+type QuoteSource interface {
+	GetLatestRate(ctx context.Context, from, to model.Currency) (*model.ExchangeRate, error)
+}
+
+// Server accepts FIX sessions and implements ports.RatePublisher to stream
+// updates to them
+type Server struct {
+	cfg      config.FIXConfig
+	quotes   QuoteSource
+	metrics  *metrics.Metrics
+	log      *logger.Logger
+	mutex    sync.Mutex
+	sessions map[*session]struct{}
+	listener net.Listener
+}
+
+func NewServer(quotes QuoteSource, cfg config.FIXConfig, metrics *metrics.Metrics, log *logger.Logger) *Server {
+	return &Server{
+		cfg:      cfg,
+		quotes:   quotes,
+		metrics:  metrics,
+		log:      log,
+		sessions: make(map[*session]struct{}),
+	}
+}
+
+func (s *Server) allowsCompID(compID string) bool {
+	if compID == "" {
+		return false
+	}
+	return len(s.cfg.AllowedCompIDs) == 0 || contains(s.cfg.AllowedCompIDs, compID)
+}
+
+// Serve accepts sessions on l until Close is called
+func (s *Server) Serve(l net.Listener) error {
+	s.mutex.Lock()
+	s.listener = l
+	s.mutex.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	sess := newSession(s, conn)
+	s.mutex.Lock()
+	s.sessions[sess] = struct{}{}
+	s.mutex.Unlock()
+	s.metrics.FIXSessions.Inc()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.sessions, sess)
+		s.mutex.Unlock()
+		s.metrics.FIXSessions.Dec()
+	}()
+
+	err := sess.run()
+	switch {
+	case err == nil, errors.Is(err, errLoggedOut), errors.Is(err, io.EOF), errors.Is(err, net.ErrClosed):
+		s.log.Info("FIX session ended", "remote_addr", conn.RemoteAddr().String(), "comp_id", sess.targetCompID)
+	default:
+		s.log.Warn("FIX session failed", "remote_addr", conn.RemoteAddr().String(), "comp_id", sess.targetCompID, "error", err)
+	}
+}
+
+// Publish queues rates for every session subscribed to their pairs. It never
+// blocks on a slow session: an update still queued when the next one for its
+// pair arrives is dropped. Under SlowConsumerDisconnect, so is a session whose
+// dropped update had waited longer than its heartbeat interval.
+func (s *Server) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for sess := range s.sessions {
+		dropped, slow := 0, false
+		for _, rate := range rates {
+			replaced, stalled := sess.offer(rate)
+			if replaced {
+				dropped++
+			}
+			slow = slow || stalled
+		}
+		if dropped == 0 {
+			continue
+		}
+
+		s.metrics.FIXDroppedUpdates.Add(float64(dropped))
+		if slow && s.cfg.SlowConsumerPolicy == SlowConsumerDisconnect && sess.disconnect() {
+			s.metrics.FIXSlowConsumerDisconnects.Inc()
+			s.log.Warn("Disconnecting slow FIX session", "comp_id", sess.targetCompID, "dropped", dropped)
+		}
+	}
+	return nil
+}
+
+// Close stops accepting sessions and logs out the open ones
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for sess := range s.sessions {
+		sess.sendLogout("server shutting down")
+		sess.conn.SetReadDeadline(time.Now())
+	}
+	return err
+}
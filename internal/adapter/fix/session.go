@@ -0,0 +1,544 @@
+package fix
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+const (
+	logonTimeout    = 10 * time.Second
+	minHeartBtInt   = 1
+	maxHeartBtInt   = 300
+	sendingTimeForm = "20060102-15:04:05.000"
+	entryDateForm   = "20060102"
+	entryTimeForm   = "15:04:05.000"
+
+	// entryMidPrice is MDEntryType H; the service has a single rate per pair, not a book
+	entryMidPrice = "H"
+)
+
+var errLoggedOut = errors.New("session logged out")
+
+// queuedRate is an update waiting to be sent, and since when it waits
+type queuedRate struct {
+	rate   model.ExchangeRate
+	queued time.Time
+}
+
+type subscription struct {
+	reqID       string
+	incremental bool
+}
+
+// session is one FIX connection. Sequence numbers start at 1 on every logon
+// since nothing is persisted, so clients must log on with ResetSeqNumFlag=Y.
+type session struct {
+	server       *Server
+	conn         net.Conn
+	reader       *bufio.Reader
+	targetCompID string
+	heartbeat    time.Duration
+	inSeq        int
+
+	writeMutex sync.Mutex
+	outSeq     int
+	lastSent   time.Time
+
+	mutex         sync.Mutex
+	subscriptions map[model.CurrencyPair][]subscription
+	pending       map[model.CurrencyPair]queuedRate
+	lastReceived  time.Time
+	testRequested bool
+	disconnecting bool
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+func newSession(server *Server, conn net.Conn) *session {
+	return &session{
+		server:        server,
+		conn:          conn,
+		reader:        bufio.NewReader(conn),
+		outSeq:        1,
+		subscriptions: make(map[model.CurrencyPair][]subscription),
+		pending:       make(map[model.CurrencyPair]queuedRate),
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+}
+
+// run handles the session until the peer logs out, the connection fails or
+// the server closes it
+func (s *session) run() error {
+	defer close(s.done)
+
+	if err := s.logon(); err != nil {
+		return err
+	}
+
+	go s.writeLoop()
+
+	for {
+		s.conn.SetReadDeadline(time.Now().Add(3 * s.heartbeat))
+		m, err := readMessage(s.reader)
+		if err != nil {
+			return err
+		}
+
+		s.mutex.Lock()
+		s.lastReceived = time.Now()
+		s.testRequested = false
+		s.mutex.Unlock()
+
+		if err := s.checkSeqNum(m); err != nil {
+			return err
+		}
+
+		if err := s.dispatch(m); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *session) logon() error {
+	s.conn.SetReadDeadline(time.Now().Add(logonTimeout))
+	m, err := readMessage(s.reader)
+	if err != nil {
+		return err
+	}
+	if m.msgType() != msgLogon {
+		return fmt.Errorf("expected Logon, got MsgType %s", m.msgType())
+	}
+
+	s.targetCompID = m.get(tagSenderCompID)
+	if !s.server.allowsCompID(s.targetCompID) {
+		s.sendLogout("unknown SenderCompID")
+		return fmt.Errorf("SenderCompID %q not allowed", s.targetCompID)
+	}
+	if m.get(tagTargetCompID) != s.server.cfg.SenderCompID {
+		s.sendLogout("TargetCompID must be " + s.server.cfg.SenderCompID)
+		return fmt.Errorf("logon for TargetCompID %q", m.get(tagTargetCompID))
+	}
+
+	heartBtInt, err := strconv.Atoi(m.get(tagHeartBtInt))
+	if err != nil || heartBtInt < minHeartBtInt || heartBtInt > maxHeartBtInt {
+		s.sendLogout(fmt.Sprintf("HeartBtInt must be between %d and %d", minHeartBtInt, maxHeartBtInt))
+		return fmt.Errorf("bad HeartBtInt %q", m.get(tagHeartBtInt))
+	}
+	s.heartbeat = time.Duration(heartBtInt) * time.Second
+
+	seq, _ := strconv.Atoi(m.get(tagMsgSeqNum))
+	if seq != 1 && m.get(tagResetSeqNumFlag) != "Y" {
+		s.sendLogout("ResetSeqNumFlag=Y required, sequence numbers are not persisted")
+		return fmt.Errorf("logon with MsgSeqNum %d without reset", seq)
+	}
+	s.inSeq = seq + 1
+	s.lastReceived = time.Now()
+
+	reply := newMessage(msgLogon).
+		add(tagEncryptMethod, "0").
+		add(tagHeartBtInt, strconv.Itoa(heartBtInt))
+	if m.get(tagResetSeqNumFlag) == "Y" {
+		reply.add(tagResetSeqNumFlag, "Y")
+	}
+	return s.send(reply)
+}
+
+// checkSeqNum rejects messages replayed below the expected sequence number.
+// Gaps are accepted: the session is read-only, so a lost client message can
+// only be a heartbeat or a subscription the client will retry.
+func (s *session) checkSeqNum(m *message) error {
+	seq, err := strconv.Atoi(m.get(tagMsgSeqNum))
+	if err != nil {
+		s.sendLogout("MsgSeqNum missing")
+		return fmt.Errorf("message without MsgSeqNum")
+	}
+	if m.msgType() == msgSequenceReset {
+		return nil
+	}
+	if seq < s.inSeq {
+		if m.get(tagPossDupFlag) == "Y" {
+			return nil
+		}
+		s.sendLogout(fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", s.inSeq, seq))
+		return fmt.Errorf("MsgSeqNum %d below expected %d", seq, s.inSeq)
+	}
+	s.inSeq = seq + 1
+	return nil
+}
+
+func (s *session) dispatch(m *message) error {
+	switch m.msgType() {
+	case msgHeartbeat:
+		return nil
+	case msgTestRequest:
+		return s.send(newMessage(msgHeartbeat).add(tagTestReqID, m.get(tagTestReqID)))
+	case msgResendRequest:
+		// Nothing is stored for replay; market data is superseded anyway, so skip the range
+		begin := m.get(tagBeginSeqNo)
+		return s.send(newMessage(msgSequenceReset).
+			add(tagGapFillFlag, "Y").
+			add(tagNewSeqNo, strconv.Itoa(s.nextOutSeq())).
+			withSeqNum(begin))
+	case msgSequenceReset:
+		if seq, err := strconv.Atoi(m.get(tagNewSeqNo)); err == nil && seq >= s.inSeq {
+			s.inSeq = seq
+		}
+		return nil
+	case msgLogout:
+		s.sendLogout("")
+		return errLoggedOut
+	case msgMarketDataRequest:
+		return s.handleMarketDataRequest(m)
+	case msgLogon:
+		return s.send(newMessage(msgReject).
+			add(tagRefSeqNum, m.get(tagMsgSeqNum)).
+			add(tagRefMsgType, msgLogon).
+			add(tagText, "already logged on"))
+	default:
+		return s.send(newMessage(msgBusinessReject).
+			add(tagRefSeqNum, m.get(tagMsgSeqNum)).
+			add(tagRefMsgType, m.msgType()).
+			add(tagBusinessRejectReason, "3").
+			add(tagText, "read-only market data session"))
+	}
+}
+
+func (s *session) handleMarketDataRequest(m *message) error {
+	reqID := m.get(tagMDReqID)
+	if reqID == "" {
+		return s.send(newMessage(msgReject).
+			add(tagRefSeqNum, m.get(tagMsgSeqNum)).
+			add(tagRefMsgType, msgMarketDataRequest).
+			add(tagSessionRejectReason, "1").
+			add(tagText, "MDReqID is required"))
+	}
+
+	switch m.get(tagSubscriptionRequestType) {
+	case "0", "1":
+	case "2":
+		s.unsubscribe(reqID)
+		return nil
+	default:
+		return s.rejectMarketData(reqID, "", "unsupported SubscriptionRequestType")
+	}
+
+	if types := m.all(tagMDEntryType); len(types) > 0 && !contains(types, entryMidPrice) {
+		return s.rejectMarketData(reqID, "8", "only MDEntryType H (mid price) is available")
+	}
+
+	symbols := m.all(tagSymbol)
+	if len(symbols) == 0 {
+		return s.rejectMarketData(reqID, "0", "no symbols requested")
+	}
+
+	if s.exceedsSubscriptions(m.get(tagSubscriptionRequestType), symbols) {
+		return s.rejectMarketData(reqID, "3", fmt.Sprintf("at most %d pairs may be subscribed per session", s.server.cfg.MaxSubscriptions))
+	}
+
+	// Every symbol is checked before anything is sent, so a request either fully succeeds or is rejected
+	ctx, cancel := context.WithTimeout(context.Background(), logonTimeout)
+	defer cancel()
+	rates := make([]*model.ExchangeRate, 0, len(symbols))
+	for _, symbol := range symbols {
+		pair, err := parseSymbol(symbol)
+		if err != nil {
+			return s.rejectMarketData(reqID, "0", "unknown symbol "+symbol)
+		}
+		rate, err := s.server.quotes.GetLatestRate(ctx, pair.BaseCurrency, pair.TargetCurrency)
+		if err != nil {
+			return s.rejectMarketData(reqID, "0", "no rate for "+symbol)
+		}
+		rates = append(rates, rate)
+	}
+
+	for _, rate := range rates {
+		if err := s.send(snapshot(reqID, *rate)); err != nil {
+			return err
+		}
+	}
+
+	if m.get(tagSubscriptionRequestType) == "1" {
+		s.mutex.Lock()
+		for _, rate := range rates {
+			pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+			s.subscriptions[pair] = append(s.subscriptions[pair], subscription{
+				reqID:       reqID,
+				incremental: m.get(tagMDUpdateType) == "1",
+			})
+		}
+		s.mutex.Unlock()
+	}
+
+	return nil
+}
+
+// exceedsSubscriptions reports whether subscribing to symbols would take the
+// session past the subscription limit. Snapshots alone never count.
+func (s *session) exceedsSubscriptions(requestType string, symbols []string) bool {
+	limit := s.server.cfg.MaxSubscriptions
+	if requestType != "1" || limit == 0 {
+		return false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	added := make(map[model.CurrencyPair]bool)
+	for _, symbol := range symbols {
+		pair, err := parseSymbol(symbol)
+		if err != nil {
+			continue
+		}
+		if _, subscribed := s.subscriptions[pair]; !subscribed {
+			added[pair] = true
+		}
+	}
+	return len(s.subscriptions)+len(added) > limit
+}
+
+func (s *session) unsubscribe(reqID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for pair, subs := range s.subscriptions {
+		kept := subs[:0]
+		for _, sub := range subs {
+			if sub.reqID != reqID {
+				kept = append(kept, sub)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.subscriptions, pair)
+		} else {
+			s.subscriptions[pair] = kept
+		}
+	}
+}
+
+func (s *session) rejectMarketData(reqID, reason, text string) error {
+	reject := newMessage(msgMarketDataReject).add(tagMDReqID, reqID)
+	if reason != "" {
+		reject.add(tagMDReqRejReason, reason)
+	}
+	return s.send(reject.add(tagText, text))
+}
+
+// offer queues rate for subscribers of its pair. Only the newest rate per pair
+// is kept, so a slow client receives fewer updates instead of stalling
+// publishers. offer reports whether it replaced a rate not yet sent, and
+// whether that rate had waited longer than a heartbeat interval.
+func (s *session) offer(rate model.ExchangeRate) (dropped, stalled bool) {
+	pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+	now := time.Now()
+
+	s.mutex.Lock()
+	_, subscribed := s.subscriptions[pair]
+	if subscribed {
+		var previous queuedRate
+		previous, dropped = s.pending[pair]
+		stalled = dropped && now.Sub(previous.queued) > s.heartbeat
+		s.pending[pair] = queuedRate{rate: rate, queued: now}
+	}
+	s.mutex.Unlock()
+
+	if subscribed {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return dropped, stalled
+}
+
+// disconnect closes the connection of a session that fell behind, and reports
+// false when it was already closing. No Logout is sent, since the session's
+// writer is the one that is stuck.
+func (s *session) disconnect() bool {
+	s.mutex.Lock()
+	closing := s.disconnecting
+	s.disconnecting = true
+	s.mutex.Unlock()
+
+	if closing {
+		return false
+	}
+	s.conn.Close()
+	return true
+}
+
+// writeLoop sends queued updates and keeps the session alive with heartbeats
+func (s *session) writeLoop() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+			if err := s.flush(); err != nil {
+				s.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if err := s.keepAlive(); err != nil {
+				s.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func (s *session) flush() error {
+	s.mutex.Lock()
+	updates := make([]*message, 0, len(s.pending))
+	for pair, queued := range s.pending {
+		rate := queued.rate
+		for _, sub := range s.subscriptions[pair] {
+			if sub.incremental {
+				updates = append(updates, incrementalRefresh(sub.reqID, rate))
+			} else {
+				updates = append(updates, snapshot(sub.reqID, rate))
+			}
+		}
+		delete(s.pending, pair)
+	}
+	s.mutex.Unlock()
+
+	for _, m := range updates {
+		if err := s.send(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepAlive sends a heartbeat when the session has been quiet and tests a
+// silent peer before giving up on it
+func (s *session) keepAlive() error {
+	s.writeMutex.Lock()
+	idle := time.Since(s.lastSent)
+	s.writeMutex.Unlock()
+	if idle >= s.heartbeat {
+		if err := s.send(newMessage(msgHeartbeat)); err != nil {
+			return err
+		}
+	}
+
+	s.mutex.Lock()
+	silent := time.Since(s.lastReceived)
+	requested := s.testRequested
+	if silent > s.heartbeat+s.heartbeat/5 && !requested {
+		s.testRequested = true
+	}
+	s.mutex.Unlock()
+
+	switch {
+	case silent > 2*s.heartbeat+s.heartbeat/5:
+		s.sendLogout("heartbeat timeout")
+		return errors.New("heartbeat timeout")
+	case silent > s.heartbeat+s.heartbeat/5 && !requested:
+		return s.send(newMessage(msgTestRequest).add(tagTestReqID, strconv.FormatInt(time.Now().Unix(), 10)))
+	}
+	return nil
+}
+
+func (s *session) sendLogout(text string) {
+	logout := newMessage(msgLogout)
+	if text != "" {
+		logout.add(tagText, text)
+	}
+	if err := s.send(logout); err != nil {
+		s.server.log.Debug("Failed to send FIX logout", "error", err)
+	}
+}
+
+func (s *session) nextOutSeq() int {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+	return s.outSeq
+}
+
+// send adds the standard header and writes m. A message carrying its own
+// MsgSeqNum, such as a gap fill, does not advance the outgoing sequence.
+func (s *session) send(m *message) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	seq := m.seqNumOverride
+	if seq == "" {
+		seq = strconv.Itoa(s.outSeq)
+		s.outSeq++
+	}
+
+	header := []field{
+		m.fields[0],
+		{tagSenderCompID, s.server.cfg.SenderCompID},
+		{tagTargetCompID, s.targetCompID},
+		{tagMsgSeqNum, seq},
+	}
+	if m.seqNumOverride != "" {
+		header = append(header, field{tagPossDupFlag, "Y"})
+	}
+	header = append(header, field{tagSendingTime, time.Now().UTC().Format(sendingTimeForm)})
+
+	out := &message{fields: append(header, m.fields[1:]...)}
+	s.conn.SetWriteDeadline(time.Now().Add(logonTimeout))
+	if _, err := s.conn.Write(out.encode()); err != nil {
+		return err
+	}
+	s.lastSent = time.Now()
+	return nil
+}
+
+func snapshot(reqID string, rate model.ExchangeRate) *message {
+	return newMessage(msgMarketDataSnapshot).
+		add(tagMDReqID, reqID).
+		add(tagSymbol, symbol(rate)).
+		add(tagNoMDEntries, "1").
+		add(tagMDEntryType, entryMidPrice).
+		add(tagMDEntryPx, strconv.FormatFloat(rate.Rate, 'f', -1, 64)).
+		add(tagMDEntryDate, rate.LastUpdated.UTC().Format(entryDateForm)).
+		add(tagMDEntryTime, rate.LastUpdated.UTC().Format(entryTimeForm))
+}
+
+func incrementalRefresh(reqID string, rate model.ExchangeRate) *message {
+	return newMessage(msgMarketDataIncr).
+		add(tagMDReqID, reqID).
+		add(tagNoMDEntries, "1").
+		add(tagMDUpdateAction, "1").
+		add(tagMDEntryType, entryMidPrice).
+		add(tagSymbol, symbol(rate)).
+		add(tagMDEntryPx, strconv.FormatFloat(rate.Rate, 'f', -1, 64)).
+		add(tagMDEntryDate, rate.LastUpdated.UTC().Format(entryDateForm)).
+		add(tagMDEntryTime, rate.LastUpdated.UTC().Format(entryTimeForm))
+}
+
+// symbol writes a pair in the usual FX form, USD/INR
+func symbol(rate model.ExchangeRate) string {
+	return string(rate.BaseCurrency) + "/" + string(rate.TargetCurrency)
+}
+
+func parseSymbol(s string) (model.CurrencyPair, error) {
+	return model.ParseCurrencyPair(strings.Replace(s, "/", "-", 1))
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
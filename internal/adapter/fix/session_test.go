@@ -0,0 +1,118 @@
+package fix
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestServer(cfg config.FIXConfig) (*Server, *prometheus.Registry) {
+	appMetrics := &metrics.Metrics{
+		FIXSessions:                prometheus.NewGauge(prometheus.GaugeOpts{Name: "fix_sessions"}),
+		FIXDroppedUpdates:          prometheus.NewCounter(prometheus.CounterOpts{Name: "fix_dropped_updates_total"}),
+		FIXSlowConsumerDisconnects: prometheus.NewCounter(prometheus.CounterOpts{Name: "fix_slow_consumer_disconnects_total"}),
+	}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(appMetrics.FIXDroppedUpdates, appMetrics.FIXSlowConsumerDisconnects)
+	return NewServer(nil, cfg, appMetrics, logger.NewLogger("error")), registry
+}
+
+func counterValues(t *testing.T, registry *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]float64{}
+	for _, family := range families {
+		values[family.GetName()] = family.GetMetric()[0].GetCounter().GetValue()
+	}
+	return values
+}
+
+func TestSessionSubscriptionLimit(t *testing.T) {
+	server, _ := newTestServer(config.FIXConfig{MaxSubscriptions: 2})
+	sess := newSession(server, nil)
+	sess.subscriptions[model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}] = []subscription{{reqID: "req1"}}
+
+	testCases := []struct {
+		name        string
+		requestType string
+		symbols     []string
+		expected    bool
+	}{
+		{name: "New pair within the limit", requestType: "1", symbols: []string{"USD/INR", "USD/EUR"}},
+		{name: "Subscribed pairs count once", requestType: "1", symbols: []string{"USD/INR", "USD/INR", "USD/EUR", "USD/EUR"}},
+		{name: "Two new pairs exceed the limit", requestType: "1", symbols: []string{"USD/EUR", "USD/JPY"}, expected: true},
+		{name: "Snapshots are not limited", requestType: "0", symbols: []string{"USD/EUR", "USD/JPY", "USD/GBP"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if exceeds := sess.exceedsSubscriptions(tc.requestType, tc.symbols); exceeds != tc.expected {
+				t.Errorf("Expected exceeds %t, got %t", tc.expected, exceeds)
+			}
+		})
+	}
+}
+
+func TestPublishSlowConsumer(t *testing.T) {
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	rate := model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83}
+
+	testCases := []struct {
+		name                string
+		policy              string
+		waited              time.Duration
+		expectedDisconnects float64
+	}{
+		{name: "Conflate keeps the session", policy: "conflate", waited: time.Minute},
+		{name: "Disconnect spares a session within its heartbeat", policy: SlowConsumerDisconnect, waited: 0},
+		{name: "Disconnect closes a stalled session", policy: SlowConsumerDisconnect, waited: time.Minute, expectedDisconnects: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, registry := newTestServer(config.FIXConfig{SlowConsumerPolicy: tc.policy})
+			conn, peer := net.Pipe()
+			defer peer.Close()
+
+			sess := newSession(server, conn)
+			sess.heartbeat = 30 * time.Second
+			sess.subscriptions[pair] = []subscription{{reqID: "req1"}}
+			sess.pending[pair] = queuedRate{rate: rate, queued: time.Now().Add(-tc.waited)}
+			server.sessions[sess] = struct{}{}
+
+			if err := server.Publish(context.Background(), []model.ExchangeRate{rate}); err != nil {
+				t.Fatal(err)
+			}
+
+			values := counterValues(t, registry)
+			if values["fix_dropped_updates_total"] != 1 {
+				t.Errorf("Expected 1 dropped update, got %v", values["fix_dropped_updates_total"])
+			}
+			if values["fix_slow_consumer_disconnects_total"] != tc.expectedDisconnects {
+				t.Errorf("Expected %v disconnects, got %v", tc.expectedDisconnects, values["fix_slow_consumer_disconnects_total"])
+			}
+
+			peer.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+			_, err := peer.Read(make([]byte, 1))
+			if closed := err != nil && !isTimeout(err); closed != (tc.expectedDisconnects > 0) {
+				t.Errorf("Expected connection closed %t, got read error %v", tc.expectedDisconnects > 0, err)
+			}
+		})
+	}
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+const (
+	defaultBackfillLimit = 100
+	defaultSLAWindow     = 24 * time.Hour
+	defaultStatusWindow  = time.Hour
+	defaultDiffTolerance = 0.5
+)
+
+// parseOptionalPair reads from/to query parameters. Both absent means all pairs.
+func parseOptionalPair(r *http.Request) (*model.CurrencyPair, bool) {
+	from := model.Currency(r.URL.Query().Get("from"))
+	to := model.Currency(r.URL.Query().Get("to"))
+
+	if from == "" && to == "" {
+		return nil, true
+	}
+	if from == "" || to == "" {
+		return nil, false
+	}
+
+	return &model.CurrencyPair{BaseCurrency: from, TargetCurrency: to}, true
+}
+
+func (h *Handler) HistoryGapsHandler(w http.ResponseWriter, r *http.Request) {
+	pair, ok := parseOptionalPair(r)
+	if !ok {
+		h.sendErrorResponse(w, http.StatusBadRequest, "from and to must be given together")
+		return
+	}
+
+	ctx := r.Context()
+	report, err := h.service.HistoryGaps(ctx, pair)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.metrics.ObserveHistoryGaps(report)
+	h.sendSuccessResponse(w, r, report)
+}
+
+func (h *Handler) BackfillHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	pair, ok := parseOptionalPair(r)
+	if !ok {
+		h.sendErrorResponse(w, http.StatusBadRequest, "from and to must be given together")
+		return
+	}
+
+	limit := defaultBackfillLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+	}
+
+	ctx := r.Context()
+	result, err := h.service.BackfillHistory(ctx, pair, limit)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	if report, err := h.service.HistoryGaps(ctx, pair); err == nil {
+		h.metrics.ObserveHistoryGaps(report)
+	}
+
+	h.sendSuccessResponse(w, r, result)
+}
+
+// CacheStatsHandler reports the rate cache statistics and refreshes the cache metrics with them
+func (h *Handler) CacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	stats := h.service.CacheStats(r.Context())
+	h.metrics.ObserveCacheStats(stats)
+	h.sendSuccessResponse(w, r, stats)
+}
+
+// parseWindow reads the window query parameter, fallback when absent
+func parseWindow(r *http.Request, fallback time.Duration) (time.Duration, bool) {
+	windowStr := r.URL.Query().Get("window")
+	if windowStr == "" {
+		return fallback, true
+	}
+	window, err := time.ParseDuration(windowStr)
+	return window, err == nil && window > 0
+}
+
+func (h *Handler) ProviderSLAHandler(w http.ResponseWriter, r *http.Request) {
+	window, ok := parseWindow(r, defaultSLAWindow)
+	if !ok {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid window parameter, use a duration such as 24h")
+		return
+	}
+
+	ctx := r.Context()
+	summaries, err := h.service.ProviderSLA(ctx, window)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, summaries)
+}
+
+// ProviderHealthHandler reports the failover state of each provider of the chain
+func (h *Handler) ProviderHealthHandler(w http.ResponseWriter, r *http.Request) {
+	health, err := h.service.ProviderHealth(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, health)
+}
+
+// ProviderStatusHandler reports, per upstream provider, the last success,
+// consecutive failures, latency percentiles over the window and whether the
+// chain skips it
+func (h *Handler) ProviderStatusHandler(w http.ResponseWriter, r *http.Request) {
+	window, ok := parseWindow(r, defaultStatusWindow)
+	if !ok {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid window parameter, use a duration such as 1h")
+		return
+	}
+
+	statuses, err := h.service.ProviderStatus(r.Context(), window)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, statuses)
+}
+
+// ProviderUsageHandler reports the calls made to each provider, against the
+// monthly budget when there is one
+func (h *Handler) ProviderUsageHandler(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.service.ProviderUsage(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, usage)
+}
+
+// HealthHandler answers OK, or DEGRADED while refreshes are paused because
+// the provider rate limited them. Either way rates are still served.
+func (h *Handler) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if limit := h.service.ProviderRateLimit(r.Context()); limit.Limited {
+		w.Write([]byte("DEGRADED: provider rate limited until " + limit.Until.UTC().Format(time.RFC3339)))
+		return
+	}
+	w.Write([]byte("OK"))
+}
+
+func (h *Handler) ProviderDiffHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := model.Currency(query.Get("from"))
+	to := model.Currency(query.Get("to"))
+	if from == "" || to == "" || query.Get("start_date") == "" || query.Get("end_date") == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameters: from, to, start_date, and end_date")
+		return
+	}
+
+	startDate, err := parseDate(query.Get("start_date"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := parseDate(query.Get("end_date"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	tolerance := defaultDiffTolerance
+	if toleranceStr := query.Get("tolerance"); toleranceStr != "" {
+		tolerance, err = strconv.ParseFloat(toleranceStr, 64)
+		if err != nil || tolerance < 0 {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid tolerance parameter, use a percentage such as 0.5")
+			return
+		}
+	}
+
+	request := model.ProviderDiffRequest{
+		Pair:      model.CurrencyPair{BaseCurrency: from, TargetCurrency: to},
+		StartDate: startDate,
+		EndDate:   endDate,
+		Tolerance: tolerance,
+	}
+
+	ctx := r.Context()
+	diff, err := h.service.DiffProviders(ctx, request)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, diff)
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSeparateAdmin(t *testing.T) {
+	router := newGoldenRouter(t)
+	router.SeparateAdmin()
+	admin := router.SetupAdminRoutes()
+	public := router.SetupRoutes()
+
+	testCases := []struct {
+		target         string
+		expectedPublic int
+		expectedAdmin  int
+	}{
+		{target: "/api/v1/rates?from=USD&to=INR", expectedPublic: 200, expectedAdmin: 404},
+		{target: "/api/v1/admin/cache/stats", expectedPublic: 404, expectedAdmin: 200},
+		{target: "/metrics", expectedPublic: 404, expectedAdmin: 200},
+		{target: "/debug/pprof/", expectedPublic: 404, expectedAdmin: 200},
+		{target: "/health", expectedPublic: 200, expectedAdmin: 200},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.target, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			public.ServeHTTP(rec, httptest.NewRequest("GET", tc.target, nil))
+			if rec.Code != tc.expectedPublic {
+				t.Errorf("Expected %d from the public listener, got %d", tc.expectedPublic, rec.Code)
+			}
+
+			rec = httptest.NewRecorder()
+			admin.ServeHTTP(rec, httptest.NewRequest("GET", tc.target, nil))
+			if rec.Code != tc.expectedAdmin {
+				t.Errorf("Expected %d from the admin listener, got %d", tc.expectedAdmin, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"exchange-rate-service/internal/adapter/webhook"
+	"exchange-rate-service/internal/domain/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AlertDocuments exports and imports the alert rules and the webhook
+// subscriptions of every tenant as one document
+type AlertDocuments interface {
+	Export(ctx context.Context, includeSecrets bool) (*model.AlertDocument, error)
+	Import(ctx context.Context, document model.AlertDocument, prune bool) (*model.AlertImportResult, error)
+}
+
+// isYAML reports whether mediaType, a Content-Type or an Accept entry, is YAML
+func isYAML(mediaType string) bool {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	return err == nil && (parsed == "application/yaml" || parsed == "application/x-yaml" || parsed == "text/yaml")
+}
+
+// exportAlertsHandler answers the document itself rather than an envelope,
+// so it can be committed as is. It is YAML with format=yaml or when YAML is
+// accepted, and JSON otherwise.
+func (h *Handler) exportAlertsHandler(alerts AlertDocuments) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		includeSecrets := r.URL.Query().Get("include_secrets") == "true"
+		document, err := alerts.Export(r.Context(), includeSecrets)
+		if err != nil {
+			h.handleServiceError(w, err)
+			return
+		}
+
+		asYAML := r.URL.Query().Get("format") == "yaml"
+		if format := r.URL.Query().Get("format"); format == "" {
+			for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+				asYAML = asYAML || isYAML(strings.TrimSpace(accepted))
+			}
+		} else if format != "json" && format != "yaml" {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid format, use json or yaml")
+			return
+		}
+
+		var body bytes.Buffer
+		if asYAML {
+			encoder := yaml.NewEncoder(&body)
+			encoder.SetIndent(2)
+			err = encoder.Encode(document)
+			w.Header().Set("Content-Type", "application/yaml")
+			w.Header().Set("Content-Disposition", `attachment; filename="alerts.yaml"`)
+		} else {
+			encoder := json.NewEncoder(&body)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(document)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Disposition", `attachment; filename="alerts.json"`)
+		}
+		if err != nil {
+			h.log.Error("Failed to encode alert document", "error", err)
+			h.sendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		if includeSecrets {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Write(body.Bytes())
+	}
+}
+
+// decodeDocument reads the body of r into document, as YAML when sent with a
+// YAML Content-Type and as JSON otherwise. Unknown fields are rejected, as
+// they are likely typos that would otherwise be dropped silently. It answers
+// 400 and returns false when the body is invalid.
+func (h *Handler) decodeDocument(w http.ResponseWriter, r *http.Request, document any) bool {
+	body := http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	if isYAML(r.Header.Get("Content-Type")) {
+		decoder := yaml.NewDecoder(body)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(document); err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid YAML body: "+err.Error())
+			return false
+		}
+		return true
+	}
+
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(document); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// importAlertsHandler takes a JSON document, or a YAML one sent with a YAML
+// Content-Type
+func (h *Handler) importAlertsHandler(alerts AlertDocuments) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var document model.AlertDocument
+		if !h.decodeDocument(w, r, &document) {
+			return
+		}
+
+		result, err := alerts.Import(r.Context(), document, r.URL.Query().Get("prune") == "true")
+		if err != nil {
+			if errors.Is(err, webhook.ErrInvalidAlertDocument) {
+				h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			h.handleServiceError(w, err)
+			return
+		}
+
+		// Created subscriptions carry their secrets
+		w.Header().Set("Cache-Control", "no-store")
+		h.sendSuccessResponse(w, r, result)
+	}
+}
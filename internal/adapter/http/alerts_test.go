@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/adapter/webhook"
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestAlertDocumentRoundTrip(t *testing.T) {
+	log := logger.NewLogger("error")
+	webhookStore, err := store.NewFileWebhookStore(filepath.Join(t.TempDir(), "webhooks.json"), 10, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newGoldenRouter(t)
+	router.ManageAlerts(webhook.NewDispatcher(webhookStore, config.WebhookConfig{Timeout: time.Second, AlertThreshold: 1}, goldenMetrics(), log))
+	handler := router.SetupRoutes()
+
+	send := func(method, target, contentType, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	document := `alert_rules:
+  threshold_percent: 2
+webhooks:
+  - id: whk_ops
+    tenant: ops
+    url: https://ops.example/hooks
+    events:
+      - alert.triggered
+      - anomaly.detected
+`
+	if rec := send(http.MethodPost, "/api/v1/admin/alerts/import", "application/yaml", document); rec.Code != http.StatusOK {
+		t.Fatalf("Import status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := send(http.MethodGet, "/api/v1/admin/alerts/export?format=yaml", "", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/yaml" {
+		t.Fatalf("Export status = %d, type %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	if rec.Body.String() != document {
+		t.Errorf("Export =\n%s\nwant the imported document\n%s", rec.Body.String(), document)
+	}
+
+	if rec := send(http.MethodGet, "/api/v1/admin/alerts/export", "", ""); !strings.Contains(rec.Body.String(), `"threshold_percent": 2`) {
+		t.Errorf("JSON export = %s", rec.Body.String())
+	}
+
+	for name, body := range map[string]string{
+		"unknown field":    `{"alert_rules": {"threshold": 2}}`,
+		"invalid document": `{"webhooks": [{"tenant": "ops", "url": "ftp://ops.example", "events": ["alert.triggered"]}]}`,
+	} {
+		if rec := send(http.MethodPost, "/api/v1/admin/alerts/import", "application/json", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Import of %s status = %d, want %d", name, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
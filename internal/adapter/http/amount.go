@@ -0,0 +1,113 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+var errCurrencyMismatch = errors.New("amount currency does not match from currency")
+
+// parseAmount accepts plain numbers as well as UI-formatted amounts such as
+// "1,234.56", "₹1,00,000" or "USD 100". A currency symbol or code, if present,
+// must match from. Commas are grouping separators and must form valid groups
+// (Western 3-digit or Indian 2-digit groups, always ending with 3 digits).
+func parseAmount(input string, from model.Currency) (float64, error) {
+	s := strings.TrimSpace(input)
+
+	s, err := stripCurrency(s, from)
+	if err != nil {
+		return 0, err
+	}
+
+	s = strings.Map(func(r rune) rune {
+		if r == '_' || r == '\'' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	if strings.Contains(s, ",") {
+		if s, err = stripGrouping(s); err != nil {
+			return 0, err
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid amount: %q", input)
+	}
+
+	return value, nil
+}
+
+// stripCurrency removes a leading or trailing currency symbol or ISO code
+func stripCurrency(s string, from model.Currency) (string, error) {
+	for _, currency := range model.SupportedCurrencies {
+		code := currency.String()
+		if strings.HasPrefix(s, code) || strings.HasSuffix(s, code) {
+			if currency != from {
+				return "", errCurrencyMismatch
+			}
+			return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, code), code)), nil
+		}
+	}
+
+	if first, size := utf8.DecodeRuneInString(s); size > 0 && isCurrencySymbol(first) {
+		return matchSymbol(s[size:], string(first), from)
+	}
+	if last, size := utf8.DecodeLastRuneInString(s); size > 0 && isCurrencySymbol(last) {
+		return matchSymbol(s[:len(s)-size], string(last), from)
+	}
+
+	return s, nil
+}
+
+func isCurrencySymbol(r rune) bool {
+	return unicode.Is(unicode.Sc, r)
+}
+
+func matchSymbol(rest, symbol string, from model.Currency) (string, error) {
+	currency, known := model.CurrencyForSymbol(symbol)
+	if !known || currency != from {
+		return "", errCurrencyMismatch
+	}
+	return strings.TrimSpace(rest), nil
+}
+
+func stripGrouping(s string) (string, error) {
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+
+	sign := ""
+	if strings.HasPrefix(integer, "-") || strings.HasPrefix(integer, "+") {
+		sign, integer = integer[:1], integer[1:]
+	}
+
+	groups := strings.Split(integer, ",")
+	for i, group := range groups {
+		valid := false
+		switch {
+		case i == 0:
+			valid = len(group) >= 1 && len(group) <= 3
+		case i == len(groups)-1:
+			valid = len(group) == 3
+		default:
+			valid = len(group) == 2 || len(group) == 3
+		}
+		if !valid || strings.Trim(group, "0123456789") != "" {
+			return "", fmt.Errorf("invalid digit grouping in amount: %q", s)
+		}
+	}
+
+	result := sign + strings.Join(groups, "")
+	if hasFraction {
+		result += "." + fraction
+	}
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"testing"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestParseAmount(t *testing.T) {
+
+	testCases := []struct {
+		name           string
+		input          string
+		from           model.Currency
+		expectedAmount float64
+		expectError    bool
+	}{
+		{name: "Plain", input: "100", from: model.USD, expectedAmount: 100},
+		{name: "Decimal", input: "99.95", from: model.USD, expectedAmount: 99.95},
+		{name: "Grouped", input: "1,234.56", from: model.USD, expectedAmount: 1234.56},
+		{name: "Indian Grouping", input: "₹1,00,000", from: model.INR, expectedAmount: 100000},
+		{name: "Leading Symbol", input: "₹1000", from: model.INR, expectedAmount: 1000},
+		{name: "Trailing Symbol", input: "12,50 €", from: model.EUR, expectError: true},
+		{name: "Trailing Symbol No Grouping", input: "1250 €", from: model.EUR, expectedAmount: 1250},
+		{name: "ISO Code", input: "GBP 20", from: model.GBP, expectedAmount: 20},
+		{name: "Spaces", input: "1 000 000", from: model.JPY, expectedAmount: 1000000},
+		{name: "Symbol Mismatch", input: "$100", from: model.INR, expectError: true},
+		{name: "Code Mismatch", input: "EUR 100", from: model.USD, expectError: true},
+		{name: "Unknown Symbol", input: "₩100", from: model.USD, expectError: true},
+		{name: "Bad Grouping", input: "1,2345", from: model.USD, expectError: true},
+		{name: "Not A Number", input: "abc", from: model.USD, expectError: true},
+		{name: "Infinity", input: "Inf", from: model.USD, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			amount, err := parseAmount(tc.input, tc.from)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q, got amount: %f", tc.input, amount)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error for %q, got: %v", tc.input, err)
+			}
+
+			if amount != tc.expectedAmount {
+				t.Errorf("Expected amount: %f, got: %f", tc.expectedAmount, amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+const maxRequestBodySize = 1 << 20
+
+type createAnnotationRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Date string `json:"date"`
+	Text string `json:"text"`
+}
+
+func (h *Handler) CreateAnnotationHandler(w http.ResponseWriter, r *http.Request) {
+	var body createAnnotationRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	date, err := parseDate(body.Date)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid date format, use YYYY-MM-DD")
+		return
+	}
+
+	annotation := model.Annotation{
+		BaseCurrency:   model.Currency(body.From),
+		TargetCurrency: model.Currency(body.To),
+		Date:           date,
+		Text:           body.Text,
+	}
+
+	ctx := r.Context()
+	created, err := h.service.CreateAnnotation(ctx, annotation)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, created)
+}
+
+func (h *Handler) ListAnnotationsHandler(w http.ResponseWriter, r *http.Request) {
+	from := model.Currency(r.URL.Query().Get("from"))
+	to := model.Currency(r.URL.Query().Get("to"))
+
+	if (from == "") != (to == "") {
+		h.sendErrorResponse(w, http.StatusBadRequest, "from and to must be given together")
+		return
+	}
+
+	var startDate, endDate time.Time
+	var err error
+	if startDate, err = parseDate(r.URL.Query().Get("start_date")); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+	if endDate, err = parseDate(r.URL.Query().Get("end_date")); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	filter := model.AnnotationFilter{
+		BaseCurrency:   from,
+		TargetCurrency: to,
+		StartDate:      startDate,
+		EndDate:        endDate,
+	}
+
+	ctx := r.Context()
+	annotations, err := h.service.ListAnnotations(ctx, filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, annotations)
+}
+
+func (h *Handler) DeleteAnnotationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := h.service.DeleteAnnotation(ctx, r.PathValue("id")); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, nil)
+}
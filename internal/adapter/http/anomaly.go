@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// AnomalyLog lists the abnormal rate moves detected recently
+type AnomalyLog interface {
+	Anomalies(base, target model.Currency) []model.RateAnomaly
+}
+
+func (h *Handler) listAnomaliesHandler(anomalies AnomalyLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		from := model.Currency(strings.ToUpper(query.Get("from")))
+		to := model.Currency(strings.ToUpper(query.Get("to")))
+
+		h.sendSuccessResponse(w, r, anomalies.Anomalies(from, to))
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/adapter/webhook"
+	"exchange-rate-service/internal/apply"
+)
+
+// ConfigurationApplier brings the runtime resources to the state of a
+// declarative document
+type ConfigurationApplier interface {
+	Apply(ctx context.Context, document apply.Document, dryRun bool) (*apply.Plan, error)
+}
+
+// applyHandler takes a JSON or YAML document and answers the plan of its
+// changes, which are only made without dry_run=true
+func (h *Handler) applyHandler(applier ConfigurationApplier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var document apply.Document
+		if !h.decodeDocument(w, r, &document) {
+			return
+		}
+
+		plan, err := applier.Apply(r.Context(), document, r.URL.Query().Get("dry_run") == "true")
+		if err != nil {
+			if errors.Is(err, apply.ErrInvalidDocument) || errors.Is(err, webhook.ErrInvalidAlertDocument) {
+				h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			h.handleServiceError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, plan)
+	}
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"exchange-rate-service/internal/apply"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+type stubPairPolicy struct {
+	policy model.CurrencyPolicy
+}
+
+func (p *stubPairPolicy) CurrencyPolicy() model.CurrencyPolicy          { return p.policy }
+func (p *stubPairPolicy) SetCurrencyPolicy(policy model.CurrencyPolicy) { p.policy = policy }
+
+func TestApplyConfiguration(t *testing.T) {
+	pairs := &stubPairPolicy{}
+	router := newGoldenRouter(t)
+	router.ApplyConfiguration(apply.NewApplier(apply.Resources{Pairs: pairs}, logger.NewLogger("error")))
+	handler := router.SetupRoutes()
+
+	send := func(target, contentType, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	document := "pairs:\n  allow: [USD-INR]\n  deny: []\n"
+	rec := send("/api/v1/admin/apply?dry_run=true", "application/yaml", document)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"applied":false`) || len(pairs.policy.AllowedPairs) != 0 {
+		t.Fatalf("Dry run status = %d, allowed %v: %s", rec.Code, pairs.policy.AllowedPairs, rec.Body.String())
+	}
+
+	rec = send("/api/v1/admin/apply", "application/yaml", document)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"applied":true`) || len(pairs.policy.AllowedPairs) != 1 {
+		t.Fatalf("Apply status = %d, allowed %v: %s", rec.Code, pairs.policy.AllowedPairs, rec.Body.String())
+	}
+
+	for name, body := range map[string]string{
+		"unknown field":    `{"pairs": {"permit": ["USD-INR"]}}`,
+		"invalid pair":     `{"pairs": {"allow": ["USD"]}}`,
+		"disabled section": `{"keys": ["secret"]}`,
+	} {
+		if rec := send("/api/v1/admin/apply", "application/json", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Apply of %s status = %d, want %d", name, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
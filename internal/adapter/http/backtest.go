@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func (h *Handler) BacktestHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := model.Currency(query.Get("from"))
+	to := model.Currency(query.Get("to"))
+	amountStr := query.Get("amount")
+	startDateStr := query.Get("start_date")
+	endDateStr := query.Get("end_date")
+
+	if from == "" || to == "" || amountStr == "" || startDateStr == "" || endDateStr == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameters: from, to, amount, start_date, and end_date")
+		return
+	}
+
+	amount, err := parseAmount(amountStr, from)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid amount parameter")
+		return
+	}
+
+	interval := model.BacktestInterval(query.Get("interval"))
+	if interval == "" {
+		interval = model.BacktestMonthly
+	}
+	if !interval.Valid() {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid interval parameter, use daily, weekly or monthly")
+		return
+	}
+
+	startDate, err := parseDate(startDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := parseDate(endDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	backtest, err := h.service.Backtest(r.Context(), model.BacktestRequest{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Amount:       amount,
+		Interval:     interval,
+		StartDate:    startDate,
+		EndDate:      endDate,
+	})
+	h.observeUnsupportedCurrencies(err, from, to)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendHistoricalResponse(w, r, backtest)
+}
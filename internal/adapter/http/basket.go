@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+type basketComponentRequest struct {
+	Currency model.Currency `json:"currency"`
+	Weight   float64        `json:"weight"`
+}
+
+type createBasketRequest struct {
+	Name       string                   `json:"name"`
+	Base       model.Currency           `json:"base"`
+	Components []basketComponentRequest `json:"components"`
+}
+
+func (h *Handler) CreateBasketHandler(w http.ResponseWriter, r *http.Request) {
+	var body createBasketRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	basket := model.Basket{Name: body.Name, Base: body.Base}
+	for _, component := range body.Components {
+		basket.Components = append(basket.Components, model.BasketComponent{Currency: component.Currency, Weight: component.Weight})
+	}
+
+	created, err := h.service.CreateBasket(r.Context(), model.TenantFromContext(r.Context()), basket)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, created)
+}
+
+func (h *Handler) ListBasketsHandler(w http.ResponseWriter, r *http.Request) {
+	baskets, err := h.service.ListBaskets(r.Context(), model.TenantFromContext(r.Context()))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, baskets)
+}
+
+func (h *Handler) DeleteBasketHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteBasket(r.Context(), model.TenantFromContext(r.Context()), r.PathValue("id")); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, nil)
+}
+
+func (h *Handler) QuoteBasketHandler(w http.ResponseWriter, r *http.Request) {
+	currency := model.Currency(r.URL.Query().Get("currency"))
+	if currency == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameter: currency")
+		return
+	}
+
+	quote, err := h.service.QuoteBasket(r.Context(), model.TenantFromContext(r.Context()), r.PathValue("id"), currency)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, quote)
+}
+
+func (h *Handler) ChartBasketHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	currency := model.Currency(query.Get("currency"))
+	startDateStr := query.Get("start_date")
+	endDateStr := query.Get("end_date")
+
+	if currency == "" || startDateStr == "" || endDateStr == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameters: currency, start_date, and end_date")
+		return
+	}
+
+	startDate, err := parseDate(startDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := parseDate(endDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	chart, err := h.service.ChartBasket(r.Context(), model.TenantFromContext(r.Context()), r.PathValue("id"), currency, startDate, endDate)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendHistoricalResponse(w, r, chart)
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func (h *Handler) TravelBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := model.Currency(query.Get("from"))
+	to := model.Currency(query.Get("to"))
+	dailyStr := query.Get("daily")
+	startDateStr := query.Get("start_date")
+	endDateStr := query.Get("end_date")
+
+	if from == "" || to == "" || dailyStr == "" || startDateStr == "" || endDateStr == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameters: from, to, daily, start_date, and end_date")
+		return
+	}
+
+	daily, err := parseAmount(dailyStr, from)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid daily parameter")
+		return
+	}
+
+	startDate, err := parseDate(startDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := parseDate(endDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	budget, err := h.service.TravelBudget(r.Context(), model.TravelBudgetRequest{
+		HomeCurrency:        from,
+		DestinationCurrency: to,
+		DailyAmount:         daily,
+		StartDate:           startDate,
+		EndDate:             endDate,
+	})
+	h.observeUnsupportedCurrencies(err, from, to)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendHistoricalResponse(w, r, budget)
+}
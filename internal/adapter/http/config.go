@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"exchange-rate-service/internal/config"
+)
+
+type configResponse struct {
+	LoadedAt time.Time        `json:"loaded_at"`
+	Dirs     []string         `json:"dirs"`
+	Settings []config.Setting `json:"settings"`
+}
+
+// configHandler reports the effective configuration and where each value came from
+func (h *Handler) configHandler(current func() *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cfg := current()
+		h.sendSuccessResponse(w, r, configResponse{
+			LoadedAt: cfg.LoadedAt,
+			Dirs:     cfg.Dirs(),
+			Settings: cfg.RedactedSettings(),
+		})
+	}
+}
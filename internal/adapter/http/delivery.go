@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/delivery"
+	"exchange-rate-service/internal/domain/model"
+)
+
+// DeliveryLedger exposes failed webhook and publisher deliveries to operators
+type DeliveryLedger interface {
+	List(ctx context.Context, filter model.DeliveryFilter) ([]model.Delivery, error)
+	Get(ctx context.Context, id string) (*model.Delivery, error)
+	Replay(ctx context.Context, id string) (*model.Delivery, error)
+}
+
+func (h *Handler) listDeliveriesHandler(ledger DeliveryLedger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := model.DeliveryFilter{
+			Status: model.DeliveryStatus(r.URL.Query().Get("status")),
+			Kind:   r.URL.Query().Get("kind"),
+			Target: r.URL.Query().Get("target"),
+		}
+		switch filter.Status {
+		case "", model.DeliveryRetrying, model.DeliveryDelivered, model.DeliveryDead:
+		default:
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid status, use retrying, delivered or dead")
+			return
+		}
+
+		deliveries, err := ledger.List(r.Context(), filter)
+		if err != nil {
+			h.handleDeliveryError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, deliveries)
+	}
+}
+
+func (h *Handler) getDeliveryHandler(ledger DeliveryLedger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := ledger.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			h.handleDeliveryError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, d)
+	}
+}
+
+func (h *Handler) replayDeliveryHandler(ledger DeliveryLedger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d, err := ledger.Replay(r.Context(), r.PathValue("id"))
+		if err != nil {
+			h.handleDeliveryError(w, err)
+			return
+		}
+
+		// The replay itself may have failed; the entry says how it went
+		d.Payload = nil
+		h.sendSuccessResponse(w, r, d)
+	}
+}
+
+func (h *Handler) handleDeliveryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, delivery.ErrDeliveryNotFound):
+		h.sendErrorResponse(w, http.StatusNotFound, "delivery not found")
+	case errors.Is(err, delivery.ErrAlreadyDelivered):
+		h.sendErrorResponse(w, http.StatusConflict, "delivery already succeeded")
+	default:
+		h.handleServiceError(w, err)
+	}
+}
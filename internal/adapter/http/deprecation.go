@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// deprecation describes a route or query parameter that has a successor.
+// Until Sunset it keeps working and announces its retirement with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers; afterwards a route
+// answers 410 Gone and a parameter 400 Bad Request.
+type deprecation struct {
+	Since     time.Time
+	Sunset    time.Time
+	Successor string
+}
+
+// deprecatedRoutes lists retired routes by their registration pattern, e.g.
+//
+//	"/api/v1/historical": {
+//		Since:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+//		Sunset:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Successor: "/api/v2/historical",
+//	},
+//
+// Give clients at least six months between Since and Sunset.
+var deprecatedRoutes = map[string]deprecation{}
+
+// deprecatedParams lists retired query parameters by name, on every route
+// that accepts them. Successor names the replacement parameter or a page
+// describing it, e.g.
+//
+//	"cash_rounding": {
+//		Since:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+//		Sunset:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Successor: "/docs/rounding",
+//	},
+var deprecatedParams = map[string]deprecation{}
+
+// deprecate wraps handler with the deprecation registered for pattern, if any
+func (r *Router) deprecate(pattern string, handler http.Handler) http.Handler {
+	d, exists := deprecatedRoutes[pattern]
+	if !exists {
+		return handler
+	}
+	return d.middleware(r.countDeprecated(pattern), handler)
+}
+
+// deprecatedParamsMiddleware announces, and after the sunset rejects, requests
+// using a parameter from deprecatedParams
+func (r *Router) deprecatedParamsMiddleware(next http.Handler) http.Handler {
+	if len(deprecatedParams) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		for name, d := range deprecatedParams {
+			if !query.Has(name) {
+				continue
+			}
+
+			r.countDeprecated("param:" + name)()
+			d.announce(w)
+			if d.retired() {
+				writeRetired(w, http.StatusBadRequest, "the "+name+" parameter was retired on "+d.Sunset.UTC().Format("2006-01-02"))
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// countDeprecated returns a func that counts one use of feature
+func (r *Router) countDeprecated(feature string) func() {
+	if r.metrics == nil || r.metrics.DeprecatedUsage == nil {
+		return func() {}
+	}
+	counter := r.metrics.DeprecatedUsage.WithLabelValues(feature)
+	return counter.Inc
+}
+
+func (d deprecation) middleware(used func(), next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		used()
+		d.announce(w)
+		if d.retired() {
+			writeRetired(w, http.StatusGone, "this endpoint was retired on "+d.Sunset.UTC().Format("2006-01-02"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (d deprecation) announce(w http.ResponseWriter) {
+	w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	if !d.Sunset.IsZero() {
+		w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		w.Header().Add("Link", "<"+d.Successor+`>; rel="successor-version"`)
+	}
+}
+
+func (d deprecation) retired() bool {
+	return !d.Sunset.IsZero() && time.Now().After(d.Sunset)
+}
+
+func writeRetired(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(Response{
+		Success: false,
+		Error:   message,
+	})
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// envelopeHeader lets clients that cannot add query parameters opt out of the envelope
+const envelopeHeader = "X-Envelope"
+
+// parseEnvelope reads ?envelope= or the X-Envelope header, the query parameter
+// winning when both are set. ok is false for a value that is not a boolean.
+func parseEnvelope(r *http.Request) (enveloped bool, ok bool) {
+	value := r.URL.Query().Get("envelope")
+	if value == "" {
+		value = r.Header.Get(envelopeHeader)
+	}
+	if value == "" {
+		return true, true
+	}
+
+	enveloped, err := strconv.ParseBool(value)
+	if err != nil {
+		return true, false
+	}
+	return enveloped, true
+}
+
+// envelopeMiddleware rejects envelope values that are not booleans before any handler runs
+func envelopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := parseEnvelope(r); !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(Response{
+				Success: false,
+				Error:   "invalid envelope parameter, use true or false",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wantsEnvelope reports whether the success payload should be wrapped in
+// Response. Errors are always wrapped so the message has a single shape.
+func wantsEnvelope(r *http.Request) bool {
+	enveloped, _ := parseEnvelope(r)
+	return enveloped
+}
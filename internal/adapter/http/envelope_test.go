@@ -0,0 +1,56 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnvelopeOptOut(t *testing.T) {
+	router := goldenRouter(t)
+
+	tests := []struct {
+		name         string
+		target       string
+		header       string
+		wantStatus   int
+		wantEnvelope bool
+	}{
+		{"enveloped by default", "/api/v1/rates?from=USD&to=INR", "", http.StatusOK, true},
+		{"query opt-out", "/api/v1/rates?from=USD&to=INR&envelope=false", "", http.StatusOK, false},
+		{"header opt-out", "/api/v1/rates?from=USD&to=INR", "false", http.StatusOK, false},
+		{"query wins over header", "/api/v1/rates?from=USD&to=INR&envelope=true", "false", http.StatusOK, true},
+		{"errors keep the envelope", "/api/v1/rates?envelope=false", "", http.StatusBadRequest, true},
+		{"invalid value rejected", "/api/v1/rates?from=USD&to=INR&envelope=maybe", "", http.StatusBadRequest, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(envelopeHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Response is not JSON: %v", err)
+			}
+			_, enveloped := body["success"]
+			if enveloped != tt.wantEnvelope {
+				t.Errorf("Enveloped = %v, want %v: %s", enveloped, tt.wantEnvelope, rec.Body.String())
+			}
+			if !enveloped {
+				if _, ok := body["rate"]; !ok {
+					t.Errorf("Bare payload has no rate: %s", rec.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestServiceErrorsByKind(t *testing.T) {
+	h := NewHandler(nil, logger.NewLogger("error"), nil, "rfc3339")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantRetry  string
+		wantRange  string
+		wantAge    string
+		wantCache  string
+	}{
+		{
+			name:       "provider rate limit",
+			err:        fmt.Errorf("%w: %w", service.ErrExternalAPIFailure, &xerrors.Error{Kind: xerrors.RateLimited, Code: "http_429", Status: 429, RetryAfter: 30 * time.Second}),
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   "http_429",
+			wantRetry:  "31",
+		},
+		{
+			name:       "provider failure",
+			err:        fmt.Errorf("%w: %w", service.ErrExternalAPIFailure, &xerrors.Error{Kind: xerrors.Upstream, Code: "http_500", Status: 500}),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "http_500",
+		},
+		{
+			name:       "service sentinel",
+			err:        service.ErrInvalidCurrency,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_currency",
+		},
+		{
+			name:       "range too long",
+			err:        &service.RangeLimitError{MaxDays: 366},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "range_too_long",
+			wantRange:  "366",
+		},
+		{
+			name:       "rate older than the max age",
+			err:        &service.StaleRateError{Rate: &model.ExchangeRate{Rate: 83}, Age: time.Hour, MaxAge: time.Minute},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "stale_rate",
+			wantCache:  "STALE",
+			wantAge:    "3600",
+		},
+		{
+			name:       "unknown error of a kind",
+			err:        xerrors.New(xerrors.NotFound, "missing", "missing"),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "missing",
+		},
+		{
+			name:       "unclassified error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.handleServiceError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var response Response
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", response.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+			if got := rec.Header().Get("X-Max-Range-Days"); got != tt.wantRange {
+				t.Errorf("X-Max-Range-Days = %q, want %q", got, tt.wantRange)
+			}
+			if got := rec.Header().Get(cacheHeader); got != tt.wantCache {
+				t.Errorf("%s = %q, want %q", cacheHeader, got, tt.wantCache)
+			}
+			if got := rec.Header().Get(dataAgeHeader); got != tt.wantAge {
+				t.Errorf("%s = %q, want %q", dataAgeHeader, got, tt.wantAge)
+			}
+			// The stale rate comes along
+			if hasData := response.Data != nil; hasData != (tt.wantCache != "") {
+				t.Errorf("Data = %v, want it only with a stale rate", response.Data)
+			}
+		})
+	}
+}
+
+func TestPartialResultsAnswerMultiStatus(t *testing.T) {
+	matrix := &model.ConversionMatrix{
+		FromCurrency: model.USD,
+		Rates:        map[model.Currency]model.ExchangeRate{model.INR: {BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83}},
+		Rows:         []model.ConversionMatrixRow{{Amount: 1, Converted: map[model.Currency]float64{model.INR: 83}}},
+	}
+	exchangeService := &mocks.ExchangeServiceMock{
+		ConvertMatrixFunc: func(ctx context.Context, request model.ConversionMatrixRequest) (*model.ConversionMatrix, error) {
+			return matrix, nil
+		},
+	}
+	h := NewHandler(exchangeService, logger.NewLogger("error"), goldenMetrics(), "rfc3339")
+
+	convert := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ConvertMatrixHandler(rec, httptest.NewRequest("POST", "/api/v1/convert/matrix", strings.NewReader(`{"from":"USD","amounts":[1],"targets":["INR","JPY"]}`)))
+		return rec
+	}
+
+	if rec := convert(); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a complete matrix, got %d", rec.Code)
+	}
+
+	matrix.Errors = []model.ItemError{{Item: "JPY", Code: "unreachable", Message: "failed to send request"}}
+	rec := convert()
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected 207 for a partial matrix, got %d", rec.Code)
+	}
+	var body struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Errors []model.ItemError `json:"errors"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.Success || len(body.Data.Errors) != 1 || body.Data.Errors[0].Item != "JPY" {
+		t.Errorf("Expected the failed target to be listed, got %s", rec.Body.String())
+	}
+}
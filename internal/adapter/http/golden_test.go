@@ -0,0 +1,410 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/cache"
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Run `go test ./internal/adapter/http -run TestResponseSchemas -update` to
+// accept an intentional change to a response schema
+var update = flag.Bool("update", false, "rewrite the golden response fixtures")
+
+// goldenQuotes are the USD based quotes served by goldenRepository
+var goldenQuotes = map[model.Currency]float64{
+	model.USD: 1,
+	model.INR: 83.12,
+	model.EUR: 0.92,
+	model.JPY: 151.3,
+	model.GBP: 0.79,
+}
+
+// goldenRepository is a provider with fixed quotes for every day
+type goldenRepository struct {
+	name string
+}
+
+func (g goldenRepository) Name() string { return g.name }
+
+func (g goldenRepository) rate(pair model.CurrencyPair, date time.Time) *model.ExchangeRate {
+	return &model.ExchangeRate{
+		BaseCurrency:   pair.BaseCurrency,
+		TargetCurrency: pair.TargetCurrency,
+		Rate:           goldenQuotes[pair.TargetCurrency] / goldenQuotes[pair.BaseCurrency],
+		Date:           date,
+		LastUpdated:    time.Now().UTC(),
+	}
+}
+
+func (g goldenRepository) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	return g.rate(pair, time.Now().UTC().Truncate(24*time.Hour)), nil
+}
+
+func (g goldenRepository) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	return g.rate(pair, date), nil
+}
+
+func (g goldenRepository) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	pair := model.CurrencyPair{BaseCurrency: request.BaseCurrency, TargetCurrency: request.TargetCurrency}
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+	for date := request.StartDate; !date.After(request.EndDate); date = date.AddDate(0, 0, 1) {
+		result.Rates[date.Format("2006-01-02")] = *g.rate(pair, date)
+	}
+	return result, nil
+}
+
+func (g goldenRepository) RefreshRates(ctx context.Context) error { return nil }
+
+func (g goldenRepository) LatestRates(ctx context.Context) []model.ExchangeRate {
+	rates := make([]model.ExchangeRate, 0)
+	for target := range goldenQuotes {
+		if target != model.USD {
+			rates = append(rates, *g.rate(model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: target}, time.Now().UTC().Truncate(24*time.Hour)))
+		}
+	}
+	return rates
+}
+
+// goldenRouter wires the real service to goldenRepository and in-memory or
+// temporary stores
+func goldenRouter(t *testing.T) http.Handler {
+	return newGoldenRouter(t).SetupRoutes()
+}
+
+func newGoldenRouter(t *testing.T) *Router {
+	t.Helper()
+
+	log := logger.NewLogger("error")
+	dir := t.TempDir()
+
+	annotations, err := store.NewFileAnnotationStore(filepath.Join(dir, "annotations.json"), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, err := store.NewFileHistoryStore(filepath.Join(dir, "history.json"), 90, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sla, err := store.NewFileSLAStore(filepath.Join(dir, "sla.json"), 24*time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticks, err := store.NewFileTickStore(filepath.Join(dir, "ticks.json"), 24*time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exchangeService := service.NewExchangeService(
+		goldenRepository{name: "primary"},
+		service.WithCache(cache.NewMemoryCache(time.Hour, log)),
+		service.WithLogger(log),
+		service.WithSecondary(goldenRepository{name: "secondary"}),
+		service.WithConversionStore(store.NewMemoryConversionStore(100, log)),
+		service.WithAnnotationStore(annotations),
+		service.WithHistoryStore(history),
+		service.WithSLAStore(sla),
+		service.WithTickStore(ticks),
+		service.WithCurrencyPolicy(model.CurrencyPolicy{Matching: model.CurrencyMatchingStrict}),
+		service.WithRecentRates(cache.NewRingBuffer(10)),
+		service.WithCPI(goldenCPI{}),
+		service.WithProviderLicenses(model.ProviderLicenses{
+			"primary": {Required: true, Attribution: "Rates by Primary", TermsURL: "https://primary.example/terms"},
+		}),
+	)
+	if err := exchangeService.RefreshRates(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := exchangeService.SampleIntraday(context.Background(), []model.CurrencyPair{{BaseCurrency: model.USD, TargetCurrency: model.INR}}); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewHandler(exchangeService, log, goldenMetrics(), "rfc3339")
+	return NewRouter(handler, log, goldenMetrics(), nil, nil)
+}
+
+// goldenCPI has an index of 100 in 2015 rising by 3 a year
+type goldenCPI struct{}
+
+func (goldenCPI) At(ctx context.Context, currency model.Currency, date time.Time) (*model.CPIObservation, error) {
+	year := time.Date(date.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	return &model.CPIObservation{Currency: currency, Date: year, Value: 100 + 3*float64(date.Year()-2015)}, nil
+}
+
+func (c goldenCPI) Latest(ctx context.Context, currency model.Currency) (*model.CPIObservation, error) {
+	return c.At(ctx, currency, time.Now().UTC())
+}
+
+var sharedMetrics *metrics.Metrics
+
+// goldenMetrics registers the metrics once per test binary
+func goldenMetrics() *metrics.Metrics {
+	if sharedMetrics == nil {
+		sharedMetrics = metrics.NewMetrics()
+	}
+	return sharedMetrics
+}
+
+func TestResponseSchemas(t *testing.T) {
+	router := goldenRouter(t)
+
+	day := func(daysAgo int) string {
+		return time.Now().UTC().AddDate(0, 0, -daysAgo).Format("2006-01-02")
+	}
+
+	call := func(t *testing.T, method, target, body string) []byte {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code >= 300 {
+			t.Fatalf("%s %s returned %d: %s", method, target, rec.Code, rec.Body.String())
+		}
+		return rec.Body.Bytes()
+	}
+
+	var conversion struct {
+		Data struct {
+			ID string `json:"conversion_id"`
+		} `json:"data"`
+	}
+	converted := call(t, http.MethodGet, "/api/v1/convert?from=USD&to=INR&amount=100", "")
+	if err := json.Unmarshal(converted, &conversion); err != nil || conversion.Data.ID == "" {
+		t.Fatalf("Conversion response has no ID: %s", converted)
+	}
+
+	endpoints := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"rates", "GET", "/api/v1/rates?from=USD&to=INR", ""},
+		{"sparkline", "GET", "/api/v1/rates/sparkline?from=USD&to=INR&points=5", ""},
+		{"pairs", "GET", "/api/v1/pairs", ""},
+		{"currencies", "GET", "/api/v1/currencies", ""},
+		{"providers_status", "GET", "/api/v1/providers/status", ""},
+		{"providers_usage", "GET", "/api/v1/providers/usage", ""},
+		{"convert", "GET", "/api/v1/convert?from=USD&to=JPY&amount=100&cash_rounding=true", ""},
+		{"convert_matrix", "POST", "/api/v1/convert/matrix", `{"from":"USD","amounts":[10,100],"targets":["INR","EUR"]}`},
+		{"conversion", "GET", "/api/v1/conversions/" + conversion.Data.ID, ""},
+		{"historical", "GET", "/api/v1/historical?from=USD&to=EUR&date=" + day(3), ""},
+		{"historical_range", "GET", "/api/v1/historical/range?from=USD&to=EUR&start_date=" + day(3) + "&end_date=" + day(1), ""},
+		{"historical_stats", "GET", "/api/v1/historical/stats?from=USD&to=EUR&start_date=" + day(3) + "&end_date=" + day(1), ""},
+		{"budget", "GET", "/api/v1/budget?from=USD&to=EUR&daily=150&start_date=" + day(3) + "&end_date=" + day(1), ""},
+		{"backtest", "GET", "/api/v1/backtest?from=USD&to=INR&amount=1000&interval=daily&start_date=" + day(3) + "&end_date=" + day(1), ""},
+		{"inflation", "GET", "/api/v1/convert/inflation?from=USD&to=INR&amount=100&date=2015-06-01", ""},
+		{"ohlc", "GET", "/api/v1/ohlc?pair=USD-INR&interval=1h", ""},
+		{"annotation_create", "POST", "/api/v1/annotations", `{"from":"USD","to":"INR","date":"` + day(2) + `","text":"golden"}`},
+		{"annotations", "GET", "/api/v1/annotations?from=USD&to=INR", ""},
+		{"widget_convert", "GET", "/api/v1/widget/convert?from=USD&to=INR&amount=100", ""},
+		{"udf_config", "GET", "/udf/config", ""},
+		{"udf_symbols", "GET", "/udf/symbols?symbol=USDINR", ""},
+		{"udf_search", "GET", "/udf/search?query=USD", ""},
+		{"udf_history", "GET", "/udf/history?symbol=USDINR&resolution=60&from=0&to=" + fmt.Sprint(time.Now().Unix()), ""},
+		{"admin_history_gaps", "GET", "/api/v1/admin/history/gaps?from=USD&to=INR", ""},
+		{"admin_history_backfill", "POST", "/api/v1/admin/history/backfill?from=USD&to=INR&limit=2", ""},
+		{"admin_providers_sla", "GET", "/api/v1/admin/providers/sla", ""},
+		{"admin_cache_stats", "GET", "/api/v1/admin/cache/stats", ""},
+		{"admin_providers_diff", "GET", "/api/v1/admin/providers/diff?from=USD&to=INR&start_date=" + day(2) + "&end_date=" + day(1), ""},
+	}
+
+	for _, endpoint := range endpoints {
+		t.Run(endpoint.name, func(t *testing.T) {
+			body := call(t, endpoint.method, endpoint.target, endpoint.body)
+			path := filepath.Join("testdata", "golden", endpoint.name+".json")
+
+			if *update {
+				var pretty bytes.Buffer
+				if err := json.Indent(&pretty, body, "", "  "); err != nil {
+					t.Fatalf("Response is not JSON: %v", err)
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, pretty.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			golden, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Missing fixture, run with -update to create it: %v", err)
+			}
+			if diff := diffSchemas(schemaOf(t, golden), schemaOf(t, body)); len(diff) > 0 {
+				t.Errorf("Response schema changed, run with -update if intended:\n%s", strings.Join(diff, "\n"))
+			}
+		})
+	}
+}
+
+// dynamicKey matches object keys that are data rather than field names
+var dynamicKey = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// schemaOf flattens a JSON document into field paths and their JSON types.
+// Array elements share the path suffix [] and date keys become *.
+func schemaOf(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	schema := make(map[string]string)
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			schema[path] = "object"
+			for key, child := range value {
+				if dynamicKey.MatchString(key) {
+					key = "*"
+				}
+				walk(path+"."+key, child)
+			}
+		case []interface{}:
+			schema[path] = "array"
+			for _, child := range value {
+				walk(path+"[]", child)
+			}
+		case string:
+			schema[path] = "string"
+		case float64:
+			schema[path] = "number"
+		case bool:
+			schema[path] = "boolean"
+		case nil:
+			schema[path] = "null"
+		}
+	}
+	walk("$", doc)
+	return schema
+}
+
+func diffSchemas(want, got map[string]string) []string {
+	diff := make([]string, 0)
+	for path, typ := range want {
+		switch actual, exists := got[path]; {
+		case !exists:
+			diff = append(diff, fmt.Sprintf("  removed %s (%s)", path, typ))
+		case actual != typ:
+			diff = append(diff, fmt.Sprintf("  %s changed from %s to %s", path, typ, actual))
+		}
+	}
+	for path, typ := range got {
+		if _, exists := want[path]; !exists {
+			diff = append(diff, fmt.Sprintf("  added %s (%s)", path, typ))
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+func TestDeprecatedRoute(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	uses := 0
+	used := func() { uses++ }
+
+	active := deprecation{
+		Since:     time.Now().Add(-time.Hour),
+		Sunset:    time.Now().Add(time.Hour),
+		Successor: "/api/v2/rates",
+	}
+	rec := httptest.NewRecorder()
+	active.middleware(used, ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/rates", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Deprecation"), "@") || rec.Header().Get("Sunset") == "" {
+		t.Errorf("Expected deprecation headers on a working route, got %d %v", rec.Code, rec.Header())
+	}
+	if link := rec.Header().Get("Link"); link != `</api/v2/rates>; rel="successor-version"` {
+		t.Errorf("Unexpected Link header %q", link)
+	}
+
+	retired := active
+	retired.Sunset = time.Now().Add(-time.Minute)
+	rec = httptest.NewRecorder()
+	retired.middleware(used, ok).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/rates", nil))
+	if rec.Code != http.StatusGone {
+		t.Errorf("Expected 410 after the sunset, got %d", rec.Code)
+	}
+	if uses != 2 {
+		t.Errorf("Expected 2 counted uses, got %d", uses)
+	}
+}
+
+func TestDeprecatedParams(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	usage := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "deprecated_test"}, []string{"feature"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(usage)
+	router := &Router{metrics: &metrics.Metrics{DeprecatedUsage: usage}}
+
+	deprecatedParams["legacy"] = deprecation{Since: time.Now().Add(-time.Hour), Sunset: time.Now().Add(time.Hour)}
+	deprecatedParams["gone"] = deprecation{Since: time.Now().Add(-time.Hour), Sunset: time.Now().Add(-time.Minute)}
+	defer func() {
+		delete(deprecatedParams, "legacy")
+		delete(deprecatedParams, "gone")
+	}()
+	handler := router.deprecatedParamsMiddleware(ok)
+
+	tests := []struct {
+		name           string
+		target         string
+		wantStatus     int
+		wantDeprecated bool
+	}{
+		{"parameter not used", "/api/v1/rates?from=USD", http.StatusOK, false},
+		{"deprecated parameter", "/api/v1/rates?legacy=1", http.StatusOK, true},
+		{"retired parameter", "/api/v1/rates?gone=1", http.StatusBadRequest, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if deprecated := rec.Header().Get("Deprecation") != ""; deprecated != tt.wantDeprecated {
+				t.Errorf("Deprecation header present = %v, want %v", deprecated, tt.wantDeprecated)
+			}
+		})
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counted := map[string]float64{}
+	for _, m := range families[0].GetMetric() {
+		counted[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+	}
+	if counted["param:legacy"] != 1 || counted["param:gone"] != 1 {
+		t.Errorf("Expected one counted use of each parameter, got %v", counted)
+	}
+}
@@ -9,28 +9,46 @@ import (
 
 	"exchange-rate-service/internal/domain/model"
 	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/i18n"
 	"exchange-rate-service/internal/metrics"
 	"exchange-rate-service/internal/service"
 	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
 )
 
 type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Code names the error precisely, down to a provider's own error code
+	Code        string             `json:"code,omitempty"`
+	Attribution *model.Attribution `json:"attribution,omitempty"`
+	// Meta describes how the data was served, when asked for with include_meta
+	Meta *model.ResponseMeta `json:"meta,omitempty"`
 }
 
 type Handler struct {
-	service ports.ExchangeService
-	log     *logger.Logger
-	metrics *metrics.Metrics
+	service         ports.ExchangeService
+	log             *logger.Logger
+	metrics         *metrics.Metrics
+	timestampFormat timestampFormat
+	transforms      []ResponseTransform
+	staleAfter      time.Duration
 }
 
-func NewHandler(service ports.ExchangeService, log *logger.Logger, metrics *metrics.Metrics) *Handler {
+// NewHandler creates the API handler. timestampFormat is the default
+// serialization of timestamps (rfc3339, unix, unix_ms or date).
+func NewHandler(service ports.ExchangeService, log *logger.Logger, metrics *metrics.Metrics, timestampFormat string) *Handler {
+	format, ok := parseTimestampFormat(timestampFormat)
+	if !ok {
+		format = timestampRFC3339
+	}
+
 	return &Handler{
-		service: service,
-		log:     log,
-		metrics: metrics,
+		service:         service,
+		log:             log,
+		metrics:         metrics,
+		timestampFormat: format,
 	}
 }
 
@@ -54,16 +72,20 @@ func (h *Handler) GetLatestRateHandler(w http.ResponseWriter, r *http.Request) {
 	
 	ctx := r.Context()
 	rate, err := h.service.GetLatestRate(ctx, from, to)
+	h.observeUnsupportedCurrencies(err, from, to)
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 	
-	h.sendSuccessResponse(w, rate)
+	h.sendSuccessResponse(w, r, rate)
 }
 
 func (h *Handler) ConvertCurrencyHandler(w http.ResponseWriter, r *http.Request) {
-	h.metrics.ConversionRequestsTotal.Inc()
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	if !dryRun {
+		h.metrics.ConversionRequestsTotal.Inc()
+	}
 	
 	from := model.Currency(r.URL.Query().Get("from"))
 	to := model.Currency(r.URL.Query().Get("to"))
@@ -78,7 +100,11 @@ func (h *Handler) ConvertCurrencyHandler(w http.ResponseWriter, r *http.Request)
 	amount := 1.0
 	if amountStr != "" {
 		var err error
-		amount, err = strconv.ParseFloat(amountStr, 64)
+		amount, err = parseAmount(amountStr, from)
+		if errors.Is(err, errCurrencyMismatch) {
+			h.sendErrorResponse(w, http.StatusBadRequest, "amount currency does not match from currency")
+			return
+		}
 		if err != nil {
 			h.sendErrorResponse(w, http.StatusBadRequest, "invalid amount parameter")
 			return
@@ -95,24 +121,122 @@ func (h *Handler) ConvertCurrencyHandler(w http.ResponseWriter, r *http.Request)
 		}
 	}
 	
+	unit, err := model.ParseQuantityUnit(r.URL.Query().Get("unit"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid unit parameter")
+		return
+	}
+	
 	request := model.ConversionRequest{
 		FromCurrency: from,
 		ToCurrency:   to,
 		Amount:       amount,
 		Date:         date,
+		DryRun:       dryRun,
+		CashRounding: r.URL.Query().Get("cash_rounding") == "true",
+		Unit:         unit,
 	}
 	
 	ctx := r.Context()
 	result, err := h.service.ConvertCurrency(ctx, request)
+	if !dryRun {
+		h.observeUnsupportedCurrencies(err, from, to)
+	}
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 	
-	simplifiedResult := map[string]float64{
-		"amount": result.ToAmount,
+	h.sendSuccessResponse(w, r, newConversionResponse(result))
+}
+
+type conversionMatrixRequest struct {
+	From    string    `json:"from"`
+	Amounts []float64 `json:"amounts"`
+	Targets []string  `json:"targets"`
+	Date    string    `json:"date"`
+}
+
+func (h *Handler) ConvertMatrixHandler(w http.ResponseWriter, r *http.Request) {
+	h.metrics.ConversionRequestsTotal.Inc()
+
+	var body conversionMatrixRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if body.From == "" || len(body.Targets) == 0 {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required fields: from and targets")
+		return
+	}
+
+	date, err := parseDate(body.Date)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid date format, use YYYY-MM-DD")
+		return
+	}
+
+	request := model.ConversionMatrixRequest{
+		FromCurrency: model.Currency(body.From),
+		Amounts:      body.Amounts,
+		Targets:      make([]model.Currency, 0, len(body.Targets)),
+		Date:         date,
+	}
+	for _, target := range body.Targets {
+		request.Targets = append(request.Targets, model.Currency(target))
+	}
+
+	ctx := r.Context()
+	matrix, err := h.service.ConvertMatrix(ctx, request)
+	h.observeUnsupportedCurrencies(err, append([]model.Currency{request.FromCurrency}, request.Targets...)...)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
 	}
-	h.sendSuccessResponse(w, simplifiedResult)
+
+	h.sendSuccessResponse(w, r, matrix)
+}
+
+func (h *Handler) GetConversionHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing conversion id")
+		return
+	}
+
+	ctx := r.Context()
+	result, err := h.service.GetConversion(ctx, id)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, newConversionResponse(result))
+}
+
+// conversionResponse keeps the original top-level "amount" field next to the full receipt
+type conversionResponse struct {
+	Amount float64 `json:"amount"`
+	*model.ConversionResult
+}
+
+func newConversionResponse(result *model.ConversionResult) conversionResponse {
+	return conversionResponse{
+		Amount:           result.ToAmount,
+		ConversionResult: result,
+	}
+}
+
+func (h *Handler) ListPairsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	pairs, err := h.service.ListPairs(ctx)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, pairs)
 }
 
 func (h *Handler) GetHistoricalRateHandler(w http.ResponseWriter, r *http.Request) {
@@ -135,12 +259,13 @@ func (h *Handler) GetHistoricalRateHandler(w http.ResponseWriter, r *http.Reques
 	
 	ctx := r.Context()
 	rate, err := h.service.GetHistoricalRate(ctx, from, to, date)
+	h.observeUnsupportedCurrencies(err, from, to)
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 	
-	h.sendSuccessResponse(w, rate)
+	h.sendHistoricalResponse(w, r, rate)
 }
 
 func (h *Handler) GetHistoricalRatesHandler(w http.ResponseWriter, r *http.Request) {
@@ -168,31 +293,126 @@ func (h *Handler) GetHistoricalRatesHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	
+	fill, err := model.ParseFillMode(r.URL.Query().Get("fill"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid fill parameter, use interpolate, previous or none")
+		return
+	}
+
+	strict := false
+	if value := r.URL.Query().Get("strict"); value != "" {
+		strict, err = strconv.ParseBool(value)
+		if err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid strict parameter, use true or false")
+			return
+		}
+	}
+	
 	request := model.HistoricalRateRequest{
 		BaseCurrency:   from,
 		TargetCurrency: to,
 		StartDate:      startDate,
 		EndDate:        endDate,
+		Fill:           fill,
+		Strict:         strict,
 	}
 	
 	ctx := r.Context()
 	rates, err := h.service.GetHistoricalRates(ctx, request)
+	h.observeUnsupportedCurrencies(err, from, to)
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 	
-	h.sendSuccessResponse(w, rates)
+	h.sendHistoricalResponse(w, r, rates)
 }
 
-func (h *Handler) sendSuccessResponse(w http.ResponseWriter, data interface{}) {
-	response := Response{
-		Success: true,
-		Data:    data,
+// observeUnsupportedCurrencies counts requests naming currencies outside the
+// supported list, so lenient matching can be watched before a currency is added
+func (h *Handler) observeUnsupportedCurrencies(err error, currencies ...model.Currency) {
+	outcome := "served"
+	switch {
+	case err == nil:
+	case errors.Is(err, service.ErrInvalidCurrency), errors.Is(err, service.ErrPairNotAvailable):
+		outcome = "rejected"
+	case errors.Is(err, service.ErrRateNotFound):
+		outcome = "not_found"
+	default:
+		outcome = "error"
+	}
+
+	for _, currency := range currencies {
+		if currency.IsSupported() || currency.IsCommodity() || currency.IsCrypto() {
+			continue
+		}
+		label := currency.String()
+		if !currency.IsValidCode() {
+			label = "invalid"
+		}
+		h.metrics.UnsupportedCurrencyRequests.WithLabelValues(label, outcome).Inc()
+	}
+}
+
+func (h *Handler) sendSuccessResponse(w http.ResponseWriter, r *http.Request, data interface{}) {
+	h.writeSuccessResponse(w, r, data, nil)
+}
+
+// sendHistoricalResponse sends data built from the provider's historical
+// rates, crediting the provider when its license requires it
+func (h *Handler) sendHistoricalResponse(w http.ResponseWriter, r *http.Request, data interface{}) {
+	attribution := h.service.HistoricalAttribution(r.Context())
+	if attribution != nil && attribution.TermsURL != "" {
+		w.Header().Add("Link", "<"+attribution.TermsURL+`>; rel="license"`)
+	}
+	h.writeSuccessResponse(w, r, data, attribution)
+}
+
+// partialResult is implemented by results of ranges and batches, which may
+// leave failed items out and list them instead
+type partialResult interface {
+	Partial() bool
+}
+
+// writeSuccessResponse answers 200, or 207 Multi-Status for a partial result
+func (h *Handler) writeSuccessResponse(w http.ResponseWriter, r *http.Request, data interface{}, attribution *model.Attribution) {
+	status := http.StatusOK
+	if partial, ok := data.(partialResult); ok && partial.Partial() {
+		status = http.StatusMultiStatus
+	}
+
+	data, err := reformatTimestamps(data, h.responseTimestampFormat(r))
+	if err != nil {
+		h.log.Error("Failed to reformat timestamps", "error", err)
+		h.sendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	data, err = h.applyTransforms(r, data)
+	if err != nil {
+		h.log.Error("Failed to transform response", "error", err, "path", r.URL.Path)
+		h.sendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	meta := h.responseMeta(r)
+	setFreshnessHeaders(w, meta)
+	if !includesMeta(r) {
+		meta = nil
+	}
+
+	var response interface{} = Response{
+		Success:     true,
+		Data:        data,
+		Attribution: attribution,
+		Meta:        meta,
+	}
+	if !wantsEnvelope(r) {
+		response = data
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.log.Error("Failed to encode response", "error", err)
@@ -200,9 +420,17 @@ func (h *Handler) sendSuccessResponse(w http.ResponseWriter, data interface{}) {
 }
 
 func (h *Handler) sendErrorResponse(w http.ResponseWriter, statusCode int, message string) {
+	h.sendCodedErrorResponse(w, statusCode, message, "", nil)
+}
+
+// sendCodedErrorResponse answers with message and code, and data when the
+// error comes with some, which is left out when nil
+func (h *Handler) sendCodedErrorResponse(w http.ResponseWriter, statusCode int, message, code string, data interface{}) {
 	response := Response{
 		Success: false,
-		Error:   message,
+		Data:    data,
+		Error:   i18n.Message(responseLocale(w), message),
+		Code:    code,
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
@@ -213,9 +441,10 @@ func (h *Handler) sendErrorResponse(w http.ResponseWriter, statusCode int, messa
 	}
 }
 
-func (h *Handler) handleServiceError(w http.ResponseWriter, err error) {
-	statusCode := http.StatusInternalServerError
-	errorMessage := "internal server error"
+// serviceErrorStatus maps a service error to an HTTP status and a message safe to show clients.
+// Errors without a case of their own are mapped by their kind.
+func serviceErrorStatus(err error) (int, string) {
+	statusCode, errorMessage := kindStatus(xerrors.KindOf(err))
 	
 	switch {
 	case errors.Is(err, service.ErrInvalidCurrency):
@@ -227,17 +456,124 @@ func (h *Handler) handleServiceError(w http.ResponseWriter, err error) {
 	case errors.Is(err, service.ErrInvalidDateRange):
 		statusCode = http.StatusBadRequest
 		errorMessage = "invalid date range"
+	case errors.Is(err, service.ErrRangeTooLong):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrStaleRate):
+		statusCode = http.StatusServiceUnavailable
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrBudgetExhausted):
+		statusCode = http.StatusTooManyRequests
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrPairNotAvailable):
+		statusCode = http.StatusNotFound
+		errorMessage = "currency pair not available"
 	case errors.Is(err, service.ErrRateNotFound):
 		statusCode = http.StatusNotFound
 		errorMessage = "exchange rate not found"
+	case errors.Is(err, service.ErrIncompleteRange):
+		statusCode = http.StatusNotFound
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrNoSecondaryProvider):
+		statusCode = http.StatusNotImplemented
+		errorMessage = "no secondary provider configured"
+	case errors.Is(err, service.ErrNoProviderChain):
+		statusCode = http.StatusNotImplemented
+		errorMessage = "no provider chain configured"
+	case errors.Is(err, service.ErrSparklinesDisabled):
+		statusCode = http.StatusNotImplemented
+		errorMessage = "sparklines are disabled"
+	case errors.Is(err, service.ErrInvalidInterval):
+		statusCode = http.StatusBadRequest
+		errorMessage = "invalid candle interval"
+	case errors.Is(err, service.ErrInvalidSchedule):
+		statusCode = http.StatusBadRequest
+		errorMessage = "invalid conversion schedule"
+	case errors.Is(err, service.ErrInvalidBasket):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrBasketNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = "basket not found"
+	case errors.Is(err, service.ErrBasketsDisabled):
+		statusCode = http.StatusNotImplemented
+		errorMessage = "baskets are disabled"
+	case errors.Is(err, service.ErrInvalidUnit):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrInflationDisabled):
+		statusCode = http.StatusNotImplemented
+		errorMessage = "inflation adjustment is disabled"
+	case errors.Is(err, service.ErrInvalidInflation):
+		statusCode = http.StatusBadRequest
+		errorMessage = "invalid inflation method"
+	case errors.Is(err, service.ErrCPINotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrUnknownProvider):
+		statusCode = http.StatusNotFound
+		errorMessage = "unknown provider"
+	case errors.Is(err, xerrors.RateLimited):
+		statusCode = http.StatusTooManyRequests
+		errorMessage = "provider rate limit reached"
 	case errors.Is(err, service.ErrExternalAPIFailure):
 		statusCode = http.StatusServiceUnavailable
 		errorMessage = "external API failure"
 	case errors.Is(err, service.ErrInvalidAmount):
 		statusCode = http.StatusBadRequest
 		errorMessage = "invalid amount"
+	case errors.Is(err, service.ErrConversionNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = "conversion not found"
+	case errors.Is(err, service.ErrInvalidAnnotation):
+		statusCode = http.StatusBadRequest
+		errorMessage = err.Error()
+	case errors.Is(err, service.ErrAnnotationNotFound):
+		statusCode = http.StatusNotFound
+		errorMessage = "annotation not found"
+	}
+	
+	return statusCode, errorMessage
+}
+
+// kindStatus maps an error kind to an HTTP status and a generic message
+func kindStatus(kind xerrors.Kind) (int, string) {
+	switch kind {
+	case xerrors.Validation:
+		return http.StatusBadRequest, "invalid request"
+	case xerrors.NotFound:
+		return http.StatusNotFound, "not found"
+	case xerrors.Upstream:
+		return http.StatusBadGateway, "external API failure"
+	case xerrors.RateLimited:
+		return http.StatusTooManyRequests, "provider rate limit reached"
+	default:
+		return http.StatusInternalServerError, "internal server error"
+	}
+}
+
+// handleServiceError answers with the status, message and code of err. When a
+// provider asked to wait before calling again, so are clients, a range too
+// long is answered with the longest allowed, and a rate older than the max
+// age asked for comes along, marked stale.
+func (h *Handler) handleServiceError(w http.ResponseWriter, err error) {
+	statusCode, errorMessage := serviceErrorStatus(err)
+	
+	if e, ok := xerrors.Find(err); ok && e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(e.RetryAfter.Seconds())+1))
+	}
+	var rangeLimit *service.RangeLimitError
+	if errors.As(err, &rangeLimit) {
+		w.Header().Set("X-Max-Range-Days", strconv.Itoa(rangeLimit.MaxDays))
+	}
+	var data interface{}
+	var stale *service.StaleRateError
+	if errors.As(err, &stale) {
+		data = stale.Rate
+		w.Header().Set(cacheHeader, "STALE")
+		w.Header().Set(dataAgeHeader, strconv.Itoa(int(stale.Age.Seconds())))
 	}
 	
-	h.log.Error("Service error", "error", err, "status_code", statusCode)
-	h.sendErrorResponse(w, statusCode, errorMessage)
+	h.log.Error("Service error", "error", err, "status_code", statusCode, "kind", xerrors.KindOf(err))
+	h.sendCodedErrorResponse(w, statusCode, errorMessage, xerrors.CodeOf(err), data)
 }
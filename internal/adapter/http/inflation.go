@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func (h *Handler) InflationHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := model.Currency(query.Get("from"))
+	to := model.Currency(query.Get("to"))
+	amountStr := query.Get("amount")
+	dateStr := query.Get("date")
+
+	if from == "" || to == "" || amountStr == "" || dateStr == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameters: from, to, amount, and date")
+		return
+	}
+
+	amount, err := parseAmount(amountStr, from)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid amount parameter")
+		return
+	}
+
+	date, err := parseDate(dateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid date format, use YYYY-MM-DD")
+		return
+	}
+
+	method := model.InflationMethod(query.Get("method"))
+	if method == "" {
+		method = model.InflateThenConvert
+	}
+	if !method.Valid() {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid method parameter, use inflate_then_convert or convert_then_inflate")
+		return
+	}
+
+	result, err := h.service.InflationAdjustedConversion(r.Context(), model.InflationRequest{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Amount:       amount,
+		Date:         date,
+		Method:       method,
+	})
+	h.observeUnsupportedCurrencies(err, from, to)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendHistoricalResponse(w, r, result)
+}
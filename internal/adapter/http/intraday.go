@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+const (
+	defaultOHLCInterval = "1h"
+	defaultOHLCRange    = 24 * time.Hour
+)
+
+// parseInstant accepts RFC 3339 timestamps, YYYY-MM-DD dates and unix seconds
+func parseInstant(s string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func (h *Handler) GetOHLCHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	pair, err := model.ParseCurrencyPair(query.Get("pair"))
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing or invalid pair parameter, use e.g. USD-INR")
+		return
+	}
+
+	to := time.Now().UTC()
+	if s := query.Get("to"); s != "" {
+		if to, err = parseInstant(s); err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid to parameter, use RFC 3339, YYYY-MM-DD or unix seconds")
+			return
+		}
+	}
+
+	from := to.Add(-defaultOHLCRange)
+	if s := query.Get("from"); s != "" {
+		if from, err = parseInstant(s); err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid from parameter, use RFC 3339, YYYY-MM-DD or unix seconds")
+			return
+		}
+	}
+
+	interval := query.Get("interval")
+	if interval == "" {
+		interval = defaultOHLCInterval
+	}
+
+	series, err := h.service.GetOHLC(r.Context(), model.OHLCRequest{
+		Pair:     pair,
+		From:     from,
+		To:       to,
+		Interval: interval,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, series)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistoricalAttribution(t *testing.T) {
+	router := goldenRouter(t)
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
+
+	testCases := []struct {
+		name       string
+		target     string
+		attributed bool
+	}{
+		{name: "Historical rate", target: "/api/v1/historical?from=USD&to=INR&date=" + yesterday, attributed: true},
+		{name: "Historical range", target: "/api/v1/historical/range?from=USD&to=INR&start_date=" + yesterday + "&end_date=" + yesterday, attributed: true},
+		{name: "Latest rate", target: "/api/v1/rates?from=USD&to=INR"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", tc.target, nil))
+			if rec.Code != 200 {
+				t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var response Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			link := rec.Header().Get("Link")
+
+			if !tc.attributed {
+				if response.Attribution != nil || link != "" {
+					t.Errorf("Expected no attribution, got %+v and Link %q", response.Attribution, link)
+				}
+				return
+			}
+			if response.Attribution == nil || response.Attribution.Provider != "primary" || response.Attribution.Text != "Rates by Primary" {
+				t.Errorf("Expected the primary provider's attribution, got %+v", response.Attribution)
+			}
+			if link != `<https://primary.example/terms>; rel="license"` {
+				t.Errorf("Expected a license link, got %q", link)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/i18n"
+)
+
+type currencyResponse struct {
+	Code   model.Currency     `json:"code"`
+	Name   string             `json:"name"`
+	Symbol string             `json:"symbol,omitempty"`
+	Kind   string             `json:"kind"`
+	Unit   model.QuantityUnit `json:"unit,omitempty"`
+}
+
+// languageMiddleware negotiates the response locale from Accept-Language and
+// records it in Content-Language, which sendErrorResponse reads back
+func languageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Language", i18n.Negotiate(r.Header.Get("Accept-Language")))
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// responseLocale returns the locale negotiated by languageMiddleware
+func responseLocale(w http.ResponseWriter) string {
+	if locale := w.Header().Get("Content-Language"); locale != "" {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+// CurrenciesHandler lists every accepted code with its name in the negotiated locale
+func (h *Handler) CurrenciesHandler(w http.ResponseWriter, r *http.Request) {
+	locale := responseLocale(w)
+
+	currencies := make([]currencyResponse, 0, len(model.SupportedCurrencies)+len(model.CompositeUnits)+len(model.Commodities))
+	for _, code := range model.SupportedCurrencies {
+		currencies = append(currencies, currencyResponse{
+			Code:   code,
+			Name:   i18n.CurrencyName(locale, code.String()),
+			Symbol: code.Symbol(),
+			Kind:   "currency",
+		})
+	}
+
+	var extra []currencyResponse
+	for code := range model.CompositeUnits {
+		extra = append(extra, currencyResponse{
+			Code: code,
+			Name: i18n.CurrencyName(locale, code.String()),
+			Kind: "composite",
+		})
+	}
+	for code, commodity := range model.Commodities {
+		extra = append(extra, currencyResponse{
+			Code: code,
+			Name: i18n.CurrencyName(locale, code.String()),
+			Kind: "commodity",
+			Unit: commodity.Unit,
+		})
+	}
+	sort.Slice(extra, func(i, j int) bool {
+		if extra[i].Kind != extra[j].Kind {
+			return extra[i].Kind > extra[j].Kind
+		}
+		return extra[i].Code < extra[j].Code
+	})
+
+	h.sendSuccessResponse(w, r, append(currencies, extra...))
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalizedResponses(t *testing.T) {
+	router := goldenRouter(t)
+
+	tests := []struct {
+		name         string
+		target       string
+		language     string
+		wantLanguage string
+		wantError    string
+	}{
+		{"default english", "/api/v1/rates", "", "en", "missing required parameters: from and to"},
+		{"german error", "/api/v1/rates", "de-DE, en;q=0.5", "de", "fehlende Pflichtparameter: from und to"},
+		{"unsupported language falls back", "/api/v1/rates", "pt-BR", "en", "missing required parameters: from and to"},
+		{"service error translated", "/api/v1/rates?from=USD&to=ABC", "fr", "fr", "devise invalide"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.language != "" {
+				req.Header.Set("Accept-Language", tt.language)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Language"); got != tt.wantLanguage {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantLanguage)
+			}
+
+			var response Response
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Response is not JSON: %v", err)
+			}
+			if response.Error != tt.wantError {
+				t.Errorf("Error = %q, want %q", response.Error, tt.wantError)
+			}
+		})
+	}
+
+	t.Run("currency names", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/currencies", nil)
+		req.Header.Set("Accept-Language", "ja")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var response struct {
+			Data []currencyResponse `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Response is not JSON: %v", err)
+		}
+		if len(response.Data) == 0 || response.Data[0].Code != "USD" || response.Data[0].Name != "米ドル" {
+			t.Errorf("Unexpected currencies: %+v", response.Data)
+		}
+	})
+}
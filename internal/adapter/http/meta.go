@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// Headers telling how fresh the rates of a response are, so caches in front
+// of the service and support can tell without parsing bodies
+const (
+	cacheHeader   = "X-Cache"
+	dataAgeHeader = "X-Data-Age"
+)
+
+type includeMetaKey struct{}
+
+// metaMiddleware collects how every request is served, for the freshness
+// headers and, with ?include_meta=true, the meta block. It rejects
+// include_meta values that are not booleans before any handler runs.
+func metaMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := model.WithResponseMeta(r.Context(), time.Now())
+
+		if value := r.URL.Query().Get("include_meta"); value != "" {
+			include, err := strconv.ParseBool(value)
+			if err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(Response{
+					Success: false,
+					Error:   "invalid include_meta parameter, use true or false",
+				})
+				return
+			}
+			ctx = context.WithValue(ctx, includeMetaKey{}, include)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// includesMeta reports whether the response to r carries the meta block
+func includesMeta(r *http.Request) bool {
+	include, _ := r.Context().Value(includeMetaKey{}).(bool)
+	return include
+}
+
+// MarkStaleAfter reports rates older than staleAfter as stale, in the meta
+// block and the X-Cache header. 0 never does.
+func (h *Handler) MarkStaleAfter(staleAfter time.Duration) {
+	h.staleAfter = staleAfter
+}
+
+// responseMeta returns what was collected of how r was served, or nil for
+// requests that did not go through metaMiddleware
+func (h *Handler) responseMeta(r *http.Request) *model.ResponseMeta {
+	collector := model.ResponseMetaFromContext(r.Context())
+	if collector == nil {
+		return nil
+	}
+	meta := collector.Meta(time.Now())
+	if h.staleAfter > 0 && meta.DataAgeSeconds > h.staleAfter.Seconds() {
+		meta.Cache = model.CacheStale
+	}
+	return &meta
+}
+
+// setFreshnessHeaders sets X-Cache and X-Data-Age on responses serving rates
+func setFreshnessHeaders(w http.ResponseWriter, meta *model.ResponseMeta) {
+	if meta == nil || meta.Cache == "" {
+		return
+	}
+	w.Header().Set(cacheHeader, strings.ToUpper(meta.Cache))
+	// Only rates with a timestamp have a snapshot
+	if meta.SnapshotID != "" {
+		w.Header().Set(dataAgeHeader, strconv.Itoa(int(meta.DataAgeSeconds)))
+	}
+}
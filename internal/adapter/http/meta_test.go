@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestIncludeMeta(t *testing.T) {
+	router := goldenRouter(t)
+
+	get := func(target string) (*httptest.ResponseRecorder, *model.ResponseMeta) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+		var body struct {
+			Meta *model.ResponseMeta `json:"meta"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Response is not JSON: %v", err)
+		}
+		return rec, body.Meta
+	}
+
+	if _, meta := get("/api/v1/rates?from=USD&to=EUR"); meta != nil {
+		t.Errorf("Meta without include_meta = %+v, want none", meta)
+	}
+
+	rec, first := get("/api/v1/rates?from=USD&to=EUR&include_meta=true")
+	if rec.Code != http.StatusOK || first == nil {
+		t.Fatalf("Status = %d, meta = %+v: %s", rec.Code, first, rec.Body.String())
+	}
+	if first.Provider != "primary" {
+		t.Errorf("Provider = %q, want primary", first.Provider)
+	}
+	if first.SnapshotID == "" {
+		t.Error("SnapshotID is empty")
+	}
+
+	// The first plain request cached the rate
+	if first.Cache != model.CacheHit {
+		t.Errorf("Cache = %q, want %q", first.Cache, model.CacheHit)
+	}
+	_, second := get("/api/v1/rates?from=USD&to=GBP&include_meta=true")
+	if second == nil || second.Cache != model.CacheMiss {
+		t.Errorf("Meta of an uncached pair = %+v, want cache %q", second, model.CacheMiss)
+	}
+
+	if rec, _ := get("/api/v1/rates?from=USD&to=EUR&include_meta=maybe"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Invalid include_meta status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFreshnessHeaders(t *testing.T) {
+	router := goldenRouter(t)
+
+	get := func(handler http.Handler, target string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Status = %d: %s", rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	first := get(router, "/api/v1/convert?from=USD&to=JPY&amount=10")
+	if got := first.Header().Get(cacheHeader); got != "MISS" {
+		t.Errorf("%s of an uncached pair = %q, want MISS", cacheHeader, got)
+	}
+	if got := first.Header().Get(dataAgeHeader); got != "0" {
+		t.Errorf("%s of a fresh rate = %q, want 0", dataAgeHeader, got)
+	}
+	if got := get(router, "/api/v1/rates?from=USD&to=JPY").Header().Get(cacheHeader); got != "HIT" {
+		t.Errorf("%s of a cached pair = %q, want HIT", cacheHeader, got)
+	}
+	if got := get(router, "/api/v1/currencies").Header().Get(cacheHeader); got != "" {
+		t.Errorf("%s without rates = %q, want none", cacheHeader, got)
+	}
+
+	stale := newGoldenRouter(t)
+	stale.handler.MarkStaleAfter(time.Nanosecond)
+	if got := get(stale.SetupRoutes(), "/api/v1/rates?from=USD&to=JPY").Header().Get(cacheHeader); got != "STALE" {
+		t.Errorf("%s of a rate past the staleness = %q, want STALE", cacheHeader, got)
+	}
+}
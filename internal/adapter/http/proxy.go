@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+// maxProxyEntries bounds the proxy cache; the oldest entry is evicted first
+const maxProxyEntries = 1000
+
+// RawProvider passes requests through to the primary provider with the
+// service's API key
+type RawProvider interface {
+	FetchRaw(ctx context.Context, endpoint string, query url.Values) (int, []byte, error)
+}
+
+// proxyParams lists the query parameters forwarded per provider endpoint.
+// Anything else, the client's access_key in particular, is dropped.
+var proxyParams = map[string][]string{
+	"live":       {"base", "source", "currencies"},
+	"historical": {"date", "base", "source", "currencies"},
+}
+
+type proxyEntry struct {
+	status  int
+	body    []byte
+	expires time.Time
+}
+
+// providerProxy serves provider-shaped responses for legacy clients, caching
+// them per endpoint and normalized query
+type providerProxy struct {
+	provider RawProvider
+	cfg      config.ProxyConfig
+	limiter  *rateLimiter
+	metrics  *metrics.Metrics
+	log      *logger.Logger
+
+	mutex   sync.Mutex
+	entries map[string]*proxyEntry
+}
+
+func newProviderProxy(provider RawProvider, cfg config.ProxyConfig, metrics *metrics.Metrics, log *logger.Logger) *providerProxy {
+	return &providerProxy{
+		provider: provider,
+		cfg:      cfg,
+		limiter:  newRateLimiter(cfg.RateLimit, time.Minute),
+		metrics:  metrics,
+		log:      log,
+		entries:  make(map[string]*proxyEntry),
+	}
+}
+
+// handler returns the handler for one provider endpoint, e.g. "live"
+func (p *providerProxy) handler(endpoint string) http.HandlerFunc {
+	ttl := p.cfg.LiveTTL
+	if endpoint == "historical" {
+		ttl = p.cfg.HistoricalTTL
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if allowed, retryAfter := p.limiter.allow(clientKey(r)); !allowed {
+			p.metrics.ProxyRequests.WithLabelValues(endpoint, "limited").Inc()
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			writeProviderError(w, http.StatusTooManyRequests, 106, "rate_limit_reached", "Rate limit reached, please retry later.")
+			return
+		}
+
+		query := url.Values{}
+		for _, param := range proxyParams[endpoint] {
+			if value := r.URL.Query().Get(param); value != "" {
+				query.Set(param, value)
+			}
+		}
+		key := endpoint + "?" + query.Encode()
+
+		if entry, found := p.get(key); found {
+			p.metrics.ProxyRequests.WithLabelValues(endpoint, "hit").Inc()
+			writeProxyEntry(w, entry, "HIT")
+			return
+		}
+
+		status, body, err := p.provider.FetchRaw(r.Context(), endpoint, query)
+		if err != nil {
+			p.log.Error("Proxy request to provider failed", "endpoint", endpoint, "error", err)
+			p.metrics.ProxyRequests.WithLabelValues(endpoint, "error").Inc()
+			writeProviderError(w, http.StatusBadGateway, 503, "provider_unavailable", "The upstream provider could not be reached.")
+			return
+		}
+
+		entry := &proxyEntry{status: status, body: body, expires: time.Now().Add(ttl)}
+		// Only successful answers are cached; provider errors may be transient
+		if status == http.StatusOK && successful(body) {
+			p.set(key, entry)
+		}
+		p.metrics.ProxyRequests.WithLabelValues(endpoint, "miss").Inc()
+		writeProxyEntry(w, entry, "MISS")
+	}
+}
+
+func (p *providerProxy) get(key string) (*proxyEntry, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	entry, exists := p.entries[key]
+	if !exists || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (p *providerProxy) set(key string, entry *proxyEntry) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, exists := p.entries[key]; !exists && len(p.entries) >= maxProxyEntries {
+		keys := make([]string, 0, len(p.entries))
+		for k := range p.entries {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return p.entries[keys[i]].expires.Before(p.entries[keys[j]].expires)
+		})
+		delete(p.entries, keys[0])
+	}
+	p.entries[key] = entry
+}
+
+// successful reports whether a provider body has "success": true
+func successful(body []byte) bool {
+	var envelope struct {
+		Success bool `json:"success"`
+	}
+	return json.Unmarshal(body, &envelope) == nil && envelope.Success
+}
+
+func writeProxyEntry(w http.ResponseWriter, entry *proxyEntry, cache string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Cache", cache)
+	if cache == "HIT" {
+		w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(int(time.Until(entry.expires).Seconds())))
+	}
+	w.WriteHeader(entry.status)
+	w.Write(entry.body)
+}
+
+// writeProviderError answers in the provider's error format so legacy clients
+// keep parsing it
+func writeProviderError(w http.ResponseWriter, status, code int, errorType, info string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error": map[string]interface{}{
+			"code": code,
+			"type": errorType,
+			"info": info,
+		},
+	})
+}
+
+// clientKey identifies a client for rate limiting: its API key if it sent
+// one, otherwise its address
+func clientKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return "key:" + key
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
+
+// rateLimiter is a fixed-window limiter allowing limit requests per client
+// and window. A limit of 0 disables it.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mutex  sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		window: window,
+		counts: make(map[string]int),
+	}
+}
+
+// allow counts a request from client, or returns false and the time until
+// the window resets when the client is over the limit
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	if l.limit <= 0 {
+		return true, 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// Starting a new window also forgets clients that went quiet
+	now := time.Now()
+	if now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = make(map[string]int)
+	}
+
+	if l.counts[client] >= l.limit {
+		return false, l.window - now.Sub(l.start)
+	}
+	l.counts[client]++
+	return true, 0
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type fakeRawProvider struct {
+	calls   int
+	queries []url.Values
+}
+
+func (f *fakeRawProvider) FetchRaw(ctx context.Context, endpoint string, query url.Values) (int, []byte, error) {
+	f.calls++
+	f.queries = append(f.queries, query)
+	return http.StatusOK, []byte(`{"success":true,"source":"USD","quotes":{"USDINR":83.12}}`), nil
+}
+
+func TestProviderProxy(t *testing.T) {
+	provider := &fakeRawProvider{}
+	appMetrics := &metrics.Metrics{
+		ProxyRequests: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "proxy_requests_total"}, []string{"endpoint", "result"}),
+	}
+	proxy := newProviderProxy(provider, config.ProxyConfig{LiveTTL: time.Minute, RateLimit: 3}, appMetrics, logger.NewLogger("error"))
+	handler := proxy.handler("live")
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	first := get("/proxy/live?base=USD&currencies=INR&access_key=client")
+	if first.Code != http.StatusOK || first.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("Expected a cache miss, got %d %q", first.Code, first.Header().Get("X-Cache"))
+	}
+	if provider.queries[0].Has("access_key") {
+		t.Errorf("The client's access_key must not be forwarded: %v", provider.queries[0])
+	}
+
+	// The same query in a different order is answered from the cache
+	second := get("/proxy/live?currencies=INR&base=USD")
+	if second.Header().Get("X-Cache") != "HIT" || provider.calls != 1 {
+		t.Errorf("Expected a cache hit, got %q after %d provider calls", second.Header().Get("X-Cache"), provider.calls)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("Cached body differs: %s", second.Body.String())
+	}
+
+	get("/proxy/live?base=EUR")
+	limited := get("/proxy/live?base=USD")
+	if limited.Code != http.StatusTooManyRequests || limited.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected the fourth request to be rate limited, got %d", limited.Code)
+	}
+}
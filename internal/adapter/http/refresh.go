@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/refresh"
+)
+
+// RefreshSchedule lets operators change how often rates are refreshed
+type RefreshSchedule interface {
+	Current() model.RefreshSchedule
+	SetInterval(interval time.Duration) (model.RefreshSchedule, error)
+}
+
+type refreshIntervalRequest struct {
+	Interval string `json:"interval"`
+}
+
+func (h *Handler) getRefreshIntervalHandler(schedule RefreshSchedule) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.sendSuccessResponse(w, r, schedule.Current())
+	}
+}
+
+func (h *Handler) setRefreshIntervalHandler(schedule RefreshSchedule) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body refreshIntervalRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+
+		interval, err := time.ParseDuration(body.Interval)
+		if err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid interval, use a duration such as 15m")
+			return
+		}
+
+		updated, err := schedule.SetInterval(interval)
+		if err != nil {
+			if errors.Is(err, refresh.ErrInvalidInterval) {
+				h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			h.handleServiceError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, updated)
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// Headers carrying per-request options; the query parameters of the same
+// meaning take precedence
+const (
+	roundingHeader = "X-Rounding"
+	providerHeader = "X-Provider"
+	rateTypeHeader = "X-Rate-Type"
+	maxAgeHeader   = "X-Max-Age"
+)
+
+// requestOptionsMiddleware reads the rounding, provider, rate_type and
+// max_age options of a request into its context, where the service picks them
+// up, and rejects invalid ones before any handler runs
+func requestOptionsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", roundingHeader+", "+providerHeader+", "+rateTypeHeader+", "+maxAgeHeader)
+
+		query := r.URL.Query()
+		option := func(param, header string) string {
+			if value := query.Get(param); value != "" {
+				return value
+			}
+			return r.Header.Get(header)
+		}
+		options := model.RequestOptions{
+			Rounding: option("rounding", roundingHeader),
+			Provider: option("provider", providerHeader),
+			RateType: option("rate_type", rateTypeHeader),
+		}
+		maxAge, err := parseMaxAge(option("max_age", maxAgeHeader))
+		if err == nil {
+			options.MaxAge = maxAge
+			err = options.Validate()
+		}
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(Response{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if options != (model.RequestOptions{}) {
+			r = r.WithContext(model.WithRequestOptions(r.Context(), options))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseMaxAge reads a max age given as a duration such as 60s, or as whole
+// seconds. Empty is no max age.
+func parseMaxAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	maxAge, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid max age %q, use a duration such as 60s", value)
+		}
+		maxAge = time.Duration(seconds) * time.Second
+	}
+	if maxAge <= 0 {
+		return 0, fmt.Errorf("max age must be positive, got %q", value)
+	}
+	return maxAge, nil
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestRequestOptionsMiddleware(t *testing.T) {
+	var got model.RequestOptions
+	handler := requestOptionsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = model.RequestOptionsFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name       string
+		target     string
+		headers    map[string]string
+		wantStatus int
+		want       model.RequestOptions
+	}{
+		{"no options", "/api/v1/rates", nil, http.StatusOK, model.RequestOptions{}},
+		{"query", "/api/v1/rates?rounding=cash&rate_type=mid", nil, http.StatusOK, model.RequestOptions{Rounding: "cash", RateType: "mid"}},
+		{"headers", "/api/v1/rates", map[string]string{providerHeader: "secondary", rateTypeHeader: "mid"}, http.StatusOK, model.RequestOptions{Provider: "secondary", RateType: "mid"}},
+		{"query wins over header", "/api/v1/rates?rounding=none", map[string]string{roundingHeader: "cash"}, http.StatusOK, model.RequestOptions{Rounding: "none"}},
+		{"invalid rounding", "/api/v1/rates?rounding=up", nil, http.StatusBadRequest, model.RequestOptions{}},
+		{"invalid rate type", "/api/v1/rates", map[string]string{rateTypeHeader: "bid"}, http.StatusBadRequest, model.RequestOptions{}},
+		{"max age", "/api/v1/convert?max_age=90s", nil, http.StatusOK, model.RequestOptions{MaxAge: 90 * time.Second}},
+		{"max age in seconds", "/api/v1/rates", map[string]string{maxAgeHeader: "60"}, http.StatusOK, model.RequestOptions{MaxAge: time.Minute}},
+		{"invalid max age", "/api/v1/rates?max_age=soon", nil, http.StatusBadRequest, model.RequestOptions{}},
+		{"zero max age", "/api/v1/rates?max_age=0s", nil, http.StatusBadRequest, model.RequestOptions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = model.RequestOptions{}
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got != tt.want {
+				t.Errorf("Options = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
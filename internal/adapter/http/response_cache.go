@@ -0,0 +1,212 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+)
+
+// maxResponseEntries bounds the response cache; when full, expired entries
+// are dropped first and then the ones closest to expiring
+const maxResponseEntries = 10000
+
+type responseEntry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// responseCache answers repeated GET API requests with the response recorded
+// for the first one, keyed by path, query and everything else the response
+// varies by. It sits behind authentication, so a hit is never served to a
+// client that would have been turned away.
+type responseCache struct {
+	cfg     config.ResponseCacheConfig
+	metrics *metrics.Metrics
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*responseEntry
+}
+
+func newResponseCache(cfg config.ResponseCacheConfig, metrics *metrics.Metrics) *responseCache {
+	return &responseCache{
+		cfg:     cfg,
+		metrics: metrics,
+		now:     time.Now,
+		entries: make(map[string]*responseEntry),
+	}
+}
+
+// CacheResponses answers identical GET API requests within cfg.TTL from a
+// cache, without calling the handler again. A TTL of 0 leaves it disabled.
+func (r *Router) CacheResponses(cfg config.ResponseCacheConfig) {
+	if cfg.TTL <= 0 {
+		return
+	}
+	r.responses = newResponseCache(cfg, r.metrics)
+}
+
+// cache wraps the handler of the route registered with pattern when its
+// responses are to be cached
+func (r *Router) cache(pattern string, handler http.Handler) http.Handler {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		method, path = "", pattern
+	}
+	if r.responses == nil || !r.responses.enabled(method, path) {
+		return handler
+	}
+	return r.responses.middleware(path, handler)
+}
+
+func (c *responseCache) enabled(method, path string) bool {
+	if method != "" && method != http.MethodGet {
+		return false
+	}
+	if slices.Contains(c.cfg.DisabledRoutes, path) {
+		return false
+	}
+	return len(c.cfg.Routes) == 0 || slices.Contains(c.cfg.Routes, path)
+}
+
+func (c *responseCache) middleware(path string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The meta block describes how one request was served
+		if r.Method != http.MethodGet || includesMeta(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := responseKey(w, r)
+		if entry, found := c.get(key); found {
+			c.metrics.ResponseCacheLookups.WithLabelValues(path, "hit").Inc()
+			for name, values := range entry.header {
+				w.Header()[name] = append([]string(nil), values...)
+			}
+			// Stale rates stay stale, and grow older while cached
+			if entry.header.Get(cacheHeader) != "STALE" {
+				w.Header().Set(cacheHeader, "HIT")
+			}
+			if age, err := strconv.Atoi(entry.header.Get(dataAgeHeader)); err == nil {
+				cached := c.now().Sub(entry.expires.Add(-c.cfg.TTL))
+				w.Header().Set(dataAgeHeader, strconv.Itoa(age+int(cached.Seconds())))
+			}
+			w.WriteHeader(entry.status)
+			w.Write(entry.body)
+			return
+		}
+		c.metrics.ResponseCacheLookups.WithLabelValues(path, "miss").Inc()
+
+		w.Header().Set(cacheHeader, "MISS")
+		rec := &recordingWriter{ResponseWriter: w, before: w.Header().Clone()}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.WriteHeader(http.StatusOK)
+		}
+
+		// Errors may be transient, and cookies belong to one client
+		if rec.status == http.StatusOK && rec.header.Get("Set-Cookie") == "" {
+			c.set(key, &responseEntry{
+				status:  rec.status,
+				header:  rec.header,
+				body:    rec.body.Bytes(),
+				expires: c.now().Add(c.cfg.TTL),
+			})
+		}
+	})
+}
+
+// responseKey identifies a request by what its response depends on. The
+// language is read from the response, where languageMiddleware negotiated it.
+func responseKey(w http.ResponseWriter, r *http.Request) string {
+	return strings.Join([]string{
+		r.URL.Path,
+		r.URL.Query().Encode(),
+		model.TenantFromContext(r.Context()),
+		model.RequestOptionsFromContext(r.Context()).String(),
+		w.Header().Get("Content-Language"),
+		r.Header.Get(envelopeHeader),
+	}, "|")
+}
+
+func (c *responseCache) get(key string) (*responseEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, found := c.entries[key]
+	if found && !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, found
+}
+
+func (c *responseCache) set(key string, entry *responseEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= maxResponseEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	// Entries share one TTL, so the one expiring first is also the oldest
+	for len(c.entries) >= maxResponseEntries {
+		oldest := ""
+		for k, e := range c.entries {
+			if oldest == "" || e.expires.Before(c.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+
+	c.entries[key] = entry
+}
+
+// recordingWriter passes a response through while keeping a copy of its
+// status, body and the headers the handler set, leaving out those set before
+// it ran, which the middleware chain sets again on every request
+type recordingWriter struct {
+	http.ResponseWriter
+	before http.Header
+
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if rw.status != 0 {
+		return
+	}
+	rw.status = code
+	rw.header = http.Header{}
+	for name, values := range rw.ResponseWriter.Header() {
+		if !slices.Equal(values, rw.before[name]) {
+			rw.header[name] = append([]string(nil), values...)
+		}
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
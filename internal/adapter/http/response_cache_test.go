@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestResponseCache(t *testing.T) {
+	lookups := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "http_response_cache_lookups_total"}, []string{"path", "result"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(lookups)
+
+	router := &Router{metrics: &metrics.Metrics{ResponseCacheLookups: lookups}}
+	router.CacheResponses(config.ResponseCacheConfig{TTL: 5 * time.Second})
+	now := time.Date(2025, 5, 15, 12, 0, 0, 0, time.UTC)
+	router.responses.now = func() time.Time { return now }
+
+	calls := 0
+	handler := router.cache("/api/v1/rates", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("from") == "XXX" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"call":` + strconv.Itoa(calls) + `}`))
+	}))
+
+	get := func(target, tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		if tenant != "" {
+			req = req.WithContext(model.WithTenant(context.Background(), tenant))
+		}
+		rec := httptest.NewRecorder()
+		// Set by the middleware chain on every request, so never stored
+		rec.Header().Add("Vary", "Accept-Language")
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/api/v1/rates?from=USD&to=INR", "")
+	if first.Header().Get("X-Cache") != "MISS" || first.Body.String() != `{"call":1}` {
+		t.Fatalf("Expected a miss answered by the handler, got %s %s", first.Header().Get("X-Cache"), first.Body.String())
+	}
+
+	second := get("/api/v1/rates?to=INR&from=USD", "")
+	if second.Header().Get("X-Cache") != "HIT" || second.Body.String() != `{"call":1}` || calls != 1 {
+		t.Errorf("Expected a hit with the first body regardless of parameter order, got %s %s", second.Header().Get("X-Cache"), second.Body.String())
+	}
+	if second.Header().Get("Content-Type") != "application/json" || len(second.Header().Values("Vary")) != 1 {
+		t.Errorf("Expected the handler's headers replayed once, got %v", second.Header())
+	}
+
+	if rec := get("/api/v1/rates?from=USD&to=INR", "tenant"); rec.Header().Get("X-Cache") != "MISS" {
+		t.Error("Expected another tenant not to share the cached response")
+	}
+	if rec := get("/api/v1/rates?from=USD&to=EUR", ""); rec.Header().Get("X-Cache") != "MISS" {
+		t.Error("Expected another query to miss")
+	}
+
+	get("/api/v1/rates?from=XXX&to=INR", "")
+	if rec := get("/api/v1/rates?from=XXX&to=INR", ""); rec.Code != http.StatusBadRequest || rec.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected errors not to be cached, got %d %s", rec.Code, rec.Header().Get("X-Cache"))
+	}
+
+	now = now.Add(5 * time.Second)
+	if rec := get("/api/v1/rates?from=USD&to=INR", ""); rec.Header().Get("X-Cache") != "MISS" {
+		t.Error("Expected the entry to expire after the TTL")
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counted := map[string]float64{}
+	for _, m := range families[0].GetMetric() {
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "result" {
+				counted[label.GetValue()] += m.GetCounter().GetValue()
+			}
+		}
+	}
+	if counted["hit"] != 1 || counted["miss"] != 6 {
+		t.Errorf("Expected 1 hit and 6 misses, got %v", counted)
+	}
+}
+
+func TestResponseCacheRoutes(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     config.ResponseCacheConfig
+		pattern string
+		cached  bool
+	}{
+		{name: "Every GET route by default", pattern: "GET /api/v1/pairs", cached: true},
+		{name: "Routes without a method", pattern: "/api/v1/rates", cached: true},
+		{name: "Other methods", pattern: "POST /api/v1/convert/matrix"},
+		{name: "Disabled route", cfg: config.ResponseCacheConfig{DisabledRoutes: []string{"/api/v1/pairs"}}, pattern: "GET /api/v1/pairs"},
+		{name: "Listed route", cfg: config.ResponseCacheConfig{Routes: []string{"/api/v1/pairs"}}, pattern: "GET /api/v1/pairs", cached: true},
+		{name: "Unlisted route", cfg: config.ResponseCacheConfig{Routes: []string{"/api/v1/pairs"}}, pattern: "GET /api/v1/ohlc"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.TTL = time.Second
+			router := &Router{metrics: goldenMetrics()}
+			router.CacheResponses(tc.cfg)
+
+			calls := 0
+			handler := router.cache(tc.pattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+			}))
+			for i := 0; i < 2; i++ {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			}
+
+			if cached := calls == 1; cached != tc.cached {
+				t.Errorf("Expected cached %v, handler called %d times", tc.cached, calls)
+			}
+		})
+	}
+}
@@ -1,33 +1,168 @@
 package http
 
 import (
+	"context"
 	"net/http"
+	"net/http/pprof"
+	"strings"
 	"time"
 
+	"exchange-rate-service/internal/adapter/auth"
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
 	"exchange-rate-service/internal/metrics"
 	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/tracing"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"fmt"
 )
 
 type Router struct {
-	handler *Handler
-	log     *logger.Logger
-	metrics *metrics.Metrics
+	handler     *Handler
+	log         *logger.Logger
+	metrics     *metrics.Metrics
+	auth        *auth.OIDCAuthenticator
+	apiKeys     *auth.APIKeyAuthenticator
+	widgetHosts []string
+	webhooks    map[string]string
+	subscribers WebhookSubscriptions
+	alerts      AlertDocuments
+	applier     ConfigurationApplier
+	simulator   OrderSimulator
+	anomalies   AnomalyLog
+	deliveries  DeliveryLedger
+	schedule    RefreshSchedule
+	proxy       *providerProxy
+	adminSplit  bool
+	readOnly    bool
+	responses   *responseCache
+	config      func() *config.Config
+	middlewares []func(http.Handler) http.Handler
 }
 
-func NewRouter(handler *Handler, log *logger.Logger, metrics *metrics.Metrics) *Router {
+// NewRouter creates the HTTP router. authenticator may be nil, in which case
+// the dashboard and admin pages are served without authentication. apiKeys may
+// be nil, in which case the public API is open.
+func NewRouter(handler *Handler, log *logger.Logger, metrics *metrics.Metrics, authenticator *auth.OIDCAuthenticator, apiKeys *auth.APIKeyAuthenticator) *Router {
 	return &Router{
 		handler: handler,
 		log:     log,
 		metrics: metrics,
+		auth:    authenticator,
+		apiKeys: apiKeys,
+	}
+}
+
+// Use adds a middleware that runs inside the logging middleware, in the order added
+func (r *Router) Use(middleware func(http.Handler) http.Handler) {
+	r.middlewares = append(r.middlewares, middleware)
+}
+
+// AllowWidgetOrigins restricts the widget endpoints to the given origins, such as https://shop.example.com
+func (r *Router) AllowWidgetOrigins(origins []string) {
+	r.widgetHosts = origins
+}
+
+// AcceptProviderWebhooks enables push webhooks for the providers in secrets,
+// keyed by provider name with the shared signing secret as value
+func (r *Router) AcceptProviderWebhooks(secrets map[string]string) {
+	r.webhooks = secrets
+}
+
+// ManageWebhooks lets API key holders subscribe to outgoing webhooks. It has no
+// effect without API keys, which identify the tenant owning a subscription.
+func (r *Router) ManageWebhooks(subscriptions WebhookSubscriptions) {
+	r.subscribers = subscriptions
+}
+
+// ManageAlerts lets admins export and import the alert rules and webhook
+// subscriptions as one document
+func (r *Router) ManageAlerts(alerts AlertDocuments) {
+	r.alerts = alerts
+}
+
+// ApplyConfiguration lets admins manage pairs, margins, alerts and API keys
+// with declarative documents
+func (r *Router) ApplyConfiguration(applier ConfigurationApplier) {
+	r.applier = applier
+}
+
+// SimulateOrders lets API key holders place simulated orders. Like webhooks,
+// it has no effect without API keys.
+func (r *Router) SimulateOrders(simulator OrderSimulator) {
+	r.simulator = simulator
+}
+
+// ReportAnomalies serves the anomalies detected in published rates
+func (r *Router) ReportAnomalies(anomalies AnomalyLog) {
+	r.anomalies = anomalies
+}
+
+// InspectDeliveries serves the delivery ledger and manual replays on the admin API
+func (r *Router) InspectDeliveries(ledger DeliveryLedger) {
+	r.deliveries = ledger
+}
+
+// ScheduleRefreshes lets operators change the refresh interval on the admin API
+func (r *Router) ScheduleRefreshes(schedule RefreshSchedule) {
+	r.schedule = schedule
+}
+
+// ProxyProvider enables the provider-shaped /proxy/ endpoints, answered from
+// a cache in front of provider
+func (r *Router) ProxyProvider(provider RawProvider, cfg config.ProxyConfig) {
+	r.proxy = newProviderProxy(provider, cfg, r.metrics, r.log)
+}
+
+// ExposeConfig serves the effective configuration, redacted, on the admin API
+func (r *Router) ExposeConfig(current func() *config.Config) {
+	r.config = current
+}
+
+// SeparateAdmin moves the admin API, the admin page and /metrics out of
+// SetupRoutes so they are only reachable through the handler returned by
+// SetupAdminRoutes, which also serves the pprof profiles
+func (r *Router) SeparateAdmin() {
+	r.adminSplit = true
+}
+
+type routePatternKey struct{}
+
+// recordPattern tells loggingMiddleware the pattern mux matched. Middlewares
+// in between pass copies of the request inward, so the outer one never sees
+// the Pattern the mux sets on its own copy.
+func recordPattern(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mux.ServeHTTP(w, req)
+		if pattern, ok := req.Context().Value(routePatternKey{}).(*string); ok {
+			*pattern = req.Pattern
+		}
+	})
+}
+
+// ReadOnly refuses every request that changes state, for replicas, which
+// serve what the primary stores
+func (r *Router) ReadOnly() {
+	r.readOnly = true
+}
+
+// writes returns handler, or on a read-only router a handler refusing the
+// change with 403 and the code replica
+func (r *Router) writes(handler http.HandlerFunc) http.HandlerFunc {
+	if !r.readOnly {
+		return handler
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.handler.sendCodedErrorResponse(w, http.StatusForbidden, "read-only replica, send changes to the primary", "replica", nil)
 	}
 }
 
 func (r *Router) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		start := time.Now()
+		pattern := new(string)
+		req = req.WithContext(context.WithValue(req.Context(), routePatternKey{}, pattern))
 
 		crw := &customResponseWriter{
 			ResponseWriter: w,
@@ -37,13 +172,19 @@ func (r *Router) loggingMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(crw, req)
 
 		if req.URL.Path != "/metrics" {
+			// Label by route pattern so path parameters such as IDs don't explode cardinality
+			path := req.URL.Path
+			if strings.Contains(*pattern, "{") {
+				_, path, _ = strings.Cut(*pattern, " ")
+			}
+
 			duration := time.Since(start).Seconds()
-			r.metrics.HTTPRequestDuration.WithLabelValues(req.URL.Path, req.Method).Observe(duration)
-			r.metrics.HTTPRequestsTotal.WithLabelValues(req.URL.Path, req.Method, fmt.Sprint('0'+crw.statusCode/100)+"xx").Inc()
+			r.metrics.HTTPRequestDuration.WithLabelValues(path, req.Method).Observe(duration)
+			r.metrics.HTTPRequestsTotal.WithLabelValues(path, req.Method, fmt.Sprint('0'+crw.statusCode/100)+"xx").Inc()
 		}
 
 		duration := time.Since(start)
-		r.log.Info("HTTP request",
+		r.log.InfoContext(req.Context(), "HTTP request",
 			"method", req.Method,
 			"path", req.URL.Path,
 			"query", req.URL.RawQuery,
@@ -65,28 +206,262 @@ func (crw *customResponseWriter) WriteHeader(code int) {
 	crw.ResponseWriter.WriteHeader(code)
 }
 
+// handleAdmin registers an admin API route, restricted to the admin role when
+// OIDC is enabled, or to API key holders when only API keys are configured
+func (r *Router) handleAdmin(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	guarded := r.deprecate(pattern, handler)
+	switch {
+	case r.auth != nil:
+		mux.Handle(pattern, r.auth.RequireAPI(auth.RoleAdmin, guarded))
+	case r.apiKeys != nil:
+		mux.Handle(pattern, r.apiKeys.RequireKey(guarded))
+	default:
+		mux.Handle(pattern, guarded)
+	}
+}
+
+// handleAPI registers a public API route. When API keys are configured it
+// requires a key, or a widget token when widget is true. A dashboard session
+// is accepted too so the embedded pages keep working.
+func (r *Router) handleAPI(mux *http.ServeMux, pattern string, handler http.HandlerFunc, widget bool) {
+	mux.Handle(pattern, r.protectAPI(r.deprecate(pattern, r.cache(pattern, handler)), widget))
+}
+
+// handleUDF registers a TradingView datafeed route. The charting library calls
+// it from the browser, so it answers CORS for the widget origins like the widget.
+func (r *Router) handleUDF(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	mux.Handle(pattern, widgetOrigins(r.widgetHosts, r.protectAPI(r.deprecate(pattern, handler), false)))
+}
+
+// handleTenant registers a route scoped to the holder of an API key. Only
+// meaningful when API keys are configured.
+func (r *Router) handleTenant(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	mux.Handle(pattern, r.apiKeys.RequireKey(r.deprecate(pattern, handler)))
+}
+
+func (r *Router) protectAPI(handler http.Handler, widget bool) http.Handler {
+	if r.apiKeys == nil {
+		return handler
+	}
+
+	guarded := r.apiKeys.RequireKey(handler)
+	if widget {
+		guarded = r.apiKeys.RequireKeyOrToken(handler)
+	}
+	if r.auth == nil {
+		return guarded
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if session, err := r.auth.SessionFromRequest(req); err == nil && session.HasRole(auth.RoleViewer) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		guarded.ServeHTTP(w, req)
+	})
+}
+
 func (r *Router) SetupRoutes() http.Handler {
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/api/v1/rates", r.handler.GetLatestRateHandler)
-	mux.HandleFunc("/api/v1/convert", r.handler.ConvertCurrencyHandler)
-	mux.HandleFunc("/api/v1/historical", r.handler.GetHistoricalRateHandler)
-	mux.HandleFunc("/api/v1/historical/range", r.handler.GetHistoricalRatesHandler)
+	r.handleAPI(mux, "/api/v1/rates", r.handler.GetLatestRateHandler, true)
+	r.handleAPI(mux, "GET /api/v1/rates/sparkline", r.handler.SparklineHandler, true)
+	r.handleAPI(mux, "GET /api/v1/pairs", r.handler.ListPairsHandler, false)
+	r.handleAPI(mux, "GET /api/v1/currencies", r.handler.CurrenciesHandler, false)
+	r.handleAPI(mux, "GET /api/v1/providers/status", r.handler.ProviderStatusHandler, false)
+	r.handleAPI(mux, "GET /api/v1/providers/usage", r.handler.ProviderUsageHandler, false)
+	r.handleAPI(mux, "/api/v1/convert", r.handler.ConvertCurrencyHandler, true)
+	r.handleAPI(mux, "POST /api/v1/convert/matrix", r.handler.ConvertMatrixHandler, false)
+	r.handleAPI(mux, "GET /api/v1/conversions/{id}", r.handler.GetConversionHandler, false)
+	r.handleAPI(mux, "POST /api/v1/validate", r.handler.ValidateHandler, false)
+	r.handleAPI(mux, "/api/v1/historical", r.handler.GetHistoricalRateHandler, true)
+	r.handleAPI(mux, "/api/v1/historical/range", r.handler.GetHistoricalRatesHandler, true)
+	r.handleAPI(mux, "GET /api/v1/historical/stats", r.handler.RangeStatisticsHandler, false)
+	r.handleAPI(mux, "GET /api/v1/budget", r.handler.TravelBudgetHandler, false)
+	r.handleAPI(mux, "GET /api/v1/backtest", r.handler.BacktestHandler, false)
+	r.handleAPI(mux, "GET /api/v1/convert/inflation", r.handler.InflationHandler, false)
+	r.handleAPI(mux, "GET /api/v1/ohlc", r.handler.GetOHLCHandler, false)
+	r.handleAPI(mux, "GET /api/v1/annotations", r.handler.ListAnnotationsHandler, false)
+	r.handleAPI(mux, "POST /api/v1/annotations", r.writes(r.handler.CreateAnnotationHandler), false)
+	r.handleAPI(mux, "DELETE /api/v1/annotations/{id}", r.writes(r.handler.DeleteAnnotationHandler), false)
+	if r.anomalies != nil {
+		r.handleAPI(mux, "GET /api/v1/anomalies", r.handler.listAnomaliesHandler(r.anomalies), false)
+	}
+
+	// Origin checks run before authentication so CORS preflights, which carry no credentials, are answered
+	mux.Handle("/api/v1/widget/convert", widgetOrigins(r.widgetHosts, r.protectAPI(http.HandlerFunc(r.handler.WidgetConvertHandler), true)))
+	mux.HandleFunc("GET /widget.js", serveScript("widget.js"))
+
+	mux.HandleFunc("GET /soap", r.handler.WSDLHandler)
+	r.handleAPI(mux, "POST /soap", r.handler.SOAPHandler, false)
+
+	r.handleUDF(mux, "/udf/config", r.handler.UDFConfigHandler)
+	r.handleUDF(mux, "/udf/time", r.handler.UDFTimeHandler)
+	r.handleUDF(mux, "/udf/symbols", r.handler.UDFSymbolsHandler)
+	r.handleUDF(mux, "/udf/search", r.handler.UDFSearchHandler)
+	r.handleUDF(mux, "/udf/history", r.handler.UDFHistoryHandler)
+
+	if r.proxy != nil {
+		r.handleAPI(mux, "GET /proxy/live", r.proxy.handler("live"), false)
+		r.handleAPI(mux, "GET /proxy/historical", r.proxy.handler("historical"), false)
+	}
+
+	if r.apiKeys != nil && r.apiKeys.TokensEnabled() {
+		mux.Handle("POST /api/v1/tokens", r.apiKeys.RequireKey(http.HandlerFunc(r.apiKeys.MintTokenHandler)))
+	}
+
+	// Subscriptions belong to an API key, so a dashboard session is not enough
+	if r.apiKeys != nil && r.subscribers != nil {
+		r.handleTenant(mux, "GET /api/v1/webhooks", r.handler.listSubscriptionsHandler(r.subscribers))
+		r.handleTenant(mux, "POST /api/v1/webhooks", r.writes(r.handler.createSubscriptionHandler(r.subscribers)))
+		r.handleTenant(mux, "DELETE /api/v1/webhooks/{id}", r.writes(r.handler.deleteSubscriptionHandler(r.subscribers)))
+		r.handleTenant(mux, "GET /api/v1/webhooks/{id}/deliveries", r.handler.subscriptionDeliveriesHandler(r.subscribers))
+	}
+	if r.apiKeys != nil {
+		r.handleTenant(mux, "GET /api/v1/baskets", r.handler.ListBasketsHandler)
+		r.handleTenant(mux, "POST /api/v1/baskets", r.writes(r.handler.CreateBasketHandler))
+		r.handleTenant(mux, "DELETE /api/v1/baskets/{id}", r.writes(r.handler.DeleteBasketHandler))
+		r.handleTenant(mux, "GET /api/v1/baskets/{id}/quote", r.handler.QuoteBasketHandler)
+		r.handleTenant(mux, "GET /api/v1/baskets/{id}/chart", r.handler.ChartBasketHandler)
+	}
+	if r.apiKeys != nil && r.simulator != nil {
+		r.handleTenant(mux, "GET /api/v1/simulations", r.handler.listOrdersHandler(r.simulator))
+		r.handleTenant(mux, "POST /api/v1/simulations", r.writes(r.handler.placeOrderHandler(r.simulator)))
+		r.handleTenant(mux, "DELETE /api/v1/simulations/{id}", r.writes(r.handler.cancelOrderHandler(r.simulator)))
+		r.handleTenant(mux, "GET /api/v1/simulations/{id}/executions", r.handler.orderExecutionsHandler(r.simulator))
+	}
+
+	// Providers authenticate with a signature rather than an API key
+	if len(r.webhooks) > 0 {
+		mux.HandleFunc("POST /hooks/provider/{name}", r.writes(r.handler.providerWebhookHandler(r.webhooks)))
+	}
+
+	if !r.adminSplit {
+		r.registerAdmin(mux)
+	}
+
+	r.registerCommon(mux)
+
+	dashboard := http.Handler(servePage("dashboard.html"))
+	if r.auth != nil {
+		dashboard = r.auth.Require(auth.RoleViewer, dashboard)
+	}
+	mux.Handle("/dashboard/", dashboard)
+
+	return r.wrap(mux, !r.adminSplit)
+}
+
+// SetupAdminRoutes returns the handler for a dedicated admin listener. It is
+// only meaningful after SeparateAdmin.
+func (r *Router) SetupAdminRoutes() http.Handler {
+	mux := http.NewServeMux()
+	r.registerAdmin(mux)
+	r.registerDebug(mux)
+	r.registerCommon(mux)
+	return r.wrap(mux, true)
+}
+
+func (r *Router) registerAdmin(mux *http.ServeMux) {
+	r.handleAdmin(mux, "GET /api/v1/admin/history/gaps", r.handler.HistoryGapsHandler)
+	r.handleAdmin(mux, "POST /api/v1/admin/history/backfill", r.writes(r.handler.BackfillHistoryHandler))
+	r.handleAdmin(mux, "GET /api/v1/admin/providers/sla", r.handler.ProviderSLAHandler)
+	r.handleAdmin(mux, "GET /api/v1/admin/providers/health", r.handler.ProviderHealthHandler)
+	r.handleAdmin(mux, "GET /api/v1/admin/providers/diff", r.handler.ProviderDiffHandler)
+	r.handleAdmin(mux, "GET /api/v1/admin/cache/stats", r.handler.CacheStatsHandler)
+	if r.config != nil {
+		r.handleAdmin(mux, "GET /api/v1/admin/config", r.handler.configHandler(r.config))
+	}
+	if r.deliveries != nil {
+		r.handleAdmin(mux, "GET /api/v1/admin/deliveries", r.handler.listDeliveriesHandler(r.deliveries))
+		r.handleAdmin(mux, "GET /api/v1/admin/deliveries/{id}", r.handler.getDeliveryHandler(r.deliveries))
+		r.handleAdmin(mux, "POST /api/v1/admin/deliveries/{id}/replay", r.writes(r.handler.replayDeliveryHandler(r.deliveries)))
+	}
+	if r.alerts != nil {
+		r.handleAdmin(mux, "GET /api/v1/admin/alerts/export", r.handler.exportAlertsHandler(r.alerts))
+		r.handleAdmin(mux, "POST /api/v1/admin/alerts/import", r.writes(r.handler.importAlertsHandler(r.alerts)))
+	}
+	if r.applier != nil {
+		r.handleAdmin(mux, "POST /api/v1/admin/apply", r.writes(r.handler.applyHandler(r.applier)))
+	}
+	if r.schedule != nil {
+		r.handleAdmin(mux, "GET /api/v1/admin/refresh/interval", r.handler.getRefreshIntervalHandler(r.schedule))
+		r.handleAdmin(mux, "PUT /api/v1/admin/refresh/interval", r.writes(r.handler.setRefreshIntervalHandler(r.schedule)))
+	}
+
+	admin := http.Handler(servePage("admin.html"))
+	if r.auth != nil {
+		admin = r.auth.Require(auth.RoleAdmin, admin)
+	}
+	mux.Handle("/admin/", admin)
+}
 
+// registerDebug adds the pprof profiles. They are never served publicly, so
+// only the dedicated admin listener registers them.
+func (r *Router) registerDebug(mux *http.ServeMux) {
+	r.handleAdmin(mux, "GET /debug/pprof/", pprof.Index)
+	r.handleAdmin(mux, "GET /debug/pprof/cmdline", pprof.Cmdline)
+	r.handleAdmin(mux, "GET /debug/pprof/profile", pprof.Profile)
+	r.handleAdmin(mux, "GET /debug/pprof/symbol", pprof.Symbol)
+	r.handleAdmin(mux, "GET /debug/pprof/trace", pprof.Trace)
+}
+
+// registerCommon adds the routes every listener serves: health and the login flow
+func (r *Router) registerCommon(mux *http.ServeMux) {
 	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
+	mux.HandleFunc("/health", r.handler.HealthHandler)
+
+	if r.auth != nil {
+		mux.HandleFunc("/auth/login", r.auth.LoginHandler)
+		mux.HandleFunc("/auth/callback", r.auth.CallbackHandler)
+		mux.HandleFunc("/auth/logout", r.auth.LogoutHandler)
+		mux.HandleFunc("/auth/session", r.auth.SessionHandler)
+	}
+}
+
+// tenantMiddleware records the holder of the request's valid API key in its
+// context, for handlers and services that vary by tenant
+func (r *Router) tenantMiddleware(next http.Handler) http.Handler {
+	if r.apiKeys == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if tenant := r.apiKeys.Tenant(req); tenant != "" {
+			req = req.WithContext(model.WithTenant(req.Context(), tenant))
+		}
+		next.ServeHTTP(w, req)
 	})
+}
 
-	apiWithMiddleware := r.loggingMiddleware(mux)
+// traceMiddleware carries the trace headers of a request into its context,
+// for provider calls and logs, and echoes them in the response
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if trace, ok := tracing.Extract(r.Header); ok {
+			trace.Inject(w.Header())
+			r = r.WithContext(tracing.NewContext(r.Context(), trace))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wrap applies the middleware chain, and exposes /metrics next to mux when
+// withMetrics is set
+func (r *Router) wrap(mux *http.ServeMux, withMetrics bool) http.Handler {
+	var api http.Handler = languageMiddleware(r.tenantMiddleware(requestOptionsMiddleware(metaMiddleware(timestampFormatMiddleware(envelopeMiddleware(r.deprecatedParamsMiddleware(recordPattern(mux))))))))
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		api = r.middlewares[i](api)
+	}
+
+	apiWithMiddleware := traceMiddleware(r.loggingMiddleware(api))
 
 	rootMux := http.NewServeMux()
 
 	rootMux.Handle("/", apiWithMiddleware)
 	rootMux.Handle("/api/", apiWithMiddleware)
 
-	rootMux.Handle("/metrics", promhttp.Handler())
+	if withMetrics {
+		rootMux.Handle("/metrics", promhttp.Handler())
+	}
 
 	return rootMux
 }
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestMetricsLabelRoutesByPattern(t *testing.T) {
+	router := newGoldenRouter(t)
+	handler := router.SetupRoutes()
+
+	// include_meta makes metaMiddleware pass a copy of the request inward
+	for _, target := range []string{"/api/v1/conversions/abc-123", "/api/v1/conversions/def-456?include_meta=true"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+	}
+
+	paths := make(map[string]bool)
+	series := make(chan prometheus.Metric, 1024)
+	router.metrics.HTTPRequestDuration.Collect(series)
+	close(series)
+	for metric := range series {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "path" {
+				paths[label.GetValue()] = true
+			}
+		}
+	}
+
+	if !paths["/api/v1/conversions/{id}"] {
+		t.Errorf("Expected requests labelled by their route pattern, got %v", paths)
+	}
+	for _, raw := range []string{"/api/v1/conversions/abc-123", "/api/v1/conversions/def-456"} {
+		if paths[raw] {
+			t.Errorf("Expected no series labelled with the raw path %s", raw)
+		}
+	}
+}
+
+func TestReadOnlyRouterRefusesWrites(t *testing.T) {
+	router := newGoldenRouter(t)
+	router.ReadOnly()
+	handler := router.SetupRoutes()
+
+	body := `{"from":"USD","to":"INR","date":"` + time.Now().UTC().Format("2006-01-02") + `","text":"replica"}`
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/api/v1/annotations", strings.NewReader(body)))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("Expected a write to a replica to be refused with 403, got %d: %s", recorder.Code, recorder.Body)
+	}
+	var response Response
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Success || response.Code != "replica" {
+		t.Errorf("Expected the code replica, got %+v", response)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/annotations?from=USD&to=INR", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected reads to be served by a replica, got %d: %s", recorder.Code, recorder.Body)
+	}
+}
+
+func TestSeparateAdminMovesAdminRoutes(t *testing.T) {
+	router := newGoldenRouter(t)
+	router.SeparateAdmin()
+	public, admin := router.SetupRoutes(), router.SetupAdminRoutes()
+
+	tests := []struct {
+		target       string
+		publicStatus int
+		adminStatus  int
+	}{
+		{"/api/v1/admin/cache/stats", http.StatusNotFound, http.StatusOK},
+		{"/metrics", http.StatusNotFound, http.StatusOK},
+		{"/debug/pprof/", http.StatusNotFound, http.StatusOK},
+		{"/health", http.StatusOK, http.StatusOK},
+		{"/api/v1/rates?from=USD&to=INR", http.StatusOK, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		for listener, expected := range map[string]struct {
+			handler http.Handler
+			status  int
+		}{"public": {public, tt.publicStatus}, "admin": {admin, tt.adminStatus}} {
+			recorder := httptest.NewRecorder()
+			expected.handler.ServeHTTP(recorder, httptest.NewRequest("GET", tt.target, nil))
+			if recorder.Code != expected.status {
+				t.Errorf("Expected %s on the %s listener to answer %d, got %d", tt.target, listener, expected.status, recorder.Code)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/simulation"
+)
+
+// OrderSimulator manages the simulated orders of API key holders, each of
+// which only sees its own orders
+type OrderSimulator interface {
+	Place(ctx context.Context, tenant string, order model.SimulationOrder) (*model.SimulationOrder, error)
+	Orders(ctx context.Context, tenant string) ([]model.SimulationOrder, error)
+	Cancel(ctx context.Context, tenant, id string) error
+	Executions(ctx context.Context, tenant, id string) ([]model.SimulatedExecution, error)
+}
+
+type placeOrderRequest struct {
+	Type           model.SimulationOrderType `json:"type"`
+	BaseCurrency   model.Currency            `json:"base_currency"`
+	TargetCurrency model.Currency            `json:"target_currency"`
+	Amount         float64                   `json:"amount"`
+	Condition      model.SimulationCondition `json:"condition"`
+	Threshold      float64                   `json:"threshold"`
+	Every          string                    `json:"every"`
+}
+
+func (h *Handler) placeOrderHandler(simulator OrderSimulator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body placeOrderRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+
+		created, err := simulator.Place(r.Context(), model.TenantFromContext(r.Context()), model.SimulationOrder{
+			Type:           body.Type,
+			BaseCurrency:   body.BaseCurrency,
+			TargetCurrency: body.TargetCurrency,
+			Amount:         body.Amount,
+			Condition:      body.Condition,
+			Threshold:      body.Threshold,
+			Every:          body.Every,
+		})
+		if err != nil {
+			h.handleSimulationError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, created)
+	}
+}
+
+func (h *Handler) listOrdersHandler(simulator OrderSimulator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orders, err := simulator.Orders(r.Context(), model.TenantFromContext(r.Context()))
+		if err != nil {
+			h.handleSimulationError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, orders)
+	}
+}
+
+func (h *Handler) cancelOrderHandler(simulator OrderSimulator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := simulator.Cancel(r.Context(), model.TenantFromContext(r.Context()), r.PathValue("id")); err != nil {
+			h.handleSimulationError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, nil)
+	}
+}
+
+func (h *Handler) orderExecutionsHandler(simulator OrderSimulator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		executions, err := simulator.Executions(r.Context(), model.TenantFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			h.handleSimulationError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, executions)
+	}
+}
+
+func (h *Handler) handleSimulationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, simulation.ErrInvalidOrder):
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, simulation.ErrTooManyOrders):
+		h.sendErrorResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, simulation.ErrOrderNotFound):
+		h.sendErrorResponse(w, http.StatusNotFound, "simulated order not found")
+	default:
+		h.handleServiceError(w, err)
+	}
+}
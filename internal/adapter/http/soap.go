@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"text/template"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+const soapEnvelopeNS = "http://schemas.xmlsoap.org/soap/envelope/"
+
+var wsdlTemplate = template.Must(template.ParseFS(webContent, "web/exchange.wsdl"))
+
+// The request types match elements by local name only, so clients that leave
+// the children unqualified are accepted too
+type soapRequestEnvelope struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+	Body    struct {
+		GetRate *soapGetRate `xml:"GetRate"`
+		Convert *soapConvert `xml:"Convert"`
+	} `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
+}
+
+type soapGetRate struct {
+	From string `xml:"From"`
+	To   string `xml:"To"`
+	Date string `xml:"Date"`
+}
+
+type soapConvert struct {
+	From   string  `xml:"From"`
+	To     string  `xml:"To"`
+	Amount float64 `xml:"Amount"`
+	Date   string  `xml:"Date"`
+}
+
+type soapGetRateResponse struct {
+	XMLName xml.Name `xml:"urn:exchange-rate-service:soap:v1 GetRateResponse"`
+	From    string   `xml:"From"`
+	To      string   `xml:"To"`
+	Rate    float64  `xml:"Rate"`
+	Date    string   `xml:"Date"`
+}
+
+type soapConvertResponse struct {
+	XMLName         xml.Name `xml:"urn:exchange-rate-service:soap:v1 ConvertResponse"`
+	ConversionID    string   `xml:"ConversionId"`
+	From            string   `xml:"From"`
+	To              string   `xml:"To"`
+	Amount          float64  `xml:"Amount"`
+	ConvertedAmount float64  `xml:"ConvertedAmount"`
+	Rate            float64  `xml:"Rate"`
+	Date            string   `xml:"Date"`
+}
+
+type soapFault struct {
+	XMLName     xml.Name `xml:"soap:Fault"`
+	FaultCode   string   `xml:"faultcode"`
+	FaultString string   `xml:"faultstring"`
+}
+
+type soapResponseEnvelope struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	SoapNS  string   `xml:"xmlns:soap,attr"`
+	Body    struct {
+		Content interface{}
+	} `xml:"soap:Body"`
+}
+
+// WSDLHandler publishes the service description, pointing clients back at the
+// address they fetched it from
+func (h *Handler) WSDLHandler(w http.ResponseWriter, r *http.Request) {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	// The Host header is client controlled, so it is escaped before going into the XML
+	address := template.HTMLEscapeString(scheme + "://" + r.Host + r.URL.Path)
+	if err := wsdlTemplate.Execute(w, address); err != nil {
+		h.log.Error("Failed to render WSDL", "error", err)
+	}
+}
+
+// SOAPHandler is a SOAP 1.1 document/literal bridge to the service for
+// systems that cannot call the JSON API
+func (h *Handler) SOAPHandler(w http.ResponseWriter, r *http.Request) {
+	var envelope soapRequestEnvelope
+	if err := xml.NewDecoder(io.LimitReader(r.Body, maxRequestBodySize)).Decode(&envelope); err != nil {
+		h.sendSOAPFault(w, "soap:Client", "malformed SOAP envelope")
+		return
+	}
+
+	switch {
+	case envelope.Body.GetRate != nil:
+		h.soapGetRate(w, r, envelope.Body.GetRate)
+	case envelope.Body.Convert != nil:
+		h.soapConvert(w, r, envelope.Body.Convert)
+	default:
+		h.sendSOAPFault(w, "soap:Client", "unknown operation, expected GetRate or Convert")
+	}
+}
+
+func (h *Handler) soapGetRate(w http.ResponseWriter, r *http.Request, request *soapGetRate) {
+	h.metrics.RateRequestsTotal.Inc()
+
+	from := model.Currency(request.From)
+	to := model.Currency(request.To)
+	date, err := parseDate(request.Date)
+	if err != nil {
+		h.sendSOAPFault(w, "soap:Client", "invalid Date, use YYYY-MM-DD")
+		return
+	}
+
+	var rate *model.ExchangeRate
+	if date.IsZero() {
+		rate, err = h.service.GetLatestRate(r.Context(), from, to)
+	} else {
+		rate, err = h.service.GetHistoricalRate(r.Context(), from, to, date)
+	}
+	h.observeUnsupportedCurrencies(err, from, to)
+	if err != nil {
+		h.sendSOAPServiceError(w, err)
+		return
+	}
+
+	h.sendSOAPResponse(w, soapGetRateResponse{
+		From: string(rate.BaseCurrency),
+		To:   string(rate.TargetCurrency),
+		Rate: rate.Rate,
+		Date: rate.Date.Format("2006-01-02"),
+	})
+}
+
+func (h *Handler) soapConvert(w http.ResponseWriter, r *http.Request, request *soapConvert) {
+	h.metrics.ConversionRequestsTotal.Inc()
+
+	from := model.Currency(request.From)
+	to := model.Currency(request.To)
+	date, err := parseDate(request.Date)
+	if err != nil {
+		h.sendSOAPFault(w, "soap:Client", "invalid Date, use YYYY-MM-DD")
+		return
+	}
+
+	result, err := h.service.ConvertCurrency(r.Context(), model.ConversionRequest{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Amount:       request.Amount,
+		Date:         date,
+	})
+	h.observeUnsupportedCurrencies(err, from, to)
+	if err != nil {
+		h.sendSOAPServiceError(w, err)
+		return
+	}
+
+	h.sendSOAPResponse(w, soapConvertResponse{
+		ConversionID:    result.ID,
+		From:            string(result.FromCurrency),
+		To:              string(result.ToCurrency),
+		Amount:          result.FromAmount,
+		ConvertedAmount: result.ToAmount,
+		Rate:            result.Rate,
+		Date:            result.Date.Format("2006-01-02"),
+	})
+}
+
+func (h *Handler) sendSOAPResponse(w http.ResponseWriter, content interface{}) {
+	h.writeSOAPEnvelope(w, http.StatusOK, content)
+}
+
+// sendSOAPServiceError reports a service error as a fault, blaming the client
+// for anything the JSON API would answer with a 4xx
+func (h *Handler) sendSOAPServiceError(w http.ResponseWriter, err error) {
+	statusCode, message := serviceErrorStatus(err)
+	h.log.Error("Service error", "error", err, "status_code", statusCode)
+
+	code := "soap:Server"
+	if statusCode < http.StatusInternalServerError {
+		code = "soap:Client"
+	}
+	h.sendSOAPFault(w, code, message)
+}
+
+// sendSOAPFault answers 500 as SOAP 1.1 requires for faults
+func (h *Handler) sendSOAPFault(w http.ResponseWriter, code, message string) {
+	h.writeSOAPEnvelope(w, http.StatusInternalServerError, soapFault{FaultCode: code, FaultString: message})
+}
+
+func (h *Handler) writeSOAPEnvelope(w http.ResponseWriter, statusCode int, content interface{}) {
+	envelope := soapResponseEnvelope{SoapNS: soapEnvelopeNS}
+	envelope.Body.Content = content
+
+	payload, err := xml.Marshal(envelope)
+	if err != nil {
+		h.log.Error("Failed to encode SOAP response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(statusCode)
+	w.Write([]byte(xml.Header))
+	w.Write(payload)
+}
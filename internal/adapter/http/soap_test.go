@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func soapEnvelope(body string) string {
+	return `<?xml version="1.0"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:ex="urn:exchange-rate-service:soap:v1"><soap:Body>` + body + `</soap:Body></soap:Envelope>`
+}
+
+func TestSOAPHandler(t *testing.T) {
+	handler := goldenRouter(t)
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		contains []string
+	}{
+		{
+			name:     "latest rate",
+			body:     soapEnvelope(`<ex:GetRate><ex:From>USD</ex:From><ex:To>INR</ex:To></ex:GetRate>`),
+			status:   http.StatusOK,
+			contains: []string{`<GetRateResponse xmlns="urn:exchange-rate-service:soap:v1">`, "<Rate>83.12</Rate>"},
+		},
+		{
+			name:     "unqualified children",
+			body:     soapEnvelope(`<GetRate><From>USD</From><To>EUR</To></GetRate>`),
+			status:   http.StatusOK,
+			contains: []string{"<Rate>0.92</Rate>"},
+		},
+		{
+			name:     "conversion",
+			body:     soapEnvelope(`<ex:Convert><ex:From>USD</ex:From><ex:To>INR</ex:To><ex:Amount>10</ex:Amount></ex:Convert>`),
+			status:   http.StatusOK,
+			contains: []string{"<ConvertedAmount>831.2</ConvertedAmount>", "<ConversionId>"},
+		},
+		{
+			name:     "unsupported currency",
+			body:     soapEnvelope(`<ex:GetRate><ex:From>USD</ex:From><ex:To>XXX</ex:To></ex:GetRate>`),
+			status:   http.StatusInternalServerError,
+			contains: []string{"<faultcode>soap:Client</faultcode>"},
+		},
+		{
+			name:     "invalid date",
+			body:     soapEnvelope(`<ex:GetRate><ex:From>USD</ex:From><ex:To>INR</ex:To><ex:Date>10/03/2025</ex:Date></ex:GetRate>`),
+			status:   http.StatusInternalServerError,
+			contains: []string{"<faultcode>soap:Client</faultcode>", "invalid Date"},
+		},
+		{
+			name:     "unknown operation",
+			body:     soapEnvelope(`<ex:Transfer/>`),
+			status:   http.StatusInternalServerError,
+			contains: []string{"unknown operation"},
+		},
+		{
+			name:     "malformed envelope",
+			body:     `<Envelope>`,
+			status:   http.StatusInternalServerError,
+			contains: []string{"malformed SOAP envelope"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/soap", strings.NewReader(tt.body)))
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, recorder.Code, recorder.Body)
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "text/xml; charset=utf-8" {
+				t.Errorf("Expected an XML response, got %s", contentType)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(recorder.Body.String(), want) {
+					t.Errorf("Expected the response to contain %s, got %s", want, recorder.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestWSDLHandlerEscapesHost(t *testing.T) {
+	handler := goldenRouter(t)
+
+	req := httptest.NewRequest("GET", "/soap", nil)
+	req.Host = `rates.example.com"/><evil`
+	req.Header.Set("X-Forwarded-Proto", "https")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	body := recorder.Body.String()
+	if recorder.Code != http.StatusOK || !strings.Contains(body, `location="https://rates.example.com&#34;/&gt;&lt;evil/soap"`) {
+		t.Errorf("Expected the escaped address in the WSDL, got %d: %s", recorder.Code, body)
+	}
+	if strings.Contains(body, "<evil") {
+		t.Error("Expected the Host header not to inject markup")
+	}
+}
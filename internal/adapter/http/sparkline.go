@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// maxSparklinePoints bounds the points parameter; the buffer may hold fewer
+const maxSparklinePoints = 1000
+
+func (h *Handler) SparklineHandler(w http.ResponseWriter, r *http.Request) {
+	from := model.Currency(r.URL.Query().Get("from"))
+	to := model.Currency(r.URL.Query().Get("to"))
+	if from == "" || to == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameters: from and to")
+		return
+	}
+
+	points := model.DefaultSparklinePoints
+	if s := r.URL.Query().Get("points"); s != "" {
+		var err error
+		points, err = strconv.Atoi(s)
+		if err != nil || points <= 0 || points > maxSparklinePoints {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid points parameter, use 1 to "+strconv.Itoa(maxSparklinePoints))
+			return
+		}
+	}
+
+	sparkline, err := h.service.Sparkline(r.Context(), from, to, points)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, r, sparkline)
+}
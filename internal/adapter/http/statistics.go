@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func (h *Handler) RangeStatisticsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := model.Currency(query.Get("from"))
+	to := model.Currency(query.Get("to"))
+	startDateStr := query.Get("start_date")
+	endDateStr := query.Get("end_date")
+
+	if from == "" || to == "" || startDateStr == "" || endDateStr == "" {
+		h.sendErrorResponse(w, http.StatusBadRequest, "missing required parameters: from, to, start_date, and end_date")
+		return
+	}
+
+	startDate, err := parseDate(startDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid start_date format, use YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := parseDate(endDateStr)
+	if err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid end_date format, use YYYY-MM-DD")
+		return
+	}
+
+	stats, err := h.service.RangeStatistics(r.Context(), model.RangeStatisticsRequest{
+		BaseCurrency:   from,
+		TargetCurrency: to,
+		StartDate:      startDate,
+		EndDate:        endDate,
+	})
+	h.observeUnsupportedCurrencies(err, from, to)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.sendHistoricalResponse(w, r, stats)
+}
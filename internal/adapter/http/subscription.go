@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"exchange-rate-service/internal/adapter/webhook"
+	"exchange-rate-service/internal/domain/model"
+)
+
+// WebhookSubscriptions manages the outgoing webhooks of API key holders,
+// each of which only sees its own subscriptions
+type WebhookSubscriptions interface {
+	Subscribe(ctx context.Context, tenant string, subscription model.WebhookSubscription) (*model.WebhookSubscription, error)
+	Subscriptions(ctx context.Context, tenant string) ([]model.WebhookSubscription, error)
+	Unsubscribe(ctx context.Context, tenant, id string) error
+	Deliveries(ctx context.Context, tenant, id string) ([]model.WebhookDelivery, error)
+}
+
+type createSubscriptionRequest struct {
+	URL    string                   `json:"url"`
+	Events []model.WebhookEventType `json:"events"`
+}
+
+func (h *Handler) createSubscriptionHandler(subscriptions WebhookSubscriptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body createSubscriptionRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+
+		created, err := subscriptions.Subscribe(r.Context(), model.TenantFromContext(r.Context()), model.WebhookSubscription{URL: body.URL, Events: body.Events})
+		if err != nil {
+			h.handleSubscriptionError(w, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		h.sendSuccessResponse(w, r, created)
+	}
+}
+
+func (h *Handler) listSubscriptionsHandler(subscriptions WebhookSubscriptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := subscriptions.Subscriptions(r.Context(), model.TenantFromContext(r.Context()))
+		if err != nil {
+			h.handleSubscriptionError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, list)
+	}
+}
+
+func (h *Handler) deleteSubscriptionHandler(subscriptions WebhookSubscriptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := subscriptions.Unsubscribe(r.Context(), model.TenantFromContext(r.Context()), r.PathValue("id")); err != nil {
+			h.handleSubscriptionError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, nil)
+	}
+}
+
+func (h *Handler) subscriptionDeliveriesHandler(subscriptions WebhookSubscriptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deliveries, err := subscriptions.Deliveries(r.Context(), model.TenantFromContext(r.Context()), r.PathValue("id"))
+		if err != nil {
+			h.handleSubscriptionError(w, err)
+			return
+		}
+
+		h.sendSuccessResponse(w, r, deliveries)
+	}
+}
+
+func (h *Handler) handleSubscriptionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidSubscription):
+		h.sendErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, webhook.ErrTooManySubscriptions):
+		h.sendErrorResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, webhook.ErrSubscriptionNotFound):
+		h.sendErrorResponse(w, http.StatusNotFound, "webhook subscription not found")
+	default:
+		h.handleServiceError(w, err)
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type timestampFormat string
+
+const (
+	timestampRFC3339 timestampFormat = "rfc3339"
+	timestampUnix    timestampFormat = "unix"
+	timestampUnixMs  timestampFormat = "unix_ms"
+	timestampDate    timestampFormat = "date"
+)
+
+func parseTimestampFormat(s string) (timestampFormat, bool) {
+	switch timestampFormat(s) {
+	case timestampRFC3339, timestampUnix, timestampUnixMs, timestampDate:
+		return timestampFormat(s), true
+	}
+	return "", false
+}
+
+// timestampFormatMiddleware rejects unknown ts_format values before any handler runs
+func timestampFormatMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if format := r.URL.Query().Get("ts_format"); format != "" {
+			if _, ok := parseTimestampFormat(format); !ok {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(Response{
+					Success: false,
+					Error:   "invalid ts_format parameter, use rfc3339, unix, unix_ms or date",
+				})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// responseTimestampFormat returns the format requested via ?ts_format=, falling back to the configured default
+func (h *Handler) responseTimestampFormat(r *http.Request) timestampFormat {
+	if format, ok := parseTimestampFormat(r.URL.Query().Get("ts_format")); ok {
+		return format
+	}
+	return h.timestampFormat
+}
+
+// reformatTimestamps re-encodes data with every RFC 3339 timestamp value
+// rewritten into format. Map keys such as the dates of a historical range are
+// left alone.
+func reformatTimestamps(data interface{}, format timestampFormat) (interface{}, error) {
+	if format == timestampRFC3339 {
+		return data, nil
+	}
+
+	tree, err := decodeJSONTree(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return rewriteTimestamps(tree, format), nil
+}
+
+func rewriteTimestamps(value interface{}, format timestampFormat) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = rewriteTimestamps(item, format)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rewriteTimestamps(item, format)
+		}
+		return v
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return v
+		}
+		switch format {
+		case timestampUnix:
+			return t.Unix()
+		case timestampUnixMs:
+			return t.UnixMilli()
+		case timestampDate:
+			return t.UTC().Format("2006-01-02")
+		}
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReformatTimestamps(t *testing.T) {
+	at := time.Date(2025, 3, 10, 14, 30, 15, 500_000_000, time.FixedZone("CET", 3600))
+	data := map[string]interface{}{
+		"last_updated": at,
+		"rates": map[string]interface{}{
+			// Map keys are dates but never rewritten
+			"2025-03-10": map[string]interface{}{"date": at, "rate": 83.1},
+		},
+		"history": []interface{}{map[string]interface{}{"at": at}},
+		"note":    "not a timestamp",
+	}
+
+	tests := []struct {
+		format timestampFormat
+		want   interface{}
+	}{
+		{timestampRFC3339, at},
+		{timestampUnix, float64(at.Unix())},
+		{timestampUnixMs, float64(at.UnixMilli())},
+		{timestampDate, "2025-03-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			reformatted, err := reformatTimestamps(data, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded, _ := json.Marshal(reformatted)
+			var tree map[string]interface{}
+			json.Unmarshal(encoded, &tree)
+
+			want, _ := json.Marshal(tt.want)
+			for name, got := range map[string]interface{}{
+				"top level": tree["last_updated"],
+				"nested":    tree["rates"].(map[string]interface{})["2025-03-10"].(map[string]interface{})["date"],
+				"in a list": tree["history"].([]interface{})[0].(map[string]interface{})["at"],
+			} {
+				if encoded, _ := json.Marshal(got); string(encoded) != string(want) {
+					t.Errorf("Expected the %s timestamp to be %s, got %s", name, want, encoded)
+				}
+			}
+			if tree["note"] != "not a timestamp" {
+				t.Errorf("Expected other strings to be left alone, got %v", tree["note"])
+			}
+		})
+	}
+}
+
+func TestTimestampFormatParameter(t *testing.T) {
+	handler := goldenRouter(t)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/rates?from=USD&to=INR&ts_format=unix", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", recorder.Code, recorder.Body)
+	}
+	var response struct {
+		Data struct {
+			LastUpdated json.Number `json:"last_updated"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected last_updated as a number, got %s: %v", recorder.Body, err)
+	}
+	if seconds, err := response.Data.LastUpdated.Int64(); err != nil || time.Since(time.Unix(seconds, 0)) > time.Hour {
+		t.Errorf("Expected a recent Unix time, got %s", response.Data.LastUpdated)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/rates?from=USD&to=INR&ts_format=iso", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown ts_format to be rejected, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTraceHeadersEchoed(t *testing.T) {
+	router := goldenRouter(t)
+
+	testCases := []struct {
+		name   string
+		header map[string]string
+		echoed map[string]string
+	}{
+		{
+			name:   "W3C",
+			header: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "tracestate": "vendor=opaque"},
+			echoed: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "tracestate": "vendor=opaque"},
+		},
+		{
+			name:   "B3",
+			header: map[string]string{"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7", "X-B3-SpanId": "e457b5a2e4d86bd1", "X-B3-Sampled": "1"},
+			echoed: map[string]string{"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7", "X-B3-SpanId": "e457b5a2e4d86bd1", "X-B3-Sampled": "1"},
+		},
+		{
+			name:   "Malformed",
+			header: map[string]string{"traceparent": "not-a-trace"},
+			echoed: map[string]string{"traceparent": ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/rates?from=USD&to=INR", nil)
+			for name, value := range tc.header {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != 200 {
+				t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			for name, value := range tc.echoed {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("Expected %s %q, got %q", name, value, got)
+				}
+			}
+		})
+	}
+}
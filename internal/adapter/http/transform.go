@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// ResponseTransform changes the data of a successful JSON response before it
+// is sent. data is the decoded JSON: map[string]interface{}, []interface{},
+// string, json.Number, bool or nil. Returning an error fails the request with
+// 500.
+type ResponseTransform func(r *http.Request, data interface{}) (interface{}, error)
+
+var (
+	transformsMutex sync.RWMutex
+	transforms      = make(map[string]ResponseTransform)
+)
+
+// RegisterTransform makes a transform available under name, so deployments can
+// enable it with RESPONSE_TRANSFORMS. It is meant to be called from an init
+// function and panics if name is already taken.
+func RegisterTransform(name string, transform ResponseTransform) {
+	transformsMutex.Lock()
+	defer transformsMutex.Unlock()
+
+	if _, exists := transforms[name]; exists {
+		panic(fmt.Sprintf("response transform %q registered twice", name))
+	}
+	transforms[name] = transform
+}
+
+// LookupTransform returns the transform registered under name
+func LookupTransform(name string) (ResponseTransform, error) {
+	transformsMutex.RLock()
+	defer transformsMutex.RUnlock()
+
+	transform, exists := transforms[name]
+	if !exists {
+		registered := make([]string, 0, len(transforms))
+		for known := range transforms {
+			registered = append(registered, known)
+		}
+		sort.Strings(registered)
+		return nil, fmt.Errorf("unknown response transform %q, registered: %v", name, registered)
+	}
+	return transform, nil
+}
+
+// AddTransform appends a transform applied to every successful JSON response,
+// in the order added. Transforms must be added before the server starts.
+func (h *Handler) AddTransform(transform ResponseTransform) {
+	h.transforms = append(h.transforms, transform)
+}
+
+func (h *Handler) applyTransforms(r *http.Request, data interface{}) (interface{}, error) {
+	if len(h.transforms) == 0 {
+		return data, nil
+	}
+
+	tree, err := decodeJSONTree(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, transform := range h.transforms {
+		if tree, err = transform(r, tree); err != nil {
+			return nil, err
+		}
+	}
+	return tree, nil
+}
+
+// decodeJSONTree round-trips data through JSON, keeping numbers exact
+func decodeJSONTree(data interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree interface{}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// StripFields removes object fields with any of the given names, at any depth
+func StripFields(fields []string) ResponseTransform {
+	return func(r *http.Request, data interface{}) (interface{}, error) {
+		return stripFields(data, fields), nil
+	}
+}
+
+func stripFields(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if slices.Contains(fields, key) {
+				delete(v, key)
+				continue
+			}
+			v[key] = stripFields(item, fields)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = stripFields(item, fields)
+		}
+	}
+	return value
+}
+
+// InjectFields sets the given fields on the data object, or on every object
+// of a data list. Existing fields are overwritten.
+func InjectFields(fields map[string]string) ResponseTransform {
+	return func(r *http.Request, data interface{}) (interface{}, error) {
+		inject := func(value interface{}) {
+			if object, ok := value.(map[string]interface{}); ok {
+				for key, field := range fields {
+					object[key] = field
+				}
+			}
+		}
+
+		if list, ok := data.([]interface{}); ok {
+			for _, item := range list {
+				inject(item)
+			}
+		} else {
+			inject(data)
+		}
+		return data, nil
+	}
+}
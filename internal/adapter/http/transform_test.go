@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestResponseTransforms(t *testing.T) {
+
+	h := NewHandler(nil, logger.NewLogger("error"), nil, "rfc3339")
+	h.AddTransform(StripFields([]string{"last_updated"}))
+	h.AddTransform(InjectFields(map[string]string{"cost_center": "fx-ops"}))
+	h.AddTransform(func(r *http.Request, data interface{}) (interface{}, error) {
+		data.(map[string]interface{})["path"] = r.URL.Path
+		return data, nil
+	})
+
+	rate := model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.12, LastUpdated: time.Now()}
+	w := httptest.NewRecorder()
+	h.sendSuccessResponse(w, httptest.NewRequest(http.MethodGet, "/api/v1/rates/latest", nil), rate)
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if _, exists := response.Data["last_updated"]; exists {
+		t.Error("Expected last_updated to be stripped")
+	}
+	if response.Data["cost_center"] != "fx-ops" || response.Data["path"] != "/api/v1/rates/latest" {
+		t.Errorf("Expected injected fields, got %v", response.Data)
+	}
+	if response.Data["rate"] != 83.12 {
+		t.Errorf("Expected the rate to be kept, got %v", response.Data["rate"])
+	}
+}
+
+func TestResponseTransformError(t *testing.T) {
+
+	h := NewHandler(nil, logger.NewLogger("error"), nil, "rfc3339")
+	h.AddTransform(func(r *http.Request, data interface{}) (interface{}, error) {
+		return nil, errors.New("lookup failed")
+	})
+
+	w := httptest.NewRecorder()
+	h.sendSuccessResponse(w, httptest.NewRequest(http.MethodGet, "/", nil), []int{1})
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+}
+
+func TestLookupTransform(t *testing.T) {
+
+	RegisterTransform("test-noop", func(r *http.Request, data interface{}) (interface{}, error) {
+		return data, nil
+	})
+
+	if _, err := LookupTransform("test-noop"); err != nil {
+		t.Errorf("Expected the registered transform, got %v", err)
+	}
+	if _, err := LookupTransform("missing"); err == nil {
+		t.Error("Expected an error for an unregistered transform")
+	}
+}
@@ -0,0 +1,243 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// udfResolutions maps TradingView resolutions to candle intervals
+var udfResolutions = map[string]string{
+	"1":   "1m",
+	"5":   "5m",
+	"15":  "15m",
+	"30":  "30m",
+	"60":  "1h",
+	"240": "4h",
+	"1D":  "1d",
+	"D":   "1d",
+}
+
+var udfSupportedResolutions = []string{"1", "5", "15", "30", "60", "240", "1D"}
+
+const (
+	udfPriceScale  = 10000
+	udfSearchLimit = 30
+)
+
+type udfConfig struct {
+	SupportedResolutions   []string `json:"supported_resolutions"`
+	SupportsSearch         bool     `json:"supports_search"`
+	SupportsGroupRequest   bool     `json:"supports_group_request"`
+	SupportsMarks          bool     `json:"supports_marks"`
+	SupportsTimescaleMarks bool     `json:"supports_timescale_marks"`
+	SupportsTime           bool     `json:"supports_time"`
+	SymbolsTypes           []udfKV  `json:"symbols_types"`
+}
+
+type udfKV struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type udfSymbol struct {
+	Name                 string   `json:"name"`
+	Ticker               string   `json:"ticker"`
+	Description          string   `json:"description"`
+	Type                 string   `json:"type"`
+	Session              string   `json:"session"`
+	Timezone             string   `json:"timezone"`
+	Exchange             string   `json:"exchange"`
+	ListedExchange       string   `json:"listed_exchange"`
+	MinMov               int      `json:"minmov"`
+	PriceScale           int      `json:"pricescale"`
+	HasIntraday          bool     `json:"has_intraday"`
+	HasDaily             bool     `json:"has_daily"`
+	HasNoVolume          bool     `json:"has_no_volume"`
+	SupportedResolutions []string `json:"supported_resolutions"`
+	IntradayMultipliers  []string `json:"intraday_multipliers"`
+	DataStatus           string   `json:"data_status"`
+}
+
+type udfSearchResult struct {
+	Symbol      string `json:"symbol"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Exchange    string `json:"exchange"`
+	Type        string `json:"type"`
+}
+
+// udfHistory is the bar response; on failure only S and ErrMsg are set
+type udfHistory struct {
+	S      string    `json:"s"`
+	ErrMsg string    `json:"errmsg,omitempty"`
+	T      []int64   `json:"t,omitempty"`
+	O      []float64 `json:"o,omitempty"`
+	H      []float64 `json:"h,omitempty"`
+	L      []float64 `json:"l,omitempty"`
+	C      []float64 `json:"c,omitempty"`
+}
+
+// parseUDFSymbol accepts USDINR, USD/INR and USD-INR, optionally prefixed with
+// an exchange as in EXRATE:USDINR
+func parseUDFSymbol(s string) (model.CurrencyPair, error) {
+	if _, symbol, found := strings.Cut(s, ":"); found {
+		s = symbol
+	}
+	s = strings.ReplaceAll(s, "/", "-")
+	if len(s) == 6 && !strings.Contains(s, "-") {
+		s = s[:3] + "-" + s[3:]
+	}
+	return model.ParseCurrencyPair(s)
+}
+
+func udfTicker(pair model.CurrencyPair) string {
+	return string(pair.BaseCurrency) + string(pair.TargetCurrency)
+}
+
+// sendUDFResponse writes data as is: TradingView expects bare JSON, not the API envelope
+func (h *Handler) sendUDFResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.Error("Failed to encode UDF response", "error", err)
+	}
+}
+
+func (h *Handler) UDFConfigHandler(w http.ResponseWriter, r *http.Request) {
+	h.sendUDFResponse(w, http.StatusOK, udfConfig{
+		SupportedResolutions: udfSupportedResolutions,
+		SupportsSearch:       true,
+		SupportsTime:         true,
+		SymbolsTypes:         []udfKV{{Name: "Forex", Value: "forex"}},
+	})
+}
+
+func (h *Handler) UDFTimeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(strconv.FormatInt(time.Now().Unix(), 10)))
+}
+
+func (h *Handler) UDFSymbolsHandler(w http.ResponseWriter, r *http.Request) {
+	pair, err := parseUDFSymbol(r.URL.Query().Get("symbol"))
+	if err != nil {
+		h.sendUDFResponse(w, http.StatusNotFound, udfHistory{S: "error", ErrMsg: "unknown symbol"})
+		return
+	}
+
+	h.sendUDFResponse(w, http.StatusOK, udfSymbol{
+		Name:                 udfTicker(pair),
+		Ticker:               udfTicker(pair),
+		Description:          string(pair.BaseCurrency) + "/" + string(pair.TargetCurrency),
+		Type:                 "forex",
+		Session:              "24x7",
+		Timezone:             "Etc/UTC",
+		Exchange:             "EXRATE",
+		ListedExchange:       "EXRATE",
+		MinMov:               1,
+		PriceScale:           udfPriceScale,
+		HasIntraday:          true,
+		HasDaily:             true,
+		HasNoVolume:          true,
+		SupportedResolutions: udfSupportedResolutions,
+		IntradayMultipliers:  []string{"1", "5", "15", "30", "60", "240"},
+		DataStatus:           "streaming",
+	})
+}
+
+// UDFSearchHandler searches the pairs the service can currently quote
+func (h *Handler) UDFSearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToUpper(strings.NewReplacer("/", "", "-", "").Replace(r.URL.Query().Get("query")))
+	limit := udfSearchLimit
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 && n < limit {
+		limit = n
+	}
+
+	pairs, err := h.service.ListPairs(r.Context())
+	if err != nil {
+		h.sendUDFResponse(w, http.StatusInternalServerError, udfHistory{S: "error", ErrMsg: "failed to list pairs"})
+		return
+	}
+
+	results := make([]udfSearchResult, 0)
+	for _, p := range pairs {
+		pair := model.CurrencyPair{BaseCurrency: p.BaseCurrency, TargetCurrency: p.TargetCurrency}
+		ticker := udfTicker(pair)
+		if !strings.Contains(ticker, query) {
+			continue
+		}
+		results = append(results, udfSearchResult{
+			Symbol:      ticker,
+			FullName:    "EXRATE:" + ticker,
+			Description: string(pair.BaseCurrency) + "/" + string(pair.TargetCurrency),
+			Exchange:    "EXRATE",
+			Type:        "forex",
+		})
+		if len(results) == limit {
+			break
+		}
+	}
+
+	h.sendUDFResponse(w, http.StatusOK, results)
+}
+
+// UDFHistoryHandler serves bars from the OHLC store. Ranges longer than the
+// candle limit are cut at the recent end; TradingView pages back for the rest.
+func (h *Handler) UDFHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	pair, err := parseUDFSymbol(query.Get("symbol"))
+	if err != nil {
+		h.sendUDFResponse(w, http.StatusBadRequest, udfHistory{S: "error", ErrMsg: "unknown symbol"})
+		return
+	}
+
+	interval, found := udfResolutions[query.Get("resolution")]
+	if !found {
+		h.sendUDFResponse(w, http.StatusBadRequest, udfHistory{S: "error", ErrMsg: "unsupported resolution"})
+		return
+	}
+
+	fromSeconds, fromErr := strconv.ParseInt(query.Get("from"), 10, 64)
+	toSeconds, toErr := strconv.ParseInt(query.Get("to"), 10, 64)
+	if fromErr != nil || toErr != nil {
+		h.sendUDFResponse(w, http.StatusBadRequest, udfHistory{S: "error", ErrMsg: "from and to must be unix timestamps"})
+		return
+	}
+
+	from := time.Unix(fromSeconds, 0).UTC()
+	to := time.Unix(toSeconds, 0).UTC()
+	width := model.CandleIntervals[interval]
+	if earliest := to.Add(-model.MaxCandles * width); from.Before(earliest) {
+		from = earliest
+	}
+
+	series, err := h.service.GetOHLC(r.Context(), model.OHLCRequest{Pair: pair, From: from, To: to, Interval: interval})
+	if err != nil {
+		h.sendUDFResponse(w, http.StatusOK, udfHistory{S: "error", ErrMsg: err.Error()})
+		return
+	}
+
+	candles := series.Candles
+	if countback, err := strconv.Atoi(query.Get("countback")); err == nil && countback > 0 && countback < len(candles) {
+		candles = candles[len(candles)-countback:]
+	}
+	if len(candles) == 0 {
+		h.sendUDFResponse(w, http.StatusOK, udfHistory{S: "no_data"})
+		return
+	}
+
+	bars := udfHistory{S: "ok"}
+	for _, candle := range candles {
+		bars.T = append(bars.T, candle.Time.Unix())
+		bars.O = append(bars.O, candle.Open)
+		bars.H = append(bars.H, candle.High)
+		bars.L = append(bars.L, candle.Low)
+		bars.C = append(bars.C, candle.Close)
+	}
+	h.sendUDFResponse(w, http.StatusOK, bars)
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestParseUDFSymbol(t *testing.T) {
+	usdinr := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	for _, symbol := range []string{"USDINR", "usdinr", "USD/INR", "USD-INR", "EXRATE:USDINR"} {
+		if pair, err := parseUDFSymbol(symbol); err != nil || pair != usdinr {
+			t.Errorf("Expected %s to be USD-INR, got %v, %v", symbol, pair, err)
+		}
+	}
+	for _, symbol := range []string{"", "USD", "USDINRX", "USD_INR"} {
+		if _, err := parseUDFSymbol(symbol); err == nil {
+			t.Errorf("Expected %q to be rejected", symbol)
+		}
+	}
+}
+
+func TestUDFHistoryHandler(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	var requested model.OHLCRequest
+	exchangeService := &mocks.ExchangeServiceMock{
+		GetOHLCFunc: func(ctx context.Context, request model.OHLCRequest) (*model.OHLCSeries, error) {
+			requested = request
+			if request.Pair.TargetCurrency == model.JPY {
+				return &model.OHLCSeries{}, nil
+			}
+			if request.Pair.TargetCurrency == model.GBP {
+				return nil, errors.New("no ticks recorded")
+			}
+			candles := make([]model.Candle, 3)
+			for i := range candles {
+				open := 83 + float64(i)
+				candles[i] = model.Candle{Time: start.Add(time.Duration(i) * time.Hour), Open: open, High: open + 0.5, Low: open - 0.5, Close: open + 0.2}
+			}
+			return &model.OHLCSeries{Candles: candles}, nil
+		},
+	}
+	h := NewHandler(exchangeService, logger.NewLogger("error"), goldenMetrics(), "rfc3339")
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		s      string
+		bars   int
+	}{
+		{"bars", "symbol=USDINR&resolution=60&from=1741564800&to=1741575600", http.StatusOK, "ok", 3},
+		{"countback", "symbol=USD/INR&resolution=60&from=1741564800&to=1741575600&countback=2", http.StatusOK, "ok", 2},
+		{"no data", "symbol=USDJPY&resolution=1D&from=1741564800&to=1741575600", http.StatusOK, "no_data", 0},
+		{"lookup failure", "symbol=USDGBP&resolution=1D&from=1741564800&to=1741575600", http.StatusOK, "error", 0},
+		{"unknown symbol", "symbol=NOPE&resolution=60&from=1&to=2", http.StatusBadRequest, "error", 0},
+		{"unsupported resolution", "symbol=USDINR&resolution=7&from=1&to=2", http.StatusBadRequest, "error", 0},
+		{"invalid range", "symbol=USDINR&resolution=60&from=yesterday&to=2", http.StatusBadRequest, "error", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			h.UDFHistoryHandler(recorder, httptest.NewRequest("GET", "/udf/history?"+tt.query, nil))
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, recorder.Code, recorder.Body)
+			}
+			var history udfHistory
+			if err := json.Unmarshal(recorder.Body.Bytes(), &history); err != nil {
+				t.Fatal(err)
+			}
+			if history.S != tt.s || len(history.T) != tt.bars || len(history.C) != tt.bars {
+				t.Errorf("Expected status %s with %d bars, got %+v", tt.s, tt.bars, history)
+			}
+		})
+	}
+
+	// countback keeps the most recent bars
+	recorder := httptest.NewRecorder()
+	h.UDFHistoryHandler(recorder, httptest.NewRequest("GET", "/udf/history?symbol=USDINR&resolution=60&from=1741564800&to=1741575600&countback=1", nil))
+	var history udfHistory
+	json.Unmarshal(recorder.Body.Bytes(), &history)
+	if len(history.T) != 1 || history.T[0] != start.Add(2*time.Hour).Unix() || history.O[0] != 85 {
+		t.Errorf("Expected only the last bar, got %+v", history)
+	}
+
+	// Ranges longer than the candle limit are cut at the recent end
+	recorder = httptest.NewRecorder()
+	h.UDFHistoryHandler(recorder, httptest.NewRequest("GET", "/udf/history?symbol=USDINR&resolution=1&from=0&to=1741575600", nil))
+	if requested.Interval != "1m" || requested.To.Sub(requested.From) != model.MaxCandles*time.Minute {
+		t.Errorf("Expected the range to be cut to %d minutes, got %s to %s", model.MaxCandles, requested.From, requested.To)
+	}
+}
+
+func TestUDFSearchHandler(t *testing.T) {
+	exchangeService := &mocks.ExchangeServiceMock{
+		ListPairsFunc: func(ctx context.Context) ([]model.PairAvailability, error) {
+			return []model.PairAvailability{
+				{BaseCurrency: model.USD, TargetCurrency: model.INR},
+				{BaseCurrency: model.EUR, TargetCurrency: model.INR},
+				{BaseCurrency: model.USD, TargetCurrency: model.EUR},
+			}, nil
+		},
+	}
+	h := NewHandler(exchangeService, logger.NewLogger("error"), goldenMetrics(), "rfc3339")
+
+	tests := []struct {
+		query   string
+		symbols []string
+	}{
+		{"query=inr", []string{"USDINR", "EURINR"}},
+		{"query=usd/eur", []string{"USDEUR"}},
+		{"query=inr&limit=1", []string{"USDINR"}},
+		{"query=chf", []string{}},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		h.UDFSearchHandler(recorder, httptest.NewRequest("GET", "/udf/search?"+tt.query, nil))
+		var results []udfSearchResult
+		if err := json.Unmarshal(recorder.Body.Bytes(), &results); err != nil {
+			t.Fatal(err)
+		}
+		symbols := make([]string, 0, len(results))
+		for _, result := range results {
+			symbols = append(symbols, result.Symbol)
+		}
+		if len(symbols) != len(tt.symbols) || (len(symbols) > 0 && symbols[0] != tt.symbols[0]) {
+			t.Errorf("Expected %s to find %v, got %v", tt.query, tt.symbols, symbols)
+		}
+	}
+}
+
+func TestUDFSymbolsHandler(t *testing.T) {
+	h := NewHandler(&mocks.ExchangeServiceMock{}, logger.NewLogger("error"), goldenMetrics(), "rfc3339")
+
+	recorder := httptest.NewRecorder()
+	h.UDFSymbolsHandler(recorder, httptest.NewRequest("GET", "/udf/symbols?symbol=EXRATE:USD/INR", nil))
+	var symbol udfSymbol
+	if err := json.Unmarshal(recorder.Body.Bytes(), &symbol); err != nil || symbol.Ticker != "USDINR" || symbol.PriceScale != udfPriceScale {
+		t.Errorf("Expected the USDINR symbol, got %+v, %v", symbol, err)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.UDFSymbolsHandler(recorder, httptest.NewRequest("GET", "/udf/symbols?symbol=nope", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected an unknown symbol to answer 404, got %d", recorder.Code)
+	}
+}
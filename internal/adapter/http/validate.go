@@ -0,0 +1,124 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/i18n"
+)
+
+type validateRequest struct {
+	Type string `json:"type"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Amount takes the formats of the amount parameter of /api/v1/convert
+	Amount    string `json:"amount"`
+	Unit      string `json:"unit"`
+	Date      string `json:"date"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// ValidateHandler checks a conversion or historical request against the rules
+// of the service without serving it. It answers 200 whether the request is
+// valid or not, listing every issue by field, so forms can show them all at
+// once.
+func (h *Handler) ValidateHandler(w http.ResponseWriter, r *http.Request) {
+	var body validateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&body); err != nil {
+		h.sendErrorResponse(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	request, issues := parseValidateRequest(body)
+	if request.Type != "" {
+		// Fields that did not parse are reported once, as sent
+		reported := make(map[string]bool, len(issues))
+		for _, issue := range issues {
+			reported[issue.Field] = true
+		}
+		for _, issue := range h.service.ValidateRequest(r.Context(), request).Issues {
+			if !reported[issue.Field] {
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Field < issues[j].Field })
+	locale := responseLocale(w)
+	for i := range issues {
+		issues[i].Message = i18n.Message(locale, issues[i].Message)
+	}
+	h.sendSuccessResponse(w, r, &model.ValidationResult{Valid: len(issues) == 0, Issues: issues})
+}
+
+// parseValidateRequest parses body, returning the issues of the fields that
+// are missing or do not parse. The request has no Type when body names none
+// it can check.
+func parseValidateRequest(body validateRequest) (model.ValidationRequest, []model.ValidationIssue) {
+	issues := make([]model.ValidationIssue, 0)
+	fail := func(field, code, message string) {
+		issues = append(issues, model.ValidationIssue{Field: field, Code: code, Message: message})
+	}
+	required := func(field, value string) {
+		if value == "" {
+			fail(field, "missing_field", "missing required field: "+field)
+		}
+	}
+	date := func(field, value string) time.Time {
+		parsed, err := parseDate(value)
+		if err != nil {
+			fail(field, "invalid_date", "invalid "+field+" format, use YYYY-MM-DD")
+		}
+		return parsed
+	}
+
+	request := model.ValidationRequest{
+		Type:           body.Type,
+		BaseCurrency:   model.Currency(body.From),
+		TargetCurrency: model.Currency(body.To),
+	}
+	switch body.Type {
+	case model.ValidateConversion, model.ValidateHistorical, model.ValidateHistoricalRange:
+	default:
+		fail("type", "invalid_type", "type must be conversion, historical or historical_range")
+		request.Type = ""
+		return request, issues
+	}
+	required("from", body.From)
+	required("to", body.To)
+
+	switch body.Type {
+	case model.ValidateConversion:
+		request.Amount = 1
+		if body.Amount != "" {
+			amount, err := parseAmount(body.Amount, request.BaseCurrency)
+			switch {
+			case errors.Is(err, errCurrencyMismatch):
+				fail("amount", "invalid_amount", "amount currency does not match from currency")
+			case err != nil:
+				fail("amount", "invalid_amount", "invalid amount parameter")
+			}
+			request.Amount = amount
+		}
+		unit, err := model.ParseQuantityUnit(body.Unit)
+		if err != nil {
+			fail("unit", "invalid_unit", "invalid unit parameter")
+		}
+		request.Unit = unit
+		request.Date = date("date", body.Date)
+	case model.ValidateHistorical:
+		required("date", body.Date)
+		request.Date = date("date", body.Date)
+	case model.ValidateHistoricalRange:
+		required("start_date", body.StartDate)
+		required("end_date", body.EndDate)
+		request.StartDate = date("start_date", body.StartDate)
+		request.EndDate = date("end_date", body.EndDate)
+	}
+	return request, issues
+}
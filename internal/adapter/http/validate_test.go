@@ -0,0 +1,72 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestValidate(t *testing.T) {
+	router := goldenRouter(t)
+	day := func(daysAgo int) string {
+		return time.Now().UTC().AddDate(0, 0, -daysAgo).Format("2006-01-02")
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"valid conversion", `{"type": "conversion", "from": "USD", "to": "INR", "amount": "1,000.50"}`, nil},
+		{"valid range", `{"type": "historical_range", "from": "USD", "to": "EUR", "start_date": "` + day(10) + `", "end_date": "` + day(2) + `"}`, nil},
+		{"unknown type", `{"type": "quote", "from": "USD", "to": "INR"}`, []string{"type:invalid_type"}},
+		{
+			"every issue of a conversion",
+			`{"type": "conversion", "from": "XXX", "to": "INR", "amount": "-5", "unit": "ounce", "date": "` + day(120) + `"}`,
+			[]string{"amount:invalid_amount", "date:date_out_of_range", "from:invalid_currency", "unit:invalid_unit"},
+		},
+		{"unparsed fields reported once", `{"type": "historical", "from": "USD", "date": "yesterday"}`, []string{"date:invalid_date", "to:missing_field"}},
+		{
+			"reversed range",
+			`{"type": "historical_range", "from": "USD", "to": "EUR", "start_date": "` + day(2) + `", "end_date": "` + day(10) + `"}`,
+			[]string{"end_date:invalid_date_range"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/validate", strings.NewReader(tt.body)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+
+			var response struct {
+				Data model.ValidationResult `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(response.Data.Issues))
+			for _, issue := range response.Data.Issues {
+				got = append(got, issue.Field+":"+issue.Code)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Issues = %v, want %v", got, tt.want)
+			}
+			if response.Data.Valid != (len(tt.want) == 0) {
+				t.Errorf("Valid = %v with issues %v", response.Data.Valid, got)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/validate", strings.NewReader("{")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Invalid JSON status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"embed"
+	"net/http"
+)
+
+//go:embed web/*.html web/*.js web/*.wsdl
+var webContent embed.FS
+
+func servePage(name string) http.HandlerFunc {
+	return serveFile(name, "text/html; charset=utf-8", "no-store")
+}
+
+// serveScript serves an embedded script that third-party pages load, so it may be cached briefly
+func serveScript(name string) http.HandlerFunc {
+	return serveFile(name, "application/javascript; charset=utf-8", "public, max-age=300")
+}
+
+func serveFile(name, contentType, cacheControl string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		content, err := webContent.ReadFile("web/" + name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Write(content)
+	}
+}
@@ -0,0 +1,48 @@
+// Exchange rate widget. Embed with:
+//   <script src="https://rates.example.com/widget.js" data-from="USD" data-to="INR"
+//           data-amount="100" data-token="wt_..."></script>
+(function () {
+  var script = document.currentScript;
+  if (!script) return;
+
+  var base = new URL(script.src).origin;
+  var from = (script.dataset.from || "USD").toUpperCase();
+  var to = (script.dataset.to || "EUR").toUpperCase();
+  var token = script.dataset.token || "";
+  var refresh = parseInt(script.dataset.refresh || "60", 10) * 1000;
+
+  var root = document.createElement("span");
+  root.className = "exrate-widget";
+  var input = document.createElement("input");
+  input.type = "number";
+  input.step = "any";
+  input.min = "0";
+  input.value = script.dataset.amount || "1";
+  input.style.width = "6em";
+  var output = document.createElement("output");
+  root.appendChild(input);
+  root.appendChild(document.createTextNode(" " + from + " = "));
+  root.appendChild(output);
+  script.parentNode.insertBefore(root, script.nextSibling);
+
+  function update() {
+    var url = base + "/api/v1/widget/convert?from=" + encodeURIComponent(from) +
+      "&to=" + encodeURIComponent(to) + "&amount=" + encodeURIComponent(input.value || "1");
+    var headers = token ? { Authorization: "Bearer " + token } : {};
+    fetch(url, { headers: headers })
+      .then(function (resp) { return resp.json(); })
+      .then(function (data) {
+        if (data.error) throw new Error(data.error);
+        output.textContent = data.result.toFixed(2) + " " + to;
+        output.title = "Rate " + data.rate + " as of " + data.date;
+      })
+      .catch(function (err) {
+        output.textContent = "unavailable";
+        output.title = err.message;
+      });
+  }
+
+  input.addEventListener("input", update);
+  update();
+  if (refresh > 0) setInterval(update, refresh);
+})();
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	// webhookTolerance bounds clock skew and how long a captured push can be replayed
+	webhookTolerance   = 5 * time.Minute
+	maxWebhookBodySize = 1 << 20
+)
+
+// verifyWebhookSignature checks a signature of the form sha256=<hex>, computed
+// as HMAC-SHA256 over "<timestamp>.<body>" with the provider's shared secret
+func verifyWebhookSignature(secret string, timestamp string, body []byte, signature string) bool {
+	encoded, found := strings.CutPrefix(signature, "sha256=")
+	if !found {
+		return false
+	}
+	got, err := hex.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// providerWebhookHandler accepts rate pushes from providers that have a
+// shared secret in secrets, keyed by provider name
+func (h *Handler) providerWebhookHandler(secrets map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		secret, found := secrets[name]
+		if !found {
+			h.sendErrorResponse(w, http.StatusNotFound, "unknown provider")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+		if err != nil || len(body) > maxWebhookBodySize {
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+
+		timestamp := r.Header.Get(webhookTimestampHeader)
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			h.metrics.ProviderPushes.WithLabelValues(name, "rejected").Inc()
+			h.sendErrorResponse(w, http.StatusUnauthorized, "missing or invalid webhook timestamp")
+			return
+		}
+		if skew := time.Since(time.Unix(seconds, 0)); skew > webhookTolerance || skew < -webhookTolerance {
+			h.metrics.ProviderPushes.WithLabelValues(name, "rejected").Inc()
+			h.sendErrorResponse(w, http.StatusUnauthorized, "webhook timestamp outside tolerance")
+			return
+		}
+		if !verifyWebhookSignature(secret, timestamp, body, r.Header.Get(webhookSignatureHeader)) {
+			h.metrics.ProviderPushes.WithLabelValues(name, "rejected").Inc()
+			h.log.Warn("Rejected provider webhook with bad signature", "provider", name, "remote_addr", r.RemoteAddr)
+			h.sendErrorResponse(w, http.StatusUnauthorized, "invalid webhook signature")
+			return
+		}
+
+		var push model.ProviderPush
+		if err := json.Unmarshal(body, &push); err != nil || push.Base == "" || len(push.Rates) == 0 {
+			h.metrics.ProviderPushes.WithLabelValues(name, "invalid").Inc()
+			h.sendErrorResponse(w, http.StatusBadRequest, "invalid push payload")
+			return
+		}
+		push.Provider = name
+
+		result, err := h.service.ApplyProviderPush(r.Context(), push)
+		if err != nil {
+			h.metrics.ProviderPushes.WithLabelValues(name, "invalid").Inc()
+			h.handleServiceError(w, err)
+			return
+		}
+
+		h.metrics.ProviderPushes.WithLabelValues(name, "applied").Inc()
+		h.sendSuccessResponse(w, r, result)
+	}
+}
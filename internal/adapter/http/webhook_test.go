@@ -0,0 +1,41 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+
+	body := []byte(`{"base":"USD","rates":{"INR":83.1}}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	valid := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	testCases := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		signature string
+		expected  bool
+	}{
+		{name: "Valid", secret: "secret", timestamp: "1700000000", body: body, signature: valid, expected: true},
+		{name: "Wrong Secret", secret: "other", timestamp: "1700000000", body: body, signature: valid},
+		{name: "Wrong Timestamp", secret: "secret", timestamp: "1700000001", body: body, signature: valid},
+		{name: "Tampered Body", secret: "secret", timestamp: "1700000000", body: []byte(`{"base":"USD","rates":{"INR":90}}`), signature: valid},
+		{name: "Missing Prefix", secret: "secret", timestamp: "1700000000", body: body, signature: valid[len("sha256="):]},
+		{name: "Not Hex", secret: "secret", timestamp: "1700000000", body: body, signature: "sha256=zz"},
+		{name: "Empty", secret: "secret", timestamp: "1700000000", body: body, signature: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := verifyWebhookSignature(tc.secret, tc.timestamp, tc.body, tc.signature); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// jsonpCallback restricts callbacks to plain JavaScript identifiers so the
+// response cannot be turned into arbitrary script
+var jsonpCallback = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.]{0,63}$`)
+
+type widgetConversion struct {
+	From   model.Currency `json:"from"`
+	To     model.Currency `json:"to"`
+	Amount float64        `json:"amount"`
+	Result float64        `json:"result"`
+	Rate   float64        `json:"rate"`
+	Date   time.Time      `json:"date"`
+}
+
+// WidgetConvertHandler is a lightweight convert endpoint for embedded widgets.
+// It answers with a flat object, or JSONP when a callback is given. Widget
+// conversions are dry runs and leave no receipt.
+func (h *Handler) WidgetConvertHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	callback := query.Get("callback")
+	if callback != "" && !jsonpCallback.MatchString(callback) {
+		h.sendWidgetResponse(w, "", http.StatusBadRequest, map[string]string{"error": "invalid callback"})
+		return
+	}
+
+	from := model.Currency(query.Get("from"))
+	to := model.Currency(query.Get("to"))
+	if from == "" || to == "" {
+		h.sendWidgetResponse(w, callback, http.StatusBadRequest, map[string]string{"error": "missing required parameters: from and to"})
+		return
+	}
+
+	amount := 1.0
+	if amountStr := query.Get("amount"); amountStr != "" {
+		var err error
+		amount, err = parseAmount(amountStr, from)
+		if err != nil {
+			h.sendWidgetResponse(w, callback, http.StatusBadRequest, map[string]string{"error": "invalid amount parameter"})
+			return
+		}
+	}
+
+	result, err := h.service.ConvertCurrency(r.Context(), model.ConversionRequest{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Amount:       amount,
+		DryRun:       true,
+	})
+	if err != nil {
+		h.log.Error("Widget conversion failed", "error", err)
+		h.sendWidgetResponse(w, callback, http.StatusServiceUnavailable, map[string]string{"error": "conversion unavailable"})
+		return
+	}
+
+	h.sendWidgetResponse(w, callback, http.StatusOK, widgetConversion{
+		From:   result.FromCurrency,
+		To:     result.ToCurrency,
+		Amount: result.FromAmount,
+		Result: result.ToAmount,
+		Rate:   result.Rate,
+		Date:   result.Date,
+	})
+}
+
+// sendWidgetResponse writes JSON, or JSONP when callback is set. JSONP always
+// answers 200 because a script tag cannot read the status code.
+func (h *Handler) sendWidgetResponse(w http.ResponseWriter, callback string, statusCode int, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		h.log.Error("Failed to encode widget response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if callback == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		w.Write(payload)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/javascript")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write([]byte("/**/" + callback + "("))
+	w.Write(payload)
+	w.Write([]byte(");"))
+}
+
+// widgetOrigins lets widget endpoints be called from the allowed origins,
+// either with CORS or, for JSONP, by checking the Referer. An empty list
+// allows every origin.
+func widgetOrigins(allowed []string, next http.Handler) http.Handler {
+	allows := func(origin string) bool {
+		if len(allowed) == 0 {
+			return true
+		}
+		for _, a := range allowed {
+			if a == origin {
+				return true
+			}
+		}
+		return false
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			if referer, err := url.Parse(r.Referer()); err == nil && referer.Host != "" {
+				origin = referer.Scheme + "://" + referer.Host
+			}
+		}
+
+		if origin != "" && !allows(origin) {
+			writeForbiddenOrigin(w)
+			return
+		}
+
+		if origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Vary", "Origin")
+		}
+
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, X-API-Key, X-Envelope")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeForbiddenOrigin(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{"error": "origin not allowed"})
+}
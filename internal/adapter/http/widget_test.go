@@ -0,0 +1,167 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWidgetOriginsAndJSONP(t *testing.T) {
+	router := newGoldenRouter(t)
+	router.AllowWidgetOrigins([]string{"https://shop.example.com"})
+	handler := router.SetupRoutes()
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		origin      string
+		referer     string
+		status      int
+		allowOrigin string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "allowed origin",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&to=INR",
+			origin:      "https://shop.example.com",
+			status:      http.StatusOK,
+			allowOrigin: "https://shop.example.com",
+			contentType: "application/json",
+			body:        `"rate":83.12`,
+		},
+		{
+			name:   "denied origin",
+			method: "GET",
+			target: "/api/v1/widget/convert?from=USD&to=INR",
+			origin: "https://evil.example.com",
+			status: http.StatusForbidden,
+			body:   "origin not allowed",
+		},
+		{
+			name:   "origin differing only in scheme",
+			method: "GET",
+			target: "/api/v1/widget/convert?from=USD&to=INR",
+			origin: "http://shop.example.com",
+			status: http.StatusForbidden,
+		},
+		{
+			name:        "preflight from an allowed origin",
+			method:      "OPTIONS",
+			target:      "/api/v1/widget/convert",
+			origin:      "https://shop.example.com",
+			status:      http.StatusNoContent,
+			allowOrigin: "https://shop.example.com",
+		},
+		{
+			name:   "preflight from a denied origin",
+			method: "OPTIONS",
+			target: "/api/v1/widget/convert",
+			origin: "https://evil.example.com",
+			status: http.StatusForbidden,
+		},
+		{
+			name:        "JSONP from an allowed referer",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&to=INR&amount=2&callback=rates.update_1",
+			referer:     "https://shop.example.com/checkout?step=2",
+			status:      http.StatusOK,
+			allowOrigin: "https://shop.example.com",
+			contentType: "application/javascript",
+			body:        "/**/rates.update_1({",
+		},
+		{
+			name:    "JSONP from a denied referer",
+			method:  "GET",
+			target:  "/api/v1/widget/convert?from=USD&to=INR&callback=update",
+			referer: "https://evil.example.com/page",
+			status:  http.StatusForbidden,
+		},
+		{
+			name:        "JSONP error answered with 200",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&callback=update",
+			status:      http.StatusOK,
+			contentType: "application/javascript",
+			body:        `/**/update({"error":"missing required parameters: from and to"});`,
+		},
+		{
+			name:        "callback with markup",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&to=INR&callback=%3Cscript%3Ealert(1)",
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+			body:        "invalid callback",
+		},
+		{
+			name:        "callback calling a function",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&to=INR&callback=alert(1)",
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name:        "callback starting with a digit",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&to=INR&callback=1update",
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name:        "callback longer than 64 characters",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&to=INR&callback=" + strings.Repeat("a", 65),
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+		},
+		{
+			name:        "no origin",
+			method:      "GET",
+			target:      "/api/v1/widget/convert?from=USD&to=INR",
+			status:      http.StatusOK,
+			contentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.referer != "" {
+				req.Header.Set("Referer", tt.referer)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, recorder.Code, recorder.Body)
+			}
+			if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.allowOrigin, got)
+			}
+			if tt.contentType != "" && recorder.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, recorder.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(recorder.Body.String(), tt.body) {
+				t.Errorf("Expected the body to contain %s, got %s", tt.body, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestWidgetOriginsAllowEveryOriginByDefault(t *testing.T) {
+	handler := newGoldenRouter(t).SetupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/v1/widget/convert?from=USD&to=INR", nil)
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Access-Control-Allow-Origin") != "https://anywhere.example.com" {
+		t.Errorf("Expected any origin to be allowed without a list, got %d, %q", recorder.Code, recorder.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
@@ -0,0 +1,142 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MQTT 3.1.1 control packet types
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+const (
+	protocolLevel = 4
+
+	flagUsername     = 0x80
+	flagPassword     = 0x40
+	flagCleanSession = 0x02
+
+	publishRetain = 0x01
+
+	// maxPacketSize bounds packets read from the broker, which only sends
+	// acknowledgements to a publisher
+	maxPacketSize = 1 << 10
+)
+
+var errMalformed = errors.New("malformed MQTT packet")
+
+// connackErrors maps CONNACK return codes to their meaning
+var connackErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+type packet struct {
+	typ   byte
+	flags byte
+	body  []byte
+}
+
+func (p packet) encode() []byte {
+	buf := []byte{p.typ<<4 | p.flags}
+	buf = appendLength(buf, len(p.body))
+	return append(buf, p.body...)
+}
+
+// appendLength appends the variable length encoding of the remaining length
+func appendLength(buf []byte, n int) []byte {
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if n == 0 {
+			return buf
+		}
+	}
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(s)))
+	return append(buf, s...)
+}
+
+func readPacket(r *bufio.Reader) (packet, error) {
+	first, err := r.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return packet{}, fmt.Errorf("%w: remaining length too long", errMalformed)
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return packet{}, err
+		}
+		length += int(b&0x7F) * multiplier
+		multiplier *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if length > maxPacketSize {
+		return packet{}, fmt.Errorf("%w: packet of %d bytes", errMalformed, length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return packet{}, err
+	}
+	return packet{typ: first >> 4, flags: first & 0x0F, body: body}, nil
+}
+
+func connectPacket(clientID, username, password string, keepAlive uint16) packet {
+	flags := byte(flagCleanSession)
+	if username != "" {
+		flags |= flagUsername
+		if password != "" {
+			flags |= flagPassword
+		}
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, protocolLevel, flags)
+	body = binary.BigEndian.AppendUint16(body, keepAlive)
+	body = appendString(body, clientID)
+	if flags&flagUsername != 0 {
+		body = appendString(body, username)
+	}
+	if flags&flagPassword != 0 {
+		body = appendString(body, password)
+	}
+	return packet{typ: packetConnect, body: body}
+}
+
+// publishPacket builds a retained PUBLISH. The packet ID is only sent for
+// QoS 1.
+func publishPacket(topic string, payload []byte, qos byte, id uint16) packet {
+	body := appendString(nil, topic)
+	if qos > 0 {
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	body = append(body, payload...)
+	return packet{typ: packetPublish, flags: qos<<1 | publishRetain, body: body}
+}
@@ -0,0 +1,320 @@
+// Package mqtt publishes the latest rates to an MQTT 3.1.1 broker as retained
+// messages, one topic per pair, so display boards get the current rate as soon
+// as they subscribe.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+// connectTimeout bounds the CONNECT/CONNACK exchange after dialing
+const connectTimeout = 10 * time.Second
+
+var errConnectionClosed = errors.New("MQTT connection closed")
+
+// connection is a broker connection. A reader goroutine delivers PUBACKs and
+// a pinger keeps the session alive; once the connection fails, done is closed
+// and err holds the cause.
+type connection struct {
+	conn      net.Conn
+	writeMu   sync.Mutex
+	keepAlive time.Duration
+
+	// nextID is the packet ID of the next QoS 1 publish, guarded by the
+	// publisher
+	nextID uint16
+	acks   chan uint16
+
+	done     chan struct{}
+	failOnce sync.Once
+	err      error
+}
+
+func dial(ctx context.Context, cfg config.MQTTConfig) (*connection, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT URL: %w", err)
+	}
+
+	port := "1883"
+	switch u.Scheme {
+	case "mqtt", "tcp":
+	case "mqtts", "ssl":
+		port = "8883"
+	default:
+		return nil, fmt.Errorf("unsupported MQTT URL scheme %q", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if port == "8883" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	var username, password string
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+	}
+
+	deadline := time.Now().Add(connectTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	keepAlive := uint16(cfg.KeepAlive / time.Second)
+	if _, err := conn.Write(connectPacket(cfg.ClientID, username, password, keepAlive).encode()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	connack, err := readPacket(reader)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if connack.typ != packetConnack || len(connack.body) != 2 {
+		conn.Close()
+		return nil, fmt.Errorf("%w: expected CONNACK", errMalformed)
+	}
+	if code := connack.body[1]; code != 0 {
+		conn.Close()
+		if reason, known := connackErrors[code]; known {
+			return nil, fmt.Errorf("broker refused connection: %s", reason)
+		}
+		return nil, fmt.Errorf("broker refused connection with code %d", code)
+	}
+	conn.SetDeadline(time.Time{})
+
+	c := &connection{
+		conn:      conn,
+		keepAlive: time.Duration(keepAlive) * time.Second,
+		nextID:    1,
+		acks:      make(chan uint16, 64),
+		done:      make(chan struct{}),
+	}
+	go c.readLoop(reader)
+	if c.keepAlive > 0 {
+		go c.pingLoop()
+	}
+	return c, nil
+}
+
+func (c *connection) write(p packet) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(p.encode())
+	return err
+}
+
+// publish sends a retained message and returns the packet ID to wait for, or 0
+// for QoS 0
+func (c *connection) publish(topic string, payload []byte, qos byte) (uint16, error) {
+	var id uint16
+	if qos > 0 {
+		id = c.nextID
+		// Packet IDs are non-zero and wrap around
+		c.nextID++
+		if c.nextID == 0 {
+			c.nextID = 1
+		}
+	}
+	return id, c.write(publishPacket(topic, payload, qos, id))
+}
+
+func (c *connection) readLoop(r *bufio.Reader) {
+	for {
+		if c.keepAlive > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(c.keepAlive * 3 / 2))
+		}
+		p, err := readPacket(r)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+
+		switch p.typ {
+		case packetPuback:
+			if len(p.body) != 2 {
+				c.fail(fmt.Errorf("%w: bad PUBACK", errMalformed))
+				return
+			}
+			select {
+			case c.acks <- binary.BigEndian.Uint16(p.body):
+			case <-c.done:
+				return
+			}
+		case packetPingresp:
+		default:
+			c.fail(fmt.Errorf("%w: unexpected packet type %d", errMalformed, p.typ))
+			return
+		}
+	}
+}
+
+func (c *connection) pingLoop() {
+	ticker := time.NewTicker(c.keepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.write(packet{typ: packetPingreq}); err != nil {
+				c.fail(err)
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// fail records the first error, closes the socket and wakes up waiters
+func (c *connection) fail(err error) {
+	c.failOnce.Do(func() {
+		c.err = err
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// Publisher implements ports.RatePublisher. It connects lazily and reconnects
+// on the next publish after a failure.
+type Publisher struct {
+	cfg config.MQTTConfig
+	log *logger.Logger
+
+	mutex sync.Mutex
+	conn  *connection
+}
+
+func NewPublisher(cfg config.MQTTConfig, log *logger.Logger) *Publisher {
+	return &Publisher{cfg: cfg, log: log}
+}
+
+// topic returns the topic of a pair, e.g. rates/USD/INR
+func (p *Publisher) topic(rate model.ExchangeRate) string {
+	return strings.TrimSuffix(p.cfg.TopicPrefix, "/") + "/" + string(rate.BaseCurrency) + "/" + string(rate.TargetCurrency)
+}
+
+// Publish sends each rate as a retained JSON message and, with QoS 1, waits
+// for the broker to acknowledge them all
+func (p *Publisher) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	conn, err := p.connection(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	pending := make(map[uint16]bool, len(rates))
+	for _, rate := range rates {
+		payload, err := json.Marshal(rate)
+		if err != nil {
+			return err
+		}
+		id, err := conn.publish(p.topic(rate), payload, p.cfg.QoS)
+		if err != nil {
+			p.drop(err)
+			return fmt.Errorf("failed to publish to MQTT broker: %w", err)
+		}
+		if id != 0 {
+			pending[id] = true
+		}
+	}
+
+	timer := time.NewTimer(p.cfg.AckTimeout)
+	defer timer.Stop()
+
+	for len(pending) > 0 {
+		select {
+		case id := <-conn.acks:
+			delete(pending, id)
+		case <-conn.done:
+			p.conn = nil
+			return fmt.Errorf("MQTT connection lost with %d unacknowledged messages: %w", len(pending), conn.err)
+		case <-timer.C:
+			// Late acknowledgements could be mistaken for the next batch's
+			p.drop(fmt.Errorf("acknowledgement timeout"))
+			return fmt.Errorf("%d messages unacknowledged after %s", len(pending), p.cfg.AckTimeout)
+		case <-ctx.Done():
+			p.drop(ctx.Err())
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// connection returns the open connection, dialing a new one if there is none
+// or the last one failed. The caller holds the mutex.
+func (p *Publisher) connection(ctx context.Context) (*connection, error) {
+	if p.conn != nil {
+		select {
+		case <-p.conn.done:
+			p.log.Warn("MQTT connection lost, reconnecting", "error", p.conn.err)
+			p.conn = nil
+		default:
+			return p.conn, nil
+		}
+	}
+
+	conn, err := dial(ctx, p.cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.log.Info("Connected to MQTT broker", "client_id", p.cfg.ClientID, "topic_prefix", p.cfg.TopicPrefix)
+	p.conn = conn
+	return conn, nil
+}
+
+// drop closes the connection after a publishing error. The caller holds the
+// mutex.
+func (p *Publisher) drop(err error) {
+	if p.conn != nil {
+		p.conn.fail(err)
+		p.conn = nil
+	}
+}
+
+// Close disconnects from the broker, if connected
+func (p *Publisher) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.conn != nil {
+		p.conn.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		p.conn.write(packet{typ: packetDisconnect})
+		p.conn.fail(errConnectionClosed)
+		p.conn = nil
+	}
+	return nil
+}
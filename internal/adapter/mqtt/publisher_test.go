@@ -0,0 +1,108 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+type message struct {
+	topic    string
+	retained bool
+	payload  string
+}
+
+// fakeBroker accepts one connection, answers CONNACK with code and
+// acknowledges every publish
+func fakeBroker(t *testing.T, code byte) (string, <-chan message) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	messages := make(chan message, 16)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+
+		if p, err := readPacket(r); err != nil || p.typ != packetConnect {
+			return
+		}
+		conn.Write(packet{typ: packetConnack, body: []byte{0, code}}.encode())
+
+		for {
+			p, err := readPacket(r)
+			if err != nil || p.typ != packetPublish {
+				return
+			}
+			n := int(binary.BigEndian.Uint16(p.body))
+			topic, rest := string(p.body[2:2+n]), p.body[2+n:]
+			if qos := p.flags >> 1 & 3; qos == 1 {
+				conn.Write(packet{typ: packetPuback, body: rest[:2]}.encode())
+				rest = rest[2:]
+			}
+			messages <- message{topic: topic, retained: p.flags&publishRetain != 0, payload: string(rest)}
+		}
+	}()
+
+	return l.Addr().String(), messages
+}
+
+func testConfig(addr string) config.MQTTConfig {
+	return config.MQTTConfig{
+		URL:         "mqtt://display:secret@" + addr,
+		ClientID:    "test",
+		TopicPrefix: "rates/",
+		QoS:         1,
+		KeepAlive:   time.Minute,
+		AckTimeout:  time.Second,
+	}
+}
+
+func TestPublish(t *testing.T) {
+	addr, messages := fakeBroker(t, 0)
+
+	publisher := NewPublisher(testConfig(addr), logger.NewLogger("error"))
+	defer publisher.Close()
+
+	rates := []model.ExchangeRate{
+		{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.12},
+		{BaseCurrency: model.EUR, TargetCurrency: model.USD, Rate: 1.08},
+	}
+	if err := publisher.Publish(context.Background(), rates); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	for _, topic := range []string{"rates/USD/INR", "rates/EUR/USD"} {
+		m := <-messages
+		if m.topic != topic || !m.retained || m.payload == "" {
+			t.Errorf("Expected a retained message on %s, got %+v", topic, m)
+		}
+	}
+}
+
+func TestPublishRefused(t *testing.T) {
+	addr, _ := fakeBroker(t, 4)
+
+	publisher := NewPublisher(testConfig(addr), logger.NewLogger("error"))
+	defer publisher.Close()
+
+	err := publisher.Publish(context.Background(), []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.12}})
+	if err == nil {
+		t.Fatal("Expected an error when the broker refuses the connection")
+	}
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+)
+
+// AggregateProviderName identifies rates combined from several providers
+const AggregateProviderName = "aggregate"
+
+// Strategies a ProviderAggregate combines rates with
+const (
+	AggregateMedian   = "median"
+	AggregateWeighted = "weighted"
+)
+
+// ProviderAggregateOptions tunes how a ProviderAggregate combines rates
+type ProviderAggregateOptions struct {
+	// Strategy is AggregateMedian or AggregateWeighted
+	Strategy string
+	// Weights of the providers in a weighted average, by name; providers not
+	// listed weigh 1
+	Weights map[string]float64
+	// Timeout bounds each call to a provider; 0 leaves it to the provider
+	Timeout time.Duration
+}
+
+// ProviderAggregate asks every provider at once and serves the median, or a
+// weighted average, of the rates they answer with, so that a single bad feed
+// cannot move conversions on its own. Each rate names its Sources. Providers
+// failing or lacking a quote are left out; only when none answers does the
+// call fail.
+type ProviderAggregate struct {
+	providers []ports.RateRepository
+	opts      ProviderAggregateOptions
+	log       *logger.Logger
+}
+
+// providerRate is the answer of one provider
+type providerRate struct {
+	provider string
+	rate     *model.ExchangeRate
+}
+
+func NewProviderAggregate(providers []ports.RateRepository, opts ProviderAggregateOptions, log *logger.Logger) (*ProviderAggregate, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("a provider aggregate needs at least one provider")
+	}
+	switch opts.Strategy {
+	case AggregateMedian, AggregateWeighted:
+	default:
+		return nil, fmt.Errorf("unknown aggregation %q, use %s or %s", opts.Strategy, AggregateMedian, AggregateWeighted)
+	}
+	for name, weight := range opts.Weights {
+		if weight <= 0 {
+			return nil, fmt.Errorf("weight of provider %s must be positive", name)
+		}
+	}
+
+	return &ProviderAggregate{providers: providers, opts: opts, log: log}, nil
+}
+
+func (a *ProviderAggregate) Name() string {
+	return AggregateProviderName
+}
+
+func (a *ProviderAggregate) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	return a.fetchRate(ctx, func(ctx context.Context, provider ports.RateRepository) (*model.ExchangeRate, error) {
+		return provider.FetchLatestRate(ctx, pair)
+	})
+}
+
+func (a *ProviderAggregate) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	return a.fetchRate(ctx, func(ctx context.Context, provider ports.RateRepository) (*model.ExchangeRate, error) {
+		return provider.FetchHistoricalRate(ctx, pair, date)
+	})
+}
+
+// FetchHistoricalRates combines the ranges of the providers date by date. A
+// date no provider has a rate of keeps the error of the first that failed it.
+func (a *ProviderAggregate) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	ranges := make([]*model.HistoricalRates, len(a.providers))
+	errs := a.each(ctx, func(ctx context.Context, i int, provider ports.RateRepository) (err error) {
+		ranges[i], err = provider.FetchHistoricalRates(ctx, request)
+		return err
+	})
+
+	byDate := make(map[string][]providerRate)
+	itemErrors := make(map[string]model.ItemError)
+	answered := false
+	for i, rates := range ranges {
+		if errs[i] != nil {
+			continue
+		}
+		answered = true
+		for date, rate := range rates.Rates {
+			byDate[date] = append(byDate[date], providerRate{provider: a.providers[i].Name(), rate: &rate})
+		}
+		for _, itemErr := range rates.Errors {
+			if _, exists := itemErrors[itemErr.Item]; !exists {
+				itemErrors[itemErr.Item] = itemErr
+			}
+		}
+	}
+	if !answered {
+		return nil, errors.Join(errs...)
+	}
+
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate, len(byDate)),
+	}
+	for date, answers := range byDate {
+		result.Rates[date] = *a.combine(answers)
+	}
+	for date, itemErr := range itemErrors {
+		if _, found := result.Rates[date]; !found {
+			result.Errors = append(result.Errors, itemErr)
+		}
+	}
+	sort.Slice(result.Errors, func(i, j int) bool { return result.Errors[i].Item < result.Errors[j].Item })
+	return result, nil
+}
+
+// RefreshRates refreshes every provider. It succeeds when any provider did,
+// and returns ports.ErrRatesUnchanged when the others all failed or were
+// unchanged too.
+func (a *ProviderAggregate) RefreshRates(ctx context.Context) error {
+	errs := a.each(ctx, func(ctx context.Context, i int, provider ports.RateRepository) error {
+		return provider.RefreshRates(ctx)
+	})
+
+	unchanged := false
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, ports.ErrRatesUnchanged):
+			unchanged = true
+		}
+	}
+	if unchanged {
+		return ports.ErrRatesUnchanged
+	}
+	return errors.Join(errs...)
+}
+
+// LatestRates combines the rates the providers hold, pair by pair
+func (a *ProviderAggregate) LatestRates(ctx context.Context) []model.ExchangeRate {
+	byPair := make(map[string][]providerRate)
+	for _, provider := range a.providers {
+		for _, rate := range provider.LatestRates(ctx) {
+			pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+			byPair[pair.String()] = append(byPair[pair.String()], providerRate{provider: provider.Name(), rate: &rate})
+		}
+	}
+
+	pairs := make([]string, 0, len(byPair))
+	for pair := range byPair {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+
+	rates := make([]model.ExchangeRate, 0, len(pairs))
+	for _, pair := range pairs {
+		rates = append(rates, *a.combine(byPair[pair]))
+	}
+	return rates
+}
+
+// fetchRate asks every provider for a rate with fetch and combines the answers
+func (a *ProviderAggregate) fetchRate(ctx context.Context, fetch func(ctx context.Context, provider ports.RateRepository) (*model.ExchangeRate, error)) (*model.ExchangeRate, error) {
+	rates := make([]*model.ExchangeRate, len(a.providers))
+	errs := a.each(ctx, func(ctx context.Context, i int, provider ports.RateRepository) (err error) {
+		rates[i], err = fetch(ctx, provider)
+		return err
+	})
+
+	answers := make([]providerRate, 0, len(a.providers))
+	for i, rate := range rates {
+		if errs[i] == nil {
+			answers = append(answers, providerRate{provider: a.providers[i].Name(), rate: rate})
+		}
+	}
+	if len(answers) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return a.combine(answers), nil
+}
+
+// each calls fn on every provider at once and returns their errors, in the
+// order of the providers. Failures other than missing quotes are logged.
+func (a *ProviderAggregate) each(ctx context.Context, fn func(ctx context.Context, i int, provider ports.RateRepository) error) []error {
+	errs := make([]error, len(a.providers))
+	var wg sync.WaitGroup
+	for i, provider := range a.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = a.call(ctx, i, provider, fn)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, ports.ErrQuoteNotFound) && !errors.Is(err, ports.ErrRatesUnchanged) {
+			a.log.Warn("Provider left out of the aggregate", "provider", a.providers[i].Name(), "error", err)
+		}
+	}
+	return errs
+}
+
+func (a *ProviderAggregate) call(ctx context.Context, i int, provider ports.RateRepository, fn func(ctx context.Context, i int, provider ports.RateRepository) error) error {
+	if a.opts.Timeout <= 0 {
+		return fn(ctx, i, provider)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.opts.Timeout)
+	defer cancel()
+	err := fn(ctx, i, provider)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s: %w", provider.Name(), a.opts.Timeout, err)
+	}
+	return err
+}
+
+// combine returns the rate of the first answer with the rate of all of them,
+// the oldest LastUpdated and the providers used as Sources
+func (a *ProviderAggregate) combine(answers []providerRate) *model.ExchangeRate {
+	combined := *answers[0].rate
+	combined.Sources = make([]string, 0, len(answers))
+	values := make([]float64, 0, len(answers))
+	weighted, totalWeight := 0.0, 0.0
+	for _, answer := range answers {
+		combined.Sources = append(combined.Sources, answer.provider)
+		values = append(values, answer.rate.Rate)
+
+		weight, exists := a.opts.Weights[answer.provider]
+		if !exists {
+			weight = 1
+		}
+		weighted += weight * answer.rate.Rate
+		totalWeight += weight
+
+		if answer.rate.LastUpdated.Before(combined.LastUpdated) {
+			combined.LastUpdated = answer.rate.LastUpdated
+		}
+	}
+	sort.Strings(combined.Sources)
+
+	switch a.opts.Strategy {
+	case AggregateWeighted:
+		combined.Rate = weighted / totalWeight
+	default:
+		combined.Rate = median(values)
+	}
+	return &combined
+}
+
+// median of values, the mean of the middle two for an even count
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestProviderAggregateCombinesRates(t *testing.T) {
+	var noErr, badErr error
+	providers := []ports.RateRepository{
+		chainProvider("a", 83, &noErr),
+		chainProvider("b", 84, &noErr),
+		chainProvider("c", 830, &badErr),
+	}
+	ctx := context.Background()
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+
+	median, err := NewProviderAggregate(providers, ProviderAggregateOptions{Strategy: AggregateMedian}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewProviderAggregate failed: %v", err)
+	}
+	// A bad feed does not move the median
+	rate, err := median.FetchLatestRate(ctx, pair)
+	if err != nil || rate.Rate != 84 {
+		t.Fatalf("Expected the median 84, got %v, %v", rate, err)
+	}
+	if !slices.Equal(rate.Sources, []string{"a", "b", "c"}) {
+		t.Errorf("Sources = %v, want a, b and c", rate.Sources)
+	}
+
+	// A failing provider is left out, and two rates give their mean
+	badErr = errors.New("upstream down")
+	rate, err = median.FetchLatestRate(ctx, pair)
+	if err != nil || rate.Rate != 83.5 || !slices.Equal(rate.Sources, []string{"a", "b"}) {
+		t.Errorf("Expected 83.5 from a and b, got %v, %v", rate, err)
+	}
+
+	weighted, err := NewProviderAggregate(providers, ProviderAggregateOptions{
+		Strategy: AggregateWeighted,
+		Weights:  map[string]float64{"b": 3},
+	}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewProviderAggregate failed: %v", err)
+	}
+	if rate, err := weighted.FetchLatestRate(ctx, pair); err != nil || rate.Rate != (83+3*84)/4.0 {
+		t.Errorf("Expected the weighted average %v, got %v, %v", (83+3*84)/4.0, rate, err)
+	}
+
+	// Only when no provider answers does the call fail
+	noErr = ports.ErrQuoteNotFound
+	if _, err := median.FetchLatestRate(ctx, pair); !errors.Is(err, ports.ErrQuoteNotFound) {
+		t.Errorf("Expected ErrQuoteNotFound, got %v", err)
+	}
+}
+
+func TestProviderAggregateHistoricalRanges(t *testing.T) {
+	ranged := func(name string, rates map[string]float64, failed ...string) *mocks.RateRepositoryMock {
+		return &mocks.RateRepositoryMock{
+			NameFunc: func() string { return name },
+			FetchHistoricalRatesFunc: func(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+				result := &model.HistoricalRates{Rates: make(map[string]model.ExchangeRate)}
+				for date, rate := range rates {
+					result.Rates[date] = model.ExchangeRate{Rate: rate}
+				}
+				for _, date := range failed {
+					result.Errors = append(result.Errors, model.NewItemError(date, errors.New("no rate")))
+				}
+				return result, nil
+			},
+		}
+	}
+	aggregate, err := NewProviderAggregate([]ports.RateRepository{
+		ranged("a", map[string]float64{"2025-03-03": 80, "2025-03-04": 81}, "2025-03-05"),
+		ranged("b", map[string]float64{"2025-03-03": 82}, "2025-03-04", "2025-03-05"),
+	}, ProviderAggregateOptions{Strategy: AggregateMedian}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewProviderAggregate failed: %v", err)
+	}
+
+	rates, err := aggregate.FetchHistoricalRates(context.Background(), model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("FetchHistoricalRates failed: %v", err)
+	}
+	if got := rates.Rates["2025-03-03"].Rate; got != 81 {
+		t.Errorf("Expected the median 81 on 2025-03-03, got %v", got)
+	}
+	if got := rates.Rates["2025-03-04"]; got.Rate != 81 || !slices.Equal(got.Sources, []string{"a"}) {
+		t.Errorf("Expected a's 81 on 2025-03-04, got %+v", got)
+	}
+	if len(rates.Errors) != 1 || rates.Errors[0].Item != "2025-03-05" {
+		t.Errorf("Expected only 2025-03-05 to fail, got %+v", rates.Errors)
+	}
+}
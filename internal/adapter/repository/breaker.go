@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// States of a CircuitBreaker
+const (
+	BreakerClosed   = "closed"
+	BreakerHalfOpen = "half_open"
+	BreakerOpen     = "open"
+)
+
+// ErrCircuitOpen is returned for calls a CircuitBreaker does not let through
+var ErrCircuitOpen = xerrors.New(xerrors.Upstream, "circuit_open", "provider circuit open, call short-circuited")
+
+// CircuitBreakerOptions tunes when a CircuitBreaker opens and closes
+type CircuitBreakerOptions struct {
+	// Failures in a row open the circuit
+	Failures int
+	// OpenFor is how long the circuit stays open before trial calls
+	OpenFor time.Duration
+	// HalfOpenSuccesses trial calls in a row close the circuit again
+	HalfOpenSuccesses int
+}
+
+// CircuitBreaker stops calling a provider that keeps failing, so requests
+// fail at once instead of each waiting for the timeout. After Failures calls
+// in a row fail, the circuit opens for OpenFor, or for as long as a provider
+// rate limiting calls asked when that is longer. Latest rates are then served
+// from those the provider held from its last refresh, however old, and every
+// other call fails with ErrCircuitOpen. Once OpenFor has passed, the circuit
+// is half open: one trial call at a time goes through, and HalfOpenSuccesses
+// of them in a row close it, while a failure opens it again. Missing quotes
+// and calls the caller gave up on count neither way.
+type CircuitBreaker struct {
+	next     ports.RateRepository
+	opts     CircuitBreakerOptions
+	clock    clock.Clock
+	log      *logger.Logger
+	onChange func(provider, state string)
+
+	mutex       sync.Mutex
+	state       string
+	consecutive int
+	successes   int
+	trial       bool
+	openUntil   time.Time
+}
+
+func NewCircuitBreaker(next ports.RateRepository, opts CircuitBreakerOptions, log *logger.Logger) *CircuitBreaker {
+	opts.Failures = max(opts.Failures, 1)
+	opts.HalfOpenSuccesses = max(opts.HalfOpenSuccesses, 1)
+
+	return &CircuitBreaker{
+		next:  next,
+		opts:  opts,
+		clock: clock.System,
+		log:   log,
+		state: BreakerClosed,
+	}
+}
+
+// UseClock replaces the wall clock the open period is measured on
+func (b *CircuitBreaker) UseClock(clock clock.Clock) {
+	b.clock = clock
+}
+
+// OnChange registers fn to be called with the new state whenever the circuit
+// changes state. It must be set before the breaker is used.
+func (b *CircuitBreaker) OnChange(fn func(provider, state string)) {
+	b.onChange = fn
+}
+
+// State returns the current state of the circuit. An open circuit whose open
+// period has passed is half open.
+func (b *CircuitBreaker) State() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == BreakerOpen && !b.clock.Now().Before(b.openUntil) {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+func (b *CircuitBreaker) Name() string {
+	return b.next.Name()
+}
+
+// FetchLatestRate serves the rate held from the last refresh while the
+// circuit is open, and fails only when there is none
+func (b *CircuitBreaker) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	var rate *model.ExchangeRate
+	err := b.call(ctx, func() (err error) {
+		rate, err = b.next.FetchLatestRate(ctx, pair)
+		return err
+	})
+	if errors.Is(err, ErrCircuitOpen) {
+		for _, held := range b.next.LatestRates(ctx) {
+			if held.BaseCurrency == pair.BaseCurrency && held.TargetCurrency == pair.TargetCurrency {
+				return &held, nil
+			}
+		}
+	}
+	return rate, err
+}
+
+func (b *CircuitBreaker) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	var rate *model.ExchangeRate
+	err := b.call(ctx, func() (err error) {
+		rate, err = b.next.FetchHistoricalRate(ctx, pair, date)
+		return err
+	})
+	return rate, err
+}
+
+func (b *CircuitBreaker) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	var rates *model.HistoricalRates
+	err := b.call(ctx, func() (err error) {
+		rates, err = b.next.FetchHistoricalRates(ctx, request)
+		return err
+	})
+	return rates, err
+}
+
+func (b *CircuitBreaker) RefreshRates(ctx context.Context) error {
+	return b.call(ctx, func() error {
+		return b.next.RefreshRates(ctx)
+	})
+}
+
+// RefreshPairs passes a partial refresh on when the wrapped repository
+// supports one, and refreshes every pair otherwise
+func (b *CircuitBreaker) RefreshPairs(ctx context.Context, pairs []model.CurrencyPair) error {
+	return b.call(ctx, func() error {
+		if refresher, ok := b.next.(ports.PairRefresher); ok {
+			return refresher.RefreshPairs(ctx, pairs)
+		}
+		return b.next.RefreshRates(ctx)
+	})
+}
+
+func (b *CircuitBreaker) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return b.next.LatestRates(ctx)
+}
+
+// call runs fn when the circuit lets it through, and records its outcome
+func (b *CircuitBreaker) call(ctx context.Context, fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	switch {
+	case err == nil || errors.Is(err, ports.ErrRatesUnchanged) || errors.Is(err, ports.ErrQuoteNotFound):
+		b.succeeded()
+	case ctx.Err() != nil:
+		// The caller gave up; that says nothing about the provider
+		b.abandoned()
+	default:
+		b.failed(err)
+	}
+	return err
+}
+
+// allow returns nil when a call may go through, and the error to fail it
+// with otherwise. It takes the trial slot of a half-open circuit.
+func (b *CircuitBreaker) allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.clock.Now()
+	switch b.state {
+	case BreakerOpen:
+		if now.Before(b.openUntil) {
+			return b.openError(b.openUntil.Sub(now))
+		}
+		b.setState(BreakerHalfOpen)
+		b.successes = 0
+	case BreakerHalfOpen:
+		if b.trial {
+			return b.openError(0)
+		}
+	default:
+		return nil
+	}
+
+	b.trial = true
+	return nil
+}
+
+func (b *CircuitBreaker) openError(retryAfter time.Duration) error {
+	return fmt.Errorf("%w: %w", ErrCircuitOpen, &xerrors.Error{
+		Kind:       xerrors.Upstream,
+		Code:       ErrCircuitOpen.Code,
+		Message:    b.next.Name() + " is not called until its circuit closes",
+		Provider:   b.next.Name(),
+		RetryAfter: retryAfter,
+	})
+}
+
+func (b *CircuitBreaker) succeeded() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.consecutive = 0
+	if b.state != BreakerHalfOpen {
+		return
+	}
+
+	b.trial = false
+	b.successes++
+	if b.successes >= b.opts.HalfOpenSuccesses {
+		b.setState(BreakerClosed)
+		b.log.Info("Provider circuit closed", "provider", b.next.Name())
+	}
+}
+
+func (b *CircuitBreaker) abandoned() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.trial = false
+}
+
+func (b *CircuitBreaker) failed(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.consecutive++
+	openFor := b.opts.OpenFor
+	limited, ok := xerrors.Find(err)
+	rateLimited := ok && limited.Kind == xerrors.RateLimited
+	if rateLimited {
+		openFor = max(limited.RetryAfter, openFor)
+	}
+
+	// A failed trial, a provider rate limiting calls or one failure too many
+	if b.state == BreakerHalfOpen || rateLimited || b.consecutive >= b.opts.Failures {
+		b.trial = false
+		b.openUntil = b.clock.Now().Add(openFor)
+		if b.state != BreakerOpen {
+			b.log.Error("Provider circuit opened", "provider", b.next.Name(), "retry_in", openFor, "error", err)
+		}
+		b.setState(BreakerOpen)
+	}
+}
+
+// setState moves the circuit to state. The caller holds mutex.
+func (b *CircuitBreaker) setState(state string) {
+	if state == b.state {
+		return
+	}
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(b.next.Name(), state)
+	}
+}
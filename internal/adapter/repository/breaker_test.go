@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestCircuitBreakerOpensAndCloses(t *testing.T) {
+	var providerErr error
+	usdEUR := model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.EUR, Rate: 0.92}
+	provider := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+		FetchHistoricalRateFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+			if providerErr != nil {
+				return nil, providerErr
+			}
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 83}, nil
+		},
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return nil, providerErr
+		},
+		LatestRatesFunc: func(ctx context.Context) []model.ExchangeRate {
+			return []model.ExchangeRate{usdEUR}
+		},
+	}
+
+	breaker := NewCircuitBreaker(provider, CircuitBreakerOptions{Failures: 2, OpenFor: time.Minute, HalfOpenSuccesses: 2}, logger.NewLogger("error"))
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	breaker.UseClock(fake)
+	var states []string
+	breaker.OnChange(func(name, state string) { states = append(states, state) })
+
+	ctx := context.Background()
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	fetch := func() error {
+		_, err := breaker.FetchHistoricalRate(ctx, pair, fake.Now())
+		return err
+	}
+
+	// Missing quotes say nothing about the provider's health
+	providerErr = ports.ErrQuoteNotFound
+	for i := 0; i < 3; i++ {
+		fetch()
+	}
+	if breaker.State() != BreakerClosed {
+		t.Fatalf("Expected missing quotes to keep the circuit closed, got %s", breaker.State())
+	}
+
+	providerErr = errors.New("timeout")
+	fetch()
+	fetch()
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("Expected two failures in a row to open the circuit, got %s", breaker.State())
+	}
+
+	calls := len(provider.FetchHistoricalRateCalls())
+	err := fetch()
+	if !errors.Is(err, ErrCircuitOpen) || len(provider.FetchHistoricalRateCalls()) != calls {
+		t.Fatalf("Expected an open circuit to fail without calling the provider, got %v", err)
+	}
+	if e, ok := xerrors.Find(err); !ok || e.RetryAfter != time.Minute || e.Provider != "primary" {
+		t.Errorf("Expected the error to tell when the provider is tried again, got %+v", e)
+	}
+
+	// Latest rates come from those the provider holds, other pairs fail
+	if rate, err := breaker.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.EUR}); err != nil || rate.Rate != 0.92 {
+		t.Errorf("Expected the held rate while the circuit is open, got %v, %v", rate, err)
+	}
+	if _, err := breaker.FetchLatestRate(ctx, pair); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a rate the provider does not hold to fail, got %v", err)
+	}
+
+	// A failed trial opens the circuit again
+	fake.Advance(time.Minute)
+	if breaker.State() != BreakerHalfOpen {
+		t.Fatalf("Expected the circuit to be half open after a minute, got %s", breaker.State())
+	}
+	if err := fetch(); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("Expected a trial call to go through")
+	}
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("Expected a failed trial to open the circuit again, got %s", breaker.State())
+	}
+
+	// Two successful trials close it
+	fake.Advance(time.Minute)
+	providerErr = nil
+	fetch()
+	if breaker.State() != BreakerHalfOpen {
+		t.Fatalf("Expected one successful trial to leave the circuit half open, got %s", breaker.State())
+	}
+	fetch()
+	if breaker.State() != BreakerClosed {
+		t.Fatalf("Expected two successful trials to close the circuit, got %s", breaker.State())
+	}
+
+	want := []string{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if !slices.Equal(states, want) {
+		t.Errorf("State changes = %v, want %v", states, want)
+	}
+}
+
+func TestCircuitBreakerOpensAtOnceWhenRateLimited(t *testing.T) {
+	provider := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+		RefreshRatesFunc: func(ctx context.Context) error {
+			return &xerrors.Error{Kind: xerrors.RateLimited, Code: "http_429", RetryAfter: 5 * time.Minute}
+		},
+	}
+	breaker := NewCircuitBreaker(provider, CircuitBreakerOptions{Failures: 3, OpenFor: time.Minute}, logger.NewLogger("error"))
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	breaker.UseClock(fake)
+
+	breaker.RefreshRates(context.Background())
+	fake.Advance(4 * time.Minute)
+	if err := breaker.RefreshRates(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected the circuit to stay open for the Retry-After, got %v", err)
+	}
+	if len(provider.RefreshRatesCalls()) != 1 {
+		t.Errorf("Expected a single call to the provider, got %d", len(provider.RefreshRatesCalls()))
+	}
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// ProviderChainOptions tunes when a ProviderChain gives up on a provider
+type ProviderChainOptions struct {
+	// FailAfter consecutive failures take a provider out of the chain
+	FailAfter int
+	// Cooldown is how long a provider stays out before it is tried again
+	Cooldown time.Duration
+	// Timeout bounds each call to a provider; 0 leaves it to the provider
+	Timeout time.Duration
+}
+
+// providerState tracks the health of one provider of a chain
+type providerState struct {
+	failures    int
+	consecutive int
+	lastError   string
+	lastFailure time.Time
+	lastSuccess time.Time
+	retryAt     time.Time
+	rateLimited bool
+}
+
+// ProviderChain serves rates from an ordered list of providers. Every call
+// goes to the first available provider and fails over to the next one when it
+// returns an error or times out. A provider that fails FailAfter times in a
+// row is skipped until Cooldown has passed; its next success puts it back.
+// Missing quotes fail over too, but do not count against a provider's health.
+// A provider rate limiting calls is skipped at once, for as long as its
+// Retry-After asks when that is longer than Cooldown.
+type ProviderChain struct {
+	providers []ports.RateRepository
+	opts      ProviderChainOptions
+	clock     clock.Clock
+	log       *logger.Logger
+
+	mutex  sync.Mutex
+	states []providerState
+	// active is the provider that last answered successfully
+	active int
+}
+
+func NewProviderChain(providers []ports.RateRepository, opts ProviderChainOptions, log *logger.Logger) (*ProviderChain, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("a provider chain needs at least one provider")
+	}
+	if opts.FailAfter < 1 {
+		opts.FailAfter = 1
+	}
+
+	return &ProviderChain{
+		providers: providers,
+		opts:      opts,
+		clock:     clock.System,
+		log:       log,
+		states:    make([]providerState, len(providers)),
+	}, nil
+}
+
+// UseClock replaces the wall clock that cooldowns are measured on
+func (c *ProviderChain) UseClock(clock clock.Clock) {
+	c.clock = clock
+}
+
+// Name identifies the provider that last answered, whose rates are served
+func (c *ProviderChain) Name() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.providers[c.active].Name()
+}
+
+func (c *ProviderChain) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	var rate *model.ExchangeRate
+	err := c.try(ctx, func(ctx context.Context, provider ports.RateRepository) (err error) {
+		rate, err = provider.FetchLatestRate(ctx, pair)
+		return err
+	})
+	return rate, err
+}
+
+func (c *ProviderChain) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	var rate *model.ExchangeRate
+	err := c.try(ctx, func(ctx context.Context, provider ports.RateRepository) (err error) {
+		rate, err = provider.FetchHistoricalRate(ctx, pair, date)
+		return err
+	})
+	return rate, err
+}
+
+func (c *ProviderChain) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	var rates *model.HistoricalRates
+	err := c.try(ctx, func(ctx context.Context, provider ports.RateRepository) (err error) {
+		rates, err = provider.FetchHistoricalRates(ctx, request)
+		return err
+	})
+	return rates, err
+}
+
+// RefreshRates refreshes the first available provider. ErrRatesUnchanged
+// counts as a success.
+func (c *ProviderChain) RefreshRates(ctx context.Context) error {
+	return c.try(ctx, func(ctx context.Context, provider ports.RateRepository) error {
+		return provider.RefreshRates(ctx)
+	})
+}
+
+// RefreshPairs refreshes pairs of the first available provider, or all its
+// pairs when it cannot refresh a subset
+func (c *ProviderChain) RefreshPairs(ctx context.Context, pairs []model.CurrencyPair) error {
+	return c.try(ctx, func(ctx context.Context, provider ports.RateRepository) error {
+		if refresher, ok := provider.(ports.PairRefresher); ok {
+			return refresher.RefreshPairs(ctx, pairs)
+		}
+		return provider.RefreshRates(ctx)
+	})
+}
+
+// LatestRates returns the rates held by the provider that last answered
+func (c *ProviderChain) LatestRates(ctx context.Context) []model.ExchangeRate {
+	c.mutex.Lock()
+	provider := c.providers[c.active]
+	c.mutex.Unlock()
+	return provider.LatestRates(ctx)
+}
+
+// ProviderHealth reports every provider of the chain, in failover order
+func (c *ProviderChain) ProviderHealth() []model.ProviderHealth {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+	health := make([]model.ProviderHealth, len(c.providers))
+	for i, provider := range c.providers {
+		state := c.states[i]
+		health[i] = model.ProviderHealth{
+			Provider:            provider.Name(),
+			Position:            i + 1,
+			Healthy:             c.available(i, now),
+			Active:              i == c.active,
+			Failures:            state.failures,
+			ConsecutiveFailures: state.consecutive,
+			LastError:           state.lastError,
+			LastFailure:         state.lastFailure,
+			LastSuccess:         state.lastSuccess,
+			RateLimited:         state.rateLimited,
+		}
+		if state.retryAt.After(now) {
+			health[i].RetryAt = state.retryAt
+		}
+	}
+	return health
+}
+
+// try calls fn on each available provider in order until one succeeds, and
+// returns the last error when none does. When every provider is out, all are
+// tried rather than none.
+func (c *ProviderChain) try(ctx context.Context, fn func(ctx context.Context, provider ports.RateRepository) error) error {
+	candidates := c.candidates()
+
+	var err error
+	for n, i := range candidates {
+		err = c.call(ctx, c.providers[i], fn)
+		switch {
+		case err == nil || errors.Is(err, ports.ErrRatesUnchanged):
+			c.succeeded(i)
+			return err
+		case ctx.Err() != nil:
+			// The caller gave up; that says nothing about the provider
+			return err
+		case errors.Is(err, ports.ErrQuoteNotFound):
+			// The provider answered, it just has no such quote
+		default:
+			c.failed(i, err)
+		}
+
+		if n+1 < len(candidates) {
+			c.log.Warn("Provider failed, trying the next one", "provider", c.providers[i].Name(), "next", c.providers[candidates[n+1]].Name(), "error", err)
+		}
+	}
+	return err
+}
+
+func (c *ProviderChain) call(ctx context.Context, provider ports.RateRepository, fn func(ctx context.Context, provider ports.RateRepository) error) error {
+	if c.opts.Timeout <= 0 {
+		return fn(ctx, provider)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+	err := fn(ctx, provider)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s: %w", provider.Name(), c.opts.Timeout, err)
+	}
+	return err
+}
+
+// candidates returns the indexes of the available providers in order, or of
+// every provider when none is available
+func (c *ProviderChain) candidates() []int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+	candidates := make([]int, 0, len(c.providers))
+	for i := range c.providers {
+		if c.available(i, now) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		for i := range c.providers {
+			candidates = append(candidates, i)
+		}
+	}
+	return candidates
+}
+
+// available reports whether provider i may be called. The caller holds mutex.
+func (c *ProviderChain) available(i int, now time.Time) bool {
+	state := c.states[i]
+	return state.consecutive < c.opts.FailAfter || !now.Before(state.retryAt)
+}
+
+func (c *ProviderChain) succeeded(i int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state := &c.states[i]
+	if state.consecutive >= c.opts.FailAfter {
+		c.log.Info("Provider recovered", "provider", c.providers[i].Name())
+	}
+	state.consecutive = 0
+	state.retryAt = time.Time{}
+	state.rateLimited = false
+	state.lastSuccess = c.clock.Now()
+
+	if c.active != i {
+		c.log.Warn("Serving rates from another provider", "from", c.providers[c.active].Name(), "to", c.providers[i].Name())
+		c.active = i
+	}
+}
+
+func (c *ProviderChain) failed(i int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+	state := &c.states[i]
+	state.failures++
+	state.consecutive++
+	state.lastError = err.Error()
+	state.lastFailure = now
+
+	// A provider rate limiting calls is out at once, for as long as it asked
+	if limited, ok := xerrors.Find(err); ok && limited.Kind == xerrors.RateLimited {
+		cooldown := max(limited.RetryAfter, c.opts.Cooldown)
+		state.consecutive = max(state.consecutive, c.opts.FailAfter)
+		state.rateLimited = true
+		state.retryAt = now.Add(cooldown)
+		c.log.Error("Provider rate limited, taken out of the chain", "provider", c.providers[i].Name(), "retry_in", cooldown, "error", err)
+		return
+	}
+	state.rateLimited = false
+
+	if state.consecutive >= c.opts.FailAfter {
+		state.retryAt = now.Add(c.opts.Cooldown)
+		if state.consecutive == c.opts.FailAfter {
+			c.log.Error("Provider taken out of the chain", "provider", c.providers[i].Name(), "retry_in", c.opts.Cooldown, "error", err)
+		}
+	}
+}
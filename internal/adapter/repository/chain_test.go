@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// chainProvider answers with rate, or with err when it is set
+func chainProvider(name string, rate float64, err *error) *mocks.RateRepositoryMock {
+	return &mocks.RateRepositoryMock{
+		NameFunc: func() string { return name },
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			if *err != nil {
+				return nil, *err
+			}
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: rate}, nil
+		},
+	}
+}
+
+func TestProviderChainFailsOver(t *testing.T) {
+	var primaryErr, fallbackErr error
+	primary := chainProvider("primary", 83, &primaryErr)
+	fallback := chainProvider("fallback", 84, &fallbackErr)
+
+	chain, err := NewProviderChain([]ports.RateRepository{primary, fallback}, ProviderChainOptions{
+		FailAfter: 2,
+		Cooldown:  time.Minute,
+	}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewProviderChain failed: %v", err)
+	}
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	chain.UseClock(fake)
+
+	ctx := context.Background()
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	fetch := func() float64 {
+		t.Helper()
+		rate, err := chain.FetchLatestRate(ctx, pair)
+		if err != nil {
+			t.Fatalf("FetchLatestRate failed: %v", err)
+		}
+		return rate.Rate
+	}
+
+	if got := fetch(); got != 83 {
+		t.Fatalf("Expected the primary rate, got %v", got)
+	}
+
+	// The primary is still tried until it has failed twice in a row
+	primaryErr = errors.New("upstream down")
+	fetch()
+	if got := fetch(); got != 84 || chain.Name() != "fallback" {
+		t.Fatalf("Expected the fallback rate, got %v from %s", got, chain.Name())
+	}
+	fetch()
+	if calls := len(primary.FetchLatestRateCalls()); calls != 3 {
+		t.Errorf("Expected the primary to be skipped after 2 failures, it was called %d times", calls)
+	}
+
+	health := chain.ProviderHealth()
+	if health[0].Healthy || health[0].ConsecutiveFailures != 2 || !health[0].RetryAt.Equal(fake.Now().Add(time.Minute)) {
+		t.Errorf("Unexpected primary health: %+v", health[0])
+	}
+	if !health[1].Healthy || !health[1].Active {
+		t.Errorf("Unexpected fallback health: %+v", health[1])
+	}
+
+	// After the cooldown the recovered primary takes over again
+	primaryErr = nil
+	fake.Advance(time.Minute)
+	if got := fetch(); got != 83 || chain.Name() != "primary" {
+		t.Fatalf("Expected the primary rate after the cooldown, got %v from %s", got, chain.Name())
+	}
+	if health := chain.ProviderHealth(); !health[0].Healthy || health[0].Failures != 2 || health[0].ConsecutiveFailures != 0 {
+		t.Errorf("Unexpected primary health after recovery: %+v", health[0])
+	}
+
+	// With every provider out, all are still tried
+	primaryErr, fallbackErr = errors.New("upstream down"), errors.New("upstream down")
+	for range 2 {
+		chain.FetchLatestRate(ctx, pair)
+	}
+	before := len(primary.FetchLatestRateCalls())
+	if _, err := chain.FetchLatestRate(ctx, pair); err == nil {
+		t.Fatal("Expected an error with every provider down")
+	}
+	if len(primary.FetchLatestRateCalls()) != before+1 {
+		t.Error("Expected the primary to be tried when no provider is healthy")
+	}
+}
+
+func TestProviderChainTimesOut(t *testing.T) {
+	slow := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "slow" },
+		RefreshRatesFunc: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	fallback := &mocks.RateRepositoryMock{
+		NameFunc:         func() string { return "fallback" },
+		RefreshRatesFunc: func(ctx context.Context) error { return ports.ErrRatesUnchanged },
+	}
+
+	chain, err := NewProviderChain([]ports.RateRepository{slow, fallback}, ProviderChainOptions{
+		FailAfter: 1,
+		Cooldown:  time.Minute,
+		Timeout:   10 * time.Millisecond,
+	}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewProviderChain failed: %v", err)
+	}
+
+	if err := chain.RefreshRates(context.Background()); !errors.Is(err, ports.ErrRatesUnchanged) {
+		t.Fatalf("Expected the fallback's answer, got %v", err)
+	}
+	if health := chain.ProviderHealth(); health[0].Healthy || health[0].LastError == "" || !health[1].Active {
+		t.Errorf("Expected the slow provider to be taken out, got %+v", health)
+	}
+}
+
+func TestProviderChainMissingQuoteKeepsProviderHealthy(t *testing.T) {
+	primary := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return nil, ports.ErrQuoteNotFound
+		},
+	}
+	var noErr error
+	fallback := chainProvider("fallback", 84, &noErr)
+
+	chain, err := NewProviderChain([]ports.RateRepository{primary, fallback}, ProviderChainOptions{FailAfter: 1}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewProviderChain failed: %v", err)
+	}
+
+	rate, err := chain.FetchLatestRate(context.Background(), model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.GBP})
+	if err != nil || rate.Rate != 84 {
+		t.Fatalf("Expected the fallback to answer, got %v, %v", rate, err)
+	}
+	if health := chain.ProviderHealth(); !health[0].Healthy || health[0].Failures != 0 {
+		t.Errorf("Expected a missing quote not to count as a failure, got %+v", health[0])
+	}
+}
+
+func TestProviderChainHonorsRetryAfter(t *testing.T) {
+	var primaryErr, noErr error
+	primary := chainProvider("primary", 83, &primaryErr)
+	fallback := chainProvider("fallback", 84, &noErr)
+
+	chain, err := NewProviderChain([]ports.RateRepository{primary, fallback}, ProviderChainOptions{
+		FailAfter: 3,
+		Cooldown:  time.Minute,
+	}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("NewProviderChain failed: %v", err)
+	}
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	chain.UseClock(fake)
+
+	// A single 429 takes the provider out, for its longer Retry-After
+	primaryErr = &xerrors.Error{Kind: xerrors.RateLimited, Code: "http_429", RetryAfter: 5 * time.Minute}
+	if _, err := chain.FetchLatestRate(context.Background(), model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}); err != nil {
+		t.Fatalf("Expected the fallback to answer, got %v", err)
+	}
+	health := chain.ProviderHealth()
+	if health[0].Healthy || !health[0].RateLimited || !health[0].RetryAt.Equal(fake.Now().Add(5*time.Minute)) {
+		t.Errorf("Expected the primary out for 5m as rate limited, got %+v", health[0])
+	}
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/tracing"
+)
+
+const (
+	CoinGeckoProviderName = "coingecko"
+	// CoinGeckoBaseURL is the public API, which works without a key at a low
+	// rate limit
+	CoinGeckoBaseURL = "https://api.coingecko.com/api/v3"
+)
+
+// coinGeckoIDs are the CoinGecko coin ids of the model.Cryptocurrencies
+var coinGeckoIDs = map[model.Currency]string{
+	"BTC":  "bitcoin",
+	"ETH":  "ethereum",
+	"USDT": "tether",
+}
+
+// CoinGecko fetches cryptocurrency prices in USD from CoinGecko. It serves
+// pairs of cryptocurrencies and USD only, as USD quotes like those of the
+// fiat providers, so the service can cross them with fiat rates.
+type CoinGecko struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	clock      clock.Clock
+	log        *logger.Logger
+
+	// latest is replaced whole, never modified, so readers need no lock
+	latest atomic.Pointer[rateSnapshot]
+}
+
+// coinGeckoHistory is the body of the coin history endpoint
+type coinGeckoHistory struct {
+	MarketData struct {
+		CurrentPrice map[string]float64 `json:"current_price"`
+	} `json:"market_data"`
+}
+
+// NewCoinGecko creates a CoinGecko client. apiKey is a demo API key, and may
+// be empty.
+func NewCoinGecko(baseURL, apiKey string, timeout time.Duration, sla ports.SLAStore, log *logger.Logger) *CoinGecko {
+	c := &CoinGecko{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: newSLATransport(CoinGeckoProviderName, http.DefaultTransport, sla),
+		},
+		clock: clock.System,
+		log:   log,
+	}
+	c.latest.Store(&rateSnapshot{rates: make(map[string]*model.ExchangeRate)})
+	return c
+}
+
+// UseClock replaces the wall clock that dates and timestamps latest rates
+func (c *CoinGecko) UseClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+func (c *CoinGecko) Name() string {
+	return CoinGeckoProviderName
+}
+
+func (c *CoinGecko) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	if rate, exists := c.latest.Load().rates[pair.String()]; exists {
+		return rate, nil
+	}
+
+	quotes, err := c.fetchPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return computeRate(quotes, pair, c.clock.Now())
+}
+
+// FetchHistoricalRate asks for the price of each cryptocurrency of pair on
+// date, which CoinGecko gives as of 00:00 UTC
+func (c *CoinGecko) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	quotes := make(map[string]float64, 2)
+	for _, currency := range []model.Currency{pair.BaseCurrency, pair.TargetCurrency} {
+		id, found := coinGeckoIDs[currency]
+		if !found {
+			continue
+		}
+
+		var history coinGeckoHistory
+		query := url.Values{}
+		query.Set("date", date.Format("02-01-2006"))
+		query.Set("localization", "false")
+		if err := c.get(ctx, "coins/"+id+"/history", query, &history); err != nil {
+			return nil, err
+		}
+		price := history.MarketData.CurrentPrice["usd"]
+		if price <= 0 {
+			return nil, fmt.Errorf("%w: no CoinGecko price of %s on %s", ports.ErrQuoteNotFound, currency, date.Format("2006-01-02"))
+		}
+		quotes["USD"+string(currency)] = 1 / price
+	}
+
+	rate, err := computeRate(quotes, pair, c.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+// FetchHistoricalRates fetches the range a date at a time
+func (c *CoinGecko) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+
+	for date := request.StartDate; !date.After(request.EndDate); date = date.AddDate(0, 0, 1) {
+		dateKey := date.Format("2006-01-02")
+		rate, err := c.FetchHistoricalRate(ctx, pair, date)
+		if err != nil {
+			c.log.Error("Failed to fetch historical rate", "error", err, "date", dateKey)
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		result.Rates[dateKey] = *rate
+	}
+
+	return result, nil
+}
+
+// RefreshRates fetches the latest prices and serves every pair of
+// cryptocurrencies and USD from them. A refresh that finds the same prices on
+// the same day returns ports.ErrRatesUnchanged.
+func (c *CoinGecko) RefreshRates(ctx context.Context) error {
+	c.log.Info("Refreshing cryptocurrency prices")
+
+	quotes, err := c.fetchPrices(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch cryptocurrency prices: %w", err)
+	}
+
+	now := c.clock.Now()
+	digest := quotesDigest(quotes, now.UTC())
+	if digest == c.latest.Load().digest {
+		c.log.Info("Cryptocurrency prices unchanged, keeping the serving rates")
+		return ports.ErrRatesUnchanged
+	}
+
+	currencies := []model.Currency{model.USD}
+	for currency := range model.Cryptocurrencies {
+		currencies = append(currencies, currency)
+	}
+	rates := make(map[string]*model.ExchangeRate)
+	for _, base := range currencies {
+		for _, target := range currencies {
+			if base == target {
+				continue
+			}
+			pair := model.CurrencyPair{BaseCurrency: base, TargetCurrency: target}
+			rate, err := computeRate(quotes, pair, now)
+			if err != nil {
+				c.log.Error("Failed to extract rate", "error", err, "pair", pair.String())
+				continue
+			}
+			rates[pair.String()] = rate
+		}
+	}
+
+	c.latest.Store(&rateSnapshot{rates: rates, quotes: quotes, digest: digest})
+	c.log.Info("Successfully refreshed cryptocurrency prices")
+	return nil
+}
+
+func (c *CoinGecko) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return snapshotRates(c.latest.Load())
+}
+
+// fetchPrices returns the USD quotes of every cryptocurrency CoinGecko prices
+func (c *CoinGecko) fetchPrices(ctx context.Context) (map[string]float64, error) {
+	ids := make([]string, 0, len(coinGeckoIDs))
+	for _, id := range coinGeckoIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	query := url.Values{}
+	query.Set("ids", strings.Join(ids, ","))
+	query.Set("vs_currencies", "usd")
+
+	var prices map[string]map[string]float64
+	if err := c.get(ctx, "simple/price", query, &prices); err != nil {
+		return nil, err
+	}
+
+	quotes := make(map[string]float64, len(coinGeckoIDs))
+	for currency, id := range coinGeckoIDs {
+		if price := prices[id]["usd"]; price > 0 {
+			quotes["USD"+string(currency)] = 1 / price
+		}
+	}
+	return quotes, nil
+}
+
+// get calls endpoint with query, adding the API key, and decodes the response
+// into into
+func (c *CoinGecko) get(ctx context.Context, endpoint string, query url.Values, into any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("x-cg-demo-api-key", c.apiKey)
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return sendError(CoinGeckoProviderName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(CoinGeckoProviderName, resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+		return decodeError(CoinGeckoProviderName, err)
+	}
+	return nil
+}
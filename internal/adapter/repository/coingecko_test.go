@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestCoinGeckoRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-cg-demo-api-key") != "demo" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/simple/price":
+			if r.URL.Query().Get("ids") != "bitcoin,ethereum,tether" || r.URL.Query().Get("vs_currencies") != "usd" {
+				http.Error(w, `{"error":"bad request"}`, http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"bitcoin":{"usd":60000},"ethereum":{"usd":3000},"tether":{"usd":1.0}}`))
+		case "/coins/bitcoin/history":
+			if r.URL.Query().Get("date") != "06-03-2025" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(`{"id":"bitcoin","market_data":{"current_price":{"usd":90000,"inr":7830000}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	coinGecko := NewCoinGecko(server.URL, "demo", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	coinGecko.UseClock(clock.NewFake(time.Date(2025, 3, 7, 12, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+
+	if err := coinGecko.RefreshRates(ctx); err != nil {
+		t.Fatalf("RefreshRates failed: %v", err)
+	}
+	rate, err := coinGecko.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: "BTC", TargetCurrency: model.USD})
+	if err != nil || math.Abs(rate.Rate-60000) > 1e-6 {
+		t.Errorf("Expected BTC-USD at 60000, got %v, %v", rate, err)
+	}
+	if rates := coinGecko.LatestRates(ctx); len(rates) != 12 {
+		t.Errorf("Expected every pair of USD and the three cryptocurrencies, got %d", len(rates))
+	}
+
+	rates, err := coinGecko.FetchHistoricalRates(ctx, model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: "BTC",
+		StartDate:      time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("FetchHistoricalRates failed: %v", err)
+	}
+	if len(rates.Rates) != 1 || rates.Rates["2025-03-06"].Rate != 1.0/90000 {
+		t.Errorf("Unexpected historical rates: %+v", rates.Rates)
+	}
+	if len(rates.Errors) != 1 || rates.Errors[0].Item != "2025-03-07" {
+		t.Errorf("Expected 2025-03-07 to be reported as failed, got %+v", rates.Errors)
+	}
+}
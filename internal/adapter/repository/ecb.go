@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/tracing"
+)
+
+const (
+	ECBProviderName = "ecb"
+	// ECBBaseURL serves the euro foreign exchange reference rates, no key needed
+	ECBBaseURL = "https://www.ecb.europa.eu/stats/eurofxref"
+)
+
+// The ECB feeds: the latest working day, the last 90 days, and every day
+// since 1999
+const (
+	ecbDailyFeed   = "eurofxref-daily.xml"
+	ecbRecentFeed  = "eurofxref-hist-90d.xml"
+	ecbHistoryFeed = "eurofxref-hist.xml"
+)
+
+// ECB fetches the reference rates the European Central Bank publishes every
+// working day around 16:00 CET. The rates are quoted against the euro; every
+// other pair is crossed through it. There are no rates for weekends and
+// TARGET holidays.
+type ECB struct {
+	baseURL    string
+	httpClient *http.Client
+	clock      clock.Clock
+	log        *logger.Logger
+
+	// latest is replaced whole, never modified, so readers need no lock
+	latest atomic.Pointer[rateSnapshot]
+	guardrail
+}
+
+// ecbEnvelope is the document of every ECB feed: one Cube per day holding one
+// Cube per currency. Namespaces are left out so any prefix matches.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string  `xml:"currency,attr"`
+			Rate     float64 `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+func NewECB(baseURL string, timeout time.Duration, sla ports.SLAStore, log *logger.Logger) *ECB {
+	e := &ECB{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: newSLATransport(ECBProviderName, http.DefaultTransport, sla),
+		},
+		clock: clock.System,
+		log:   log,
+	}
+	e.latest.Store(&rateSnapshot{rates: make(map[string]*model.ExchangeRate)})
+	return e
+}
+
+// UseClock replaces the wall clock that dates and timestamps latest rates
+func (e *ECB) UseClock(c clock.Clock) {
+	e.clock = c
+}
+
+func (e *ECB) Name() string {
+	return ECBProviderName
+}
+
+func (e *ECB) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	if rate, exists := e.latest.Load().rates[pair.String()]; exists {
+		return rate, nil
+	}
+
+	days, err := e.fetchFeed(ctx, ecbDailyFeed)
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := latestQuotes(days)
+	if err != nil {
+		return nil, err
+	}
+	return computeRate(quotes, pair, e.clock.Now())
+}
+
+func (e *ECB) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	days, err := e.fetchFeed(ctx, e.historyFeed(date))
+	if err != nil {
+		return nil, err
+	}
+
+	dateKey := date.Format("2006-01-02")
+	quotes, exists := days[dateKey]
+	if !exists {
+		return nil, fmt.Errorf("%w: no ECB reference rates on %s", ports.ErrQuoteNotFound, dateKey)
+	}
+	return e.historicalRate(quotes, pair, date)
+}
+
+// FetchHistoricalRates reads the whole range from one feed. Days without
+// reference rates are left out.
+func (e *ECB) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	days, err := e.fetchFeed(ctx, e.historyFeed(request.StartDate))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+
+	for dateKey, quotes := range days {
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil || date.Before(request.StartDate) || date.After(request.EndDate) {
+			continue
+		}
+
+		rate, err := e.historicalRate(quotes, pair, date)
+		if err != nil {
+			e.log.Error("Failed to extract historical rate", "error", err, "date", dateKey)
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		result.Rates[dateKey] = *rate
+	}
+
+	return result, nil
+}
+
+// historicalRate computes the rate of pair on date
+func (e *ECB) historicalRate(quotes map[string]float64, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	rate, err := computeRate(quotes, pair, e.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+// historyFeed picks the 90 day feed for dates it covers, and the much larger
+// full history for older ones
+func (e *ECB) historyFeed(date time.Time) string {
+	if e.clock.Now().Sub(date) < 89*24*time.Hour {
+		return ecbRecentFeed
+	}
+	return ecbHistoryFeed
+}
+
+// RefreshRates fetches the latest reference rates and serves every supported
+// pair from them. A refresh that finds the same rates on the same day returns
+// ports.ErrRatesUnchanged.
+func (e *ECB) RefreshRates(ctx context.Context) error {
+	e.log.Info("Refreshing all exchange rates")
+
+	days, err := e.fetchFeed(ctx, ecbDailyFeed)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest rates: %w", err)
+	}
+	quotes, err := latestQuotes(days)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest rates: %w", err)
+	}
+
+	if err := e.promoteQuotes(&e.latest, quotes, e.clock.Now(), e.log); err != nil {
+		return err
+	}
+	e.log.Info("Successfully refreshed all exchange rates")
+	return nil
+}
+
+func (e *ECB) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return snapshotRates(e.latest.Load())
+}
+
+// fetchFeed downloads an ECB feed and returns the quotes of each day in it,
+// keyed by date. The euro based rates are turned into the USD quotes the
+// other providers give, so computeRate crosses them alike.
+func (e *ECB) fetchFeed(ctx context.Context, feed string) (map[string]map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+"/"+feed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, sendError(ECBProviderName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(ECBProviderName, resp)
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, decodeError(ECBProviderName, err)
+	}
+
+	days := make(map[string]map[string]float64, len(envelope.Days))
+	for _, day := range envelope.Days {
+		perEuro := make(map[string]float64, len(day.Rates))
+		for _, rate := range day.Rates {
+			perEuro[rate.Currency] = rate.Rate
+		}
+
+		quotes, ok := usdQuotes(string(model.EUR), perEuro)
+		if !ok {
+			e.log.Warn("ECB reference rates without USD, skipping the day", "date", day.Time)
+			continue
+		}
+		days[day.Time] = quotes
+	}
+	return days, nil
+}
+
+// latestQuotes returns the quotes of the most recent day of days
+func latestQuotes(days map[string]map[string]float64) (map[string]float64, error) {
+	if len(days) == 0 {
+		return nil, fmt.Errorf("ECB published no rates")
+	}
+	dates := slices.Sorted(maps.Keys(days))
+	return days[dates[len(dates)-1]], nil
+}
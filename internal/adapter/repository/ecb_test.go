@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+const ecbDaily = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender>
+	<Cube>
+		<Cube time="2025-03-07">
+			<Cube currency="USD" rate="1.0800"/>
+			<Cube currency="JPY" rate="160.00"/>
+			<Cube currency="GBP" rate="0.8400"/>
+			<Cube currency="INR" rate="94.50"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const ecbRecent = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2025-03-07">
+			<Cube currency="USD" rate="1.0800"/>
+			<Cube currency="INR" rate="94.50"/>
+		</Cube>
+		<Cube time="2025-03-06">
+			<Cube currency="USD" rate="1.0000"/>
+			<Cube currency="INR" rate="90.00"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestECBReferenceRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + ecbDailyFeed:
+			w.Write([]byte(ecbDaily))
+		case "/" + ecbRecentFeed:
+			w.Write([]byte(ecbRecent))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ecb := NewECB(server.URL, time.Second, nopSLAStore(), logger.NewLogger("error"))
+	ecb.UseClock(clock.NewFake(time.Date(2025, 3, 8, 9, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+
+	if err := ecb.RefreshRates(ctx); err != nil {
+		t.Fatalf("RefreshRates failed: %v", err)
+	}
+	if err := ecb.RefreshRates(ctx); !errors.Is(err, ports.ErrRatesUnchanged) {
+		t.Errorf("Expected unchanged rates on the second refresh, got %v", err)
+	}
+
+	latest := []struct {
+		pair model.CurrencyPair
+		want float64
+	}{
+		{model.CurrencyPair{BaseCurrency: model.EUR, TargetCurrency: model.USD}, 1.08},
+		{model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.EUR}, 1 / 1.08},
+		{model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}, 94.5 / 1.08},
+		{model.CurrencyPair{BaseCurrency: model.GBP, TargetCurrency: model.JPY}, 160 / 0.84},
+	}
+	for _, tc := range latest {
+		rate, err := ecb.FetchLatestRate(ctx, tc.pair)
+		if err != nil {
+			t.Fatalf("FetchLatestRate(%s) failed: %v", tc.pair, err)
+		}
+		if math.Abs(rate.Rate-tc.want) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", tc.pair, tc.want, rate.Rate)
+		}
+	}
+	if got := len(ecb.LatestRates(ctx)); got != 20 {
+		t.Errorf("Expected every supported pair to be served, got %d rates", got)
+	}
+
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	date := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	rate, err := ecb.FetchHistoricalRate(ctx, pair, date)
+	if err != nil {
+		t.Fatalf("FetchHistoricalRate failed: %v", err)
+	}
+	if rate.Rate != 90 || !rate.Date.Equal(date) {
+		t.Errorf("Expected 90 on %s, got %v on %s", date.Format("2006-01-02"), rate.Rate, rate.Date)
+	}
+
+	// There are no reference rates on weekends
+	if _, err := ecb.FetchHistoricalRate(ctx, pair, time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ports.ErrQuoteNotFound) {
+		t.Errorf("Expected ErrQuoteNotFound on a Saturday, got %v", err)
+	}
+
+	rates, err := ecb.FetchHistoricalRates(ctx, model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("FetchHistoricalRates failed: %v", err)
+	}
+	if len(rates.Rates) != 2 || rates.Rates["2025-03-07"].Rate != 94.5/1.08 {
+		t.Errorf("Unexpected historical rates: %+v", rates.Rates)
+	}
+}
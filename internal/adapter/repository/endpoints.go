@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+const (
+	// latencyWeight is the weight of a new sample in the moving average
+	latencyWeight = 0.3
+	// unhealthyAfter consecutive failures take an endpoint out of rotation
+	unhealthyAfter = 2
+)
+
+// endpoint is one regional address of a provider
+type endpoint struct {
+	scheme   string
+	host     string
+	latency  time.Duration
+	measured bool
+	failures int
+}
+
+func (e *endpoint) healthy() bool {
+	return e.failures < unhealthyAfter
+}
+
+func (e *endpoint) String() string {
+	return e.scheme + "://" + e.host
+}
+
+// EndpointPool discovers the regional endpoints of a provider, from a static
+// list and optionally a DNS SRV record, and routes requests to the healthy one
+// with the lowest latency. Latency comes from periodic probes only, so that
+// endpoints are compared on the same request; failed requests count against
+// an endpoint's health too.
+type EndpointPool struct {
+	provider   string
+	static     []string
+	srv        string
+	healthPath string
+	client     *http.Client
+	resolver   *net.Resolver
+	log        *logger.Logger
+
+	mutex     sync.RWMutex
+	endpoints map[string]*endpoint
+	selected  string
+	// initial is true until the first selection, which is not a switch
+	initial  bool
+	onSwitch func(provider, from, to string)
+}
+
+// NewEndpointPool creates a pool from static endpoint URLs and an optional SRV
+// name like _https._tcp.api.example.com. SRV targets use the scheme of the
+// service label.
+func NewEndpointPool(provider string, static []string, srv, healthPath string, timeout time.Duration, log *logger.Logger) *EndpointPool {
+	return &EndpointPool{
+		provider:   provider,
+		static:     static,
+		srv:        srv,
+		healthPath: healthPath,
+		client:     &http.Client{Timeout: timeout},
+		resolver:   net.DefaultResolver,
+		log:        log,
+		endpoints:  make(map[string]*endpoint),
+		initial:    true,
+	}
+}
+
+// OnSwitch registers fn to be called when requests move to another endpoint
+// after the first selection. from or to is empty when requests came from or
+// fall back to the base URL because no endpoint is healthy. It must be set
+// before Run.
+func (p *EndpointPool) OnSwitch(fn func(provider, from, to string)) {
+	p.onSwitch = fn
+}
+
+// Run discovers and probes endpoints immediately and then every interval
+// until ctx is cancelled
+func (p *EndpointPool) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.discover(ctx); err != nil {
+			p.log.Error("Failed to discover provider endpoints", "provider", p.provider, "error", err)
+		}
+		p.probe(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// discover merges the static endpoints with the current SRV targets. Stats of
+// endpoints that are still present are kept; vanished ones are dropped.
+func (p *EndpointPool) discover(ctx context.Context) error {
+	found := make(map[string]*endpoint)
+	for _, raw := range p.static {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q", raw)
+		}
+		found[u.Scheme+"://"+u.Host] = &endpoint{scheme: u.Scheme, host: u.Host}
+	}
+
+	var srvErr error
+	if p.srv != "" {
+		scheme := "https"
+		if strings.HasPrefix(p.srv, "_http.") {
+			scheme = "http"
+		}
+		_, records, err := p.resolver.LookupSRV(ctx, "", "", p.srv)
+		if err != nil {
+			srvErr = fmt.Errorf("SRV lookup of %s failed: %w", p.srv, err)
+		}
+		for _, record := range records {
+			host := net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port)))
+			found[scheme+"://"+host] = &endpoint{scheme: scheme, host: host}
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// A failed lookup keeps the endpoints discovered earlier
+	if srvErr != nil {
+		for key, e := range p.endpoints {
+			if _, exists := found[key]; !exists {
+				found[key] = e
+			}
+		}
+	}
+	for key := range found {
+		if existing, exists := p.endpoints[key]; exists {
+			found[key] = existing
+		}
+	}
+	p.endpoints = found
+	return srvErr
+}
+
+// probe requests the health path on every endpoint and records the latency
+func (p *EndpointPool) probe(ctx context.Context) {
+	p.mutex.RLock()
+	endpoints := make([]*endpoint, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		endpoints = append(endpoints, e)
+	}
+	p.mutex.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, e := range endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodHead, e.String()+p.healthPath, nil)
+			if err != nil {
+				p.observe(e, 0, err)
+				return
+			}
+			start := time.Now()
+			resp, err := p.client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode >= http.StatusInternalServerError {
+					err = fmt.Errorf("status %d", resp.StatusCode)
+				}
+			}
+			p.observe(e, time.Since(start), err)
+		}(e)
+	}
+	wg.Wait()
+
+	p.reselect()
+}
+
+func (p *EndpointPool) observe(e *endpoint, latency time.Duration, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err != nil {
+		e.failures++
+		if e.failures == unhealthyAfter {
+			p.log.Warn("Provider endpoint unhealthy", "provider", p.provider, "endpoint", e.String(), "error", err)
+		}
+		return
+	}
+
+	if e.failures >= unhealthyAfter {
+		p.log.Info("Provider endpoint healthy again", "provider", p.provider, "endpoint", e.String())
+	}
+	e.failures = 0
+	if !e.measured {
+		e.latency = latency
+		e.measured = true
+	} else {
+		e.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(e.latency))
+	}
+}
+
+// pick returns the healthy endpoint with the lowest latency, preferring
+// measured endpoints, or nil when none is healthy
+func (p *EndpointPool) pick() *endpoint {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	candidates := make([]*endpoint, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		if e.healthy() {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.measured != b.measured {
+			return a.measured
+		}
+		if a.latency != b.latency {
+			return a.latency < b.latency
+		}
+		return a.String() < b.String()
+	})
+	return candidates[0]
+}
+
+// reselect logs and reports when the preferred endpoint changes
+func (p *EndpointPool) reselect() {
+	e := p.pick()
+	selected := ""
+	if e != nil {
+		selected = e.String()
+	}
+
+	p.mutex.Lock()
+	previous, initial := p.selected, p.initial
+	changed := selected != previous
+	p.selected = selected
+	if selected != "" {
+		p.initial = false
+	}
+	var latency time.Duration
+	if e != nil {
+		latency = e.latency
+	}
+	p.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+	if p.onSwitch != nil && !initial {
+		p.onSwitch(p.provider, previous, selected)
+	}
+	if e == nil {
+		p.log.Warn("No healthy provider endpoint, using the base URL", "provider", p.provider)
+		return
+	}
+	p.log.Info("Selected provider endpoint", "provider", p.provider, "endpoint", selected, "latency", latency.String())
+}
+
+// endpointTransport sends each request to the pool's preferred endpoint. Only
+// the scheme and host are replaced; the path and query of the base URL stay.
+type endpointTransport struct {
+	pool *EndpointPool
+	next http.RoundTripper
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e := t.pool.pick()
+	if e == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	routed := req.Clone(req.Context())
+	routed.URL.Scheme = e.scheme
+	routed.URL.Host = e.host
+	routed.Host = ""
+
+	resp, err := t.next.RoundTrip(routed)
+	failure := err
+	if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+		failure = fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if failure != nil {
+		t.pool.observe(e, 0, failure)
+		if t.pool.pick() != e {
+			t.pool.reselect()
+		}
+	}
+	return resp, err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestEndpointPoolSelection(t *testing.T) {
+	hits := make(map[string]int)
+	server := func(name string, delay time.Duration, status int) *httptest.Server {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			if r.Method == http.MethodGet {
+				hits[name]++
+			}
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(s.Close)
+		return s
+	}
+
+	slow := server("slow", 50*time.Millisecond, http.StatusOK)
+	fast := server("fast", 0, http.StatusOK)
+	broken := server("broken", 0, http.StatusServiceUnavailable)
+
+	pool := NewEndpointPool("test", []string{slow.URL, fast.URL, broken.URL}, "", "/", time.Second, logger.NewLogger("error"))
+	for i := 0; i < unhealthyAfter; i++ {
+		if err := pool.discover(context.Background()); err != nil {
+			t.Fatalf("discover failed: %v", err)
+		}
+		pool.probe(context.Background())
+	}
+
+	if e := pool.pick(); e == nil || e.String() != fast.URL {
+		t.Fatalf("Expected %s to be selected, got %v", fast.URL, e)
+	}
+
+	client := &http.Client{Transport: &endpointTransport{pool: pool, next: http.DefaultTransport}}
+	resp, err := client.Get("http://base.invalid/live?base=USD")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if hits["fast"] != 1 {
+		t.Errorf("Expected the request to reach the fast endpoint, hits: %v", hits)
+	}
+
+	// Once the fast endpoint fails, requests move to the remaining healthy one
+	fast.Close()
+	for i := 0; i < unhealthyAfter; i++ {
+		pool.probe(context.Background())
+	}
+	if e := pool.pick(); e == nil || e.String() != slow.URL {
+		t.Errorf("Expected failover to %s, got %v", slow.URL, e)
+	}
+}
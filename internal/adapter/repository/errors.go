@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// usageLimitReached is the error code exchangerate.host gives once the
+// monthly calls of the plan are used up
+const usageLimitReached = 104
+
+// providerFailure is the error exchangerate.host reports in the body of an
+// unsuccessful response
+type providerFailure struct {
+	Code int    `json:"code"`
+	Type string `json:"type"`
+	Info string `json:"info"`
+}
+
+// sendError classifies a call that got no response from provider
+func sendError(provider string, err error) error {
+	return &xerrors.Error{
+		Kind:     xerrors.Upstream,
+		Code:     "unreachable",
+		Message:  "failed to send request",
+		Provider: provider,
+		Err:      err,
+	}
+}
+
+// statusError classifies a response of provider with a status other than
+// 200. 429 Too Many Requests is rate limiting, anything else a failure.
+func statusError(provider string, resp *http.Response) error {
+	kind := xerrors.Upstream
+	if resp.StatusCode == http.StatusTooManyRequests {
+		kind = xerrors.RateLimited
+	}
+	return &xerrors.Error{
+		Kind:       kind,
+		Code:       "http_" + strconv.Itoa(resp.StatusCode),
+		Message:    fmt.Sprintf("API returned non-OK status: %d", resp.StatusCode),
+		Provider:   provider,
+		Status:     resp.StatusCode,
+		RetryAfter: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// decodeError classifies a response of provider that could not be read
+func decodeError(provider string, err error) error {
+	return &xerrors.Error{
+		Kind:     xerrors.Upstream,
+		Code:     "malformed_response",
+		Message:  "failed to decode response",
+		Provider: provider,
+		Err:      err,
+	}
+}
+
+// reportedError classifies a failure provider reported in a 200 response,
+// keeping its own error type, or number, as the code
+func reportedError(provider string, failure *providerFailure) error {
+	if failure == nil {
+		return &xerrors.Error{Kind: xerrors.Upstream, Code: "failure", Message: "API reported failure", Provider: provider}
+	}
+
+	kind := xerrors.Upstream
+	if failure.Code == usageLimitReached {
+		kind = xerrors.RateLimited
+	}
+	code := failure.Type
+	if code == "" {
+		code = strconv.Itoa(failure.Code)
+	}
+	return &xerrors.Error{
+		Kind:     kind,
+		Code:     code,
+		Message:  "API reported failure: " + failure.Info,
+		Provider: provider,
+	}
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as a date
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestProviderErrorsAreClassified(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantKind   xerrors.Kind
+		wantCode   string
+		wantStatus int
+		wantRetry  time.Duration
+	}{
+		{
+			name: "rate limited",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantKind: xerrors.RateLimited, wantCode: "http_429", wantStatus: 429, wantRetry: 30 * time.Second,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantKind: xerrors.Upstream, wantCode: "http_500", wantStatus: 500,
+		},
+		{
+			name: "usage limit reported",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"success":false,"error":{"code":104,"type":"usage_limit_reached","info":"Your monthly usage limit has been reached."}}`))
+			},
+			wantKind: xerrors.RateLimited, wantCode: "usage_limit_reached",
+		},
+		{
+			name: "invalid key reported",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"success":false,"error":{"code":101,"type":"invalid_access_key","info":"You have not supplied a valid API Access Key."}}`))
+			},
+			wantKind: xerrors.Upstream, wantCode: "invalid_access_key",
+		},
+		{
+			name: "malformed response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`<html>`))
+			},
+			wantKind: xerrors.Upstream, wantCode: "malformed_response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			t.Cleanup(server.Close)
+
+			api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+			_, err := api.FetchLatestRate(context.Background(), model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR})
+
+			var providerErr *xerrors.Error
+			if !errors.As(err, &providerErr) {
+				t.Fatalf("Expected a classified error, got %v", err)
+			}
+			if providerErr.Kind != tt.wantKind || providerErr.Code != tt.wantCode || providerErr.Status != tt.wantStatus || providerErr.RetryAfter != tt.wantRetry {
+				t.Errorf("Unexpected error %+v", providerErr)
+			}
+			if providerErr.Provider != ExchangeAPIProviderName {
+				t.Errorf("Expected the provider to be named, got %q", providerErr.Provider)
+			}
+		})
+	}
+}
@@ -2,23 +2,51 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
-	"sync"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
 	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/tracing"
 )
 
+const ExchangeAPIProviderName = "exchangerate.host"
+
+// maxRawResponse bounds provider responses passed through by FetchRaw
+const maxRawResponse = 4 << 20
+
 type ExchangeAPI struct {
-	baseURL     string
-	apiKey      string
-	httpClient  *http.Client
-	log         *logger.Logger
-	mutex       sync.RWMutex
-	latestRates map[string]*model.ExchangeRate
+	name       string
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	clock      clock.Clock
+	log        *logger.Logger
+
+	// latest is replaced whole, never modified, so readers need no lock
+	latest atomic.Pointer[rateSnapshot]
+	guardrail
+}
+
+// rateSnapshot is an immutable set of serving rates, keyed by pair, along
+// with the USD quotes they were computed from and their digest
+type rateSnapshot struct {
+	rates  map[string]*model.ExchangeRate
+	quotes map[string]float64
+	digest [sha256.Size]byte
 }
 
 type exchangerateAPIResponse struct {
@@ -28,30 +56,62 @@ type exchangerateAPIResponse struct {
 	Timestamp int64              `json:"timestamp"`
 	Source    string             `json:"source"`
 	Quotes    map[string]float64 `json:"quotes"`
+	Error     *providerFailure   `json:"error,omitempty"`
+}
+
+func NewExchangeAPI(baseURL, apiKey string, timeout time.Duration, sla ports.SLAStore, log *logger.Logger) *ExchangeAPI {
+	return NewNamedExchangeAPI(ExchangeAPIProviderName, baseURL, apiKey, timeout, sla, log)
 }
 
-func NewExchangeAPI(baseURL, apiKey string, timeout time.Duration, log *logger.Logger) *ExchangeAPI {
-	return &ExchangeAPI{
+// NewNamedExchangeAPI creates a client for any exchangerate.host compatible
+// API. name identifies the provider in SLA data and comparisons.
+func NewNamedExchangeAPI(name, baseURL, apiKey string, timeout time.Duration, sla ports.SLAStore, log *logger.Logger) *ExchangeAPI {
+	e := &ExchangeAPI{
+		name:    name,
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: newSLATransport(name, http.DefaultTransport, sla),
 		},
-		log:         log,
-		latestRates: make(map[string]*model.ExchangeRate),
+		clock: clock.System,
+		log:   log,
+	}
+	e.latest.Store(&rateSnapshot{rates: make(map[string]*model.ExchangeRate)})
+	return e
+}
+
+// UseEndpoints routes requests to the preferred endpoint of pool instead of
+// the base URL's host whenever the pool has a healthy one
+func (e *ExchangeAPI) UseEndpoints(pool *EndpointPool) {
+	transport := e.httpClient.Transport
+	if t, ok := transport.(*cachingTransport); ok {
+		transport = t.next
+	}
+	if t, ok := transport.(*limitingTransport); ok {
+		transport = t.next
 	}
+	if t, ok := transport.(*slaTransport); ok {
+		t.next = &endpointTransport{pool: pool, next: t.next}
+	}
+}
+
+// UseClock replaces the wall clock that dates and timestamps fetched rates
+func (e *ExchangeAPI) UseClock(c clock.Clock) {
+	e.clock = c
+}
+
+func (e *ExchangeAPI) Name() string {
+	return e.name
 }
 
 func (e *ExchangeAPI) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
 
 	cacheKey := fmt.Sprintf("%s-%s", pair.BaseCurrency, pair.TargetCurrency)
 
-	e.mutex.RLock()
-	if rate, exists := e.latestRates[cacheKey]; exists {
-		e.mutex.RUnlock()
+	if rate, exists := e.latest.Load().rates[cacheKey]; exists {
 		return rate, nil
 	}
-	e.mutex.RUnlock()
 
 	rates, err := e.fetchAllLatestRates(ctx)
 	if err != nil {
@@ -67,9 +127,19 @@ func (e *ExchangeAPI) FetchLatestRate(ctx context.Context, pair model.CurrencyPa
 }
 
 func (e *ExchangeAPI) fetchAllLatestRates(ctx context.Context) (map[string]float64, error) {
+	return e.fetchLatestQuotes(ctx, nil)
+}
+
+// fetchLatestQuotes returns the USD quotes of currencies, or of every
+// currency the provider has when currencies is empty
+func (e *ExchangeAPI) fetchLatestQuotes(ctx context.Context, currencies []string) (map[string]float64, error) {
 
 	url := fmt.Sprintf("%s/live?base=USD", e.baseURL)
 
+	if len(currencies) > 0 {
+		url += "&currencies=" + strings.Join(currencies, ",")
+	}
+
 	if e.apiKey != "" {
 		url += "&access_key=" + e.apiKey
 	}
@@ -78,108 +148,131 @@ func (e *ExchangeAPI) fetchAllLatestRates(ctx context.Context) (map[string]float
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	tracing.Inject(ctx, req.Header)
 
 	resp, err := e.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, sendError(e.name, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned non-OK status: %d", resp.StatusCode)
+		return nil, statusError(e.name, resp)
 	}
 
 	var apiResp exchangerateAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, decodeError(e.name, err)
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("API reported failure")
+		return nil, reportedError(e.name, apiResp.Error)
 	}
 
 	return apiResp.Quotes, nil
 }
 
 func (e *ExchangeAPI) extractRate(quotes map[string]float64, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	exchangeRate, err := computeRate(quotes, pair, e.clock.Now())
+	if err != nil {
+		return nil, err
+	}
 
-	if pair.BaseCurrency == model.USD {
-		rateKey := fmt.Sprintf("USD%s", pair.TargetCurrency)
-		rate, exists := quotes[rateKey]
-		if !exists {
-			return nil, fmt.Errorf("rate not found for currency: %s", pair.TargetCurrency)
-		}
+	if err := e.store(&e.latest, pair, exchangeRate, e.log); err != nil {
+		return nil, err
+	}
+	return exchangeRate, nil
+}
 
-		exchangeRate := &model.ExchangeRate{
-			BaseCurrency:   pair.BaseCurrency,
-			TargetCurrency: pair.TargetCurrency,
-			Rate:           rate,
-			Date:           time.Now().UTC().Truncate(24 * time.Hour),
-			LastUpdated:    time.Now(),
-		}
+// quotesDigest hashes quotes together with the day of now, so the first
+// refresh of a day is never taken as unchanged and rates get the new date
+func quotesDigest(quotes map[string]float64, now time.Time) [sha256.Size]byte {
+	keys := make([]string, 0, len(quotes))
+	for key := range quotes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-		cacheKey := fmt.Sprintf("%s-%s", pair.BaseCurrency, pair.TargetCurrency)
-		e.mutex.Lock()
-		e.latestRates[cacheKey] = exchangeRate
-		e.mutex.Unlock()
+	hash := sha256.New()
+	hash.Write([]byte(now.Format("2006-01-02")))
+	for _, key := range keys {
+		hash.Write([]byte("\n" + key + "=" + strconv.FormatFloat(quotes[key], 'g', -1, 64)))
+	}
 
-		return exchangeRate, nil
+	var digest [sha256.Size]byte
+	hash.Sum(digest[:0])
+	return digest
+}
+
+// snapshotRates lists the rates of snapshot
+func snapshotRates(snapshot *rateSnapshot) []model.ExchangeRate {
+	rates := make([]model.ExchangeRate, 0, len(snapshot.rates))
+	for _, rate := range snapshot.rates {
+		rates = append(rates, *rate)
 	}
+	return rates
+}
 
-	if pair.TargetCurrency == model.USD {
-		rateKey := fmt.Sprintf("USD%s", pair.BaseCurrency)
-		rate, exists := quotes[rateKey]
+// computeRate derives the rate of pair from USD based quotes, as of now
+func computeRate(quotes map[string]float64, pair model.CurrencyPair, now time.Time) (*model.ExchangeRate, error) {
+	var rate float64
+	switch {
+	case pair.BaseCurrency == model.USD:
+		quote, exists := quotes[fmt.Sprintf("USD%s", pair.TargetCurrency)]
 		if !exists {
-			return nil, fmt.Errorf("rate not found for currency: %s", pair.BaseCurrency)
+			return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.TargetCurrency)
 		}
+		rate = quote
 
-		inverseRate := 1.0 / rate
-
-		exchangeRate := &model.ExchangeRate{
-			BaseCurrency:   pair.BaseCurrency,
-			TargetCurrency: pair.TargetCurrency,
-			Rate:           inverseRate,
-			Date:           time.Now().UTC().Truncate(24 * time.Hour),
-			LastUpdated:    time.Now(),
+	case pair.TargetCurrency == model.USD:
+		quote, exists := quotes[fmt.Sprintf("USD%s", pair.BaseCurrency)]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.BaseCurrency)
 		}
+		rate = 1.0 / quote
 
-		cacheKey := fmt.Sprintf("%s-%s", pair.BaseCurrency, pair.TargetCurrency)
-		e.mutex.Lock()
-		e.latestRates[cacheKey] = exchangeRate
-		e.mutex.Unlock()
-
-		return exchangeRate, nil
-	}
-
-	baseUsdKey := fmt.Sprintf("USD%s", pair.BaseCurrency)
-	targetUsdKey := fmt.Sprintf("USD%s", pair.TargetCurrency)
-
-	baseRate, baseExists := quotes[baseUsdKey]
-	targetRate, targetExists := quotes[targetUsdKey]
-
-	if !baseExists {
-		return nil, fmt.Errorf("rate not found for currency: %s", pair.BaseCurrency)
-	}
-	if !targetExists {
-		return nil, fmt.Errorf("rate not found for currency: %s", pair.TargetCurrency)
+	default:
+		baseRate, baseExists := quotes[fmt.Sprintf("USD%s", pair.BaseCurrency)]
+		targetRate, targetExists := quotes[fmt.Sprintf("USD%s", pair.TargetCurrency)]
+		if !baseExists {
+			return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.BaseCurrency)
+		}
+		if !targetExists {
+			return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.TargetCurrency)
+		}
+		rate = targetRate / baseRate
 	}
 
-	crossRate := targetRate / baseRate
-
-	exchangeRate := &model.ExchangeRate{
+	return &model.ExchangeRate{
 		BaseCurrency:   pair.BaseCurrency,
 		TargetCurrency: pair.TargetCurrency,
-		Rate:           crossRate,
-		Date:           time.Now().UTC().Truncate(24 * time.Hour),
-		LastUpdated:    time.Now(),
-	}
+		Rate:           rate,
+		Date:           now.UTC().Truncate(24 * time.Hour),
+		LastUpdated:    now,
+		Unit:           pair.QuoteUnit(),
+	}, nil
+}
 
-	cacheKey := fmt.Sprintf("%s-%s", pair.BaseCurrency, pair.TargetCurrency)
-	e.mutex.Lock()
-	e.latestRates[cacheKey] = exchangeRate
-	e.mutex.Unlock()
+// usdQuotes turns rates given per unit of base, as providers with a euro base
+// quote them, into the USD quotes computeRate takes. It fails when rates have
+// no USD rate to go through.
+func usdQuotes(base string, rates map[string]float64) (map[string]float64, bool) {
+	perBase := map[string]float64{base: 1}
+	for currency, rate := range rates {
+		if rate > 0 {
+			perBase[currency] = rate
+		}
+	}
 
-	return exchangeRate, nil
+	usd, exists := perBase[string(model.USD)]
+	if !exists {
+		return nil, false
+	}
+	quotes := make(map[string]float64, len(perBase))
+	for currency, rate := range perBase {
+		quotes["USD"+currency] = rate / usd
+	}
+	return quotes, true
 }
 
 func (e *ExchangeAPI) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
@@ -199,24 +292,25 @@ func (e *ExchangeAPI) FetchHistoricalRate(ctx context.Context, pair model.Curren
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	tracing.Inject(ctx, req.Header)
 
 	resp, err := e.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, sendError(e.name, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned non-OK status: %d", resp.StatusCode)
+		return nil, statusError(e.name, resp)
 	}
 
 	var apiResp exchangerateAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, decodeError(e.name, err)
 	}
 
 	if !apiResp.Success {
-		return nil, fmt.Errorf("API reported failure")
+		return nil, reportedError(e.name, apiResp.Error)
 	}
 
 	tempPair := model.CurrencyPair{
@@ -238,7 +332,7 @@ func (e *ExchangeAPI) extractHistoricalRate(quotes map[string]float64, pair mode
 		rateKey := fmt.Sprintf("USD%s", pair.TargetCurrency)
 		rate, exists := quotes[rateKey]
 		if !exists {
-			return nil, fmt.Errorf("rate not found for currency: %s", pair.TargetCurrency)
+			return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.TargetCurrency)
 		}
 
 		return &model.ExchangeRate{
@@ -246,7 +340,8 @@ func (e *ExchangeAPI) extractHistoricalRate(quotes map[string]float64, pair mode
 			TargetCurrency: pair.TargetCurrency,
 			Rate:           rate,
 			Date:           date,
-			LastUpdated:    time.Now(),
+			LastUpdated:    e.clock.Now(),
+			Unit:           pair.QuoteUnit(),
 		}, nil
 	}
 
@@ -254,7 +349,7 @@ func (e *ExchangeAPI) extractHistoricalRate(quotes map[string]float64, pair mode
 		rateKey := fmt.Sprintf("USD%s", pair.BaseCurrency)
 		rate, exists := quotes[rateKey]
 		if !exists {
-			return nil, fmt.Errorf("rate not found for currency: %s", pair.BaseCurrency)
+			return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.BaseCurrency)
 		}
 
 		return &model.ExchangeRate{
@@ -262,7 +357,8 @@ func (e *ExchangeAPI) extractHistoricalRate(quotes map[string]float64, pair mode
 			TargetCurrency: pair.TargetCurrency,
 			Rate:           1.0 / rate,
 			Date:           date,
-			LastUpdated:    time.Now(),
+			LastUpdated:    e.clock.Now(),
+			Unit:           pair.QuoteUnit(),
 		}, nil
 	}
 
@@ -273,10 +369,10 @@ func (e *ExchangeAPI) extractHistoricalRate(quotes map[string]float64, pair mode
 	targetRate, targetExists := quotes[targetUsdKey]
 
 	if !baseExists {
-		return nil, fmt.Errorf("rate not found for currency: %s", pair.BaseCurrency)
+		return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.BaseCurrency)
 	}
 	if !targetExists {
-		return nil, fmt.Errorf("rate not found for currency: %s", pair.TargetCurrency)
+		return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.TargetCurrency)
 	}
 
 	return &model.ExchangeRate{
@@ -284,10 +380,13 @@ func (e *ExchangeAPI) extractHistoricalRate(quotes map[string]float64, pair mode
 		TargetCurrency: pair.TargetCurrency,
 		Rate:           targetRate / baseRate,
 		Date:           date,
-		LastUpdated:    time.Now(),
+		LastUpdated:    e.clock.Now(),
+		Unit:           pair.QuoteUnit(),
 	}, nil
 }
 
+// FetchHistoricalRates asks the provider for the whole range in one timeframe
+// call, falling back to a call per date for plans without the endpoint
 func (e *ExchangeAPI) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
 
 	result := &model.HistoricalRates{
@@ -296,6 +395,37 @@ func (e *ExchangeAPI) FetchHistoricalRates(ctx context.Context, request model.Hi
 		Rates:          make(map[string]model.ExchangeRate),
 	}
 
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+
+	timeframe, err := e.fetchTimeframe(ctx, request.StartDate, request.EndDate)
+	if err != nil {
+		e.log.Warn("Timeframe request failed, fetching dates one by one", "error", err)
+		return e.fetchHistoricalRatesDaily(ctx, request, result)
+	}
+
+	for dateKey, quotes := range timeframe {
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil || date.Before(request.StartDate) || date.After(request.EndDate) {
+			continue
+		}
+
+		rate, err := e.extractHistoricalRate(quotes, pair, date)
+		if err != nil {
+			e.log.Error("Failed to extract historical rate", "error", err, "date", dateKey)
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		result.Rates[dateKey] = *rate
+	}
+
+	return result, nil
+}
+
+func (e *ExchangeAPI) fetchHistoricalRatesDaily(ctx context.Context, request model.HistoricalRateRequest, result *model.HistoricalRates) (*model.HistoricalRates, error) {
+
 	currentDate := request.StartDate
 	for !currentDate.After(request.EndDate) {
 
@@ -307,6 +437,7 @@ func (e *ExchangeAPI) FetchHistoricalRates(ctx context.Context, request model.Hi
 		rate, err := e.FetchHistoricalRate(ctx, pair, currentDate)
 		if err != nil {
 			e.log.Error("Failed to fetch historical rate", "error", err, "date", currentDate.Format("2006-01-02"))
+			result.Errors = append(result.Errors, model.NewItemError(currentDate.Format("2006-01-02"), err))
 
 			currentDate = currentDate.AddDate(0, 0, 1)
 			continue
@@ -321,36 +452,137 @@ func (e *ExchangeAPI) FetchHistoricalRates(ctx context.Context, request model.Hi
 	return result, nil
 }
 
+// timeframeResponse holds USD quotes per date, like the historical endpoint
+// does for a single date
+type timeframeResponse struct {
+	Success bool                          `json:"success"`
+	Source  string                        `json:"source"`
+	Quotes  map[string]map[string]float64 `json:"quotes"`
+	Error   *providerFailure              `json:"error,omitempty"`
+}
+
+// fetchTimeframe returns the USD quotes of every date from start to end,
+// keyed by date
+func (e *ExchangeAPI) fetchTimeframe(ctx context.Context, start, end time.Time) (map[string]map[string]float64, error) {
+
+	url := fmt.Sprintf("%s/timeframe?start_date=%s&end_date=%s&source=USD",
+		e.baseURL,
+		start.Format("2006-01-02"),
+		end.Format("2006-01-02"),
+	)
+
+	if e.apiKey != "" {
+		url += "&access_key=" + e.apiKey
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, sendError(e.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(e.name, resp)
+	}
+
+	var apiResp timeframeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, decodeError(e.name, err)
+	}
+
+	if !apiResp.Success {
+		return nil, reportedError(e.name, apiResp.Error)
+	}
+
+	return apiResp.Quotes, nil
+}
+
+// FetchRaw calls a provider endpoint such as "live" with query, adding the API
+// key, and returns the status and body unchanged
+func (e *ExchangeAPI) FetchRaw(ctx context.Context, endpoint string, query url.Values) (int, []byte, error) {
+	query = maps.Clone(query)
+	if query == nil {
+		query = url.Values{}
+	}
+	if e.apiKey != "" {
+		query.Set("access_key", e.apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+"/"+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, sendError(e.name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRawResponse))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+func (e *ExchangeAPI) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return snapshotRates(e.latest.Load())
+}
+
+// RefreshRates fetches all rates in two phases. The rates are first staged
+// into a new snapshot and checked against the guardrail; only when they pass
+// is the snapshot swapped in, so readers never see a partial set.
 func (e *ExchangeAPI) RefreshRates(ctx context.Context) error {
 	e.log.Info("Refreshing all exchange rates")
 
-	e.mutex.Lock()
-	e.latestRates = make(map[string]*model.ExchangeRate)
-	e.mutex.Unlock()
-
-	rates, err := e.fetchAllLatestRates(ctx)
+	quotes, err := e.fetchAllLatestRates(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch latest rates: %w", err)
 	}
 
-	for _, base := range model.SupportedCurrencies {
-		for _, target := range model.SupportedCurrencies {
-			if base == target {
-				continue
-			}
+	if err := e.promoteQuotes(&e.latest, quotes, e.clock.Now(), e.log); err != nil {
+		return err
+	}
 
-			pair := model.CurrencyPair{
-				BaseCurrency:   base,
-				TargetCurrency: target,
-			}
+	e.log.Info("Successfully refreshed all exchange rates")
+	return nil
+}
 
-			_, err := e.extractRate(rates, pair)
-			if err != nil {
-				e.log.Error("Failed to extract rate", "error", err, "pair", pair.String())
+// RefreshPairs fetches only the quotes pairs need and refreshes their rates,
+// keeping the rates of every other pair from earlier refreshes
+func (e *ExchangeAPI) RefreshPairs(ctx context.Context, pairs []model.CurrencyPair) error {
+	e.log.Info("Refreshing exchange rates of selected pairs", "pairs", len(pairs))
+
+	currencies := make([]string, 0)
+	for _, pair := range pairs {
+		for _, currency := range []model.Currency{pair.BaseCurrency, pair.TargetCurrency} {
+			if currency != model.USD && !slices.Contains(currencies, string(currency)) {
+				currencies = append(currencies, string(currency))
 			}
 		}
 	}
+	sort.Strings(currencies)
 
-	e.log.Info("Successfully refreshed all exchange rates")
-	return nil
+	fetched, err := e.fetchLatestQuotes(ctx, currencies)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest rates: %w", err)
+	}
+
+	// Quotes of currencies left out keep their earlier value, so the digest
+	// and the guardrail compare like with like
+	quotes := maps.Clone(e.latest.Load().quotes)
+	if quotes == nil {
+		quotes = make(map[string]float64)
+	}
+	maps.Copy(quotes, fetched)
+
+	return e.promote(&e.latest, quotes, pairs, true, e.clock.Now(), e.log)
 }
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/tracing"
+)
+
+func TestRefreshNeverExposesMissingRates(t *testing.T) {
+	// Quotes move slightly on every call so each refresh swaps in a new snapshot
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inr := 83 + float64(calls.Add(1))/1000
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{
+			Success: true,
+			Source:  "USD",
+			Quotes:  map[string]float64{"USDINR": inr, "USDEUR": 0.92, "USDJPY": 150, "USDGBP": 0.79},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	ctx := context.Background()
+	if err := api.RefreshRates(ctx); err != nil {
+		t.Fatalf("Initial refresh failed: %v", err)
+	}
+	want := len(api.LatestRates(ctx))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if got := len(api.LatestRates(ctx)); got != want {
+				t.Errorf("Expected %d rates during a refresh, got %d", want, got)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		if err := api.RefreshRates(ctx); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if _, err := api.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: model.EUR, TargetCurrency: model.JPY}); err != nil {
+		t.Errorf("Expected EUR-JPY to be served, got %v", err)
+	}
+}
+
+func TestRefreshSkipsUnchangedQuotes(t *testing.T) {
+	quotes := map[string]float64{"USDINR": 83, "USDEUR": 0.92, "USDJPY": 150, "USDGBP": 0.79}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{Success: true, Source: "USD", Quotes: quotes})
+	}))
+	t.Cleanup(server.Close)
+
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	ctx := context.Background()
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+
+	if err := api.RefreshRates(ctx); err != nil {
+		t.Fatalf("Initial refresh failed: %v", err)
+	}
+	first, _ := api.FetchLatestRate(ctx, pair)
+
+	if err := api.RefreshRates(ctx); !errors.Is(err, ports.ErrRatesUnchanged) {
+		t.Fatalf("Expected ErrRatesUnchanged for identical quotes, got %v", err)
+	}
+	if unchanged, _ := api.FetchLatestRate(ctx, pair); unchanged != first {
+		t.Error("Expected the serving rates to be kept when quotes are unchanged")
+	}
+
+	quotes = map[string]float64{"USDINR": 83.1, "USDEUR": 0.92, "USDJPY": 150, "USDGBP": 0.79}
+	if err := api.RefreshRates(ctx); err != nil {
+		t.Fatalf("Expected changed quotes to be served, got %v", err)
+	}
+	if changed, _ := api.FetchLatestRate(ctx, pair); changed.Rate != 83.1 {
+		t.Errorf("Expected the new rate, got %v", changed.Rate)
+	}
+}
+
+func TestProviderRequestsCarryTrace(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("traceparent")
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{
+			Success: true,
+			Source:  "USD",
+			Quotes:  map[string]float64{"USDINR": 83},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	header := http.Header{}
+	header.Set("traceparent", traceparent)
+	trace, _ := tracing.Extract(header)
+	ctx := tracing.NewContext(context.Background(), trace)
+
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	date := time.Now().UTC().AddDate(0, 0, -1).Truncate(24 * time.Hour)
+	if _, err := api.FetchHistoricalRate(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}, date); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if got := <-received; got != traceparent {
+		t.Errorf("Expected the provider to receive traceparent %q, got %q", traceparent, got)
+	}
+}
+
+func TestFetchHistoricalRatesUsesTimeframe(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	testCases := []struct {
+		name      string
+		timeframe bool
+		calls     int
+	}{
+		{name: "One timeframe call", timeframe: true, calls: 1},
+		{name: "Daily calls without timeframe", timeframe: false, calls: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				switch r.URL.Path {
+				case "/timeframe":
+					if !tc.timeframe {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					quotes := map[string]map[string]float64{}
+					for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+						quotes[d.Format("2006-01-02")] = map[string]float64{"USDINR": 83}
+					}
+					json.NewEncoder(w).Encode(timeframeResponse{Success: true, Source: "USD", Quotes: quotes})
+				case "/historical":
+					json.NewEncoder(w).Encode(exchangerateAPIResponse{Success: true, Source: "USD", Quotes: map[string]float64{"USDINR": 83}})
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+			rates, err := api.FetchHistoricalRates(context.Background(), model.HistoricalRateRequest{
+				BaseCurrency:   model.INR,
+				TargetCurrency: model.USD,
+				StartDate:      start,
+				EndDate:        end,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rates.Rates) != 3 || rates.Rates["2024-03-02"].Rate != 1.0/83 {
+				t.Errorf("Expected 3 INR-USD rates, got %+v", rates.Rates)
+			}
+			if got := calls.Load(); got != int64(tc.calls) {
+				t.Errorf("Expected %d provider calls, got %d", tc.calls, got)
+			}
+		})
+	}
+}
+
+func TestRefreshPairsKeepsOtherRates(t *testing.T) {
+	var currencies atomic.Value
+	currencies.Store("")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested := r.URL.Query().Get("currencies")
+		currencies.Store(requested)
+
+		quotes := map[string]float64{"USDINR": 83, "USDEUR": 0.92, "USDJPY": 150}
+		if requested != "" {
+			quotes = map[string]float64{"USDINR": 84}
+		}
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{Success: true, Source: "USD", Quotes: quotes})
+	}))
+	t.Cleanup(server.Close)
+
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	ctx := context.Background()
+	if err := api.RefreshRates(ctx); err != nil {
+		t.Fatal(err)
+	}
+	full := len(api.LatestRates(ctx))
+
+	if err := api.RefreshPairs(ctx, []model.CurrencyPair{{BaseCurrency: model.USD, TargetCurrency: model.INR}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := currencies.Load(); got != "INR" {
+		t.Errorf("Expected only INR to be requested, got %q", got)
+	}
+	if got := len(api.LatestRates(ctx)); got != full {
+		t.Errorf("Expected %d rates to be kept, got %d", full, got)
+	}
+
+	inr, _ := api.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR})
+	eurInr, _ := api.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: model.EUR, TargetCurrency: model.INR})
+	if inr.Rate != 84 || eurInr.Rate != 83/0.92 {
+		t.Errorf("Expected USD-INR refreshed and EUR-INR kept, got %v and %v", inr.Rate, eurInr.Rate)
+	}
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/tracing"
+)
+
+const (
+	FixerProviderName = "fixer"
+	FixerBaseURL      = "https://data.fixer.io/api"
+)
+
+// Fixer fetches rates from a Fixer.io compatible API. Rates are quoted per
+// unit of a base currency, EUR on the free plan, and asked for only for the
+// supported currencies; every pair is crossed through the base.
+type Fixer struct {
+	baseURL    string
+	apiKey     string
+	base       string
+	httpClient *http.Client
+	clock      clock.Clock
+	log        *logger.Logger
+
+	// latest is replaced whole, never modified, so readers need no lock
+	latest atomic.Pointer[rateSnapshot]
+	guardrail
+}
+
+// fixerResponse is the body of the latest and historical endpoints
+type fixerResponse struct {
+	Success bool               `json:"success"`
+	Base    string             `json:"base"`
+	Rates   map[string]float64 `json:"rates"`
+	Error   *providerFailure   `json:"error,omitempty"`
+}
+
+// fixerTimeseriesResponse holds the rates of every date in the range, keyed
+// by date
+type fixerTimeseriesResponse struct {
+	Success bool                          `json:"success"`
+	Base    string                        `json:"base"`
+	Rates   map[string]map[string]float64 `json:"rates"`
+	Error   *providerFailure              `json:"error,omitempty"`
+}
+
+// NewFixer creates a client of the Fixer.io compatible API at baseURL. base is
+// the currency rates are asked against; the free plan only allows EUR.
+func NewFixer(baseURL, apiKey, base string, timeout time.Duration, sla ports.SLAStore, log *logger.Logger) *Fixer {
+	f := &Fixer{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		base:    base,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: newSLATransport(FixerProviderName, http.DefaultTransport, sla),
+		},
+		clock: clock.System,
+		log:   log,
+	}
+	f.latest.Store(&rateSnapshot{rates: make(map[string]*model.ExchangeRate)})
+	return f
+}
+
+// UseClock replaces the wall clock that dates and timestamps latest rates
+func (f *Fixer) UseClock(c clock.Clock) {
+	f.clock = c
+}
+
+func (f *Fixer) Name() string {
+	return FixerProviderName
+}
+
+func (f *Fixer) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	if rate, exists := f.latest.Load().rates[pair.String()]; exists {
+		return rate, nil
+	}
+
+	quotes, err := f.fetchQuotes(ctx, "latest")
+	if err != nil {
+		return nil, err
+	}
+	return computeRate(quotes, pair, f.clock.Now())
+}
+
+func (f *Fixer) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	quotes, err := f.fetchQuotes(ctx, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return f.historicalRate(quotes, pair, date)
+}
+
+// FetchHistoricalRates asks for the whole range in one timeseries call,
+// falling back to a call per date for plans without the endpoint
+func (f *Fixer) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+
+	days, err := f.fetchTimeseries(ctx, request.StartDate, request.EndDate)
+	if err != nil {
+		f.log.Warn("Timeseries request failed, fetching dates one by one", "error", err)
+		for date := request.StartDate; !date.After(request.EndDate); date = date.AddDate(0, 0, 1) {
+			rate, err := f.FetchHistoricalRate(ctx, pair, date)
+			if err != nil {
+				f.log.Error("Failed to fetch historical rate", "error", err, "date", date.Format("2006-01-02"))
+				result.Errors = append(result.Errors, model.NewItemError(date.Format("2006-01-02"), err))
+				continue
+			}
+			result.Rates[date.Format("2006-01-02")] = *rate
+		}
+		return result, nil
+	}
+
+	for dateKey, quotes := range days {
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil || date.Before(request.StartDate) || date.After(request.EndDate) {
+			continue
+		}
+
+		rate, err := f.historicalRate(quotes, pair, date)
+		if err != nil {
+			f.log.Error("Failed to extract historical rate", "error", err, "date", dateKey)
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		result.Rates[dateKey] = *rate
+	}
+
+	return result, nil
+}
+
+// historicalRate computes the rate of pair on date
+func (f *Fixer) historicalRate(quotes map[string]float64, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	rate, err := computeRate(quotes, pair, f.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+// RefreshRates fetches the latest rates and serves every supported pair from
+// them. A refresh that finds the same rates on the same day returns
+// ports.ErrRatesUnchanged.
+func (f *Fixer) RefreshRates(ctx context.Context) error {
+	f.log.Info("Refreshing all exchange rates")
+
+	quotes, err := f.fetchQuotes(ctx, "latest")
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest rates: %w", err)
+	}
+
+	if err := f.promoteQuotes(&f.latest, quotes, f.clock.Now(), f.log); err != nil {
+		return err
+	}
+	f.log.Info("Successfully refreshed all exchange rates")
+	return nil
+}
+
+func (f *Fixer) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return snapshotRates(f.latest.Load())
+}
+
+// fetchQuotes calls endpoint, "latest" or a date, and returns its rates as USD
+// quotes
+func (f *Fixer) fetchQuotes(ctx context.Context, endpoint string) (map[string]float64, error) {
+	var apiResp fixerResponse
+	if err := f.get(ctx, endpoint, url.Values{}, &apiResp); err != nil {
+		return nil, err
+	}
+	if !apiResp.Success {
+		return nil, reportedError(FixerProviderName, apiResp.Error)
+	}
+
+	quotes, ok := usdQuotes(f.quoteBase(apiResp.Base), apiResp.Rates)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, model.USD)
+	}
+	return quotes, nil
+}
+
+// fetchTimeseries returns the USD quotes of every date from start to end,
+// keyed by date
+func (f *Fixer) fetchTimeseries(ctx context.Context, start, end time.Time) (map[string]map[string]float64, error) {
+	query := url.Values{}
+	query.Set("start_date", start.Format("2006-01-02"))
+	query.Set("end_date", end.Format("2006-01-02"))
+
+	var apiResp fixerTimeseriesResponse
+	if err := f.get(ctx, "timeseries", query, &apiResp); err != nil {
+		return nil, err
+	}
+	if !apiResp.Success {
+		return nil, reportedError(FixerProviderName, apiResp.Error)
+	}
+
+	days := make(map[string]map[string]float64, len(apiResp.Rates))
+	for dateKey, rates := range apiResp.Rates {
+		quotes, ok := usdQuotes(f.quoteBase(apiResp.Base), rates)
+		if !ok {
+			f.log.Warn("Rates without USD, skipping the day", "date", dateKey)
+			continue
+		}
+		days[dateKey] = quotes
+	}
+	return days, nil
+}
+
+// quoteBase is the base a response reports, or the one asked for when it
+// reports none
+func (f *Fixer) quoteBase(reported string) string {
+	if reported == "" {
+		return f.base
+	}
+	return reported
+}
+
+// get calls endpoint with query, the API key, the base and the supported
+// symbols, and decodes the response into into. The base is left out when it
+// is EUR, the only one the free plan accepts.
+func (f *Fixer) get(ctx context.Context, endpoint string, query url.Values, into any) error {
+	if f.apiKey != "" {
+		query.Set("access_key", f.apiKey)
+	}
+	if f.base != string(model.EUR) {
+		query.Set("base", f.base)
+	}
+	symbols := make([]string, 0, len(model.SupportedCurrencies))
+	for _, currency := range model.SupportedCurrencies {
+		if string(currency) != f.base {
+			symbols = append(symbols, string(currency))
+		}
+	}
+	query.Set("symbols", strings.Join(symbols, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.baseURL+"/"+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return sendError(FixerProviderName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(FixerProviderName, resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+		return decodeError(FixerProviderName, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestFixerRates(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("access_key") != "secret" {
+			w.Write([]byte(`{"success":false,"error":{"code":101,"type":"missing_access_key","info":"You have not supplied an API Access Key."}}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/latest":
+			w.Write([]byte(`{"success":true,"base":"EUR","date":"2025-03-07","rates":{"USD":1.08,"INR":94.5,"JPY":160,"GBP":0.84}}`))
+		case "/2025-03-06":
+			w.Write([]byte(`{"success":true,"historical":true,"base":"EUR","date":"2025-03-06","rates":{"USD":1.0,"INR":90}}`))
+		case "/timeseries":
+			w.Write([]byte(`{"success":false,"error":{"code":106,"type":"function_access_restricted","info":"Your current subscription plan does not support this API function."}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	fixer := NewFixer(server.URL, "secret", "EUR", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	fixer.UseClock(clock.NewFake(time.Date(2025, 3, 7, 12, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+
+	if err := fixer.RefreshRates(ctx); err != nil {
+		t.Fatalf("RefreshRates failed: %v", err)
+	}
+	// The free plan only allows the default base, so it is not sent
+	if want := "access_key=secret&symbols=USD%2CINR%2CJPY%2CGBP"; queries[0] != want {
+		t.Errorf("Expected query %q, got %q", want, queries[0])
+	}
+
+	rate, err := fixer.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR})
+	if err != nil {
+		t.Fatalf("FetchLatestRate failed: %v", err)
+	}
+	if math.Abs(rate.Rate-94.5/1.08) > 1e-9 {
+		t.Errorf("Expected USD-INR crossed through EUR, got %v", rate.Rate)
+	}
+
+	// Without the timeseries endpoint, the range is fetched a date at a time
+	rates, err := fixer.FetchHistoricalRates(ctx, model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("FetchHistoricalRates failed: %v", err)
+	}
+	if len(rates.Rates) != 1 || rates.Rates["2025-03-06"].Rate != 90 {
+		t.Errorf("Unexpected historical rates: %+v", rates.Rates)
+	}
+
+	unauthorized := NewFixer(server.URL, "", "EUR", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	err = unauthorized.RefreshRates(ctx)
+	if xerrors.KindOf(err) != xerrors.Upstream || xerrors.CodeOf(err) != "missing_access_key" {
+		t.Errorf("Expected the provider's error code, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+const FixtureProviderName = "fixture"
+
+// Fixture serves rates from a local file instead of an upstream API, for CI,
+// demos and development offline. Dates without rates of their own are served
+// the latest ones, so a file of latest rates alone answers every endpoint.
+//
+// A .json file holds rates per unit of base, USD by default, with optional
+// historical rates by date:
+//
+//	{"base": "USD", "rates": {"EUR": 0.92}, "historical": {"2025-01-02": {"EUR": 0.91}}}
+//
+// A .csv file has a date,currency,rate header and rates per unit of USD; rows
+// without a date are the latest rates.
+type Fixture struct {
+	path  string
+	clock clock.Clock
+	log   *logger.Logger
+
+	// rates is the file as last read, replaced whole like latest
+	rates  atomic.Pointer[fixtureRates]
+	latest atomic.Pointer[rateSnapshot]
+	guardrail
+}
+
+// fixtureRates holds the USD quotes of a fixture file
+type fixtureRates struct {
+	latest     map[string]float64
+	historical map[string]map[string]float64
+}
+
+// fixtureFile is the shape of a .json fixture
+type fixtureFile struct {
+	Base       string                        `json:"base"`
+	Rates      map[string]float64            `json:"rates"`
+	Historical map[string]map[string]float64 `json:"historical"`
+}
+
+// NewFixture reads the fixture at path, failing when it cannot be served
+func NewFixture(path string, log *logger.Logger) (*Fixture, error) {
+	f := &Fixture{
+		path:  path,
+		clock: clock.System,
+		log:   log,
+	}
+	f.latest.Store(&rateSnapshot{rates: make(map[string]*model.ExchangeRate)})
+
+	rates, err := readFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	f.rates.Store(rates)
+	return f, nil
+}
+
+// UseClock replaces the wall clock that dates and timestamps rates
+func (f *Fixture) UseClock(c clock.Clock) {
+	f.clock = c
+}
+
+func (f *Fixture) Name() string {
+	return FixtureProviderName
+}
+
+func (f *Fixture) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	if rate, exists := f.latest.Load().rates[pair.String()]; exists {
+		return rate, nil
+	}
+	return computeRate(f.rates.Load().latest, pair, f.clock.Now())
+}
+
+func (f *Fixture) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	rates := f.rates.Load()
+	quotes, exists := rates.historical[date.Format("2006-01-02")]
+	if !exists {
+		quotes = rates.latest
+	}
+
+	rate, err := computeRate(quotes, pair, f.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+func (f *Fixture) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+
+	for date := request.StartDate; !date.After(request.EndDate); date = date.AddDate(0, 0, 1) {
+		dateKey := date.Format("2006-01-02")
+		rate, err := f.FetchHistoricalRate(ctx, pair, date)
+		if err != nil {
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		result.Rates[dateKey] = *rate
+	}
+
+	return result, nil
+}
+
+// RefreshRates reads the file again, so edits are picked up without a
+// restart, and serves every supported pair from its latest rates. An
+// unreadable file keeps the rates of the last good read.
+func (f *Fixture) RefreshRates(ctx context.Context) error {
+	rates, err := readFixture(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	f.rates.Store(rates)
+
+	return f.promoteQuotes(&f.latest, rates.latest, f.clock.Now(), f.log)
+}
+
+func (f *Fixture) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return snapshotRates(f.latest.Load())
+}
+
+// readFixture reads the fixture at path, in the format of its extension
+func readFixture(path string) (*fixtureRates, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture: %w", err)
+	}
+	defer file.Close()
+
+	var rates *fixtureRates
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		rates, err = decodeJSONFixture(file)
+	case ".csv":
+		rates, err = decodeCSVFixture(file)
+	default:
+		return nil, fmt.Errorf("fixture %s must be a .json or .csv file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	// The quotes always hold USD itself
+	if len(rates.latest) < 2 {
+		return nil, fmt.Errorf("fixture %s has no latest rates", path)
+	}
+	return rates, nil
+}
+
+func decodeJSONFixture(r io.Reader) (*fixtureRates, error) {
+	var file fixtureFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	base := strings.ToUpper(file.Base)
+	if base == "" {
+		base = string(model.USD)
+	}
+
+	latest, ok := usdQuotes(base, file.Rates)
+	if !ok {
+		return nil, fmt.Errorf("rates have no %s rate", model.USD)
+	}
+	rates := &fixtureRates{latest: latest, historical: make(map[string]map[string]float64, len(file.Historical))}
+	for date, perBase := range file.Historical {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("historical date %q is not YYYY-MM-DD", date)
+		}
+		quotes, ok := usdQuotes(base, perBase)
+		if !ok {
+			return nil, fmt.Errorf("rates of %s have no %s rate", date, model.USD)
+		}
+		rates.historical[date] = quotes
+	}
+	return rates, nil
+}
+
+func decodeCSVFixture(r io.Reader) (*fixtureRates, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if strings.Join(header, ",") != "date,currency,rate" {
+		return nil, errors.New("header must be date,currency,rate")
+	}
+
+	rates := &fixtureRates{
+		latest:     make(map[string]float64),
+		historical: make(map[string]map[string]float64),
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			rates.latest["USD"+string(model.USD)] = 1
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		date, currency := record[0], strings.ToUpper(record[1])
+		rate, err := strconv.ParseFloat(record[2], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("rate of %s %s must be a positive number, got %q", currency, date, record[2])
+		}
+
+		quotes := rates.latest
+		if date != "" {
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return nil, fmt.Errorf("date %q is not YYYY-MM-DD", date)
+			}
+			if rates.historical[date] == nil {
+				rates.historical[date] = map[string]float64{"USD" + string(model.USD): 1}
+			}
+			quotes = rates.historical[date]
+		}
+		quotes["USD"+currency] = rate
+	}
+}
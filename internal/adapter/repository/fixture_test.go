@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestFixtureRates(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"rates.json": `{"base":"EUR","rates":{"USD":1.25,"INR":100},"historical":{"2025-01-02":{"USD":1.0,"INR":90}}}`,
+		"rates.csv":  "date,currency,rate\n,EUR,0.8\n,INR,80\n2025-01-02,EUR,1\n2025-01-02,INR,90\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			fixture, err := NewFixture(path, logger.NewLogger("error"))
+			if err != nil {
+				t.Fatalf("NewFixture failed: %v", err)
+			}
+			ctx := context.Background()
+
+			if err := fixture.RefreshRates(ctx); err != nil {
+				t.Fatalf("RefreshRates failed: %v", err)
+			}
+			pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+			rate, err := fixture.FetchLatestRate(ctx, pair)
+			if err != nil || rate.Rate != 80 {
+				t.Errorf("Expected a latest USD-INR of 80, got %v, %v", rate, err)
+			}
+
+			request := model.HistoricalRateRequest{
+				BaseCurrency:   model.USD,
+				TargetCurrency: model.INR,
+				StartDate:      time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+				EndDate:        time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+			}
+			history, err := fixture.FetchHistoricalRates(ctx, request)
+			if err != nil {
+				t.Fatalf("FetchHistoricalRates failed: %v", err)
+			}
+			if got := history.Rates["2025-01-02"].Rate; got != 90 {
+				t.Errorf("Expected 90 on 2025-01-02, got %v", got)
+			}
+			// A date without rates of its own is served the latest ones
+			if got := history.Rates["2025-01-03"].Rate; got != 80 {
+				t.Errorf("Expected the latest 80 on 2025-01-03, got %v", got)
+			}
+
+			missing := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.JPY}
+			if _, err := fixture.FetchLatestRate(ctx, missing); !errors.Is(err, ports.ErrQuoteNotFound) {
+				t.Errorf("Expected ErrQuoteNotFound for a currency not in the fixture, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFixtureRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"no_usd.json":    `{"base":"EUR","rates":{"INR":100}}`,
+		"empty.json":     `{"rates":{}}`,
+		"bad_header.csv": "currency,rate,date\nINR,80,\n",
+		"bad_rate.csv":   "date,currency,rate\n,INR,lots\n",
+		"rates.yaml":     "rates: {}",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewFixture(path, logger.NewLogger("error")); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+	if _, err := NewFixture(filepath.Join(dir, "missing.json"), logger.NewLogger("error")); err == nil {
+		t.Error("Expected a missing file to be rejected")
+	}
+}
+
+func TestShippedFixture(t *testing.T) {
+	fixture, err := NewProvider(FixtureProviderName, ProviderSettings{
+		BaseURL: filepath.Join("..", "..", "..", "fixtures", "rates.json"),
+		Log:     logger.NewLogger("error"),
+	})
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	if err := fixture.RefreshRates(context.Background()); err != nil {
+		t.Fatalf("RefreshRates failed: %v", err)
+	}
+	if got := len(fixture.LatestRates(context.Background())); got != 20 {
+		t.Errorf("Expected every supported pair, got %d rates", got)
+	}
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/tracing"
+)
+
+const (
+	FrankfurterProviderName = "frankfurter"
+	// FrankfurterBaseURL serves the ECB reference rates with any base, no key
+	// needed
+	FrankfurterBaseURL = "https://api.frankfurter.dev/v1"
+)
+
+// Frankfurter fetches rates from the free Frankfurter API, which needs no API
+// key and so suits local development. Its rates are the ECB reference rates,
+// asked for against USD; there are none for weekends and TARGET holidays.
+type Frankfurter struct {
+	baseURL    string
+	httpClient *http.Client
+	clock      clock.Clock
+	log        *logger.Logger
+
+	// latest is replaced whole, never modified, so readers need no lock
+	latest atomic.Pointer[rateSnapshot]
+	guardrail
+}
+
+// frankfurterResponse is the body of the latest and date endpoints. Date is
+// the working day the rates are from, which for a weekend or holiday is an
+// earlier one than asked for.
+type frankfurterResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// frankfurterRangeResponse holds the rates of every working day of a range,
+// keyed by date
+type frankfurterRangeResponse struct {
+	Base  string                        `json:"base"`
+	Rates map[string]map[string]float64 `json:"rates"`
+}
+
+func NewFrankfurter(baseURL string, timeout time.Duration, sla ports.SLAStore, log *logger.Logger) *Frankfurter {
+	f := &Frankfurter{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: newSLATransport(FrankfurterProviderName, http.DefaultTransport, sla),
+		},
+		clock: clock.System,
+		log:   log,
+	}
+	f.latest.Store(&rateSnapshot{rates: make(map[string]*model.ExchangeRate)})
+	return f
+}
+
+// UseClock replaces the wall clock that dates and timestamps latest rates
+func (f *Frankfurter) UseClock(c clock.Clock) {
+	f.clock = c
+}
+
+func (f *Frankfurter) Name() string {
+	return FrankfurterProviderName
+}
+
+func (f *Frankfurter) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	if rate, exists := f.latest.Load().rates[pair.String()]; exists {
+		return rate, nil
+	}
+
+	var apiResp frankfurterResponse
+	if err := f.get(ctx, "latest", &apiResp); err != nil {
+		return nil, err
+	}
+	quotes, err := frankfurterQuotes(apiResp.Base, apiResp.Rates)
+	if err != nil {
+		return nil, err
+	}
+	return computeRate(quotes, pair, f.clock.Now())
+}
+
+// FetchHistoricalRate returns ports.ErrQuoteNotFound for days without rates,
+// rather than the earlier working day Frankfurter answers with
+func (f *Frankfurter) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	dateKey := date.Format("2006-01-02")
+
+	var apiResp frankfurterResponse
+	if err := f.get(ctx, dateKey, &apiResp); err != nil {
+		return nil, err
+	}
+	if apiResp.Date != dateKey {
+		return nil, fmt.Errorf("%w: no Frankfurter rates on %s", ports.ErrQuoteNotFound, dateKey)
+	}
+
+	quotes, err := frankfurterQuotes(apiResp.Base, apiResp.Rates)
+	if err != nil {
+		return nil, err
+	}
+	return f.historicalRate(quotes, pair, date)
+}
+
+// FetchHistoricalRates reads the whole range from the start_date..end_date
+// endpoint. Days without rates are left out.
+func (f *Frankfurter) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	var apiResp frankfurterRangeResponse
+	endpoint := request.StartDate.Format("2006-01-02") + ".." + request.EndDate.Format("2006-01-02")
+	if err := f.get(ctx, endpoint, &apiResp); err != nil {
+		return nil, err
+	}
+
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+
+	for dateKey, rates := range apiResp.Rates {
+		// The range starts at the working day before a weekend or holiday
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil || date.Before(request.StartDate) || date.After(request.EndDate) {
+			continue
+		}
+
+		quotes, err := frankfurterQuotes(apiResp.Base, rates)
+		if err != nil {
+			f.log.Error("Failed to extract historical rate", "error", err, "date", dateKey)
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		rate, err := f.historicalRate(quotes, pair, date)
+		if err != nil {
+			f.log.Error("Failed to extract historical rate", "error", err, "date", dateKey)
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		result.Rates[dateKey] = *rate
+	}
+
+	return result, nil
+}
+
+// historicalRate computes the rate of pair on date
+func (f *Frankfurter) historicalRate(quotes map[string]float64, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	rate, err := computeRate(quotes, pair, f.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+// RefreshRates fetches the latest rates and serves every supported pair from
+// them. A refresh that finds the same rates on the same day returns
+// ports.ErrRatesUnchanged.
+func (f *Frankfurter) RefreshRates(ctx context.Context) error {
+	f.log.Info("Refreshing all exchange rates")
+
+	var apiResp frankfurterResponse
+	if err := f.get(ctx, "latest", &apiResp); err != nil {
+		return fmt.Errorf("failed to fetch latest rates: %w", err)
+	}
+	quotes, err := frankfurterQuotes(apiResp.Base, apiResp.Rates)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest rates: %w", err)
+	}
+
+	if err := f.promoteQuotes(&f.latest, quotes, f.clock.Now(), f.log); err != nil {
+		return err
+	}
+	f.log.Info("Successfully refreshed all exchange rates")
+	return nil
+}
+
+func (f *Frankfurter) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return snapshotRates(f.latest.Load())
+}
+
+// frankfurterQuotes turns rates per unit of base into USD quotes
+func frankfurterQuotes(base string, rates map[string]float64) (map[string]float64, error) {
+	quotes, ok := usdQuotes(base, rates)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, model.USD)
+	}
+	return quotes, nil
+}
+
+// get calls endpoint for the supported currencies against USD and decodes the
+// response into into
+func (f *Frankfurter) get(ctx context.Context, endpoint string, into any) error {
+	symbols := make([]string, 0, len(model.SupportedCurrencies))
+	for _, currency := range model.SupportedCurrencies {
+		if currency != model.USD {
+			symbols = append(symbols, string(currency))
+		}
+	}
+	query := url.Values{}
+	query.Set("base", string(model.USD))
+	query.Set("symbols", strings.Join(symbols, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.baseURL+"/"+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	tracing.Inject(ctx, req.Header)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return sendError(FrankfurterProviderName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(FrankfurterProviderName, resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+		return decodeError(FrankfurterProviderName, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestFrankfurterRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("base") != "USD" || r.URL.Query().Get("symbols") != "INR,EUR,JPY,GBP" {
+			http.Error(w, `{"message":"bad request"}`, http.StatusUnprocessableEntity)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/latest":
+			w.Write([]byte(`{"amount":1.0,"base":"USD","date":"2025-03-07","rates":{"EUR":0.925,"GBP":0.775,"INR":87.0,"JPY":148.0}}`))
+		case "/2025-03-06":
+			w.Write([]byte(`{"amount":1.0,"base":"USD","date":"2025-03-06","rates":{"EUR":1.0,"INR":90.0}}`))
+		case "/2025-03-08":
+			// Frankfurter answers a Saturday with the Friday before
+			w.Write([]byte(`{"amount":1.0,"base":"USD","date":"2025-03-07","rates":{"EUR":0.925,"INR":87.0}}`))
+		case "/2025-03-06..2025-03-09":
+			w.Write([]byte(`{"amount":1.0,"base":"USD","start_date":"2025-03-06","end_date":"2025-03-07","rates":{"2025-03-06":{"EUR":1.0,"INR":90.0},"2025-03-07":{"EUR":0.925,"INR":87.0}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	frankfurter := NewFrankfurter(server.URL, time.Second, nopSLAStore(), logger.NewLogger("error"))
+	frankfurter.UseClock(clock.NewFake(time.Date(2025, 3, 9, 9, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+
+	if err := frankfurter.RefreshRates(ctx); err != nil {
+		t.Fatalf("RefreshRates failed: %v", err)
+	}
+	rate, err := frankfurter.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: model.EUR, TargetCurrency: model.INR})
+	if err != nil {
+		t.Fatalf("FetchLatestRate failed: %v", err)
+	}
+	if rate.Rate != 87.0/0.925 {
+		t.Errorf("Expected EUR-INR crossed through USD, got %v", rate.Rate)
+	}
+
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	rate, err = frankfurter.FetchHistoricalRate(ctx, pair, time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil || rate.Rate != 90 {
+		t.Errorf("Expected 90 on 2025-03-06, got %v, %v", rate, err)
+	}
+	if _, err := frankfurter.FetchHistoricalRate(ctx, pair, time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ports.ErrQuoteNotFound) {
+		t.Errorf("Expected ErrQuoteNotFound on a Saturday, got %v", err)
+	}
+
+	rates, err := frankfurter.FetchHistoricalRates(ctx, model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("FetchHistoricalRates failed: %v", err)
+	}
+	if len(rates.Rates) != 2 || rates.Rates["2025-03-07"].Rate != 87 {
+		t.Errorf("Unexpected historical rates: %+v", rates.Rates)
+	}
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+)
+
+// GuardrailError is returned by RefreshRates when the fetched rates were not
+// promoted because they moved too far from the serving ones
+type GuardrailError struct {
+	Moves []model.RateAlert
+}
+
+func (e *GuardrailError) Error() string {
+	pairs := make([]string, 0, len(e.Moves))
+	for _, move := range e.Moves {
+		pairs = append(pairs, fmt.Sprintf("%s-%s %+.2f%%", move.BaseCurrency, move.TargetCurrency, move.ChangePercent))
+	}
+	return "rates moved beyond the guardrail: " + strings.Join(pairs, ", ")
+}
+
+// guardrail promotes the rates of a provider, holding back those that moved
+// too far from the serving ones. Providers embed it, so all of them take
+// GuardMoves and every write to their serving rates goes through it.
+type guardrail struct {
+	// mutex serializes promotions and guards rejected, the rates the
+	// guardrail last held back, by pair
+	mutex    sync.Mutex
+	maxMove  float64
+	onReject func(moves []model.RateAlert)
+	rejected map[string]float64
+}
+
+// GuardMoves rejects refreshes in which the rate of a pair, a cross pair as
+// much as a USD one, moved by more than maxPercent from the serving one, and
+// reports them to onReject, which may be nil. A move that the next refresh
+// confirms, within maxPercent, is accepted as genuine. A maxPercent of 0
+// disables the guardrail.
+func (g *guardrail) GuardMoves(maxPercent float64, onReject func(moves []model.RateAlert)) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.maxMove = maxPercent
+	g.onReject = onReject
+}
+
+// supportedPairs lists every pair of supported currencies
+func supportedPairs() []model.CurrencyPair {
+	pairs := make([]model.CurrencyPair, 0, len(model.SupportedCurrencies)*(len(model.SupportedCurrencies)-1))
+	for _, base := range model.SupportedCurrencies {
+		for _, target := range model.SupportedCurrencies {
+			if base != target {
+				pairs = append(pairs, model.CurrencyPair{BaseCurrency: base, TargetCurrency: target})
+			}
+		}
+	}
+	return pairs
+}
+
+// promoteQuotes serves every supported pair from quotes, computed as of now.
+// It is the refresh of providers that always fetch every quote.
+func (g *guardrail) promoteQuotes(latest *atomic.Pointer[rateSnapshot], quotes map[string]float64, now time.Time, log *logger.Logger) error {
+	return g.promote(latest, quotes, supportedPairs(), false, now, log)
+}
+
+// promote computes the rates of pairs from quotes, as of now, and swaps them
+// in as the serving rates of latest. Quotes latest already serves today
+// return ports.ErrRatesUnchanged, and rates beyond the guardrail a
+// *GuardrailError, leaving the serving rates alone. With keep set, the rates
+// of other pairs are carried over.
+func (g *guardrail) promote(latest *atomic.Pointer[rateSnapshot], quotes map[string]float64, pairs []model.CurrencyPair, keep bool, now time.Time, log *logger.Logger) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	serving := latest.Load()
+	digest := quotesDigest(quotes, now.UTC())
+	if digest == serving.digest {
+		g.rejected = nil
+		log.Info("Provider quotes unchanged, keeping the serving rates")
+		return ports.ErrRatesUnchanged
+	}
+
+	fresh := make(map[string]*model.ExchangeRate, len(pairs))
+	for _, pair := range pairs {
+		rate, err := computeRate(quotes, pair, now)
+		if err != nil {
+			log.Error("Failed to extract rate", "error", err, "pair", pair.String())
+			continue
+		}
+		fresh[pair.String()] = rate
+	}
+
+	if err := g.check(serving, pairs, fresh, log); err != nil {
+		return err
+	}
+
+	staged := fresh
+	if keep {
+		staged = maps.Clone(serving.rates)
+		maps.Copy(staged, fresh)
+	}
+	latest.Store(&rateSnapshot{rates: staged, quotes: quotes, digest: digest})
+	g.rejected = nil
+	return nil
+}
+
+// store adds the single rate of pair to the serving rates of latest, unless
+// it moved beyond the guardrail from the rate the serving quotes give
+func (g *guardrail) store(latest *atomic.Pointer[rateSnapshot], pair model.CurrencyPair, rate *model.ExchangeRate, log *logger.Logger) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	serving := latest.Load()
+	if err := g.check(serving, []model.CurrencyPair{pair}, map[string]*model.ExchangeRate{pair.String(): rate}, log); err != nil {
+		return err
+	}
+
+	rates := make(map[string]*model.ExchangeRate, len(serving.rates)+1)
+	maps.Copy(rates, serving.rates)
+	rates[pair.String()] = rate
+	latest.Store(&rateSnapshot{rates: rates, quotes: serving.quotes, digest: serving.digest})
+	return nil
+}
+
+// check returns a *GuardrailError, after reporting it, when fresh rates
+// moved beyond the guardrail, and remembers them so the next refresh can
+// confirm them. The caller holds mutex.
+func (g *guardrail) check(serving *rateSnapshot, pairs []model.CurrencyPair, fresh map[string]*model.ExchangeRate, log *logger.Logger) error {
+	moves := g.excessiveMoves(serving, pairs, fresh)
+	if len(moves) == 0 {
+		return nil
+	}
+
+	g.rejected = make(map[string]float64, len(fresh))
+	for key, rate := range fresh {
+		g.rejected[key] = rate.Rate
+	}
+	log.Error("Refreshed rates rejected by the guardrail, keeping the serving rates", "pairs", len(moves), "max_move_percent", g.maxMove)
+	if g.onReject != nil {
+		g.onReject(moves)
+	}
+	return &GuardrailError{Moves: moves}
+}
+
+// excessiveMoves compares the fresh rate of each of pairs with the serving
+// one, or the one the serving quotes give, and returns the moves beyond the
+// guardrail that the previously rejected rates do not confirm. A pair and its
+// inverse move alike, so only the first of them in pairs is reported. The
+// caller holds mutex.
+func (g *guardrail) excessiveMoves(serving *rateSnapshot, pairs []model.CurrencyPair, fresh map[string]*model.ExchangeRate) []model.RateAlert {
+	if g.maxMove <= 0 {
+		return nil
+	}
+
+	moves := make([]model.RateAlert, 0)
+	checked := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		key := pair.String()
+		rate, exists := fresh[key]
+		inverse := model.CurrencyPair{BaseCurrency: pair.TargetCurrency, TargetCurrency: pair.BaseCurrency}
+		if !exists || checked[inverse.String()] {
+			continue
+		}
+		checked[key] = true
+
+		previous := servingRate(serving, pair)
+		if previous == 0 {
+			continue
+		}
+		change := (rate.Rate - previous) / previous * 100
+		if math.Abs(change) <= g.maxMove {
+			continue
+		}
+		if rejected, exists := g.rejected[key]; exists && rejected != 0 && math.Abs((rate.Rate-rejected)/rejected*100) <= g.maxMove {
+			continue
+		}
+
+		moves = append(moves, model.RateAlert{
+			BaseCurrency:   pair.BaseCurrency,
+			TargetCurrency: pair.TargetCurrency,
+			PreviousRate:   previous,
+			Rate:           rate.Rate,
+			ChangePercent:  math.Round(change*1e4) / 1e4,
+			Rejected:       true,
+		})
+	}
+	return moves
+}
+
+// servingRate is the rate of pair in serving, or computed from its quotes
+// when it holds none; 0 when neither has it
+func servingRate(serving *rateSnapshot, pair model.CurrencyPair) float64 {
+	if rate, exists := serving.rates[pair.String()]; exists {
+		return rate.Rate
+	}
+	if serving.quotes == nil {
+		return 0
+	}
+	rate, err := computeRate(serving.quotes, pair, time.Time{})
+	if err != nil {
+		return 0
+	}
+	return rate.Rate
+}
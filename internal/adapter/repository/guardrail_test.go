@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+// nopSLAStore discards the SLA data of the provider under test
+func nopSLAStore() *mocks.SLAStoreMock {
+	return &mocks.SLAStoreMock{
+		RecordFunc: func(ctx context.Context, provider string, latency time.Duration, success bool) {},
+	}
+}
+
+func TestGuardrailHoldsBackLargeMoves(t *testing.T) {
+	inr := 83.0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{
+			Success: true,
+			Source:  "USD",
+			Quotes:  map[string]float64{"USDINR": inr, "USDEUR": 0.92, "USDJPY": 150, "USDGBP": 0.79},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	var rejected []model.RateAlert
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	api.GuardMoves(10, func(moves []model.RateAlert) {
+		rejected = append(rejected, moves...)
+	})
+
+	ctx := context.Background()
+	servedINR := func() float64 {
+		rate, err := api.FetchLatestRate(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR})
+		if err != nil {
+			t.Fatalf("FetchLatestRate failed: %v", err)
+		}
+		return rate.Rate
+	}
+
+	if err := api.RefreshRates(ctx); err != nil {
+		t.Fatalf("Initial refresh failed: %v", err)
+	}
+
+	// A small move is promoted
+	inr = 85
+	if err := api.RefreshRates(ctx); err != nil || servedINR() != 85 {
+		t.Fatalf("Expected a small move to be served, got %v, %v", servedINR(), err)
+	}
+
+	// A spike is held back and reported
+	inr = 850
+	var guardrailErr *GuardrailError
+	if err := api.RefreshRates(ctx); !errors.As(err, &guardrailErr) {
+		t.Fatalf("Expected a guardrail error, got %v", err)
+	}
+	if servedINR() != 85 {
+		t.Errorf("Expected the previous rate to be served, got %v", servedINR())
+	}
+	// The crosses of INR moved as much, each reported once
+	if len(rejected) != 4 || rejected[0].BaseCurrency != model.USD || rejected[0].TargetCurrency != model.INR || !rejected[0].Rejected {
+		t.Fatalf("Expected the USD-INR move first of four, got %+v", rejected)
+	}
+	for _, move := range rejected[1:] {
+		if move.BaseCurrency != model.INR {
+			t.Errorf("Expected only INR crosses besides USD-INR, got %+v", move)
+		}
+	}
+
+	// The next refresh confirms the move, so it is promoted
+	inr = 845
+	if err := api.RefreshRates(ctx); err != nil || servedINR() != 845 {
+		t.Fatalf("Expected a confirmed move to be served, got %v, %v", servedINR(), err)
+	}
+}
+
+func TestGuardrailChecksCrossRates(t *testing.T) {
+	quotes := map[string]float64{"USDINR": 83, "USDEUR": 0.92, "USDJPY": 150, "USDGBP": 0.79}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{Success: true, Source: "USD", Quotes: quotes})
+	}))
+	t.Cleanup(server.Close)
+
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	api.GuardMoves(10, nil)
+	ctx := context.Background()
+	if err := api.RefreshRates(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each USD leg moves 6%, under the guardrail, but EUR-GBP moves 11%
+	quotes = map[string]float64{"USDINR": 83, "USDEUR": 0.92 * 1.06, "USDJPY": 150, "USDGBP": 0.79 * 0.94}
+	var guardrailErr *GuardrailError
+	if err := api.RefreshRates(ctx); !errors.As(err, &guardrailErr) {
+		t.Fatalf("Expected the cross move to be held back, got %v", err)
+	}
+	if len(guardrailErr.Moves) != 1 || guardrailErr.Moves[0].BaseCurrency != model.EUR || guardrailErr.Moves[0].TargetCurrency != model.GBP {
+		t.Errorf("Expected only EUR-GBP to move beyond the guardrail, got %+v", guardrailErr.Moves)
+	}
+}
+
+func TestGuardrailCoversEveryWritePath(t *testing.T) {
+	ctx := context.Background()
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+
+	// A single fetch of a pair not served yet is checked against the rate
+	// the serving quotes give
+	inr := 83.0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{Success: true, Source: "USD", Quotes: map[string]float64{"USDINR": inr, "USDEUR": 0.92}})
+	}))
+	t.Cleanup(server.Close)
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	api.GuardMoves(10, nil)
+	if err := api.RefreshPairs(ctx, []model.CurrencyPair{{BaseCurrency: model.USD, TargetCurrency: model.EUR}}); err != nil {
+		t.Fatal(err)
+	}
+	inr = 830
+	var guardrailErr *GuardrailError
+	if _, err := api.FetchLatestRate(ctx, pair); !errors.As(err, &guardrailErr) {
+		t.Errorf("Expected a single fetch beyond the guardrail to be held back, got %v", err)
+	}
+	// The next fetch confirms the move, so it is served
+	inr = 835
+	if rate, err := api.FetchLatestRate(ctx, pair); err != nil || rate.Rate != 835 {
+		t.Errorf("Expected the confirmed rate to be served, got %v, %v", rate, err)
+	}
+
+	// Providers refreshing through the shared path are guarded alike
+	path := filepath.Join(t.TempDir(), "rates.json")
+	write := func(inr float64) {
+		data, _ := json.Marshal(map[string]any{"rates": map[string]float64{"USD": 1, "INR": inr}})
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(83)
+	fixture, err := NewFixture(path, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture.GuardMoves(10, nil)
+	if err := fixture.RefreshRates(ctx); err != nil {
+		t.Fatal(err)
+	}
+	write(830)
+	if err := fixture.RefreshRates(ctx); !errors.As(err, &guardrailErr) {
+		t.Errorf("Expected the fixture refresh to be held back, got %v", err)
+	}
+	if rate, _ := fixture.FetchLatestRate(ctx, pair); rate.Rate != 83 {
+		t.Errorf("Expected the fixture to keep serving 83, got %v", rate.Rate)
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"exchange-rate-service/pkg/clock"
+)
+
+// RateLimiter is a token bucket pacing the calls made to a provider. It holds
+// up to burst tokens, refilled at perMinute a minute, and every call takes
+// one, waiting for it when the bucket is empty. It is safe for concurrent use;
+// waiting calls are served in order.
+type RateLimiter struct {
+	perSecond float64
+	burst     float64
+	clock     clock.Clock
+
+	mutex  sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a full bucket. perMinute must be positive; a burst
+// below 1 is 1.
+func NewRateLimiter(perMinute float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		perSecond: perMinute / 60,
+		burst:     float64(max(burst, 1)),
+		clock:     clock.System,
+		tokens:    float64(max(burst, 1)),
+	}
+}
+
+// UseClock replaces the wall clock that tokens are refilled on
+func (l *RateLimiter) UseClock(c clock.Clock) {
+	l.clock = c
+}
+
+// Wait takes a token, waiting for one as long as needed. It fails at once,
+// without a token, when ctx would be done before one is available.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		l.release()
+		return fmt.Errorf("outbound rate limit: next call allowed in %s, after the request deadline", delay.Round(time.Millisecond))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token, possibly ahead of time, and returns how long to wait
+// until it is due
+func (l *RateLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.clock.Now()
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.perSecond)
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+}
+
+// release gives back a token reserved for a call that was not made
+func (l *RateLimiter) release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// limitingTransport makes every request wait for a token of limiter
+type limitingTransport struct {
+	limiter *RateLimiter
+	next    http.RoundTripper
+}
+
+func (t *limitingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// limitClient paces the calls of client with limiter. The limiter goes under
+// a response cache, so cache hits take no token, and over the SLA recording,
+// so waiting for a token is not taken for provider latency.
+func limitClient(client *http.Client, limiter *RateLimiter) {
+	if t, ok := client.Transport.(*cachingTransport); ok {
+		t.next = &limitingTransport{limiter: limiter, next: t.next}
+		return
+	}
+	client.Transport = &limitingTransport{limiter: limiter, next: client.Transport}
+}
+
+// UseRateLimit paces the calls made to the provider with limiter
+func (e *ExchangeAPI) UseRateLimit(limiter *RateLimiter) {
+	limitClient(e.httpClient, limiter)
+}
+
+// UseRateLimit paces the calls made to the ECB with limiter
+func (e *ECB) UseRateLimit(limiter *RateLimiter) {
+	limitClient(e.httpClient, limiter)
+}
+
+// UseRateLimit paces the calls made to Fixer.io with limiter
+func (f *Fixer) UseRateLimit(limiter *RateLimiter) {
+	limitClient(f.httpClient, limiter)
+}
+
+// UseRateLimit paces the calls made to Frankfurter with limiter
+func (f *Frankfurter) UseRateLimit(limiter *RateLimiter) {
+	limitClient(f.httpClient, limiter)
+}
+
+// UseRateLimit paces the calls made to CoinGecko with limiter
+func (c *CoinGecko) UseRateLimit(limiter *RateLimiter) {
+	limitClient(c.httpClient, limiter)
+}
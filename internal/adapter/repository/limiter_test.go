@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestRateLimiterRefillsTokens(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	limiter := NewRateLimiter(60, 2)
+	limiter.UseClock(fake)
+
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("Expected call %d to be within the burst, got a delay of %s", i, delay)
+		}
+	}
+	if delay := limiter.reserve(); delay != time.Second {
+		t.Errorf("Expected the third call to wait a second, got %s", delay)
+	}
+	if delay := limiter.reserve(); delay != 2*time.Second {
+		t.Errorf("Expected the fourth call to queue behind the third, got %s", delay)
+	}
+
+	fake.Advance(time.Minute)
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve(); delay != 0 {
+			t.Fatalf("Expected the bucket to refill up to the burst, got a delay of %s", delay)
+		}
+	}
+	if delay := limiter.reserve(); delay == 0 {
+		t.Error("Expected the bucket to hold no more than the burst")
+	}
+}
+
+func TestRateLimiterFailsFastPastTheDeadline(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	limiter := NewRateLimiter(1, 1)
+	limiter.UseClock(fake)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected the first call to go through, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	started := time.Now()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Fatal("Expected a call a minute away to fail under a one second deadline")
+	}
+	if time.Since(started) > 500*time.Millisecond {
+		t.Error("Expected the call to fail without waiting for the deadline")
+	}
+
+	// The failed call gave its token back
+	fake.Advance(time.Minute)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Expected a token after a minute, got %v", err)
+	}
+}
+
+func TestRateLimitedProviderSkipsCachedResponses(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{Success: true, Source: "USD", Quotes: map[string]float64{"USDINR": 83}})
+	}))
+	t.Cleanup(server.Close)
+
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	api.UseResponseCache(NewResponseCache(10))
+	api.UseRateLimit(NewRateLimiter(1, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if _, err := api.FetchHistoricalRate(ctx, pair, date); err != nil {
+			t.Fatalf("Expected cached responses to take no token, fetch %d failed: %v", i, err)
+		}
+	}
+	if _, err := api.FetchHistoricalRate(ctx, pair, date.AddDate(0, 0, 1)); err == nil {
+		t.Error("Expected an uncached call beyond the burst to be refused")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected a single upstream call, got %d", got)
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+// ProviderSettings configure a provider built by NewProvider. Name identifies
+// exchangerate.host compatible providers, which may be any number; the other
+// providers are named after their kind.
+type ProviderSettings struct {
+	Name string
+	// BaseURL is the file rates are read from for the fixture provider
+	BaseURL string
+	APIKey  string
+	Timeout time.Duration
+	// Base is the currency rates are asked against, for providers that take one
+	Base string
+	// RequestsPerMinute paces calls to the provider, allowing bursts of Burst
+	// calls; 0 leaves them unpaced
+	RequestsPerMinute float64
+	Burst             int
+	// Simulation drives the walks of the simulation provider
+	Simulation SimulationOptions
+
+	SLA   ports.SLAStore
+	Clock clock.Clock
+	Log   *logger.Logger
+}
+
+// ProviderFactory builds a provider from settings
+type ProviderFactory func(settings ProviderSettings) (ports.RateRepository, error)
+
+// registry holds the factory of every provider kind, the built-in ones
+// registered from the start
+var registry = struct {
+	mutex     sync.RWMutex
+	factories map[string]ProviderFactory
+}{
+	factories: map[string]ProviderFactory{
+		ExchangeAPIProviderName: func(settings ProviderSettings) (ports.RateRepository, error) {
+			e := NewNamedExchangeAPI(settings.Name, settings.BaseURL, settings.APIKey, settings.Timeout, settings.SLA, settings.Log)
+			e.UseClock(settings.Clock)
+			return e, nil
+		},
+		ECBProviderName: func(settings ProviderSettings) (ports.RateRepository, error) {
+			ecb := NewECB(settings.BaseURL, settings.Timeout, settings.SLA, settings.Log)
+			ecb.UseClock(settings.Clock)
+			return ecb, nil
+		},
+		FixerProviderName: func(settings ProviderSettings) (ports.RateRepository, error) {
+			fixer := NewFixer(settings.BaseURL, settings.APIKey, settings.Base, settings.Timeout, settings.SLA, settings.Log)
+			fixer.UseClock(settings.Clock)
+			return fixer, nil
+		},
+		FrankfurterProviderName: func(settings ProviderSettings) (ports.RateRepository, error) {
+			frankfurter := NewFrankfurter(settings.BaseURL, settings.Timeout, settings.SLA, settings.Log)
+			frankfurter.UseClock(settings.Clock)
+			return frankfurter, nil
+		},
+		FixtureProviderName: func(settings ProviderSettings) (ports.RateRepository, error) {
+			fixture, err := NewFixture(settings.BaseURL, settings.Log)
+			if err != nil {
+				return nil, err
+			}
+			fixture.UseClock(settings.Clock)
+			return fixture, nil
+		},
+		SimulationProviderName: func(settings ProviderSettings) (ports.RateRepository, error) {
+			simulation, err := NewSimulation(settings.Simulation, settings.Log)
+			if err != nil {
+				return nil, err
+			}
+			simulation.UseClock(settings.Clock)
+			return simulation, nil
+		},
+	},
+}
+
+// Register makes a provider kind available to NewProvider. It panics when
+// kind is already registered, as two adapters cannot share a kind.
+func Register(kind string, factory ProviderFactory) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, exists := registry.factories[kind]; exists {
+		panic("repository: provider " + kind + " registered twice")
+	}
+	registry.factories[kind] = factory
+}
+
+// Providers lists the registered provider kinds in order
+func Providers() []string {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	kinds := make([]string, 0, len(registry.factories))
+	for kind := range registry.factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewProvider builds a provider of a registered kind. Settings without a
+// clock get the system one. Providers paced by RequestsPerMinute must have a
+// UseRateLimit method.
+func NewProvider(kind string, settings ProviderSettings) (ports.RateRepository, error) {
+	registry.mutex.RLock()
+	factory, exists := registry.factories[kind]
+	registry.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown provider %q, use one of %s", kind, strings.Join(Providers(), ", "))
+	}
+
+	if settings.Name == "" {
+		settings.Name = kind
+	}
+	if settings.Clock == nil {
+		settings.Clock = clock.System
+	}
+	provider, err := factory(settings)
+	if err != nil || settings.RequestsPerMinute <= 0 {
+		return provider, err
+	}
+
+	limited, ok := provider.(interface{ UseRateLimit(limiter *RateLimiter) })
+	if !ok {
+		return nil, fmt.Errorf("provider %s makes no calls to rate limit", settings.Name)
+	}
+	limiter := NewRateLimiter(settings.RequestsPerMinute, settings.Burst)
+	limiter.UseClock(settings.Clock)
+	limited.UseRateLimit(limiter)
+	return provider, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestProviderRegistry(t *testing.T) {
+	settings := ProviderSettings{Timeout: time.Second, SLA: nopSLAStore(), Log: logger.NewLogger("error")}
+
+	for _, kind := range []string{ExchangeAPIProviderName, ECBProviderName, FixerProviderName, FrankfurterProviderName} {
+		provider, err := NewProvider(kind, settings)
+		if err != nil {
+			t.Fatalf("Expected built-in provider %s, got %v", kind, err)
+		}
+		if provider.Name() != kind {
+			t.Errorf("Expected provider named %s, got %s", kind, provider.Name())
+		}
+	}
+
+	// exchangerate.host compatible APIs keep the name they are given
+	named := settings
+	named.Name = "backup"
+	if provider, err := NewProvider(ExchangeAPIProviderName, named); err != nil || provider.Name() != "backup" {
+		t.Errorf("Expected a provider named backup, got %v, %v", provider, err)
+	}
+
+	paced := settings
+	paced.RequestsPerMinute, paced.Burst = 60, 5
+	if _, err := NewProvider(ECBProviderName, paced); err != nil {
+		t.Errorf("Expected a rate limited ECB provider, got %v", err)
+	}
+
+	if _, err := NewProvider("acme", settings); err == nil || !strings.Contains(err.Error(), "frankfurter") {
+		t.Errorf("Expected an unknown provider error listing the kinds, got %v", err)
+	}
+
+	Register("acme", func(settings ProviderSettings) (ports.RateRepository, error) {
+		return &mocks.RateRepositoryMock{NameFunc: func() string { return settings.Name }}, nil
+	})
+	provider, err := NewProvider("acme", settings)
+	if err != nil || provider.Name() != "acme" {
+		t.Fatalf("Expected the registered provider, got %v, %v", provider, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a kind twice to panic")
+		}
+	}()
+	Register("acme", nil)
+}
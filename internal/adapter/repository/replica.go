@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// ErrReplica is returned by a Replica for every rate asked for and every
+// refresh: what the primary has not stored is not served
+var ErrReplica = xerrors.New(xerrors.Upstream, "replica", "replicas serve only what the primary stores and do not call providers")
+
+// Replica stands in for the providers of a read-only replica. It never calls
+// one, so the service answers only from the cache and history the primary
+// shares, and refreshes fail.
+type Replica struct {
+	name string
+}
+
+// NewReplica returns a Replica named after the provider of the primary
+func NewReplica(name string) *Replica {
+	return &Replica{name: name}
+}
+
+func (r *Replica) Name() string {
+	return r.name
+}
+
+func (r *Replica) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	return nil, ErrReplica
+}
+
+func (r *Replica) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	return nil, ErrReplica
+}
+
+func (r *Replica) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	return nil, ErrReplica
+}
+
+func (r *Replica) RefreshRates(ctx context.Context) error {
+	return ErrReplica
+}
+
+func (r *Replica) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/cache"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestReplicaServesOnlyTheSharedSnapshot(t *testing.T) {
+	log := logger.NewLogger("error")
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	ctx := context.Background()
+
+	// The primary saves its cache to the shared snapshot
+	primary := cache.NewMemoryCache(time.Hour, log)
+	if err := primary.Set(ctx, &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: time.Now().UTC().Truncate(24 * time.Hour), LastUpdated: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	replicaCache := cache.NewMemoryCache(time.Hour, log)
+	if _, err := replicaCache.LoadSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	replica := service.NewExchangeService(NewReplica(ExchangeAPIProviderName), service.WithCache(replicaCache), service.WithLogger(log))
+
+	rate, err := replica.GetLatestRate(ctx, model.USD, model.INR)
+	if err != nil || rate.Rate != 83 {
+		t.Fatalf("Expected the primary's rate, got %v, %v", rate, err)
+	}
+
+	_, err = replica.GetLatestRate(ctx, model.USD, model.EUR)
+	if !errors.Is(err, ErrReplica) || xerrors.CodeOf(err) != "replica" {
+		t.Errorf("Expected a pair the primary did not store to fail with ErrReplica, got %v", err)
+	}
+	if err := replica.RefreshRates(ctx); !errors.Is(err, ErrReplica) {
+		t.Errorf("Expected refreshes to fail with ErrReplica, got %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheableEndpoints are the provider endpoints whose responses never change
+// once published, so keeping them as long as the provider allows is safe
+var cacheableEndpoints = map[string]bool{
+	"historical": true,
+	"timeframe":  true,
+}
+
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	stored  time.Time
+	expires time.Time
+}
+
+// ResponseCache keeps upstream responses of historical endpoints, keyed by
+// URL, for as long as their Cache-Control or Expires headers allow. Responses
+// without either header are not kept. One cache can serve several providers.
+type ResponseCache struct {
+	maxEntries int
+	now        func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// NewResponseCache creates a cache holding at most maxEntries responses, which
+// must be positive
+func NewResponseCache(maxEntries int) *ResponseCache {
+	return &ResponseCache{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*cachedResponse),
+	}
+}
+
+// UseResponseCache answers repeated requests to historical endpoints from cache
+// instead of calling the provider. Cache hits are not recorded as SLA samples.
+func (e *ExchangeAPI) UseResponseCache(cache *ResponseCache) {
+	e.httpClient.Transport = &cachingTransport{cache: cache, next: e.httpClient.Transport}
+}
+
+// EvictOldest drops the given fraction of responses, earliest stored first
+func (c *ResponseCache) EvictOldest(fraction float64) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	keys := c.keysByAge()
+	count := int(float64(len(keys)) * fraction)
+	for _, key := range keys[:count] {
+		delete(c.entries, key)
+	}
+	return count
+}
+
+func (c *ResponseCache) get(key string) (*cachedResponse, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *ResponseCache) put(key string, entry *cachedResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.entries) >= c.maxEntries {
+		now := c.now()
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		keys := c.keysByAge()
+		for _, k := range keys[:len(keys)-c.maxEntries+1] {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+}
+
+// keysByAge returns the keys, earliest stored first. The caller holds the mutex.
+func (c *ResponseCache) keysByAge() []string {
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.entries[keys[i]].stored.Before(c.entries[keys[j]].stored)
+	})
+	return keys
+}
+
+// cachingTransport serves cacheable requests from a ResponseCache and stores
+// the responses the provider allows it to keep
+type cachingTransport struct {
+	cache *ResponseCache
+	next  http.RoundTripper
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !cacheableEndpoints[path.Base(req.URL.Path)] {
+		return t.next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	if entry, hit := t.cache.get(key); hit {
+		return entry.response(req, t.cache.now()), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	now := t.cache.now()
+	lifetime := freshnessLifetime(resp.Header, now)
+	if lifetime <= 0 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRawResponse+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) > maxRawResponse {
+		return resp, nil
+	}
+
+	t.cache.put(key, &cachedResponse{
+		status:  resp.StatusCode,
+		header:  resp.Header.Clone(),
+		body:    body,
+		stored:  now,
+		expires: now.Add(lifetime),
+	})
+	return resp, nil
+}
+
+func (e *cachedResponse) response(req *http.Request, now time.Time) *http.Response {
+	header := e.header.Clone()
+	header.Set("Age", strconv.Itoa(int(now.Sub(e.stored).Seconds())))
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// freshnessLifetime is how much longer a response may be reused, following
+// RFC 9111: max-age wins over Expires, and no-store or no-cache forbid reuse
+func freshnessLifetime(header http.Header, now time.Time) time.Duration {
+	maxAge := -1
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil {
+				return 0
+			}
+			maxAge = seconds
+		}
+	}
+
+	var age time.Duration
+	if seconds, err := strconv.Atoi(header.Get("Age")); err == nil {
+		age = time.Duration(seconds) * time.Second
+	}
+
+	if maxAge >= 0 {
+		return time.Duration(maxAge)*time.Second - age
+	}
+
+	expiresValue := header.Get("Expires")
+	if expiresValue == "" {
+		return 0
+	}
+	expires, err := http.ParseTime(expiresValue)
+	if err != nil {
+		return 0
+	}
+	date := now
+	if sent, err := http.ParseTime(header.Get("Date")); err == nil {
+		date = sent
+	}
+	return expires.Sub(date) - age
+}
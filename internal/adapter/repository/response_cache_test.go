@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestResponseCacheHonorsCachingHeaders(t *testing.T) {
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		header    map[string]string
+		wantCalls int32
+	}{
+		{"max-age", map[string]string{"Cache-Control": "public, max-age=3600"}, 1},
+		{"expires", map[string]string{"Expires": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}, 1},
+		{"max-age wins over expires", map[string]string{"Cache-Control": "max-age=0", "Expires": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}, 3},
+		{"no-store", map[string]string{"Cache-Control": "no-store, max-age=3600"}, 3},
+		{"no headers", map[string]string{}, 3},
+		{"already aged", map[string]string{"Cache-Control": "max-age=60", "Age": "60"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				for name, value := range tt.header {
+					w.Header().Set(name, value)
+				}
+				json.NewEncoder(w).Encode(exchangerateAPIResponse{
+					Success: true,
+					Source:  "USD",
+					Quotes:  map[string]float64{"USDINR": 83},
+				})
+			}))
+			t.Cleanup(server.Close)
+
+			api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+			api.UseResponseCache(NewResponseCache(10))
+
+			for i := 0; i < 3; i++ {
+				rate, err := api.FetchHistoricalRate(context.Background(), pair, date)
+				if err != nil || rate.Rate != 83 {
+					t.Fatalf("Fetch %d returned %v, %v", i, rate, err)
+				}
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Expected %d upstream calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestResponseCacheOnlyKeepsHistorical(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		json.NewEncoder(w).Encode(exchangerateAPIResponse{
+			Success: true,
+			Source:  "USD",
+			Quotes:  map[string]float64{"USDINR": 83, "USDEUR": 0.92, "USDJPY": 150, "USDGBP": 0.79},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	api := NewExchangeAPI(server.URL, "", time.Second, nopSLAStore(), logger.NewLogger("error"))
+	api.UseResponseCache(NewResponseCache(10))
+
+	for i := 0; i < 2; i++ {
+		if err := api.RefreshRates(context.Background()); err != nil && !errors.Is(err, ports.ErrRatesUnchanged) {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected every live refresh to reach the provider, got %d calls", got)
+	}
+}
+
+func TestResponseCacheBounded(t *testing.T) {
+	cache := NewResponseCache(2)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	for i, key := range []string{"a", "b", "c"} {
+		cache.put(key, &cachedResponse{status: http.StatusOK, stored: now.Add(time.Duration(i) * time.Second), expires: now.Add(time.Hour)})
+	}
+	if _, hit := cache.get("a"); hit {
+		t.Error("Expected the earliest stored response to be evicted")
+	}
+	if _, hit := cache.get("c"); !hit {
+		t.Error("Expected the newest response to be kept")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, hit := cache.get("c"); hit {
+		t.Error("Expected an expired response to be dropped")
+	}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+const SimulationProviderName = "simulation"
+
+// simulationEpoch is the day simulated quotes start walking from, at
+// simulationQuotes; earlier dates get those quotes
+var simulationEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// simulationQuotes are the USD quotes the walks start from and revert to
+var simulationQuotes = map[model.Currency]float64{
+	model.EUR: 0.92,
+	model.GBP: 0.79,
+	model.INR: 83.25,
+	model.JPY: 151.4,
+}
+
+// simulationReversion is the share of its distance from the starting quote
+// a walk moves back each day, so quotes stay plausible over the years
+const simulationReversion = 1.0 / 250
+
+// SimulationOptions drives the walks of a Simulation
+type SimulationOptions struct {
+	// Seed picks the walks; the same seed always gives the same rates
+	Seed int64
+	// VolatilityPercent is the standard deviation of a day's move
+	VolatilityPercent float64
+	// Step is how often latest rates move within a day
+	Step time.Duration
+}
+
+// Simulation makes up plausible rates instead of calling an upstream API, for
+// demos and load tests that need lively data without any dependency. Each
+// quote is a seeded random walk from the day of simulationEpoch, reverting
+// slowly to where it started. Historical rates are the walk at the start of
+// their day, and latest rates move every Step from one day's rate to the
+// next, so the rates of any moment are the same on every run with the seed.
+type Simulation struct {
+	opts  SimulationOptions
+	clock clock.Clock
+	log   *logger.Logger
+
+	// mutex guards days, the walk of each currency at the start of every day
+	// since simulationEpoch, as a log deviation from its starting quote. It
+	// is extended as later days are asked for.
+	mutex sync.Mutex
+	days  map[model.Currency][]float64
+
+	latest atomic.Pointer[rateSnapshot]
+	guardrail
+}
+
+// NewSimulation creates a simulation, failing when opts cannot drive a walk
+func NewSimulation(opts SimulationOptions, log *logger.Logger) (*Simulation, error) {
+	if opts.VolatilityPercent < 0 {
+		return nil, fmt.Errorf("simulation volatility must not be negative, got %v", opts.VolatilityPercent)
+	}
+	if opts.Step <= 0 || opts.Step > 24*time.Hour {
+		return nil, fmt.Errorf("simulation step must be positive and at most a day, got %s", opts.Step)
+	}
+
+	s := &Simulation{
+		opts:  opts,
+		clock: clock.System,
+		log:   log,
+		days:  make(map[model.Currency][]float64, len(simulationQuotes)),
+	}
+	s.latest.Store(&rateSnapshot{rates: make(map[string]*model.ExchangeRate)})
+	return s, nil
+}
+
+// UseClock replaces the wall clock latest rates are simulated at
+func (s *Simulation) UseClock(c clock.Clock) {
+	s.clock = c
+}
+
+func (s *Simulation) Name() string {
+	return SimulationProviderName
+}
+
+func (s *Simulation) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+	if rate, exists := s.latest.Load().rates[pair.String()]; exists {
+		return rate, nil
+	}
+	now := s.clock.Now()
+	return computeRate(s.quotes(now), pair, now)
+}
+
+func (s *Simulation) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+	rate, err := computeRate(s.quotes(date.UTC().Truncate(24*time.Hour)), pair, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = date
+	return rate, nil
+}
+
+func (s *Simulation) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+	result := &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+
+	for date := request.StartDate; !date.After(request.EndDate); date = date.AddDate(0, 0, 1) {
+		dateKey := date.Format("2006-01-02")
+		rate, err := s.FetchHistoricalRate(ctx, pair, date)
+		if err != nil {
+			result.Errors = append(result.Errors, model.NewItemError(dateKey, err))
+			continue
+		}
+		result.Rates[dateKey] = *rate
+	}
+
+	return result, nil
+}
+
+// RefreshRates serves every supported pair at the current step of the walks.
+// A refresh within the step of the last one leaves the rates unchanged.
+func (s *Simulation) RefreshRates(ctx context.Context) error {
+	now := s.clock.Now()
+	return s.promoteQuotes(&s.latest, s.quotes(now), now, s.log)
+}
+
+func (s *Simulation) LatestRates(ctx context.Context) []model.ExchangeRate {
+	return snapshotRates(s.latest.Load())
+}
+
+// quotes returns the simulated USD quotes at t
+func (s *Simulation) quotes(t time.Time) map[string]float64 {
+	quotes := make(map[string]float64, len(simulationQuotes))
+	for currency, start := range simulationQuotes {
+		quote := start * math.Exp(s.walk(currency, t))
+		quotes["USD"+string(currency)] = math.Round(quote*1e6) / 1e6
+	}
+	return quotes
+}
+
+// walk returns the log deviation of currency from its starting quote at t.
+// Within a day it follows a bridge from the day's value to the next one's:
+// steps of the day's volatility spread over the day, pulled in so they end
+// where the next day starts.
+func (s *Simulation) walk(currency model.Currency, t time.Time) float64 {
+	elapsed := t.Sub(simulationEpoch)
+	if elapsed < 0 {
+		return 0
+	}
+	day := int(elapsed / (24 * time.Hour))
+	from, to := s.day(currency, day), s.day(currency, day+1)
+
+	steps := int((24 * time.Hour) / s.opts.Step)
+	step := min(int((elapsed%(24*time.Hour))/s.opts.Step), steps)
+	if step == 0 {
+		return from
+	}
+
+	stepVolatility := s.opts.VolatilityPercent / 100 / math.Sqrt(float64(steps))
+	var reached, total float64
+	for i := 1; i <= steps; i++ {
+		total += stepVolatility * s.noise(currency, day, i)
+		if i == step {
+			reached = total
+		}
+	}
+
+	progress := float64(step) / float64(steps)
+	return from + progress*(to-from) + reached - progress*total
+}
+
+// day returns the walk of currency at the start of the day-th day since
+// simulationEpoch, extending the walk up to it
+func (s *Simulation) day(currency model.Currency, day int) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	walk := s.days[currency]
+	if len(walk) == 0 {
+		walk = append(walk, 0)
+	}
+	volatility := s.opts.VolatilityPercent / 100
+	for len(walk) <= day {
+		d := len(walk)
+		walk = append(walk, walk[d-1]*(1-simulationReversion)+volatility*s.noise(currency, d, 0))
+	}
+	s.days[currency] = walk
+	return walk[day]
+}
+
+// noise returns a standard normal draw fixed by the seed, currency, day and
+// step, so the walks need no state to be reproduced
+func (s *Simulation) noise(currency model.Currency, day, step int) float64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(currency))
+	var key [16]byte
+	binary.BigEndian.PutUint64(key[:8], uint64(day))
+	binary.BigEndian.PutUint64(key[8:], uint64(step))
+	hash.Write(key[:])
+
+	return rand.New(rand.NewPCG(uint64(s.opts.Seed), hash.Sum64())).NormFloat64()
+}
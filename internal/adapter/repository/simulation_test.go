@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+func newTestSimulation(t *testing.T, seed int64, now time.Time) (*Simulation, *clock.Fake) {
+	t.Helper()
+	simulation, err := NewSimulation(SimulationOptions{Seed: seed, VolatilityPercent: 0.5, Step: time.Minute}, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := clock.NewFake(now)
+	simulation.UseClock(fake)
+	return simulation, fake
+}
+
+func TestSimulationIsReproducible(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC)
+	ctx := context.Background()
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	request := model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	first, _ := newTestSimulation(t, 42, now)
+	second, _ := newTestSimulation(t, 42, now)
+	other, _ := newTestSimulation(t, 7, now)
+
+	a, err := first.FetchHistoricalRates(ctx, request)
+	if err != nil || len(a.Rates) != 10 {
+		t.Fatalf("Expected ten days of rates, got %+v, %v", a, err)
+	}
+	b, _ := second.FetchHistoricalRates(ctx, request)
+	c, _ := other.FetchHistoricalRates(ctx, request)
+	for date, rate := range a.Rates {
+		if b.Rates[date].Rate != rate.Rate {
+			t.Errorf("Expected the same seed to give the same rate on %s, got %v and %v", date, rate.Rate, b.Rates[date].Rate)
+		}
+		// Years of walking keep the quote plausible
+		if math.Abs(rate.Rate/83.25-1) > 0.5 {
+			t.Errorf("Expected a plausible USD-INR on %s, got %v", date, rate.Rate)
+		}
+	}
+	if c.Rates["2025-03-10"].Rate == a.Rates["2025-03-10"].Rate {
+		t.Error("Expected another seed to give other rates")
+	}
+
+	latest, err := first.FetchLatestRate(ctx, pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := second.FetchLatestRate(ctx, pair); again.Rate != latest.Rate {
+		t.Errorf("Expected the same latest rate, got %v and %v", latest.Rate, again.Rate)
+	}
+
+	missing := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: "CHF"}
+	if _, err := first.FetchLatestRate(ctx, missing); !errors.Is(err, ports.ErrQuoteNotFound) {
+		t.Errorf("Expected ErrQuoteNotFound for a currency not simulated, got %v", err)
+	}
+}
+
+func TestSimulationAdvancesOverTime(t *testing.T) {
+	ctx := context.Background()
+	pair := model.CurrencyPair{BaseCurrency: model.EUR, TargetCurrency: model.JPY}
+	simulation, fake := newTestSimulation(t, 1, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	// At the start of a day, latest rates are that day's historical ones
+	if err := simulation.RefreshRates(ctx); err != nil {
+		t.Fatal(err)
+	}
+	opening, _ := simulation.FetchLatestRate(ctx, pair)
+	historical, _ := simulation.FetchHistoricalRate(ctx, pair, fake.Now())
+	if opening.Rate != historical.Rate {
+		t.Errorf("Expected the opening rate %v to be the historical one, got %v", opening.Rate, historical.Rate)
+	}
+
+	// Within a step nothing moves
+	fake.Advance(30 * time.Second)
+	if err := simulation.RefreshRates(ctx); !errors.Is(err, ports.ErrRatesUnchanged) {
+		t.Errorf("Expected the rates to stay within a step, got %v", err)
+	}
+
+	fake.Advance(time.Hour)
+	if err := simulation.RefreshRates(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if moved, _ := simulation.FetchLatestRate(ctx, pair); moved.Rate == opening.Rate {
+		t.Error("Expected the rate to move after an hour")
+	}
+
+	// The day ends at the next day's rate
+	fake.Set(time.Date(2025, 3, 10, 23, 59, 59, 0, time.UTC))
+	closing := simulation.quotes(fake.Now())
+	next := simulation.quotes(time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC))
+	for key, quote := range next {
+		if math.Abs(closing[key]/quote-1) > 0.001 {
+			t.Errorf("Expected %s to close near the next day's %v, got %v", key, quote, closing[key])
+		}
+	}
+}
+
+func TestSimulationRejectsInvalidOptions(t *testing.T) {
+	for _, opts := range []SimulationOptions{
+		{VolatilityPercent: -1, Step: time.Minute},
+		{VolatilityPercent: 0.5},
+		{VolatilityPercent: 0.5, Step: 48 * time.Hour},
+	} {
+		if _, err := NewSimulation(opts, logger.NewLogger("error")); err == nil {
+			t.Errorf("Expected %+v to be rejected", opts)
+		}
+	}
+}
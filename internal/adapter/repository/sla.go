@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"net/http"
+	"time"
+
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// slaTransport records latency and outcome of every upstream HTTP call
+type slaTransport struct {
+	provider string
+	next     http.RoundTripper
+	store    ports.SLAStore
+}
+
+func newSLATransport(provider string, next http.RoundTripper, store ports.SLAStore) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &slaTransport{
+		provider: provider,
+		next:     next,
+		store:    store,
+	}
+}
+
+func (t *slaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	success := err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests
+	t.store.Record(req.Context(), t.provider, time.Since(start), success)
+
+	return resp, err
+}
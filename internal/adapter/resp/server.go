@@ -0,0 +1,305 @@
+// Package resp serves rates over a read-only subset of the Redis protocol
+// (RESP2), so applications can read them with any Redis client:
+//
+//	GET rate:USD:INR
+//	GET rate:USD:INR:2025-01-31
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/pkg/logger"
+)
+
+// lookupTimeout bounds a single key lookup, which may reach the provider on a
+// cache miss
+const lookupTimeout = 10 * time.Second
+
+// RateSource resolves the keys clients ask for
+type RateSource interface {
+	GetLatestRate(ctx context.Context, from, to model.Currency) (*model.ExchangeRate, error)
+	GetHistoricalRate(ctx context.Context, from, to model.Currency, date time.Time) (*model.ExchangeRate, error)
+}
+
+// KeyValidator checks the password given to AUTH
+type KeyValidator interface {
+	ValidKey(key string) bool
+}
+
+// writeCommands are answered with READONLY rather than as unknown commands
+var writeCommands = map[string]bool{
+	"SET": true, "SETEX": true, "SETNX": true, "MSET": true, "GETSET": true, "GETDEL": true,
+	"DEL": true, "UNLINK": true, "EXPIRE": true, "INCR": true, "DECR": true, "APPEND": true,
+	"FLUSHDB": true, "FLUSHALL": true,
+}
+
+// Server accepts RESP connections. When keys is non-nil, clients must AUTH
+// with an API key before reading rates.
+type Server struct {
+	rates    RateSource
+	keys     KeyValidator
+	log      *logger.Logger
+	mutex    sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+}
+
+func NewServer(rates RateSource, keys KeyValidator, log *logger.Logger) *Server {
+	return &Server{
+		rates: rates,
+		keys:  keys,
+		log:   log,
+		conns: make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve accepts connections on l until Close is called
+func (s *Server) Serve(l net.Listener) error {
+	s.mutex.Lock()
+	s.listener = l
+	s.mutex.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close stops accepting connections and closes the open ones
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	return err
+}
+
+type client struct {
+	authenticated bool
+	quit          bool
+}
+
+func (s *Server) handle(conn net.Conn) {
+	s.mutex.Lock()
+	s.conns[conn] = struct{}{}
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := writer{bufio.NewWriter(conn)}
+	c := &client{authenticated: s.keys == nil}
+
+	for !c.quit {
+		args, err := readCommand(r)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				w.error("ERR " + err.Error())
+				w.Flush()
+				s.log.Warn("RESP protocol error", "remote_addr", conn.RemoteAddr().String(), "error", err)
+			} else if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.log.Warn("RESP connection failed", "remote_addr", conn.RemoteAddr().String(), "error", err)
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		s.execute(c, w, args)
+
+		// Flush once the pipeline is drained rather than after every reply
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+	w.Flush()
+}
+
+func (s *Server) execute(c *client, w writer, args []string) {
+	name := strings.ToUpper(args[0])
+
+	switch name {
+	case "AUTH":
+		s.auth(c, w, args[1:])
+		return
+	case "HELLO":
+		// Clients fall back to RESP2 and AUTH when HELLO is refused
+		w.error("NOPROTO this server only speaks RESP2")
+		return
+	case "QUIT":
+		w.simple("OK")
+		c.quit = true
+		return
+	}
+
+	if !c.authenticated {
+		w.error("NOAUTH Authentication required.")
+		return
+	}
+
+	switch name {
+	case "PING":
+		if len(args) > 1 {
+			w.bulk(args[1])
+		} else {
+			w.simple("PONG")
+		}
+	case "ECHO":
+		if len(args) != 2 {
+			w.error(wrongArgs(name))
+			return
+		}
+		w.bulk(args[1])
+	case "SELECT":
+		if len(args) != 2 {
+			w.error(wrongArgs(name))
+		} else if args[1] != "0" {
+			w.error("ERR DB index is out of range")
+		} else {
+			w.simple("OK")
+		}
+	case "CLIENT":
+		// SETNAME and SETINFO are sent by client libraries on connect
+		w.simple("OK")
+	case "COMMAND":
+		w.array(0)
+	case "GET":
+		if len(args) != 2 {
+			w.error(wrongArgs(name))
+			return
+		}
+		value, err := s.lookup(args[1])
+		reply(w, value, err)
+	case "MGET":
+		if len(args) < 2 {
+			w.error(wrongArgs(name))
+			return
+		}
+		w.array(len(args) - 1)
+		for _, key := range args[1:] {
+			value, err := s.lookup(key)
+			if err != nil {
+				// Like Redis, MGET never fails per key
+				value = ""
+			}
+			reply(w, value, nil)
+		}
+	case "EXISTS":
+		if len(args) < 2 {
+			w.error(wrongArgs(name))
+			return
+		}
+		count := 0
+		for _, key := range args[1:] {
+			if value, err := s.lookup(key); err == nil && value != "" {
+				count++
+			}
+		}
+		w.integer(count)
+	default:
+		if writeCommands[name] {
+			w.error("READONLY You can't write against a read only replica.")
+			return
+		}
+		w.error(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+}
+
+func (s *Server) auth(c *client, w writer, args []string) {
+	if len(args) != 1 && len(args) != 2 {
+		w.error(wrongArgs("AUTH"))
+		return
+	}
+	if s.keys == nil {
+		w.error("ERR AUTH called without any API keys configured")
+		return
+	}
+
+	// AUTH <password> or AUTH <username> <password>; the username is ignored
+	if !s.keys.ValidKey(args[len(args)-1]) {
+		w.error("WRONGPASS invalid username-password pair or user is disabled.")
+		return
+	}
+	c.authenticated = true
+	w.simple("OK")
+}
+
+// lookup resolves rate:<BASE>:<TARGET>[:<YYYY-MM-DD>]. It returns "" for keys
+// that do not name a known rate, like Redis does for missing keys.
+func (s *Server) lookup(key string) (string, error) {
+	parts := strings.Split(key, ":")
+	if (len(parts) != 3 && len(parts) != 4) || parts[0] != "rate" {
+		return "", nil
+	}
+	from := model.Currency(strings.ToUpper(parts[1]))
+	to := model.Currency(strings.ToUpper(parts[2]))
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	var rate *model.ExchangeRate
+	var err error
+	if len(parts) == 4 {
+		date, parseErr := time.Parse("2006-01-02", parts[3])
+		if parseErr != nil {
+			return "", nil
+		}
+		rate, err = s.rates.GetHistoricalRate(ctx, from, to, date)
+	} else {
+		rate, err = s.rates.GetLatestRate(ctx, from, to)
+	}
+
+	if errors.Is(err, service.ErrExternalAPIFailure) {
+		return "", err
+	}
+	if err != nil {
+		return "", nil
+	}
+	return strconv.FormatFloat(rate.Rate, 'f', -1, 64), nil
+}
+
+func reply(w writer, value string, err error) {
+	switch {
+	case err != nil:
+		w.error("ERR rate provider unavailable")
+	case value == "":
+		w.null()
+	default:
+		w.bulk(value)
+	}
+}
+
+func wrongArgs(command string) string {
+	return fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(command))
+}
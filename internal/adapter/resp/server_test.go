@@ -0,0 +1,110 @@
+package resp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/pkg/logger"
+)
+
+type fakeRates struct{}
+
+func (fakeRates) GetLatestRate(ctx context.Context, from, to model.Currency) (*model.ExchangeRate, error) {
+	switch {
+	case from == model.USD && to == model.INR:
+		return &model.ExchangeRate{BaseCurrency: from, TargetCurrency: to, Rate: 83.12}, nil
+	case from == model.GBP:
+		return nil, fmt.Errorf("%w: timeout", service.ErrExternalAPIFailure)
+	}
+	return nil, service.ErrRateNotFound
+}
+
+func (fakeRates) GetHistoricalRate(ctx context.Context, from, to model.Currency, date time.Time) (*model.ExchangeRate, error) {
+	return &model.ExchangeRate{BaseCurrency: from, TargetCurrency: to, Rate: 82.5, Date: date}, nil
+}
+
+type fakeKeys string
+
+func (k fakeKeys) ValidKey(key string) bool {
+	return key == string(k)
+}
+
+// roundTrip sends raw to a fresh server and returns the replies it wrote
+// before the connection was closed by QUIT
+func roundTrip(t *testing.T, keys KeyValidator, raw string) string {
+	t.Helper()
+
+	server := NewServer(fakeRates{}, keys, logger.NewLogger("error"))
+	client, conn := net.Pipe()
+	go server.handle(conn)
+	defer client.Close()
+
+	go client.Write([]byte(raw + "QUIT\r\n"))
+
+	replies, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatalf("Failed to read replies: %v", err)
+	}
+	return string(replies)
+}
+
+func TestCommands(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keys     KeyValidator
+		input    string
+		expected string
+	}{
+		{
+			name:     "Get Latest",
+			input:    "*2\r\n$3\r\nGET\r\n$12\r\nrate:USD:INR\r\n",
+			expected: "$5\r\n83.12\r\n",
+		},
+		{
+			name:     "Get Historical Inline",
+			input:    "get rate:usd:inr:2025-01-31\r\n",
+			expected: "$4\r\n82.5\r\n",
+		},
+		{
+			name:     "Missing Key",
+			input:    "GET rate:USD:XXX\r\nGET other\r\n",
+			expected: "$-1\r\n$-1\r\n",
+		},
+		{
+			name:     "Provider Failure",
+			input:    "GET rate:GBP:INR\r\n",
+			expected: "-ERR rate provider unavailable\r\n",
+		},
+		{
+			name:     "MGET",
+			input:    "MGET rate:USD:INR rate:GBP:INR\r\n",
+			expected: "*2\r\n$5\r\n83.12\r\n$-1\r\n",
+		},
+		{
+			name:     "Read Only",
+			input:    "SET rate:USD:INR 1\r\n",
+			expected: "-READONLY You can't write against a read only replica.\r\n",
+		},
+		{
+			name:     "Auth Required",
+			keys:     fakeKeys("secret"),
+			input:    "GET rate:USD:INR\r\nAUTH wrong\r\nAUTH default secret\r\nGET rate:USD:INR\r\n",
+			expected: "-NOAUTH Authentication required.\r\n-WRONGPASS invalid username-password pair or user is disabled.\r\n+OK\r\n$5\r\n83.12\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replies := roundTrip(t, tc.keys, tc.input)
+			if replies != tc.expected+"+OK\r\n" {
+				t.Errorf("Expected %q, got %q", tc.expected+"+OK\r\n", replies)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// FileAnnotationStore keeps annotations in memory and persists every change to a JSON file
+type FileAnnotationStore struct {
+	path        string
+	annotations []model.Annotation
+	mutex       sync.RWMutex
+	log         *logger.Logger
+}
+
+func NewFileAnnotationStore(path string, log *logger.Logger) (*FileAnnotationStore, error) {
+	s := &FileAnnotationStore{
+		path:        path,
+		annotations: make([]model.Annotation, 0),
+		log:         log,
+	}
+
+	if err := utils.ReadJSONFile(path, &s.annotations); err != nil {
+		return nil, err
+	}
+
+	log.Info("Loaded annotations", "path", path, "count", len(s.annotations))
+	return s, nil
+}
+
+func (s *FileAnnotationStore) Create(ctx context.Context, annotation *model.Annotation) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	annotations := append(s.annotations, *annotation)
+	if err := utils.WriteJSONFile(s.path, annotations); err != nil {
+		return err
+	}
+
+	s.annotations = annotations
+	return nil
+}
+
+func (s *FileAnnotationStore) List(ctx context.Context, filter model.AnnotationFilter) ([]model.Annotation, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.Annotation, 0)
+	for _, annotation := range s.annotations {
+		if annotation.Matches(filter) {
+			result = append(result, annotation)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	return result, nil
+}
+
+func (s *FileAnnotationStore) Delete(ctx context.Context, id string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	annotations := make([]model.Annotation, 0, len(s.annotations))
+	for _, annotation := range s.annotations {
+		if annotation.ID != id {
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	if len(annotations) == len(s.annotations) {
+		return false, nil
+	}
+
+	if err := utils.WriteJSONFile(s.path, annotations); err != nil {
+		return false, err
+	}
+
+	s.annotations = annotations
+	return true, nil
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// FileBasketStore keeps currency baskets in memory and persists every change to a JSON file
+type FileBasketStore struct {
+	path    string
+	baskets []model.Basket
+	mutex   sync.RWMutex
+	log     *logger.Logger
+}
+
+func NewFileBasketStore(path string, log *logger.Logger) (*FileBasketStore, error) {
+	s := &FileBasketStore{
+		path:    path,
+		baskets: make([]model.Basket, 0),
+		log:     log,
+	}
+
+	if err := utils.ReadJSONFile(path, &s.baskets); err != nil {
+		return nil, err
+	}
+
+	log.Info("Loaded currency baskets", "path", path, "count", len(s.baskets))
+	return s, nil
+}
+
+func (s *FileBasketStore) Create(ctx context.Context, basket *model.Basket) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	baskets := append(append([]model.Basket{}, s.baskets...), *basket)
+	if err := utils.WriteJSONFile(s.path, baskets); err != nil {
+		return err
+	}
+
+	s.baskets = baskets
+	return nil
+}
+
+func (s *FileBasketStore) List(ctx context.Context, tenant string) ([]model.Basket, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.Basket, 0)
+	for _, basket := range s.baskets {
+		if basket.Tenant == tenant {
+			result = append(result, basket)
+		}
+	}
+	return result, nil
+}
+
+func (s *FileBasketStore) Delete(ctx context.Context, tenant, id string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	baskets := make([]model.Basket, 0, len(s.baskets))
+	for _, basket := range s.baskets {
+		if basket.ID != id || basket.Tenant != tenant {
+			baskets = append(baskets, basket)
+		}
+	}
+
+	if len(baskets) == len(s.baskets) {
+		return false, nil
+	}
+
+	if err := utils.WriteJSONFile(s.path, baskets); err != nil {
+		return false, err
+	}
+
+	s.baskets = baskets
+	return true, nil
+}
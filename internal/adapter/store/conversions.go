@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+// MemoryConversionStore keeps conversion receipts in memory. Once the
+// configured limit is reached the oldest receipts are dropped first.
+type MemoryConversionStore struct {
+	receipts map[string]model.ConversionResult
+	order    []string
+	limit    int
+	mutex    sync.RWMutex
+	log      *logger.Logger
+}
+
+func NewMemoryConversionStore(limit int, log *logger.Logger) *MemoryConversionStore {
+	return &MemoryConversionStore{
+		receipts: make(map[string]model.ConversionResult),
+		order:    make([]string, 0),
+		limit:    limit,
+		log:      log,
+	}
+}
+
+func (s *MemoryConversionStore) Save(ctx context.Context, result *model.ConversionResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Receipts are immutable, a second save with the same ID is ignored
+	if _, exists := s.receipts[result.ID]; exists {
+		return nil
+	}
+
+	s.receipts[result.ID] = copyResult(result)
+	s.order = append(s.order, result.ID)
+
+	for s.limit > 0 && len(s.order) > s.limit {
+		oldest := s.order[0]
+		s.order = s.order[1:]
+		delete(s.receipts, oldest)
+		s.log.Debug("Evicted conversion receipt", "conversion_id", oldest)
+	}
+
+	return nil
+}
+
+func (s *MemoryConversionStore) Get(ctx context.Context, id string) (*model.ConversionResult, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result, found := s.receipts[id]
+	if !found {
+		return nil, false
+	}
+
+	stored := copyResult(&result)
+	return &stored, true
+}
+
+// EvictOldest drops the given fraction of receipts, oldest first
+func (s *MemoryConversionStore) EvictOldest(fraction float64) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := int(float64(len(s.order)) * fraction)
+	for _, id := range s.order[:count] {
+		delete(s.receipts, id)
+	}
+	s.order = s.order[count:]
+
+	return count
+}
+
+// copyResult detaches a receipt from the caller so stored receipts cannot be mutated
+func copyResult(result *model.ConversionResult) model.ConversionResult {
+	stored := *result
+	if result.RateSnapshot != nil {
+		snapshot := *result.RateSnapshot
+		stored.RateSnapshot = &snapshot
+	}
+	return stored
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// FileDeliveryStore keeps the delivery ledger in memory and persists every
+// change to a JSON file. Entries that are no longer retrying are dropped once
+// they have not changed for the retention period.
+type FileDeliveryStore struct {
+	path       string
+	retention  time.Duration
+	deliveries map[string]model.Delivery
+	mutex      sync.RWMutex
+	log        *logger.Logger
+}
+
+func NewFileDeliveryStore(path string, retention time.Duration, log *logger.Logger) (*FileDeliveryStore, error) {
+	s := &FileDeliveryStore{
+		path:       path,
+		retention:  retention,
+		deliveries: make(map[string]model.Delivery),
+		log:        log,
+	}
+
+	if err := utils.ReadJSONFile(path, &s.deliveries); err != nil {
+		return nil, err
+	}
+
+	log.Info("Loaded delivery ledger", "path", path, "count", len(s.deliveries))
+	return s, nil
+}
+
+func (s *FileDeliveryStore) Save(ctx context.Context, delivery *model.Delivery) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cutoff := time.Now().Add(-s.retention)
+	deliveries := make(map[string]model.Delivery, len(s.deliveries)+1)
+	for id, d := range s.deliveries {
+		if d.Status == model.DeliveryRetrying || d.UpdatedAt.After(cutoff) {
+			deliveries[id] = d
+		}
+	}
+	deliveries[delivery.ID] = *delivery
+
+	if err := utils.WriteJSONFile(s.path, deliveries); err != nil {
+		return err
+	}
+
+	s.deliveries = deliveries
+	return nil
+}
+
+func (s *FileDeliveryStore) Get(ctx context.Context, id string) (*model.Delivery, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	delivery, exists := s.deliveries[id]
+	if !exists {
+		return nil, false
+	}
+	return &delivery, true
+}
+
+func (s *FileDeliveryStore) List(ctx context.Context, filter model.DeliveryFilter) ([]model.Delivery, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.Delivery, 0)
+	for _, delivery := range s.deliveries {
+		if delivery.Matches(filter) {
+			result = append(result, delivery)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+	return result, nil
+}
+
+func (s *FileDeliveryStore) Due(ctx context.Context, now time.Time) ([]model.Delivery, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.Delivery, 0)
+	for _, delivery := range s.deliveries {
+		if delivery.Status == model.DeliveryRetrying && !delivery.NextAttemptAt.After(now) {
+			result = append(result, delivery)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NextAttemptAt.Before(result[j].NextAttemptAt)
+	})
+	return result, nil
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// FileHistoryStore keeps daily rates per pair in memory and persists them to a
+// JSON file. Entries older than the retention window are pruned on save.
+type FileHistoryStore struct {
+	path      string
+	retention int
+	rates     map[string]map[string]model.ExchangeRate
+	mutex     sync.RWMutex
+	log       *logger.Logger
+}
+
+func NewFileHistoryStore(path string, retentionDays int, log *logger.Logger) (*FileHistoryStore, error) {
+	s := &FileHistoryStore{
+		path:      path,
+		retention: retentionDays,
+		rates:     make(map[string]map[string]model.ExchangeRate),
+		log:       log,
+	}
+
+	count, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Loaded historical rates", "path", path, "count", count)
+	return s, nil
+}
+
+// Reload replaces the rates in memory with those in the file, which another
+// instance writes when this one is a replica
+func (s *FileHistoryStore) Reload() error {
+	count, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	s.log.Debug("Reloaded historical rates", "path", s.path, "count", count)
+	return nil
+}
+
+// load reads the file into a fresh set of rates and returns how many it read
+func (s *FileHistoryStore) load() (int, error) {
+	var stored []model.ExchangeRate
+	if err := utils.ReadJSONFile(s.path, &stored); err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rates = make(map[string]map[string]model.ExchangeRate)
+	for _, rate := range stored {
+		s.put(rate)
+	}
+	return len(stored), nil
+}
+
+func (s *FileHistoryStore) Save(ctx context.Context, rates []model.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, rate := range rates {
+		if rate.Interpolated {
+			continue
+		}
+		s.put(rate)
+	}
+	s.prune()
+
+	return utils.WriteJSONFile(s.path, s.all())
+}
+
+func (s *FileHistoryStore) Get(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	rate, found := s.rates[pair.String()][date.Format("2006-01-02")]
+	if !found {
+		return nil, false
+	}
+	return &rate, true
+}
+
+func (s *FileHistoryStore) Range(ctx context.Context, pair model.CurrencyPair, start, end time.Time) ([]model.ExchangeRate, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.ExchangeRate, 0)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if rate, found := s.rates[pair.String()][d.Format("2006-01-02")]; found {
+			result = append(result, rate)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *FileHistoryStore) put(rate model.ExchangeRate) {
+	pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+
+	dates, exists := s.rates[pair.String()]
+	if !exists {
+		dates = make(map[string]model.ExchangeRate)
+		s.rates[pair.String()] = dates
+	}
+	dates[rate.Date.Format("2006-01-02")] = rate
+}
+
+func (s *FileHistoryStore) prune() {
+	cutoff := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -s.retention).Format("2006-01-02")
+
+	for pair, dates := range s.rates {
+		for date := range dates {
+			if date < cutoff {
+				delete(dates, date)
+			}
+		}
+		if len(dates) == 0 {
+			delete(s.rates, pair)
+		}
+	}
+}
+
+func (s *FileHistoryStore) all() []model.ExchangeRate {
+	result := make([]model.ExchangeRate, 0)
+	for _, dates := range s.rates {
+		for _, rate := range dates {
+			result = append(result, rate)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		if result[i].BaseCurrency != result[j].BaseCurrency {
+			return result[i].BaseCurrency < result[j].BaseCurrency
+		}
+		return result[i].TargetCurrency < result[j].TargetCurrency
+	})
+
+	return result
+}
+
+// EvictOldest drops the given fraction of stored rates from memory, oldest dates
+// first. The file catches up on the next save.
+func (s *FileHistoryStore) EvictOldest(fraction float64) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	all := s.all()
+	count := int(float64(len(all)) * fraction)
+	for _, rate := range all[:count] {
+		pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+		delete(s.rates[pair.String()], rate.Date.Format("2006-01-02"))
+		if len(s.rates[pair.String()]) == 0 {
+			delete(s.rates, pair.String())
+		}
+	}
+
+	return count
+}
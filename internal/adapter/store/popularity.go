@@ -0,0 +1,157 @@
+package store
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// minPopularityScore is the decayed score below which a pair is forgotten
+const minPopularityScore = 0.01
+
+type pairScore struct {
+	Pair    model.CurrencyPair `json:"pair"`
+	Score   float64            `json:"score"`
+	Updated time.Time          `json:"updated"`
+}
+
+// FilePopularityStore counts requests per pair with scores that halve every
+// half-life, kept in memory and flushed to a JSON file periodically like the
+// SLA store, since a request is recorded on every lookup
+type FilePopularityStore struct {
+	path     string
+	halfLife time.Duration
+	scores   map[model.CurrencyPair]*pairScore
+	dirty    bool
+	now      func() time.Time
+	mutex    sync.Mutex
+	log      *logger.Logger
+}
+
+func NewFilePopularityStore(path string, halfLife time.Duration, log *logger.Logger) (*FilePopularityStore, error) {
+	s := &FilePopularityStore{
+		path:     path,
+		halfLife: halfLife,
+		scores:   make(map[model.CurrencyPair]*pairScore),
+		now:      time.Now,
+		log:      log,
+	}
+
+	var stored []pairScore
+	if err := utils.ReadJSONFile(path, &stored); err != nil {
+		return nil, err
+	}
+	for i := range stored {
+		score := stored[i]
+		s.scores[score.Pair] = &score
+	}
+
+	return s, nil
+}
+
+// decayed returns score as of now. The caller holds the mutex.
+func (s *FilePopularityStore) decayed(score *pairScore, now time.Time) float64 {
+	if s.halfLife <= 0 {
+		return score.Score
+	}
+	return score.Score * math.Exp2(-float64(now.Sub(score.Updated))/float64(s.halfLife))
+}
+
+func (s *FilePopularityStore) Record(ctx context.Context, pair model.CurrencyPair) {
+	now := s.now().UTC()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	score, exists := s.scores[pair]
+	if !exists {
+		score = &pairScore{Pair: pair}
+		s.scores[pair] = score
+	}
+	score.Score = s.decayed(score, now) + 1
+	score.Updated = now
+
+	s.dirty = true
+}
+
+func (s *FilePopularityStore) Popular(ctx context.Context, limit int) []model.CurrencyPair {
+	now := s.now().UTC()
+
+	s.mutex.Lock()
+	current := make([]pairScore, 0, len(s.scores))
+	for _, score := range s.scores {
+		if decayed := s.decayed(score, now); decayed >= minPopularityScore {
+			current = append(current, pairScore{Pair: score.Pair, Score: decayed})
+		}
+	}
+	s.mutex.Unlock()
+
+	sort.Slice(current, func(i, j int) bool {
+		if current[i].Score != current[j].Score {
+			return current[i].Score > current[j].Score
+		}
+		return current[i].Pair.String() < current[j].Pair.String()
+	})
+	if len(current) > limit {
+		current = current[:limit]
+	}
+
+	pairs := make([]model.CurrencyPair, 0, len(current))
+	for _, score := range current {
+		pairs = append(pairs, score.Pair)
+	}
+	return pairs
+}
+
+// Run flushes recorded scores every interval until ctx is cancelled, then flushes once more
+func (s *FilePopularityStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-ctx.Done():
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *FilePopularityStore) flush() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now().UTC()
+	for pair, score := range s.scores {
+		if s.decayed(score, now) < minPopularityScore {
+			delete(s.scores, pair)
+			s.dirty = true
+		}
+	}
+
+	if !s.dirty {
+		return
+	}
+
+	scores := make([]pairScore, 0, len(s.scores))
+	for _, score := range s.scores {
+		scores = append(scores, *score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Pair.String() < scores[j].Pair.String()
+	})
+
+	if err := utils.WriteJSONFile(s.path, scores); err != nil {
+		s.log.Error("Failed to persist pair popularity", "error", err)
+		return
+	}
+	s.dirty = false
+}
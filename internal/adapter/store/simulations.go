@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+type simulationData struct {
+	Orders     []model.SimulationOrder    `json:"orders"`
+	Executions []model.SimulatedExecution `json:"executions"`
+}
+
+// FileSimulationStore keeps simulated orders and the most recent executions of
+// each in memory and persists every change to a JSON file
+type FileSimulationStore struct {
+	path     string
+	logLimit int
+	data     simulationData
+	mutex    sync.RWMutex
+	log      *logger.Logger
+}
+
+// NewFileSimulationStore loads the store from path. logLimit is the number of
+// executions kept per order.
+func NewFileSimulationStore(path string, logLimit int, log *logger.Logger) (*FileSimulationStore, error) {
+	s := &FileSimulationStore{
+		path:     path,
+		logLimit: logLimit,
+		data: simulationData{
+			Orders:     make([]model.SimulationOrder, 0),
+			Executions: make([]model.SimulatedExecution, 0),
+		},
+		log: log,
+	}
+
+	if err := utils.ReadJSONFile(path, &s.data); err != nil {
+		return nil, err
+	}
+
+	log.Info("Loaded simulated orders", "path", path, "count", len(s.data.Orders))
+	return s, nil
+}
+
+// SaveOrder replaces the order with the same ID, or appends it
+func (s *FileSimulationStore) SaveOrder(ctx context.Context, order *model.SimulationOrder) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	orders := make([]model.SimulationOrder, 0, len(s.data.Orders)+1)
+	replaced := false
+	for _, o := range s.data.Orders {
+		if o.ID == order.ID {
+			o = *order
+			replaced = true
+		}
+		orders = append(orders, o)
+	}
+	if !replaced {
+		orders = append(orders, *order)
+	}
+
+	data := simulationData{Orders: orders, Executions: s.data.Executions}
+	if err := utils.WriteJSONFile(s.path, data); err != nil {
+		return err
+	}
+
+	s.data = data
+	return nil
+}
+
+func (s *FileSimulationStore) Orders(ctx context.Context, tenant string) ([]model.SimulationOrder, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.SimulationOrder, 0)
+	for _, order := range s.data.Orders {
+		if tenant == "" || order.Tenant == tenant {
+			result = append(result, order)
+		}
+	}
+	return result, nil
+}
+
+// DeleteOrder removes an order of tenant along with its executions
+func (s *FileSimulationStore) DeleteOrder(ctx context.Context, tenant, id string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data := simulationData{
+		Orders:     make([]model.SimulationOrder, 0, len(s.data.Orders)),
+		Executions: make([]model.SimulatedExecution, 0, len(s.data.Executions)),
+	}
+	for _, order := range s.data.Orders {
+		if order.ID != id || order.Tenant != tenant {
+			data.Orders = append(data.Orders, order)
+		}
+	}
+	if len(data.Orders) == len(s.data.Orders) {
+		return false, nil
+	}
+	for _, execution := range s.data.Executions {
+		if execution.OrderID != id {
+			data.Executions = append(data.Executions, execution)
+		}
+	}
+
+	if err := utils.WriteJSONFile(s.path, data); err != nil {
+		return false, err
+	}
+
+	s.data = data
+	return true, nil
+}
+
+// RecordExecution appends an execution, dropping the oldest one of the same
+// order beyond the log limit
+func (s *FileSimulationStore) RecordExecution(ctx context.Context, execution *model.SimulatedExecution) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for _, e := range s.data.Executions {
+		if e.OrderID == execution.OrderID {
+			count++
+		}
+	}
+
+	executions := make([]model.SimulatedExecution, 0, len(s.data.Executions)+1)
+	for _, e := range s.data.Executions {
+		if e.OrderID == execution.OrderID && count >= s.logLimit {
+			count--
+			continue
+		}
+		executions = append(executions, e)
+	}
+	executions = append(executions, *execution)
+
+	data := simulationData{Orders: s.data.Orders, Executions: executions}
+	if err := utils.WriteJSONFile(s.path, data); err != nil {
+		return err
+	}
+
+	s.data = data
+	return nil
+}
+
+func (s *FileSimulationStore) Executions(ctx context.Context, orderID string) ([]model.SimulatedExecution, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.SimulatedExecution, 0)
+	for i := len(s.data.Executions) - 1; i >= 0; i-- {
+		if s.data.Executions[i].OrderID == orderID {
+			result = append(result, s.data.Executions[i])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// FileSLAStore aggregates provider calls into hourly buckets in memory and
+// flushes them to a JSON file periodically, since recording happens on every
+// upstream call and rewriting the file each time would be wasteful.
+type FileSLAStore struct {
+	path      string
+	retention time.Duration
+	buckets   map[string]*model.ProviderSLABucket
+	dirty     bool
+	mutex     sync.Mutex
+	log       *logger.Logger
+}
+
+func NewFileSLAStore(path string, retention time.Duration, log *logger.Logger) (*FileSLAStore, error) {
+	s := &FileSLAStore{
+		path:      path,
+		retention: retention,
+		buckets:   make(map[string]*model.ProviderSLABucket),
+		log:       log,
+	}
+
+	var stored []model.ProviderSLABucket
+	if err := utils.ReadJSONFile(path, &stored); err != nil {
+		return nil, err
+	}
+	for i := range stored {
+		bucket := stored[i]
+		s.buckets[bucketKey(bucket.Provider, bucket.Hour)] = &bucket
+	}
+
+	return s, nil
+}
+
+func bucketKey(provider string, hour time.Time) string {
+	return provider + "|" + hour.Format(time.RFC3339)
+}
+
+func (s *FileSLAStore) Record(ctx context.Context, provider string, latency time.Duration, success bool) {
+	now := time.Now().UTC()
+	hour := now.Truncate(time.Hour)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := bucketKey(provider, hour)
+	bucket, exists := s.buckets[key]
+	if !exists {
+		bucket = &model.ProviderSLABucket{
+			Provider:      provider,
+			Hour:          hour,
+			LatencyCounts: make([]int, len(model.LatencyBoundsMs)+1),
+		}
+		s.buckets[key] = bucket
+	}
+
+	bucket.Requests++
+	if success {
+		bucket.LastSuccess = now
+	} else {
+		bucket.Failures++
+	}
+
+	ms := float64(latency) / float64(time.Millisecond)
+	slot := sort.SearchFloat64s(model.LatencyBoundsMs, ms)
+	bucket.LatencyCounts[slot]++
+
+	s.dirty = true
+}
+
+func (s *FileSLAStore) Buckets(ctx context.Context, since time.Time) ([]model.ProviderSLABucket, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]model.ProviderSLABucket, 0)
+	for _, bucket := range s.buckets {
+		if !bucket.Hour.Before(since.Truncate(time.Hour)) {
+			copied := *bucket
+			copied.LatencyCounts = append([]int(nil), bucket.LatencyCounts...)
+			result = append(result, copied)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Hour.Equal(result[j].Hour) {
+			return result[i].Hour.Before(result[j].Hour)
+		}
+		return result[i].Provider < result[j].Provider
+	})
+
+	return result, nil
+}
+
+// Run flushes recorded buckets every interval until ctx is cancelled, then flushes once more
+func (s *FileSLAStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-ctx.Done():
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *FileSLAStore) flush() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cutoff := time.Now().UTC().Add(-s.retention)
+	for key, bucket := range s.buckets {
+		if bucket.Hour.Before(cutoff) {
+			delete(s.buckets, key)
+			s.dirty = true
+		}
+	}
+
+	if !s.dirty {
+		return
+	}
+
+	buckets := make([]model.ProviderSLABucket, 0, len(s.buckets))
+	for _, bucket := range s.buckets {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return bucketKey(buckets[i].Provider, buckets[i].Hour) < bucketKey(buckets[j].Provider, buckets[j].Hour)
+	})
+
+	if err := utils.WriteJSONFile(s.path, buckets); err != nil {
+		s.log.Error("Failed to persist provider SLA data", "error", err)
+		return
+	}
+	s.dirty = false
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// FileTickStore keeps intraday ticks per pair in memory, sorted by time, and
+// flushes them to a JSON file periodically like the SLA store, since ticks
+// arrive far more often than the file should be rewritten.
+type FileTickStore struct {
+	path      string
+	retention time.Duration
+	ticks     map[string][]model.Tick
+	dirty     bool
+	mutex     sync.Mutex
+	log       *logger.Logger
+}
+
+func NewFileTickStore(path string, retention time.Duration, log *logger.Logger) (*FileTickStore, error) {
+	s := &FileTickStore{
+		path:      path,
+		retention: retention,
+		ticks:     make(map[string][]model.Tick),
+		log:       log,
+	}
+
+	var stored []model.Tick
+	if err := utils.ReadJSONFile(path, &stored); err != nil {
+		return nil, err
+	}
+	s.insert(stored)
+
+	log.Info("Loaded intraday ticks", "path", path, "count", len(stored))
+	return s, nil
+}
+
+func (s *FileTickStore) Append(ctx context.Context, ticks []model.Tick) error {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.insert(ticks)
+	s.dirty = true
+	return nil
+}
+
+// insert adds ticks, keeping each pair sorted. Ticks normally arrive in order,
+// so the sort only runs for late deliveries.
+func (s *FileTickStore) insert(ticks []model.Tick) {
+	unsorted := make(map[string]bool)
+	for _, tick := range ticks {
+		key := tick.Pair.String()
+		series := s.ticks[key]
+		if n := len(series); n > 0 && tick.Time.Before(series[n-1].Time) {
+			unsorted[key] = true
+		}
+		s.ticks[key] = append(series, tick)
+	}
+
+	for key := range unsorted {
+		series := s.ticks[key]
+		sort.SliceStable(series, func(i, j int) bool {
+			return series[i].Time.Before(series[j].Time)
+		})
+	}
+}
+
+func (s *FileTickStore) Range(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.Tick, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	series := s.ticks[pair.String()]
+	start := sort.Search(len(series), func(i int) bool {
+		return !series[i].Time.Before(from)
+	})
+	end := sort.Search(len(series), func(i int) bool {
+		return !series[i].Time.Before(to)
+	})
+
+	return append([]model.Tick(nil), series[start:end]...), nil
+}
+
+// Run flushes ticks every interval until ctx is cancelled, then flushes once more
+func (s *FileTickStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-ctx.Done():
+			s.flush()
+			return
+		}
+	}
+}
+
+func (s *FileTickStore) flush() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cutoff := time.Now().UTC().Add(-s.retention)
+	for key, series := range s.ticks {
+		keep := sort.Search(len(series), func(i int) bool {
+			return !series[i].Time.Before(cutoff)
+		})
+		if keep == 0 {
+			continue
+		}
+		if keep == len(series) {
+			delete(s.ticks, key)
+		} else {
+			s.ticks[key] = append([]model.Tick(nil), series[keep:]...)
+		}
+		s.dirty = true
+	}
+
+	if !s.dirty {
+		return
+	}
+
+	if err := utils.WriteJSONFile(s.path, s.all()); err != nil {
+		s.log.Error("Failed to persist intraday ticks", "error", err)
+		return
+	}
+	s.dirty = false
+}
+
+func (s *FileTickStore) all() []model.Tick {
+	keys := make([]string, 0, len(s.ticks))
+	for key := range s.ticks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]model.Tick, 0)
+	for _, key := range keys {
+		result = append(result, s.ticks[key]...)
+	}
+	return result
+}
+
+// EvictOldest drops the given fraction of each pair's ticks from memory,
+// oldest first. The file catches up on the next flush.
+func (s *FileTickStore) EvictOldest(fraction float64) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	evicted := 0
+	for key, series := range s.ticks {
+		count := int(float64(len(series)) * fraction)
+		s.ticks[key] = append([]model.Tick(nil), series[count:]...)
+		evicted += count
+	}
+	if evicted > 0 {
+		s.dirty = true
+	}
+
+	return evicted
+}
@@ -0,0 +1,200 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+type webhookData struct {
+	Subscriptions []model.WebhookSubscription `json:"subscriptions"`
+	Deliveries    []model.WebhookDelivery     `json:"deliveries"`
+	// AlertRules are unset until an admin imports some
+	AlertRules *model.AlertRules `json:"alert_rules,omitempty"`
+}
+
+// FileWebhookStore keeps webhook subscriptions and the most recent deliveries
+// of each in memory and persists every change to a JSON file
+type FileWebhookStore struct {
+	path     string
+	logLimit int
+	data     webhookData
+	mutex    sync.RWMutex
+	log      *logger.Logger
+}
+
+// NewFileWebhookStore loads the store from path. logLimit is the number of
+// deliveries kept per subscription.
+func NewFileWebhookStore(path string, logLimit int, log *logger.Logger) (*FileWebhookStore, error) {
+	s := &FileWebhookStore{
+		path:     path,
+		logLimit: logLimit,
+		data: webhookData{
+			Subscriptions: make([]model.WebhookSubscription, 0),
+			Deliveries:    make([]model.WebhookDelivery, 0),
+		},
+		log: log,
+	}
+
+	if err := utils.ReadJSONFile(path, &s.data); err != nil {
+		return nil, err
+	}
+
+	log.Info("Loaded webhook subscriptions", "path", path, "count", len(s.data.Subscriptions))
+	return s, nil
+}
+
+func (s *FileWebhookStore) CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data := s.data
+	data.Subscriptions = append(append([]model.WebhookSubscription{}, s.data.Subscriptions...), *subscription)
+	if err := utils.WriteJSONFile(s.path, data); err != nil {
+		return err
+	}
+
+	s.data = data
+	return nil
+}
+
+func (s *FileWebhookStore) Subscriptions(ctx context.Context, tenant string) ([]model.WebhookSubscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.WebhookSubscription, 0)
+	for _, subscription := range s.data.Subscriptions {
+		if tenant == "" || subscription.Tenant == tenant {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+// UpdateSubscription replaces the subscription with the ID and tenant of
+// subscription, keeping its deliveries
+func (s *FileWebhookStore) UpdateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data := s.data
+	data.Subscriptions = append([]model.WebhookSubscription{}, s.data.Subscriptions...)
+	for i, existing := range data.Subscriptions {
+		if existing.ID != subscription.ID || existing.Tenant != subscription.Tenant {
+			continue
+		}
+		data.Subscriptions[i] = *subscription
+		if err := utils.WriteJSONFile(s.path, data); err != nil {
+			return false, err
+		}
+		s.data = data
+		return true, nil
+	}
+	return false, nil
+}
+
+// DeleteSubscription removes a subscription of tenant along with its deliveries
+func (s *FileWebhookStore) DeleteSubscription(ctx context.Context, tenant, id string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data := webhookData{
+		Subscriptions: make([]model.WebhookSubscription, 0, len(s.data.Subscriptions)),
+		Deliveries:    make([]model.WebhookDelivery, 0, len(s.data.Deliveries)),
+		AlertRules:    s.data.AlertRules,
+	}
+	for _, subscription := range s.data.Subscriptions {
+		if subscription.ID != id || subscription.Tenant != tenant {
+			data.Subscriptions = append(data.Subscriptions, subscription)
+		}
+	}
+	if len(data.Subscriptions) == len(s.data.Subscriptions) {
+		return false, nil
+	}
+	for _, delivery := range s.data.Deliveries {
+		if delivery.SubscriptionID != id {
+			data.Deliveries = append(data.Deliveries, delivery)
+		}
+	}
+
+	if err := utils.WriteJSONFile(s.path, data); err != nil {
+		return false, err
+	}
+
+	s.data = data
+	return true, nil
+}
+
+// RecordDelivery appends a delivery, dropping the oldest one of the same
+// subscription beyond the log limit
+func (s *FileWebhookStore) RecordDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	count := 0
+	for _, d := range s.data.Deliveries {
+		if d.SubscriptionID == delivery.SubscriptionID {
+			count++
+		}
+	}
+
+	deliveries := make([]model.WebhookDelivery, 0, len(s.data.Deliveries)+1)
+	for _, d := range s.data.Deliveries {
+		if d.SubscriptionID == delivery.SubscriptionID && count >= s.logLimit {
+			count--
+			continue
+		}
+		deliveries = append(deliveries, d)
+	}
+	deliveries = append(deliveries, *delivery)
+
+	data := webhookData{Subscriptions: s.data.Subscriptions, Deliveries: deliveries, AlertRules: s.data.AlertRules}
+	if err := utils.WriteJSONFile(s.path, data); err != nil {
+		return err
+	}
+
+	s.data = data
+	return nil
+}
+
+func (s *FileWebhookStore) Deliveries(ctx context.Context, subscriptionID string) ([]model.WebhookDelivery, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]model.WebhookDelivery, 0)
+	for i := len(s.data.Deliveries) - 1; i >= 0; i-- {
+		if s.data.Deliveries[i].SubscriptionID == subscriptionID {
+			result = append(result, s.data.Deliveries[i])
+		}
+	}
+	return result, nil
+}
+
+// AlertRules returns the saved alert rules, or nil when none were saved
+func (s *FileWebhookStore) AlertRules(ctx context.Context) (*model.AlertRules, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.data.AlertRules == nil {
+		return nil, nil
+	}
+	rules := *s.data.AlertRules
+	return &rules, nil
+}
+
+func (s *FileWebhookStore) SaveAlertRules(ctx context.Context, rules model.AlertRules) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data := s.data
+	data.AlertRules = &rules
+	if err := utils.WriteJSONFile(s.path, data); err != nil {
+		return err
+	}
+
+	s.data = data
+	return nil
+}
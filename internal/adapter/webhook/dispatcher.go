@@ -0,0 +1,386 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/delivery"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+const (
+	// maxSubscriptions bounds the subscriptions of a single tenant
+	maxSubscriptions = 20
+	userAgent        = "exchange-rate-service-webhooks"
+	// ledgerKind labels webhook deliveries in the delivery ledger
+	ledgerKind = "webhook"
+)
+
+var (
+	ErrInvalidSubscription  = errors.New("invalid webhook subscription")
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrTooManySubscriptions = errors.New("too many webhook subscriptions")
+)
+
+// Dispatcher delivers events to the webhook subscriptions of API key holders.
+// Every delivery is signed with the subscription's secret the same way
+// providers sign their pushes: X-Webhook-Signature is sha256=<hex> of
+// HMAC-SHA256 over "<X-Webhook-Timestamp>.<body>".
+type Dispatcher struct {
+	store          ports.WebhookStore
+	client         *http.Client
+	alertThreshold float64
+	metrics        *metrics.Metrics
+	log            *logger.Logger
+	ledger         *delivery.Ledger
+
+	// mutex guards alertThreshold too, which admins may import
+	mutex    sync.Mutex
+	last     map[model.CurrencyPair]float64
+	inflight sync.WaitGroup
+}
+
+// NewDispatcher starts from the alert rules saved in store, if any, and
+// otherwise from the threshold of cfg
+func NewDispatcher(store ports.WebhookStore, cfg config.WebhookConfig, metrics *metrics.Metrics, log *logger.Logger) *Dispatcher {
+	d := &Dispatcher{
+		store:          store,
+		client:         &http.Client{Timeout: cfg.Timeout},
+		alertThreshold: cfg.AlertThreshold,
+		metrics:        metrics,
+		log:            log,
+		last:           make(map[model.CurrencyPair]float64),
+	}
+
+	rules, err := store.AlertRules(context.Background())
+	switch {
+	case err != nil:
+		log.Warn("Ignoring saved alert rules", "error", err)
+	case rules != nil:
+		d.alertThreshold = rules.ThresholdPercent
+		log.Info("Using saved alert rules", "threshold_percent", rules.ThresholdPercent)
+	}
+	return d
+}
+
+// UseLedger hands failed deliveries to ledger for retries. It must be called
+// before the first event.
+func (d *Dispatcher) UseLedger(ledger *delivery.Ledger) {
+	d.ledger = ledger
+	ledger.Register(ledgerKind, d.redeliver)
+}
+
+// Subscribe validates and stores a subscription of tenant. The returned copy
+// is the only one that includes the signing secret.
+func (d *Dispatcher) Subscribe(ctx context.Context, tenant string, subscription model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	target, events, err := validateSubscription(subscription.URL, subscription.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := d.store.Subscriptions(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxSubscriptions {
+		return nil, fmt.Errorf("%w: the limit is %d", ErrTooManySubscriptions, maxSubscriptions)
+	}
+
+	created := model.WebhookSubscription{
+		ID:        "whk_" + randomHex(8),
+		Tenant:    tenant,
+		URL:       target,
+		Events:    events,
+		Secret:    newSecret(),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := d.store.CreateSubscription(ctx, &created); err != nil {
+		d.log.Error("Failed to store webhook subscription", "error", err)
+		return nil, err
+	}
+
+	d.log.Info("Webhook subscription created", "id", created.ID, "tenant", tenant, "events", events)
+	return &created, nil
+}
+
+// validateSubscription checks the URL and events of a subscription, returning
+// them normalized and without duplicate events
+func validateSubscription(rawURL string, events []model.WebhookEventType) (string, []model.WebhookEventType, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return "", nil, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+
+	if len(events) == 0 {
+		return "", nil, fmt.Errorf("%w: at least one event is required", ErrInvalidSubscription)
+	}
+	unique := make([]model.WebhookEventType, 0, len(events))
+	for _, event := range events {
+		if !event.Valid() {
+			return "", nil, fmt.Errorf("%w: unknown event %q", ErrInvalidSubscription, event)
+		}
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return target.String(), unique, nil
+}
+
+// Subscriptions lists the subscriptions of tenant without their secrets
+func (d *Dispatcher) Subscriptions(ctx context.Context, tenant string) ([]model.WebhookSubscription, error) {
+	subscriptions, err := d.store.Subscriptions(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+func (d *Dispatcher) Unsubscribe(ctx context.Context, tenant, id string) error {
+	deleted, err := d.store.DeleteSubscription(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrSubscriptionNotFound
+	}
+
+	d.log.Info("Webhook subscription deleted", "id", id, "tenant", tenant)
+	return nil
+}
+
+// Deliveries returns the delivery log of a subscription of tenant, newest first
+func (d *Dispatcher) Deliveries(ctx context.Context, tenant, id string) ([]model.WebhookDelivery, error) {
+	subscriptions, err := d.store.Subscriptions(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	for _, subscription := range subscriptions {
+		if subscription.ID == id {
+			return d.store.Deliveries(ctx, id)
+		}
+	}
+	return nil, ErrSubscriptionNotFound
+}
+
+// Publish sends rate.refreshed with the rates, and alert.triggered for every
+// rate that moved by at least the alert threshold since it was last published
+func (d *Dispatcher) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	d.Emit(model.EventRateRefreshed, rates)
+	for _, alert := range d.alerts(rates) {
+		d.Emit(model.EventAlertTriggered, alert)
+	}
+	return nil
+}
+
+func (d *Dispatcher) alerts(rates []model.ExchangeRate) []model.RateAlert {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	alerts := make([]model.RateAlert, 0)
+	for _, rate := range rates {
+		pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+		previous, seen := d.last[pair]
+		d.last[pair] = rate.Rate
+		if !seen || previous == 0 || d.alertThreshold <= 0 {
+			continue
+		}
+
+		change := (rate.Rate - previous) / previous * 100
+		if math.Abs(change) >= d.alertThreshold {
+			alerts = append(alerts, model.RateAlert{
+				BaseCurrency:   rate.BaseCurrency,
+				TargetCurrency: rate.TargetCurrency,
+				PreviousRate:   previous,
+				Rate:           rate.Rate,
+				ChangePercent:  math.Round(change*1e4) / 1e4,
+			})
+		}
+	}
+	return alerts
+}
+
+// GuardrailTripped sends alert.triggered for every move that kept a refresh
+// from being served. It matches the callback of repository.ExchangeAPI.GuardMoves.
+func (d *Dispatcher) GuardrailTripped(moves []model.RateAlert) {
+	for _, move := range moves {
+		d.Emit(model.EventAlertTriggered, move)
+	}
+}
+
+// AnomaliesDetected sends anomaly.detected for every anomaly. It matches the
+// callback of anomaly.Detector.OnAnomaly.
+func (d *Dispatcher) AnomaliesDetected(anomalies []model.RateAnomaly) {
+	for _, anomaly := range anomalies {
+		d.Emit(model.EventAnomalyDetected, anomaly)
+	}
+}
+
+// ProviderFailover sends provider.failover. It matches the callback of
+// repository.EndpointPool.OnSwitch.
+func (d *Dispatcher) ProviderFailover(provider, from, to string) {
+	d.Emit(model.EventProviderFailover, model.ProviderFailover{Provider: provider, From: from, To: to})
+}
+
+// Emit delivers an event to every subscription of its type in the background
+func (d *Dispatcher) Emit(eventType model.WebhookEventType, data interface{}) {
+	subscriptions, err := d.store.Subscriptions(context.Background(), "")
+	if err != nil {
+		d.log.Error("Failed to list webhook subscriptions", "error", err)
+		return
+	}
+
+	event := model.WebhookEvent{
+		ID:        "evt_" + randomHex(8),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	var body []byte
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(eventType) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(event); err != nil {
+				d.log.Error("Failed to encode webhook event", "event", eventType, "error", err)
+				return
+			}
+		}
+
+		d.inflight.Add(1)
+		go func() {
+			defer d.inflight.Done()
+			if err := d.deliver(subscription, event, body); err != nil && d.ledger != nil {
+				d.ledger.Failed(context.Background(), ledgerKind, subscription.ID, string(eventType), body, err)
+			}
+		}()
+	}
+}
+
+// redeliver is the ledger's sender for webhook deliveries
+func (d *Dispatcher) redeliver(ctx context.Context, target string, payload []byte) error {
+	subscriptions, err := d.store.Subscriptions(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var event model.WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return delivery.Permanent(err)
+	}
+	for _, subscription := range subscriptions {
+		if subscription.ID == target {
+			return d.deliver(subscription, event, payload)
+		}
+	}
+	return delivery.Permanent(ErrSubscriptionNotFound)
+}
+
+// deliver posts an event once and logs the attempt on the subscription
+func (d *Dispatcher) deliver(subscription model.WebhookSubscription, event model.WebhookEvent, body []byte) error {
+	delivery := model.WebhookDelivery{
+		ID:             "dlv_" + randomHex(8),
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		Event:          event.Type,
+		AttemptedAt:    time.Now().UTC(),
+	}
+
+	status, err := d.post(subscription, event, body)
+	delivery.DurationMs = time.Since(delivery.AttemptedAt).Milliseconds()
+	delivery.StatusCode = status
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case status < 200 || status > 299:
+		delivery.Error = "unexpected status " + strconv.Itoa(status)
+	default:
+		delivery.Success = true
+	}
+
+	result := "delivered"
+	if !delivery.Success {
+		result = "failed"
+		d.log.Warn("Webhook delivery failed", "subscription", subscription.ID, "event", event.Type, "error", delivery.Error)
+	}
+	d.metrics.WebhookDeliveries.WithLabelValues(string(event.Type), result).Inc()
+
+	if err := d.store.RecordDelivery(context.Background(), &delivery); err != nil {
+		d.log.Error("Failed to record webhook delivery", "subscription", subscription.ID, "error", err)
+	}
+
+	if !delivery.Success {
+		return errors.New(delivery.Error)
+	}
+	return nil
+}
+
+func (d *Dispatcher) post(subscription model.WebhookSubscription, event model.WebhookEvent, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Webhook-ID", event.ID)
+	req.Header.Set("X-Webhook-Event", string(event.Type))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// Close waits for deliveries in flight
+func (d *Dispatcher) Close() {
+	d.inflight.Wait()
+}
+
+// Sign returns the X-Webhook-Signature value for body sent at timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newSecret() string {
+	return "whsec_" + randomHex(24)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
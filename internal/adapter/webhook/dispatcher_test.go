@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	t.Helper()
+	log := logger.NewLogger("error")
+	webhookStore, err := store.NewFileWebhookStore(filepath.Join(t.TempDir(), "webhooks.json"), 10, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appMetrics := &metrics.Metrics{
+		WebhookDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "webhook_deliveries_total"}, []string{"event", "result"}),
+	}
+	return NewDispatcher(webhookStore, config.WebhookConfig{Timeout: time.Second, AlertThreshold: 1}, appMetrics, log)
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	var mutex sync.Mutex
+	received := make(map[model.WebhookEventType]int)
+	var signatureErr error
+	var secret string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if Sign(secret, r.Header.Get("X-Webhook-Timestamp"), body) != r.Header.Get("X-Webhook-Signature") {
+			signatureErr = errors.New("signature mismatch")
+		}
+		var event model.WebhookEvent
+		json.Unmarshal(body, &event)
+		received[event.Type]++
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(t)
+	ctx := context.Background()
+
+	if _, err := d.Subscribe(ctx, "tenant-a", model.WebhookSubscription{URL: server.URL, Events: []model.WebhookEventType{"rate.updated"}}); !errors.Is(err, ErrInvalidSubscription) {
+		t.Errorf("Expected an unknown event to be rejected, got %v", err)
+	}
+
+	created, err := d.Subscribe(ctx, "tenant-a", model.WebhookSubscription{
+		URL:    server.URL,
+		Events: []model.WebhookEventType{model.EventRateRefreshed, model.EventAlertTriggered},
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	mutex.Lock()
+	secret = created.Secret
+	mutex.Unlock()
+
+	rate := func(value float64) []model.ExchangeRate {
+		return []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: value}}
+	}
+	d.Publish(ctx, rate(83.0))
+	d.Publish(ctx, rate(83.1))
+	d.Publish(ctx, rate(85.0))
+	d.ProviderFailover("primary", "https://eu.example.com", "https://us.example.com")
+	d.Close()
+
+	if signatureErr != nil {
+		t.Error(signatureErr)
+	}
+	if received[model.EventRateRefreshed] != 3 || received[model.EventAlertTriggered] != 1 || received[model.EventProviderFailover] != 0 {
+		t.Errorf("Unexpected deliveries: %v", received)
+	}
+
+	deliveries, err := d.Deliveries(ctx, "tenant-a", created.ID)
+	if err != nil || len(deliveries) != 4 || !deliveries[0].Success {
+		t.Errorf("Expected 4 successful deliveries in the log, got %v (%v)", deliveries, err)
+	}
+
+	// Other tenants can neither see nor delete the subscription
+	if _, err := d.Deliveries(ctx, "tenant-b", created.ID); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Expected another tenant's deliveries to be hidden, got %v", err)
+	}
+	if err := d.Unsubscribe(ctx, "tenant-b", created.ID); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Expected another tenant's delete to fail, got %v", err)
+	}
+
+	list, _ := d.Subscriptions(ctx, "tenant-a")
+	if len(list) != 1 || list[0].Secret != "" {
+		t.Errorf("Expected one subscription without its secret, got %v", list)
+	}
+}
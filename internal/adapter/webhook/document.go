@@ -0,0 +1,234 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// ErrInvalidAlertDocument is returned for an import that cannot be applied
+// as a whole. Nothing is changed then.
+var ErrInvalidAlertDocument = errors.New("invalid alert document")
+
+// AlertRules returns the rules alert.triggered is currently sent by
+func (d *Dispatcher) AlertRules() model.AlertRules {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return model.AlertRules{ThresholdPercent: d.alertThreshold}
+}
+
+// Export returns the alert rules and the subscriptions of every tenant.
+// Secrets are left out unless includeSecrets is set.
+func (d *Dispatcher) Export(ctx context.Context, includeSecrets bool) (*model.AlertDocument, error) {
+	subscriptions, err := d.store.Subscriptions(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	document := &model.AlertDocument{
+		AlertRules: d.AlertRules(),
+		Webhooks:   make([]model.WebhookDefinition, 0, len(subscriptions)),
+	}
+	for _, subscription := range subscriptions {
+		definition := model.WebhookDefinition{
+			ID:     subscription.ID,
+			Tenant: subscription.Tenant,
+			URL:    subscription.URL,
+			Events: subscription.Events,
+		}
+		if includeSecrets {
+			definition.Secret = subscription.Secret
+		}
+		document.Webhooks = append(document.Webhooks, definition)
+	}
+	return document, nil
+}
+
+// Import applies document. Webhooks with the ID of a subscription of their
+// tenant replace it, keeping its deliveries and, unless they have one, its
+// secret; the others are created, with the ID they have, if any. With prune,
+// subscriptions the document does not list are deleted, so that the document
+// describes the result exactly. The whole document is validated first.
+func (d *Dispatcher) Import(ctx context.Context, document model.AlertDocument, prune bool) (*model.AlertImportResult, error) {
+	planned, err := d.planImport(ctx, document, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.store.SaveAlertRules(ctx, document.AlertRules); err != nil {
+		return nil, err
+	}
+	d.mutex.Lock()
+	d.alertThreshold = document.AlertRules.ThresholdPercent
+	d.mutex.Unlock()
+
+	for _, change := range planned.changes {
+		switch {
+		case change.previous == nil:
+			if err := d.store.CreateSubscription(ctx, &change.subscription); err != nil {
+				return nil, err
+			}
+		case change.unchanged():
+		default:
+			if _, err := d.store.UpdateSubscription(ctx, &change.subscription); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, subscription := range planned.deleted {
+		if _, err := d.store.DeleteSubscription(ctx, subscription.Tenant, subscription.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := planned.result(document.AlertRules)
+	d.log.Info("Alert document imported",
+		"threshold_percent", document.AlertRules.ThresholdPercent,
+		"created", len(result.Created),
+		"updated", len(result.Updated),
+		"deleted", len(result.Deleted),
+	)
+	return result, nil
+}
+
+// PlanImport validates document and returns what importing it would do,
+// without doing it. Subscriptions to create get IDs and secrets of their own
+// on import, unless the document gives them.
+func (d *Dispatcher) PlanImport(ctx context.Context, document model.AlertDocument, prune bool) (*model.AlertImportResult, error) {
+	planned, err := d.planImport(ctx, document, prune)
+	if err != nil {
+		return nil, err
+	}
+	return planned.result(document.AlertRules), nil
+}
+
+func (d *Dispatcher) planImport(ctx context.Context, document model.AlertDocument, prune bool) (*importPlan, error) {
+	existing, err := d.store.Subscriptions(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return d.plan(document, existing, prune)
+}
+
+// subscriptionChange is a webhook of an imported document, with the
+// subscription it replaces, if any
+type subscriptionChange struct {
+	subscription model.WebhookSubscription
+	previous     *model.WebhookSubscription
+}
+
+func (c subscriptionChange) unchanged() bool {
+	return c.subscription.URL == c.previous.URL &&
+		c.subscription.Secret == c.previous.Secret &&
+		slices.Equal(c.subscription.Events, c.previous.Events)
+}
+
+type importPlan struct {
+	changes []subscriptionChange
+	deleted []model.WebhookSubscription
+}
+
+func (p *importPlan) result(rules model.AlertRules) *model.AlertImportResult {
+	result := &model.AlertImportResult{
+		AlertRules: rules,
+		Created:    make([]model.WebhookSubscription, 0),
+		Updated:    make([]string, 0),
+		Unchanged:  make([]string, 0),
+		Deleted:    make([]string, 0),
+	}
+	for _, change := range p.changes {
+		switch {
+		case change.previous == nil:
+			result.Created = append(result.Created, change.subscription)
+		case change.unchanged():
+			result.Unchanged = append(result.Unchanged, change.subscription.ID)
+		default:
+			result.Updated = append(result.Updated, change.subscription.ID)
+		}
+	}
+	for _, subscription := range p.deleted {
+		result.Deleted = append(result.Deleted, subscription.ID)
+	}
+	return result
+}
+
+// plan validates document against the existing subscriptions and works out
+// the changes importing it makes
+func (d *Dispatcher) plan(document model.AlertDocument, existing []model.WebhookSubscription, prune bool) (*importPlan, error) {
+	if threshold := document.AlertRules.ThresholdPercent; threshold < 0 || math.IsNaN(threshold) || math.IsInf(threshold, 0) {
+		return nil, fmt.Errorf("%w: alert_rules.threshold_percent must not be negative", ErrInvalidAlertDocument)
+	}
+
+	byID := make(map[string]model.WebhookSubscription, len(existing))
+	for _, subscription := range existing {
+		byID[subscription.ID] = subscription
+	}
+
+	plan := &importPlan{changes: make([]subscriptionChange, 0, len(document.Webhooks))}
+	listed := make(map[string]bool, len(document.Webhooks))
+	perTenant := make(map[string]int)
+	now := time.Now().UTC()
+	for i, definition := range document.Webhooks {
+		if definition.Tenant == "" {
+			return nil, fmt.Errorf("%w: webhooks[%d]: tenant is required", ErrInvalidAlertDocument, i)
+		}
+		target, events, err := validateSubscription(definition.URL, definition.Events)
+		if err != nil {
+			return nil, fmt.Errorf("%w: webhooks[%d]: %w", ErrInvalidAlertDocument, i, err)
+		}
+
+		change := subscriptionChange{subscription: model.WebhookSubscription{
+			ID:        definition.ID,
+			Tenant:    definition.Tenant,
+			URL:       target,
+			Events:    events,
+			Secret:    definition.Secret,
+			CreatedAt: now,
+		}}
+		if change.subscription.ID == "" {
+			change.subscription.ID = "whk_" + randomHex(8)
+		}
+		if listed[change.subscription.ID] {
+			return nil, fmt.Errorf("%w: webhooks[%d]: id %s is listed twice", ErrInvalidAlertDocument, i, definition.ID)
+		}
+		listed[change.subscription.ID] = true
+
+		if previous, found := byID[change.subscription.ID]; found {
+			if previous.Tenant != definition.Tenant {
+				return nil, fmt.Errorf("%w: webhooks[%d]: id %s belongs to another tenant", ErrInvalidAlertDocument, i, definition.ID)
+			}
+			change.previous = &previous
+			change.subscription.CreatedAt = previous.CreatedAt
+			if change.subscription.Secret == "" {
+				change.subscription.Secret = previous.Secret
+			}
+		} else if change.subscription.Secret == "" {
+			change.subscription.Secret = newSecret()
+		}
+
+		perTenant[definition.Tenant]++
+		plan.changes = append(plan.changes, change)
+	}
+
+	for _, subscription := range existing {
+		switch {
+		case listed[subscription.ID]:
+		case prune:
+			plan.deleted = append(plan.deleted, subscription)
+		default:
+			perTenant[subscription.Tenant]++
+		}
+	}
+	for tenant, count := range perTenant {
+		if count > maxSubscriptions {
+			return nil, fmt.Errorf("%w: tenant %s would have %d subscriptions, the limit is %d", ErrInvalidAlertDocument, tenant, count, maxSubscriptions)
+		}
+	}
+	return plan, nil
+}
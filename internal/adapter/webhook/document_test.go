@@ -0,0 +1,136 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestImportAlertDocument(t *testing.T) {
+	d := newTestDispatcher(t)
+	ctx := context.Background()
+
+	kept, err := d.Subscribe(ctx, "tenant-a", model.WebhookSubscription{URL: "https://a.example/hook", Events: []model.WebhookEventType{model.EventRateRefreshed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := d.Subscribe(ctx, "tenant-a", model.WebhookSubscription{URL: "https://a.example/alerts", Events: []model.WebhookEventType{model.EventAlertTriggered}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := d.Subscribe(ctx, "tenant-b", model.WebhookSubscription{URL: "https://b.example/hook", Events: []model.WebhookEventType{model.EventRateRefreshed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := d.Export(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(document.Webhooks) != 3 || document.Webhooks[0].Secret != "" || document.AlertRules.ThresholdPercent != 1 {
+		t.Fatalf("Expected 3 webhooks without secrets and the configured threshold, got %+v", document)
+	}
+
+	document.AlertRules.ThresholdPercent = 2.5
+	document.Webhooks = []model.WebhookDefinition{
+		document.Webhooks[0],
+		{ID: changed.ID, Tenant: "tenant-a", URL: "https://a.example/alerts", Events: []model.WebhookEventType{model.EventAlertTriggered, model.EventAnomalyDetected}},
+		{Tenant: "tenant-c", URL: "https://c.example/hook", Events: []model.WebhookEventType{model.EventProviderFailover}},
+	}
+	result, err := d.Import(ctx, *document, true)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if len(result.Created) != 1 || result.Created[0].Tenant != "tenant-c" || result.Created[0].Secret == "" {
+		t.Errorf("Expected the tenant-c webhook to be created with a secret, got %+v", result.Created)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != changed.ID {
+		t.Errorf("Expected %s to be updated, got %v", changed.ID, result.Updated)
+	}
+	if len(result.Unchanged) != 1 || result.Unchanged[0] != kept.ID {
+		t.Errorf("Expected %s to be unchanged, got %v", kept.ID, result.Unchanged)
+	}
+	if len(result.Deleted) != 1 || result.Deleted[0] != stale.ID {
+		t.Errorf("Expected %s to be pruned, got %v", stale.ID, result.Deleted)
+	}
+	if got := d.AlertRules().ThresholdPercent; got != 2.5 {
+		t.Errorf("Expected the imported threshold to apply, got %v", got)
+	}
+
+	exported, err := d.Export(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, webhook := range exported.Webhooks {
+		if webhook.ID == changed.ID && (webhook.Secret != changed.Secret || len(webhook.Events) != 2) {
+			t.Errorf("Expected the updated webhook to keep its secret and get the new events, got %+v", webhook)
+		}
+	}
+}
+
+func TestImportRejectsInvalidDocuments(t *testing.T) {
+	d := newTestDispatcher(t)
+	ctx := context.Background()
+	hook := model.WebhookDefinition{Tenant: "tenant-a", URL: "https://a.example/hook", Events: []model.WebhookEventType{model.EventRateRefreshed}}
+
+	tests := map[string]model.AlertDocument{
+		"negative threshold": {AlertRules: model.AlertRules{ThresholdPercent: -1}},
+		"missing tenant":     {Webhooks: []model.WebhookDefinition{{URL: hook.URL, Events: hook.Events}}},
+		"unknown event":      {Webhooks: []model.WebhookDefinition{{Tenant: "tenant-a", URL: hook.URL, Events: []model.WebhookEventType{"rate.updated"}}}},
+		"duplicate id": {Webhooks: []model.WebhookDefinition{
+			{ID: "whk_1", Tenant: hook.Tenant, URL: hook.URL, Events: hook.Events},
+			{ID: "whk_1", Tenant: hook.Tenant, URL: hook.URL, Events: hook.Events},
+		}},
+	}
+	tooMany := model.AlertDocument{}
+	for i := 0; i <= maxSubscriptions; i++ {
+		tooMany.Webhooks = append(tooMany.Webhooks, hook)
+	}
+	tests["too many subscriptions"] = tooMany
+
+	for name, document := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := d.Import(ctx, document, false); !errors.Is(err, ErrInvalidAlertDocument) {
+				t.Errorf("Expected ErrInvalidAlertDocument, got %v", err)
+			}
+		})
+	}
+
+	exported, err := d.Export(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Webhooks) != 0 || exported.AlertRules.ThresholdPercent != 1 {
+		t.Errorf("Expected rejected imports to change nothing, got %+v", exported)
+	}
+}
+
+func TestImportedAlertRulesOutliveRestarts(t *testing.T) {
+	log := logger.NewLogger("error")
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	cfg := config.WebhookConfig{Timeout: time.Second, AlertThreshold: 1}
+
+	webhookStore, err := store.NewFileWebhookStore(path, 10, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDispatcher(webhookStore, cfg, nil, log).Import(context.Background(), model.AlertDocument{AlertRules: model.AlertRules{ThresholdPercent: 0.5}}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := store.NewFileWebhookStore(path, 10, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := NewDispatcher(reloaded, cfg, nil, log).AlertRules().ThresholdPercent; got != 0.5 {
+		t.Errorf("Expected the saved threshold over the configured one, got %v", got)
+	}
+}
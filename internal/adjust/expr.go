@@ -0,0 +1,259 @@
+package adjust
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a compiled arithmetic expression over the variable rate.
+// It supports numbers, + - * /, unary minus, parentheses and the functions
+// min(a, b), max(a, b) and round(x, digits).
+type Expression struct {
+	source string
+	eval   func(rate float64) float64
+}
+
+// Compile parses src into an Expression
+func Compile(src string) (*Expression, error) {
+	p := &parser{src: src}
+	p.next()
+
+	eval, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEnd {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+	return &Expression{source: src, eval: eval}, nil
+}
+
+// Eval evaluates the expression for rate. The result may be infinite or NaN,
+// e.g. after a division by zero.
+func (e *Expression) Eval(rate float64) float64 {
+	return e.eval(rate)
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenInvalid
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("expression %q at %d: %s", p.src, p.tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+// next reads the following token into p.tok
+func (p *parser) next() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos == len(p.src) {
+		p.tok = token{kind: tokenEnd, pos: start}
+		return
+	}
+
+	c := rune(p.src[p.pos])
+	switch {
+	case unicode.IsDigit(c) || c == '.':
+		for p.pos < len(p.src) && (unicode.IsDigit(rune(p.src[p.pos])) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		p.tok = token{kind: tokenNumber, text: p.src[start:p.pos], pos: start}
+	case unicode.IsLetter(c) || c == '_':
+		for p.pos < len(p.src) && (unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos])) || p.src[p.pos] == '_') {
+			p.pos++
+		}
+		p.tok = token{kind: tokenIdent, text: p.src[start:p.pos], pos: start}
+	case strings.ContainsRune("+-*/(),", c):
+		p.pos++
+		p.tok = token{kind: tokenOperator, text: string(c), pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokenInvalid, text: string(c), pos: start}
+	}
+}
+
+func (p *parser) accept(operator string) bool {
+	if p.tok.kind == tokenOperator && p.tok.text == operator {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// expr := term (("+" | "-") term)*
+func (p *parser) expr() (func(float64) float64, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.accept("+"):
+			right, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			l := left
+			left = func(rate float64) float64 { return l(rate) + right(rate) }
+		case p.accept("-"):
+			right, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			l := left
+			left = func(rate float64) float64 { return l(rate) - right(rate) }
+		default:
+			return left, nil
+		}
+	}
+}
+
+// term := unary (("*" | "/") unary)*
+func (p *parser) term() (func(float64) float64, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.accept("*"):
+			right, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			l := left
+			left = func(rate float64) float64 { return l(rate) * right(rate) }
+		case p.accept("/"):
+			right, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			l := left
+			left = func(rate float64) float64 { return l(rate) / right(rate) }
+		default:
+			return left, nil
+		}
+	}
+}
+
+// unary := "-" unary | primary
+func (p *parser) unary() (func(float64) float64, error) {
+	if p.accept("-") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(rate float64) float64 { return -operand(rate) }, nil
+	}
+	return p.primary()
+}
+
+// primary := number | "rate" | function "(" args ")" | "(" expr ")"
+func (p *parser) primary() (func(float64) float64, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", tok.text)
+		}
+		p.next()
+		return func(float64) float64 { return value }, nil
+
+	case tok.kind == tokenIdent && tok.text == "rate":
+		p.next()
+		return func(rate float64) float64 { return rate }, nil
+
+	case tok.kind == tokenIdent:
+		p.next()
+		return p.call(tok)
+
+	case p.accept("("):
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("missing )")
+		}
+		return inner, nil
+
+	case tok.kind == tokenEnd:
+		return nil, p.errorf("unexpected end")
+	default:
+		return nil, p.errorf("unexpected %q", tok.text)
+	}
+}
+
+func (p *parser) call(name token) (func(float64) float64, error) {
+	if !p.accept("(") {
+		return nil, fmt.Errorf("expression %q at %d: unknown variable %q", p.src, name.pos+1, name.text)
+	}
+
+	args := make([]func(float64) float64, 0, 2)
+	if !p.accept(")") {
+		for {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.accept(")") {
+				break
+			}
+			if !p.accept(",") {
+				return nil, p.errorf("expected , or )")
+			}
+		}
+	}
+
+	var apply func(a, b float64) float64
+	switch name.text {
+	case "min":
+		apply = math.Min
+	case "max":
+		apply = math.Max
+	case "round":
+		apply = func(x, digits float64) float64 {
+			scale := math.Pow(10, math.Round(digits))
+			return math.Round(x*scale) / scale
+		}
+	default:
+		return nil, fmt.Errorf("expression %q at %d: unknown function %q", p.src, name.pos+1, name.text)
+	}
+
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expression %q at %d: %s takes 2 arguments", p.src, name.pos+1, name.text)
+	}
+	a, b := args[0], args[1]
+	return func(rate float64) float64 { return apply(a(rate), b(rate)) }, nil
+}
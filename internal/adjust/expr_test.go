@@ -0,0 +1,51 @@
+package adjust
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		expr     string
+		rate     float64
+		expected float64
+	}{
+		{name: "Identity", expr: "rate", rate: 83.1, expected: 83.1},
+		{name: "Markup", expr: "rate * 1.002 + 0.01", rate: 100, expected: 100.21},
+		{name: "Precedence", expr: "rate + 2 * 3", rate: 1, expected: 7},
+		{name: "Parentheses", expr: "(rate + 2) * 3", rate: 1, expected: 9},
+		{name: "Left Associative", expr: "rate - 2 - 3", rate: 10, expected: 5},
+		{name: "Division", expr: "rate / 4 / 2", rate: 16, expected: 2},
+		{name: "Unary Minus", expr: "-rate + 10", rate: 4, expected: 6},
+		{name: "Min", expr: "min(rate * 1.05, rate + 1)", rate: 100, expected: 101},
+		{name: "Max", expr: "max(rate * 1.05, rate + 1)", rate: 100, expected: 105},
+		{name: "Round", expr: "round(rate * 1.0015, 2)", rate: 83.1234, expected: 83.25},
+		{name: "Whitespace", expr: "\trate*2 ", rate: 3, expected: 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			expression, err := Compile(tc.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := expression.Eval(tc.rate); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("Expected: %v, got: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+
+	for _, expr := range []string{"", "rate *", "(rate", "rate)", "spread * 2", "sqrt(rate, 2)", "min(rate)", "rate $ 2", "1.2.3", "rate 2"} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Expected an error for %q", expr)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package adjust
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync/atomic"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// anyPair matches every pair in a rule
+const anyPair = "*"
+
+// Rule adjusts the rates of Pair, or of every pair when Pair is "*". A rule
+// with a Tenant only applies to requests with that tenant's API key.
+type Rule struct {
+	Pair   string `json:"pair"`
+	Tenant string `json:"tenant,omitempty"`
+	Expr   string `json:"expr"`
+}
+
+type ruleKey struct {
+	pair   string
+	tenant string
+}
+
+// Adjuster applies the rules of a JSON file to served rates. The most
+// specific rule wins: tenant and pair, then tenant, then pair, then "*".
+type Adjuster struct {
+	path  string
+	rules atomic.Pointer[ruleSet]
+	log   *logger.Logger
+}
+
+// ruleSet is the rules in place, as written and compiled
+type ruleSet struct {
+	rules    []Rule
+	compiled map[ruleKey]*Expression
+}
+
+// NewAdjuster loads the rules in path. A missing file has no rules.
+func NewAdjuster(path string, log *logger.Logger) (*Adjuster, error) {
+	a := &Adjuster{path: path, log: log}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload reads the rules file again. If it is invalid the current rules stay
+// in place.
+func (a *Adjuster) Reload() error {
+	var rules []Rule
+	if err := utils.ReadJSONFile(a.path, &rules); err != nil {
+		return err
+	}
+
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return fmt.Errorf("%s: %w", a.path, err)
+	}
+
+	a.rules.Store(&ruleSet{rules: rules, compiled: compiled})
+	a.log.Info("Loaded rate adjustments", "path", a.path, "rules", len(compiled))
+	return nil
+}
+
+// Rules returns the rules in place, in the order they were given
+func (a *Adjuster) Rules() []Rule {
+	return slices.Clone(a.rules.Load().rules)
+}
+
+// Save writes rules to the rules file and puts them in place. Invalid rules
+// are refused, leaving the file and the current rules as they were.
+func (a *Adjuster) Save(rules []Rule) error {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return err
+	}
+	if rules == nil {
+		rules = []Rule{}
+	}
+	if err := utils.WriteJSONFile(a.path, rules); err != nil {
+		return err
+	}
+
+	a.rules.Store(&ruleSet{rules: slices.Clone(rules), compiled: compiled})
+	a.log.Info("Saved rate adjustments", "path", a.path, "rules", len(compiled))
+	return nil
+}
+
+// Validate checks rules as Save would, without saving them
+func Validate(rules []Rule) error {
+	_, err := compileRules(rules)
+	return err
+}
+
+func compileRules(rules []Rule) (map[ruleKey]*Expression, error) {
+	compiled := make(map[ruleKey]*Expression, len(rules))
+	for i, rule := range rules {
+		key := ruleKey{pair: rule.Pair, tenant: rule.Tenant}
+		if rule.Pair != anyPair {
+			pair, err := model.ParseCurrencyPair(rule.Pair)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			key.pair = pair.String()
+		}
+		if _, exists := compiled[key]; exists {
+			return nil, fmt.Errorf("rule %d: duplicate rule for pair %s and tenant %q", i+1, rule.Pair, rule.Tenant)
+		}
+
+		expression, err := Compile(rule.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		compiled[key] = expression
+	}
+	return compiled, nil
+}
+
+// Adjust returns rate after the rule matching tenant and pair. A result that
+// is not a positive finite number is discarded and rate is returned as is.
+func (a *Adjuster) Adjust(tenant string, pair model.CurrencyPair, rate float64) float64 {
+	expression := a.match(tenant, pair)
+	if expression == nil {
+		return rate
+	}
+
+	adjusted := expression.Eval(rate)
+	if math.IsNaN(adjusted) || math.IsInf(adjusted, 0) || adjusted <= 0 {
+		a.log.Error("Ignoring invalid rate adjustment", "pair", pair.String(), "expr", expression.String(), "rate", rate, "result", adjusted)
+		return rate
+	}
+	return adjusted
+}
+
+func (a *Adjuster) match(tenant string, pair model.CurrencyPair) *Expression {
+	rules := a.rules.Load().compiled
+	if len(rules) == 0 {
+		return nil
+	}
+
+	candidates := []ruleKey{{pair.String(), tenant}, {anyPair, tenant}, {pair.String(), ""}, {anyPair, ""}}
+	for _, key := range candidates {
+		if expression, found := rules[key]; found {
+			return expression
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package adjust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestAdjusterPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adjustments.json")
+	rules := `[
+		{"pair": "*", "expr": "rate + 1"},
+		{"pair": "usd-inr", "expr": "rate + 2"},
+		{"pair": "*", "tenant": "acme", "expr": "rate + 3"},
+		{"pair": "USD-INR", "tenant": "acme", "expr": "rate + 4"},
+		{"pair": "EUR-USD", "expr": "rate - rate"}
+	]`
+	if err := os.WriteFile(path, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewAdjuster(path, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatalf("Failed to load rules: %v", err)
+	}
+
+	usdINR := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	usdJPY := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.JPY}
+	checks := []struct {
+		tenant   string
+		pair     model.CurrencyPair
+		expected float64
+	}{
+		{"", usdJPY, 11},
+		{"", usdINR, 12},
+		{"other", usdINR, 12},
+		{"acme", usdJPY, 13},
+		{"acme", usdINR, 14},
+		// A non-positive result is discarded
+		{"", model.CurrencyPair{BaseCurrency: model.EUR, TargetCurrency: model.USD}, 10},
+	}
+	for _, check := range checks {
+		if got := a.Adjust(check.tenant, check.pair, 10); got != check.expected {
+			t.Errorf("Adjust(%q, %s) = %v, want %v", check.tenant, check.pair, got, check.expected)
+		}
+	}
+
+	// An invalid edit keeps the previous rules
+	if err := os.WriteFile(path, []byte(`[{"pair": "*", "expr": "rate *"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Reload(); err == nil {
+		t.Error("Expected the invalid rules to be rejected")
+	}
+	if got := a.Adjust("", usdINR, 10); got != 12 {
+		t.Errorf("Expected the previous rules after a failed reload, got %v", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"pair": "*", "expr": "rate * 2"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Reload(); err != nil || a.Adjust("acme", usdINR, 10) != 20 {
+		t.Errorf("Expected the reloaded rules to apply, got %v", err)
+	}
+}
+
+func TestAdjusterSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "adjustments.json")
+	log := logger.NewLogger("error")
+	a, err := NewAdjuster(path, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Save([]Rule{{Pair: "*", Expr: "rate *"}}); err == nil {
+		t.Error("Expected invalid rules to be refused")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected refused rules not to be written, got %v", err)
+	}
+
+	rules := []Rule{{Pair: "USD-INR", Expr: "rate * 2"}}
+	if err := a.Save(rules); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	usdINR := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	if got := a.Adjust("", usdINR, 10); got != 20 {
+		t.Errorf("Expected the saved rules in place, got %v", got)
+	}
+
+	reloaded, err := NewAdjuster(path, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Rules(); len(got) != 1 || got[0] != rules[0] {
+		t.Errorf("Expected the saved rules in the file, got %+v", got)
+	}
+}
@@ -0,0 +1,188 @@
+package anomaly
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+const (
+	// minSamples is the number of returns a pair needs before its moves are scored
+	minSamples = 10
+	// madScale makes the median absolute deviation of normally distributed
+	// returns comparable with their standard deviation
+	madScale = 0.6745
+	// meanDeviationScale does the same for the mean absolute deviation, used
+	// when more than half the returns equal the median
+	meanDeviationScale = 0.7979
+)
+
+// Detector flags published rates that moved far outside the recent behaviour
+// of their pair. Each pair keeps its last log returns; a new return is scored
+// against their median and median absolute deviation, so a single earlier
+// spike does not mask the next one the way it would inflate a standard
+// deviation. Anomalous returns join the window like any other, so a lasting
+// change of regime stops being flagged once it is the norm.
+type Detector struct {
+	threshold float64
+	window    int
+	limit     int
+	metrics   *metrics.Metrics
+	log       *logger.Logger
+	callbacks []func(anomalies []model.RateAnomaly)
+
+	mutex    sync.Mutex
+	last     map[model.CurrencyPair]float64
+	returns  map[model.CurrencyPair][]float64
+	detected []model.RateAnomaly
+	now      func() time.Time
+}
+
+func NewDetector(cfg config.AnomalyConfig, metrics *metrics.Metrics, log *logger.Logger) *Detector {
+	return &Detector{
+		threshold: cfg.Threshold,
+		window:    cfg.Window,
+		limit:     cfg.Limit,
+		metrics:   metrics,
+		log:       log,
+		last:      make(map[model.CurrencyPair]float64),
+		returns:   make(map[model.CurrencyPair][]float64),
+		now:       time.Now,
+	}
+}
+
+// OnAnomaly registers callback to receive the anomalies found in each
+// publish, such as the webhook dispatcher. It must be called before the first
+// publish.
+func (d *Detector) OnAnomaly(callback func(anomalies []model.RateAnomaly)) {
+	d.callbacks = append(d.callbacks, callback)
+}
+
+// Publish scores every rate against the recent returns of its pair
+func (d *Detector) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	d.mutex.Lock()
+	found := d.evaluate(rates)
+	d.mutex.Unlock()
+
+	if len(found) == 0 {
+		return nil
+	}
+	for _, anomaly := range found {
+		d.metrics.RateAnomalies.WithLabelValues(anomaly.Pair().String()).Inc()
+		d.log.Warn("Anomalous rate move detected",
+			"pair", anomaly.Pair().String(),
+			"previous", anomaly.PreviousRate,
+			"rate", anomaly.Rate,
+			"change_percent", anomaly.ChangePercent,
+			"score", anomaly.Score,
+		)
+	}
+	for _, callback := range d.callbacks {
+		callback(found)
+	}
+	return nil
+}
+
+// evaluate records rates and returns the anomalous moves among them. The
+// caller holds the mutex.
+func (d *Detector) evaluate(rates []model.ExchangeRate) []model.RateAnomaly {
+	now := d.now().UTC()
+	found := make([]model.RateAnomaly, 0)
+
+	for _, rate := range rates {
+		if rate.Rate <= 0 {
+			continue
+		}
+		pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+		previous, seen := d.last[pair]
+		d.last[pair] = rate.Rate
+		if !seen {
+			continue
+		}
+
+		change := math.Log(rate.Rate / previous)
+		returns := d.returns[pair]
+		if score, scored := robustScore(returns, change); scored && math.Abs(score) >= d.threshold {
+			found = append(found, model.RateAnomaly{
+				BaseCurrency:   rate.BaseCurrency,
+				TargetCurrency: rate.TargetCurrency,
+				PreviousRate:   previous,
+				Rate:           rate.Rate,
+				ChangePercent:  math.Round((rate.Rate/previous-1)*100*1e4) / 1e4,
+				Score:          math.Round(score*100) / 100,
+				DetectedAt:     now,
+			})
+		}
+
+		returns = append(returns, change)
+		if len(returns) > d.window {
+			returns = append(returns[:0], returns[len(returns)-d.window:]...)
+		}
+		d.returns[pair] = returns
+	}
+
+	d.detected = append(d.detected, found...)
+	if len(d.detected) > d.limit {
+		d.detected = append(d.detected[:0], d.detected[len(d.detected)-d.limit:]...)
+	}
+	return found
+}
+
+// Anomalies returns the anomalies kept, newest first. An empty base or target
+// matches any currency.
+func (d *Detector) Anomalies(base, target model.Currency) []model.RateAnomaly {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	anomalies := make([]model.RateAnomaly, 0)
+	for i := len(d.detected) - 1; i >= 0; i-- {
+		anomaly := d.detected[i]
+		if (base == "" || anomaly.BaseCurrency == base) && (target == "" || anomaly.TargetCurrency == target) {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	return anomalies
+}
+
+// robustScore is the modified z-score of change against returns. It reports
+// false while there are too few returns, or when they are all equal and so
+// any move would score infinitely high.
+func robustScore(returns []float64, change float64) (float64, bool) {
+	if len(returns) < minSamples {
+		return 0, false
+	}
+
+	center := median(returns)
+	deviations := make([]float64, len(returns))
+	total := 0.0
+	for i, r := range returns {
+		deviations[i] = math.Abs(r - center)
+		total += deviations[i]
+	}
+
+	if mad := median(deviations); mad > 0 {
+		return madScale * (change - center) / mad, true
+	}
+	if mean := total / float64(len(deviations)); mean > 0 {
+		return meanDeviationScale * (change - center) / mean, true
+	}
+	return 0, false
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,124 @@
+package anomaly
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestDetector(t *testing.T) (*Detector, *prometheus.Registry) {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	appMetrics := &metrics.Metrics{
+		RateAnomalies: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "rate_anomalies_total"}, []string{"pair"}),
+	}
+	registry.MustRegister(appMetrics.RateAnomalies)
+
+	d := NewDetector(config.AnomalyConfig{Threshold: 5, Window: 20, Limit: 2}, appMetrics, logger.NewLogger("error"))
+	d.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return d, registry
+}
+
+func publish(t *testing.T, d *Detector, target model.Currency, rate float64) {
+	t.Helper()
+	if err := d.Publish(context.Background(), []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: target, Rate: rate}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// wiggle publishes small irregular moves around rate
+func wiggle(t *testing.T, d *Detector, target model.Currency, rate float64, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		publish(t, d, target, rate*(1+0.002*math.Sin(float64(i)*1.7)))
+	}
+}
+
+func anomalyCount(t *testing.T, registry *prometheus.Registry, pair string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "pair" && label.GetValue() == pair {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestDetectorFlagsSpikes(t *testing.T) {
+	d, registry := newTestDetector(t)
+	var notified []model.RateAnomaly
+	d.OnAnomaly(func(anomalies []model.RateAnomaly) {
+		notified = append(notified, anomalies...)
+	})
+
+	// A jump before enough returns are known is not scored
+	publish(t, d, model.INR, 83)
+	publish(t, d, model.INR, 90)
+	if len(notified) != 0 {
+		t.Fatalf("Expected no anomaly without history, got %v", notified)
+	}
+
+	wiggle(t, d, model.INR, 83, 15)
+	if len(notified) != 0 {
+		t.Fatalf("Expected ordinary moves to pass, got %v", notified)
+	}
+
+	publish(t, d, model.INR, 88)
+	if len(notified) != 1 {
+		t.Fatalf("Expected the spike to be flagged, got %v", notified)
+	}
+	anomaly := notified[0]
+	if anomaly.TargetCurrency != model.INR || anomaly.Rate != 88 || anomaly.ChangePercent <= 5 || anomaly.Score < 5 {
+		t.Errorf("Unexpected anomaly: %+v", anomaly)
+	}
+	if count := anomalyCount(t, registry, "USD-INR"); count != 1 {
+		t.Errorf("Expected rate_anomalies_total of 1, got %v", count)
+	}
+}
+
+func TestDetectorHandlesFlatHistory(t *testing.T) {
+	d, _ := newTestDetector(t)
+
+	// Mostly unchanged quotes leave a median absolute deviation of zero
+	for i := 0; i < 12; i++ {
+		publish(t, d, model.EUR, 0.9)
+	}
+	publish(t, d, model.EUR, 0.901)
+	publish(t, d, model.EUR, 0.9)
+	if anomalies := d.Anomalies("", ""); len(anomalies) != 1 {
+		t.Fatalf("Expected a move off a flat history to be flagged, got %v", anomalies)
+	}
+}
+
+func TestDetectorKeepsNewestAnomalies(t *testing.T) {
+	d, _ := newTestDetector(t)
+
+	for _, target := range []model.Currency{model.INR, model.EUR, model.GBP} {
+		wiggle(t, d, target, 10, 12)
+		publish(t, d, target, 12)
+	}
+
+	anomalies := d.Anomalies("", "")
+	if len(anomalies) != 2 || anomalies[0].TargetCurrency != model.GBP || anomalies[1].TargetCurrency != model.EUR {
+		t.Fatalf("Expected the two newest anomalies, newest first, got %v", anomalies)
+	}
+	if filtered := d.Anomalies(model.USD, model.EUR); len(filtered) != 1 || filtered[0].TargetCurrency != model.EUR {
+		t.Errorf("Expected only USD/EUR, got %v", filtered)
+	}
+}
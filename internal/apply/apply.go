@@ -0,0 +1,384 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"exchange-rate-service/internal/adapter/auth"
+	"exchange-rate-service/internal/adjust"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+// PairPolicy holds the currency policy requests are checked against
+type PairPolicy interface {
+	CurrencyPolicy() model.CurrencyPolicy
+	SetCurrencyPolicy(policy model.CurrencyPolicy)
+}
+
+// Margins hold the rate adjustment rules
+type Margins interface {
+	Rules() []adjust.Rule
+	Save(rules []adjust.Rule) error
+}
+
+// Alerts hold the alert rules and the webhook subscriptions of every tenant
+type Alerts interface {
+	Export(ctx context.Context, includeSecrets bool) (*model.AlertDocument, error)
+	PlanImport(ctx context.Context, document model.AlertDocument, prune bool) (*model.AlertImportResult, error)
+	Import(ctx context.Context, document model.AlertDocument, prune bool) (*model.AlertImportResult, error)
+}
+
+// Keys hold the accepted API keys
+type Keys interface {
+	Keys() []string
+	SetKeys(keys []string)
+}
+
+// Resources are what an Applier manages. Any of them may be nil when its
+// feature is off; documents with its section are refused then.
+type Resources struct {
+	Pairs   PairPolicy
+	Margins Margins
+	// MarginsChanged, if any, is called once new margins are in place, e.g.
+	// to drop the rates adjusted with the previous ones
+	MarginsChanged func()
+	Alerts         Alerts
+	Keys           Keys
+}
+
+// Applier brings the runtime resources to the state a Document describes,
+// one document at a time
+type Applier struct {
+	resources Resources
+	log       *logger.Logger
+
+	mutex sync.Mutex
+}
+
+func NewApplier(resources Resources, log *logger.Logger) *Applier {
+	return &Applier{resources: resources, log: log}
+}
+
+// step applies one section of a document; undo restores what it changed,
+// even when apply failed halfway
+type step struct {
+	apply func(ctx context.Context) error
+	undo  func(ctx context.Context) error
+}
+
+// Apply diffs document against the current state and, unless dryRun, makes
+// the changes. Every section is validated before anything changes, and when
+// a section fails to apply the ones applied before it are restored, so a
+// document applies whole or not at all. Alerts and margins, which are
+// persisted, go first; pairs and keys, only kept in memory, cannot fail.
+func (a *Applier) Apply(ctx context.Context, document Document, dryRun bool) (*Plan, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	plan := &Plan{Changes: make([]Change, 0)}
+	steps := make([]step, 0, 4)
+	for _, prepare := range []func(context.Context, Document, *Plan) (*step, error){a.planAlerts, a.planMargins, a.planPairs, a.planKeys} {
+		prepared, err := prepare(ctx, document, plan)
+		if err != nil {
+			return nil, err
+		}
+		if prepared != nil {
+			steps = append(steps, *prepared)
+		}
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	for i, current := range steps {
+		if err := current.apply(ctx); err != nil {
+			for j := i; j >= 0; j-- {
+				if steps[j].undo == nil {
+					continue
+				}
+				if undoErr := steps[j].undo(ctx); undoErr != nil {
+					a.log.Error("Failed to restore configuration after a failed apply", "error", undoErr)
+				}
+			}
+			return nil, err
+		}
+	}
+
+	plan.Applied = true
+	a.log.Info("Configuration document applied",
+		"created", plan.Summary.Create,
+		"updated", plan.Summary.Update,
+		"deleted", plan.Summary.Delete,
+	)
+	return plan, nil
+}
+
+func (a *Applier) planAlerts(ctx context.Context, document Document, plan *Plan) (*step, error) {
+	if document.Alerts == nil {
+		return nil, nil
+	}
+	if a.resources.Alerts == nil {
+		return nil, fmt.Errorf("%w: alerts are not enabled", ErrInvalidDocument)
+	}
+
+	previous, err := a.resources.Alerts.Export(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	result, err := a.resources.Alerts.PlanImport(ctx, *document.Alerts, true)
+	if err != nil {
+		return nil, fmt.Errorf("alerts: %w", err)
+	}
+
+	changes := len(plan.Changes)
+	if before, after := previous.AlertRules.ThresholdPercent, result.AlertRules.ThresholdPercent; before != after {
+		plan.add(Change{
+			Resource: ResourceAlertRules,
+			ID:       "threshold_percent",
+			Action:   ActionUpdate,
+			Before:   strconv.FormatFloat(before, 'f', -1, 64),
+			After:    strconv.FormatFloat(after, 'f', -1, 64),
+		})
+	}
+
+	// Webhooks the document gives no ID get one on import, so until then
+	// their changes have none
+	declared := make(map[string]bool, len(document.Alerts.Webhooks))
+	for _, webhook := range document.Alerts.Webhooks {
+		declared[webhook.ID] = webhook.ID != ""
+	}
+	created := make([]int, 0, len(result.Created))
+	for _, subscription := range result.Created {
+		change := Change{Resource: ResourceWebhook, Action: ActionCreate, After: subscription.URL}
+		if declared[subscription.ID] {
+			change.ID = subscription.ID
+		}
+		created = append(created, len(plan.Changes))
+		plan.add(change)
+	}
+	for _, id := range result.Updated {
+		plan.add(Change{Resource: ResourceWebhook, ID: id, Action: ActionUpdate})
+	}
+	urls := make(map[string]string, len(previous.Webhooks))
+	for _, webhook := range previous.Webhooks {
+		urls[webhook.ID] = webhook.URL
+	}
+	for _, id := range result.Deleted {
+		plan.add(Change{Resource: ResourceWebhook, ID: id, Action: ActionDelete, Before: urls[id]})
+	}
+	if len(plan.Changes) == changes {
+		return nil, nil
+	}
+
+	return &step{
+		apply: func(ctx context.Context) error {
+			imported, err := a.resources.Alerts.Import(ctx, *document.Alerts, true)
+			if err != nil {
+				return err
+			}
+			for i, subscription := range imported.Created {
+				if i < len(created) {
+					plan.Changes[created[i]].ID = subscription.ID
+				}
+			}
+			return nil
+		},
+		undo: func(ctx context.Context) error {
+			_, err := a.resources.Alerts.Import(ctx, *previous, true)
+			return err
+		},
+	}, nil
+}
+
+func (a *Applier) planMargins(ctx context.Context, document Document, plan *Plan) (*step, error) {
+	if document.Margins == nil {
+		return nil, nil
+	}
+	if a.resources.Margins == nil {
+		return nil, fmt.Errorf("%w: margins are not enabled", ErrInvalidDocument)
+	}
+	if err := adjust.Validate(document.Margins); err != nil {
+		return nil, fmt.Errorf("%w: margins: %w", ErrInvalidDocument, err)
+	}
+
+	previous := a.resources.Margins.Rules()
+	before := make(map[string]string, len(previous))
+	for _, rule := range previous {
+		before[marginID(rule)] = rule.Expr
+	}
+
+	changes := len(plan.Changes)
+	desired := make(map[string]bool, len(document.Margins))
+	for _, rule := range document.Margins {
+		id := marginID(rule)
+		desired[id] = true
+		expr, exists := before[id]
+		switch {
+		case !exists:
+			plan.add(Change{Resource: ResourceMargin, ID: id, Action: ActionCreate, After: rule.Expr})
+		case expr != rule.Expr:
+			plan.add(Change{Resource: ResourceMargin, ID: id, Action: ActionUpdate, Before: expr, After: rule.Expr})
+		}
+	}
+	for _, rule := range previous {
+		if id := marginID(rule); !desired[id] {
+			plan.add(Change{Resource: ResourceMargin, ID: id, Action: ActionDelete, Before: rule.Expr})
+		}
+	}
+	if len(plan.Changes) == changes {
+		return nil, nil
+	}
+
+	save := func(rules []adjust.Rule) error {
+		if err := a.resources.Margins.Save(rules); err != nil {
+			return err
+		}
+		if a.resources.MarginsChanged != nil {
+			a.resources.MarginsChanged()
+		}
+		return nil
+	}
+	return &step{
+		apply: func(ctx context.Context) error { return save(document.Margins) },
+		undo:  func(ctx context.Context) error { return save(previous) },
+	}, nil
+}
+
+// marginID identifies the rule of a pair, or "*", and a tenant, if any, as
+// tenant/pair
+func marginID(rule adjust.Rule) string {
+	id := rule.Pair
+	if pair, err := model.ParseCurrencyPair(rule.Pair); err == nil {
+		id = pair.String()
+	}
+	if rule.Tenant != "" {
+		id = rule.Tenant + "/" + id
+	}
+	return id
+}
+
+func (a *Applier) planPairs(ctx context.Context, document Document, plan *Plan) (*step, error) {
+	if document.Pairs == nil {
+		return nil, nil
+	}
+	if a.resources.Pairs == nil {
+		return nil, fmt.Errorf("%w: pairs are not enabled", ErrInvalidDocument)
+	}
+	allow, err := parsePairs("pairs.allow", document.Pairs.Allow)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := parsePairs("pairs.deny", document.Pairs.Deny)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := a.resources.Pairs.CurrencyPolicy()
+	changes := len(plan.Changes)
+	diffPairs(plan, ResourceAllowedPair, policy.AllowedPairs, allow)
+	diffPairs(plan, ResourceDeniedPair, policy.DeniedPairs, deny)
+	if len(plan.Changes) == changes {
+		return nil, nil
+	}
+
+	policy.AllowedPairs, policy.DeniedPairs = allow, deny
+	return &step{
+		apply: func(ctx context.Context) error {
+			a.resources.Pairs.SetCurrencyPolicy(policy)
+			return nil
+		},
+	}, nil
+}
+
+func parsePairs(field string, values []string) ([]model.CurrencyPair, error) {
+	pairs := make([]model.CurrencyPair, 0, len(values))
+	seen := make(map[model.CurrencyPair]bool, len(values))
+	for _, value := range values {
+		pair, err := model.ParseCurrencyPair(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidDocument, field, err)
+		}
+		if seen[pair] {
+			return nil, fmt.Errorf("%w: %s lists %s twice", ErrInvalidDocument, field, pair)
+		}
+		seen[pair] = true
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// diffPairs adds the pairs of desired missing from current, then the pairs
+// of current missing from desired
+func diffPairs(plan *Plan, resource string, current, desired []model.CurrencyPair) {
+	existing := make(map[model.CurrencyPair]bool, len(current))
+	for _, pair := range current {
+		existing[pair] = true
+	}
+	wanted := make(map[model.CurrencyPair]bool, len(desired))
+	for _, pair := range desired {
+		wanted[pair] = true
+		if !existing[pair] {
+			plan.add(Change{Resource: resource, ID: pair.String(), Action: ActionCreate})
+		}
+	}
+	for _, pair := range current {
+		if !wanted[pair] {
+			plan.add(Change{Resource: resource, ID: pair.String(), Action: ActionDelete})
+		}
+	}
+}
+
+func (a *Applier) planKeys(ctx context.Context, document Document, plan *Plan) (*step, error) {
+	if document.Keys == nil {
+		return nil, nil
+	}
+	if a.resources.Keys == nil {
+		return nil, fmt.Errorf("%w: API keys are not enabled", ErrInvalidDocument)
+	}
+	// An empty list would lock every client out
+	if len(document.Keys) == 0 {
+		return nil, fmt.Errorf("%w: keys must not be empty, leave the section out to keep the current keys", ErrInvalidDocument)
+	}
+
+	wanted := make(map[string]bool, len(document.Keys))
+	for i, key := range document.Keys {
+		if key == "" {
+			return nil, fmt.Errorf("%w: keys[%d] is empty", ErrInvalidDocument, i)
+		}
+		if wanted[key] {
+			return nil, fmt.Errorf("%w: keys[%d] is listed twice", ErrInvalidDocument, i)
+		}
+		wanted[key] = true
+	}
+
+	current := a.resources.Keys.Keys()
+	existing := make(map[string]bool, len(current))
+	for _, key := range current {
+		existing[key] = true
+	}
+	changes := len(plan.Changes)
+	for _, key := range document.Keys {
+		if !existing[key] {
+			plan.add(Change{Resource: ResourceAPIKey, ID: auth.TenantOf(key), Action: ActionCreate})
+		}
+	}
+	for _, key := range current {
+		if !wanted[key] {
+			plan.add(Change{Resource: ResourceAPIKey, ID: auth.TenantOf(key), Action: ActionDelete})
+		}
+	}
+	if len(plan.Changes) == changes {
+		return nil, nil
+	}
+
+	keys := document.Keys
+	return &step{
+		apply: func(ctx context.Context) error {
+			a.resources.Keys.SetKeys(keys)
+			return nil
+		},
+	}, nil
+}
@@ -0,0 +1,226 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/auth"
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/adapter/webhook"
+	"exchange-rate-service/internal/adjust"
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+)
+
+type fakePolicy struct {
+	policy model.CurrencyPolicy
+}
+
+func (p *fakePolicy) CurrencyPolicy() model.CurrencyPolicy          { return p.policy }
+func (p *fakePolicy) SetCurrencyPolicy(policy model.CurrencyPolicy) { p.policy = policy }
+
+type testResources struct {
+	Resources
+	policy     *fakePolicy
+	adjuster   *adjust.Adjuster
+	dispatcher *webhook.Dispatcher
+	keys       *auth.APIKeyAuthenticator
+	dropped    int
+}
+
+func newTestResources(t *testing.T) *testResources {
+	t.Helper()
+	log := logger.NewLogger("error")
+	dir := t.TempDir()
+
+	adjuster, err := adjust.NewAdjuster(filepath.Join(dir, "adjustments.json"), log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := adjuster.Save([]adjust.Rule{{Pair: "*", Expr: "rate * 1.001"}}); err != nil {
+		t.Fatal(err)
+	}
+	webhookStore, err := store.NewFileWebhookStore(filepath.Join(dir, "webhooks.json"), 10, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &testResources{
+		policy:     &fakePolicy{policy: model.CurrencyPolicy{AllowedPairs: []model.CurrencyPair{{BaseCurrency: model.USD, TargetCurrency: model.EUR}}}},
+		adjuster:   adjuster,
+		dispatcher: webhook.NewDispatcher(webhookStore, config.WebhookConfig{Timeout: time.Second, AlertThreshold: 1}, nil, log),
+		keys:       auth.NewAPIKeyAuthenticator(config.APIKeyConfig{Keys: []string{"old-key"}}, log),
+	}
+	r.Resources = Resources{Pairs: r.policy, Margins: r.adjuster, Alerts: r.dispatcher, Keys: r.keys, MarginsChanged: func() { r.dropped++ }}
+	return r
+}
+
+func testDocument() Document {
+	return Document{
+		Pairs: &Pairs{Allow: []string{"USD-INR", "usd-eur"}, Deny: []string{"USD-JPY"}},
+		Margins: []adjust.Rule{
+			{Pair: "*", Expr: "rate * 1.002"},
+			{Pair: "usd-inr", Tenant: "acme", Expr: "rate + 1"},
+		},
+		Alerts: &model.AlertDocument{
+			AlertRules: model.AlertRules{ThresholdPercent: 2},
+			Webhooks: []model.WebhookDefinition{
+				{Tenant: "ops", URL: "https://ops.example/hooks", Events: []model.WebhookEventType{model.EventAlertTriggered}},
+			},
+		},
+		Keys: []string{"new-key"},
+	}
+}
+
+func TestApplyPlansThenApplies(t *testing.T) {
+	r := newTestResources(t)
+	applier := NewApplier(r.Resources, logger.NewLogger("error"))
+	ctx := context.Background()
+
+	plan, err := applier.Apply(ctx, testDocument(), true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	want := []Change{
+		{Resource: ResourceAlertRules, ID: "threshold_percent", Action: ActionUpdate, Before: "1", After: "2"},
+		{Resource: ResourceWebhook, Action: ActionCreate, After: "https://ops.example/hooks"},
+		{Resource: ResourceMargin, ID: "*", Action: ActionUpdate, Before: "rate * 1.001", After: "rate * 1.002"},
+		{Resource: ResourceMargin, ID: "acme/USD-INR", Action: ActionCreate, After: "rate + 1"},
+		{Resource: ResourceAllowedPair, ID: "USD-INR", Action: ActionCreate},
+		{Resource: ResourceDeniedPair, ID: "USD-JPY", Action: ActionCreate},
+		{Resource: ResourceAPIKey, ID: auth.TenantOf("new-key"), Action: ActionCreate},
+		{Resource: ResourceAPIKey, ID: auth.TenantOf("old-key"), Action: ActionDelete},
+	}
+	if plan.Applied || !slices.Equal(plan.Changes, want) {
+		t.Fatalf("Dry run plan =\n%+v\nwant\n%+v", plan.Changes, want)
+	}
+	if plan.Summary != (PlanSummary{Create: 5, Update: 2, Delete: 1}) {
+		t.Errorf("Summary = %+v", plan.Summary)
+	}
+	if !r.keys.ValidKey("old-key") || r.dispatcher.AlertRules().ThresholdPercent != 1 || len(r.adjuster.Rules()) != 1 {
+		t.Fatal("Expected a dry run to change nothing")
+	}
+
+	plan, err = applier.Apply(ctx, testDocument(), false)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !plan.Applied || plan.Changes[1].ID == "" {
+		t.Errorf("Expected an applied plan naming the created webhook, got %+v", plan)
+	}
+	if !r.keys.ValidKey("new-key") || r.keys.ValidKey("old-key") {
+		t.Error("Expected the API keys to be replaced")
+	}
+	if policy := r.policy.policy; len(policy.AllowedPairs) != 2 || len(policy.DeniedPairs) != 1 {
+		t.Errorf("Expected the pair lists to be replaced, got %+v", policy)
+	}
+	if len(r.adjuster.Rules()) != 2 || r.dropped != 1 {
+		t.Errorf("Expected the margins to be saved and adjusted rates dropped, got %+v, %d drops", r.adjuster.Rules(), r.dropped)
+	}
+	if exported, _ := r.dispatcher.Export(ctx, false); len(exported.Webhooks) != 1 || exported.AlertRules.ThresholdPercent != 2 {
+		t.Errorf("Expected the alerts to be imported, got %+v", exported)
+	}
+
+	// Applying the same document again changes nothing, except for webhooks
+	// without IDs, which are created every time
+	document := testDocument()
+	document.Alerts.Webhooks[0].ID = plan.Changes[1].ID
+	plan, err = applier.Apply(ctx, document, false)
+	if err != nil || len(plan.Changes) != 0 {
+		t.Errorf("Expected no changes on a second apply, got %+v, %v", plan, err)
+	}
+}
+
+func TestApplyRejectsInvalidDocuments(t *testing.T) {
+	tests := map[string]func(d *Document){
+		"invalid pair":   func(d *Document) { d.Pairs.Allow = []string{"USD"} },
+		"duplicate pair": func(d *Document) { d.Pairs.Deny = []string{"USD-JPY", "usd-jpy"} },
+		"invalid margin": func(d *Document) { d.Margins[0].Expr = "rate *" },
+		"no keys":        func(d *Document) { d.Keys = []string{} },
+		"invalid alerts": func(d *Document) { d.Alerts.AlertRules.ThresholdPercent = -1 },
+	}
+
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := newTestResources(t)
+			document := testDocument()
+			change(&document)
+
+			_, err := NewApplier(r.Resources, logger.NewLogger("error")).Apply(context.Background(), document, false)
+			if !errors.Is(err, ErrInvalidDocument) && !errors.Is(err, webhook.ErrInvalidAlertDocument) {
+				t.Fatalf("Expected an invalid document error, got %v", err)
+			}
+			if !r.keys.ValidKey("old-key") || r.dispatcher.AlertRules().ThresholdPercent != 1 || len(r.adjuster.Rules()) != 1 || len(r.policy.policy.DeniedPairs) != 0 {
+				t.Error("Expected a rejected document to change nothing")
+			}
+		})
+	}
+
+	r := newTestResources(t)
+	r.Resources.Margins = nil
+	if _, err := NewApplier(r.Resources, logger.NewLogger("error")).Apply(context.Background(), testDocument(), false); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("Expected margins to be refused when they are not enabled, got %v", err)
+	}
+}
+
+func TestApplyRestoresAppliedSectionsOnFailure(t *testing.T) {
+	r := newTestResources(t)
+	ctx := context.Background()
+
+	// Margins cannot be written over a directory
+	path := filepath.Join(t.TempDir(), "adjustments.json")
+	adjuster, err := adjust.NewAdjuster(path, logger.NewLogger("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	r.Resources.Margins = adjuster
+
+	if _, err := NewApplier(r.Resources, logger.NewLogger("error")).Apply(ctx, testDocument(), false); err == nil {
+		t.Fatal("Expected the apply to fail")
+	}
+
+	exported, err := r.dispatcher.Export(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Webhooks) != 0 || exported.AlertRules.ThresholdPercent != 1 {
+		t.Errorf("Expected the imported alerts to be rolled back, got %+v", exported)
+	}
+	if !r.keys.ValidKey("old-key") || len(r.policy.policy.DeniedPairs) != 0 {
+		t.Error("Expected later sections not to be applied")
+	}
+}
+
+func TestAppliedKeysSurviveConfigReload(t *testing.T) {
+	log := logger.NewLogger("error")
+	keys := auth.NewAPIKeyAuthenticator(config.APIKeyConfig{Keys: []string{"config-a", "config-b"}}, log)
+	applier := NewApplier(Resources{Keys: keys}, log)
+
+	// Apply installs a key and revokes config-b
+	if _, err := applier.Apply(context.Background(), Document{Keys: []string{"config-a", "applied"}}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The Secret then drops config-a and adds config-c
+	keys.ReloadKeys([]string{"config-b", "config-c"})
+
+	for key, valid := range map[string]bool{
+		"applied":  true,
+		"config-c": true,
+		"config-a": false,
+		"config-b": false,
+	} {
+		if keys.ValidKey(key) != valid {
+			t.Errorf("Expected %s to be valid: %v, got the keys %v", key, valid, keys.Keys())
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package apply
+
+import (
+	"errors"
+
+	"exchange-rate-service/internal/adjust"
+	"exchange-rate-service/internal/domain/model"
+)
+
+// ErrInvalidDocument is returned for a document that cannot be applied as a
+// whole. Nothing is changed then.
+var ErrInvalidDocument = errors.New("invalid configuration document")
+
+// Document is the desired state of the resources managed at runtime. A
+// section left out is not managed, and stays as it is; an empty one clears
+// what it manages. Alerts describe every webhook, so those it does not list
+// are deleted.
+type Document struct {
+	Pairs   *Pairs               `json:"pairs,omitempty" yaml:"pairs,omitempty"`
+	Margins []adjust.Rule        `json:"margins,omitempty" yaml:"margins,omitempty"`
+	Alerts  *model.AlertDocument `json:"alerts,omitempty" yaml:"alerts,omitempty"`
+	Keys    []string             `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+// Pairs are the allow and deny lists of pairs, as BASE-TARGET
+type Pairs struct {
+	Allow []string `json:"allow" yaml:"allow"`
+	Deny  []string `json:"deny" yaml:"deny"`
+}
+
+// Actions of a Change
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Resources a Change is about
+const (
+	ResourceAllowedPair = "allowed_pair"
+	ResourceDeniedPair  = "denied_pair"
+	ResourceMargin      = "margin"
+	ResourceAlertRules  = "alert_rules"
+	ResourceWebhook     = "webhook"
+	ResourceAPIKey      = "api_key"
+)
+
+// Change is one difference between a document and the current state. API
+// keys are identified by their tenant, never by themselves.
+type Change struct {
+	Resource string `json:"resource"`
+	ID       string `json:"id,omitempty"`
+	Action   string `json:"action"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+}
+
+// Plan lists the changes a document makes, in the order they are applied,
+// and whether they were
+type Plan struct {
+	Applied bool        `json:"applied"`
+	Changes []Change    `json:"changes"`
+	Summary PlanSummary `json:"summary"`
+}
+
+type PlanSummary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+func (p *Plan) add(change Change) {
+	p.Changes = append(p.Changes, change)
+	switch change.Action {
+	case ActionCreate:
+		p.Summary.Create++
+	case ActionUpdate:
+		p.Summary.Update++
+	case ActionDelete:
+		p.Summary.Delete++
+	}
+}
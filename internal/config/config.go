@@ -1,60 +1,952 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
+	settings map[string]Setting
+	LoadedAt time.Time
+
 	Server     ServerConfig
 	ExchangeAPI ExchangeAPIConfig
 	Cache      CacheConfig
+	Auth       AuthConfig
+	Conversion ConversionConfig
+	Currency   CurrencyConfig
+	Storage    StorageConfig
+	Intraday   IntradayConfig
+	FIX        FIXConfig
+	AMQP       AMQPConfig
+	MQTT       MQTTConfig
+	RESP       RESPConfig
+	Proxy      ProxyConfig
+	Webhooks   WebhookConfig
+	Delivery   DeliveryConfig
+	Chaos      ChaosConfig
+	Memory     MemoryConfig
+	Responses  ResponseConfig
+	ResponseCache ResponseCacheConfig
+	Anomalies  AnomalyConfig
+	Discrepancies DiscrepancyConfig
 }
 
 type ServerConfig struct {
-	Port         int
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	IdleTimeout  time.Duration
+	Port            int
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	TimestampFormat string
+
+	// Listen overrides Port with explicit addresses, see cmd/server/listen.go
+	Listen      []string
+	AdminListen []string
+	ReusePort   bool
+
+	// Mode is primary, the default, or replica. A replica never calls a
+	// provider: it serves the rates a primary saves to the shared cache
+	// snapshot and history files, reloading them every snapshot interval.
+	Mode string
+}
+
+// Server modes
+const (
+	ModePrimary = "primary"
+	ModeReplica = "replica"
+)
+
+// Replica reports whether the server runs as a read-only replica
+func (c ServerConfig) Replica() bool {
+	return c.Mode == ModeReplica
 }
 
 type ExchangeAPIConfig struct {
+	// Provider is the primary source of rates when Providers is not given:
+	// exchangerate.host, ecb for the European Central Bank reference rates,
+	// fixer for Fixer.io, frankfurter for the keyless Frankfurter API,
+	// fixture for rates read from FixtureFile, simulation for random-walk
+	// rates, or any other kind registered with the repository
+	Provider    string
+	ECBBaseURL  string
 	BaseURL     string
 	APIKey      string
 	Timeout     time.Duration
 	RefreshRate time.Duration
+
+	// Fixer settings apply to the fixer provider, primary or fallback.
+	// FixerBase is the currency rates are asked against.
+	FixerBaseURL string
+	FixerAPIKey  string
+	FixerTimeout time.Duration
+	FixerBase    string
+
+	FrankfurterBaseURL string
+
+	// FixtureFile is the .json or .csv file the fixture provider serves
+	FixtureFile string
+
+	// Simulation drives the made-up rates of the simulation provider
+	Simulation SimulationConfig
+
+	// CryptoEnabled serves cryptocurrencies, priced by CoinGecko
+	CryptoEnabled    bool
+	CoinGeckoBaseURL string
+	CoinGeckoAPIKey  string
+
+	// Secondary is an optional second provider used for comparisons
+	SecondaryName    string
+	SecondaryBaseURL string
+	SecondaryAPIKey  string
+
+	// WebhookSecrets holds the shared secret per provider name for push webhooks
+	WebhookSecrets map[string]string
+
+	// LicensesFile holds the license terms per provider name, including the
+	// attribution historical responses must carry
+	LicensesFile string
+
+	// Endpoints and EndpointsSRV list regional endpoints of the primary
+	// provider; requests go to the healthy one with the lowest latency
+	Endpoints           []string
+	EndpointsSRV        string
+	HealthCheckPath     string
+	HealthCheckInterval time.Duration
+
+	// MaxMovePercent is the largest change of a quote a refresh may bring
+	// before it is held back; 0 disables the guardrail
+	MaxMovePercent float64
+
+	// MonthlyQuota is the number of calls the provider plan allows per 30
+	// days; refresh intervals that would exceed it are refused. 0 is unlimited
+	MonthlyQuota int
+
+	// QuotaReservePercent of MonthlyQuota is kept for refreshes and client
+	// requests: historical backfills are refused once the rest is used
+	QuotaReservePercent float64
+
+	// ResponseCacheSize bounds the historical responses kept as long as the
+	// provider's caching headers allow; 0 disables the response cache
+	ResponseCacheSize int
+
+	// FullRefreshEvery makes only every nth refresh update every pair, the
+	// others updating just the PopularPairs most requested ones, with request
+	// counts halving every PopularityHalfLife. 0 or 1 refreshes every pair
+	// every time.
+	FullRefreshEvery   int
+	PopularPairs       int
+	PopularityHalfLife time.Duration
+
+	// Providers are the sources of rates, in order: the first is the primary
+	// one, the others are failed over to when it fails. A provider failing
+	// FailoverAfter times in a row is skipped for FailoverCooldown. Each
+	// attempt is bounded by Timeout.
+	Providers        []ProviderConfig
+	FailoverAfter    int
+	FailoverCooldown time.Duration
+
+	// Breaker is the circuit breaker put around each provider when its
+	// Failures is positive
+	Breaker BreakerConfig
+
+	// Aggregation, median or weighted, asks every provider at once and
+	// combines their rates instead of failing over. ProviderWeights weigh the
+	// weighted average by provider name, 1 for any other.
+	Aggregation     string
+	ProviderWeights map[string]float64
+
+	// RateLimits pace the calls made to providers, by provider name, so a
+	// burst of cache misses cannot use up their quota
+	RateLimits map[string]RateLimitConfig
+
+	// RateLimitCooldown pauses refreshes after the provider rate limited one
+	// without a Retry-After
+	RateLimitCooldown time.Duration
+
+	// MaxRangeDays caps the dates a single range request may span, so one
+	// request cannot cost hundreds of provider calls; 0 is unlimited
+	MaxRangeDays int
+}
+
+// ProviderConfig selects the provider of the repository registry of Kind and
+// configures it. Name identifies the provider in SLA data and health; it is
+// the kind, or any name for exchangerate.host compatible APIs.
+type ProviderConfig struct {
+	Name    string
+	Kind    string
+	BaseURL string
+	APIKey  string
+	Timeout time.Duration
+	// Base is the currency Fixer.io rates are asked against
+	Base       string
+	RateLimit  RateLimitConfig
+	Simulation SimulationConfig
+}
+
+// SimulationConfig seeds the random walks of simulated rates. Quotes move by
+// VolatilityPercent a day, every Step.
+type SimulationConfig struct {
+	Seed              int
+	VolatilityPercent float64
+	Step              time.Duration
+}
+
+// BreakerConfig opens a provider's circuit after Failures calls in a row
+// fail, for OpenFor, and closes it after HalfOpenSuccesses trial calls in a
+// row succeed
+type BreakerConfig struct {
+	Failures          int
+	OpenFor           time.Duration
+	HalfOpenSuccesses int
+}
+
+// RateLimitConfig is a token bucket of Burst calls refilled at PerMinute
+// calls a minute. A zero PerMinute is no limit.
+type RateLimitConfig struct {
+	PerMinute float64
+	Burst     int
 }
 
 type CacheConfig struct {
-	TTL time.Duration
+	TTL              time.Duration
+	SnapshotFile     string
+	SnapshotInterval time.Duration
+	// EncryptionKey seals snapshot entries with AES-GCM; nil writes them in the clear
+	EncryptionKey []byte
+	// SigningKey signs snapshot entries in the clear with HMAC-SHA256, "" for none
+	SigningKey string
+	// SparklinePoints is the number of recent rates kept per pair, 0 for none
+	SparklinePoints int
+}
+
+type ConversionConfig struct {
+	ReceiptLimit int
+
+	// PrecomputeAmounts are converted for every pair on each refresh, so
+	// latest conversions of them need no rate lookup; none disables the table
+	PrecomputeAmounts []float64
+
+	// CacheTTL is how long an identical conversion is answered with the
+	// earlier result and receipt; 0 disables the result cache
+	CacheTTL time.Duration
+}
+
+type CurrencyConfig struct {
+	Matching     string
+	AllowedPairs []string
+	DeniedPairs  []string
+
+	// AliasesFile adds legacy currency codes to the built-in aliases
+	AliasesFile string
+
+	// AdjustmentsFile holds rate expressions per pair and tenant; it is
+	// reloaded whenever it changes
+	AdjustmentsFile string
+
+	// CPIFile holds consumer price indexes per currency for
+	// inflation-adjusted conversions, which are disabled without it
+	CPIFile string
+}
+
+type StorageConfig struct {
+	AnnotationsFile string
+	HistoryFile     string
+	SLAFile         string
+	SLARetention    time.Duration
+	TicksFile       string
+	TicksRetention  time.Duration
+	WebhooksFile    string
+	DeliveriesFile  string
+	// SimulationsFile holds simulated orders and the most recent
+	// SimulationExecutionLimit executions of each
+	SimulationsFile          string
+	SimulationExecutionLimit int
+	BasketsFile              string
+	// RefreshIntervalFile keeps a refresh interval set on the admin API
+	// across restarts
+	RefreshIntervalFile string
+	// PopularityFile keeps the request counts per pair that select the pairs
+	// of partial refreshes
+	PopularityFile string
+}
+
+// IntradayConfig lists the pairs sampled for intraday ticks; none disables sampling
+type IntradayConfig struct {
+	Pairs          []string
+	SampleInterval time.Duration
+}
+
+type MemoryConfig struct {
+	WatchdogEnabled bool
+	LimitBytes      uint64
+	HighWatermark   float64
+	EvictFraction   float64
+	CheckInterval   time.Duration
+}
+
+// ResponseConfig selects the transforms applied to successful JSON responses.
+// Transforms names registered transforms, applied after the field ones.
+type ResponseConfig struct {
+	StripFields  []string
+	InjectFields map[string]string
+	Transforms   []string
+	// StaleAfter is the age past which rates are reported stale in X-Cache;
+	// 0 never reports them stale
+	StaleAfter time.Duration
+}
+
+// ResponseCacheConfig enables caching GET API responses for TTL, so identical
+// requests within it are answered without reaching the service. Routes are
+// paths like /api/v1/rates; when Routes is empty every GET route is cached
+// except DisabledRoutes.
+type ResponseCacheConfig struct {
+	TTL            time.Duration
+	Routes         []string
+	DisabledRoutes []string
+}
+
+// AnomalyConfig controls the detection of abnormal rate moves. A move is
+// anomalous when its robust z-score, measured against the median and median
+// absolute deviation of the pair's last Window returns, reaches Threshold.
+type AnomalyConfig struct {
+	// Threshold is the robust z-score that flags a move, 0 to disable detection
+	Threshold float64
+	Window    int
+	// Limit is the number of detected anomalies kept for /api/v1/anomalies
+	Limit int
+}
+
+// DiscrepancyConfig controls the comparison of the rates of the providers.
+// Every check refreshes all of them, costing a call to each.
+type DiscrepancyConfig struct {
+	// Threshold is the spread between providers, in percent, that flags a
+	// pair, 0 to disable the checks
+	Threshold float64
+	Interval  time.Duration
+}
+
+// ChaosConfig enables fault injection for resilience testing in staging
+type ChaosConfig struct {
+	Enabled             bool
+	UpstreamLatency     time.Duration
+	UpstreamErrorRate   float64
+	UpstreamMalformRate float64
+	HTTPLatency         time.Duration
+	HTTPErrorRate       float64
+	// ClockSkew runs the service's clock ahead of the wall clock, or behind
+	// it when negative
+	ClockSkew time.Duration
+}
+
+type AuthConfig struct {
+	OIDC    OIDCConfig
+	APIKeys APIKeyConfig
+}
+
+// FIXConfig enables the read-only FIX 4.4 market data acceptor when Listen is set
+type FIXConfig struct {
+	Listen         string
+	SenderCompID   string
+	AllowedCompIDs []string
+
+	// MaxSubscriptions bounds the pairs one session may subscribe to; 0 is unlimited
+	MaxSubscriptions int
+	// SlowConsumerPolicy is what happens to a session that has not been sent
+	// the previous update of a pair when the next arrives: "conflate" keeps
+	// only the newest, "disconnect" closes the session
+	SlowConsumerPolicy string
+}
+
+// AMQPConfig enables publishing fresh rates to a RabbitMQ exchange when URL is
+// set. RoutingKey and the values of PairRoutes may use {base} and {target}.
+type AMQPConfig struct {
+	URL          string
+	Exchange     string
+	ExchangeType string
+	RoutingKey   string
+	// PairRoutes maps pairs like USD-INR to "[exchange/]routing-key"
+	PairRoutes     map[string]string
+	ConfirmTimeout time.Duration
+}
+
+// MQTTConfig enables publishing retained rates to an MQTT broker when URL is
+// set, on topics like {TopicPrefix}/USD/INR
+type MQTTConfig struct {
+	URL         string
+	ClientID    string
+	TopicPrefix string
+	QoS         byte
+	KeepAlive   time.Duration
+	AckTimeout  time.Duration
+}
+
+// RESPConfig enables the read-only Redis protocol interface when Listen is set
+type RESPConfig struct {
+	Listen string
+}
+
+// ProxyConfig enables /proxy/live and /proxy/historical, which pass requests
+// through to the primary provider with the service's API key
+type ProxyConfig struct {
+	Enabled       bool
+	LiveTTL       time.Duration
+	HistoricalTTL time.Duration
+	// RateLimit is the number of requests per minute and client, 0 for no limit
+	RateLimit int
+}
+
+// WebhookConfig controls the outgoing webhooks API key holders subscribe to
+type WebhookConfig struct {
+	Timeout time.Duration
+	// AlertThreshold is the rate move in percent that triggers alert.triggered, 0 for none
+	AlertThreshold float64
+	// DeliveryLogLimit is the number of deliveries kept per subscription
+	DeliveryLogLimit int
+}
+
+// DeliveryConfig is the retry schedule of failed webhook and publisher
+// deliveries: RetryBase after the first failure, doubling up to RetryMax
+type DeliveryConfig struct {
+	MaxAttempts int
+	RetryBase   time.Duration
+	RetryMax    time.Duration
+	// Retention is how long delivered and dead entries stay in the ledger
+	Retention time.Duration
+}
+
+// APIKeyConfig protects the public API with static keys. Holders of a key can
+// mint short-lived widget tokens scoped to specific pairs.
+type APIKeyConfig struct {
+	Keys              []string
+	WidgetTokenSecret string
+	WidgetTokenMaxTTL time.Duration
+	WidgetOrigins     []string
+}
+
+type OIDCConfig struct {
+	Enabled       bool
+	IssuerURL     string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	Scopes        []string
+	GroupsClaim   string
+	RoleMapping   map[string]string
+	SessionSecret string
+	SessionTTL    time.Duration
 }
 
 func LoadConfig() (*Config, error) {
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+
+	active = newSources()
+	defer func() { active = nil }()
+
 	config := &Config{
 		Server: ServerConfig{
-			Port:         getEnvInt("SERVER_PORT", 8080),
-			ReadTimeout:  getEnvDuration("SERVER_READ_TIMEOUT", 5*time.Second),
-			WriteTimeout: getEnvDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
-			IdleTimeout:  getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			Port:            getEnvInt("SERVER_PORT", 8080),
+			ReadTimeout:     getEnvDuration("SERVER_READ_TIMEOUT", 5*time.Second),
+			WriteTimeout:    getEnvDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
+			IdleTimeout:     getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			TimestampFormat: getEnvString("TIMESTAMP_FORMAT", "rfc3339"),
+			Listen:          getEnvList("SERVER_LISTEN", []string{}),
+			AdminListen:     getEnvList("ADMIN_LISTEN", []string{}),
+			ReusePort:       getEnvBool("SERVER_REUSE_PORT", false),
+			Mode:            getEnvString("MODE", ModePrimary),
 		},
 		ExchangeAPI: ExchangeAPIConfig{
+			Provider:   getEnvString("EXCHANGE_PROVIDER", "exchangerate.host"),
+			ECBBaseURL: getEnvString("ECB_BASE_URL", "https://www.ecb.europa.eu/stats/eurofxref"),
+
+			FixerBaseURL: getEnvString("FIXER_BASE_URL", "https://data.fixer.io/api"),
+			FixerAPIKey:  getEnvString("FIXER_API_KEY", ""),
+			FixerTimeout: getEnvDuration("FIXER_TIMEOUT", 10*time.Second),
+			FixerBase:    getEnvString("FIXER_BASE_CURRENCY", "EUR"),
+
+			FrankfurterBaseURL: getEnvString("FRANKFURTER_BASE_URL", "https://api.frankfurter.dev/v1"),
+
+			FixtureFile: getEnvString("FIXTURE_FILE", "fixtures/rates.json"),
+
+			Simulation: SimulationConfig{
+				Seed:              getEnvInt("SIMULATION_SEED", 1),
+				VolatilityPercent: getEnvFloat("SIMULATION_VOLATILITY_PERCENT", 0.5),
+				Step:              getEnvDuration("SIMULATION_STEP", time.Minute),
+			},
+
+			CryptoEnabled:    getEnvBool("CRYPTO_ENABLED", false),
+			CoinGeckoBaseURL: getEnvString("COINGECKO_BASE_URL", "https://api.coingecko.com/api/v3"),
+			CoinGeckoAPIKey:  getEnvString("COINGECKO_API_KEY", ""),
+
 			BaseURL:     getEnvString("EXCHANGE_API_BASE_URL", "https://api.exchangerate.host"),
 			APIKey:      getEnvString("EXCHANGE_API_KEY", ""),
 			Timeout:     getEnvDuration("EXCHANGE_API_TIMEOUT", 10*time.Second),
 			RefreshRate: getEnvDuration("EXCHANGE_API_REFRESH_RATE", 1*time.Hour),
+
+			SecondaryName:    getEnvString("SECONDARY_EXCHANGE_API_NAME", "secondary"),
+			SecondaryBaseURL: getEnvString("SECONDARY_EXCHANGE_API_BASE_URL", ""),
+			SecondaryAPIKey:  getEnvString("SECONDARY_EXCHANGE_API_KEY", ""),
+
+			WebhookSecrets: getEnvMap("PROVIDER_WEBHOOK_SECRETS", map[string]string{}),
+			LicensesFile:   getEnvString("PROVIDER_LICENSES_FILE", ""),
+
+			Endpoints:           getEnvList("EXCHANGE_API_ENDPOINTS", []string{}),
+			EndpointsSRV:        getEnvString("EXCHANGE_API_ENDPOINTS_SRV", ""),
+			HealthCheckPath:     getEnvString("EXCHANGE_API_HEALTH_PATH", "/"),
+			HealthCheckInterval: getEnvDuration("EXCHANGE_API_HEALTH_INTERVAL", 30*time.Second),
+
+			MaxMovePercent: getEnvFloat("EXCHANGE_API_MAX_MOVE_PERCENT", 10),
+
+			MonthlyQuota:        getEnvInt("EXCHANGE_API_MONTHLY_QUOTA", 0),
+			QuotaReservePercent: getEnvFloat("EXCHANGE_API_QUOTA_RESERVE_PERCENT", 0),
+			ResponseCacheSize:   getEnvInt("EXCHANGE_API_RESPONSE_CACHE_SIZE", 1000),
+
+			FullRefreshEvery:   getEnvInt("EXCHANGE_API_FULL_REFRESH_EVERY", 0),
+			PopularPairs:       getEnvInt("EXCHANGE_API_POPULAR_PAIRS", 20),
+			PopularityHalfLife: getEnvDuration("EXCHANGE_API_POPULARITY_HALF_LIFE", 24*time.Hour),
+
+			FailoverAfter:    getEnvInt("EXCHANGE_API_FAILOVER_AFTER", 2),
+			FailoverCooldown: getEnvDuration("EXCHANGE_API_FAILOVER_COOLDOWN", 1*time.Minute),
+
+			Breaker: BreakerConfig{
+				Failures:          getEnvInt("EXCHANGE_API_BREAKER_FAILURES", 0),
+				OpenFor:           getEnvDuration("EXCHANGE_API_BREAKER_OPEN_FOR", 30*time.Second),
+				HalfOpenSuccesses: getEnvInt("EXCHANGE_API_BREAKER_HALF_OPEN_SUCCESSES", 1),
+			},
+
+			Aggregation: getEnvString("PROVIDER_AGGREGATION", ""),
+
+			RateLimitCooldown: getEnvDuration("EXCHANGE_API_RATE_LIMIT_COOLDOWN", 1*time.Minute),
+			MaxRangeDays:      getEnvInt("HISTORICAL_MAX_RANGE_DAYS", 366),
 		},
 		Cache: CacheConfig{
-			TTL: getEnvDuration("CACHE_TTL", 30*time.Minute),
+			TTL:              getEnvDuration("CACHE_TTL", 30*time.Minute),
+			SnapshotFile:     getEnvString("CACHE_SNAPSHOT_FILE", ""),
+			SnapshotInterval: getEnvDuration("CACHE_SNAPSHOT_INTERVAL", 5*time.Minute),
+			SigningKey:       getEnvString("CACHE_SIGNING_KEY", ""),
+			SparklinePoints:  getEnvInt("SPARKLINE_POINTS", 240),
+		},
+		Conversion: ConversionConfig{
+			ReceiptLimit:      getEnvInt("CONVERSION_RECEIPT_LIMIT", 100000),
+			PrecomputeAmounts: getEnvFloatList("CONVERSION_PRECOMPUTE_AMOUNTS", []float64{}),
+			CacheTTL:          getEnvDuration("CONVERSION_CACHE_TTL", 0),
+		},
+		Currency: CurrencyConfig{
+			Matching:     getEnvString("CURRENCY_MATCHING", "strict"),
+			AllowedPairs: getEnvList("CURRENCY_PAIRS_ALLOW", []string{}),
+			DeniedPairs:  getEnvList("CURRENCY_PAIRS_DENY", []string{}),
+			AliasesFile:  getEnvString("CURRENCY_ALIASES_FILE", ""),
+
+			AdjustmentsFile: getEnvString("RATE_ADJUSTMENTS_FILE", ""),
+
+			CPIFile: getEnvString("CPI_FILE", ""),
+		},
+		Storage: StorageConfig{
+			AnnotationsFile: getEnvString("ANNOTATIONS_FILE", "data/annotations.json"),
+			HistoryFile:     getEnvString("HISTORY_FILE", "data/history.json"),
+			SLAFile:         getEnvString("SLA_FILE", "data/provider_sla.json"),
+			SLARetention:    getEnvDuration("SLA_RETENTION", 30*24*time.Hour),
+			TicksFile:       getEnvString("TICKS_FILE", "data/ticks.json"),
+			TicksRetention:  getEnvDuration("TICKS_RETENTION", 7*24*time.Hour),
+			WebhooksFile:    getEnvString("WEBHOOKS_FILE", "data/webhooks.json"),
+			DeliveriesFile:  getEnvString("DELIVERIES_FILE", "data/deliveries.json"),
+			SimulationsFile:          getEnvString("SIMULATIONS_FILE", "data/simulations.json"),
+			SimulationExecutionLimit: getEnvInt("SIMULATION_EXECUTION_LIMIT", 500),
+			BasketsFile:              getEnvString("BASKETS_FILE", "data/baskets.json"),
+			RefreshIntervalFile:      getEnvString("REFRESH_INTERVAL_FILE", "data/refresh_interval.json"),
+			PopularityFile:           getEnvString("POPULARITY_FILE", "data/popularity.json"),
+		},
+		Intraday: IntradayConfig{
+			Pairs:          getEnvList("INTRADAY_PAIRS", []string{}),
+			SampleInterval: getEnvDuration("INTRADAY_SAMPLE_INTERVAL", time.Minute),
 		},
+		FIX: FIXConfig{
+			Listen:         getEnvString("FIX_LISTEN", ""),
+			SenderCompID:   getEnvString("FIX_SENDER_COMP_ID", "EXRATE"),
+			AllowedCompIDs: getEnvList("FIX_ALLOWED_COMP_IDS", []string{}),
+
+			MaxSubscriptions:   getEnvInt("FIX_MAX_SUBSCRIPTIONS", 100),
+			SlowConsumerPolicy: getEnvString("FIX_SLOW_CONSUMER_POLICY", "conflate"),
+		},
+		AMQP: AMQPConfig{
+			URL:            getEnvString("AMQP_URL", ""),
+			Exchange:       getEnvString("AMQP_EXCHANGE", "exchange-rates"),
+			ExchangeType:   getEnvString("AMQP_EXCHANGE_TYPE", "topic"),
+			RoutingKey:     getEnvString("AMQP_ROUTING_KEY", "rates.{base}.{target}"),
+			PairRoutes:     getEnvMap("AMQP_PAIR_ROUTES", map[string]string{}),
+			ConfirmTimeout: getEnvDuration("AMQP_CONFIRM_TIMEOUT", 5*time.Second),
+		},
+		Proxy: ProxyConfig{
+			Enabled:       getEnvBool("PROXY_ENABLED", false),
+			LiveTTL:       getEnvDuration("PROXY_LIVE_TTL", time.Minute),
+			HistoricalTTL: getEnvDuration("PROXY_HISTORICAL_TTL", 24*time.Hour),
+			RateLimit:     getEnvInt("PROXY_RATE_LIMIT", 60),
+		},
+		Webhooks: WebhookConfig{
+			Timeout:          getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			AlertThreshold:   getEnvFloat("WEBHOOK_ALERT_THRESHOLD", 1),
+			DeliveryLogLimit: getEnvInt("WEBHOOK_DELIVERY_LOG_LIMIT", 100),
+		},
+		Delivery: DeliveryConfig{
+			MaxAttempts: getEnvInt("DELIVERY_MAX_ATTEMPTS", 8),
+			RetryBase:   getEnvDuration("DELIVERY_RETRY_BASE", 30*time.Second),
+			RetryMax:    getEnvDuration("DELIVERY_RETRY_MAX", time.Hour),
+			Retention:   getEnvDuration("DELIVERY_RETENTION", 7*24*time.Hour),
+		},
+		RESP: RESPConfig{
+			Listen: getEnvString("RESP_LISTEN", ""),
+		},
+		MQTT: MQTTConfig{
+			URL:         getEnvString("MQTT_URL", ""),
+			ClientID:    getEnvString("MQTT_CLIENT_ID", "exchange-rate-service"),
+			TopicPrefix: getEnvString("MQTT_TOPIC_PREFIX", "rates"),
+			QoS:         byte(getEnvInt("MQTT_QOS", 1)),
+			KeepAlive:   getEnvDuration("MQTT_KEEP_ALIVE", time.Minute),
+			AckTimeout:  getEnvDuration("MQTT_ACK_TIMEOUT", 5*time.Second),
+		},
+		Memory: MemoryConfig{
+			WatchdogEnabled: getEnvBool("MEMORY_WATCHDOG_ENABLED", true),
+			LimitBytes:      uint64(getEnvInt("MEMORY_LIMIT_BYTES", 0)),
+			HighWatermark:   getEnvFloat("MEMORY_HIGH_WATERMARK", 0.85),
+			EvictFraction:   getEnvFloat("MEMORY_EVICT_FRACTION", 0.25),
+			CheckInterval:   getEnvDuration("MEMORY_CHECK_INTERVAL", 15*time.Second),
+		},
+		Responses: ResponseConfig{
+			StripFields:  getEnvList("RESPONSE_STRIP_FIELDS", []string{}),
+			InjectFields: getEnvMap("RESPONSE_INJECT_FIELDS", map[string]string{}),
+			Transforms:   getEnvList("RESPONSE_TRANSFORMS", []string{}),
+			StaleAfter:   getEnvDuration("RESPONSE_STALE_AFTER", 2*time.Hour),
+		},
+		ResponseCache: ResponseCacheConfig{
+			TTL:            getEnvDuration("RESPONSE_CACHE_TTL", 0),
+			Routes:         getEnvList("RESPONSE_CACHE_ROUTES", []string{}),
+			DisabledRoutes: getEnvList("RESPONSE_CACHE_DISABLED_ROUTES", []string{}),
+		},
+		Anomalies: AnomalyConfig{
+			Threshold: getEnvFloat("ANOMALY_THRESHOLD", 5),
+			Window:    getEnvInt("ANOMALY_WINDOW", 60),
+			Limit:     getEnvInt("ANOMALY_LIMIT", 100),
+		},
+		Discrepancies: DiscrepancyConfig{
+			Threshold: getEnvFloat("DISCREPANCY_THRESHOLD_PERCENT", 0),
+			Interval:  getEnvDuration("DISCREPANCY_CHECK_INTERVAL", 15*time.Minute),
+		},
+		Chaos: ChaosConfig{
+			Enabled:             getEnvBool("CHAOS_ENABLED", false),
+			UpstreamLatency:     getEnvDuration("CHAOS_UPSTREAM_LATENCY", 0),
+			UpstreamErrorRate:   getEnvFloat("CHAOS_UPSTREAM_ERROR_RATE", 0),
+			UpstreamMalformRate: getEnvFloat("CHAOS_UPSTREAM_MALFORMED_RATE", 0),
+			HTTPLatency:         getEnvDuration("CHAOS_HTTP_LATENCY", 0),
+			HTTPErrorRate:       getEnvFloat("CHAOS_HTTP_ERROR_RATE", 0),
+			ClockSkew:           getEnvDuration("CHAOS_CLOCK_SKEW", 0),
+		},
+		Auth: AuthConfig{
+			OIDC: OIDCConfig{
+				Enabled:       getEnvBool("OIDC_ENABLED", false),
+				IssuerURL:     getEnvString("OIDC_ISSUER_URL", ""),
+				ClientID:      getEnvString("OIDC_CLIENT_ID", ""),
+				ClientSecret:  getEnvString("OIDC_CLIENT_SECRET", ""),
+				RedirectURL:   getEnvString("OIDC_REDIRECT_URL", "http://localhost:8080/auth/callback"),
+				Scopes:        getEnvList("OIDC_SCOPES", []string{"openid", "profile", "email"}),
+				GroupsClaim:   getEnvString("OIDC_GROUPS_CLAIM", "groups"),
+				RoleMapping:   getEnvMap("OIDC_ROLE_MAPPING", map[string]string{}),
+				SessionSecret: getEnvString("SESSION_SECRET", ""),
+				SessionTTL:    getEnvDuration("SESSION_TTL", 8*time.Hour),
+			},
+			APIKeys: APIKeyConfig{
+				Keys:              getEnvList("API_KEYS", []string{}),
+				WidgetTokenSecret: getEnvString("WIDGET_TOKEN_SECRET", ""),
+				WidgetTokenMaxTTL: getEnvDuration("WIDGET_TOKEN_MAX_TTL", 1*time.Hour),
+				WidgetOrigins:     getEnvList("WIDGET_ALLOWED_ORIGINS", []string{}),
+			},
+		},
+	}
+
+	switch config.Server.TimestampFormat {
+	case "rfc3339", "unix", "unix_ms", "date":
+	default:
+		return nil, fmt.Errorf("TIMESTAMP_FORMAT must be one of rfc3339, unix, unix_ms or date")
+	}
+
+	switch config.Server.Mode {
+	case ModePrimary:
+	case ModeReplica:
+		if config.Cache.SnapshotFile == "" {
+			return nil, fmt.Errorf("CACHE_SNAPSHOT_FILE must be set in replica mode, it is where the primary's rates are read from")
+		}
+		if config.Cache.SnapshotInterval <= 0 {
+			return nil, fmt.Errorf("CACHE_SNAPSHOT_INTERVAL must be positive in replica mode")
+		}
+	default:
+		return nil, fmt.Errorf("MODE must be primary or replica")
+	}
+
+	if config.Currency.Matching != "strict" && config.Currency.Matching != "lenient" {
+		return nil, fmt.Errorf("CURRENCY_MATCHING must be strict or lenient")
+	}
+
+	if len(config.Intraday.Pairs) > 0 && config.Intraday.SampleInterval <= 0 {
+		return nil, fmt.Errorf("INTRADAY_SAMPLE_INTERVAL must be positive")
+	}
+
+	if config.ExchangeAPI.FixerTimeout <= 0 {
+		return nil, fmt.Errorf("FIXER_TIMEOUT must be positive")
+	}
+
+	if len(config.ExchangeAPI.FixerBase) != 3 {
+		return nil, fmt.Errorf("FIXER_BASE_CURRENCY must be a three letter currency code")
+	}
+
+	if (len(config.ExchangeAPI.Endpoints) > 0 || config.ExchangeAPI.EndpointsSRV != "") && config.ExchangeAPI.HealthCheckInterval <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_HEALTH_INTERVAL must be positive")
+	}
+
+	if config.ExchangeAPI.MaxMovePercent < 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_MAX_MOVE_PERCENT must not be negative")
+	}
+
+	if config.ExchangeAPI.MonthlyQuota < 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_MONTHLY_QUOTA must not be negative")
+	}
+
+	if config.ExchangeAPI.QuotaReservePercent < 0 || config.ExchangeAPI.QuotaReservePercent >= 100 {
+		return nil, fmt.Errorf("EXCHANGE_API_QUOTA_RESERVE_PERCENT must be at least 0 and below 100")
+	}
+
+	if config.ExchangeAPI.ResponseCacheSize < 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_RESPONSE_CACHE_SIZE must not be negative")
+	}
+
+	if config.ExchangeAPI.FullRefreshEvery < 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_FULL_REFRESH_EVERY must not be negative")
+	}
+
+	if config.ExchangeAPI.FullRefreshEvery > 1 && config.ExchangeAPI.PopularPairs <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_POPULAR_PAIRS must be positive with partial refreshes")
+	}
+
+	if config.ExchangeAPI.FullRefreshEvery > 1 && config.ExchangeAPI.PopularityHalfLife <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_POPULARITY_HALF_LIFE must be positive with partial refreshes")
+	}
+
+	config.ExchangeAPI.RateLimits = make(map[string]RateLimitConfig)
+	for name, value := range getEnvMap("PROVIDER_RATE_LIMITS", map[string]string{}) {
+		limit, err := parseRateLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("PROVIDER_RATE_LIMITS of %s: %w", name, err)
+		}
+		config.ExchangeAPI.RateLimits[name] = limit
+	}
+
+	// PROVIDERS replaces EXCHANGE_PROVIDER and EXCHANGE_API_FALLBACKS
+	fallbackKeys := getEnvMap("EXCHANGE_API_FALLBACK_KEYS", map[string]string{})
+	entries := getEnvList("PROVIDERS", []string{})
+	if len(entries) == 0 {
+		entries = []string{config.ExchangeAPI.Provider}
+		for _, fallback := range getEnvList("EXCHANGE_API_FALLBACKS", []string{}) {
+			if !strings.Contains(fallback, "=") {
+				return nil, fmt.Errorf("EXCHANGE_API_FALLBACKS entries must be name=url, got %q", fallback)
+			}
+			entries = append(entries, fallback)
+		}
+	}
+	for _, entry := range entries {
+		provider, err := config.ExchangeAPI.providerConfig(entry, fallbackKeys)
+		if err != nil {
+			return nil, err
+		}
+		config.ExchangeAPI.Providers = append(config.ExchangeAPI.Providers, provider)
+	}
+
+	switch config.ExchangeAPI.Aggregation {
+	case "":
+	case "median", "weighted":
+		if len(config.ExchangeAPI.Providers) < 2 {
+			return nil, fmt.Errorf("PROVIDER_AGGREGATION needs at least two PROVIDERS")
+		}
+	default:
+		return nil, fmt.Errorf("PROVIDER_AGGREGATION must be median or weighted")
+	}
+
+	config.ExchangeAPI.ProviderWeights = make(map[string]float64)
+	for name, value := range getEnvMap("PROVIDER_WEIGHTS", map[string]string{}) {
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("PROVIDER_WEIGHTS of %s must be a positive number", name)
+		}
+		config.ExchangeAPI.ProviderWeights[name] = weight
+	}
+
+	if len(config.ExchangeAPI.Providers) > 1 && config.ExchangeAPI.FailoverAfter <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_FAILOVER_AFTER must be positive")
+	}
+
+	if len(config.ExchangeAPI.Providers) > 1 && config.ExchangeAPI.FailoverCooldown <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_FAILOVER_COOLDOWN must be positive")
+	}
+
+	if config.ExchangeAPI.Breaker.Failures < 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_BREAKER_FAILURES must not be negative")
+	}
+
+	if config.ExchangeAPI.Breaker.Failures > 0 && config.ExchangeAPI.Breaker.OpenFor <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_BREAKER_OPEN_FOR must be positive")
+	}
+
+	if config.ExchangeAPI.Breaker.Failures > 0 && config.ExchangeAPI.Breaker.HalfOpenSuccesses <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_BREAKER_HALF_OPEN_SUCCESSES must be positive")
+	}
+
+	if config.ExchangeAPI.RateLimitCooldown <= 0 {
+		return nil, fmt.Errorf("EXCHANGE_API_RATE_LIMIT_COOLDOWN must be positive")
+	}
+	if config.ExchangeAPI.MaxRangeDays < 0 {
+		return nil, fmt.Errorf("HISTORICAL_MAX_RANGE_DAYS must not be negative")
+	}
+
+	if config.Responses.StaleAfter < 0 {
+		return nil, fmt.Errorf("RESPONSE_STALE_AFTER must not be negative")
+	}
+
+	if config.ResponseCache.TTL < 0 {
+		return nil, fmt.Errorf("RESPONSE_CACHE_TTL must not be negative")
+	}
+
+	for _, route := range append(append([]string{}, config.ResponseCache.Routes...), config.ResponseCache.DisabledRoutes...) {
+		if !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("response cache route %q must be a path starting with /", route)
+		}
+	}
+
+	if config.Anomalies.Threshold < 0 || config.Anomalies.Window < 10 || config.Anomalies.Limit < 1 {
+		return nil, fmt.Errorf("ANOMALY_THRESHOLD must not be negative, ANOMALY_WINDOW must be at least 10 and ANOMALY_LIMIT positive")
+	}
+
+	if config.Discrepancies.Threshold < 0 || config.Discrepancies.Interval <= 0 {
+		return nil, fmt.Errorf("DISCREPANCY_THRESHOLD_PERCENT must not be negative and DISCREPANCY_CHECK_INTERVAL positive")
+	}
+
+	if config.FIX.MaxSubscriptions < 0 {
+		return nil, fmt.Errorf("FIX_MAX_SUBSCRIPTIONS must not be negative")
+	}
+
+	if config.FIX.SlowConsumerPolicy != "conflate" && config.FIX.SlowConsumerPolicy != "disconnect" {
+		return nil, fmt.Errorf("FIX_SLOW_CONSUMER_POLICY must be conflate or disconnect")
+	}
+
+	if config.AMQP.URL != "" && config.AMQP.ConfirmTimeout <= 0 {
+		return nil, fmt.Errorf("AMQP_CONFIRM_TIMEOUT must be positive")
+	}
+
+	if config.MQTT.URL != "" {
+		if config.MQTT.QoS > 1 {
+			return nil, fmt.Errorf("MQTT_QOS must be 0 or 1")
+		}
+		if config.MQTT.AckTimeout <= 0 || config.MQTT.KeepAlive < 0 || config.MQTT.KeepAlive > 18*time.Hour {
+			return nil, fmt.Errorf("MQTT_ACK_TIMEOUT must be positive and MQTT_KEEP_ALIVE at most 18h")
+		}
+	}
+
+	if config.Conversion.CacheTTL < 0 {
+		return nil, fmt.Errorf("CONVERSION_CACHE_TTL must not be negative")
+	}
+
+	for _, amount := range config.Conversion.PrecomputeAmounts {
+		if amount <= 0 {
+			return nil, fmt.Errorf("CONVERSION_PRECOMPUTE_AMOUNTS must all be positive")
+		}
+	}
+
+	if config.Cache.SparklinePoints < 0 {
+		return nil, fmt.Errorf("SPARKLINE_POINTS must not be negative")
+	}
+
+	if config.Webhooks.Timeout <= 0 || config.Webhooks.DeliveryLogLimit < 1 || config.Webhooks.AlertThreshold < 0 {
+		return nil, fmt.Errorf("WEBHOOK_TIMEOUT and WEBHOOK_DELIVERY_LOG_LIMIT must be positive and WEBHOOK_ALERT_THRESHOLD not negative")
+	}
+
+	if config.Storage.SimulationExecutionLimit < 1 {
+		return nil, fmt.Errorf("SIMULATION_EXECUTION_LIMIT must be positive")
+	}
+
+	if config.Delivery.MaxAttempts < 1 || config.Delivery.RetryBase <= 0 || config.Delivery.RetryMax < config.Delivery.RetryBase {
+		return nil, fmt.Errorf("DELIVERY_MAX_ATTEMPTS and DELIVERY_RETRY_BASE must be positive and DELIVERY_RETRY_MAX at least DELIVERY_RETRY_BASE")
+	}
+
+	for key, rate := range map[string]float64{
+		"MEMORY_HIGH_WATERMARK":         config.Memory.HighWatermark,
+		"MEMORY_EVICT_FRACTION":         config.Memory.EvictFraction,
+		"CHAOS_UPSTREAM_ERROR_RATE":     config.Chaos.UpstreamErrorRate,
+		"CHAOS_UPSTREAM_MALFORMED_RATE": config.Chaos.UpstreamMalformRate,
+		"CHAOS_HTTP_ERROR_RATE":         config.Chaos.HTTPErrorRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("%s must be between 0 and 1", key)
+		}
+	}
+
+	if config.Auth.OIDC.Enabled {
+		oidc := config.Auth.OIDC
+		if oidc.IssuerURL == "" || oidc.ClientID == "" || oidc.ClientSecret == "" {
+			return nil, fmt.Errorf("OIDC_ISSUER_URL, OIDC_CLIENT_ID and OIDC_CLIENT_SECRET are required when OIDC is enabled")
+		}
+		if len(oidc.SessionSecret) < 32 {
+			return nil, fmt.Errorf("SESSION_SECRET must be at least 32 characters when OIDC is enabled")
+		}
+	}
+
+	if secret := config.Auth.APIKeys.WidgetTokenSecret; secret != "" && len(secret) < 32 {
+		return nil, fmt.Errorf("WIDGET_TOKEN_SECRET must be at least 32 characters")
+	}
+
+	encryptionKey, err := loadEncryptionKey(getEnvString("CACHE_ENCRYPTION_KEY", ""), getEnvString("CACHE_ENCRYPTION_KEY_FILE", ""))
+	if err != nil {
+		return nil, err
 	}
+	config.Cache.EncryptionKey = encryptionKey
+
+	// The authentication tag of encrypted entries already detects tampering
+	if key := config.Cache.SigningKey; key != "" && (len(key) < 32 || encryptionKey != nil) {
+		return nil, fmt.Errorf("CACHE_SIGNING_KEY must be at least 32 characters and is not used with CACHE_ENCRYPTION_KEY")
+	}
+
+	config.settings = active.settings
+	config.LoadedAt = time.Now().UTC()
 	
 	return config, nil
 }
 
+// loadEncryptionKey decodes the base64 AES key given inline or in keyFile, such
+// as one written by a secrets agent. It returns nil when neither is set.
+func loadEncryptionKey(encoded, keyFile string) ([]byte, error) {
+	if encoded != "" && keyFile != "" {
+		return nil, fmt.Errorf("set only one of CACHE_ENCRYPTION_KEY and CACHE_ENCRYPTION_KEY_FILE")
+	}
+	if keyFile != "" {
+		content, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CACHE_ENCRYPTION_KEY_FILE: %w", err)
+		}
+		encoded = strings.TrimSpace(string(content))
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || (len(key) != 16 && len(key) != 24 && len(key) != 32) {
+		return nil, fmt.Errorf("the cache encryption key must be 16, 24 or 32 bytes, base64 encoded")
+	}
+	return key, nil
+}
+
 func getEnvString(key, defaultValue string) string {
-	value := os.Getenv(key)
+	value := lookup(key, defaultValue)
 	if value == "" {
 		return defaultValue
 	}
@@ -62,7 +954,7 @@ func getEnvString(key, defaultValue string) string {
 }
 
 func getEnvInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
+	valueStr := lookup(key, defaultValue)
 	if valueStr == "" {
 		return defaultValue
 	}
@@ -77,7 +969,7 @@ func getEnvInt(key string, defaultValue int) int {
 }
 
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	valueStr := os.Getenv(key)
+	valueStr := lookup(key, defaultValue)
 	if valueStr == "" {
 		return defaultValue
 	}
@@ -90,3 +982,157 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	
 	return value
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	valueStr := lookup(key, defaultValue)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		fmt.Printf("Warning: Invalid value for %s, using default: %g\n", key, defaultValue)
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	valueStr := lookup(key, defaultValue)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		fmt.Printf("Warning: Invalid value for %s, using default: %t\n", key, defaultValue)
+		return defaultValue
+	}
+
+	return value
+}
+
+// getEnvList parses a comma-separated list, dropping empty items
+func getEnvList(key string, defaultValue []string) []string {
+	valueStr := lookup(key, defaultValue)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	values := make([]string, 0)
+	for _, item := range strings.Split(valueStr, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
+
+// getEnvFloatList parses a comma-separated list of numbers, dropping empty items
+func getEnvFloatList(key string, defaultValue []float64) []float64 {
+	valueStr := lookup(key, defaultValue)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	values := make([]float64, 0)
+	for _, item := range strings.Split(valueStr, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			fmt.Printf("Warning: Invalid value for %s, using default: %s\n", key, formatDefault(defaultValue))
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// getEnvMap parses a comma-separated list of key:value pairs
+func getEnvMap(key string, defaultValue map[string]string) map[string]string {
+	valueStr := lookup(key, defaultValue)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	values := make(map[string]string)
+	for _, item := range strings.Split(valueStr, ",") {
+		k, v, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found || k == "" || v == "" {
+			fmt.Printf("Warning: Ignoring malformed entry %q in %s\n", item, key)
+			continue
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return values
+}
+
+// providerConfig parses a provider entry of PROVIDERS or
+// EXCHANGE_API_FALLBACKS, either a kind or name=url. The built-in kinds take
+// their settings from their own keys; any other name given with a URL is an
+// exchangerate.host compatible API. API keys in keys take precedence.
+func (c ExchangeAPIConfig) providerConfig(entry string, keys map[string]string) (ProviderConfig, error) {
+	name, baseURL, found := strings.Cut(entry, "=")
+	name, baseURL = strings.TrimSpace(name), strings.TrimSpace(baseURL)
+	if name == "" || (found && baseURL == "") {
+		return ProviderConfig{}, fmt.Errorf("provider entries must be a name or name=url, got %q", entry)
+	}
+
+	provider := ProviderConfig{Name: name, Kind: name, Timeout: c.Timeout}
+	switch name {
+	case "exchangerate.host":
+		provider.BaseURL, provider.APIKey = c.BaseURL, c.APIKey
+	case "ecb":
+		provider.BaseURL = c.ECBBaseURL
+	case "fixer":
+		provider.BaseURL, provider.APIKey = c.FixerBaseURL, c.FixerAPIKey
+		provider.Timeout, provider.Base = c.FixerTimeout, c.FixerBase
+	case "frankfurter":
+		provider.BaseURL = c.FrankfurterBaseURL
+	case "fixture":
+		provider.BaseURL = c.FixtureFile
+	case "simulation":
+		provider.Simulation = c.Simulation
+	default:
+		if found {
+			provider.Kind = "exchangerate.host"
+		}
+	}
+
+	if found {
+		provider.BaseURL = baseURL
+	}
+	if key, exists := keys[name]; exists {
+		provider.APIKey = key
+	}
+	provider.RateLimit = c.RateLimits[name]
+	return provider, nil
+}
+
+// parseRateLimit parses calls per minute, optionally followed by the burst
+// size as in 60/10. The burst defaults to 1.
+func parseRateLimit(value string) (RateLimitConfig, error) {
+	perMinute, burst, found := strings.Cut(value, "/")
+	limit := RateLimitConfig{Burst: 1}
+
+	var err error
+	limit.PerMinute, err = strconv.ParseFloat(strings.TrimSpace(perMinute), 64)
+	if err != nil || limit.PerMinute <= 0 || math.IsInf(limit.PerMinute, 0) {
+		return RateLimitConfig{}, fmt.Errorf("calls per minute must be a positive number, got %q", perMinute)
+	}
+	if found {
+		limit.Burst, err = strconv.Atoi(strings.TrimSpace(burst))
+		if err != nil || limit.Burst < 1 {
+			return RateLimitConfig{}, fmt.Errorf("burst must be a positive integer, got %q", burst)
+		}
+	}
+	return limit, nil
+}
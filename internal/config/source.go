@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	SourceEnv       = "env"
+	SourceConfigMap = "configmap"
+	SourceSecret    = "secret"
+	SourceDefault   = "default"
+)
+
+// Setting is one effective configuration value and where it came from
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Path   string `json:"path,omitempty"`
+
+	// PendingRestart marks values that changed on disk but are not applied yet
+	PendingRestart bool `json:"pending_restart,omitempty"`
+}
+
+// sources resolves settings from the environment, then a mounted Secret
+// directory, then a mounted ConfigMap directory. Mounted directories hold one
+// file per setting named after the environment variable, as Kubernetes
+// projects them.
+type sources struct {
+	configDir string
+	secretDir string
+	settings  map[string]Setting
+}
+
+var (
+	// loadMutex serializes LoadConfig, which resolves settings through active
+	loadMutex sync.Mutex
+	active    *sources
+)
+
+func newSources() *sources {
+	return &sources{
+		configDir: os.Getenv("CONFIG_DIR"),
+		secretDir: os.Getenv("SECRETS_DIR"),
+		settings:  make(map[string]Setting),
+	}
+}
+
+// lookup returns the raw value of key, or "" when it is not set anywhere, and
+// records where the effective value came from
+func lookup(key string, defaultValue interface{}) string {
+	if active == nil {
+		return os.Getenv(key)
+	}
+	return active.lookup(key, defaultValue)
+}
+
+func (s *sources) lookup(key string, defaultValue interface{}) string {
+	if value := os.Getenv(key); value != "" {
+		s.settings[key] = Setting{Key: key, Value: value, Source: SourceEnv}
+		return value
+	}
+
+	for _, dir := range []struct{ path, source string }{{s.secretDir, SourceSecret}, {s.configDir, SourceConfigMap}} {
+		if dir.path == "" {
+			continue
+		}
+		path := filepath.Join(dir.path, key)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if value := strings.TrimSpace(string(content)); value != "" {
+			s.settings[key] = Setting{Key: key, Value: value, Source: dir.source, Path: path}
+			return value
+		}
+	}
+
+	s.settings[key] = Setting{Key: key, Value: formatDefault(defaultValue), Source: SourceDefault}
+	return ""
+}
+
+func formatDefault(v interface{}) string {
+	switch typed := v.(type) {
+	case []string:
+		return strings.Join(typed, ",")
+	case []float64:
+		values := make([]string, 0, len(typed))
+		for _, value := range typed {
+			values = append(values, strconv.FormatFloat(value, 'g', -1, 64))
+		}
+		return strings.Join(values, ",")
+	case map[string]string:
+		pairs := make([]string, 0, len(typed))
+		for k, v := range typed {
+			pairs = append(pairs, k+":"+v)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Dirs returns the mounted configuration directories in use
+func (c *Config) Dirs() []string {
+	dirs := make([]string, 0, 2)
+	for _, dir := range []string{os.Getenv("CONFIG_DIR"), os.Getenv("SECRETS_DIR")} {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// RedactedSettings returns every effective setting sorted by key, with the
+// values of secrets replaced
+func (c *Config) RedactedSettings() []Setting {
+	result := make([]Setting, 0, len(c.settings))
+	for _, setting := range c.settings {
+		if setting.Value != "" && isSecret(setting.Key) {
+			setting.Value = "[redacted]"
+		} else if u, err := url.Parse(setting.Value); err == nil && u.User != nil {
+			// Broker URLs like AMQP_URL carry their password inline
+			setting.Value = u.Redacted()
+		}
+		result = append(result, setting)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Setting returns the effective raw value of key
+func (c *Config) Setting(key string) string {
+	return c.settings[key].Value
+}
+
+// MarkPendingRestart flags settings whose new value only applies after a restart
+func (c *Config) MarkPendingRestart(keys []string) {
+	for _, key := range keys {
+		if setting, exists := c.settings[key]; exists {
+			setting.PendingRestart = true
+			c.settings[key] = setting
+		}
+	}
+}
+
+// ChangedSettings returns the keys whose effective value differs between c and other
+func (c *Config) ChangedSettings(other *Config) []string {
+	changed := make([]string, 0)
+	for key, setting := range c.settings {
+		if other.settings[key].Value != setting.Value {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func isSecret(key string) bool {
+	for _, marker := range []string{"SECRET", "KEY", "TOKEN", "PASSWORD"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
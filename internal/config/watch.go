@@ -0,0 +1,30 @@
+package config
+
+import (
+	"context"
+	"time"
+)
+
+const debounceDelay = 500 * time.Millisecond
+
+// debounce calls onChange once events have been quiet for debounceDelay. It
+// returns when ctx is done, calling stop to shut the event source down.
+func debounce(ctx context.Context, events <-chan struct{}, stop func() error, onChange func()) error {
+	timer := time.NewTimer(debounceDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+			timer.Reset(debounceDelay)
+		case <-timer.C:
+			onChange()
+		case <-ctx.Done():
+			stop()
+			return nil
+		}
+	}
+}
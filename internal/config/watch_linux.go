@@ -0,0 +1,49 @@
+//go:build linux
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Watch calls onChange whenever a file in dirs changes, until ctx is done.
+// Kubernetes updates mounted ConfigMaps and Secrets by swapping a symlink, so
+// the directories are watched rather than the files. Bursts of events are
+// coalesced into one call.
+func Watch(ctx context.Context, dirs []string, onChange func()) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("inotify init: %w", err)
+	}
+	// A non-blocking descriptor makes the file pollable, so Close interrupts Read
+	file := os.NewFile(uintptr(fd), "inotify")
+	defer file.Close()
+
+	mask := uint32(unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_MOVED_TO | unix.IN_CLOSE_WRITE | unix.IN_ATTRIB)
+	for _, dir := range dirs {
+		if _, err := unix.InotifyAddWatch(fd, dir, mask); err != nil {
+			return fmt.Errorf("watch %s: %w", dir, err)
+		}
+	}
+
+	events := make(chan struct{}, 1)
+	go func() {
+		buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+		for {
+			if _, err := file.Read(buf); err != nil {
+				close(events)
+				return
+			}
+			select {
+			case events <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return debounce(ctx, events, file.Close, onChange)
+}
@@ -0,0 +1,57 @@
+//go:build !linux
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const pollInterval = 5 * time.Second
+
+// Watch calls onChange whenever a file in dirs changes, until ctx is done.
+// Without inotify the directories are polled.
+func Watch(ctx context.Context, dirs []string, onChange func()) error {
+	events := make(chan struct{}, 1)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		last := fingerprint(dirs)
+		for {
+			select {
+			case <-ticker.C:
+				if current := fingerprint(dirs); current != last {
+					last = current
+					select {
+					case events <- struct{}{}:
+					default:
+					}
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return debounce(ctx, events, func() error { close(stop); return nil }, onChange)
+}
+
+func fingerprint(dirs []string) string {
+	result := ""
+	for _, dir := range dirs {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if info, err := os.Stat(filepath.Join(dir, entry.Name())); err == nil {
+				result += fmt.Sprintf("%s/%s:%d:%d;", dir, entry.Name(), info.Size(), info.ModTime().UnixNano())
+			}
+		}
+	}
+	return result
+}
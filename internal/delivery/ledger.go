@@ -0,0 +1,239 @@
+package delivery
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+var (
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrAlreadyDelivered = errors.New("delivery already succeeded")
+)
+
+// Sender sends the payload of a ledger entry to target again
+type Sender func(ctx context.Context, target string, payload []byte) error
+
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
+
+// Permanent marks err as not worth retrying, e.g. because the target is gone.
+// The delivery goes straight to dead.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Ledger records deliveries that failed and retries them with exponential
+// backoff until they succeed or run out of attempts. Senders register per
+// kind; a delivery of a kind without a sender fails its attempts.
+type Ledger struct {
+	store   ports.DeliveryStore
+	cfg     config.DeliveryConfig
+	senders map[string]Sender
+	metrics *metrics.Metrics
+	log     *logger.Logger
+
+	// attempts are serialized so a replay never races the retry loop
+	mutex sync.Mutex
+}
+
+func NewLedger(store ports.DeliveryStore, cfg config.DeliveryConfig, metrics *metrics.Metrics, log *logger.Logger) *Ledger {
+	return &Ledger{
+		store:   store,
+		cfg:     cfg,
+		senders: make(map[string]Sender),
+		metrics: metrics,
+		log:     log,
+	}
+}
+
+// Register sets the sender for deliveries of kind. Senders must be registered
+// before Run.
+func (l *Ledger) Register(kind string, send Sender) {
+	l.senders[kind] = send
+}
+
+// Failed records the first failed attempt of a delivery
+func (l *Ledger) Failed(ctx context.Context, kind, target, event string, payload []byte, cause error) {
+	now := time.Now().UTC()
+	delivery := model.Delivery{
+		ID:        "del_" + randomHex(8),
+		Kind:      kind,
+		Target:    target,
+		Event:     event,
+		Payload:   payload,
+		CreatedAt: now,
+	}
+	l.settle(ctx, &delivery, cause, now)
+}
+
+// backoff is the wait after the given number of failed attempts: the base
+// interval doubled per attempt, capped at the maximum
+func (l *Ledger) backoff(attempts int) time.Duration {
+	wait := l.cfg.RetryBase
+	for i := 1; i < attempts && wait < l.cfg.RetryMax; i++ {
+		wait *= 2
+	}
+	return min(wait, l.cfg.RetryMax)
+}
+
+// settle counts an attempt that ended with err and stores the outcome
+func (l *Ledger) settle(ctx context.Context, delivery *model.Delivery, err error, now time.Time) {
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+	delivery.NextAttemptAt = time.Time{}
+
+	var permanent permanentError
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliveryDelivered
+		delivery.LastError = ""
+	case errors.As(err, &permanent) || delivery.Attempts >= l.cfg.MaxAttempts:
+		delivery.Status = model.DeliveryDead
+		delivery.LastError = err.Error()
+	default:
+		delivery.Status = model.DeliveryRetrying
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(l.backoff(delivery.Attempts))
+	}
+
+	l.metrics.Deliveries.WithLabelValues(delivery.Kind, string(delivery.Status)).Inc()
+	if delivery.Status == model.DeliveryDead {
+		l.log.Warn("Delivery moved to dead letters", "id", delivery.ID, "kind", delivery.Kind, "target", delivery.Target, "attempts", delivery.Attempts, "error", delivery.LastError)
+	}
+
+	if err := l.store.Save(ctx, delivery); err != nil {
+		l.log.Error("Failed to save delivery", "id", delivery.ID, "error", err)
+	}
+}
+
+// Run retries due deliveries every interval until ctx is cancelled
+func (l *Ledger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.retryDue(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (l *Ledger) retryDue(ctx context.Context) {
+	due, err := l.store.Due(ctx, time.Now())
+	if err != nil {
+		l.log.Error("Failed to list due deliveries", "error", err)
+		return
+	}
+
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		l.attempt(ctx, &delivery)
+	}
+}
+
+func (l *Ledger) attempt(ctx context.Context, delivery *model.Delivery) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	send, exists := l.senders[delivery.Kind]
+	err := fmt.Errorf("no sender for %s deliveries", delivery.Kind)
+	if exists {
+		err = send(ctx, delivery.Target, delivery.Payload)
+	}
+	l.settle(ctx, delivery, err, time.Now().UTC())
+}
+
+// List returns matching deliveries without their payloads
+func (l *Ledger) List(ctx context.Context, filter model.DeliveryFilter) ([]model.Delivery, error) {
+	deliveries, err := l.store.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deliveries {
+		deliveries[i].Payload = nil
+	}
+	return deliveries, nil
+}
+
+func (l *Ledger) Get(ctx context.Context, id string) (*model.Delivery, error) {
+	delivery, found := l.store.Get(ctx, id)
+	if !found {
+		return nil, ErrDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// Replay attempts a delivery immediately, including dead ones. A dead
+// delivery that fails again stays dead.
+func (l *Ledger) Replay(ctx context.Context, id string) (*model.Delivery, error) {
+	delivery, found := l.store.Get(ctx, id)
+	if !found {
+		return nil, ErrDeliveryNotFound
+	}
+	if delivery.Status == model.DeliveryDelivered {
+		return nil, ErrAlreadyDelivered
+	}
+
+	l.log.Info("Replaying delivery", "id", id, "kind", delivery.Kind, "target", delivery.Target)
+	l.attempt(ctx, delivery)
+	return delivery, nil
+}
+
+// Wrap returns p recording its failed publishes under kind, and registers the
+// sender that publishes them again
+func (l *Ledger) Wrap(kind string, p ports.RatePublisher) ports.RatePublisher {
+	l.Register(kind, func(ctx context.Context, target string, payload []byte) error {
+		var rates []model.ExchangeRate
+		if err := json.Unmarshal(payload, &rates); err != nil {
+			return Permanent(err)
+		}
+		return p.Publish(ctx, rates)
+	})
+	return &ledgerPublisher{kind: kind, next: p, ledger: l}
+}
+
+type ledgerPublisher struct {
+	kind   string
+	next   ports.RatePublisher
+	ledger *Ledger
+}
+
+func (p *ledgerPublisher) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	err := p.next.Publish(ctx, rates)
+	if err != nil {
+		payload, encodeErr := json.Marshal(rates)
+		if encodeErr != nil {
+			return err
+		}
+		p.ledger.Failed(ctx, p.kind, p.kind, "rates", payload, err)
+	}
+	return err
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
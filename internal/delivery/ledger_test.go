@@ -0,0 +1,120 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// flakyPublisher fails the first failures publications
+func flakyPublisher(failures int) *mocks.RatePublisherMock {
+	return &mocks.RatePublisherMock{
+		PublishFunc: func(ctx context.Context, rates []model.ExchangeRate) error {
+			if failures > 0 {
+				failures--
+				return errors.New("broker unavailable")
+			}
+			return nil
+		},
+	}
+}
+
+func newTestLedger(t *testing.T, maxAttempts int) *Ledger {
+	t.Helper()
+	log := logger.NewLogger("error")
+	deliveryStore, err := store.NewFileDeliveryStore(filepath.Join(t.TempDir(), "deliveries.json"), time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appMetrics := &metrics.Metrics{
+		Deliveries: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "delivery_ledger_attempts_total"}, []string{"kind", "status"}),
+	}
+	cfg := config.DeliveryConfig{MaxAttempts: maxAttempts, RetryBase: time.Second, RetryMax: 5 * time.Second}
+	return NewLedger(deliveryStore, cfg, appMetrics, log)
+}
+
+func TestBackoff(t *testing.T) {
+	l := newTestLedger(t, 10)
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, expected := range want {
+		if got := l.backoff(i + 1); got != expected {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, expected)
+		}
+	}
+}
+
+func TestLedgerRetriesPublisher(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLedger(t, 3)
+	publisher := flakyPublisher(3)
+	wrapped := l.Wrap("amqp", publisher)
+
+	rates := []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83.12}}
+	if err := wrapped.Publish(ctx, rates); err == nil {
+		t.Fatal("Expected the failed publish to be reported")
+	}
+
+	entries, _ := l.List(ctx, model.DeliveryFilter{Kind: "amqp"})
+	if len(entries) != 1 || entries[0].Status != model.DeliveryRetrying || entries[0].Attempts != 1 {
+		t.Fatalf("Expected one retrying entry, got %+v", entries)
+	}
+	if entries[0].Payload != nil {
+		t.Error("List must not include payloads")
+	}
+
+	// Nothing is due before the backoff has passed
+	l.retryDue(ctx)
+	if entry, _ := l.Get(ctx, entries[0].ID); entry.Attempts != 1 {
+		t.Errorf("Expected no retry before the next attempt time, got %d attempts", entry.Attempts)
+	}
+
+	// Two more failures exhaust the attempts
+	for i := 0; i < 2; i++ {
+		entry, _ := l.Get(ctx, entries[0].ID)
+		entry.NextAttemptAt = time.Now().Add(-time.Second)
+		l.store.Save(ctx, entry)
+		l.retryDue(ctx)
+	}
+	entry, _ := l.Get(ctx, entries[0].ID)
+	if entry.Status != model.DeliveryDead || entry.Attempts != 3 || entry.LastError == "" {
+		t.Fatalf("Expected a dead entry after 3 attempts, got %+v", entry)
+	}
+
+	replayed, err := l.Replay(ctx, entry.ID)
+	if err != nil || replayed.Status != model.DeliveryDelivered || len(publisher.PublishCalls()) != 4 {
+		t.Fatalf("Expected the replay to deliver, got %+v, %v", replayed, err)
+	}
+	if _, err := l.Replay(ctx, entry.ID); !errors.Is(err, ErrAlreadyDelivered) {
+		t.Errorf("Expected a second replay to be refused, got %v", err)
+	}
+}
+
+func TestLedgerPermanentFailure(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLedger(t, 5)
+	l.Register("webhook", func(ctx context.Context, target string, payload []byte) error {
+		return Permanent(errors.New("subscription deleted"))
+	})
+
+	l.Failed(ctx, "webhook", "whk_1", "rate.refreshed", []byte(`{}`), errors.New("status 500"))
+	entries, _ := l.List(ctx, model.DeliveryFilter{Status: model.DeliveryRetrying})
+	if len(entries) != 1 {
+		t.Fatalf("Expected a retrying entry, got %+v", entries)
+	}
+
+	replayed, err := l.Replay(ctx, entries[0].ID)
+	if err != nil || replayed.Status != model.DeliveryDead {
+		t.Errorf("Expected a permanent failure to be dead, got %+v, %v", replayed, err)
+	}
+}
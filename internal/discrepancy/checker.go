@@ -0,0 +1,119 @@
+package discrepancy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+// Checker compares the rates of several providers and flags the pairs they
+// disagree on beyond a threshold. A provider serving stale or corrupted data
+// stands out from the others well before it shows in the rates customers get.
+type Checker struct {
+	threshold float64
+	providers []ports.RateRepository
+	metrics   *metrics.Metrics
+	log       *logger.Logger
+	now       func() time.Time
+}
+
+func NewChecker(cfg config.DiscrepancyConfig, providers []ports.RateRepository, metrics *metrics.Metrics, log *logger.Logger) *Checker {
+	return &Checker{
+		threshold: cfg.Threshold,
+		providers: providers,
+		metrics:   metrics,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// Run checks the providers every interval until ctx is done
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	c.log.Info("Provider discrepancy checker started", "providers", len(c.providers), "threshold_percent", c.threshold)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.Check(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check refreshes every provider and returns the pairs at least two of them
+// quote whose rates spread beyond the threshold. A provider failing to
+// refresh is left out of the comparison, as its rates may be old.
+func (c *Checker) Check(ctx context.Context) []model.ProviderDiscrepancy {
+	byPair := make(map[model.CurrencyPair]map[string]float64)
+	for _, provider := range c.providers {
+		if err := provider.RefreshRates(ctx); err != nil && !errors.Is(err, ports.ErrRatesUnchanged) {
+			c.log.Warn("Provider left out of the discrepancy check", "provider", provider.Name(), "error", err)
+			continue
+		}
+		for _, rate := range provider.LatestRates(ctx) {
+			if rate.Rate <= 0 {
+				continue
+			}
+			pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+			if byPair[pair] == nil {
+				byPair[pair] = make(map[string]float64)
+			}
+			byPair[pair][provider.Name()] = rate.Rate
+		}
+	}
+
+	now := c.now().UTC()
+	c.metrics.ProviderRateSpread.Reset()
+	c.metrics.ProviderRateDiscrepancy.Reset()
+	found := make([]model.ProviderDiscrepancy, 0)
+	for pair, rates := range byPair {
+		if len(rates) < 2 {
+			continue
+		}
+		spread := spreadPercent(rates)
+		c.metrics.ProviderRateSpread.WithLabelValues(pair.String()).Set(spread)
+		if spread < c.threshold {
+			continue
+		}
+
+		c.metrics.ProviderRateDiscrepancy.WithLabelValues(pair.String()).Set(1)
+		c.log.Warn("Providers disagree on a rate",
+			"pair", pair.String(),
+			"rates", rates,
+			"spread_percent", spread,
+		)
+		found = append(found, model.ProviderDiscrepancy{
+			BaseCurrency:   pair.BaseCurrency,
+			TargetCurrency: pair.TargetCurrency,
+			Rates:          rates,
+			SpreadPercent:  spread,
+			DetectedAt:     now,
+		})
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Pair().String() < found[j].Pair().String() })
+	return found
+}
+
+// spreadPercent is how far the highest of rates is above the lowest, in
+// percent of the lowest
+func spreadPercent(rates map[string]float64) float64 {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, rate := range rates {
+		low = math.Min(low, rate)
+		high = math.Max(high, rate)
+	}
+	return math.Round((high/low-1)*100*1e4) / 1e4
+}
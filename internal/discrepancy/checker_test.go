@@ -0,0 +1,79 @@
+package discrepancy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"exchange-rate-service/internal/config"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newProvider(name string, refreshErr error, rates map[model.Currency]float64) *mocks.RateRepositoryMock {
+	return &mocks.RateRepositoryMock{
+		NameFunc:         func() string { return name },
+		RefreshRatesFunc: func(ctx context.Context) error { return refreshErr },
+		LatestRatesFunc: func(ctx context.Context) []model.ExchangeRate {
+			latest := make([]model.ExchangeRate, 0, len(rates))
+			for target, rate := range rates {
+				latest = append(latest, model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: target, Rate: rate})
+			}
+			return latest
+		},
+	}
+}
+
+func newTestChecker(providers ...ports.RateRepository) (*Checker, *metrics.Metrics) {
+	appMetrics := &metrics.Metrics{
+		ProviderRateSpread:      prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "provider_rate_spread_percent"}, []string{"pair"}),
+		ProviderRateDiscrepancy: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "provider_rate_discrepancy"}, []string{"pair"}),
+	}
+	return NewChecker(config.DiscrepancyConfig{Threshold: 1}, providers, appMetrics, logger.NewLogger("error")), appMetrics
+}
+
+func TestCheckFlagsDivergingPairs(t *testing.T) {
+	checker, appMetrics := newTestChecker(
+		newProvider("ecb", nil, map[model.Currency]float64{model.EUR: 0.92, model.INR: 83}),
+		newProvider("fixer", ports.ErrRatesUnchanged, map[model.Currency]float64{model.EUR: 0.921, model.INR: 85}),
+		newProvider("frankfurter", nil, map[model.Currency]float64{model.EUR: 0.919, model.GBP: 0.79}),
+	)
+
+	found := checker.Check(context.Background())
+	if len(found) != 1 {
+		t.Fatalf("Expected only USD-INR to diverge, got %+v", found)
+	}
+	if got := found[0]; got.TargetCurrency != model.INR || got.SpreadPercent != 2.4096 || len(got.Rates) != 2 {
+		t.Errorf("Expected a 2.4096%% spread between 2 providers, got %+v", got)
+	}
+
+	if got := testutil.ToFloat64(appMetrics.ProviderRateDiscrepancy.WithLabelValues("USD-INR")); got != 1 {
+		t.Errorf("Expected USD-INR to be flagged, got %v", got)
+	}
+	if got := testutil.ToFloat64(appMetrics.ProviderRateDiscrepancy.WithLabelValues("USD-EUR")); got != 0 {
+		t.Errorf("Expected USD-EUR not to be flagged, got %v", got)
+	}
+	if got := testutil.CollectAndCount(appMetrics.ProviderRateSpread); got != 2 {
+		t.Errorf("Expected spreads of the 2 pairs quoted by several providers, got %d", got)
+	}
+}
+
+func TestCheckLeavesOutFailingProviders(t *testing.T) {
+	checker, appMetrics := newTestChecker(
+		newProvider("ecb", nil, map[model.Currency]float64{model.INR: 83}),
+		newProvider("fixer", errors.New("upstream down"), map[model.Currency]float64{model.INR: 90}),
+	)
+
+	if found := checker.Check(context.Background()); len(found) != 0 {
+		t.Errorf("Expected the stale rates of a failing provider not to be compared, got %+v", found)
+	}
+	if got := testutil.CollectAndCount(appMetrics.ProviderRateDiscrepancy); got != 0 {
+		t.Errorf("Expected no pair flagged, got %d", got)
+	}
+}
@@ -0,0 +1,85 @@
+package model
+
+import "fmt"
+
+// CurrencyAlias maps a legacy or informal code to the currency it stands for.
+// Factor is how many units of the alias make one unit of Code, e.g. 100 for
+// GBX (pence) to GBP; it is 1 for plain renames.
+type CurrencyAlias struct {
+	Code   Currency `json:"code"`
+	Factor float64  `json:"factor,omitempty"`
+}
+
+// CurrencyAliases is keyed by the alias code
+type CurrencyAliases map[Currency]CurrencyAlias
+
+// DefaultCurrencyAliases covers common informal codes and the currencies the
+// euro replaced, at their irrevocably fixed conversion rates
+var DefaultCurrencyAliases = CurrencyAliases{
+	"RMB": {Code: "CNY", Factor: 1},
+	"GBX": {Code: GBP, Factor: 100},
+	"ATS": {Code: EUR, Factor: 13.7603},
+	"BEF": {Code: EUR, Factor: 40.3399},
+	"DEM": {Code: EUR, Factor: 1.95583},
+	"ESP": {Code: EUR, Factor: 166.386},
+	"FIM": {Code: EUR, Factor: 5.94573},
+	"FRF": {Code: EUR, Factor: 6.55957},
+	"GRD": {Code: EUR, Factor: 340.750},
+	"IEP": {Code: EUR, Factor: 0.787564},
+	"ITL": {Code: EUR, Factor: 1936.27},
+	"LUF": {Code: EUR, Factor: 40.3399},
+	"NLG": {Code: EUR, Factor: 2.20371},
+	"PTE": {Code: EUR, Factor: 200.482},
+}
+
+// Validate checks that every alias is a valid code that is neither supported
+// nor itself an alias target, and that factors are positive. A zero factor
+// defaults to 1.
+func (a CurrencyAliases) Validate() error {
+	for alias, target := range a {
+		if !alias.IsValidCode() || !target.Code.IsValidCode() {
+			return fmt.Errorf("invalid currency alias %s=%s", alias, target.Code)
+		}
+		if alias.IsSupported() {
+			return fmt.Errorf("currency alias %s shadows a supported currency", alias)
+		}
+		if _, chained := a[target.Code]; chained {
+			return fmt.Errorf("currency alias %s points at alias %s", alias, target.Code)
+		}
+		if target.Factor < 0 {
+			return fmt.Errorf("currency alias %s has a negative factor", alias)
+		}
+		if target.Factor == 0 {
+			target.Factor = 1
+			a[alias] = target
+		}
+	}
+	return nil
+}
+
+// Canonical returns the currency c stands for, or c when it is not an alias
+func (a CurrencyAliases) Canonical(c Currency) Currency {
+	if alias, found := a[c]; found {
+		return alias.Code
+	}
+	return c
+}
+
+// Resolve returns the pair of canonical currencies behind from and to, and the
+// factor that turns a rate of that pair into a rate of from and to. aliased is
+// false when neither currency is an alias.
+func (a CurrencyAliases) Resolve(from, to Currency) (pair CurrencyPair, factor float64, aliased bool) {
+	pair = CurrencyPair{BaseCurrency: from, TargetCurrency: to}
+	factor = 1
+	if alias, found := a[from]; found {
+		pair.BaseCurrency = alias.Code
+		factor /= alias.Factor
+		aliased = true
+	}
+	if alias, found := a[to]; found {
+		pair.TargetCurrency = alias.Code
+		factor *= alias.Factor
+		aliased = true
+	}
+	return pair, factor, aliased
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCurrencyAliasesResolve(t *testing.T) {
+
+	testCases := []struct {
+		name           string
+		from           Currency
+		to             Currency
+		expectedPair   CurrencyPair
+		expectedFactor float64
+		expectedAlias  bool
+	}{
+		{name: "No Alias", from: USD, to: INR, expectedPair: CurrencyPair{USD, INR}, expectedFactor: 1},
+		{name: "Rename", from: USD, to: "RMB", expectedPair: CurrencyPair{USD, "CNY"}, expectedFactor: 1, expectedAlias: true},
+		{name: "Pence Target", from: USD, to: "GBX", expectedPair: CurrencyPair{USD, GBP}, expectedFactor: 100, expectedAlias: true},
+		{name: "Pence Base", from: "GBX", to: USD, expectedPair: CurrencyPair{GBP, USD}, expectedFactor: 0.01, expectedAlias: true},
+		{name: "Legacy Both", from: "DEM", to: "FRF", expectedPair: CurrencyPair{EUR, EUR}, expectedFactor: 6.55957 / 1.95583, expectedAlias: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			pair, factor, aliased := DefaultCurrencyAliases.Resolve(tc.from, tc.to)
+
+			if pair != tc.expectedPair || aliased != tc.expectedAlias {
+				t.Errorf("Expected %v (aliased %v), got %v (aliased %v)", tc.expectedPair, tc.expectedAlias, pair, aliased)
+			}
+			if math.Abs(factor-tc.expectedFactor) > 1e-12 {
+				t.Errorf("Expected factor: %v, got: %v", tc.expectedFactor, factor)
+			}
+		})
+	}
+}
+
+func TestCurrencyAliasesValidate(t *testing.T) {
+
+	if err := DefaultCurrencyAliases.Validate(); err != nil {
+		t.Fatalf("Expected the default aliases to be valid, got %v", err)
+	}
+
+	defaulted := CurrencyAliases{"XYZ": {Code: USD}}
+	if err := defaulted.Validate(); err != nil || defaulted["XYZ"].Factor != 1 {
+		t.Errorf("Expected a missing factor to default to 1, got %+v, %v", defaulted["XYZ"], err)
+	}
+
+	invalid := map[string]CurrencyAliases{
+		"Shadows Supported": {USD: {Code: EUR, Factor: 1}},
+		"Chained":           {"ABC": {Code: "XYZ", Factor: 1}, "XYZ": {Code: USD, Factor: 1}},
+		"Negative Factor":   {"ABC": {Code: USD, Factor: -1}},
+		"Invalid Code":      {"abc": {Code: USD, Factor: 1}},
+	}
+	for name, aliases := range invalid {
+		if err := aliases.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
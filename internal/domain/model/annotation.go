@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Annotation attaches context to a date, a pair, or a pair on a date.
+// Empty currencies or a zero date act as wildcards.
+type Annotation struct {
+	ID             string    `json:"id"`
+	BaseCurrency   Currency  `json:"base_currency,omitempty"`
+	TargetCurrency Currency  `json:"target_currency,omitempty"`
+	Date           time.Time `json:"date,omitzero"`
+	Text           string    `json:"text"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type AnnotationFilter struct {
+	BaseCurrency   Currency
+	TargetCurrency Currency
+	StartDate      time.Time
+	EndDate        time.Time
+}
+
+// Matches reports whether the annotation is relevant to the filter. A pair
+// annotation applies in both directions, so USD-INR notes also show for INR-USD.
+func (a Annotation) Matches(filter AnnotationFilter) bool {
+	if a.BaseCurrency != "" && filter.BaseCurrency != "" {
+		sameDirection := a.BaseCurrency == filter.BaseCurrency && a.TargetCurrency == filter.TargetCurrency
+		reversed := a.BaseCurrency == filter.TargetCurrency && a.TargetCurrency == filter.BaseCurrency
+		if !sameDirection && !reversed {
+			return false
+		}
+	}
+
+	if !a.Date.IsZero() {
+		if !filter.StartDate.IsZero() && a.Date.Before(filter.StartDate) {
+			return false
+		}
+		if !filter.EndDate.IsZero() && a.Date.After(filter.EndDate) {
+			return false
+		}
+	}
+
+	return true
+}
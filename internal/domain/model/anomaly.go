@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// RateAnomaly is a rate move far outside the recent behaviour of its pair.
+// Score is the robust z-score of the move: its distance from the median of
+// the recent returns in median absolute deviations, scaled to be comparable
+// with a standard z-score.
+type RateAnomaly struct {
+	BaseCurrency   Currency  `json:"base_currency"`
+	TargetCurrency Currency  `json:"target_currency"`
+	PreviousRate   float64   `json:"previous_rate"`
+	Rate           float64   `json:"rate"`
+	ChangePercent  float64   `json:"change_percent"`
+	Score          float64   `json:"score"`
+	DetectedAt     time.Time `json:"detected_at"`
+}
+
+func (a RateAnomaly) Pair() CurrencyPair {
+	return CurrencyPair{BaseCurrency: a.BaseCurrency, TargetCurrency: a.TargetCurrency}
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// BacktestInterval is how often a recurring conversion is made
+type BacktestInterval string
+
+const (
+	BacktestDaily   BacktestInterval = "daily"
+	BacktestWeekly  BacktestInterval = "weekly"
+	BacktestMonthly BacktestInterval = "monthly"
+)
+
+// Valid reports whether i is a known interval
+func (i BacktestInterval) Valid() bool {
+	switch i {
+	case BacktestDaily, BacktestWeekly, BacktestMonthly:
+		return true
+	}
+	return false
+}
+
+// BacktestRequest converts Amount every Interval from StartDate through
+// EndDate. Monthly conversions fall on StartDate's day of the month, or the
+// last day of shorter months.
+type BacktestRequest struct {
+	FromCurrency Currency
+	ToCurrency   Currency
+	Amount       float64
+	Interval     BacktestInterval
+	StartDate    time.Time
+	EndDate      time.Time
+}
+
+// BacktestConversion is one scheduled conversion at that day's rate. Filled
+// marks days without a published rate that reuse the previous day's.
+type BacktestConversion struct {
+	Date   time.Time `json:"date"`
+	Rate   float64   `json:"rate"`
+	Amount float64   `json:"amount"`
+	Filled bool      `json:"filled,omitempty"`
+}
+
+// BacktestLumpSum is the alternative of converting everything on the first
+// scheduled day that has a rate
+type BacktestLumpSum struct {
+	Date   time.Time `json:"date"`
+	Rate   float64   `json:"rate"`
+	Amount float64   `json:"amount"`
+}
+
+// Backtest compares a recurring conversion schedule with a lump sum of the
+// same total. Difference is the scheduled total minus the lump sum.
+type Backtest struct {
+	FromCurrency Currency             `json:"from_currency"`
+	ToCurrency   Currency             `json:"to_currency"`
+	Amount       float64              `json:"amount"`
+	Interval     BacktestInterval     `json:"interval"`
+	StartDate    time.Time            `json:"start_date"`
+	EndDate      time.Time            `json:"end_date"`
+	Conversions  []BacktestConversion `json:"conversions"`
+	// MissingDates are scheduled days without a rate on or before them; they are left out of the totals
+	MissingDates      []string         `json:"missing_dates,omitempty"`
+	TotalInvested     float64          `json:"total_invested"`
+	Total             float64          `json:"total"`
+	AverageRate       float64          `json:"average_rate"`
+	LumpSum           *BacktestLumpSum `json:"lump_sum,omitempty"`
+	Difference        float64          `json:"difference"`
+	DifferencePercent float64          `json:"difference_percent"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// BasketComponent is one currency of a basket. Weight is its share of the
+// basket's value when the basket was created; Units is the fixed amount of
+// the currency that share bought at the time, which is what the basket holds
+// from then on.
+type BasketComponent struct {
+	Currency Currency `json:"currency"`
+	Weight   float64  `json:"weight"`
+	Units    float64  `json:"units"`
+}
+
+// Basket is a user-defined composite currency. One unit of the basket was
+// worth one unit of Base when it was created.
+type Basket struct {
+	ID         string            `json:"id"`
+	Tenant     string            `json:"tenant"`
+	Name       string            `json:"name"`
+	Base       Currency          `json:"base"`
+	Components []BasketComponent `json:"components"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// BasketComponentValue is what one component of a basket is worth in the
+// quote currency
+type BasketComponentValue struct {
+	Currency Currency `json:"currency"`
+	Units    float64  `json:"units"`
+	Rate     float64  `json:"rate"`
+	Value    float64  `json:"value"`
+}
+
+// BasketQuote is the value of one unit of a basket in Currency
+type BasketQuote struct {
+	BasketID   string                 `json:"basket_id"`
+	Name       string                 `json:"name"`
+	Currency   Currency               `json:"currency"`
+	Value      float64                `json:"value"`
+	Components []BasketComponentValue `json:"components"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// BasketPoint is the value of a basket in the chart currency on Date
+type BasketPoint struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+}
+
+// BasketChart is the daily value of a basket in Currency. Days on which a
+// component had no rate are listed in MissingDates rather than charted.
+type BasketChart struct {
+	BasketID     string        `json:"basket_id"`
+	Name         string        `json:"name"`
+	Currency     Currency      `json:"currency"`
+	StartDate    time.Time     `json:"start_date"`
+	EndDate      time.Time     `json:"end_date"`
+	Points       []BasketPoint `json:"points"`
+	MissingDates []string      `json:"missing_dates,omitempty"`
+}
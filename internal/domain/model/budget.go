@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+type TravelBudgetRequest struct {
+	HomeCurrency        Currency
+	DestinationCurrency Currency
+	DailyAmount         float64
+	StartDate           time.Time
+	EndDate             time.Time
+}
+
+// TravelBudgetDay is the daily budget converted at that day's rate. Filled
+// marks days without a published rate, such as weekends, that reuse the
+// previous day's rate.
+type TravelBudgetDay struct {
+	Date   time.Time `json:"date"`
+	Rate   float64   `json:"rate"`
+	Amount float64   `json:"amount"`
+	Filled bool      `json:"filled,omitempty"`
+}
+
+type TravelBudget struct {
+	HomeCurrency        Currency          `json:"home_currency"`
+	DestinationCurrency Currency          `json:"destination_currency"`
+	DailyAmount         float64           `json:"daily_amount"`
+	StartDate           time.Time         `json:"start_date"`
+	EndDate             time.Time         `json:"end_date"`
+	Days                []TravelBudgetDay `json:"days"`
+	// MissingDates have no rate on or before them within the range and are left out of the totals
+	MissingDates       []string `json:"missing_dates,omitempty"`
+	TotalHome          float64  `json:"total_home"`
+	Total              float64  `json:"total"`
+	AverageRate        float64  `json:"average_rate"`
+	AverageDailyAmount float64  `json:"average_daily_amount"`
+}
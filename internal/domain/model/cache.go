@@ -0,0 +1,36 @@
+package model
+
+// Eviction reasons reported in CacheStats.Evictions
+const (
+	EvictionExpired  = "expired"
+	EvictionPressure = "pressure"
+)
+
+// CacheHitRatio is the share of cache lookups that hit over a trailing window
+type CacheHitRatio struct {
+	Window string  `json:"window"`
+	Hits   int64   `json:"hits"`
+	Misses int64   `json:"misses"`
+	Ratio  float64 `json:"ratio"`
+}
+
+// CacheNamespaceStats is the share of a cache held by one namespace, such as
+// the rates adjusted for a tenant
+type CacheNamespaceStats struct {
+	Namespace      string `json:"namespace"`
+	Entries        int    `json:"entries"`
+	EstimatedBytes int64  `json:"estimated_bytes"`
+}
+
+// CacheStats describes the contents and effectiveness of a RateCache. Entries
+// and EstimatedBytes cover every namespace; hits and misses only the shared one.
+type CacheStats struct {
+	Entries        int                   `json:"entries"`
+	ExpiredEntries int                   `json:"expired_entries"`
+	EstimatedBytes int64                 `json:"estimated_bytes"`
+	Namespaces     []CacheNamespaceStats `json:"namespaces,omitempty"`
+	Hits           int64                 `json:"hits"`
+	Misses         int64                 `json:"misses"`
+	HitRatios      []CacheHitRatio       `json:"hit_ratios"`
+	Evictions      map[string]int64      `json:"evictions"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "math"
+
+// RoundingCash marks a conversion that also carries a cash-rounded amount
+const RoundingCash = "cash"
+
+// defaultCashIncrement applies to currencies without an entry in cashIncrements
+const defaultCashIncrement = 0.01
+
+// cashIncrements are the smallest amounts that can be paid in cash, where they
+// differ from the currency's minor unit or are worth stating explicitly
+var cashIncrements = map[Currency]float64{
+	USD:   0.01,
+	EUR:   0.01,
+	GBP:   0.01,
+	JPY:   1,
+	INR:   1,
+	"CHF": 0.05,
+	"CAD": 0.05,
+	"AUD": 0.05,
+	"SGD": 0.05,
+	"NZD": 0.10,
+	"HKD": 0.10,
+	"DKK": 0.50,
+	"SEK": 1,
+	"NOK": 1,
+	"CZK": 1,
+	"HUF": 5,
+	"KRW": 10,
+}
+
+// CashIncrement returns the smallest physically payable amount of c
+func (c Currency) CashIncrement() float64 {
+	if increment, found := cashIncrements[c]; found {
+		return increment
+	}
+	return defaultCashIncrement
+}
+
+// CashRounding is a converted amount rounded to what can be paid in cash
+type CashRounding struct {
+	Increment float64 `json:"increment"`
+	Amount    float64 `json:"amount"`
+	// Delta is Amount minus the exact amount
+	Delta float64 `json:"delta"`
+}
+
+// RoundToCash rounds amount to the nearest cash increment of c, halves away
+// from zero as in Swiss and Swedish rounding
+func RoundToCash(amount float64, c Currency) CashRounding {
+	increment := c.CashIncrement()
+	// Snap the quotient first so binary halves such as 12.325/0.05 = 246.4999... still round up
+	steps := math.Round(amount/increment*1e9) / 1e9
+	rounded := math.Round(steps) * increment
+
+	// Drop the float noise multiplying by the increment leaves, e.g. 12.350000000000001
+	decimals := math.Pow(10, math.Max(0, math.Ceil(-math.Log10(increment))))
+	rounded = math.Round(rounded*decimals) / decimals
+
+	return CashRounding{
+		Increment: increment,
+		Amount:    rounded,
+		Delta:     math.Round((rounded-amount)*1e9) / 1e9,
+	}
+}
@@ -0,0 +1,37 @@
+package model
+
+import "testing"
+
+func TestRoundToCash(t *testing.T) {
+
+	testCases := []struct {
+		name           string
+		amount         float64
+		currency       Currency
+		expectedAmount float64
+		expectedDelta  float64
+	}{
+		{name: "CHF Down", amount: 12.32, currency: "CHF", expectedAmount: 12.30, expectedDelta: -0.02},
+		{name: "CHF Up", amount: 12.33, currency: "CHF", expectedAmount: 12.35, expectedDelta: 0.02},
+		{name: "CHF Half", amount: 12.325, currency: "CHF", expectedAmount: 12.35, expectedDelta: 0.025},
+		{name: "JPY Integer", amount: 1499.5, currency: JPY, expectedAmount: 1500, expectedDelta: 0.5},
+		{name: "INR Integer", amount: 8312.49, currency: INR, expectedAmount: 8312, expectedDelta: -0.49},
+		{name: "DKK Half Krone", amount: 10.74, currency: "DKK", expectedAmount: 10.5, expectedDelta: -0.24},
+		{name: "USD Cent", amount: 10.004, currency: USD, expectedAmount: 10, expectedDelta: -0.004},
+		{name: "Unknown Defaults To Cent", amount: 3.456, currency: "PLN", expectedAmount: 3.46, expectedDelta: 0.004},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			cash := RoundToCash(tc.amount, tc.currency)
+
+			if cash.Amount != tc.expectedAmount {
+				t.Errorf("Expected amount: %v, got: %v", tc.expectedAmount, cash.Amount)
+			}
+			if cash.Delta != tc.expectedDelta {
+				t.Errorf("Expected delta: %v, got: %v", tc.expectedDelta, cash.Delta)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import "fmt"
+
+// QuantityUnit is the unit a commodity is quoted or traded in
+type QuantityUnit string
+
+const (
+	TroyOunce QuantityUnit = "troy_ounce"
+	Gram      QuantityUnit = "gram"
+	Kilogram  QuantityUnit = "kilogram"
+	Barrel    QuantityUnit = "barrel"
+	Liter     QuantityUnit = "liter"
+	Gallon    QuantityUnit = "gallon"
+)
+
+const (
+	gramsPerTroyOunce = 31.1034768
+	litersPerBarrel   = 158.987294928
+	litersPerGallon   = 3.785411784
+)
+
+// quantityUnits gives, for every unit, the quote unit it measures and how
+// many quote units one of it is
+var quantityUnits = map[QuantityUnit]struct {
+	quote QuantityUnit
+	size  float64
+}{
+	TroyOunce: {TroyOunce, 1},
+	Gram:      {TroyOunce, 1 / gramsPerTroyOunce},
+	Kilogram:  {TroyOunce, 1000 / gramsPerTroyOunce},
+	Barrel:    {Barrel, 1},
+	Liter:     {Barrel, 1 / litersPerBarrel},
+	Gallon:    {Barrel, litersPerGallon / litersPerBarrel},
+}
+
+// Commodity is a metal or energy product quoted like a currency, per Unit
+type Commodity struct {
+	Name string
+	Unit QuantityUnit
+}
+
+// Commodities are accepted wherever currencies are, and served when the
+// provider quotes them
+var Commodities = map[Currency]Commodity{
+	"XAU": {Name: "Gold", Unit: TroyOunce},
+	"XAG": {Name: "Silver", Unit: TroyOunce},
+	"XPT": {Name: "Platinum", Unit: TroyOunce},
+	"XPD": {Name: "Palladium", Unit: TroyOunce},
+	"WTI": {Name: "WTI Crude Oil", Unit: Barrel},
+	"BRN": {Name: "Brent Crude Oil", Unit: Barrel},
+}
+
+// IsCommodity reports whether c is one of the Commodities
+func (c Currency) IsCommodity() bool {
+	_, found := Commodities[c]
+	return found
+}
+
+// UnitSize returns how many of c's quote units one unit is, and false when
+// unit does not measure c, e.g. grams of oil or any unit of a currency
+func (c Currency) UnitSize(unit QuantityUnit) (float64, bool) {
+	commodity, found := Commodities[c]
+	if !found {
+		return 0, false
+	}
+	size, known := quantityUnits[unit]
+	if !known || size.quote != commodity.Unit {
+		return 0, false
+	}
+	return size.size, true
+}
+
+// ParseQuantityUnit validates a unit name; an empty name is no unit
+func ParseQuantityUnit(s string) (QuantityUnit, error) {
+	unit := QuantityUnit(s)
+	if _, known := quantityUnits[unit]; s != "" && !known {
+		return "", fmt.Errorf("unknown unit: %s", s)
+	}
+	return unit, nil
+}
+
+// QuoteUnit is the unit the rate of p is quoted per: that of the base when it
+// is a commodity, otherwise that of the target, or none for two currencies
+func (p CurrencyPair) QuoteUnit() QuantityUnit {
+	if commodity, found := Commodities[p.BaseCurrency]; found {
+		return commodity.Unit
+	}
+	return Commodities[p.TargetCurrency].Unit
+}
@@ -0,0 +1,30 @@
+package model
+
+// CompositeUnit is a unit of account defined as fixed amounts of other
+// currencies, such as the IMF's Special Drawing Right
+type CompositeUnit struct {
+	Name       string
+	Components map[Currency]float64
+}
+
+// CompositeUnits are served even when the provider does not quote them, by
+// valuing their components. The XDR amounts are those of the IMF basket in
+// effect since August 2022.
+var CompositeUnits = map[Currency]CompositeUnit{
+	"XDR": {
+		Name: "IMF Special Drawing Right",
+		Components: map[Currency]float64{
+			USD:   0.57813,
+			EUR:   0.37379,
+			"CNY": 1.0993,
+			JPY:   13.452,
+			GBP:   0.08087,
+		},
+	},
+}
+
+// IsComposite reports whether c is one of the CompositeUnits
+func (c Currency) IsComposite() bool {
+	_, found := CompositeUnits[c]
+	return found
+}
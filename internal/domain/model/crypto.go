@@ -0,0 +1,21 @@
+package model
+
+// Cryptocurrency is a crypto asset quoted like a currency
+type Cryptocurrency struct {
+	Name string
+}
+
+// Cryptocurrencies are served by the crypto provider, when one is configured,
+// crossed with the rates of the fiat provider. Their symbols need not be
+// shaped like ISO 4217 codes.
+var Cryptocurrencies = map[Currency]Cryptocurrency{
+	"BTC":  {Name: "Bitcoin"},
+	"ETH":  {Name: "Ether"},
+	"USDT": {Name: "Tether"},
+}
+
+// IsCrypto reports whether c is one of the Cryptocurrencies
+func (c Currency) IsCrypto() bool {
+	_, found := Cryptocurrencies[c]
+	return found
+}
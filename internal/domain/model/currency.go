@@ -12,6 +12,24 @@ const (
 
 var SupportedCurrencies = []Currency{USD, INR, EUR, JPY, GBP}
 
+var currencySymbols = map[Currency]string{
+	USD: "$",
+	INR: "₹",
+	EUR: "€",
+	JPY: "¥",
+	GBP: "£",
+}
+
+// CurrencyForSymbol returns the supported currency using symbol, if any
+func CurrencyForSymbol(symbol string) (Currency, bool) {
+	for currency, s := range currencySymbols {
+		if s == symbol {
+			return currency, true
+		}
+	}
+	return "", false
+}
+
 func (c Currency) IsSupported() bool {
 	for _, supportedCurrency := range SupportedCurrencies {
 		if c == supportedCurrency {
@@ -21,6 +39,34 @@ func (c Currency) IsSupported() bool {
 	return false
 }
 
+// IsValidCode reports whether c is shaped like an ISO 4217 code, regardless of
+// whether the service supports it
+func (c Currency) IsValidCode() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// CurrencyMatching controls how currencies outside SupportedCurrencies are treated
+type CurrencyMatching string
+
+const (
+	// CurrencyMatchingStrict rejects any currency that is not supported
+	CurrencyMatchingStrict CurrencyMatching = "strict"
+	// CurrencyMatchingLenient forwards valid but unsupported ISO codes to the provider
+	CurrencyMatchingLenient CurrencyMatching = "lenient"
+)
+
 func (c Currency) String() string {
 	return string(c)
 }
+
+func (c Currency) Symbol() string {
+	return currencySymbols[c]
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeliveryStatus is the state of a delivery in the retry ledger
+type DeliveryStatus string
+
+const (
+	// DeliveryRetrying deliveries failed and wait for NextAttemptAt
+	DeliveryRetrying DeliveryStatus = "retrying"
+	// DeliveryDelivered deliveries succeeded on a retry or a replay
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead deliveries ran out of attempts and are only retried by a replay
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// Delivery is a webhook or publisher delivery that failed at least once.
+// Kind says who sends it (webhook, amqp, mqtt) and Target whom to, e.g. the
+// webhook subscription ID.
+type Delivery struct {
+	ID            string          `json:"id"`
+	Kind          string          `json:"kind"`
+	Target        string          `json:"target"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	Status        DeliveryStatus  `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at,omitzero"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// DeliveryFilter selects ledger entries; empty fields match everything
+type DeliveryFilter struct {
+	Status DeliveryStatus
+	Kind   string
+	Target string
+}
+
+func (d Delivery) Matches(filter DeliveryFilter) bool {
+	return (filter.Status == "" || d.Status == filter.Status) &&
+		(filter.Kind == "" || d.Kind == filter.Kind) &&
+		(filter.Target == "" || d.Target == filter.Target)
+}
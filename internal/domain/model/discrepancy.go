@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ProviderDiscrepancy is a pair the providers disagree on. SpreadPercent is
+// how far the highest rate is above the lowest, in percent of the lowest.
+type ProviderDiscrepancy struct {
+	BaseCurrency   Currency `json:"base_currency"`
+	TargetCurrency Currency `json:"target_currency"`
+	// Rates are the rates of the pair by provider
+	Rates         map[string]float64 `json:"rates"`
+	SpreadPercent float64            `json:"spread_percent"`
+	DetectedAt    time.Time          `json:"detected_at"`
+}
+
+func (d ProviderDiscrepancy) Pair() CurrencyPair {
+	return CurrencyPair{BaseCurrency: d.BaseCurrency, TargetCurrency: d.TargetCurrency}
+}
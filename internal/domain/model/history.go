@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// HistoryRetentionDays is how far back historical rates are served and kept
+const HistoryRetentionDays = 90
+
+type PairCompleteness struct {
+	BaseCurrency   Currency `json:"base_currency"`
+	TargetCurrency Currency `json:"target_currency"`
+	ExpectedDays   int      `json:"expected_days"`
+	AvailableDays  int      `json:"available_days"`
+	Completeness   float64  `json:"completeness_percent"`
+	MissingDates   []string `json:"missing_dates"`
+}
+
+type HistoryGapReport struct {
+	WindowStart  time.Time          `json:"window_start"`
+	WindowEnd    time.Time          `json:"window_end"`
+	Completeness float64            `json:"completeness_percent"`
+	Pairs        []PairCompleteness `json:"pairs"`
+}
+
+type BackfillResult struct {
+	Requested int      `json:"requested"`
+	Filled    int      `json:"filled"`
+	Failed    []string `json:"failed"`
+}
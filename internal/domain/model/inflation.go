@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// InflationMethod is the order in which an inflation-adjusted conversion
+// applies its two steps
+type InflationMethod string
+
+const (
+	// InflateThenConvert restates the amount in today's money of the source
+	// currency, then converts it at the latest rate. It works for any date
+	// the CPI data covers.
+	InflateThenConvert InflationMethod = "inflate_then_convert"
+	// ConvertThenInflate converts the amount at the rate of its date, then
+	// restates it in today's money of the target currency. It is limited to
+	// dates with historical rates.
+	ConvertThenInflate InflationMethod = "convert_then_inflate"
+)
+
+// Valid reports whether m is a known method
+func (m InflationMethod) Valid() bool {
+	return m == InflateThenConvert || m == ConvertThenInflate
+}
+
+// CPIObservation is a consumer price index value of a currency's economy
+// for the period starting on Date
+type CPIObservation struct {
+	Currency Currency  `json:"currency"`
+	Date     time.Time `json:"date"`
+	Value    float64   `json:"value"`
+}
+
+type InflationRequest struct {
+	FromCurrency Currency
+	ToCurrency   Currency
+	Amount       float64
+	Date         time.Time
+	Method       InflationMethod
+}
+
+// InflationStepType labels a step of an inflation-adjusted conversion
+type InflationStepType string
+
+const (
+	InflationStepInflation  InflationStepType = "inflation"
+	InflationStepConversion InflationStepType = "conversion"
+)
+
+// InflationStep turns AmountBefore into AmountAfter by Factor. An inflation
+// step gives the CPI observations it compares; a conversion step gives the
+// rate it used.
+type InflationStep struct {
+	Type         InflationStepType `json:"type"`
+	Label        string            `json:"label"`
+	AmountBefore float64           `json:"amount_before"`
+	AmountAfter  float64           `json:"amount_after"`
+	Factor       float64           `json:"factor"`
+	StartCPI     *CPIObservation   `json:"start_cpi,omitempty"`
+	EndCPI       *CPIObservation   `json:"end_cpi,omitempty"`
+	Rate         *ExchangeRate     `json:"rate,omitempty"`
+}
+
+type InflationAdjustedConversion struct {
+	FromCurrency Currency        `json:"from_currency"`
+	ToCurrency   Currency        `json:"to_currency"`
+	Amount       float64         `json:"amount"`
+	Date         time.Time       `json:"date"`
+	Method       InflationMethod `json:"method"`
+	Steps        []InflationStep `json:"steps"`
+	Result       float64         `json:"result"`
+}
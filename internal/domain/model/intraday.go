@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tick is a single intraday observation of a pair's rate
+type Tick struct {
+	Pair CurrencyPair `json:"pair"`
+	Rate float64      `json:"rate"`
+	Time time.Time    `json:"time"`
+}
+
+// MaxCandles bounds a single OHLC response
+const MaxCandles = 1000
+
+// CandleIntervals are the candle widths the OHLC endpoint accepts
+var CandleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+func ParseCandleInterval(s string) (time.Duration, error) {
+	interval, found := CandleIntervals[s]
+	if !found {
+		return 0, fmt.Errorf("unknown candle interval: %s", s)
+	}
+	return interval, nil
+}
+
+// Candle summarizes the ticks that fell into one interval starting at Time
+type Candle struct {
+	Time  time.Time `json:"time"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+	Ticks int       `json:"ticks"`
+}
+
+type OHLCRequest struct {
+	Pair     CurrencyPair
+	From     time.Time
+	To       time.Time
+	Interval string
+}
+
+type OHLCSeries struct {
+	BaseCurrency   Currency  `json:"base_currency"`
+	TargetCurrency Currency  `json:"target_currency"`
+	Interval       string    `json:"interval"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	Candles        []Candle  `json:"candles"`
+}
+
+// BuildCandles groups ticks, sorted by time, into candles of the given
+// interval aligned to the unix epoch. Intervals without ticks have no candle.
+func BuildCandles(ticks []Tick, interval time.Duration) []Candle {
+	candles := make([]Candle, 0)
+	for _, tick := range ticks {
+		start := tick.Time.UTC().Truncate(interval)
+
+		last := len(candles) - 1
+		if last < 0 || !candles[last].Time.Equal(start) {
+			candles = append(candles, Candle{
+				Time:  start,
+				Open:  tick.Rate,
+				High:  tick.Rate,
+				Low:   tick.Rate,
+				Close: tick.Rate,
+				Ticks: 1,
+			})
+			continue
+		}
+
+		candle := &candles[last]
+		candle.High = max(candle.High, tick.Rate)
+		candle.Low = min(candle.Low, tick.Rate)
+		candle.Close = tick.Rate
+		candle.Ticks++
+	}
+
+	return candles
+}
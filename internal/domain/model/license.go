@@ -0,0 +1,45 @@
+package model
+
+import "fmt"
+
+// ProviderLicense holds the terms of a provider's historical data. When
+// Required is set, responses built from that data carry an Attribution.
+type ProviderLicense struct {
+	Required    bool   `json:"required"`
+	Attribution string `json:"attribution"`
+	TermsURL    string `json:"terms_url,omitempty"`
+}
+
+// ProviderLicenses maps provider names to their license terms
+type ProviderLicenses map[string]ProviderLicense
+
+// Attribution credits the provider of the historical data in a response
+type Attribution struct {
+	Provider string `json:"provider"`
+	Text     string `json:"text"`
+	TermsURL string `json:"terms_url,omitempty"`
+}
+
+// Validate checks that every required attribution has a text
+func (l ProviderLicenses) Validate() error {
+	for provider, license := range l {
+		if license.Required && license.Attribution == "" {
+			return fmt.Errorf("license of provider %s requires attribution but has none", provider)
+		}
+	}
+	return nil
+}
+
+// Attribution returns the attribution responses with data of provider must
+// carry, or nil when its license does not require one
+func (l ProviderLicenses) Attribution(provider string) *Attribution {
+	license, found := l[provider]
+	if !found || !license.Required {
+		return nil
+	}
+	return &Attribution{
+		Provider: provider,
+		Text:     license.Attribution,
+		TermsURL: license.TermsURL,
+	}
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Cache statuses of ResponseMeta
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+	// CacheStale marks rates older than the service considers fresh, however
+	// they were served
+	CacheStale = "stale"
+)
+
+// ResponseMeta describes how the data of a response was obtained, for clients
+// debugging what they were served
+type ResponseMeta struct {
+	// Cache is CacheHit when no rate had to be fetched from the provider, and
+	// CacheStale when the oldest rate is too old to be fresh
+	Cache string `json:"cache,omitempty"`
+	// DataAgeSeconds is how long ago the oldest rate served was fetched
+	DataAgeSeconds float64 `json:"data_age_seconds,omitempty"`
+	// SnapshotID is the same for rates of the same provider refresh
+	SnapshotID       string  `json:"snapshot_id,omitempty"`
+	Provider         string  `json:"provider,omitempty"`
+	ProcessingTimeMs float64 `json:"processing_time_ms"`
+}
+
+// ResponseMetaCollector gathers the ResponseMeta of a request while it is
+// served. Lookups may run concurrently, so it is safe for concurrent use, and
+// a nil collector records nothing.
+type ResponseMetaCollector struct {
+	started time.Time
+
+	mutex    sync.Mutex
+	missed   bool
+	looked   bool
+	provider string
+	updated  time.Time
+}
+
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a context collecting the ResponseMeta of a request
+// that started at started
+func WithResponseMeta(ctx context.Context, started time.Time) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, &ResponseMetaCollector{started: started})
+}
+
+// ResponseMetaFromContext returns the collector set by WithResponseMeta, or nil
+func ResponseMetaFromContext(ctx context.Context) *ResponseMetaCollector {
+	collector, _ := ctx.Value(responseMetaKey{}).(*ResponseMetaCollector)
+	return collector
+}
+
+// Note records a rate of provider served from the cache or, when cached is
+// false, fetched from the provider. rate may be nil when the lookup did not
+// give a single rate.
+func (c *ResponseMetaCollector) Note(provider string, cached bool, rate *ExchangeRate) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.looked = true
+	c.missed = c.missed || !cached
+	if c.provider == "" {
+		c.provider = provider
+	}
+	if rate != nil && !rate.LastUpdated.IsZero() && (c.updated.IsZero() || rate.LastUpdated.Before(c.updated)) {
+		c.updated = rate.LastUpdated
+	}
+}
+
+// Meta returns what was collected, as of now
+func (c *ResponseMetaCollector) Meta(now time.Time) ResponseMeta {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	meta := ResponseMeta{
+		Provider:         c.provider,
+		ProcessingTimeMs: float64(now.Sub(c.started).Microseconds()) / 1000,
+	}
+	switch {
+	case c.missed:
+		meta.Cache = CacheMiss
+	case c.looked:
+		meta.Cache = CacheHit
+	}
+	if !c.updated.IsZero() {
+		meta.DataAgeSeconds = now.Sub(c.updated).Seconds()
+		sum := sha256.Sum256([]byte(c.provider + "|" + c.updated.UTC().Format(time.RFC3339Nano)))
+		meta.SnapshotID = hex.EncodeToString(sum[:6])
+	}
+	return meta
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseCurrencyPair parses a pair in the "USD-INR" form used by CurrencyPair.String
+func ParseCurrencyPair(s string) (CurrencyPair, error) {
+	base, target, found := strings.Cut(strings.ToUpper(strings.TrimSpace(s)), "-")
+	pair := CurrencyPair{BaseCurrency: Currency(base), TargetCurrency: Currency(target)}
+	if !found || !pair.BaseCurrency.IsValidCode() || !pair.TargetCurrency.IsValidCode() {
+		return CurrencyPair{}, fmt.Errorf("invalid currency pair %q, expected BASE-TARGET", s)
+	}
+	return pair, nil
+}
+
+// CurrencyPolicy decides which currencies and pairs the service will quote.
+// An empty AllowedPairs list allows every pair; DeniedPairs always wins.
+type CurrencyPolicy struct {
+	Matching     CurrencyMatching
+	AllowedPairs []CurrencyPair
+	DeniedPairs  []CurrencyPair
+}
+
+// AllowsPair reports whether the allow and deny lists permit pair
+func (p CurrencyPolicy) AllowsPair(pair CurrencyPair) bool {
+	for _, denied := range p.DeniedPairs {
+		if denied == pair {
+			return false
+		}
+	}
+
+	if len(p.AllowedPairs) == 0 {
+		return true
+	}
+	for _, allowed := range p.AllowedPairs {
+		if allowed == pair {
+			return true
+		}
+	}
+	return false
+}
+
+// PairAvailability describes a pair the service can currently quote and how fresh its rate is
+type PairAvailability struct {
+	BaseCurrency   Currency  `json:"base_currency"`
+	TargetCurrency Currency  `json:"target_currency"`
+	Rate           float64   `json:"rate"`
+	LastUpdated    time.Time `json:"last_updated"`
+	AgeSeconds     int64     `json:"age_seconds"`
+}
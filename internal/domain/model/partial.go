@@ -0,0 +1,20 @@
+package model
+
+import "exchange-rate-service/pkg/xerrors"
+
+// ItemError is the failure of one item of a range or batch, a date or a target
+// currency, reported alongside the items that succeeded
+type ItemError struct {
+	Item    string `json:"item"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"error"`
+}
+
+// NewItemError describes err as the failure of item, by its code and message
+func NewItemError(item string, err error) ItemError {
+	message := xerrors.MessageOf(err)
+	if message == "" {
+		message = "internal error"
+	}
+	return ItemError{Item: item, Code: xerrors.CodeOf(err), Message: message}
+}
@@ -0,0 +1,194 @@
+package model
+
+import "time"
+
+// LatencyBoundsMs are the upper bounds of the latency histogram kept per SLA bucket.
+// Counts beyond the last bound fall into an extra overflow slot.
+var LatencyBoundsMs = []float64{50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// ProviderSLABucket aggregates upstream calls to one provider during one hour
+type ProviderSLABucket struct {
+	Provider      string    `json:"provider"`
+	Hour          time.Time `json:"hour"`
+	Requests      int       `json:"requests"`
+	Failures      int       `json:"failures"`
+	LatencyCounts []int     `json:"latency_counts"`
+	LastSuccess   time.Time `json:"last_success,omitzero"`
+}
+
+// LatencyPercentile approximates the q-th latency percentile as the upper bound
+// of the histogram slot containing it.
+func LatencyPercentile(counts []int, q float64) float64 {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * float64(total)
+	cumulative := 0
+	for i, c := range counts {
+		cumulative += c
+		if float64(cumulative) >= rank {
+			if i < len(LatencyBoundsMs) {
+				return LatencyBoundsMs[i]
+			}
+			break
+		}
+	}
+	return LatencyBoundsMs[len(LatencyBoundsMs)-1]
+}
+
+type ProviderSLAPoint struct {
+	Hour         time.Time `json:"hour"`
+	Requests     int       `json:"requests"`
+	SuccessRate  float64   `json:"success_rate_percent"`
+	LatencyP90Ms float64   `json:"latency_p90_ms"`
+}
+
+type ProviderSLASummary struct {
+	Provider         string             `json:"provider"`
+	Window           string             `json:"window"`
+	Requests         int                `json:"requests"`
+	Failures         int                `json:"failures"`
+	SuccessRate      float64            `json:"success_rate_percent"`
+	LatencyP50Ms     float64            `json:"latency_p50_ms"`
+	LatencyP90Ms     float64            `json:"latency_p90_ms"`
+	LatencyP99Ms     float64            `json:"latency_p99_ms"`
+	LastSuccess      time.Time          `json:"last_success,omitzero"`
+	FreshnessSeconds float64            `json:"freshness_seconds"`
+	Hourly           []ProviderSLAPoint `json:"hourly"`
+}
+
+// ProviderHealth is the failover state of one provider of a provider chain.
+// An unhealthy provider is skipped until RetryAt. RateLimited tells that it
+// was last taken out for rate limiting calls.
+type ProviderHealth struct {
+	Provider            string    `json:"provider"`
+	Position            int       `json:"position"`
+	Healthy             bool      `json:"healthy"`
+	Active              bool      `json:"active"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Failures            int       `json:"failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastFailure         time.Time `json:"last_failure,omitzero"`
+	LastSuccess         time.Time `json:"last_success,omitzero"`
+	RetryAt             time.Time `json:"retry_at,omitzero"`
+	RateLimited         bool      `json:"rate_limited,omitempty"`
+}
+
+// ProviderStatus sums up how one upstream provider is doing. Latencies and
+// Requests cover Window; CircuitOpen tells that the provider chain skips the
+// provider until RetryAt.
+type ProviderStatus struct {
+	Provider            string    `json:"provider"`
+	LastSuccess         time.Time `json:"last_success,omitzero"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	Window              string    `json:"window"`
+	Requests            int       `json:"requests"`
+	LatencyP50Ms        float64   `json:"latency_p50_ms"`
+	LatencyP90Ms        float64   `json:"latency_p90_ms"`
+	LatencyP99Ms        float64   `json:"latency_p99_ms"`
+	CircuitOpen         bool      `json:"circuit_open"`
+	RetryAt             time.Time `json:"retry_at,omitzero"`
+}
+
+// ProviderUsage counts the calls made to one upstream provider, cached
+// responses left out: in the current hour and UTC day, over the last 30 days,
+// and per hour and day when there were any. Budget is set for the provider
+// with a monthly quota.
+type ProviderUsage struct {
+	Provider   string               `json:"provider"`
+	ThisHour   int                  `json:"this_hour"`
+	Today      int                  `json:"today"`
+	Last30Days int                  `json:"last_30_days"`
+	Budget     *UsageBudget         `json:"budget,omitempty"`
+	Hourly     []ProviderUsagePoint `json:"hourly"`
+	Daily      []ProviderUsagePoint `json:"daily"`
+}
+
+// ProviderUsagePoint is the number of calls of the hour or day from Start
+type ProviderUsagePoint struct {
+	Start    time.Time `json:"start"`
+	Requests int       `json:"requests"`
+}
+
+// UsageBudget is how much of a monthly quota the last 30 days used.
+// ReservePercent of the quota is kept for refreshes and client requests:
+// once the rest is used, historical backfills are refused.
+type UsageBudget struct {
+	Monthly          int     `json:"monthly"`
+	Remaining        int     `json:"remaining"`
+	UsedPercent      float64 `json:"used_percent"`
+	ReservePercent   float64 `json:"reserve_percent"`
+	BackfillsAllowed bool    `json:"backfills_allowed"`
+}
+
+// ProviderRateLimit tells whether refreshes are paused because the provider
+// rate limited them. They resume at Until, Remaining from now.
+type ProviderRateLimit struct {
+	Limited   bool          `json:"limited"`
+	Provider  string        `json:"provider,omitempty"`
+	Until     time.Time     `json:"until,omitzero"`
+	Remaining time.Duration `json:"-"`
+}
+
+// ProviderDiffRequest asks for a day-by-day comparison of two providers.
+// Tolerance is the relative difference, in percent, above which a day is flagged.
+type ProviderDiffRequest struct {
+	Pair      CurrencyPair
+	StartDate time.Time
+	EndDate   time.Time
+	Tolerance float64
+}
+
+// ProviderDiffDay compares both providers on one date. Rates are nil when a
+// provider had no quote for the day.
+type ProviderDiffDay struct {
+	Date              string   `json:"date"`
+	PrimaryRate       *float64 `json:"primary_rate"`
+	SecondaryRate     *float64 `json:"secondary_rate"`
+	Difference        float64  `json:"difference"`
+	DifferencePercent float64  `json:"difference_percent"`
+	ExceedsTolerance  bool     `json:"exceeds_tolerance"`
+}
+
+type ProviderDiffSummary struct {
+	Days                 int     `json:"days"`
+	Compared             int     `json:"compared"`
+	MissingPrimary       int     `json:"missing_primary"`
+	MissingSecondary     int     `json:"missing_secondary"`
+	ExceedingTolerance   int     `json:"exceeding_tolerance"`
+	MeanDifferencePct    float64 `json:"mean_difference_percent"`
+	MeanAbsDifferencePct float64 `json:"mean_abs_difference_percent"`
+	MaxAbsDifferencePct  float64 `json:"max_abs_difference_percent"`
+	MaxDifferenceDate    string  `json:"max_difference_date,omitempty"`
+}
+
+type ProviderDiff struct {
+	BaseCurrency      Currency            `json:"base_currency"`
+	TargetCurrency    Currency            `json:"target_currency"`
+	PrimaryProvider   string              `json:"primary_provider"`
+	SecondaryProvider string              `json:"secondary_provider"`
+	TolerancePercent  float64             `json:"tolerance_percent"`
+	Summary           ProviderDiffSummary `json:"summary"`
+	Days              []ProviderDiffDay   `json:"days"`
+}
+
+// ProviderPush is a set of latest rates a provider delivered through its webhook
+type ProviderPush struct {
+	Provider  string               `json:"-"`
+	Base      Currency             `json:"base"`
+	Timestamp time.Time            `json:"timestamp"`
+	Rates     map[Currency]float64 `json:"rates"`
+}
+
+// ProviderPushResult reports which pushed rates were applied to the cache
+type ProviderPushResult struct {
+	Provider string     `json:"provider"`
+	Applied  int        `json:"applied"`
+	Skipped  []Currency `json:"skipped,omitempty"`
+}
@@ -11,6 +11,15 @@ type ExchangeRate struct {
 	Rate           float64   `json:"rate"`
 	Date           time.Time `json:"date"`
 	LastUpdated    time.Time `json:"last_updated"`
+	Interpolated   bool      `json:"interpolated,omitempty"`
+	// Derived is set on rates of composite units valued from their
+	// components rather than quoted by the provider
+	Derived bool `json:"derived,omitempty"`
+	// Unit is set when the pair includes a commodity, see CurrencyPair.QuoteUnit
+	Unit QuantityUnit `json:"unit,omitempty"`
+	// Sources names the providers a rate combined from several was computed
+	// from
+	Sources []string `json:"sources,omitempty"`
 }
 
 type CurrencyPair struct {
@@ -27,26 +36,101 @@ type ConversionRequest struct {
 	ToCurrency   Currency  `json:"to_currency"`
 	Amount       float64   `json:"amount"`
 	Date         time.Time `json:"date,omitempty"`
+	DryRun       bool      `json:"dry_run,omitempty"`
+	CashRounding bool      `json:"cash_rounding,omitempty"`
+	// Unit is the unit Amount and the result are in on the commodity sides
+	// of the pair that it measures; empty means their quote units
+	Unit QuantityUnit `json:"unit,omitempty"`
 }
 
 type ConversionResult struct {
-	FromCurrency Currency  `json:"from_currency"`
-	ToCurrency   Currency  `json:"to_currency"`
-	FromAmount   float64   `json:"from_amount"`
-	ToAmount     float64   `json:"to_amount"`
-	Rate         float64   `json:"rate"`
-	Date         time.Time `json:"date"`
+	ID           string        `json:"conversion_id,omitempty"`
+	FromCurrency Currency      `json:"from_currency"`
+	ToCurrency   Currency      `json:"to_currency"`
+	FromAmount   float64       `json:"from_amount"`
+	ToAmount     float64       `json:"to_amount"`
+	Rate         float64       `json:"rate"`
+	Date         time.Time     `json:"date"`
+	RateSnapshot *ExchangeRate `json:"rate_snapshot,omitempty"`
+	Fee          float64       `json:"fee"`
+	Rounding     string        `json:"rounding"`
+	Cash         *CashRounding `json:"cash,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	DryRun       bool          `json:"dry_run,omitempty"`
+	Unit         QuantityUnit  `json:"unit,omitempty"`
 }
 
+// RoundingNone marks a conversion whose amount was not rounded
+const RoundingNone = "none"
+
 type HistoricalRateRequest struct {
 	BaseCurrency   Currency  `json:"base_currency"`
 	TargetCurrency Currency  `json:"target_currency"`
 	StartDate      time.Time `json:"start_date"`
 	EndDate        time.Time `json:"end_date"`
+	Fill           FillMode  `json:"fill,omitempty"`
+	// Strict fails the request when any date of the range has no rate,
+	// rather than leaving the date out
+	Strict bool `json:"strict,omitempty"`
 }
 
+// FillMode controls how dates without a rate are handled in a historical range
+type FillMode string
+
+const (
+	FillNone        FillMode = "none"
+	FillPrevious    FillMode = "previous"
+	FillInterpolate FillMode = "interpolate"
+)
+
+func ParseFillMode(s string) (FillMode, error) {
+	switch FillMode(s) {
+	case "", FillNone:
+		return FillNone, nil
+	case FillPrevious, FillInterpolate:
+		return FillMode(s), nil
+	}
+	return "", fmt.Errorf("unknown fill mode: %s", s)
+}
+
+// HistoricalRates are the rates of a range by date. Errors lists the dates
+// whose rate could not be fetched; days without any rate are only left out.
 type HistoricalRates struct {
 	BaseCurrency   Currency                `json:"base_currency"`
 	TargetCurrency Currency                `json:"target_currency"`
 	Rates          map[string]ExchangeRate `json:"rates"`
+	Annotations    []Annotation            `json:"annotations,omitempty"`
+	Errors         []ItemError             `json:"errors,omitempty"`
+}
+
+// Partial reports whether some dates of the range failed
+func (r *HistoricalRates) Partial() bool {
+	return len(r.Errors) > 0
+}
+
+type ConversionMatrixRequest struct {
+	FromCurrency Currency   `json:"from"`
+	Amounts      []float64  `json:"amounts"`
+	Targets      []Currency `json:"targets"`
+	Date         time.Time  `json:"date,omitzero"`
+}
+
+type ConversionMatrixRow struct {
+	Amount    float64              `json:"amount"`
+	Converted map[Currency]float64 `json:"converted"`
+}
+
+// ConversionMatrix holds every amount converted into every target, all computed
+// from the same set of rates. Targets whose rate could not be looked up are
+// left out and listed in Errors.
+type ConversionMatrix struct {
+	FromCurrency Currency                  `json:"from"`
+	Rates        map[Currency]ExchangeRate `json:"rates"`
+	Rows         []ConversionMatrixRow     `json:"rows"`
+	Errors       []ItemError               `json:"errors,omitempty"`
+}
+
+// Partial reports whether some targets failed
+func (m *ConversionMatrix) Partial() bool {
+	return len(m.Errors) > 0
 }
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// RefreshSchedule describes how often the provider is polled for latest rates
+type RefreshSchedule struct {
+	Interval string `json:"interval"`
+	// MinInterval is the shortest interval the provider quota allows
+	MinInterval string `json:"min_interval"`
+	// Source is "config" until an admin changes the interval, then "admin"
+	Source    string     `json:"source"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
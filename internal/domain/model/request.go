@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Rate types a request may ask for
+const (
+	// RateTypeAdjusted rates carry the margins set for the tenant, as by default
+	RateTypeAdjusted = "adjusted"
+	// RateTypeMid rates are the provider's mid-rates, without margins
+	RateTypeMid = "mid"
+)
+
+// RequestOptions override, for one request, how rates are served and amounts
+// rounded. The zero value keeps every default.
+type RequestOptions struct {
+	// Rounding is RoundingNone or RoundingCash; empty leaves it to the request
+	Rounding string
+	// Provider names the provider latest rates come from; empty is the primary
+	Provider string
+	// RateType is RateTypeAdjusted or RateTypeMid; empty is RateTypeAdjusted
+	RateType string
+	// MaxAge is the oldest a latest rate may be; older ones are fetched again.
+	// 0 takes any age.
+	MaxAge time.Duration
+	// DryRun marks previews, which are served like any request but neither
+	// stored nor counted. Dry runs are never cached, so String leaves it out.
+	DryRun bool
+}
+
+// Validate rejects unknown rounding modes and rate types, and negative max ages
+func (o RequestOptions) Validate() error {
+	if o.MaxAge < 0 {
+		return fmt.Errorf("max age must not be negative")
+	}
+	switch o.Rounding {
+	case "", RoundingNone, RoundingCash:
+	default:
+		return fmt.Errorf("unknown rounding %q, use %s or %s", o.Rounding, RoundingNone, RoundingCash)
+	}
+	switch o.RateType {
+	case "", RateTypeAdjusted, RateTypeMid:
+	default:
+		return fmt.Errorf("unknown rate type %q, use %s or %s", o.RateType, RateTypeAdjusted, RateTypeMid)
+	}
+	return nil
+}
+
+// String identifies the options in cache keys
+func (o RequestOptions) String() string {
+	return strings.Join([]string{o.Rounding, o.Provider, o.RateType, o.MaxAge.String()}, ",")
+}
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a context carrying the options of a request
+func WithRequestOptions(ctx context.Context, options RequestOptions) context.Context {
+	return context.WithValue(ctx, requestOptionsKey{}, options)
+}
+
+// RequestOptionsFromContext returns the options set by WithRequestOptions, or
+// the zero value
+func RequestOptionsFromContext(ctx context.Context) RequestOptions {
+	options, _ := ctx.Value(requestOptionsKey{}).(RequestOptions)
+	return options
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+// SimulationOrderType is how a simulated order executes
+type SimulationOrderType string
+
+const (
+	// SimulationLimit executes once, on the first rate that meets its condition
+	SimulationLimit SimulationOrderType = "limit"
+	// SimulationDCA executes on every rate at least Every after the previous
+	// execution, and only when its condition, if any, is met
+	SimulationDCA SimulationOrderType = "dca"
+)
+
+// SimulationCondition compares a rate with an order's threshold
+type SimulationCondition string
+
+const (
+	SimulationAbove SimulationCondition = "above"
+	SimulationBelow SimulationCondition = "below"
+)
+
+// SimulationOrder converts Amount of BaseCurrency into TargetCurrency on paper
+// whenever its rules say so. Nothing is ever converted for real.
+type SimulationOrder struct {
+	ID             string              `json:"id"`
+	Tenant         string              `json:"tenant"`
+	Type           SimulationOrderType `json:"type"`
+	BaseCurrency   Currency            `json:"base_currency"`
+	TargetCurrency Currency            `json:"target_currency"`
+	Amount         float64             `json:"amount"`
+	Condition      SimulationCondition `json:"condition,omitempty"`
+	Threshold      float64             `json:"threshold,omitempty"`
+	// Every is the minimum time between executions of a DCA order, e.g. "24h"
+	Every          string    `json:"every,omitempty"`
+	Active         bool      `json:"active"`
+	Executions     int       `json:"executions"`
+	LastExecutedAt time.Time `json:"last_executed_at,omitzero"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (o SimulationOrder) Pair() CurrencyPair {
+	return CurrencyPair{BaseCurrency: o.BaseCurrency, TargetCurrency: o.TargetCurrency}
+}
+
+// ConditionMet reports whether rate satisfies the order's condition. An order
+// without a condition accepts every rate.
+func (o SimulationOrder) ConditionMet(rate float64) bool {
+	switch o.Condition {
+	case SimulationAbove:
+		return rate > o.Threshold
+	case SimulationBelow:
+		return rate < o.Threshold
+	}
+	return true
+}
+
+// SimulatedExecution records a simulated conversion of an order at the rate
+// published at RateTime
+type SimulatedExecution struct {
+	ID             string    `json:"id"`
+	OrderID        string    `json:"order_id"`
+	BaseCurrency   Currency  `json:"base_currency"`
+	TargetCurrency Currency  `json:"target_currency"`
+	Amount         float64   `json:"amount"`
+	Rate           float64   `json:"rate"`
+	Converted      float64   `json:"converted"`
+	RateTime       time.Time `json:"rate_time"`
+	ExecutedAt     time.Time `json:"executed_at"`
+}
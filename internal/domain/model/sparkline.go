@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// DefaultSparklinePoints is the number of points a sparkline has unless the
+// client asks for another
+const DefaultSparklinePoints = 60
+
+// RatePoint is one recent value of a pair's rate
+type RatePoint struct {
+	Rate float64   `json:"rate"`
+	Time time.Time `json:"time"`
+}
+
+// Sparkline holds the most recent rates of a pair, oldest first
+type Sparkline struct {
+	BaseCurrency   Currency    `json:"base_currency"`
+	TargetCurrency Currency    `json:"target_currency"`
+	Points         []RatePoint `json:"points"`
+	Min            float64     `json:"min"`
+	Max            float64     `json:"max"`
+	// ChangePercent is the move from the first to the last point
+	ChangePercent float64 `json:"change_percent"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// RangeStatisticsRequest selects the stored daily closes of a pair to analyze
+type RangeStatisticsRequest struct {
+	BaseCurrency   Currency
+	TargetCurrency Currency
+	StartDate      time.Time
+	EndDate        time.Time
+}
+
+// Drawdown is the largest fall from a close to a later, lower close
+type Drawdown struct {
+	Percent    float64   `json:"percent"`
+	PeakRate   float64   `json:"peak_rate"`
+	PeakDate   time.Time `json:"peak_date"`
+	TroughRate float64   `json:"trough_rate"`
+	TroughDate time.Time `json:"trough_date"`
+	// RecoveryDate is the first later close back at the peak, if any
+	RecoveryDate *time.Time `json:"recovery_date,omitempty"`
+}
+
+// Streak is a run of consecutive daily closes moving the same way. Days
+// counts the moves, so a streak of 3 spans 4 closes.
+type Streak struct {
+	Days      int        `json:"days"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+}
+
+// RangeStatistics summarizes the risk of holding a pair over a window of
+// stored daily closes. Dates without a stored close are skipped.
+type RangeStatistics struct {
+	BaseCurrency   Currency  `json:"base_currency"`
+	TargetCurrency Currency  `json:"target_currency"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	Closes         int       `json:"closes"`
+
+	High     float64   `json:"high"`
+	HighDate time.Time `json:"high_date"`
+	Low      float64   `json:"low"`
+	LowDate  time.Time `json:"low_date"`
+	// RangeWidth is High - Low, and RangeWidthPercent the same relative to Low
+	RangeWidth        float64 `json:"range_width"`
+	RangeWidthPercent float64 `json:"range_width_percent"`
+
+	MaxDrawdown       Drawdown `json:"max_drawdown"`
+	LongestUpStreak   Streak   `json:"longest_up_streak"`
+	LongestDownStreak Streak   `json:"longest_down_streak"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Requests a ValidationRequest can describe
+const (
+	ValidateConversion      = "conversion"
+	ValidateHistorical      = "historical"
+	ValidateHistoricalRange = "historical_range"
+)
+
+// ValidationRequest is a conversion or historical request to check against
+// the rules of the service without serving it. Type is one of the Validate
+// constants; the fields it does not use are ignored.
+type ValidationRequest struct {
+	Type           string
+	BaseCurrency   Currency
+	TargetCurrency Currency
+	// Amount and Unit are those of a conversion
+	Amount float64
+	Unit   QuantityUnit
+	// Date is that of a historical rate, or of a conversion at a past rate
+	Date      time.Time
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// ValidationIssue is a rule a request breaks. Field names the request field
+// at fault, and Code is the error code serving the request would fail with.
+type ValidationIssue struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationResult lists every rule a request breaks, not only the first
+type ValidationResult struct {
+	Valid  bool              `json:"valid"`
+	Issues []ValidationIssue `json:"issues"`
+}
@@ -0,0 +1,128 @@
+package model
+
+import "time"
+
+// WebhookEventType names an event tenants can subscribe to
+type WebhookEventType string
+
+const (
+	// EventRateRefreshed carries the rates of every refresh, push or intraday sample
+	EventRateRefreshed WebhookEventType = "rate.refreshed"
+	// EventAlertTriggered is sent when a rate moved more than the alert
+	// threshold since it was last published
+	EventAlertTriggered WebhookEventType = "alert.triggered"
+	// EventProviderFailover is sent when requests move to another provider endpoint
+	EventProviderFailover WebhookEventType = "provider.failover"
+	// EventAnomalyDetected is sent when a rate moved far outside the recent
+	// behaviour of its pair
+	EventAnomalyDetected WebhookEventType = "anomaly.detected"
+)
+
+// WebhookEventTypes lists the events that can be subscribed to
+var WebhookEventTypes = []WebhookEventType{EventRateRefreshed, EventAlertTriggered, EventProviderFailover, EventAnomalyDetected}
+
+// Valid reports whether t is a known event type
+func (t WebhookEventType) Valid() bool {
+	for _, known := range WebhookEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the body posted to subscribers
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      interface{}      `json:"data"`
+}
+
+// WebhookSubscription delivers events of the listed types to URL. Tenant
+// identifies the API key that owns it; Secret signs every delivery and is only
+// shown when the subscription is created.
+type WebhookSubscription struct {
+	ID        string             `json:"id"`
+	Tenant    string             `json:"tenant"`
+	URL       string             `json:"url"`
+	Events    []WebhookEventType `json:"events"`
+	Secret    string             `json:"secret,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// Wants reports whether the subscription listens to events of type t
+func (s WebhookSubscription) Wants(t WebhookEventType) bool {
+	for _, event := range s.Events {
+		if event == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one attempt to deliver an event to a subscription
+type WebhookDelivery struct {
+	ID             string           `json:"id"`
+	SubscriptionID string           `json:"subscription_id"`
+	EventID        string           `json:"event_id"`
+	Event          WebhookEventType `json:"event"`
+	Success        bool             `json:"success"`
+	StatusCode     int              `json:"status_code,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	DurationMs     int64            `json:"duration_ms"`
+	AttemptedAt    time.Time        `json:"attempted_at"`
+}
+
+// RateAlert is the data of an alert.triggered event. Rejected alerts come
+// from the refresh guardrail: the new rate was not served.
+type RateAlert struct {
+	BaseCurrency   Currency `json:"base_currency"`
+	TargetCurrency Currency `json:"target_currency"`
+	PreviousRate   float64  `json:"previous_rate"`
+	Rate           float64  `json:"rate"`
+	ChangePercent  float64  `json:"change_percent"`
+	Rejected       bool     `json:"rejected,omitempty"`
+}
+
+// ProviderFailover is the data of a provider.failover event. An empty To means
+// no endpoint is healthy and requests fall back to the base URL.
+type ProviderFailover struct {
+	Provider string `json:"provider"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+}
+
+// AlertRules decide when alert.triggered is sent
+type AlertRules struct {
+	// ThresholdPercent is the rate move that triggers an alert, 0 for none
+	ThresholdPercent float64 `json:"threshold_percent" yaml:"threshold_percent"`
+}
+
+// WebhookDefinition is a subscription as exported and imported by admins.
+// Secret is only exported on request; imported without one, a subscription
+// keeps its secret or, when new, is given one.
+type WebhookDefinition struct {
+	ID     string             `json:"id,omitempty" yaml:"id,omitempty"`
+	Tenant string             `json:"tenant" yaml:"tenant"`
+	URL    string             `json:"url" yaml:"url"`
+	Events []WebhookEventType `json:"events" yaml:"events"`
+	Secret string             `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// AlertDocument holds the alert rules and webhook subscriptions of every
+// tenant, to be kept under version control and promoted between environments
+type AlertDocument struct {
+	AlertRules AlertRules          `json:"alert_rules" yaml:"alert_rules"`
+	Webhooks   []WebhookDefinition `json:"webhooks" yaml:"webhooks"`
+}
+
+// AlertImportResult tells what an imported AlertDocument changed. Created
+// subscriptions include their secrets, which are not shown again.
+type AlertImportResult struct {
+	AlertRules AlertRules            `json:"alert_rules"`
+	Created    []WebhookSubscription `json:"created"`
+	Updated    []string              `json:"updated"`
+	Unchanged  []string              `json:"unchanged"`
+	Deleted    []string              `json:"deleted"`
+}
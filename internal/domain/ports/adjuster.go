@@ -0,0 +1,9 @@
+package ports
+
+import "exchange-rate-service/internal/domain/model"
+
+// RateAdjuster changes the rates served to clients, per pair and tenant. It
+// returns rate unchanged when no adjustment applies.
+type RateAdjuster interface {
+	Adjust(tenant string, pair model.CurrencyPair, rate float64) float64
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+type AnnotationStore interface {
+	Create(ctx context.Context, annotation *model.Annotation) error
+	List(ctx context.Context, filter model.AnnotationFilter) ([]model.Annotation, error)
+	Delete(ctx context.Context, id string) (bool, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// BasketStore persists the currency baskets of API key holders
+type BasketStore interface {
+	Create(ctx context.Context, basket *model.Basket) error
+	// List returns the baskets of tenant
+	List(ctx context.Context, tenant string) ([]model.Basket, error)
+	Delete(ctx context.Context, tenant, id string) (bool, error)
+}
@@ -11,4 +11,15 @@ type RateCache interface {
 	Get(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool)
 	Set(ctx context.Context, rate *model.ExchangeRate) error
 	ClearExpired(ctx context.Context) error
+	// Stats reports entry counts, an estimate of the memory held, hit ratios
+	// over trailing windows and evictions since start
+	Stats(ctx context.Context) model.CacheStats
+}
+
+// NamespacedRateCache keeps rates apart from the shared ones of a RateCache,
+// under a namespace such as the tenant whose adjustments they carry, so they
+// are never served to anyone else
+type NamespacedRateCache interface {
+	GetIn(ctx context.Context, namespace string, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool)
+	SetIn(ctx context.Context, namespace string, rate *model.ExchangeRate) error
 }
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+type ConversionStore interface {
+	Save(ctx context.Context, result *model.ConversionResult) error
+	Get(ctx context.Context, id string) (*model.ConversionResult, bool)
+}
+
+// ConversionCache briefly keeps conversion results by a key of the request
+// inputs, so repeated identical requests get the same result and receipt
+type ConversionCache interface {
+	Get(ctx context.Context, key string) (*model.ConversionResult, bool)
+	Set(ctx context.Context, key string, result *model.ConversionResult)
+}
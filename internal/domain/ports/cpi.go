@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// ErrCPINotFound is returned by CPI sources without data for a currency or date
+var ErrCPINotFound = xerrors.New(xerrors.NotFound, "no_cpi_data", "no CPI data")
+
+// CPISource provides consumer price indexes per currency
+type CPISource interface {
+	// At returns the latest observation for currency on or before date
+	At(ctx context.Context, currency model.Currency, date time.Time) (*model.CPIObservation, error)
+	// Latest returns the most recent observation for currency
+	Latest(ctx context.Context, currency model.Currency) (*model.CPIObservation, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// DeliveryStore persists the retry ledger of failed deliveries
+type DeliveryStore interface {
+	// Save inserts or replaces a delivery by ID
+	Save(ctx context.Context, delivery *model.Delivery) error
+	Get(ctx context.Context, id string) (*model.Delivery, bool)
+	// List returns matching deliveries, most recently updated first
+	List(ctx context.Context, filter model.DeliveryFilter) ([]model.Delivery, error)
+	// Due returns retrying deliveries whose next attempt is at or before now
+	Due(ctx context.Context, now time.Time) ([]model.Delivery, error)
+}
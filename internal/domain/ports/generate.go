@@ -0,0 +1,26 @@
+package ports
+
+// The mocks package holds a mock of every port for tests, generated by moq
+// (github.com/matryer/moq). Run go generate after changing a port.
+
+//go:generate moq -out mocks/annotation_store.go -pkg mocks . AnnotationStore
+//go:generate moq -out mocks/basket_store.go -pkg mocks . BasketStore
+//go:generate moq -out mocks/cpi_source.go -pkg mocks . CPISource
+//go:generate moq -out mocks/conversion_cache.go -pkg mocks . ConversionCache
+//go:generate moq -out mocks/conversion_store.go -pkg mocks . ConversionStore
+//go:generate moq -out mocks/delivery_store.go -pkg mocks . DeliveryStore
+//go:generate moq -out mocks/exchange_service.go -pkg mocks . ExchangeService
+//go:generate moq -out mocks/history_store.go -pkg mocks . HistoryStore
+//go:generate moq -out mocks/namespaced_rate_cache.go -pkg mocks . NamespacedRateCache
+//go:generate moq -out mocks/pair_popularity.go -pkg mocks . PairPopularity
+//go:generate moq -out mocks/pair_refresher.go -pkg mocks . PairRefresher
+//go:generate moq -out mocks/provider_health_reporter.go -pkg mocks . ProviderHealthReporter
+//go:generate moq -out mocks/rate_adjuster.go -pkg mocks . RateAdjuster
+//go:generate moq -out mocks/rate_cache.go -pkg mocks . RateCache
+//go:generate moq -out mocks/rate_publisher.go -pkg mocks . RatePublisher
+//go:generate moq -out mocks/rate_repository.go -pkg mocks . RateRepository
+//go:generate moq -out mocks/recent_rate_store.go -pkg mocks . RecentRateStore
+//go:generate moq -out mocks/sla_store.go -pkg mocks . SLAStore
+//go:generate moq -out mocks/simulation_store.go -pkg mocks . SimulationStore
+//go:generate moq -out mocks/tick_store.go -pkg mocks . TickStore
+//go:generate moq -out mocks/webhook_store.go -pkg mocks . WebhookStore
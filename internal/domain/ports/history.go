@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// HistoryStore persists daily historical rates per pair
+type HistoryStore interface {
+	Save(ctx context.Context, rates []model.ExchangeRate) error
+	Get(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool)
+	Range(ctx context.Context, pair model.CurrencyPair, start, end time.Time) ([]model.ExchangeRate, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// PairPopularity tracks how often each pair is requested. Scores decay over
+// time, so pairs no longer requested fall back behind current ones.
+type PairPopularity interface {
+	Record(ctx context.Context, pair model.CurrencyPair)
+	// Popular returns up to limit pairs with a score, the most popular first
+	Popular(ctx context.Context, limit int) []model.CurrencyPair
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// RatePublisher receives rates as soon as the service obtains fresh ones, from
+// a refresh, a provider push or intraday sampling. Implementations must not
+// block for long since they run on the refresh path.
+type RatePublisher interface {
+	Publish(ctx context.Context, rates []model.ExchangeRate) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import "exchange-rate-service/internal/domain/model"
+
+// RecentRateStore keeps the last published rates of every pair in memory. It
+// is fed as a publisher.
+type RecentRateStore interface {
+	RatePublisher
+	// Recent returns up to n of the newest points of pair, oldest first
+	Recent(pair model.CurrencyPair, n int) []model.RatePoint
+}
@@ -2,14 +2,39 @@ package ports
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/xerrors"
 )
 
+// ErrQuoteNotFound is returned by repositories when the provider has no quote for a currency
+var ErrQuoteNotFound = xerrors.New(xerrors.NotFound, "quote_not_found", "provider has no quote for currency")
+
+// ErrRatesUnchanged is returned by RefreshRates when the provider sent the same
+// quotes as the refresh behind the serving rates, which are then left as they are
+var ErrRatesUnchanged = errors.New("provider quotes unchanged")
+
 type RateRepository interface {
+	// Name identifies the provider behind the repository
+	Name() string
 	FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error)
 	FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error)
 	FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error)
 	RefreshRates(ctx context.Context) error
+	// LatestRates returns the rates held from the most recent refresh without calling the provider
+	LatestRates(ctx context.Context) []model.ExchangeRate
+}
+
+// PairRefresher is implemented by repositories that can refresh some pairs
+// while keeping the rates of the others as they are
+type PairRefresher interface {
+	RefreshPairs(ctx context.Context, pairs []model.CurrencyPair) error
+}
+
+// ProviderHealthReporter is implemented by repositories that fail over
+// between several providers and track the health of each
+type ProviderHealthReporter interface {
+	ProviderHealth() []model.ProviderHealth
 }
@@ -11,6 +11,36 @@ type ExchangeService interface {
 	GetLatestRate(ctx context.Context, from, to model.Currency) (*model.ExchangeRate, error)
 	GetHistoricalRate(ctx context.Context, from, to model.Currency, date time.Time) (*model.ExchangeRate, error)
 	GetHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error)
+	HistoricalAttribution(ctx context.Context) *model.Attribution
 	ConvertCurrency(ctx context.Context, request model.ConversionRequest) (*model.ConversionResult, error)
+	TravelBudget(ctx context.Context, request model.TravelBudgetRequest) (*model.TravelBudget, error)
+	Backtest(ctx context.Context, request model.BacktestRequest) (*model.Backtest, error)
+	InflationAdjustedConversion(ctx context.Context, request model.InflationRequest) (*model.InflationAdjustedConversion, error)
+	ConvertMatrix(ctx context.Context, request model.ConversionMatrixRequest) (*model.ConversionMatrix, error)
 	RefreshRates(ctx context.Context) error
+	ListPairs(ctx context.Context) ([]model.PairAvailability, error)
+	GetConversion(ctx context.Context, id string) (*model.ConversionResult, error)
+	CreateAnnotation(ctx context.Context, annotation model.Annotation) (*model.Annotation, error)
+	ListAnnotations(ctx context.Context, filter model.AnnotationFilter) ([]model.Annotation, error)
+	DeleteAnnotation(ctx context.Context, id string) error
+	HistoryGaps(ctx context.Context, pair *model.CurrencyPair) (*model.HistoryGapReport, error)
+	BackfillHistory(ctx context.Context, pair *model.CurrencyPair, limit int) (*model.BackfillResult, error)
+	ProviderSLA(ctx context.Context, window time.Duration) ([]model.ProviderSLASummary, error)
+	ProviderHealth(ctx context.Context) ([]model.ProviderHealth, error)
+	ProviderStatus(ctx context.Context, window time.Duration) ([]model.ProviderStatus, error)
+	ProviderUsage(ctx context.Context) ([]model.ProviderUsage, error)
+	ProviderRateLimit(ctx context.Context) model.ProviderRateLimit
+	CacheStats(ctx context.Context) model.CacheStats
+	DiffProviders(ctx context.Context, request model.ProviderDiffRequest) (*model.ProviderDiff, error)
+	SampleIntraday(ctx context.Context, pairs []model.CurrencyPair) error
+	GetOHLC(ctx context.Context, request model.OHLCRequest) (*model.OHLCSeries, error)
+	Sparkline(ctx context.Context, from, to model.Currency, points int) (*model.Sparkline, error)
+	RangeStatistics(ctx context.Context, request model.RangeStatisticsRequest) (*model.RangeStatistics, error)
+	CreateBasket(ctx context.Context, tenant string, basket model.Basket) (*model.Basket, error)
+	ListBaskets(ctx context.Context, tenant string) ([]model.Basket, error)
+	DeleteBasket(ctx context.Context, tenant, id string) error
+	QuoteBasket(ctx context.Context, tenant, id string, currency model.Currency) (*model.BasketQuote, error)
+	ChartBasket(ctx context.Context, tenant, id string, currency model.Currency, start, end time.Time) (*model.BasketChart, error)
+	ApplyProviderPush(ctx context.Context, push model.ProviderPush) (*model.ProviderPushResult, error)
+	ValidateRequest(ctx context.Context, request model.ValidationRequest) *model.ValidationResult
 }
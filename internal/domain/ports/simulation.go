@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// SimulationStore persists simulated orders and their executions
+type SimulationStore interface {
+	// SaveOrder creates or replaces an order
+	SaveOrder(ctx context.Context, order *model.SimulationOrder) error
+	// Orders lists the orders of tenant, or of all tenants when tenant is empty
+	Orders(ctx context.Context, tenant string) ([]model.SimulationOrder, error)
+	DeleteOrder(ctx context.Context, tenant, id string) (bool, error)
+	RecordExecution(ctx context.Context, execution *model.SimulatedExecution) error
+	// Executions returns the executions of an order, newest first
+	Executions(ctx context.Context, orderID string) ([]model.SimulatedExecution, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// SLAStore records the outcome of every upstream provider call
+type SLAStore interface {
+	Record(ctx context.Context, provider string, latency time.Duration, success bool)
+	Buckets(ctx context.Context, since time.Time) ([]model.ProviderSLABucket, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// TickStore keeps intraday rate observations per pair
+type TickStore interface {
+	Append(ctx context.Context, ticks []model.Tick) error
+	// Range returns the ticks of a pair in [from, to), oldest first
+	Range(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.Tick, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// WebhookStore persists webhook subscriptions and their delivery log
+type WebhookStore interface {
+	CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	// Subscriptions lists the subscriptions of tenant, or of all tenants when tenant is empty
+	Subscriptions(ctx context.Context, tenant string) ([]model.WebhookSubscription, error)
+	// UpdateSubscription replaces a subscription, reporting false when the
+	// tenant has none with its ID
+	UpdateSubscription(ctx context.Context, subscription *model.WebhookSubscription) (bool, error)
+	DeleteSubscription(ctx context.Context, tenant, id string) (bool, error)
+	RecordDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	// Deliveries returns the logged deliveries of a subscription, newest first
+	Deliveries(ctx context.Context, subscriptionID string) ([]model.WebhookDelivery, error)
+	// AlertRules returns the saved alert rules, or nil when none were saved
+	AlertRules(ctx context.Context) (*model.AlertRules, error)
+	SaveAlertRules(ctx context.Context, rules model.AlertRules) error
+}
@@ -0,0 +1,132 @@
+// Package i18n localizes client-facing API error messages and currency names.
+// Catalogs are embedded and keyed by the English text, so a message without a
+// translation falls back to English rather than disappearing.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when no requested language has a catalog
+const DefaultLocale = "en"
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+type catalog struct {
+	Messages   map[string]string `json:"messages"`
+	Currencies map[string]string `json:"currencies"`
+}
+
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]catalog {
+	entries, err := catalogFiles.ReadDir("catalogs")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[string]catalog, len(entries))
+	for _, entry := range entries {
+		content, err := catalogFiles.ReadFile("catalogs/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		var c catalog
+		if err := json.Unmarshal(content, &c); err != nil {
+			panic("i18n: invalid catalog " + entry.Name() + ": " + err.Error())
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = c
+	}
+	return loaded
+}
+
+// Locales returns the locales that have a catalog, sorted
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Negotiate picks the best supported locale for an Accept-Language header.
+// Each range is tried as given and then by its primary subtag, in q order;
+// DefaultLocale is returned when nothing matches.
+func Negotiate(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var ranges []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		ranges = append(ranges, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, r := range ranges {
+		if _, ok := catalogs[r.tag]; ok {
+			return r.tag
+		}
+		primary, _, _ := strings.Cut(r.tag, "-")
+		if _, ok := catalogs[primary]; ok {
+			return primary
+		}
+	}
+	return DefaultLocale
+}
+
+// Message translates an English API message. Messages of the form
+// "prefix: detail" translate the prefix and keep the detail as is, since the
+// detail usually echoes client input.
+func Message(locale, message string) string {
+	c, ok := catalogs[locale]
+	if !ok {
+		return message
+	}
+	if translated, ok := c.Messages[message]; ok {
+		return translated
+	}
+	if prefix, detail, ok := strings.Cut(message, ": "); ok {
+		if translated, ok := c.Messages[prefix]; ok {
+			return translated + ": " + detail
+		}
+	}
+	return message
+}
+
+// CurrencyName returns the display name of code in locale, falling back to
+// English and finally to the code itself
+func CurrencyName(locale, code string) string {
+	if name, ok := catalogs[locale].Currencies[code]; ok {
+		return name
+	}
+	if name, ok := catalogs[DefaultLocale].Currencies[code]; ok {
+		return name
+	}
+	return code
+}
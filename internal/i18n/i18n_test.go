@@ -0,0 +1,67 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"empty header", "", "en"},
+		{"exact match", "de", "de"},
+		{"region falls back to primary subtag", "fr-CA", "fr"},
+		{"highest q wins", "de;q=0.4, ja;q=0.9", "ja"},
+		{"unsupported skipped", "pt-BR, es;q=0.5", "es"},
+		{"zero q ignored", "de;q=0, fr;q=0.1", "fr"},
+		{"wildcard only", "*", "en"},
+		{"nothing supported", "pt, it", "en"},
+		{"case insensitive", "HI-in", "hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		locale  string
+		message string
+		want    string
+	}{
+		{"exact translation", "de", "invalid currency", "ungültige Währung"},
+		{"prefix translated, detail kept", "fr", "invalid basket: weights must be positive", "panier de devises invalide: weights must be positive"},
+		{"untranslated falls back to english", "ja", "sparklines are disabled", "sparklines are disabled"},
+		{"unknown locale", "pt", "invalid currency", "invalid currency"},
+		{"english passes through", "en", "invalid currency", "invalid currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Message(tt.locale, tt.message); got != tt.want {
+				t.Errorf("Message(%q, %q) = %q, want %q", tt.locale, tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyName(t *testing.T) {
+
+	if got := CurrencyName("es", "JPY"); got != "yen japonés" {
+		t.Errorf("CurrencyName(es, JPY) = %q", got)
+	}
+	if got := CurrencyName("pt", "EUR"); got != "Euro" {
+		t.Errorf("CurrencyName(pt, EUR) = %q, want english fallback", got)
+	}
+	if got := CurrencyName("de", "ZZZ"); got != "ZZZ" {
+		t.Errorf("CurrencyName(de, ZZZ) = %q, want code", got)
+	}
+}
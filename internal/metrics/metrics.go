@@ -1,6 +1,10 @@
 package metrics
 
 import (
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -11,7 +15,53 @@ type Metrics struct {
 
 	RateRequestsTotal       prometheus.Counter
 	ConversionRequestsTotal prometheus.Counter
+	ConversionCacheLookups  *prometheus.CounterVec
+	ResponseCacheLookups    *prometheus.CounterVec
 	HistoricalRequestsTotal prometheus.Counter
+
+	UnsupportedCurrencyRequests *prometheus.CounterVec
+	ProviderPushes              *prometheus.CounterVec
+	ProxyRequests               *prometheus.CounterVec
+	WebhookDeliveries           *prometheus.CounterVec
+	Deliveries                  *prometheus.CounterVec
+	GuardrailRejections         *prometheus.CounterVec
+	RateAnomalies               *prometheus.CounterVec
+	SimulatedExecutions         *prometheus.CounterVec
+	DeprecatedUsage             *prometheus.CounterVec
+
+	FIXSessions                prometheus.Gauge
+	FIXDroppedUpdates          prometheus.Counter
+	FIXSlowConsumerDisconnects prometheus.Counter
+
+	HistoryCompleteness     prometheus.Gauge
+	HistoryPairCompleteness *prometheus.GaugeVec
+
+	HeapBytes               prometheus.Gauge
+	MemoryPressureEvents    prometheus.Counter
+	MemoryPressureEvictions *prometheus.CounterVec
+
+	CacheEntries        prometheus.Gauge
+	CacheEstimatedBytes prometheus.Gauge
+	CacheNamespaceBytes *prometheus.GaugeVec
+	CacheHitRatio       *prometheus.GaugeVec
+	CacheEvictions      *prometheus.CounterVec
+
+	SnapshotVerificationFailures prometheus.Counter
+
+	ProviderRateLimited *prometheus.GaugeVec
+
+	ProviderUsage       *prometheus.GaugeVec
+	ProviderBudgetUsage *prometheus.GaugeVec
+
+	ProviderCircuitState *prometheus.GaugeVec
+
+	ProviderRateSpread      *prometheus.GaugeVec
+	ProviderRateDiscrepancy *prometheus.GaugeVec
+
+	// cacheEvictions holds the eviction totals last observed, since the cache
+	// reports totals and CacheEvictions only takes increments
+	cacheMutex     sync.Mutex
+	cacheEvictions map[string]int64
 }
 
 func NewMetrics() *Metrics {
@@ -47,11 +97,311 @@ func NewMetrics() *Metrics {
 			},
 		),
 
+		ConversionCacheLookups: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "conversion_cache_lookups_total",
+				Help: "Total number of conversion result cache lookups, by result (hit, miss)",
+			},
+			[]string{"result"},
+		),
+
+		ResponseCacheLookups: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "http_response_cache_lookups_total",
+				Help: "Total number of HTTP response cache lookups, by route and result (hit, miss)",
+			},
+			[]string{"path", "result"},
+		),
+
 		HistoricalRequestsTotal: promauto.NewCounter(
 			prometheus.CounterOpts{
 				Name: "historical_requests_total",
 				Help: "Total number of historical exchange rate requests",
 			},
 		),
+
+		UnsupportedCurrencyRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "unsupported_currency_requests_total",
+				Help: "Total number of requests naming a currency outside the supported list, by outcome",
+			},
+			[]string{"currency", "outcome"},
+		),
+
+		ProviderPushes: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "provider_pushes_total",
+				Help: "Total number of rate pushes received on provider webhooks, by outcome",
+			},
+			[]string{"provider", "outcome"},
+		),
+
+		ProxyRequests: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "proxy_requests_total",
+				Help: "Total number of provider proxy requests, by endpoint and result (hit, miss, error, limited)",
+			},
+			[]string{"endpoint", "result"},
+		),
+
+		WebhookDeliveries: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "webhook_deliveries_total",
+				Help: "Total number of outgoing webhook deliveries, by event and result",
+			},
+			[]string{"event", "result"},
+		),
+
+		Deliveries: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "delivery_ledger_attempts_total",
+				Help: "Total number of attempts recorded in the delivery ledger, by kind and resulting status (retrying, delivered, dead)",
+			},
+			[]string{"kind", "status"},
+		),
+
+		GuardrailRejections: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "rate_guardrail_rejections_total",
+				Help: "Total number of quotes whose refresh was held back for moving beyond the guardrail, by pair",
+			},
+			[]string{"pair"},
+		),
+
+		RateAnomalies: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "rate_anomalies_total",
+				Help: "Total number of rate moves detected as anomalous, by pair",
+			},
+			[]string{"pair"},
+		),
+
+		SimulatedExecutions: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "simulated_executions_total",
+				Help: "Total number of simulated order executions, by order type",
+			},
+			[]string{"type"},
+		),
+		DeprecatedUsage: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "deprecated_feature_requests_total",
+				Help: "Total number of requests using a deprecated route or parameter, by feature",
+			},
+			[]string{"feature"},
+		),
+
+		FIXSessions: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "fix_sessions",
+				Help: "Number of connected FIX market data sessions",
+			},
+		),
+
+		FIXDroppedUpdates: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "fix_dropped_updates_total",
+				Help: "Total number of FIX market data updates superseded before a slow session was sent them",
+			},
+		),
+
+		FIXSlowConsumerDisconnects: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "fix_slow_consumer_disconnects_total",
+				Help: "Total number of FIX sessions closed for falling behind on updates",
+			},
+		),
+
+		HistoryCompleteness: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "history_completeness_percent",
+				Help: "Percentage of days with stored rates within the retention window, across all pairs",
+			},
+		),
+
+		HistoryPairCompleteness: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "history_pair_completeness_percent",
+				Help: "Percentage of days with stored rates within the retention window, per pair",
+			},
+			[]string{"pair"},
+		),
+
+		HeapBytes: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "memory_watchdog_heap_bytes",
+				Help: "Heap bytes in use as last sampled by the memory watchdog",
+			},
+		),
+
+		MemoryPressureEvents: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "memory_pressure_events_total",
+				Help: "Total number of times heap usage crossed the memory watchdog high watermark",
+			},
+		),
+
+		MemoryPressureEvictions: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "memory_pressure_evictions_total",
+				Help: "Total number of entries evicted under memory pressure",
+			},
+			[]string{"store"},
+		),
+
+		CacheEntries: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "rate_cache_entries",
+				Help: "Number of entries in the rate cache, including expired ones not yet cleared",
+			},
+		),
+
+		CacheEstimatedBytes: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "rate_cache_estimated_bytes",
+				Help: "Estimated memory held by rate cache entries",
+			},
+		),
+
+		CacheNamespaceBytes: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "rate_cache_namespace_estimated_bytes",
+				Help: "Estimated memory held by rate cache entries, by namespace (shared, or the tenant of adjusted rates)",
+			},
+			[]string{"namespace"},
+		),
+
+		CacheHitRatio: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "rate_cache_hit_ratio",
+				Help: "Share of rate cache lookups that hit, over a trailing window",
+			},
+			[]string{"window"},
+		),
+
+		CacheEvictions: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "rate_cache_evictions_total",
+				Help: "Total number of entries removed from the rate cache, by reason",
+			},
+			[]string{"reason"},
+		),
+
+		SnapshotVerificationFailures: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "cache_snapshot_verification_failures_total",
+				Help: "Total number of cache snapshots rejected for a bad signature or failed decryption",
+			},
+		),
+
+		ProviderRateLimited: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_rate_limited",
+				Help: "1 while refreshes are paused because the provider rate limited them, by provider",
+			},
+			[]string{"provider"},
+		),
+		ProviderUsage: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_upstream_calls",
+				Help: "Calls made to a provider in the current hour, UTC day or last 30 days, by provider and period",
+			},
+			[]string{"provider", "period"},
+		),
+		ProviderBudgetUsage: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_budget_used_percent",
+				Help: "Share of the monthly provider quota used by the calls of the last 30 days, by provider",
+			},
+			[]string{"provider"},
+		),
+		ProviderCircuitState: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_circuit_state",
+				Help: "State of the circuit breaker around a provider: 0 closed, 1 half open, 2 open, by provider",
+			},
+			[]string{"provider"},
+		),
+		ProviderRateSpread: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_rate_spread_percent",
+				Help: "Spread between the highest and lowest rate the providers hold, in percent, by pair",
+			},
+			[]string{"pair"},
+		),
+		ProviderRateDiscrepancy: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "provider_rate_discrepancy",
+				Help: "1 while the providers disagree on the rate beyond the discrepancy threshold, by pair",
+			},
+			[]string{"pair"},
+		),
+	}
+}
+
+// ObserveRateLimit records whether refreshes are paused for rate limiting
+func (m *Metrics) ObserveRateLimit(limit model.ProviderRateLimit) {
+	m.ProviderRateLimited.Reset()
+	if limit.Limited {
+		m.ProviderRateLimited.WithLabelValues(limit.Provider).Set(1)
+	}
+}
+
+// ObserveProviderUsage records the calls made to each provider
+func (m *Metrics) ObserveProviderUsage(usage []model.ProviderUsage) {
+	for _, provider := range usage {
+		m.ProviderUsage.WithLabelValues(provider.Provider, "hour").Set(float64(provider.ThisHour))
+		m.ProviderUsage.WithLabelValues(provider.Provider, "day").Set(float64(provider.Today))
+		m.ProviderUsage.WithLabelValues(provider.Provider, "30d").Set(float64(provider.Last30Days))
+		if provider.Budget != nil {
+			m.ProviderBudgetUsage.WithLabelValues(provider.Provider).Set(provider.Budget.UsedPercent)
+		}
+	}
+}
+
+// circuitStates are the provider_circuit_state values of the breaker states
+var circuitStates = map[string]float64{"closed": 0, "half_open": 1, "open": 2}
+
+// ObserveCircuitState records the state the circuit of provider moved to
+func (m *Metrics) ObserveCircuitState(provider, state string) {
+	m.ProviderCircuitState.WithLabelValues(provider).Set(circuitStates[state])
+}
+
+// ObserveHistoryGaps records the completeness figures of a gap report
+func (m *Metrics) ObserveHistoryGaps(report *model.HistoryGapReport) {
+	for _, pc := range report.Pairs {
+		pair := model.CurrencyPair{BaseCurrency: pc.BaseCurrency, TargetCurrency: pc.TargetCurrency}
+		m.HistoryPairCompleteness.WithLabelValues(pair.String()).Set(pc.Completeness)
+	}
+
+	if len(report.Pairs) > 1 {
+		m.HistoryCompleteness.Set(report.Completeness)
+	}
+}
+
+// ObserveCacheStats records the figures of a rate cache stats report
+func (m *Metrics) ObserveCacheStats(stats model.CacheStats) {
+	m.CacheEntries.Set(float64(stats.Entries))
+	m.CacheEstimatedBytes.Set(float64(stats.EstimatedBytes))
+	// Namespaces come and go with tenants, so drop those no longer reported
+	m.CacheNamespaceBytes.Reset()
+	for _, namespace := range stats.Namespaces {
+		m.CacheNamespaceBytes.WithLabelValues(namespace.Namespace).Set(float64(namespace.EstimatedBytes))
+	}
+	for _, ratio := range stats.HitRatios {
+		m.CacheHitRatio.WithLabelValues(ratio.Window).Set(ratio.Ratio)
+	}
+
+	m.cacheMutex.Lock()
+	defer m.cacheMutex.Unlock()
+
+	if m.cacheEvictions == nil {
+		m.cacheEvictions = make(map[string]int64)
+	}
+	for reason, total := range stats.Evictions {
+		if delta := total - m.cacheEvictions[reason]; delta > 0 {
+			m.CacheEvictions.WithLabelValues(reason).Add(float64(delta))
+		}
+		m.cacheEvictions[reason] = total
 	}
 }
@@ -0,0 +1,159 @@
+package refresh
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/utils"
+)
+
+// ErrInvalidInterval is returned for intervals that are not positive or that
+// would exceed the provider quota
+var ErrInvalidInterval = errors.New("invalid refresh interval")
+
+const (
+	// quotaPeriod is the period provider quotas are counted over
+	quotaPeriod = 30 * 24 * time.Hour
+	// minInterval applies when the provider has no quota
+	minInterval = 10 * time.Second
+)
+
+// QuotaFloor returns the shortest refresh interval that keeps the provider
+// calls of refreshes and of intraday sampling, when intradayInterval is not 0,
+// within monthlyQuota calls per 30 days. A monthlyQuota of 0 means unlimited.
+func QuotaFloor(monthlyQuota int, intradayInterval time.Duration) (time.Duration, error) {
+	if monthlyQuota <= 0 {
+		return minInterval, nil
+	}
+
+	budget := int64(monthlyQuota)
+	if intradayInterval > 0 {
+		budget -= int64(quotaPeriod / intradayInterval)
+	}
+	if budget <= 0 {
+		return 0, fmt.Errorf("intraday sampling every %s alone uses the quota of %d calls", intradayInterval, monthlyQuota)
+	}
+
+	floor := (quotaPeriod + time.Duration(budget) - 1) / time.Duration(budget)
+	return max(floor, minInterval), nil
+}
+
+type persistedSchedule struct {
+	Interval  string    `json:"interval"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Schedule holds the refresh interval, which admins may change at runtime.
+// A changed interval is written to a file and outlives restarts.
+type Schedule struct {
+	path  string
+	floor time.Duration
+	log   *logger.Logger
+
+	mutex     sync.Mutex
+	interval  time.Duration
+	updatedAt time.Time
+	changed   chan struct{}
+}
+
+// NewSchedule starts from the interval saved at path, if any and still within
+// floor, or else from configured, which must be within floor
+func NewSchedule(configured, floor time.Duration, path string, log *logger.Logger) (*Schedule, error) {
+	if configured < floor {
+		return nil, fmt.Errorf("%w: %s is shorter than the %s the provider quota allows", ErrInvalidInterval, configured, floor)
+	}
+
+	s := &Schedule{
+		path:     path,
+		floor:    floor,
+		log:      log,
+		interval: configured,
+		changed:  make(chan struct{}, 1),
+	}
+
+	var saved *persistedSchedule
+	if err := utils.ReadJSONFile(path, &saved); err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		return s, nil
+	}
+
+	interval, err := time.ParseDuration(saved.Interval)
+	switch {
+	case err != nil:
+		log.Warn("Ignoring saved refresh interval", "path", path, "error", err)
+	case interval < floor:
+		log.Warn("Ignoring saved refresh interval below the quota floor", "interval", interval.String(), "min_interval", floor.String())
+	default:
+		s.interval = interval
+		s.updatedAt = saved.UpdatedAt
+		log.Info("Using saved refresh interval", "interval", interval.String())
+	}
+	return s, nil
+}
+
+// Interval returns the current refresh interval
+func (s *Schedule) Interval() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.interval
+}
+
+// Changed receives a value whenever the interval changes
+func (s *Schedule) Changed() <-chan struct{} {
+	return s.changed
+}
+
+// Current describes the schedule
+func (s *Schedule) Current() model.RefreshSchedule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current := model.RefreshSchedule{
+		Interval:    s.interval.String(),
+		MinInterval: s.floor.String(),
+		Source:      "config",
+	}
+	if !s.updatedAt.IsZero() {
+		updatedAt := s.updatedAt
+		current.Source = "admin"
+		current.UpdatedAt = &updatedAt
+	}
+	return current
+}
+
+// SetInterval validates, saves and applies a new interval. The running
+// refresh loop picks it up through Changed.
+func (s *Schedule) SetInterval(interval time.Duration) (model.RefreshSchedule, error) {
+	if interval <= 0 {
+		return model.RefreshSchedule{}, fmt.Errorf("%w: must be positive", ErrInvalidInterval)
+	}
+	if interval < s.floor {
+		return model.RefreshSchedule{}, fmt.Errorf("%w: %s is shorter than the %s the provider quota allows", ErrInvalidInterval, interval, s.floor)
+	}
+
+	s.mutex.Lock()
+	now := time.Now().UTC()
+	if err := utils.WriteJSONFile(s.path, persistedSchedule{Interval: interval.String(), UpdatedAt: now}); err != nil {
+		s.mutex.Unlock()
+		return model.RefreshSchedule{}, err
+	}
+	previous := s.interval
+	s.interval = interval
+	s.updatedAt = now
+	s.mutex.Unlock()
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+
+	s.log.Info("Refresh interval changed", "from", previous.String(), "to", interval.String())
+	return s.Current(), nil
+}
@@ -0,0 +1,91 @@
+package refresh
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestQuotaFloor(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		quota    int
+		intraday time.Duration
+		want     time.Duration
+		wantErr  bool
+	}{
+		{"unlimited", 0, time.Minute, minInterval, false},
+		{"hourly fits 720 calls", 720, 0, time.Hour, false},
+		{"intraday takes its share", 1080, 2 * time.Hour, time.Hour, false},
+		{"intraday uses the whole quota", 720, time.Hour, 0, true},
+		{"never below the minimum", 10_000_000, 0, minInterval, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QuotaFloor(tt.quota, tt.intraday)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QuotaFloor error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("QuotaFloor = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleSetInterval(t *testing.T) {
+	log := logger.NewLogger("error")
+	path := filepath.Join(t.TempDir(), "refresh_interval.json")
+
+	schedule, err := NewSchedule(time.Hour, 10*time.Minute, path, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current := schedule.Current(); current.Interval != "1h0m0s" || current.Source != "config" {
+		t.Errorf("Unexpected initial schedule %+v", current)
+	}
+
+	for _, interval := range []time.Duration{0, -time.Minute, 5 * time.Minute} {
+		if _, err := schedule.SetInterval(interval); !errors.Is(err, ErrInvalidInterval) {
+			t.Errorf("SetInterval(%s) error = %v, want ErrInvalidInterval", interval, err)
+		}
+	}
+
+	updated, err := schedule.SetInterval(15 * time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Interval != "15m0s" || updated.Source != "admin" || updated.UpdatedAt == nil {
+		t.Errorf("Unexpected updated schedule %+v", updated)
+	}
+	select {
+	case <-schedule.Changed():
+	default:
+		t.Error("Expected a change notification")
+	}
+
+	restarted, err := NewSchedule(time.Hour, 10*time.Minute, path, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted.Interval() != 15*time.Minute {
+		t.Errorf("Expected the saved interval after a restart, got %s", restarted.Interval())
+	}
+
+	lowered, err := NewSchedule(time.Hour, 30*time.Minute, path, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lowered.Interval() != time.Hour {
+		t.Errorf("Expected a saved interval below the quota floor to be ignored, got %s", lowered.Interval())
+	}
+
+	if _, err := NewSchedule(time.Minute, 10*time.Minute, path, log); !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("Expected a configured interval below the floor to be refused, got %v", err)
+	}
+}
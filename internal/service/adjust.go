@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// UseRateAdjuster applies adjuster to every latest and historical rate served.
+// Cached, stored and published rates stay unadjusted.
+func (s *ExchangeService) UseRateAdjuster(adjuster ports.RateAdjuster) {
+	s.adjuster = adjuster
+}
+
+// adjust returns a copy of rate adjusted for the tenant of ctx, unless the
+// request asked for mid-rates. When the rate cache supports namespaces,
+// adjusted rates are kept in the tenant's own, and reused for as long as the
+// rate they were computed from is current.
+func (s *ExchangeService) adjust(ctx context.Context, rate *model.ExchangeRate) *model.ExchangeRate {
+	options := model.RequestOptionsFromContext(ctx)
+	if s.adjuster == nil || options.RateType == model.RateTypeMid {
+		return rate
+	}
+
+	tenant := model.TenantFromContext(ctx)
+	pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+	namespaced, _ := s.cache.(ports.NamespacedRateCache)
+	if options.Provider != "" {
+		// The namespaces hold rates adjusted from the primary provider's
+		namespaced = nil
+	}
+	if namespaced != nil {
+		// A refresh or push replaces LastUpdated, which the adjusted copy keeps
+		if cached, found := namespaced.GetIn(ctx, tenantNamespace(tenant), pair, rate.Date); found && cached.LastUpdated.Equal(rate.LastUpdated) {
+			adjusted := *cached
+			return &adjusted
+		}
+	}
+
+	adjusted := *rate
+	adjusted.Rate = s.adjuster.Adjust(tenant, pair, rate.Rate)
+	if namespaced != nil {
+		stored := adjusted
+		if err := namespaced.SetIn(ctx, tenantNamespace(tenant), &stored); err != nil {
+			s.log.Error("Failed to cache adjusted exchange rate", "error", err, "pair", pair.String())
+		}
+	}
+	return &adjusted
+}
+
+// tenantNamespace names the cache namespace of the rates adjusted for tenant.
+// Requests without an API key share one namespace, apart from the mid-rates.
+func tenantNamespace(tenant string) string {
+	if tenant == "" {
+		return "tenant:anonymous"
+	}
+	return "tenant:" + tenant
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+// namespacedCache serves one shared rate and keeps namespaced rates in a map
+type namespacedCache struct {
+	mocks.RateCacheMock
+	shared     *model.ExchangeRate
+	namespaces map[string]*model.ExchangeRate
+}
+
+func (c *namespacedCache) Get(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+	return c.shared, true
+}
+
+func (c *namespacedCache) GetIn(ctx context.Context, namespace string, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+	rate, found := c.namespaces[namespace]
+	return rate, found
+}
+
+func (c *namespacedCache) SetIn(ctx context.Context, namespace string, rate *model.ExchangeRate) error {
+	c.namespaces[namespace] = rate
+	return nil
+}
+
+func TestAdjustedRatesAreCachedPerTenant(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	cache := &namespacedCache{
+		shared:     &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 100, Date: today, LastUpdated: time.Now()},
+		namespaces: make(map[string]*model.ExchangeRate),
+	}
+	markup := &mocks.RateAdjusterMock{
+		AdjustFunc: func(tenant string, pair model.CurrencyPair, rate float64) float64 {
+			if tenant == "tenant-a" {
+				return rate * 1.02
+			}
+			return rate
+		},
+	}
+	svc := NewExchangeService(&mocks.RateRepositoryMock{}, WithCache(cache), WithLogger(logger.NewLogger("error")))
+	svc.UseRateAdjuster(markup)
+
+	latest := func(tenant string) float64 {
+		t.Helper()
+		rate, err := svc.GetLatestRate(model.WithTenant(context.Background(), tenant), model.USD, model.INR)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rate.Rate
+	}
+
+	if got := latest("tenant-a"); got != 102 {
+		t.Errorf("Expected tenant A's marked-up rate of 102, got %v", got)
+	}
+	if got := latest("tenant-b"); got != 100 {
+		t.Errorf("Expected tenant B to get the mid-rate, got %v", got)
+	}
+	if got := latest("tenant-a"); got != 102 || len(markup.AdjustCalls()) != 2 {
+		t.Errorf("Expected tenant A's rate from its namespace, got %v after %d adjustments", got, len(markup.AdjustCalls()))
+	}
+	if cache.shared.Rate != 100 || len(cache.namespaces) != 2 {
+		t.Errorf("Expected the mid-rate shared and one namespace per tenant, got %v and %v", cache.shared.Rate, cache.namespaces)
+	}
+
+	// A refresh replaces the shared rate, so the adjusted one is computed again
+	cache.shared = &model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 110, Date: today, LastUpdated: time.Now().Add(time.Second)}
+	if got := latest("tenant-a"); got != 112.2 || len(markup.AdjustCalls()) != 3 {
+		t.Errorf("Expected the refreshed rate adjusted again, got %v after %d adjustments", got, len(markup.AdjustCalls()))
+	}
+}
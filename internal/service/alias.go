@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// UseCurrencyAliases lets callers use legacy codes such as RMB, GBX or DEM.
+// Requests are served with the rates of the currencies they stand for.
+func (s *ExchangeService) UseCurrencyAliases(aliases model.CurrencyAliases) {
+	s.aliases = aliases
+}
+
+// aliasedRate restates a rate of the canonical pair as a rate of from and to
+func aliasedRate(rate *model.ExchangeRate, from, to model.Currency, factor float64) *model.ExchangeRate {
+	aliased := *rate
+	aliased.BaseCurrency = from
+	aliased.TargetCurrency = to
+	aliased.Rate = rate.Rate * factor
+	return &aliased
+}
+
+// getAliasedHistoricalRates serves a range for a pair with at least one alias
+func (s *ExchangeService) getAliasedHistoricalRates(ctx context.Context, request model.HistoricalRateRequest, pair model.CurrencyPair, factor float64) (*model.HistoricalRates, error) {
+	canonical := request
+	canonical.BaseCurrency = pair.BaseCurrency
+	canonical.TargetCurrency = pair.TargetCurrency
+
+	rates, err := s.GetHistoricalRates(ctx, canonical)
+	if err != nil {
+		return nil, err
+	}
+
+	rates.BaseCurrency = request.BaseCurrency
+	rates.TargetCurrency = request.TargetCurrency
+	for date, rate := range rates.Rates {
+		rates.Rates[date] = *aliasedRate(&rate, request.BaseCurrency, request.TargetCurrency, factor)
+	}
+	return rates, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+const maxAnnotationLength = 500
+
+func (s *ExchangeService) CreateAnnotation(ctx context.Context, annotation model.Annotation) (*model.Annotation, error) {
+	annotation.Text = strings.TrimSpace(annotation.Text)
+	if annotation.Text == "" || len(annotation.Text) > maxAnnotationLength {
+		return nil, fmt.Errorf("%w: text must be between 1 and %d characters", ErrInvalidAnnotation, maxAnnotationLength)
+	}
+
+	if (annotation.BaseCurrency == "") != (annotation.TargetCurrency == "") {
+		return nil, fmt.Errorf("%w: both currencies of the pair are required", ErrInvalidAnnotation)
+	}
+	if annotation.BaseCurrency != "" && (!annotation.BaseCurrency.IsSupported() || !annotation.TargetCurrency.IsSupported()) {
+		return nil, ErrInvalidCurrency
+	}
+
+	if annotation.BaseCurrency == "" && annotation.Date.IsZero() {
+		return nil, fmt.Errorf("%w: a pair, a date, or both are required", ErrInvalidAnnotation)
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	annotation.ID = "ann_" + hex.EncodeToString(b)
+	annotation.CreatedAt = s.clock.Now().UTC()
+	if !annotation.Date.IsZero() {
+		annotation.Date = annotation.Date.UTC().Truncate(24 * time.Hour)
+	}
+
+	if err := s.annotations.Create(ctx, &annotation); err != nil {
+		s.log.Error("Failed to store annotation", "error", err)
+		return nil, err
+	}
+
+	s.log.Info("Annotation created", "id", annotation.ID)
+	return &annotation, nil
+}
+
+func (s *ExchangeService) ListAnnotations(ctx context.Context, filter model.AnnotationFilter) ([]model.Annotation, error) {
+	if filter.BaseCurrency != "" && (!filter.BaseCurrency.IsSupported() || !filter.TargetCurrency.IsSupported()) {
+		return nil, ErrInvalidCurrency
+	}
+
+	return s.annotations.List(ctx, filter)
+}
+
+func (s *ExchangeService) DeleteAnnotation(ctx context.Context, id string) error {
+	deleted, err := s.annotations.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAnnotationNotFound
+	}
+
+	s.log.Info("Annotation deleted", "id", id)
+	return nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// Backtest replays a recurring conversion schedule against historical rates
+// and compares it with converting the same total at once on the first day.
+func (s *ExchangeService) Backtest(ctx context.Context, request model.BacktestRequest) (*model.Backtest, error) {
+	if request.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if !request.Interval.Valid() {
+		return nil, ErrInvalidSchedule
+	}
+
+	rates, err := s.GetHistoricalRates(ctx, model.HistoricalRateRequest{
+		BaseCurrency:   request.FromCurrency,
+		TargetCurrency: request.ToCurrency,
+		StartDate:      request.StartDate,
+		EndDate:        request.EndDate,
+		Fill:           model.FillPrevious,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	backtest := &model.Backtest{
+		FromCurrency: request.FromCurrency,
+		ToCurrency:   request.ToCurrency,
+		Amount:       request.Amount,
+		Interval:     request.Interval,
+		StartDate:    request.StartDate,
+		EndDate:      request.EndDate,
+		Conversions:  make([]model.BacktestConversion, 0),
+	}
+
+	rateSum := 0.0
+	for _, d := range scheduleDates(request.StartDate, request.EndDate, request.Interval) {
+		key := d.Format("2006-01-02")
+		rate, found := rates.Rates[key]
+		if !found {
+			backtest.MissingDates = append(backtest.MissingDates, key)
+			continue
+		}
+
+		amount := request.Amount * rate.Rate
+		backtest.Conversions = append(backtest.Conversions, model.BacktestConversion{
+			Date:   d,
+			Rate:   rate.Rate,
+			Amount: amount,
+			Filled: rate.Interpolated,
+		})
+		backtest.TotalInvested += request.Amount
+		backtest.Total += amount
+		rateSum += rate.Rate
+	}
+
+	if n := len(backtest.Conversions); n > 0 {
+		backtest.AverageRate = rateSum / float64(n)
+
+		first := backtest.Conversions[0]
+		backtest.LumpSum = &model.BacktestLumpSum{
+			Date:   first.Date,
+			Rate:   first.Rate,
+			Amount: backtest.TotalInvested * first.Rate,
+		}
+		backtest.Difference = backtest.Total - backtest.LumpSum.Amount
+		backtest.DifferencePercent = math.Round(backtest.Difference/backtest.LumpSum.Amount*100*1e4) / 1e4
+	}
+
+	return backtest, nil
+}
+
+// scheduleDates lists the days a conversion is made, starting with start
+func scheduleDates(start, end time.Time, interval model.BacktestInterval) []time.Time {
+	dates := make([]time.Time, 0)
+	for i := 0; ; i++ {
+		var d time.Time
+		switch interval {
+		case model.BacktestDaily:
+			d = start.AddDate(0, 0, i)
+		case model.BacktestWeekly:
+			d = start.AddDate(0, 0, 7*i)
+		case model.BacktestMonthly:
+			d = addMonthsClamped(start, i)
+		}
+		if d.After(end) {
+			return dates
+		}
+		dates = append(dates, d)
+	}
+}
+
+// addMonthsClamped adds months to t, keeping its day of the month unless the
+// target month is shorter, in which case its last day is used
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestScheduleDates(t *testing.T) {
+
+	date := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		interval model.BacktestInterval
+		expected []string
+	}{
+		{name: "Daily", start: date(1, 30), end: date(2, 1), interval: model.BacktestDaily, expected: []string{"2025-01-30", "2025-01-31", "2025-02-01"}},
+		{name: "Weekly", start: date(1, 1), end: date(1, 20), interval: model.BacktestWeekly, expected: []string{"2025-01-01", "2025-01-08", "2025-01-15"}},
+		{name: "Monthly", start: date(1, 1), end: date(3, 15), interval: model.BacktestMonthly, expected: []string{"2025-01-01", "2025-02-01", "2025-03-01"}},
+		{name: "Monthly Clamped", start: date(1, 31), end: date(4, 30), interval: model.BacktestMonthly, expected: []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"}},
+		{name: "Single Day", start: date(5, 5), end: date(5, 5), interval: model.BacktestMonthly, expected: []string{"2025-05-05"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			dates := scheduleDates(tc.start, tc.end, tc.interval)
+
+			if len(dates) != len(tc.expected) {
+				t.Fatalf("Expected %d dates, got %v", len(tc.expected), dates)
+			}
+			for i, d := range dates {
+				if got := d.Format("2006-01-02"); got != tc.expected[i] {
+					t.Errorf("Expected date %d: %s, got: %s", i, tc.expected[i], got)
+				}
+			}
+		})
+	}
+}
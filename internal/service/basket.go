@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+const (
+	maxBasketComponents = 10
+	maxBaskets          = 20
+	maxBasketNameLength = 100
+)
+
+// UseBaskets enables the user-defined currency baskets kept in store
+func (s *ExchangeService) UseBaskets(store ports.BasketStore) {
+	s.baskets = store
+}
+
+// CreateBasket stores a basket of tenant. The weights are normalized to sum to
+// one and turned into fixed units of each currency at the latest rates, so
+// that one unit of the basket is worth one unit of its base currency today.
+func (s *ExchangeService) CreateBasket(ctx context.Context, tenant string, basket model.Basket) (*model.Basket, error) {
+	if s.baskets == nil {
+		return nil, ErrBasketsDisabled
+	}
+
+	basket.Name = strings.TrimSpace(basket.Name)
+	if basket.Name == "" || len(basket.Name) > maxBasketNameLength {
+		return nil, fmt.Errorf("%w: name must be between 1 and %d characters", ErrInvalidBasket, maxBasketNameLength)
+	}
+	if basket.Base == "" {
+		basket.Base = model.USD
+	}
+	if len(basket.Components) < 2 || len(basket.Components) > maxBasketComponents {
+		return nil, fmt.Errorf("%w: a basket needs between 2 and %d currencies", ErrInvalidBasket, maxBasketComponents)
+	}
+
+	total := 0.0
+	seen := make(map[model.Currency]bool, len(basket.Components))
+	for _, component := range basket.Components {
+		if component.Weight <= 0 {
+			return nil, fmt.Errorf("%w: weight of %s must be positive", ErrInvalidBasket, component.Currency)
+		}
+		if seen[component.Currency] {
+			return nil, fmt.Errorf("%w: %s is listed twice", ErrInvalidBasket, component.Currency)
+		}
+		seen[component.Currency] = true
+		total += component.Weight
+	}
+
+	existing, err := s.baskets.List(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxBaskets {
+		return nil, fmt.Errorf("%w: the limit is %d baskets", ErrInvalidBasket, maxBaskets)
+	}
+
+	components := make([]model.BasketComponent, 0, len(basket.Components))
+	for _, component := range basket.Components {
+		rate, err := s.basketRate(ctx, basket.Base, component.Currency)
+		if err != nil {
+			return nil, err
+		}
+		weight := component.Weight / total
+		components = append(components, model.BasketComponent{
+			Currency: component.Currency,
+			Weight:   weight,
+			Units:    weight * rate,
+		})
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	created := model.Basket{
+		ID:         "bsk_" + hex.EncodeToString(b),
+		Tenant:     tenant,
+		Name:       basket.Name,
+		Base:       basket.Base,
+		Components: components,
+		CreatedAt:  s.clock.Now().UTC(),
+	}
+	if err := s.baskets.Create(ctx, &created); err != nil {
+		s.log.Error("Failed to store basket", "error", err)
+		return nil, err
+	}
+
+	s.log.Info("Basket created", "id", created.ID, "tenant", tenant)
+	return &created, nil
+}
+
+func (s *ExchangeService) ListBaskets(ctx context.Context, tenant string) ([]model.Basket, error) {
+	if s.baskets == nil {
+		return nil, ErrBasketsDisabled
+	}
+	return s.baskets.List(ctx, tenant)
+}
+
+func (s *ExchangeService) DeleteBasket(ctx context.Context, tenant, id string) error {
+	if s.baskets == nil {
+		return ErrBasketsDisabled
+	}
+
+	deleted, err := s.baskets.Delete(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBasketNotFound
+	}
+
+	s.log.Info("Basket deleted", "id", id, "tenant", tenant)
+	return nil
+}
+
+func (s *ExchangeService) basket(ctx context.Context, tenant, id string) (*model.Basket, error) {
+	if s.baskets == nil {
+		return nil, ErrBasketsDisabled
+	}
+
+	baskets, err := s.baskets.List(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	for i := range baskets {
+		if baskets[i].ID == id {
+			return &baskets[i], nil
+		}
+	}
+	return nil, ErrBasketNotFound
+}
+
+// basketRate is the latest rate from one currency to another, where a
+// currency is always worth one of itself
+func (s *ExchangeService) basketRate(ctx context.Context, from, to model.Currency) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	rate, err := s.GetLatestRate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return rate.Rate, nil
+}
+
+// QuoteBasket values one unit of a basket of tenant in currency at the latest rates
+func (s *ExchangeService) QuoteBasket(ctx context.Context, tenant, id string, currency model.Currency) (*model.BasketQuote, error) {
+	basket, err := s.basket(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &model.BasketQuote{
+		BasketID:   basket.ID,
+		Name:       basket.Name,
+		Currency:   currency,
+		Components: make([]model.BasketComponentValue, 0, len(basket.Components)),
+		Timestamp:  s.clock.Now().UTC(),
+	}
+	for _, component := range basket.Components {
+		rate, err := s.basketRate(ctx, component.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+		value := component.Units * rate
+		quote.Components = append(quote.Components, model.BasketComponentValue{
+			Currency: component.Currency,
+			Units:    component.Units,
+			Rate:     rate,
+			Value:    value,
+		})
+		quote.Value += value
+	}
+
+	return quote, nil
+}
+
+// ChartBasket values one unit of a basket of tenant in currency on every day
+// from start through end. Gaps in a component's history are filled with its
+// previous rate.
+func (s *ExchangeService) ChartBasket(ctx context.Context, tenant, id string, currency model.Currency, start, end time.Time) (*model.BasketChart, error) {
+	basket, err := s.basket(ctx, tenant, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateDateRange(start, end); err != nil {
+		return nil, err
+	}
+
+	histories := make([]map[string]model.ExchangeRate, len(basket.Components))
+	for i, component := range basket.Components {
+		if component.Currency == currency {
+			continue
+		}
+		rates, err := s.GetHistoricalRates(ctx, model.HistoricalRateRequest{
+			BaseCurrency:   component.Currency,
+			TargetCurrency: currency,
+			StartDate:      start,
+			EndDate:        end,
+			Fill:           model.FillPrevious,
+		})
+		if err != nil {
+			return nil, err
+		}
+		histories[i] = rates.Rates
+	}
+
+	chart := &model.BasketChart{
+		BasketID:  basket.ID,
+		Name:      basket.Name,
+		Currency:  currency,
+		StartDate: start,
+		EndDate:   end,
+		Points:    make([]model.BasketPoint, 0),
+	}
+
+days:
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		value := 0.0
+		for i, component := range basket.Components {
+			rate := 1.0
+			if histories[i] != nil {
+				r, found := histories[i][key]
+				if !found {
+					chart.MissingDates = append(chart.MissingDates, key)
+					continue days
+				}
+				rate = r.Rate
+			}
+			value += component.Units * rate
+		}
+		chart.Points = append(chart.Points, model.BasketPoint{Date: d, Value: value})
+	}
+
+	return chart, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestExchangeService_Baskets(t *testing.T) {
+
+	rates := map[model.CurrencyPair]float64{
+		{BaseCurrency: model.USD, TargetCurrency: model.EUR}: 0.9,
+		{BaseCurrency: model.USD, TargetCurrency: model.JPY}: 150,
+		{BaseCurrency: model.USD, TargetCurrency: model.INR}: 83,
+		{BaseCurrency: model.EUR, TargetCurrency: model.INR}: 90,
+		{BaseCurrency: model.JPY, TargetCurrency: model.INR}: 0.55,
+	}
+	repository := &mocks.RateRepositoryMock{
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: rates[pair]}, nil
+		},
+	}
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return nil, false
+		},
+		SetFunc: func(ctx context.Context, rate *model.ExchangeRate) error { return nil },
+	}
+	svc := NewExchangeService(repository, WithCache(cache), WithLogger(logger.NewLogger("error")))
+	ctx := context.Background()
+
+	if _, err := svc.CreateBasket(ctx, "tenant-a", model.Basket{Name: "Mine"}); !errors.Is(err, ErrBasketsDisabled) {
+		t.Fatalf("Expected ErrBasketsDisabled without a store, got %v", err)
+	}
+	var stored []model.Basket
+	svc.UseBaskets(&mocks.BasketStoreMock{
+		CreateFunc: func(ctx context.Context, basket *model.Basket) error {
+			stored = append(stored, *basket)
+			return nil
+		},
+		ListFunc: func(ctx context.Context, tenant string) ([]model.Basket, error) {
+			result := make([]model.Basket, 0)
+			for _, basket := range stored {
+				if basket.Tenant == tenant {
+					result = append(result, basket)
+				}
+			}
+			return result, nil
+		},
+	})
+
+	invalid := []model.Basket{
+		{Name: "", Components: []model.BasketComponent{{Currency: model.USD, Weight: 1}, {Currency: model.EUR, Weight: 1}}},
+		{Name: "Single", Components: []model.BasketComponent{{Currency: model.USD, Weight: 1}}},
+		{Name: "Twice", Components: []model.BasketComponent{{Currency: model.USD, Weight: 1}, {Currency: model.USD, Weight: 1}}},
+		{Name: "Zero", Components: []model.BasketComponent{{Currency: model.USD, Weight: 1}, {Currency: model.EUR}}},
+	}
+	for _, basket := range invalid {
+		if _, err := svc.CreateBasket(ctx, "tenant-a", basket); !errors.Is(err, ErrInvalidBasket) {
+			t.Errorf("Expected ErrInvalidBasket for %+v, got %v", basket, err)
+		}
+	}
+
+	basket, err := svc.CreateBasket(ctx, "tenant-a", model.Basket{
+		Name:       "Majors",
+		Components: []model.BasketComponent{{Currency: model.USD, Weight: 50}, {Currency: model.EUR, Weight: 30}, {Currency: model.JPY, Weight: 20}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedUnits := []float64{0.5, 0.27, 30}
+	for i, component := range basket.Components {
+		if math.Abs(component.Units-expectedUnits[i]) > 1e-9 {
+			t.Errorf("Expected %v units of %s, got %v", expectedUnits[i], component.Currency, component.Units)
+		}
+	}
+
+	quote, err := svc.QuoteBasket(ctx, "tenant-a", basket.ID, model.INR)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(quote.Value-82.3) > 1e-9 {
+		t.Errorf("Expected the basket to be worth 82.3 INR, got %v", quote.Value)
+	}
+
+	if _, err := svc.QuoteBasket(ctx, "tenant-b", basket.ID, model.INR); !errors.Is(err, ErrBasketNotFound) {
+		t.Errorf("Expected another tenant's basket to be hidden, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// TravelBudget converts a daily budget for every day of a range at that day's
+// historical rate. Days without a rate reuse the previous day's.
+func (s *ExchangeService) TravelBudget(ctx context.Context, request model.TravelBudgetRequest) (*model.TravelBudget, error) {
+	if request.DailyAmount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	rates, err := s.GetHistoricalRates(ctx, model.HistoricalRateRequest{
+		BaseCurrency:   request.HomeCurrency,
+		TargetCurrency: request.DestinationCurrency,
+		StartDate:      request.StartDate,
+		EndDate:        request.EndDate,
+		Fill:           model.FillPrevious,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	budget := &model.TravelBudget{
+		HomeCurrency:        request.HomeCurrency,
+		DestinationCurrency: request.DestinationCurrency,
+		DailyAmount:         request.DailyAmount,
+		StartDate:           request.StartDate,
+		EndDate:             request.EndDate,
+		Days:                make([]model.TravelBudgetDay, 0),
+	}
+
+	rateSum := 0.0
+	for d := request.StartDate; !d.After(request.EndDate); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		rate, found := rates.Rates[key]
+		if !found {
+			budget.MissingDates = append(budget.MissingDates, key)
+			continue
+		}
+
+		amount := request.DailyAmount * rate.Rate
+		budget.Days = append(budget.Days, model.TravelBudgetDay{
+			Date:   d,
+			Rate:   rate.Rate,
+			Amount: amount,
+			Filled: rate.Interpolated,
+		})
+		budget.TotalHome += request.DailyAmount
+		budget.Total += amount
+		rateSum += rate.Rate
+	}
+
+	if n := len(budget.Days); n > 0 {
+		budget.AverageRate = rateSum / float64(n)
+		budget.AverageDailyAmount = budget.Total / float64(n)
+	}
+
+	return budget, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestExchangeService_TravelBudget(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := func(daysAgo int) time.Time { return today.AddDate(0, 0, -daysAgo) }
+
+	// No rate five days ago, the first day of the trip, nor three days ago
+	published := map[string]float64{
+		day(4).Format("2006-01-02"): 80,
+		day(2).Format("2006-01-02"): 84,
+		day(1).Format("2006-01-02"): 82,
+	}
+	repository := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+		FetchHistoricalRatesFunc: func(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+			rates := &model.HistoricalRates{BaseCurrency: request.BaseCurrency, TargetCurrency: request.TargetCurrency, Rates: make(map[string]model.ExchangeRate)}
+			for d := request.StartDate; !d.After(request.EndDate); d = d.AddDate(0, 0, 1) {
+				if rate, exists := published[d.Format("2006-01-02")]; exists {
+					rates.Rates[d.Format("2006-01-02")] = model.ExchangeRate{BaseCurrency: request.BaseCurrency, TargetCurrency: request.TargetCurrency, Rate: rate, Date: d}
+				}
+			}
+			return rates, nil
+		},
+	}
+	svc := NewExchangeService(repository, WithLogger(logger.NewLogger("error")))
+
+	budget, err := svc.TravelBudget(context.Background(), model.TravelBudgetRequest{
+		HomeCurrency:        model.USD,
+		DestinationCurrency: model.INR,
+		DailyAmount:         100,
+		StartDate:           day(5),
+		EndDate:             day(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(budget.MissingDates) != 1 || budget.MissingDates[0] != day(5).Format("2006-01-02") {
+		t.Errorf("Expected only the day before any rate to be missing, got %v", budget.MissingDates)
+	}
+	want := []struct {
+		rate   float64
+		filled bool
+	}{{80, false}, {80, true}, {84, false}, {82, false}}
+	if len(budget.Days) != len(want) {
+		t.Fatalf("Expected %d days, got %+v", len(want), budget.Days)
+	}
+	for i, w := range want {
+		if got := budget.Days[i]; got.Rate != w.rate || got.Filled != w.filled || got.Amount != 100*w.rate {
+			t.Errorf("Expected day %d at %v (filled %v), got %+v", i, w.rate, w.filled, got)
+		}
+	}
+	if budget.TotalHome != 400 || budget.Total != 32600 {
+		t.Errorf("Expected totals of 400 USD and 32600 INR, got %v and %v", budget.TotalHome, budget.Total)
+	}
+	if math.Abs(budget.AverageRate-81.5) > 1e-9 || math.Abs(budget.AverageDailyAmount-8150) > 1e-9 {
+		t.Errorf("Expected averages of 81.5 and 8150, got %v and %v", budget.AverageRate, budget.AverageDailyAmount)
+	}
+
+	if _, err := svc.TravelBudget(context.Background(), model.TravelBudgetRequest{HomeCurrency: model.USD, DestinationCurrency: model.INR, StartDate: day(2), EndDate: day(1)}); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected a budget without a daily amount to be rejected, got %v", err)
+	}
+	if _, err := svc.TravelBudget(context.Background(), model.TravelBudgetRequest{HomeCurrency: model.USD, DestinationCurrency: model.INR, DailyAmount: 1, StartDate: day(1), EndDate: day(2)}); !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("Expected a reversed range to be rejected, got %v", err)
+	}
+}
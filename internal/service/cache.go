@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// CacheStats reports the contents and hit ratios of the rate cache
+func (s *ExchangeService) CacheStats(ctx context.Context) model.CacheStats {
+	return s.cache.Stats(ctx)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"time"
+
+	"exchange-rate-service/pkg/clock"
+)
+
+// UseClock replaces the wall clock the service reads today's date, history
+// windows and timestamps from
+func (s *ExchangeService) UseClock(c clock.Clock) {
+	s.clock = c
+}
+
+// today returns the start of the current UTC day
+func (s *ExchangeService) today() time.Time {
+	return s.clock.Now().UTC().Truncate(24 * time.Hour)
+}
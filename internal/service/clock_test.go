@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestExchangeService_HistoryLimitFollowsClock(t *testing.T) {
+	now := time.Date(2025, 6, 1, 15, 30, 0, 0, time.UTC)
+	fake := clock.NewFake(now)
+	oldest := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 83, Date: date}, true
+		},
+	}
+	service := NewExchangeService(&mocks.RateRepositoryMock{}, WithCache(cache), WithLogger(logger.NewLogger("error")))
+	service.UseClock(fake)
+
+	if _, err := service.GetHistoricalRate(context.Background(), model.USD, model.INR, oldest); err != nil {
+		t.Fatalf("Expected the date %d days back to be allowed, got %v", model.HistoryRetentionDays, err)
+	}
+	if _, err := service.GetHistoricalRate(context.Background(), model.USD, model.INR, oldest.AddDate(0, 0, -1)); !errors.Is(err, ErrDateOutOfRange) {
+		t.Errorf("Expected ErrDateOutOfRange a day earlier, got %v", err)
+	}
+
+	fake.Advance(24 * time.Hour)
+	if _, err := service.GetHistoricalRate(context.Background(), model.USD, model.INR, oldest); !errors.Is(err, ErrDateOutOfRange) {
+		t.Errorf("Expected the date to fall out of range a day later, got %v", err)
+	}
+}
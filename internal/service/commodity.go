@@ -0,0 +1,30 @@
+package service
+
+import (
+	"fmt"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// conversionUnitSizes returns how many quote units of the source and of the
+// target one request.Unit is. The unit applies to each commodity side it
+// measures, and must measure at least one.
+func conversionUnitSizes(request model.ConversionRequest) (from, to float64, err error) {
+	from, to = 1, 1
+	if request.Unit == "" {
+		return from, to, nil
+	}
+
+	fromSize, fromMeasured := request.FromCurrency.UnitSize(request.Unit)
+	toSize, toMeasured := request.ToCurrency.UnitSize(request.Unit)
+	if !fromMeasured && !toMeasured {
+		return 0, 0, fmt.Errorf("%w: %s does not measure %s or %s", ErrInvalidUnit, request.Unit, request.FromCurrency, request.ToCurrency)
+	}
+	if fromMeasured {
+		from = fromSize
+	}
+	if toMeasured {
+		to = toSize
+	}
+	return from, to, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestConversionUnitSizes(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		request      model.ConversionRequest
+		expectedFrom float64
+		expectedTo   float64
+		expectedErr  error
+	}{
+		{name: "No Unit", request: model.ConversionRequest{FromCurrency: "XAU", ToCurrency: model.USD}, expectedFrom: 1, expectedTo: 1},
+		{name: "Kilograms Of Gold", request: model.ConversionRequest{FromCurrency: "XAU", ToCurrency: model.INR, Unit: model.Kilogram}, expectedFrom: 32.150746568627985, expectedTo: 1},
+		{name: "Liters Of Oil Bought", request: model.ConversionRequest{FromCurrency: model.EUR, ToCurrency: "BRN", Unit: model.Liter}, expectedFrom: 1, expectedTo: 0.006289810770432105},
+		{name: "Grams Between Metals", request: model.ConversionRequest{FromCurrency: "XAU", ToCurrency: "XAG", Unit: model.Gram}, expectedFrom: 0.03215074656862798, expectedTo: 0.03215074656862798},
+		{name: "Grams Of Gold Into Oil", request: model.ConversionRequest{FromCurrency: "XAU", ToCurrency: "WTI", Unit: model.Gram}, expectedFrom: 0.03215074656862798, expectedTo: 1},
+		{name: "Unit Of A Currency", request: model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Unit: model.Gram}, expectedErr: ErrInvalidUnit},
+		{name: "Wrong Unit", request: model.ConversionRequest{FromCurrency: "WTI", ToCurrency: model.USD, Unit: model.Gram}, expectedErr: ErrInvalidUnit},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			from, to, err := conversionUnitSizes(tc.request)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if math.Abs(from-tc.expectedFrom) > 1e-12 || math.Abs(to-tc.expectedTo) > 1e-12 {
+				t.Errorf("Expected sizes %v and %v, got %v and %v", tc.expectedFrom, tc.expectedTo, from, to)
+			}
+		})
+	}
+}
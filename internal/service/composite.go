@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// quoteFetcher returns the provider's rate of a pair
+type quoteFetcher func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error)
+
+// getCompositeRate serves a pair with at least one composite unit or
+// cryptocurrency. A unit the provider quotes is used as quoted; any other is
+// valued from its components and the rate is flagged as derived. Results are
+// cached like any other rate.
+func (s *ExchangeService) getCompositeRate(ctx context.Context, from, to model.Currency, date time.Time, fetch quoteFetcher) (*model.ExchangeRate, error) {
+	for _, c := range []model.Currency{from, to} {
+		if !c.IsComposite() && !s.isCrypto(c) && !s.acceptsCurrency(c) {
+			return nil, ErrInvalidCurrency
+		}
+	}
+	pair := model.CurrencyPair{BaseCurrency: from, TargetCurrency: to}
+	if !s.currencies.AllowsPair(pair) {
+		return nil, ErrPairNotAvailable
+	}
+
+	if rate, found := s.cache.Get(ctx, pair, date); found && !s.tooOld(ctx, rate) {
+		s.noteMeta(ctx, s.repositoryFor(pair), true, rate)
+		return s.adjust(ctx, rate), nil
+	}
+
+	fromUSD, fromDerived, err := s.usdValue(ctx, from, fetch)
+	if err != nil {
+		return nil, err
+	}
+	toUSD, toDerived, err := s.usdValue(ctx, to, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := &model.ExchangeRate{
+		BaseCurrency:   from,
+		TargetCurrency: to,
+		Rate:           fromUSD / toUSD,
+		Date:           date,
+		LastUpdated:    s.clock.Now(),
+		Derived:        fromDerived || toDerived,
+		Unit:           pair.QuoteUnit(),
+	}
+	s.noteMeta(ctx, s.repositoryFor(pair), false, rate)
+	if err := s.cache.Set(ctx, rate); err != nil {
+		s.log.Error("Failed to cache composite exchange rate", "error", err, "pair", pair.String())
+	}
+
+	return s.adjust(ctx, rate), nil
+}
+
+// usdValue is what one unit of c is worth in USD. derived reports whether c
+// is a composite unit that had to be valued from its components.
+func (s *ExchangeService) usdValue(ctx context.Context, c model.Currency, fetch quoteFetcher) (value float64, derived bool, err error) {
+	if c == model.USD {
+		return 1, false, nil
+	}
+
+	rate, err := fetch(ctx, model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: c})
+	if err == nil {
+		return 1 / rate.Rate, false, nil
+	}
+	unit, composite := model.CompositeUnits[c]
+	if !composite || !errors.Is(err, ports.ErrQuoteNotFound) {
+		return 0, false, fetchError(err)
+	}
+
+	for component, amount := range unit.Components {
+		componentValue, _, err := s.usdValue(ctx, component, fetch)
+		if err != nil {
+			return 0, false, err
+		}
+		value += amount * componentValue
+	}
+	return value, true, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestExchangeService_CompositeRates(t *testing.T) {
+
+	testCases := []struct {
+		name            string
+		quotes          map[model.Currency]float64
+		from            model.Currency
+		to              model.Currency
+		expectedRate    float64
+		expectedDerived bool
+	}{
+		{
+			name:            "Derived From Components",
+			quotes:          map[model.Currency]float64{model.EUR: 0.9, "CNY": 7.2, model.JPY: 150, model.GBP: 0.8},
+			from:            "XDR",
+			to:              model.USD,
+			expectedRate:    1.3369002777777776,
+			expectedDerived: true,
+		},
+		{
+			name:            "Derived Inverse",
+			quotes:          map[model.Currency]float64{model.EUR: 0.9, "CNY": 7.2, model.JPY: 150, model.GBP: 0.8},
+			from:            model.EUR,
+			to:              "XDR",
+			expectedRate:    (1 / 0.9) / 1.3369002777777776,
+			expectedDerived: true,
+		},
+		{
+			name:            "Quoted By Provider",
+			quotes:          map[model.Currency]float64{"XDR": 0.75, model.INR: 83},
+			from:            "XDR",
+			to:              model.INR,
+			expectedRate:    83 / 0.75,
+			expectedDerived: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			repository := &mocks.RateRepositoryMock{
+				FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+					quote, found := tc.quotes[pair.TargetCurrency]
+					if pair.BaseCurrency != model.USD || !found {
+						return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.TargetCurrency)
+					}
+					return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: quote}, nil
+				},
+			}
+			cache := &mocks.RateCacheMock{
+				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+					return nil, false
+				},
+				SetFunc: func(ctx context.Context, rate *model.ExchangeRate) error { return nil },
+			}
+			svc := NewExchangeService(repository, WithCache(cache), WithLogger(logger.NewLogger("error")))
+
+			rate, err := svc.GetLatestRate(context.Background(), tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(rate.Rate-tc.expectedRate) > 1e-9 {
+				t.Errorf("Expected rate %v, got %v", tc.expectedRate, rate.Rate)
+			}
+			if rate.Derived != tc.expectedDerived {
+				t.Errorf("Expected derived %v, got %v", tc.expectedDerived, rate.Derived)
+			}
+		})
+	}
+}
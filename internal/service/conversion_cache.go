@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// UseConversionCache answers a conversion identical to a recent one of the
+// same tenant with the recent result, including its receipt, instead of
+// converting and storing it again
+func (s *ExchangeService) UseConversionCache(results ports.ConversionCache) {
+	s.results = results
+}
+
+// conversionKey identifies a conversion by every input that shapes its
+// result. Latest conversions share a key until the cache entry expires. Dry
+// runs are never cached, so they need no part of it.
+func conversionKey(ctx context.Context, request model.ConversionRequest) string {
+	date := "latest"
+	if !request.Date.IsZero() {
+		date = request.Date.UTC().Format("2006-01-02")
+	}
+
+	return strings.Join([]string{
+		model.TenantFromContext(ctx),
+		model.RequestOptionsFromContext(ctx).String(),
+		string(request.FromCurrency),
+		string(request.ToCurrency),
+		strconv.FormatFloat(request.Amount, 'f', -1, 64),
+		date,
+		string(request.Unit),
+		strconv.FormatBool(request.CashRounding),
+	}, "|")
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+)
+
+type mapConversionCache map[string]model.ConversionResult
+
+func (c mapConversionCache) Get(ctx context.Context, key string) (*model.ConversionResult, bool) {
+	result, found := c[key]
+	return &result, found
+}
+
+func (c mapConversionCache) Set(ctx context.Context, key string, result *model.ConversionResult) {
+	c[key] = *result
+}
+
+func TestConvertCurrencyCachesResults(t *testing.T) {
+	var cacheLookups int
+	service := newPrecomputeService(&cacheLookups)
+	var saved int
+	service.conversions = &mocks.ConversionStoreMock{
+		SaveFunc: func(ctx context.Context, result *model.ConversionResult) error {
+			saved++
+			return nil
+		},
+	}
+	service.UseConversionCache(make(mapConversionCache))
+
+	request := model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100}
+	tenantA := model.WithTenant(context.Background(), "a")
+
+	first, err := service.ConvertCurrency(tenantA, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retried, err := service.ConvertCurrency(tenantA, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.ID != first.ID || cacheLookups != 1 || saved != 1 {
+		t.Errorf("Expected the retry to return receipt %s without converting again, got %s after %d rate lookups and %d receipts", first.ID, retried.ID, cacheLookups, saved)
+	}
+
+	other, _ := service.ConvertCurrency(model.WithTenant(context.Background(), "b"), request)
+	rounded := request
+	rounded.CashRounding = true
+	cash, _ := service.ConvertCurrency(tenantA, rounded)
+	if other.ID == first.ID || cash.ID == first.ID || saved != 3 {
+		t.Errorf("Expected other tenants and inputs to convert separately, got %d receipts", saved)
+	}
+}
+
+func TestConvertCurrencyNeverCachesDryRuns(t *testing.T) {
+	var cacheLookups int
+	service := newPrecomputeService(&cacheLookups)
+	service.UseConversionCache(&mocks.ConversionCacheMock{
+		GetFunc: func(ctx context.Context, key string) (*model.ConversionResult, bool) {
+			t.Error("Expected a dry run not to look up cached results")
+			return nil, false
+		},
+		SetFunc: func(ctx context.Context, key string, result *model.ConversionResult) {
+			t.Error("Expected a dry run not to be cached")
+		},
+	})
+
+	result, err := service.ConvertCurrency(context.Background(), model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100, DryRun: true})
+	if err != nil || !result.DryRun {
+		t.Fatalf("Expected a dry run result, got %+v, %v", result, err)
+	}
+}
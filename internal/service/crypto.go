@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// UseCryptoRepository serves model.Cryptocurrencies from repository, which
+// quotes them against USD. Pairs with a cryptocurrency are then valued in USD
+// like composite units, each side from the repository that quotes it.
+func (s *ExchangeService) UseCryptoRepository(repository ports.RateRepository) {
+	s.crypto = repository
+}
+
+// isCrypto reports whether c is a cryptocurrency the service can serve
+func (s *ExchangeService) isCrypto(c model.Currency) bool {
+	return s.crypto != nil && c.IsCrypto()
+}
+
+// valuedInUSD reports whether the pair of from and to is served by valuing
+// both sides in USD rather than by asking the provider for the pair
+func (s *ExchangeService) valuedInUSD(from, to model.Currency) bool {
+	return from.IsComposite() || to.IsComposite() || s.isCrypto(from) || s.isCrypto(to)
+}
+
+// repositoryFor returns the repository quoting pair: the crypto one for
+// pairs with a cryptocurrency, the provider for any other
+func (s *ExchangeService) repositoryFor(pair model.CurrencyPair) ports.RateRepository {
+	if s.isCrypto(pair.BaseCurrency) || s.isCrypto(pair.TargetCurrency) {
+		return s.crypto
+	}
+	return s.repository
+}
+
+// refreshCrypto refreshes the cryptocurrency prices. A failure only leaves
+// them to be fetched when asked for, so it is logged rather than returned.
+func (s *ExchangeService) refreshCrypto(ctx context.Context) {
+	if s.crypto == nil {
+		return
+	}
+	if err := s.crypto.RefreshRates(ctx); err != nil && !errors.Is(err, ports.ErrRatesUnchanged) {
+		s.log.Error("Failed to refresh cryptocurrency prices", "error", err, "provider", s.crypto.Name())
+	}
+}
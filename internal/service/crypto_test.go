@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestConvertCryptoThroughUSD(t *testing.T) {
+	quoteFrom := func(quotes map[model.Currency]float64) func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+		return func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			quote, found := quotes[pair.TargetCurrency]
+			if pair.BaseCurrency != model.USD || !found {
+				return nil, fmt.Errorf("%w: %s", ports.ErrQuoteNotFound, pair.TargetCurrency)
+			}
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: quote}, nil
+		}
+	}
+	fiat := &mocks.RateRepositoryMock{
+		FetchLatestRateFunc: quoteFrom(map[model.Currency]float64{model.INR: 85}),
+	}
+	crypto := &mocks.RateRepositoryMock{
+		FetchLatestRateFunc: quoteFrom(map[model.Currency]float64{"BTC": 1.0 / 60000, "ETH": 1.0 / 3000}),
+	}
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return nil, false
+		},
+		SetFunc: func(ctx context.Context, rate *model.ExchangeRate) error { return nil },
+	}
+	ctx := context.Background()
+
+	svc := NewExchangeService(fiat, WithCache(cache), WithLogger(logger.NewLogger("error")), WithCryptoRepository(crypto))
+	result, err := svc.ConvertCurrency(ctx, model.ConversionRequest{FromCurrency: "BTC", ToCurrency: model.INR, Amount: 0.5})
+	if err != nil {
+		t.Fatalf("ConvertCurrency failed: %v", err)
+	}
+	if math.Abs(result.ToAmount-0.5*60000*85) > 1e-6 {
+		t.Errorf("Expected BTC crossed with USD-INR, got %v", result.ToAmount)
+	}
+
+	rate, err := svc.GetLatestRate(ctx, "ETH", "BTC")
+	if err != nil || math.Abs(rate.Rate-0.05) > 1e-12 {
+		t.Errorf("Expected ETH-BTC at 0.05, got %v, %v", rate, err)
+	}
+
+	// Without a crypto provider, crypto symbols are not currencies
+	svc = NewExchangeService(fiat, WithCache(cache), WithLogger(logger.NewLogger("error")))
+	if _, err := svc.GetLatestRate(ctx, "BTC", model.INR); !errors.Is(err, ErrInvalidCurrency) {
+		t.Errorf("Expected ErrInvalidCurrency without a crypto provider, got %v", err)
+	}
+}
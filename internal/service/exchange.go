@@ -2,77 +2,239 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"exchange-rate-service/internal/domain/model"
 	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
 	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
 )
 
+// Errors of the service, classified for callers that react to the kind
+// rather than to each error
 var (
-	ErrInvalidCurrency    = errors.New("invalid currency")
-	ErrDateOutOfRange     = errors.New("date is outside allowed range (older than 90 days)")
-	ErrInvalidDateRange   = errors.New("invalid date range")
-	ErrRateNotFound       = errors.New("exchange rate not found")
-	ErrExternalAPIFailure = errors.New("external API failure")
-	ErrInvalidAmount      = errors.New("invalid amount")
+	ErrInvalidCurrency     = xerrors.New(xerrors.Validation, "invalid_currency", "invalid currency")
+	ErrDateOutOfRange      = xerrors.New(xerrors.Validation, "date_out_of_range", "date is outside allowed range (older than 90 days)")
+	ErrInvalidDateRange    = xerrors.New(xerrors.Validation, "invalid_date_range", "invalid date range")
+	ErrRateNotFound        = xerrors.New(xerrors.NotFound, "rate_not_found", "exchange rate not found")
+	ErrExternalAPIFailure  = xerrors.New(xerrors.Upstream, "external_api_failure", "external API failure")
+	ErrInvalidAmount       = xerrors.New(xerrors.Validation, "invalid_amount", "invalid amount")
+	ErrConversionNotFound  = xerrors.New(xerrors.NotFound, "conversion_not_found", "conversion not found")
+	ErrInvalidAnnotation   = xerrors.New(xerrors.Validation, "invalid_annotation", "invalid annotation")
+	ErrAnnotationNotFound  = xerrors.New(xerrors.NotFound, "annotation_not_found", "annotation not found")
+	ErrPairNotAvailable    = xerrors.New(xerrors.NotFound, "pair_not_available", "currency pair not available")
+	ErrNoSecondaryProvider = xerrors.New(xerrors.NotFound, "no_secondary_provider", "no secondary provider configured")
+	ErrUnknownProvider     = xerrors.New(xerrors.NotFound, "unknown_provider", "unknown provider")
+	ErrNoProviderChain     = xerrors.New(xerrors.NotFound, "no_provider_chain", "no provider chain configured")
+	ErrInvalidInterval     = xerrors.New(xerrors.Validation, "invalid_interval", "invalid candle interval")
+	ErrSparklinesDisabled  = xerrors.New(xerrors.NotFound, "sparklines_disabled", "sparklines are disabled")
+	ErrInvalidSchedule     = xerrors.New(xerrors.Validation, "invalid_schedule", "invalid conversion schedule")
+	ErrInvalidBasket       = xerrors.New(xerrors.Validation, "invalid_basket", "invalid basket")
+	ErrBasketNotFound      = xerrors.New(xerrors.NotFound, "basket_not_found", "basket not found")
+	ErrBasketsDisabled     = xerrors.New(xerrors.NotFound, "baskets_disabled", "baskets are disabled")
+	ErrInvalidUnit         = xerrors.New(xerrors.Validation, "invalid_unit", "invalid unit")
+	ErrInflationDisabled   = xerrors.New(xerrors.NotFound, "inflation_disabled", "inflation adjustment is disabled")
+	ErrInvalidInflation    = xerrors.New(xerrors.Validation, "invalid_inflation", "invalid inflation method")
+	ErrCPINotFound         = xerrors.New(xerrors.NotFound, "cpi_not_found", "CPI data not found")
+	ErrProviderRateLimited = xerrors.New(xerrors.RateLimited, "provider_rate_limited", "provider rate limited, refreshes paused")
+	ErrIncompleteRange     = xerrors.New(xerrors.NotFound, "incomplete_range", "historical range is incomplete")
+	ErrRangeTooLong        = xerrors.New(xerrors.Validation, "range_too_long", "date range is too long")
+	ErrStaleRate           = xerrors.New(xerrors.Upstream, "stale_rate", "no rate within the requested max age")
+	ErrBudgetExhausted     = xerrors.New(xerrors.RateLimited, "budget_exhausted", "monthly provider budget reserved for essential calls")
 )
 
+// RangeLimitError is ErrRangeTooLong with the limit that was exceeded
+type RangeLimitError struct {
+	MaxDays int
+}
+
+func (e *RangeLimitError) Error() string {
+	return fmt.Sprintf("%s (at most %d days)", ErrRangeTooLong.Error(), e.MaxDays)
+}
+
+func (e *RangeLimitError) Unwrap() error {
+	return ErrRangeTooLong
+}
+
 type ExchangeService struct {
-	repository ports.RateRepository
-	cache      ports.RateCache
-	log        *logger.Logger
+	repository  ports.RateRepository
+	secondary   ports.RateRepository
+	cache       ports.RateCache
+	conversions ports.ConversionStore
+	annotations ports.AnnotationStore
+	history     ports.HistoryStore
+	sla         ports.SLAStore
+	ticks       ports.TickStore
+	maxRange    int
+	currencies  currencyPolicy
+	publishers  []ports.RatePublisher
+	recent      ports.RecentRateStore
+	aliases     model.CurrencyAliases
+	adjuster    ports.RateAdjuster
+	baskets     ports.BasketStore
+	cpi         ports.CPISource
+	crypto      ports.RateRepository
+	precomputed *precomputedConversions
+	licenses    model.ProviderLicenses
+	results     ports.ConversionCache
+	popularity  *popularRefresh
+	rateLimit   rateLimitCooldown
+	budget      usageBudget
+	clock       clock.Clock
+	log         *logger.Logger
+}
+
+// NewExchangeService creates the service on a provider and a rate cache.
+// Everything else is optional and set with opts; stores that are not given
+// keep nothing.
+func NewExchangeService(repository ports.RateRepository, opts ...Option) *ExchangeService {
+	s := &ExchangeService{
+		repository:  repository,
+		cache:       noCache{},
+		conversions: noConversions{},
+		annotations: noAnnotations{},
+		history:     noHistory{},
+		sla:         noSLA{},
+		ticks:       noTicks{},
+		rateLimit:   rateLimitCooldown{fallback: defaultRateLimitCooldown},
+		clock:       clock.System,
+		log:         defaultLogger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// acceptsCurrency reports whether rates for c may be requested. Commodities
+// are always forwarded to the provider; in lenient mode so is any valid ISO
+// code.
+func (s *ExchangeService) acceptsCurrency(c model.Currency) bool {
+	if c.IsSupported() || c.IsCommodity() {
+		return true
+	}
+	return s.currencies.get().Matching == model.CurrencyMatchingLenient && c.IsValidCode()
+}
+
+// checkPair validates both currencies of a pair and the configured allow and deny lists
+func (s *ExchangeService) checkPair(from, to model.Currency) error {
+	if !s.acceptsCurrency(from) || !s.acceptsCurrency(to) {
+		return ErrInvalidCurrency
+	}
+	if !s.currencies.AllowsPair(model.CurrencyPair{BaseCurrency: from, TargetCurrency: to}) {
+		return ErrPairNotAvailable
+	}
+	return nil
 }
 
-func NewExchangeService(repository ports.RateRepository, cache ports.RateCache, log *logger.Logger) *ExchangeService {
-	return &ExchangeService{
-		repository: repository,
-		cache:      cache,
-		log:        log,
+// fetchError maps a repository error to the service error returned to callers
+func fetchError(err error) error {
+	if errors.Is(err, ports.ErrQuoteNotFound) {
+		return fmt.Errorf("%w: %w", ErrRateNotFound, err)
 	}
+	return fmt.Errorf("%w: %w", ErrExternalAPIFailure, err)
 }
 
 func (s *ExchangeService) GetLatestRate(ctx context.Context, from, to model.Currency) (*model.ExchangeRate, error) {
 
-	if !from.IsSupported() || !to.IsSupported() {
-		return nil, ErrInvalidCurrency
+	if pair, factor, aliased := s.aliases.Resolve(from, to); aliased {
+		rate, err := s.GetLatestRate(ctx, pair.BaseCurrency, pair.TargetCurrency)
+		if err != nil {
+			return nil, err
+		}
+		return aliasedRate(rate, from, to, factor), nil
+	}
+
+	if s.valuedInUSD(from, to) {
+		today := s.today()
+		return s.getCompositeRate(ctx, from, to, today, func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return s.repositoryFor(pair).FetchLatestRate(ctx, pair)
+		})
+	}
+
+	if err := s.checkPair(from, to); err != nil {
+		return nil, err
 	}
 
 	pair := model.CurrencyPair{
 		BaseCurrency:   from,
 		TargetCurrency: to,
 	}
+	s.recordRequest(ctx, pair)
+
+	// Rates of another provider are not cached, the cache holding the primary's
+	preferred, err := s.preferredProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if preferred != nil {
+		rate, err := preferred.FetchLatestRate(ctx, pair)
+		if err != nil {
+			s.log.ErrorContext(ctx, "Failed to fetch exchange rate", "error", err, "pair", pair.String(), "provider", preferred.Name())
+			return nil, fetchError(err)
+		}
+		s.noteMeta(ctx, preferred, false, rate)
+		return s.adjust(ctx, rate), nil
+	}
 
-	today := time.Now().UTC().Truncate(24 * time.Hour)
-	if rate, found := s.cache.Get(ctx, pair, today); found {
-		s.log.Info("Exchange rate found in cache", "pair", pair.String())
-		return rate, nil
+	today := s.today()
+	if rate, found := s.cache.Get(ctx, pair, today); found && !s.tooOld(ctx, rate) {
+		s.log.InfoContext(ctx, "Exchange rate found in cache", "pair", pair.String())
+		s.noteMeta(ctx, s.repository, true, rate)
+		return s.adjust(ctx, rate), nil
 	}
 
-	s.log.Info("Fetching exchange rate from repository", "pair", pair.String())
+	s.log.InfoContext(ctx, "Fetching exchange rate from repository", "pair", pair.String())
 	rate, err := s.repository.FetchLatestRate(ctx, pair)
 	if err != nil {
-		s.log.Error("Failed to fetch exchange rate", "error", err, "pair", pair.String())
-		return nil, fmt.Errorf("%w: %v", ErrExternalAPIFailure, err)
+		s.log.ErrorContext(ctx, "Failed to fetch exchange rate", "error", err, "pair", pair.String())
+		return nil, fetchError(err)
 	}
+	rate, err = s.freshLatestRate(ctx, pair, rate)
+	if err != nil {
+		return nil, err
+	}
+	s.noteMeta(ctx, s.repository, false, rate)
 
 	if err := s.cache.Set(ctx, rate); err != nil {
 		s.log.Error("Failed to cache exchange rate", "error", err, "pair", pair.String())
 
 	}
 
-	return rate, nil
+	return s.adjust(ctx, rate), nil
 }
 
 func (s *ExchangeService) GetHistoricalRate(ctx context.Context, from, to model.Currency, date time.Time) (*model.ExchangeRate, error) {
 
-	if !from.IsSupported() || !to.IsSupported() {
-		return nil, ErrInvalidCurrency
+	if pair, factor, aliased := s.aliases.Resolve(from, to); aliased {
+		rate, err := s.GetHistoricalRate(ctx, pair.BaseCurrency, pair.TargetCurrency, date)
+		if err != nil {
+			return nil, err
+		}
+		return aliasedRate(rate, from, to, factor), nil
+	}
+
+	normalizedDate := date.UTC().Truncate(24 * time.Hour)
+	if s.valuedInUSD(from, to) {
+		if err := s.validateDate(date); err != nil {
+			return nil, err
+		}
+		return s.getCompositeRate(ctx, from, to, normalizedDate, func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return s.repositoryFor(pair).FetchHistoricalRate(ctx, pair, normalizedDate)
+		})
+	}
+
+	if err := s.checkPair(from, to); err != nil {
+		return nil, err
 	}
 
-	if err := validateDate(date); err != nil {
+	if err := s.validateDate(date); err != nil {
 		return nil, err
 	}
 
@@ -81,14 +243,24 @@ func (s *ExchangeService) GetHistoricalRate(ctx context.Context, from, to model.
 		TargetCurrency: to,
 	}
 
-	normalizedDate := date.UTC().Truncate(24 * time.Hour)
 	if rate, found := s.cache.Get(ctx, pair, normalizedDate); found {
-		return rate, nil
+		s.noteMeta(ctx, s.repository, true, rate)
+		return s.adjust(ctx, rate), nil
+	}
+
+	if rate, found := s.history.Get(ctx, pair, normalizedDate); found {
+		s.noteMeta(ctx, s.repository, true, rate)
+		return s.adjust(ctx, rate), nil
 	}
 
 	rate, err := s.repository.FetchHistoricalRate(ctx, pair, normalizedDate)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExternalAPIFailure, err)
+		return nil, fetchError(err)
+	}
+	s.noteMeta(ctx, s.repository, false, rate)
+
+	if err := s.history.Save(ctx, []model.ExchangeRate{*rate}); err != nil {
+		s.log.Error("Failed to store historical exchange rate", "error", err)
 	}
 
 	if err := s.cache.Set(ctx, rate); err != nil {
@@ -96,43 +268,120 @@ func (s *ExchangeService) GetHistoricalRate(ctx context.Context, from, to model.
 		s.log.Error("Failed to cache historical exchange rate", "error", err)
 	}
 
-	return rate, nil
+	return s.adjust(ctx, rate), nil
 }
 
 func (s *ExchangeService) GetHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
 
-	if !request.BaseCurrency.IsSupported() || !request.TargetCurrency.IsSupported() {
-		return nil, ErrInvalidCurrency
+	if pair, factor, aliased := s.aliases.Resolve(request.BaseCurrency, request.TargetCurrency); aliased {
+		return s.getAliasedHistoricalRates(ctx, request, pair, factor)
 	}
 
-	if err := validateDateRange(request.StartDate, request.EndDate); err != nil {
+	if err := s.checkPair(request.BaseCurrency, request.TargetCurrency); err != nil {
 		return nil, err
 	}
 
-	rates, err := s.repository.FetchHistoricalRates(ctx, request)
+	if err := s.validateDateRange(request.StartDate, request.EndDate); err != nil {
+		return nil, err
+	}
+
+	rates, fetched, err := s.fetchHistoricalRange(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExternalAPIFailure, err)
+		return nil, fetchError(err)
+	}
+	s.noteMeta(ctx, s.repository, len(fetched) == 0, nil)
+
+	if len(fetched) > 0 {
+		if err := s.history.Save(ctx, fetched); err != nil {
+			s.log.Error("Failed to store historical exchange rates", "error", err)
+		}
+	}
+
+	// Filled rates are not the provider's, so they do not complete a strict range
+	if request.Strict {
+		if missing := missingDates(rates, request.StartDate, request.EndDate); len(missing) > 0 {
+			return nil, fmt.Errorf("%w: no rates on %s", ErrIncompleteRange, strings.Join(missing, ", "))
+		}
+	}
+
+	fillMissingDates(rates, request.StartDate, request.EndDate, request.Fill)
+	for date, rate := range rates.Rates {
+		rates.Rates[date] = *s.adjust(ctx, &rate)
+	}
+
+	annotations, err := s.annotations.List(ctx, model.AnnotationFilter{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		StartDate:      request.StartDate,
+		EndDate:        request.EndDate,
+	})
+	if err != nil {
+		s.log.Error("Failed to load annotations", "error", err)
+	} else if len(annotations) > 0 {
+		rates.Annotations = annotations
 	}
 
 	return rates, nil
 }
 
 func (s *ExchangeService) ConvertCurrency(ctx context.Context, request model.ConversionRequest) (*model.ConversionResult, error) {
+	request = requestRounding(ctx, request)
+	if request.DryRun {
+		options := model.RequestOptionsFromContext(ctx)
+		options.DryRun = true
+		ctx = model.WithRequestOptions(ctx, options)
+	}
+	// A cached result may be older than the max age allows, and dry runs are
+	// previews that must not push out the results retries are answered with
+	if s.results == nil || request.DryRun || model.RequestOptionsFromContext(ctx).MaxAge > 0 {
+		return s.convertCurrency(ctx, request)
+	}
+
+	key := conversionKey(ctx, request)
+	if result, found := s.results.Get(ctx, key); found {
+		return result, nil
+	}
+
+	result, err := s.convertCurrency(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	s.results.Set(ctx, key, result)
+	return result, nil
+}
+
+func (s *ExchangeService) convertCurrency(ctx context.Context, request model.ConversionRequest) (*model.ConversionResult, error) {
 
-	if !request.FromCurrency.IsSupported() || !request.ToCurrency.IsSupported() {
-		return nil, ErrInvalidCurrency
+	// Aliases are checked as the currencies they stand for; the rate lookups resolve them
+	pair, _, _ := s.aliases.Resolve(request.FromCurrency, request.ToCurrency)
+	if !s.valuedInUSD(pair.BaseCurrency, pair.TargetCurrency) {
+		if err := s.checkPair(pair.BaseCurrency, pair.TargetCurrency); err != nil {
+			return nil, err
+		}
 	}
 
 	if request.Amount <= 0 {
 		return nil, ErrInvalidAmount
 	}
 
+	if options := model.RequestOptionsFromContext(ctx); s.precomputed != nil && s.adjuster == nil && options.Provider == "" && options.MaxAge == 0 {
+		if result, found := s.precomputed.lookup(request, s.today()); found {
+			s.recordRequest(ctx, pair)
+			s.noteMeta(ctx, s.repository, true, result.RateSnapshot)
+			return s.completeConversion(ctx, result, request.DryRun), nil
+		}
+	}
+
+	fromSize, toSize, err := conversionUnitSizes(request)
+	if err != nil {
+		return nil, err
+	}
+
 	var rate *model.ExchangeRate
-	var err error
 
 	if !request.Date.IsZero() {
 
-		if err := validateDate(request.Date); err != nil {
+		if err := s.validateDate(request.Date); err != nil {
 			return nil, err
 		}
 		rate, err = s.GetHistoricalRate(ctx, request.FromCurrency, request.ToCurrency, request.Date)
@@ -145,15 +394,55 @@ func (s *ExchangeService) ConvertCurrency(ctx context.Context, request model.Con
 		return nil, err
 	}
 
-	convertedAmount := request.Amount * rate.Rate
+	// The snapshot keeps the rate per quote unit; Rate is per request.Unit
+	effectiveRate := rate.Rate * fromSize / toSize
+	convertedAmount := request.Amount * effectiveRate
+	snapshot := *rate
 
 	result := &model.ConversionResult{
 		FromCurrency: request.FromCurrency,
 		ToCurrency:   request.ToCurrency,
 		FromAmount:   request.Amount,
 		ToAmount:     convertedAmount,
-		Rate:         rate.Rate,
+		Rate:         effectiveRate,
 		Date:         rate.Date,
+		RateSnapshot: &snapshot,
+		Rounding:     model.RoundingNone,
+		Unit:         request.Unit,
+	}
+
+	// The exact amount stays in ToAmount; POS clients take the cash amount and book the delta
+	if request.CashRounding {
+		cash := model.RoundToCash(convertedAmount, request.ToCurrency)
+		result.Cash = &cash
+		result.Rounding = model.RoundingCash
+	}
+
+	return s.completeConversion(ctx, result, request.DryRun), nil
+}
+
+// completeConversion stamps result and, unless it is a dry run, stores its receipt
+func (s *ExchangeService) completeConversion(ctx context.Context, result *model.ConversionResult, dryRun bool) *model.ConversionResult {
+	result.CreatedAt = s.clock.Now().UTC()
+	result.DryRun = dryRun
+
+	// Dry runs are previews: they get no receipt and leave no trace in the store
+	if dryRun {
+		return result
+	}
+
+	result.ID = newConversionID()
+	if err := s.conversions.Save(ctx, result); err != nil {
+		s.log.Error("Failed to store conversion receipt", "error", err, "conversion_id", result.ID)
+	}
+
+	return result
+}
+
+func (s *ExchangeService) GetConversion(ctx context.Context, id string) (*model.ConversionResult, error) {
+	result, found := s.conversions.Get(ctx, id)
+	if !found {
+		return nil, ErrConversionNotFound
 	}
 
 	return result, nil
@@ -162,23 +451,57 @@ func (s *ExchangeService) ConvertCurrency(ctx context.Context, request model.Con
 func (s *ExchangeService) RefreshRates(ctx context.Context) error {
 	s.log.Info("Refreshing exchange rates")
 
-	err := s.repository.RefreshRates(ctx)
-	if err != nil {
+	if err := s.coolingDown(ctx); err != nil {
+		return err
+	}
+
+	var err error
+	pairs, partial := s.pairsDue(ctx)
+	switch {
+	case !partial:
+		err = s.repository.RefreshRates(ctx)
+	case len(pairs) == 0:
+		// Nothing to fetch, and so nothing new to publish
+		s.log.Info("No pairs requested recently, skipping the partial refresh")
+		err = ports.ErrRatesUnchanged
+	default:
+		err = s.repository.(ports.PairRefresher).RefreshPairs(ctx, pairs)
+	}
+	unchanged := errors.Is(err, ports.ErrRatesUnchanged)
+	if err != nil && !unchanged {
 		s.log.Error("Failed to refresh exchange rates", "error", err)
-		return fmt.Errorf("%w: %v", ErrExternalAPIFailure, err)
+		s.noteRateLimit(err)
+		return fmt.Errorf("%w: %w", ErrExternalAPIFailure, err)
 	}
 
+	s.refreshCrypto(ctx)
+
 	if err := s.cache.ClearExpired(ctx); err != nil {
 		s.log.Error("Failed to clear expired cache entries", "error", err)
 
 	}
 
+	// Subscribers received these rates with the refresh that first brought them
+	if len(s.publishers) > 0 && !unchanged {
+		rates := s.repository.LatestRates(ctx)
+		if partial {
+			rates = ratesOf(rates, pairs)
+		}
+		s.publish(ctx, rates)
+	}
+
 	return nil
 }
 
-func validateDate(date time.Time) error {
-	today := time.Now().UTC().Truncate(24 * time.Hour)
-	ninetyDaysAgo := today.AddDate(0, 0, -90)
+func newConversionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "conv_" + hex.EncodeToString(b)
+}
+
+func (s *ExchangeService) validateDate(date time.Time) error {
+	today := s.today()
+	ninetyDaysAgo := today.AddDate(0, 0, -model.HistoryRetentionDays)
 
 	if date.Before(ninetyDaysAgo) {
 		return ErrDateOutOfRange
@@ -187,13 +510,13 @@ func validateDate(date time.Time) error {
 	return nil
 }
 
-func validateDateRange(startDate, endDate time.Time) error {
+func (s *ExchangeService) validateDateRange(startDate, endDate time.Time) error {
 
-	if err := validateDate(startDate); err != nil {
+	if err := s.validateDate(startDate); err != nil {
 		return err
 	}
 
-	if err := validateDate(endDate); err != nil {
+	if err := s.validateDate(endDate); err != nil {
 		return err
 	}
 
@@ -201,5 +524,9 @@ func validateDateRange(startDate, endDate time.Time) error {
 		return ErrInvalidDateRange
 	}
 
+	if days := int(endDate.Sub(startDate).Hours()/24) + 1; s.maxRange > 0 && days > s.maxRange {
+		return &RangeLimitError{MaxDays: s.maxRange}
+	}
+
 	return nil
 }
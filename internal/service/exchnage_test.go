@@ -3,54 +3,16 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/domain/ports/mocks"
 	"exchange-rate-service/pkg/logger"
 )
 
-type MockRateCache struct {
-	GetFunc          func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool)
-	SetFunc          func(ctx context.Context, rate *model.ExchangeRate) error
-	ClearExpiredFunc func(ctx context.Context) error
-}
-
-func (m *MockRateCache) Get(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
-	return m.GetFunc(ctx, pair, date)
-}
-
-func (m *MockRateCache) Set(ctx context.Context, rate *model.ExchangeRate) error {
-	return m.SetFunc(ctx, rate)
-}
-
-func (m *MockRateCache) ClearExpired(ctx context.Context) error {
-	return m.ClearExpiredFunc(ctx)
-}
-
-type MockRateRepository struct {
-	FetchLatestRateFunc      func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error)
-	FetchHistoricalRateFunc  func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error)
-	FetchHistoricalRatesFunc func(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error)
-	RefreshRatesFunc         func(ctx context.Context) error
-}
-
-func (m *MockRateRepository) FetchLatestRate(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
-	return m.FetchLatestRateFunc(ctx, pair)
-}
-
-func (m *MockRateRepository) FetchHistoricalRate(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
-	return m.FetchHistoricalRateFunc(ctx, pair, date)
-}
-
-func (m *MockRateRepository) FetchHistoricalRates(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
-	return m.FetchHistoricalRatesFunc(ctx, request)
-}
-
-func (m *MockRateRepository) RefreshRates(ctx context.Context) error {
-	return m.RefreshRatesFunc(ctx)
-}
-
 func TestExchangeService_GetLatestRate(t *testing.T) {
 
 	log := logger.NewLogger("debug")
@@ -59,8 +21,9 @@ func TestExchangeService_GetLatestRate(t *testing.T) {
 		name           string
 		from           model.Currency
 		to             model.Currency
-		mockCache      MockRateCache
-		mockRepository MockRateRepository
+		matching       model.CurrencyMatching
+		mockCache      *mocks.RateCacheMock
+		mockRepository *mocks.RateRepositoryMock
 		expectedRate   *model.ExchangeRate
 		expectedError  error
 	}{
@@ -68,7 +31,7 @@ func TestExchangeService_GetLatestRate(t *testing.T) {
 			name: "Success - Cache Hit",
 			from: model.USD,
 			to:   model.INR,
-			mockCache: MockRateCache{
+			mockCache: &mocks.RateCacheMock{
 				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
 					return &model.ExchangeRate{
 						BaseCurrency:   model.USD,
@@ -79,7 +42,7 @@ func TestExchangeService_GetLatestRate(t *testing.T) {
 					}, true
 				},
 			},
-			mockRepository: MockRateRepository{},
+			mockRepository: &mocks.RateRepositoryMock{},
 			expectedRate: &model.ExchangeRate{
 				BaseCurrency:   model.USD,
 				TargetCurrency: model.INR,
@@ -91,7 +54,7 @@ func TestExchangeService_GetLatestRate(t *testing.T) {
 			name: "Success - Cache Miss, Repository Hit",
 			from: model.USD,
 			to:   model.INR,
-			mockCache: MockRateCache{
+			mockCache: &mocks.RateCacheMock{
 				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
 					return nil, false
 				},
@@ -99,7 +62,7 @@ func TestExchangeService_GetLatestRate(t *testing.T) {
 					return nil
 				},
 			},
-			mockRepository: MockRateRepository{
+			mockRepository: &mocks.RateRepositoryMock{
 				FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
 					return &model.ExchangeRate{
 						BaseCurrency:   model.USD,
@@ -121,21 +84,78 @@ func TestExchangeService_GetLatestRate(t *testing.T) {
 			name:           "Error - Invalid Currency",
 			from:           model.Currency("XYZ"),
 			to:             model.INR,
-			mockCache:      MockRateCache{},
-			mockRepository: MockRateRepository{},
+			mockCache:      &mocks.RateCacheMock{},
+			mockRepository: &mocks.RateRepositoryMock{},
 			expectedRate:   nil,
 			expectedError:  ErrInvalidCurrency,
 		},
+		{
+			name:     "Success - Lenient Unsupported Currency",
+			from:     model.Currency("XYZ"),
+			to:       model.INR,
+			matching: model.CurrencyMatchingLenient,
+			mockCache: &mocks.RateCacheMock{
+				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+					return nil, false
+				},
+				SetFunc: func(ctx context.Context, rate *model.ExchangeRate) error {
+					return nil
+				},
+			},
+			mockRepository: &mocks.RateRepositoryMock{
+				FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+					return &model.ExchangeRate{
+						BaseCurrency:   pair.BaseCurrency,
+						TargetCurrency: pair.TargetCurrency,
+						Rate:           3.2,
+					}, nil
+				},
+			},
+			expectedRate: &model.ExchangeRate{
+				BaseCurrency:   model.Currency("XYZ"),
+				TargetCurrency: model.INR,
+				Rate:           3.2,
+			},
+			expectedError: nil,
+		},
+		{
+			name:           "Error - Lenient Malformed Currency",
+			from:           model.Currency("xy"),
+			to:             model.INR,
+			matching:       model.CurrencyMatchingLenient,
+			mockCache:      &mocks.RateCacheMock{},
+			mockRepository: &mocks.RateRepositoryMock{},
+			expectedRate:   nil,
+			expectedError:  ErrInvalidCurrency,
+		},
+		{
+			name:     "Error - Lenient Currency Unknown To Provider",
+			from:     model.Currency("XYZ"),
+			to:       model.INR,
+			matching: model.CurrencyMatchingLenient,
+			mockCache: &mocks.RateCacheMock{
+				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+					return nil, false
+				},
+			},
+			mockRepository: &mocks.RateRepositoryMock{
+				FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+					return nil, fmt.Errorf("%w: XYZ", ports.ErrQuoteNotFound)
+				},
+			},
+			expectedRate:  nil,
+			expectedError: ErrRateNotFound,
+		},
 		{
 			name: "Error - Repository Error",
 			from: model.USD,
 			to:   model.INR,
-			mockCache: MockRateCache{
+			mockCache: &mocks.RateCacheMock{
 				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
 					return nil, false
 				},
 			},
-			mockRepository: MockRateRepository{
+			mockRepository: &mocks.RateRepositoryMock{
 				FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
 					return nil, errors.New("API error")
 				},
@@ -148,7 +168,7 @@ func TestExchangeService_GetLatestRate(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 
-			svc := NewExchangeService(&tc.mockRepository, &tc.mockCache, log)
+			svc := NewExchangeService(tc.mockRepository, WithCache(tc.mockCache), WithLogger(log), WithCurrencyPolicy(model.CurrencyPolicy{Matching: tc.matching}))
 
 			rate, err := svc.GetLatestRate(context.Background(), tc.from, tc.to)
 
@@ -194,8 +214,8 @@ func TestExchangeService_ConvertCurrency(t *testing.T) {
 	testCases := []struct {
 		name           string
 		request        model.ConversionRequest
-		mockCache      MockRateCache
-		mockRepository MockRateRepository
+		mockCache      *mocks.RateCacheMock
+		mockRepository *mocks.RateRepositoryMock
 		expectedResult *model.ConversionResult
 		expectedError  error
 	}{
@@ -206,7 +226,7 @@ func TestExchangeService_ConvertCurrency(t *testing.T) {
 				ToCurrency:   model.INR,
 				Amount:       100,
 			},
-			mockCache: MockRateCache{
+			mockCache: &mocks.RateCacheMock{
 				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
 					return &model.ExchangeRate{
 						BaseCurrency:   model.USD,
@@ -217,7 +237,7 @@ func TestExchangeService_ConvertCurrency(t *testing.T) {
 					}, true
 				},
 			},
-			mockRepository: MockRateRepository{},
+			mockRepository: &mocks.RateRepositoryMock{},
 			expectedResult: &model.ConversionResult{
 				FromCurrency: model.USD,
 				ToCurrency:   model.INR,
@@ -227,6 +247,36 @@ func TestExchangeService_ConvertCurrency(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name: "Success - Dry Run",
+			request: model.ConversionRequest{
+				FromCurrency: model.USD,
+				ToCurrency:   model.INR,
+				Amount:       10,
+				DryRun:       true,
+			},
+			mockCache: &mocks.RateCacheMock{
+				GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+					return &model.ExchangeRate{
+						BaseCurrency:   model.USD,
+						TargetCurrency: model.INR,
+						Rate:           82.5,
+						Date:           time.Now().Truncate(24 * time.Hour),
+						LastUpdated:    time.Now(),
+					}, true
+				},
+			},
+			mockRepository: &mocks.RateRepositoryMock{},
+			expectedResult: &model.ConversionResult{
+				FromCurrency: model.USD,
+				ToCurrency:   model.INR,
+				FromAmount:   10,
+				ToAmount:     825,
+				Rate:         82.5,
+				DryRun:       true,
+			},
+			expectedError: nil,
+		},
 		{
 			name: "Error - Invalid Amount",
 			request: model.ConversionRequest{
@@ -234,8 +284,8 @@ func TestExchangeService_ConvertCurrency(t *testing.T) {
 				ToCurrency:   model.INR,
 				Amount:       -100,
 			},
-			mockCache:      MockRateCache{},
-			mockRepository: MockRateRepository{},
+			mockCache:      &mocks.RateCacheMock{},
+			mockRepository: &mocks.RateRepositoryMock{},
 			expectedResult: nil,
 			expectedError:  ErrInvalidAmount,
 		},
@@ -244,7 +294,15 @@ func TestExchangeService_ConvertCurrency(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 
-			svc := NewExchangeService(&tc.mockRepository, &tc.mockCache, log)
+			var saved *model.ConversionResult
+			conversions := &mocks.ConversionStoreMock{
+				SaveFunc: func(ctx context.Context, result *model.ConversionResult) error {
+					saved = result
+					return nil
+				},
+			}
+
+			svc := NewExchangeService(tc.mockRepository, WithCache(tc.mockCache), WithLogger(log), WithConversionStore(conversions))
 
 			result, err := svc.ConvertCurrency(context.Background(), tc.request)
 
@@ -286,6 +344,63 @@ func TestExchangeService_ConvertCurrency(t *testing.T) {
 				if tc.expectedResult.Rate != result.Rate {
 					t.Errorf("Expected rate: %f, got: %f", tc.expectedResult.Rate, result.Rate)
 				}
+
+				if tc.expectedResult.DryRun {
+					if result.ID != "" || saved != nil {
+						t.Error("Expected dry run to skip the conversion receipt")
+					}
+					return
+				}
+
+				if result.ID == "" {
+					t.Error("Expected conversion ID to be set")
+				}
+
+				if saved == nil || saved.ID != result.ID {
+					t.Error("Expected conversion receipt to be stored")
+				}
+			}
+		})
+	}
+}
+
+func TestExchangeService_RefreshRates(t *testing.T) {
+
+	log := logger.NewLogger("error")
+
+	testCases := []struct {
+		name            string
+		refreshErr      error
+		expectedError   error
+		expectPublished bool
+	}{
+		{name: "Changed quotes are published", expectPublished: true},
+		{name: "Unchanged quotes are not published", refreshErr: ports.ErrRatesUnchanged},
+		{name: "Provider failure", refreshErr: errors.New("timeout"), expectedError: ErrExternalAPIFailure},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repository := &mocks.RateRepositoryMock{
+				RefreshRatesFunc: func(ctx context.Context) error { return tc.refreshErr },
+				LatestRatesFunc: func(ctx context.Context) []model.ExchangeRate {
+					return []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83}}
+				},
+			}
+			cache := &mocks.RateCacheMock{ClearExpiredFunc: func(ctx context.Context) error { return nil }}
+			publisher := &mocks.RatePublisherMock{
+				PublishFunc: func(ctx context.Context, rates []model.ExchangeRate) error { return nil },
+			}
+
+			svc := NewExchangeService(repository, WithCache(cache), WithLogger(log))
+			svc.AddPublisher(publisher)
+
+			err := svc.RefreshRates(context.Background())
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if published := len(publisher.PublishCalls()) > 0; published != tc.expectPublished {
+				t.Errorf("Expected published %v, got %v", tc.expectPublished, published)
 			}
 		})
 	}
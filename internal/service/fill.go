@@ -0,0 +1,74 @@
+package service
+
+import (
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// missingDates lists the dates in the range that have no rate
+func missingDates(rates *model.HistoricalRates, start, end time.Time) []string {
+	var missing []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		if _, ok := rates.Rates[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// fillMissingDates adds synthetic rates for dates in the range that have no
+// data, flagged as interpolated. FillPrevious carries the last known rate
+// forward, through the end of the range as well. FillInterpolate draws a line
+// between the known rates around a gap, so it fills no dates after the last
+// one. Neither mode fills dates before the first known rate.
+func fillMissingDates(rates *model.HistoricalRates, start, end time.Time, mode model.FillMode) {
+	if mode != model.FillPrevious && mode != model.FillInterpolate {
+		return
+	}
+
+	dates := make([]time.Time, 0)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+
+	known := make([]int, 0, len(rates.Rates))
+	for i, d := range dates {
+		if _, ok := rates.Rates[d.Format("2006-01-02")]; ok {
+			known = append(known, i)
+		}
+	}
+
+	next := 0
+	for i, d := range dates {
+		key := d.Format("2006-01-02")
+		for next < len(known) && known[next] < i {
+			next++
+		}
+		if next < len(known) && known[next] == i {
+			continue
+		}
+
+		if next == 0 {
+			continue
+		}
+		prev := rates.Rates[dates[known[next-1]].Format("2006-01-02")]
+
+		filled := prev
+		filled.Date = d
+		filled.Interpolated = true
+
+		if mode == model.FillInterpolate {
+			if next == len(known) {
+				continue
+			}
+			after := rates.Rates[dates[known[next]].Format("2006-01-02")]
+			span := float64(known[next] - known[next-1])
+			offset := float64(i - known[next-1])
+			filled.Rate = prev.Rate + (after.Rate-prev.Rate)*offset/span
+		}
+
+		rates.Rates[key] = filled
+	}
+}
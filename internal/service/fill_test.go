@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestFillMissingDates(t *testing.T) {
+
+	day := func(d int) time.Time {
+		return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	newRates := func() *model.HistoricalRates {
+		return &model.HistoricalRates{
+			BaseCurrency:   model.USD,
+			TargetCurrency: model.INR,
+			Rates: map[string]model.ExchangeRate{
+				"2025-01-02": {Rate: 80, Date: day(2)},
+				"2025-01-05": {Rate: 83, Date: day(5)},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		mode          model.FillMode
+		expectedRates map[string]float64
+	}{
+		{
+			name: "None",
+			mode: model.FillNone,
+			expectedRates: map[string]float64{
+				"2025-01-02": 80,
+				"2025-01-05": 83,
+			},
+		},
+		{
+			name: "Previous",
+			mode: model.FillPrevious,
+			expectedRates: map[string]float64{
+				"2025-01-02": 80,
+				"2025-01-03": 80,
+				"2025-01-04": 80,
+				"2025-01-05": 83,
+				"2025-01-06": 83,
+			},
+		},
+		{
+			name: "Interpolate",
+			mode: model.FillInterpolate,
+			expectedRates: map[string]float64{
+				"2025-01-02": 80,
+				"2025-01-03": 81,
+				"2025-01-04": 82,
+				"2025-01-05": 83,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			rates := newRates()
+			fillMissingDates(rates, day(1), day(6), tc.mode)
+
+			if len(rates.Rates) != len(tc.expectedRates) {
+				t.Errorf("Expected %d rates, got: %d", len(tc.expectedRates), len(rates.Rates))
+			}
+
+			for date, expected := range tc.expectedRates {
+				rate, ok := rates.Rates[date]
+				if !ok {
+					t.Errorf("Expected rate for %s", date)
+					continue
+				}
+
+				if rate.Rate != expected {
+					t.Errorf("Expected rate for %s: %f, got: %f", date, expected, rate.Rate)
+				}
+
+				original := date == "2025-01-02" || date == "2025-01-05"
+				if rate.Interpolated == original {
+					t.Errorf("Expected interpolated=%t for %s", !original, date)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// historyWindow returns the retention window for stored history. Today is
+// excluded because its daily rate is not final yet.
+func (s *ExchangeService) historyWindow() (time.Time, time.Time) {
+	today := s.today()
+	return today.AddDate(0, 0, -model.HistoryRetentionDays), today.AddDate(0, 0, -1)
+}
+
+// historyPairs returns the requested pair, or every supported pair when pair is nil
+func historyPairs(pair *model.CurrencyPair) []model.CurrencyPair {
+	if pair != nil {
+		return []model.CurrencyPair{*pair}
+	}
+
+	pairs := make([]model.CurrencyPair, 0)
+	for _, base := range model.SupportedCurrencies {
+		for _, target := range model.SupportedCurrencies {
+			if base != target {
+				pairs = append(pairs, model.CurrencyPair{BaseCurrency: base, TargetCurrency: target})
+			}
+		}
+	}
+	return pairs
+}
+
+// HistoryGaps scans the history store for dates missing within the retention window
+func (s *ExchangeService) HistoryGaps(ctx context.Context, pair *model.CurrencyPair) (*model.HistoryGapReport, error) {
+	if pair != nil && (!pair.BaseCurrency.IsSupported() || !pair.TargetCurrency.IsSupported()) {
+		return nil, ErrInvalidCurrency
+	}
+
+	start, end := s.historyWindow()
+	report := &model.HistoryGapReport{
+		WindowStart: start,
+		WindowEnd:   end,
+		Pairs:       make([]model.PairCompleteness, 0),
+	}
+
+	expected, available := 0, 0
+	for _, p := range historyPairs(pair) {
+		stored, err := s.history.Range(ctx, p, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		present := make(map[string]bool, len(stored))
+		for _, rate := range stored {
+			present[rate.Date.Format("2006-01-02")] = true
+		}
+
+		pc := model.PairCompleteness{
+			BaseCurrency:   p.BaseCurrency,
+			TargetCurrency: p.TargetCurrency,
+			MissingDates:   make([]string, 0),
+		}
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			pc.ExpectedDays++
+			if present[d.Format("2006-01-02")] {
+				pc.AvailableDays++
+			} else {
+				pc.MissingDates = append(pc.MissingDates, d.Format("2006-01-02"))
+			}
+		}
+		pc.Completeness = percent(pc.AvailableDays, pc.ExpectedDays)
+
+		expected += pc.ExpectedDays
+		available += pc.AvailableDays
+		report.Pairs = append(report.Pairs, pc)
+	}
+	report.Completeness = percent(available, expected)
+
+	return report, nil
+}
+
+// BackfillHistory fetches up to limit missing dates found by HistoryGaps from
+// the repository. Backfills are not essential, so they fetch no more dates
+// than the usage budget leaves outside its reserve.
+func (s *ExchangeService) BackfillHistory(ctx context.Context, pair *model.CurrencyPair, limit int) (*model.BackfillResult, error) {
+	allowance, err := s.backfillAllowance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if allowance <= 0 {
+		s.log.Warn("Refusing history backfill, the provider budget left is reserved", "provider", s.budget.provider)
+		return nil, ErrBudgetExhausted
+	}
+	limit = min(limit, allowance)
+
+	report, err := s.HistoryGaps(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.BackfillResult{
+		Failed: make([]string, 0),
+	}
+	fetched := make([]model.ExchangeRate, 0)
+
+pairs:
+	for _, pc := range report.Pairs {
+		p := model.CurrencyPair{BaseCurrency: pc.BaseCurrency, TargetCurrency: pc.TargetCurrency}
+
+		for _, dateStr := range pc.MissingDates {
+			if result.Requested >= limit || ctx.Err() != nil {
+				break pairs
+			}
+			result.Requested++
+
+			date, _ := time.Parse("2006-01-02", dateStr)
+			rate, err := s.repository.FetchHistoricalRate(ctx, p, date)
+			if err != nil {
+				s.log.Error("Backfill fetch failed", "error", err, "pair", p.String(), "date", dateStr)
+				result.Failed = append(result.Failed, p.String()+" "+dateStr)
+				continue
+			}
+			fetched = append(fetched, *rate)
+		}
+	}
+
+	if err := s.history.Save(ctx, fetched); err != nil {
+		return nil, err
+	}
+	result.Filled = len(fetched)
+
+	s.log.Info("History backfill finished", "requested", result.Requested, "filled", result.Filled)
+	return result, nil
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(part) * 100 / float64(total)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// UseCPI enables inflation-adjusted conversions with the indexes of source
+func (s *ExchangeService) UseCPI(source ports.CPISource) {
+	s.cpi = source
+}
+
+// InflationAdjustedConversion restates an amount of a past date in today's
+// money of another currency. It takes two labelled steps, an inflation
+// adjustment with the CPI of one currency and a conversion, in the order
+// request.Method gives.
+func (s *ExchangeService) InflationAdjustedConversion(ctx context.Context, request model.InflationRequest) (*model.InflationAdjustedConversion, error) {
+	if s.cpi == nil {
+		return nil, ErrInflationDisabled
+	}
+	if request.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if request.Method == "" {
+		request.Method = model.InflateThenConvert
+	}
+	if !request.Method.Valid() {
+		return nil, ErrInvalidInflation
+	}
+	if request.Date.After(s.clock.Now().UTC()) {
+		return nil, ErrInvalidDateRange
+	}
+
+	result := &model.InflationAdjustedConversion{
+		FromCurrency: request.FromCurrency,
+		ToCurrency:   request.ToCurrency,
+		Amount:       request.Amount,
+		Date:         request.Date,
+		Method:       request.Method,
+		Steps:        make([]model.InflationStep, 0, 2),
+	}
+
+	amount := request.Amount
+	if request.Method == model.InflateThenConvert {
+		inflation, err := s.inflationStep(ctx, request.FromCurrency, request.Date, amount)
+		if err != nil {
+			return nil, err
+		}
+		rate, err := s.GetLatestRate(ctx, request.FromCurrency, request.ToCurrency)
+		if err != nil {
+			return nil, err
+		}
+		result.Steps = append(result.Steps, *inflation, conversionStep(rate, "latest", inflation.AmountAfter))
+	} else {
+		rate, err := s.GetHistoricalRate(ctx, request.FromCurrency, request.ToCurrency, request.Date)
+		if err != nil {
+			return nil, err
+		}
+		conversion := conversionStep(rate, "historical", amount)
+		inflation, err := s.inflationStep(ctx, request.ToCurrency, request.Date, conversion.AmountAfter)
+		if err != nil {
+			return nil, err
+		}
+		result.Steps = append(result.Steps, conversion, *inflation)
+	}
+
+	result.Result = result.Steps[len(result.Steps)-1].AmountAfter
+	return result, nil
+}
+
+// inflationStep restates amount of currency on date in its latest prices
+func (s *ExchangeService) inflationStep(ctx context.Context, currency model.Currency, date time.Time, amount float64) (*model.InflationStep, error) {
+	start, err := s.cpi.At(ctx, currency, date)
+	if err != nil {
+		return nil, cpiError(err)
+	}
+	end, err := s.cpi.Latest(ctx, currency)
+	if err != nil {
+		return nil, cpiError(err)
+	}
+
+	factor := end.Value / start.Value
+	return &model.InflationStep{
+		Type: model.InflationStepInflation,
+		Label: fmt.Sprintf("%s inflation from %s to %s, CPI %g to %g (%+.2f%%)",
+			currency, start.Date.Format("2006-01-02"), end.Date.Format("2006-01-02"), start.Value, end.Value, (factor-1)*100),
+		AmountBefore: amount,
+		AmountAfter:  amount * factor,
+		Factor:       factor,
+		StartCPI:     start,
+		EndCPI:       end,
+	}, nil
+}
+
+func conversionStep(rate *model.ExchangeRate, kind string, amount float64) model.InflationStep {
+	return model.InflationStep{
+		Type:         model.InflationStepConversion,
+		Label:        fmt.Sprintf("%s to %s at the %s rate of %s, %g", rate.BaseCurrency, rate.TargetCurrency, kind, rate.Date.Format("2006-01-02"), rate.Rate),
+		AmountBefore: amount,
+		AmountAfter:  amount * rate.Rate,
+		Factor:       rate.Rate,
+		Rate:         rate,
+	}
+}
+
+func cpiError(err error) error {
+	if errors.Is(err, ports.ErrCPINotFound) {
+		return fmt.Errorf("%w: %w", ErrCPINotFound, err)
+	}
+	return err
+}
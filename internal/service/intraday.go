@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// SampleIntraday refreshes the provider and records a tick for each pair. The
+// sampled rates also go into the cache so the latest rate and the newest
+// candle agree. Ticks are recorded even when the quotes are unchanged, but
+// the rates are only published when they moved.
+func (s *ExchangeService) SampleIntraday(ctx context.Context, pairs []model.CurrencyPair) error {
+	if err := s.coolingDown(ctx); err != nil {
+		return err
+	}
+
+	err := s.repository.RefreshRates(ctx)
+	unchanged := errors.Is(err, ports.ErrRatesUnchanged)
+	if err != nil && !unchanged {
+		s.noteRateLimit(err)
+		return fmt.Errorf("%w: %w", ErrExternalAPIFailure, err)
+	}
+
+	now := s.clock.Now().UTC()
+	ticks := make([]model.Tick, 0, len(pairs))
+	sampled := make([]model.ExchangeRate, 0, len(pairs))
+	for _, pair := range pairs {
+		rate, err := s.repository.FetchLatestRate(ctx, pair)
+		if err != nil {
+			s.log.Error("Failed to sample intraday rate", "error", err, "pair", pair.String())
+			continue
+		}
+
+		ticks = append(ticks, model.Tick{Pair: pair, Rate: rate.Rate, Time: now})
+		sampled = append(sampled, *rate)
+		if err := s.cache.Set(ctx, rate); err != nil {
+			s.log.Error("Failed to cache sampled exchange rate", "error", err, "pair", pair.String())
+		}
+	}
+
+	if !unchanged {
+		s.publish(ctx, sampled)
+	}
+	return s.ticks.Append(ctx, ticks)
+}
+
+// GetOHLC aggregates the stored ticks of a pair into candles
+func (s *ExchangeService) GetOHLC(ctx context.Context, request model.OHLCRequest) (*model.OHLCSeries, error) {
+	if err := s.checkPair(request.Pair.BaseCurrency, request.Pair.TargetCurrency); err != nil {
+		return nil, err
+	}
+
+	if !request.From.Before(request.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	interval, err := model.ParseCandleInterval(request.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInterval, err)
+	}
+	if request.To.Sub(request.From)/interval > model.MaxCandles {
+		return nil, fmt.Errorf("%w: range spans more than %d candles", ErrInvalidInterval, model.MaxCandles)
+	}
+
+	ticks, err := s.ticks.Range(ctx, request.Pair, request.From, request.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OHLCSeries{
+		BaseCurrency:   request.Pair.BaseCurrency,
+		TargetCurrency: request.Pair.TargetCurrency,
+		Interval:       request.Interval,
+		From:           request.From,
+		To:             request.To,
+		Candles:        model.BuildCandles(ticks, interval),
+	}, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestExchangeService_GetOHLC(t *testing.T) {
+	log := logger.NewLogger("debug")
+	pair := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 3, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	ticks := &mocks.TickStoreMock{
+		RangeFunc: func(ctx context.Context, p model.CurrencyPair, from, to time.Time) ([]model.Tick, error) {
+			return []model.Tick{
+				{Pair: p, Rate: 83.0, Time: at(9, 5)},
+				{Pair: p, Rate: 83.4, Time: at(9, 20)},
+				{Pair: p, Rate: 82.8, Time: at(9, 40)},
+				{Pair: p, Rate: 83.1, Time: at(9, 55)},
+				{Pair: p, Rate: 83.2, Time: at(11, 0)},
+			}, nil
+		},
+	}
+	svc := NewExchangeService(&mocks.RateRepositoryMock{}, WithCache(&mocks.RateCacheMock{}), WithLogger(log), WithTickStore(ticks))
+
+	series, err := svc.GetOHLC(context.Background(), model.OHLCRequest{Pair: pair, From: at(9, 0), To: at(12, 0), Interval: "1h"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []model.Candle{
+		{Time: at(9, 0), Open: 83.0, High: 83.4, Low: 82.8, Close: 83.1, Ticks: 4},
+		{Time: at(11, 0), Open: 83.2, High: 83.2, Low: 83.2, Close: 83.2, Ticks: 1},
+	}
+	if len(series.Candles) != len(expected) {
+		t.Fatalf("expected %d candles, got %d: %+v", len(expected), len(series.Candles), series.Candles)
+	}
+	for i, candle := range expected {
+		if series.Candles[i] != candle {
+			t.Errorf("candle %d: expected %+v, got %+v", i, candle, series.Candles[i])
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		request       model.OHLCRequest
+		expectedError error
+	}{
+		{name: "Unknown Interval", request: model.OHLCRequest{Pair: pair, From: at(9, 0), To: at(12, 0), Interval: "2h"}, expectedError: ErrInvalidInterval},
+		{name: "Too Many Candles", request: model.OHLCRequest{Pair: pair, From: at(0, 0).AddDate(0, 0, -2), To: at(0, 0), Interval: "1m"}, expectedError: ErrInvalidInterval},
+		{name: "Reversed Range", request: model.OHLCRequest{Pair: pair, From: at(12, 0), To: at(9, 0), Interval: "1h"}, expectedError: ErrInvalidDateRange},
+		{name: "Invalid Currency", request: model.OHLCRequest{Pair: model.CurrencyPair{BaseCurrency: "XXX", TargetCurrency: model.INR}, From: at(9, 0), To: at(12, 0), Interval: "1h"}, expectedError: ErrInvalidCurrency},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := svc.GetOHLC(context.Background(), tc.request); !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
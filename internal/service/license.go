@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// UseProviderLicenses sets the license terms of the providers, which decide
+// whether historical responses must credit them
+func (s *ExchangeService) UseProviderLicenses(licenses model.ProviderLicenses) {
+	s.licenses = licenses
+}
+
+// HistoricalAttribution returns the attribution required with historical data,
+// which always comes from the primary provider, or nil when none is required
+func (s *ExchangeService) HistoricalAttribution(ctx context.Context) *model.Attribution {
+	return s.licenses.Attribution(s.repository.Name())
+}
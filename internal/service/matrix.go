@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+const maxMatrixAmounts = 100
+
+// ConvertMatrix converts every amount into every target currency. Each rate is
+// looked up exactly once up front, so all cells share one consistent snapshot.
+// Targets whose rate cannot be looked up are left out and listed in Errors,
+// unless none can.
+func (s *ExchangeService) ConvertMatrix(ctx context.Context, request model.ConversionMatrixRequest) (*model.ConversionMatrix, error) {
+	if !s.acceptsCurrency(s.aliases.Canonical(request.FromCurrency)) {
+		return nil, ErrInvalidCurrency
+	}
+
+	if len(request.Amounts) == 0 || len(request.Amounts) > maxMatrixAmounts {
+		return nil, fmt.Errorf("%w: between 1 and %d amounts are required", ErrInvalidAmount, maxMatrixAmounts)
+	}
+	for _, amount := range request.Amounts {
+		if amount <= 0 {
+			return nil, ErrInvalidAmount
+		}
+	}
+
+	if len(request.Targets) == 0 {
+		return nil, fmt.Errorf("%w: at least one target is required", ErrInvalidCurrency)
+	}
+
+	if !request.Date.IsZero() {
+		if err := s.validateDate(request.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	matrix := &model.ConversionMatrix{
+		FromCurrency: request.FromCurrency,
+		Rates:        make(map[model.Currency]model.ExchangeRate),
+		Rows:         make([]model.ConversionMatrixRow, 0, len(request.Amounts)),
+	}
+
+	var lookupErr error
+	for _, target := range request.Targets {
+		if !s.acceptsCurrency(s.aliases.Canonical(target)) {
+			return nil, ErrInvalidCurrency
+		}
+		if _, done := matrix.Rates[target]; done {
+			continue
+		}
+
+		if target == request.FromCurrency {
+			matrix.Rates[target] = model.ExchangeRate{BaseCurrency: target, TargetCurrency: target, Rate: 1}
+			continue
+		}
+
+		var rate *model.ExchangeRate
+		var err error
+		if request.Date.IsZero() {
+			rate, err = s.GetLatestRate(ctx, request.FromCurrency, target)
+		} else {
+			rate, err = s.GetHistoricalRate(ctx, request.FromCurrency, target, request.Date)
+		}
+		if err != nil {
+			// Invalid targets fail the request; lookups that fail leave the target out
+			if errors.Is(err, xerrors.Validation) {
+				return nil, err
+			}
+			s.log.Error("Failed to look up matrix rate", "error", err, "target", target)
+			matrix.Errors = append(matrix.Errors, model.NewItemError(string(target), err))
+			lookupErr = err
+			continue
+		}
+		matrix.Rates[target] = *rate
+	}
+	if len(matrix.Rates) == 0 {
+		return nil, lookupErr
+	}
+
+	for _, amount := range request.Amounts {
+		row := model.ConversionMatrixRow{
+			Amount:    amount,
+			Converted: make(map[model.Currency]float64, len(matrix.Rates)),
+		}
+		for target, rate := range matrix.Rates {
+			row.Converted[target] = amount * rate.Rate
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+
+	return matrix, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestExchangeService_ConvertMatrix(t *testing.T) {
+	rates := map[model.Currency]float64{model.INR: 83, model.EUR: 0.9}
+	repository := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			rate, exists := rates[pair.TargetCurrency]
+			if !exists {
+				return nil, errors.New("provider down")
+			}
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: rate, Date: time.Now().UTC()}, nil
+		},
+	}
+	svc := NewExchangeService(repository, WithLogger(logger.NewLogger("error")))
+	ctx := context.Background()
+
+	matrix, err := svc.ConvertMatrix(ctx, model.ConversionMatrixRequest{
+		FromCurrency: model.USD,
+		Amounts:      []float64{10, 100},
+		Targets:      []model.Currency{model.INR, model.EUR, model.INR, model.USD, model.JPY},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each target is looked up once, and USD to itself not at all
+	if calls := len(repository.FetchLatestRateCalls()); calls != 3 {
+		t.Errorf("Expected one lookup per distinct target, got %d", calls)
+	}
+	if len(matrix.Rows) != 2 || matrix.Rows[1].Amount != 100 {
+		t.Fatalf("Expected a row per amount in order, got %+v", matrix.Rows)
+	}
+	want := map[model.Currency]float64{model.INR: 8300, model.EUR: 90, model.USD: 100}
+	for target, converted := range want {
+		if got := matrix.Rows[1].Converted[target]; got != converted {
+			t.Errorf("Expected 100 USD to be %v %s, got %v", converted, target, got)
+		}
+	}
+	if _, exists := matrix.Rows[1].Converted[model.JPY]; exists || !matrix.Partial() || matrix.Errors[0].Item != "JPY" {
+		t.Errorf("Expected JPY to be left out and reported, got %+v", matrix)
+	}
+
+	// A matrix of which no rate can be looked up fails
+	if _, err := svc.ConvertMatrix(ctx, model.ConversionMatrixRequest{FromCurrency: model.USD, Amounts: []float64{1}, Targets: []model.Currency{model.JPY}}); err == nil {
+		t.Error("Expected a matrix without any rate to fail")
+	}
+}
+
+func TestExchangeService_ConvertMatrixRejectsInvalidRequests(t *testing.T) {
+	svc := NewExchangeService(&mocks.RateRepositoryMock{}, WithLogger(logger.NewLogger("error")))
+	valid := func() model.ConversionMatrixRequest {
+		return model.ConversionMatrixRequest{FromCurrency: model.USD, Amounts: []float64{1}, Targets: []model.Currency{model.INR}}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*model.ConversionMatrixRequest)
+		want   error
+	}{
+		{"unknown source", func(r *model.ConversionMatrixRequest) { r.FromCurrency = "XXX" }, ErrInvalidCurrency},
+		{"unknown target", func(r *model.ConversionMatrixRequest) { r.Targets = []model.Currency{"XXX"} }, ErrInvalidCurrency},
+		{"no targets", func(r *model.ConversionMatrixRequest) { r.Targets = nil }, ErrInvalidCurrency},
+		{"no amounts", func(r *model.ConversionMatrixRequest) { r.Amounts = nil }, ErrInvalidAmount},
+		{"negative amount", func(r *model.ConversionMatrixRequest) { r.Amounts = []float64{5, -1} }, ErrInvalidAmount},
+		{"too many amounts", func(r *model.ConversionMatrixRequest) { r.Amounts = make([]float64, maxMatrixAmounts+1) }, ErrInvalidAmount},
+		{"date out of range", func(r *model.ConversionMatrixRequest) { r.Date = time.Now().AddDate(-1, 0, 0) }, ErrDateOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.modify(&request)
+			_, err := svc.ConvertMatrix(context.Background(), request)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
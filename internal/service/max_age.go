@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// StaleRateError is ErrStaleRate with the rate that was too old, for clients
+// that would rather have it than nothing. Age is how old the rate was by the
+// service's clock.
+type StaleRateError struct {
+	Rate   *model.ExchangeRate
+	Age    time.Duration
+	MaxAge time.Duration
+}
+
+func (e *StaleRateError) Error() string {
+	return fmt.Sprintf("%s of %s", ErrStaleRate.Error(), e.MaxAge)
+}
+
+func (e *StaleRateError) Unwrap() error {
+	return ErrStaleRate
+}
+
+// tooOld reports whether rate is older than the max age in the options of ctx
+func (s *ExchangeService) tooOld(ctx context.Context, rate *model.ExchangeRate) bool {
+	maxAge := model.RequestOptionsFromContext(ctx).MaxAge
+	return maxAge > 0 && s.clock.Now().Sub(rate.LastUpdated) > maxAge
+}
+
+// freshLatestRate returns rate, the provider's latest rate of pair, unless it
+// is older than the request allows. Then the provider is asked for pair again,
+// unless refreshes are paused for its rate limit. Quotes the provider finds
+// unchanged confirm the rate, whatever its age; any other failure returns a
+// StaleRateError.
+func (s *ExchangeService) freshLatestRate(ctx context.Context, pair model.CurrencyPair, rate *model.ExchangeRate) (*model.ExchangeRate, error) {
+	if !s.tooOld(ctx, rate) {
+		return rate, nil
+	}
+	stale := &StaleRateError{
+		Rate:   s.adjust(ctx, rate),
+		Age:    s.clock.Now().Sub(rate.LastUpdated),
+		MaxAge: model.RequestOptionsFromContext(ctx).MaxAge,
+	}
+	if err := s.coolingDown(ctx); err != nil {
+		return nil, stale
+	}
+
+	var err error
+	if refresher, ok := s.repository.(ports.PairRefresher); ok {
+		err = refresher.RefreshPairs(ctx, []model.CurrencyPair{pair})
+	} else {
+		err = s.repository.RefreshRates(ctx)
+	}
+	unchanged := errors.Is(err, ports.ErrRatesUnchanged)
+	if err != nil && !unchanged {
+		s.noteRateLimit(err)
+		s.log.ErrorContext(ctx, "Failed to fetch a rate within the max age", "error", err, "pair", pair.String())
+		return nil, stale
+	}
+
+	fresh, err := s.repository.FetchLatestRate(ctx, pair)
+	if err != nil || (!unchanged && s.tooOld(ctx, fresh)) {
+		return nil, stale
+	}
+	return fresh, nil
+}
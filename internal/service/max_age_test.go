@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestGetLatestRateMaxAge(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	updated := fake.Now().Add(-10 * time.Minute)
+	var refreshErr error
+	repository := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 83, LastUpdated: updated}, nil
+		},
+		RefreshRatesFunc: func(ctx context.Context) error {
+			if refreshErr == nil {
+				updated = fake.Now()
+			}
+			return refreshErr
+		},
+	}
+	var cached *model.ExchangeRate
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return cached, cached != nil
+		},
+		SetFunc: func(ctx context.Context, rate *model.ExchangeRate) error {
+			cached = rate
+			return nil
+		},
+	}
+	svc := NewExchangeService(repository, WithCache(cache), WithLogger(logger.NewLogger("error")), WithClock(fake))
+	within := func(maxAge time.Duration) context.Context {
+		return model.WithRequestOptions(context.Background(), model.RequestOptions{MaxAge: maxAge})
+	}
+
+	// Any age will do without a max age, and within a lax one
+	for _, ctx := range []context.Context{context.Background(), within(time.Hour)} {
+		if rate, err := svc.GetLatestRate(ctx, model.USD, model.INR); err != nil || !rate.LastUpdated.Equal(updated) {
+			t.Fatalf("Expected the 10 minute old rate, got %v, %v", rate, err)
+		}
+	}
+	if calls := len(repository.RefreshRatesCalls()); calls != 0 {
+		t.Fatalf("Expected no refresh, got %d", calls)
+	}
+
+	// A cached rate older than the max age is fetched again
+	rate, err := svc.GetLatestRate(within(time.Minute), model.USD, model.INR)
+	if err != nil || !rate.LastUpdated.Equal(fake.Now()) {
+		t.Fatalf("Expected a freshly fetched rate, got %v, %v", rate, err)
+	}
+	if calls := len(repository.RefreshRatesCalls()); calls != 1 {
+		t.Errorf("Expected one refresh, got %d", calls)
+	}
+
+	// A failed refetch returns the stale rate with the error
+	fake.Advance(5 * time.Minute)
+	cached = nil
+	refreshErr = &xerrors.Error{Kind: xerrors.RateLimited, Code: "http_429", Provider: "primary", RetryAfter: time.Minute}
+	_, err = svc.GetLatestRate(within(time.Minute), model.USD, model.INR)
+	var stale *StaleRateError
+	if !errors.As(err, &stale) || stale.Rate.Rate != 83 || stale.Age != 5*time.Minute || stale.MaxAge != time.Minute {
+		t.Fatalf("Expected a StaleRateError with the stale rate, got %v", err)
+	}
+
+	// and while refreshes are paused for the rate limit, the provider is left alone
+	refreshErr = nil
+	if _, err := svc.GetLatestRate(within(time.Minute), model.USD, model.INR); !errors.Is(err, ErrStaleRate) {
+		t.Errorf("Expected ErrStaleRate during the cool-down, got %v", err)
+	}
+	if calls := len(repository.RefreshRatesCalls()); calls != 2 {
+		t.Errorf("Expected no refresh during the cool-down, got %d", calls)
+	}
+}
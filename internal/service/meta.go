@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// noteMeta records a rate of repository in the response meta of ctx, if the
+// request asked for one
+func (s *ExchangeService) noteMeta(ctx context.Context, repository ports.RateRepository, cached bool, rate *model.ExchangeRate) {
+	if collector := model.ResponseMetaFromContext(ctx); collector != nil {
+		collector.Note(repository.Name(), cached, rate)
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+// Option configures an ExchangeService when it is created. Options for the
+// subsystems that have a Use method apply it, so they behave the same.
+type Option func(*ExchangeService)
+
+// WithCache keeps latest and historical rates in cache. Without it nothing
+// is cached and every lookup calls the provider.
+func WithCache(cache ports.RateCache) Option {
+	return func(s *ExchangeService) { s.cache = cache }
+}
+
+// WithLogger logs to log instead of defaultLogger
+func WithLogger(log *logger.Logger) Option {
+	return func(s *ExchangeService) { s.log = log }
+}
+
+// WithSecondary sets a second provider used only for comparisons
+func WithSecondary(repository ports.RateRepository) Option {
+	return func(s *ExchangeService) { s.secondary = repository }
+}
+
+// WithConversionStore keeps conversion receipts in store. Without it receipts
+// are not kept and GetConversion finds none.
+func WithConversionStore(store ports.ConversionStore) Option {
+	return func(s *ExchangeService) { s.conversions = store }
+}
+
+// WithAnnotationStore keeps annotations in store. Without it annotations
+// cannot be created and none are listed.
+func WithAnnotationStore(store ports.AnnotationStore) Option {
+	return func(s *ExchangeService) { s.annotations = store }
+}
+
+// WithHistoryStore keeps daily rates in store, so historical lookups and
+// range statistics need not call the provider. Without it nothing is kept.
+func WithHistoryStore(store ports.HistoryStore) Option {
+	return func(s *ExchangeService) { s.history = store }
+}
+
+// WithSLAStore reads provider SLA summaries from store
+func WithSLAStore(store ports.SLAStore) Option {
+	return func(s *ExchangeService) { s.sla = store }
+}
+
+// WithTickStore keeps intraday ticks, for candles, in store
+func WithTickStore(store ports.TickStore) Option {
+	return func(s *ExchangeService) { s.ticks = store }
+}
+
+// WithCurrencyPolicy restricts the currencies and pairs served
+func WithCurrencyPolicy(policy model.CurrencyPolicy) Option {
+	return func(s *ExchangeService) { s.SetCurrencyPolicy(policy) }
+}
+
+// WithClock is UseClock as an option
+func WithClock(c clock.Clock) Option {
+	return func(s *ExchangeService) { s.UseClock(c) }
+}
+
+// WithPublisher is AddPublisher as an option. It may be given more than once.
+func WithPublisher(p ports.RatePublisher) Option {
+	return func(s *ExchangeService) { s.AddPublisher(p) }
+}
+
+// WithRateAdjuster is UseRateAdjuster as an option. Margins and markups on
+// served rates are applied by the adjuster.
+func WithRateAdjuster(adjuster ports.RateAdjuster) Option {
+	return func(s *ExchangeService) { s.UseRateAdjuster(adjuster) }
+}
+
+// WithCurrencyAliases is UseCurrencyAliases as an option
+func WithCurrencyAliases(aliases model.CurrencyAliases) Option {
+	return func(s *ExchangeService) { s.UseCurrencyAliases(aliases) }
+}
+
+// WithProviderLicenses is UseProviderLicenses as an option
+func WithProviderLicenses(licenses model.ProviderLicenses) Option {
+	return func(s *ExchangeService) { s.UseProviderLicenses(licenses) }
+}
+
+// WithPairPopularity is UsePairPopularity as an option
+func WithPairPopularity(table ports.PairPopularity, count, fullEvery int) Option {
+	return func(s *ExchangeService) { s.UsePairPopularity(table, count, fullEvery) }
+}
+
+// WithBaskets is UseBaskets as an option
+func WithBaskets(store ports.BasketStore) Option {
+	return func(s *ExchangeService) { s.UseBaskets(store) }
+}
+
+// WithCPI is UseCPI as an option
+func WithCPI(source ports.CPISource) Option {
+	return func(s *ExchangeService) { s.UseCPI(source) }
+}
+
+// WithCryptoRepository is UseCryptoRepository as an option
+func WithCryptoRepository(repository ports.RateRepository) Option {
+	return func(s *ExchangeService) { s.UseCryptoRepository(repository) }
+}
+
+// WithRecentRates is UseRecentRates as an option
+func WithRecentRates(recent ports.RecentRateStore) Option {
+	return func(s *ExchangeService) { s.UseRecentRates(recent) }
+}
+
+// WithPrecomputedAmounts is UsePrecomputedAmounts as an option
+func WithPrecomputedAmounts(amounts []float64) Option {
+	return func(s *ExchangeService) { s.UsePrecomputedAmounts(amounts) }
+}
+
+// WithConversionCache is UseConversionCache as an option
+func WithConversionCache(results ports.ConversionCache) Option {
+	return func(s *ExchangeService) { s.UseConversionCache(results) }
+}
+
+// WithMaxRangeDays caps the dates a range request may span; 0 is unlimited
+func WithMaxRangeDays(days int) Option {
+	return func(s *ExchangeService) { s.maxRange = days }
+}
+
+// WithRateLimitCooldown is UseRateLimitCooldown as an option
+func WithRateLimitCooldown(cooldown time.Duration) Option {
+	return func(s *ExchangeService) { s.UseRateLimitCooldown(cooldown) }
+}
+
+// WithUsageBudget sets the monthly quota of provider. Once the calls of the
+// last 30 days leave only reservePercent of it, historical backfills are
+// refused. A zero reservePercent only reports the budget.
+func WithUsageBudget(provider string, monthly int, reservePercent float64) Option {
+	return func(s *ExchangeService) {
+		s.budget = usageBudget{provider: provider, monthly: monthly, reservePercent: reservePercent}
+	}
+}
+
+// errAnnotationsNotStored is returned when annotations are created without an
+// annotation store
+var errAnnotationsNotStored = errors.New("no annotation store configured")
+
+// defaultLogger is the logger of services created without WithLogger
+var defaultLogger = logger.NewLogger("info")
+
+// The stores below stand in for those not given to NewExchangeService. They
+// keep nothing, so every lookup misses.
+
+type noCache struct{}
+
+func (noCache) Get(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+	return nil, false
+}
+
+func (noCache) Set(ctx context.Context, rate *model.ExchangeRate) error { return nil }
+
+func (noCache) ClearExpired(ctx context.Context) error { return nil }
+
+func (noCache) Stats(ctx context.Context) model.CacheStats { return model.CacheStats{} }
+
+type noConversions struct{}
+
+func (noConversions) Save(ctx context.Context, result *model.ConversionResult) error { return nil }
+
+func (noConversions) Get(ctx context.Context, id string) (*model.ConversionResult, bool) {
+	return nil, false
+}
+
+type noAnnotations struct{}
+
+func (noAnnotations) Create(ctx context.Context, annotation *model.Annotation) error {
+	return errAnnotationsNotStored
+}
+
+func (noAnnotations) List(ctx context.Context, filter model.AnnotationFilter) ([]model.Annotation, error) {
+	return []model.Annotation{}, nil
+}
+
+func (noAnnotations) Delete(ctx context.Context, id string) (bool, error) { return false, nil }
+
+type noHistory struct{}
+
+func (noHistory) Save(ctx context.Context, rates []model.ExchangeRate) error { return nil }
+
+func (noHistory) Get(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+	return nil, false
+}
+
+func (noHistory) Range(ctx context.Context, pair model.CurrencyPair, start, end time.Time) ([]model.ExchangeRate, error) {
+	return nil, nil
+}
+
+type noSLA struct{}
+
+func (noSLA) Record(ctx context.Context, provider string, latency time.Duration, success bool) {}
+
+func (noSLA) Buckets(ctx context.Context, since time.Time) ([]model.ProviderSLABucket, error) {
+	return nil, nil
+}
+
+type noTicks struct{}
+
+func (noTicks) Append(ctx context.Context, ticks []model.Tick) error { return nil }
+
+func (noTicks) Range(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.Tick, error) {
+	return nil, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestExchangeService_WithoutStores(t *testing.T) {
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 83, Date: today}, true
+		},
+	}
+	svc := NewExchangeService(&mocks.RateRepositoryMock{}, WithCache(cache), WithLogger(logger.NewLogger("error")))
+
+	result, err := svc.ConvertCurrency(ctx, model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 2})
+	if err != nil || result.ToAmount != 166 {
+		t.Fatalf("Expected a conversion without a receipt store, got %+v, %v", result, err)
+	}
+	if _, err := svc.GetConversion(ctx, result.ID); !errors.Is(err, ErrConversionNotFound) {
+		t.Errorf("Expected no receipt to be kept, got %v", err)
+	}
+	if _, err := svc.CreateAnnotation(ctx, model.Annotation{Text: "Holiday", Date: today}); err == nil {
+		t.Error("Expected annotations to be refused without a store")
+	}
+	if annotations, err := svc.ListAnnotations(ctx, model.AnnotationFilter{}); err != nil || len(annotations) != 0 {
+		t.Errorf("Expected no annotations, got %v, %v", annotations, err)
+	}
+}
+
+func TestExchangeService_WithoutCache(t *testing.T) {
+	ctx := context.Background()
+	repository := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 83, Date: time.Now().UTC()}, nil
+		},
+	}
+	svc := NewExchangeService(repository)
+
+	for range 2 {
+		if rate, err := svc.GetLatestRate(ctx, model.USD, model.INR); err != nil || rate.Rate != 83 {
+			t.Fatalf("Expected the provider's rate without a cache, got %+v, %v", rate, err)
+		}
+	}
+	if calls := len(repository.FetchLatestRateCalls()); calls != 2 {
+		t.Errorf("Expected every lookup to call the provider without a cache, got %d calls", calls)
+	}
+}
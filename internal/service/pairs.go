@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// ListPairs returns every supported pair the allow and deny lists permit and
+// for which a latest rate is held, either in the cache or from the last
+// provider refresh. Pairs the provider did not quote are left out.
+func (s *ExchangeService) ListPairs(ctx context.Context) ([]model.PairAvailability, error) {
+	latest := make(map[model.CurrencyPair]model.ExchangeRate)
+	for _, rate := range s.repository.LatestRates(ctx) {
+		latest[model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}] = rate
+	}
+
+	now := s.clock.Now()
+	today := now.UTC().Truncate(24 * time.Hour)
+	pairs := make([]model.PairAvailability, 0)
+	for _, pair := range historyPairs(nil) {
+		if !s.currencies.AllowsPair(pair) {
+			continue
+		}
+
+		rate, found := latest[pair]
+		if cached, inCache := s.cache.Get(ctx, pair, today); inCache && (!found || cached.LastUpdated.After(rate.LastUpdated)) {
+			rate, found = *cached, true
+		}
+		if !found {
+			continue
+		}
+
+		pairs = append(pairs, model.PairAvailability{
+			BaseCurrency:   pair.BaseCurrency,
+			TargetCurrency: pair.TargetCurrency,
+			Rate:           rate.Rate,
+			LastUpdated:    rate.LastUpdated,
+			AgeSeconds:     int64(now.Sub(rate.LastUpdated).Seconds()),
+		})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].BaseCurrency != pairs[j].BaseCurrency {
+			return pairs[i].BaseCurrency < pairs[j].BaseCurrency
+		}
+		return pairs[i].TargetCurrency < pairs[j].TargetCurrency
+	})
+
+	return pairs, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// maxTimeframeDays is the longest range one provider timeframe call may cover
+const maxTimeframeDays = 365
+
+// fetchHistoricalRange answers a range from the cache and the history store
+// where it can, and fetches the remaining dates from the repository in as few
+// calls as possible. Only the fetched rates are returned in fetched. Dates that
+// failed are listed in the errors of rates, unless no date could be served.
+func (s *ExchangeService) fetchHistoricalRange(ctx context.Context, request model.HistoricalRateRequest) (rates *model.HistoricalRates, fetched []model.ExchangeRate, err error) {
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+	rates = &model.HistoricalRates{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		Rates:          make(map[string]model.ExchangeRate),
+	}
+
+	stored, err := s.history.Range(ctx, pair, request.StartDate, request.EndDate)
+	if err != nil {
+		s.log.Error("Failed to load stored historical rates", "error", err)
+	}
+	for _, rate := range stored {
+		rates.Rates[rate.Date.Format("2006-01-02")] = rate
+	}
+
+	missing := make([]time.Time, 0)
+	for date := request.StartDate; !date.After(request.EndDate); date = date.AddDate(0, 0, 1) {
+		dateKey := date.Format("2006-01-02")
+		if _, found := rates.Rates[dateKey]; found {
+			continue
+		}
+		if rate, found := s.cache.Get(ctx, pair, date); found {
+			rates.Rates[dateKey] = *rate
+			continue
+		}
+		missing = append(missing, date)
+	}
+
+	var spanErr error
+	for _, span := range planTimeframes(missing) {
+		spanRates, err := s.repository.FetchHistoricalRates(ctx, model.HistoricalRateRequest{
+			BaseCurrency:   request.BaseCurrency,
+			TargetCurrency: request.TargetCurrency,
+			StartDate:      span[0],
+			EndDate:        span[1],
+		})
+		if err != nil {
+			// The dates of a failed span are reported, the other spans still served
+			spanErr = err
+			for _, date := range missing {
+				if !date.Before(span[0]) && !date.After(span[1]) {
+					rates.Errors = append(rates.Errors, model.NewItemError(date.Format("2006-01-02"), err))
+				}
+			}
+			continue
+		}
+
+		// A span may cover dates already known; those keep the known rate
+		for dateKey, rate := range spanRates.Rates {
+			if _, known := rates.Rates[dateKey]; known {
+				continue
+			}
+			rates.Rates[dateKey] = rate
+			fetched = append(fetched, rate)
+		}
+		for _, itemErr := range spanRates.Errors {
+			if _, known := rates.Rates[itemErr.Item]; !known {
+				rates.Errors = append(rates.Errors, itemErr)
+			}
+		}
+	}
+
+	// With nothing to serve, the range fails as a whole
+	if len(rates.Rates) == 0 && spanErr != nil {
+		return nil, nil, spanErr
+	}
+	slices.SortFunc(rates.Errors, func(a, b model.ItemError) int {
+		return strings.Compare(a.Item, b.Item)
+	})
+
+	return rates, fetched, nil
+}
+
+// planTimeframes covers the sorted missing dates with the fewest ranges of at
+// most maxTimeframeDays each. A range may span known dates between missing
+// ones, since a provider call costs the same however many dates it returns.
+func planTimeframes(missing []time.Time) [][2]time.Time {
+	spans := make([][2]time.Time, 0)
+	for i := 0; i < len(missing); {
+		start := missing[i]
+		limit := start.AddDate(0, 0, maxTimeframeDays-1)
+
+		end := start
+		for i < len(missing) && !missing[i].After(limit) {
+			end = missing[i]
+			i++
+		}
+		spans = append(spans, [2]time.Time{start, end})
+	}
+	return spans
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestPlanTimeframes(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+	}
+	dates := func(days ...int) []time.Time {
+		result := make([]time.Time, 0, len(days))
+		for _, d := range days {
+			result = append(result, day(d))
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name    string
+		missing []time.Time
+		spans   [][2]int
+	}{
+		{name: "Nothing missing", missing: dates(), spans: [][2]int{}},
+		{name: "One date", missing: dates(3), spans: [][2]int{{3, 3}}},
+		{name: "Gaps within one timeframe share a call", missing: dates(0, 1, 5, 9), spans: [][2]int{{0, 9}}},
+		{name: "Split at the timeframe limit", missing: dates(0, 364, 365, 400), spans: [][2]int{{0, 364}, {365, 400}}},
+		{name: "Distant dates", missing: dates(0, 500), spans: [][2]int{{0, 0}, {500, 500}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spans := planTimeframes(tc.missing)
+			if len(spans) != len(tc.spans) {
+				t.Fatalf("Expected %d spans, got %v", len(tc.spans), spans)
+			}
+			for i, span := range spans {
+				if !span[0].Equal(day(tc.spans[i][0])) || !span[1].Equal(day(tc.spans[i][1])) {
+					t.Errorf("Expected span %d to be %v, got %v", i, tc.spans[i], span)
+				}
+			}
+		})
+	}
+}
+
+func TestGetHistoricalRatesFetchesOnlyMissingDates(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -10)
+	end := start.AddDate(0, 0, 9)
+	rate := func(date time.Time, value float64) model.ExchangeRate {
+		return model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: value, Date: date}
+	}
+
+	// Days 0-3 are stored and day 9 is cached, leaving days 4-8 to fetch
+	history := &mocks.HistoryStoreMock{
+		RangeFunc: func(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.ExchangeRate, error) {
+			stored := make([]model.ExchangeRate, 0)
+			for d := 0; d < 4; d++ {
+				stored = append(stored, rate(start.AddDate(0, 0, d), 80))
+			}
+			return stored, nil
+		},
+	}
+	var saved []model.ExchangeRate
+	history.SaveFunc = func(ctx context.Context, rates []model.ExchangeRate) error {
+		saved = rates
+		return nil
+	}
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			if date.Equal(end) {
+				cached := rate(date, 90)
+				return &cached, true
+			}
+			return nil, false
+		},
+	}
+
+	var requests []model.HistoricalRateRequest
+	repo := &mocks.RateRepositoryMock{
+		FetchHistoricalRatesFunc: func(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+			requests = append(requests, request)
+			rates := &model.HistoricalRates{Rates: map[string]model.ExchangeRate{}}
+			for d := request.StartDate; !d.After(request.EndDate); d = d.AddDate(0, 0, 1) {
+				rates.Rates[d.Format("2006-01-02")] = rate(d, 85)
+			}
+			return rates, nil
+		},
+	}
+	annotations := &mocks.AnnotationStoreMock{
+		ListFunc: func(ctx context.Context, filter model.AnnotationFilter) ([]model.Annotation, error) {
+			return nil, nil
+		},
+	}
+
+	service := NewExchangeService(repo, WithCache(cache), WithLogger(logger.NewLogger("error")), WithAnnotationStore(annotations), WithHistoryStore(history))
+	rates, err := service.GetHistoricalRates(context.Background(), model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      start,
+		EndDate:        end,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 || !requests[0].StartDate.Equal(start.AddDate(0, 0, 4)) || !requests[0].EndDate.Equal(start.AddDate(0, 0, 8)) {
+		t.Fatalf("Expected one provider call for days 4-8, got %+v", requests)
+	}
+	if len(rates.Rates) != 10 {
+		t.Fatalf("Expected 10 rates, got %d", len(rates.Rates))
+	}
+	for d, want := range map[int]float64{0: 80, 4: 85, 9: 90} {
+		if got := rates.Rates[start.AddDate(0, 0, d).Format("2006-01-02")].Rate; got != want {
+			t.Errorf("Expected day %d to be %v, got %v", d, want, got)
+		}
+	}
+	if len(saved) != 5 {
+		t.Errorf("Expected only the 5 fetched rates to be stored, got %d", len(saved))
+	}
+}
+
+func TestGetHistoricalRatesReportsFailedDates(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -5)
+	end := start.AddDate(0, 0, 2)
+	stored := model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 80, Date: start}
+
+	history := &mocks.HistoryStoreMock{
+		RangeFunc: func(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.ExchangeRate, error) {
+			return []model.ExchangeRate{stored}, nil
+		},
+	}
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return nil, false
+		},
+	}
+	var providerErr error
+	repo := &mocks.RateRepositoryMock{
+		FetchHistoricalRatesFunc: func(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+			return nil, providerErr
+		},
+	}
+	annotations := &mocks.AnnotationStoreMock{
+		ListFunc: func(ctx context.Context, filter model.AnnotationFilter) ([]model.Annotation, error) {
+			return nil, nil
+		},
+	}
+	service := NewExchangeService(repo, WithCache(cache), WithLogger(logger.NewLogger("error")), WithAnnotationStore(annotations), WithHistoryStore(history))
+	request := model.HistoricalRateRequest{BaseCurrency: model.USD, TargetCurrency: model.INR, StartDate: start, EndDate: end}
+
+	// The stored date is served, the two the provider failed on are listed
+	providerErr = &xerrors.Error{Kind: xerrors.Upstream, Code: "unreachable", Message: "failed to send request", Err: errors.New("dial tcp: https://provider.example/?access_key=secret")}
+	rates, err := service.GetHistoricalRates(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Expected a partial result, got %v", err)
+	}
+	if !rates.Partial() || len(rates.Rates) != 1 || len(rates.Errors) != 2 {
+		t.Fatalf("Expected 1 rate and 2 errors, got %+v", rates)
+	}
+	want := model.ItemError{Item: start.AddDate(0, 0, 1).Format("2006-01-02"), Code: "unreachable", Message: "failed to send request"}
+	if rates.Errors[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, rates.Errors[0])
+	}
+
+	// Without any date to serve, the range fails as a whole
+	history.RangeFunc = func(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.ExchangeRate, error) {
+		return nil, nil
+	}
+	if _, err := service.GetHistoricalRates(context.Background(), request); !errors.Is(err, ErrExternalAPIFailure) {
+		t.Errorf("Expected ErrExternalAPIFailure, got %v", err)
+	}
+}
+
+func TestGetHistoricalRatesStrict(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -5)
+	end := start.AddDate(0, 0, 2)
+
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return nil, false
+		},
+	}
+	repo := &mocks.RateRepositoryMock{
+		FetchHistoricalRatesFunc: func(ctx context.Context, request model.HistoricalRateRequest) (*model.HistoricalRates, error) {
+			// The provider has no rate for the middle date
+			rates := make(map[string]model.ExchangeRate)
+			for _, d := range []time.Time{start, end} {
+				rates[d.Format("2006-01-02")] = model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 80, Date: d}
+			}
+			return &model.HistoricalRates{BaseCurrency: model.USD, TargetCurrency: model.INR, Rates: rates}, nil
+		},
+	}
+	service := NewExchangeService(repo, WithCache(cache), WithLogger(logger.NewLogger("error")))
+	request := model.HistoricalRateRequest{BaseCurrency: model.USD, TargetCurrency: model.INR, StartDate: start, EndDate: end, Fill: model.FillPrevious}
+
+	if rates, err := service.GetHistoricalRates(context.Background(), request); err != nil || len(rates.Rates) != 3 {
+		t.Fatalf("Expected the gap to be filled, got %v, %v", rates, err)
+	}
+
+	// A filled date does not complete a strict range
+	request.Strict = true
+	_, err := service.GetHistoricalRates(context.Background(), request)
+	if !errors.Is(err, ErrIncompleteRange) || !strings.Contains(err.Error(), start.AddDate(0, 0, 1).Format("2006-01-02")) {
+		t.Errorf("Expected ErrIncompleteRange naming the missing date, got %v", err)
+	}
+}
+
+func TestGetHistoricalRatesMaxRange(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -9)
+	service := NewExchangeService(&mocks.RateRepositoryMock{}, WithCache(&mocks.RateCacheMock{}), WithLogger(logger.NewLogger("error")), WithMaxRangeDays(7))
+
+	request := model.HistoricalRateRequest{BaseCurrency: model.USD, TargetCurrency: model.INR, StartDate: start, EndDate: start.AddDate(0, 0, 7)}
+	_, err := service.GetHistoricalRates(context.Background(), request)
+	var limit *RangeLimitError
+	if !errors.Is(err, ErrRangeTooLong) || !errors.As(err, &limit) || limit.MaxDays != 7 {
+		t.Errorf("Expected an 8 day range to exceed the 7 day limit, got %v", err)
+	}
+	if xerrors.CodeOf(err) != "range_too_long" {
+		t.Errorf("Expected code range_too_long, got %q", xerrors.CodeOf(err))
+	}
+}
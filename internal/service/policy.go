@@ -0,0 +1,37 @@
+package service
+
+import (
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// currencyPolicy holds the model.CurrencyPolicy requests are checked against,
+// which may be replaced while they are served
+type currencyPolicy struct {
+	mutex  sync.RWMutex
+	policy model.CurrencyPolicy
+}
+
+func (p *currencyPolicy) get() model.CurrencyPolicy {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.policy
+}
+
+func (p *currencyPolicy) AllowsPair(pair model.CurrencyPair) bool {
+	return p.get().AllowsPair(pair)
+}
+
+// CurrencyPolicy returns the policy requests are checked against
+func (s *ExchangeService) CurrencyPolicy() model.CurrencyPolicy {
+	return s.currencies.get()
+}
+
+// SetCurrencyPolicy replaces the policy requests are checked against. Rates
+// already cached are kept; they are only served for pairs policy allows.
+func (s *ExchangeService) SetCurrencyPolicy(policy model.CurrencyPolicy) {
+	s.currencies.mutex.Lock()
+	defer s.currencies.mutex.Unlock()
+	s.currencies.policy = policy
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// popularRefresh selects the pairs each refresh fetches by how often they are
+// requested
+type popularRefresh struct {
+	table     ports.PairPopularity
+	count     int
+	fullEvery int
+	round     atomic.Int64
+}
+
+// UsePairPopularity records the pairs requested and lets most refreshes
+// update only the count most popular ones, refreshing every pair on every
+// fullEvery-th refresh, the first included. It has no effect on repositories
+// that cannot refresh a subset of pairs.
+func (s *ExchangeService) UsePairPopularity(table ports.PairPopularity, count, fullEvery int) {
+	s.popularity = &popularRefresh{table: table, count: count, fullEvery: fullEvery}
+}
+
+// recordRequest counts a request for pair towards its popularity, unless it
+// is a dry run
+func (s *ExchangeService) recordRequest(ctx context.Context, pair model.CurrencyPair) {
+	if s.popularity != nil && !model.RequestOptionsFromContext(ctx).DryRun {
+		s.popularity.table.Record(ctx, pair)
+	}
+}
+
+// pairsDue returns the pairs the current refresh should fetch, with partial
+// false when it is to refresh every pair
+func (s *ExchangeService) pairsDue(ctx context.Context) (pairs []model.CurrencyPair, partial bool) {
+	if s.popularity == nil {
+		return nil, false
+	}
+	if _, ok := s.repository.(ports.PairRefresher); !ok {
+		return nil, false
+	}
+
+	round := s.popularity.round.Add(1) - 1
+	if s.popularity.fullEvery <= 1 || round%int64(s.popularity.fullEvery) == 0 {
+		return nil, false
+	}
+	return s.popularity.table.Popular(ctx, s.popularity.count), true
+}
+
+// ratesOf returns the rates of pairs among rates
+func ratesOf(rates []model.ExchangeRate, pairs []model.CurrencyPair) []model.ExchangeRate {
+	wanted := make(map[model.CurrencyPair]bool, len(pairs))
+	for _, pair := range pairs {
+		wanted[pair] = true
+	}
+
+	selected := make([]model.ExchangeRate, 0, len(pairs))
+	for _, rate := range rates {
+		if wanted[model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}] {
+			selected = append(selected, rate)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+type partialRepository struct {
+	mocks.RateRepositoryMock
+	refreshed [][]model.CurrencyPair
+}
+
+func (r *partialRepository) RefreshRates(ctx context.Context) error {
+	r.refreshed = append(r.refreshed, nil)
+	return nil
+}
+
+func (r *partialRepository) RefreshPairs(ctx context.Context, pairs []model.CurrencyPair) error {
+	r.refreshed = append(r.refreshed, pairs)
+	return nil
+}
+
+func TestPopularityDrivenRefresh(t *testing.T) {
+	usdInr := model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+
+	repository := &partialRepository{}
+	repository.FetchLatestRateFunc = func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+		return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 1}, nil
+	}
+	repository.LatestRatesFunc = func(ctx context.Context) []model.ExchangeRate {
+		return []model.ExchangeRate{
+			{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83},
+			{BaseCurrency: model.EUR, TargetCurrency: model.GBP, Rate: 0.85},
+		}
+	}
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return nil, false
+		},
+		SetFunc:          func(ctx context.Context, rate *model.ExchangeRate) error { return nil },
+		ClearExpiredFunc: func(ctx context.Context) error { return nil },
+	}
+	var recorded []model.CurrencyPair
+	popularity := &mocks.PairPopularityMock{
+		RecordFunc: func(ctx context.Context, pair model.CurrencyPair) {
+			recorded = append(recorded, pair)
+		},
+		PopularFunc: func(ctx context.Context, limit int) []model.CurrencyPair {
+			if len(recorded) > limit {
+				return recorded[:limit]
+			}
+			return recorded
+		},
+	}
+	publisher := &mocks.RatePublisherMock{
+		PublishFunc: func(ctx context.Context, rates []model.ExchangeRate) error { return nil },
+	}
+
+	svc := NewExchangeService(repository, WithCache(cache), WithLogger(logger.NewLogger("error")), WithPairPopularity(popularity, 1, 3), WithPublisher(publisher))
+	ctx := context.Background()
+
+	refresh := func() {
+		t.Helper()
+		if err := svc.RefreshRates(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first refresh is full, and the second has no requested pair to fetch
+	refresh()
+	refresh()
+	if len(repository.refreshed) != 1 || repository.refreshed[0] != nil {
+		t.Fatalf("Expected one full refresh and one skipped, got %v", repository.refreshed)
+	}
+
+	if _, err := svc.GetLatestRate(ctx, model.USD, model.INR); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetLatestRate(ctx, model.EUR, model.GBP); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 2 {
+		t.Fatalf("Expected both requests recorded, got %v", recorded)
+	}
+
+	refresh()
+	if len(repository.refreshed) != 2 || len(repository.refreshed[1]) != 1 || repository.refreshed[1][0] != usdInr {
+		t.Fatalf("Expected a partial refresh of the most popular pair, got %v", repository.refreshed)
+	}
+	published := publisher.PublishCalls()
+	last := published[len(published)-1].Rates
+	if len(last) != 1 || last[0].TargetCurrency != model.INR {
+		t.Errorf("Expected only the refreshed pair to be published, got %v", last)
+	}
+
+	refresh()
+	if len(repository.refreshed) != 3 || repository.refreshed[2] != nil {
+		t.Fatalf("Expected every third refresh to be full, got %v", repository.refreshed)
+	}
+	published = publisher.PublishCalls()
+	if last := published[len(published)-1].Rates; len(last) != 2 {
+		t.Errorf("Expected a full refresh to publish every pair, got %v", last)
+	}
+}
+
+func TestDryRunsDoNotCountTowardsPopularity(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, precomputed := range []bool{false, true} {
+		var cacheLookups int
+		svc := newPrecomputeService(&cacheLookups)
+		var recorded int
+		svc.UsePairPopularity(&mocks.PairPopularityMock{
+			RecordFunc: func(ctx context.Context, pair model.CurrencyPair) { recorded++ },
+		}, 1, 3)
+		if precomputed {
+			svc.UsePrecomputedAmounts([]float64{100})
+			svc.publish(context.Background(), []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: today}})
+		}
+
+		request := model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100, DryRun: true}
+		if _, err := svc.ConvertCurrency(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		if recorded != 0 {
+			t.Errorf("Expected a dry run not to be counted (precomputed %v), got %d", precomputed, recorded)
+		}
+
+		request.DryRun = false
+		if _, err := svc.ConvertCurrency(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		if recorded != 1 {
+			t.Errorf("Expected a conversion to be counted (precomputed %v), got %d", precomputed, recorded)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+type precomputedKey struct {
+	pair   model.CurrencyPair
+	amount float64
+}
+
+// precomputedConversions holds, for every published pair, the conversions of
+// a few popular amounts at the latest rate. It is replaced as a whole on each
+// publish, so lookups never wait for a refresh.
+type precomputedConversions struct {
+	amounts []float64
+	allows  func(from, to model.Currency) bool
+	table   atomic.Pointer[map[precomputedKey]*model.ConversionResult]
+}
+
+// UsePrecomputedAmounts converts amounts for every published pair whenever
+// rates are published, and serves matching latest conversions from that
+// table. Like AddPublisher it must be called before the service starts
+// refreshing. The table is bypassed while a rate adjuster is in use, since
+// adjusted rates differ per tenant.
+func (s *ExchangeService) UsePrecomputedAmounts(amounts []float64) {
+	if len(amounts) == 0 {
+		return
+	}
+
+	s.precomputed = &precomputedConversions{
+		amounts: amounts,
+		allows: func(from, to model.Currency) bool {
+			return s.checkPair(from, to) == nil
+		},
+	}
+	s.AddPublisher(s.precomputed)
+}
+
+// Publish adds the conversions of rates to the table, keeping those of pairs
+// not in rates, such as the intraday pairs sampled between refreshes
+func (p *precomputedConversions) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	table := make(map[precomputedKey]*model.ConversionResult)
+	if current := p.table.Load(); current != nil {
+		for key, result := range *current {
+			table[key] = result
+		}
+	}
+
+	for _, rate := range rates {
+		if !p.allows(rate.BaseCurrency, rate.TargetCurrency) {
+			continue
+		}
+
+		pair := model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}
+		snapshot := rate
+		for _, amount := range p.amounts {
+			table[precomputedKey{pair, amount}] = &model.ConversionResult{
+				FromCurrency: rate.BaseCurrency,
+				ToCurrency:   rate.TargetCurrency,
+				FromAmount:   amount,
+				ToAmount:     amount * rate.Rate,
+				Rate:         rate.Rate,
+				Date:         rate.Date,
+				RateSnapshot: &snapshot,
+				Rounding:     model.RoundingNone,
+			}
+		}
+	}
+
+	p.table.Store(&table)
+	return nil
+}
+
+// lookup returns a copy of the precomputed conversion of request, if it is a
+// plain conversion of a popular amount at today's rate
+func (p *precomputedConversions) lookup(request model.ConversionRequest, today time.Time) (*model.ConversionResult, bool) {
+	if !request.Date.IsZero() || request.CashRounding || request.Unit != "" {
+		return nil, false
+	}
+
+	table := p.table.Load()
+	if table == nil {
+		return nil, false
+	}
+	precomputed, found := (*table)[precomputedKey{model.CurrencyPair{BaseCurrency: request.FromCurrency, TargetCurrency: request.ToCurrency}, request.Amount}]
+	if !found || !precomputed.Date.Equal(today) {
+		return nil, false
+	}
+
+	result := *precomputed
+	return &result, true
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func newPrecomputeService(cacheLookups *int) *ExchangeService {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			*cacheLookups++
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 82.5, Date: today}, true
+		},
+	}
+	conversions := &mocks.ConversionStoreMock{
+		SaveFunc: func(ctx context.Context, result *model.ConversionResult) error { return nil },
+	}
+	return NewExchangeService(&mocks.RateRepositoryMock{}, WithCache(cache), WithLogger(logger.NewLogger("error")), WithConversionStore(conversions))
+}
+
+func TestPrecomputedConversions(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	rates := []model.ExchangeRate{
+		{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: today},
+		{BaseCurrency: model.USD, TargetCurrency: model.EUR, Rate: 0.9, Date: today.AddDate(0, 0, -1)},
+	}
+
+	testCases := []struct {
+		name          string
+		request       model.ConversionRequest
+		adjusted      bool
+		expectedRate  float64
+		expectedCache int
+	}{
+		{
+			name:         "Popular amount is served from the table",
+			request:      model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100},
+			expectedRate: 83,
+		},
+		{
+			name:          "Other amounts are computed",
+			request:       model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 42},
+			expectedRate:  82.5,
+			expectedCache: 1,
+		},
+		{
+			name:          "Historical conversions are computed",
+			request:       model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100, Date: today},
+			expectedRate:  82.5,
+			expectedCache: 1,
+		},
+		{
+			name:          "Cash rounding is computed",
+			request:       model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100, CashRounding: true},
+			expectedRate:  82.5,
+			expectedCache: 1,
+		},
+		{
+			name:          "Rates of an earlier day are not served",
+			request:       model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.EUR, Amount: 100},
+			expectedRate:  82.5,
+			expectedCache: 1,
+		},
+		{
+			name:          "Adjusted rates bypass the table",
+			request:       model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100},
+			adjusted:      true,
+			expectedRate:  82.5 * 1.01,
+			expectedCache: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cacheLookups int
+			service := newPrecomputeService(&cacheLookups)
+			service.UsePrecomputedAmounts([]float64{1, 10, 100, 1000})
+			if tc.adjusted {
+				service.UseRateAdjuster(&mocks.RateAdjusterMock{
+					AdjustFunc: func(tenant string, pair model.CurrencyPair, rate float64) float64 { return rate * 1.01 },
+				})
+			}
+			service.publish(context.Background(), rates)
+
+			result, err := service.ConvertCurrency(context.Background(), tc.request)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if result.Rate != tc.expectedRate {
+				t.Errorf("Expected rate: %f, got: %f", tc.expectedRate, result.Rate)
+			}
+			if result.ToAmount != tc.request.Amount*tc.expectedRate {
+				t.Errorf("Expected to amount: %f, got: %f", tc.request.Amount*tc.expectedRate, result.ToAmount)
+			}
+			if cacheLookups != tc.expectedCache {
+				t.Errorf("Expected %d cache lookups, got: %d", tc.expectedCache, cacheLookups)
+			}
+			if result.ID == "" || result.CreatedAt.IsZero() {
+				t.Error("Expected conversion ID and creation time to be set")
+			}
+		})
+	}
+}
+
+func TestPrecomputedConversionsAreCopies(t *testing.T) {
+	var cacheLookups int
+	service := newPrecomputeService(&cacheLookups)
+	service.UsePrecomputedAmounts([]float64{100})
+	service.publish(context.Background(), []model.ExchangeRate{
+		{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: 83, Date: time.Now().UTC().Truncate(24 * time.Hour)},
+	})
+
+	request := model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100}
+	first, _ := service.ConvertCurrency(context.Background(), request)
+	second, _ := service.ConvertCurrency(context.Background(), request)
+	if first.ID == second.ID {
+		t.Errorf("Expected distinct conversion IDs, got %s twice", first.ID)
+	}
+}
+
+// BenchmarkConvertCurrency compares a popular amount served from the
+// precomputed table with one computed from a cached rate
+func BenchmarkConvertCurrency(b *testing.B) {
+	rates := make([]model.ExchangeRate, 0, len(model.SupportedCurrencies))
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, target := range model.SupportedCurrencies {
+		if target != model.USD {
+			rates = append(rates, model.ExchangeRate{BaseCurrency: model.USD, TargetCurrency: target, Rate: 1.5, Date: today})
+		}
+	}
+	request := model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.INR, Amount: 100, DryRun: true}
+
+	for _, bc := range []struct {
+		name    string
+		amounts []float64
+	}{
+		{"computed", nil},
+		{"precomputed", []float64{1, 10, 100, 1000}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var cacheLookups int
+			service := newPrecomputeService(&cacheLookups)
+			service.UsePrecomputedAmounts(bc.amounts)
+			service.publish(context.Background(), rates)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := service.ConvertCurrency(ctx, request); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
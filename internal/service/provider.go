@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// ProviderSLA summarizes upstream call outcomes per provider over the given window
+func (s *ExchangeService) ProviderSLA(ctx context.Context, window time.Duration) ([]model.ProviderSLASummary, error) {
+	now := s.clock.Now().UTC()
+	buckets, err := s.sla.Buckets(ctx, now.Add(-window))
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]*model.ProviderSLASummary)
+	latencies := make(map[string][]int)
+
+	for _, bucket := range buckets {
+		summary, exists := summaries[bucket.Provider]
+		if !exists {
+			summary = &model.ProviderSLASummary{
+				Provider: bucket.Provider,
+				Window:   window.String(),
+				Hourly:   make([]model.ProviderSLAPoint, 0),
+			}
+			summaries[bucket.Provider] = summary
+			latencies[bucket.Provider] = make([]int, len(bucket.LatencyCounts))
+		}
+
+		summary.Requests += bucket.Requests
+		summary.Failures += bucket.Failures
+		if bucket.LastSuccess.After(summary.LastSuccess) {
+			summary.LastSuccess = bucket.LastSuccess
+		}
+		for i, c := range bucket.LatencyCounts {
+			latencies[bucket.Provider][i] += c
+		}
+
+		summary.Hourly = append(summary.Hourly, model.ProviderSLAPoint{
+			Hour:         bucket.Hour,
+			Requests:     bucket.Requests,
+			SuccessRate:  percent(bucket.Requests-bucket.Failures, bucket.Requests),
+			LatencyP90Ms: model.LatencyPercentile(bucket.LatencyCounts, 0.90),
+		})
+	}
+
+	result := make([]model.ProviderSLASummary, 0, len(summaries))
+	for provider, summary := range summaries {
+		summary.SuccessRate = percent(summary.Requests-summary.Failures, summary.Requests)
+		summary.LatencyP50Ms = model.LatencyPercentile(latencies[provider], 0.50)
+		summary.LatencyP90Ms = model.LatencyPercentile(latencies[provider], 0.90)
+		summary.LatencyP99Ms = model.LatencyPercentile(latencies[provider], 0.99)
+		if !summary.LastSuccess.IsZero() {
+			summary.FreshnessSeconds = now.Sub(summary.LastSuccess).Seconds()
+		}
+		result = append(result, *summary)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Provider < result[j].Provider
+	})
+
+	return result, nil
+}
+
+// ProviderHealth reports the failover state of each provider when rates come
+// from a provider chain
+func (s *ExchangeService) ProviderHealth(ctx context.Context) ([]model.ProviderHealth, error) {
+	reporter, ok := s.repository.(ports.ProviderHealthReporter)
+	if !ok {
+		return nil, ErrNoProviderChain
+	}
+	return reporter.ProviderHealth(), nil
+}
+
+// ProviderStatus reports each upstream provider: the failover state of the
+// providers of the chain, in failover order, then any other provider that was
+// called during window. Latencies cover window.
+func (s *ExchangeService) ProviderStatus(ctx context.Context, window time.Duration) ([]model.ProviderStatus, error) {
+	summaries, err := s.ProviderSLA(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	var health []model.ProviderHealth
+	if reporter, ok := s.repository.(ports.ProviderHealthReporter); ok {
+		health = reporter.ProviderHealth()
+	}
+
+	statuses := make([]model.ProviderStatus, 0, len(health)+len(summaries))
+	index := make(map[string]int, len(health)+len(summaries))
+	for _, provider := range health {
+		index[provider.Provider] = len(statuses)
+		statuses = append(statuses, model.ProviderStatus{
+			Provider:            provider.Provider,
+			LastSuccess:         provider.LastSuccess,
+			ConsecutiveFailures: provider.ConsecutiveFailures,
+			LastError:           provider.LastError,
+			Window:              window.String(),
+			CircuitOpen:         !provider.Healthy,
+			RetryAt:             provider.RetryAt,
+		})
+	}
+
+	// Summaries are sorted by provider, so providers outside the chain are too
+	for _, summary := range summaries {
+		i, exists := index[summary.Provider]
+		if !exists {
+			i = len(statuses)
+			statuses = append(statuses, model.ProviderStatus{Provider: summary.Provider, Window: window.String()})
+		}
+
+		status := &statuses[i]
+		status.Requests = summary.Requests
+		status.LatencyP50Ms = summary.LatencyP50Ms
+		status.LatencyP90Ms = summary.LatencyP90Ms
+		status.LatencyP99Ms = summary.LatencyP99Ms
+		if summary.LastSuccess.After(status.LastSuccess) {
+			status.LastSuccess = summary.LastSuccess
+		}
+	}
+
+	return statuses, nil
+}
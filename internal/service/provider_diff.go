@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// DiffProviders fetches the same range from the primary and secondary
+// providers and compares them day by day
+func (s *ExchangeService) DiffProviders(ctx context.Context, request model.ProviderDiffRequest) (*model.ProviderDiff, error) {
+	if s.secondary == nil {
+		return nil, ErrNoSecondaryProvider
+	}
+
+	if !request.Pair.BaseCurrency.IsSupported() || !request.Pair.TargetCurrency.IsSupported() {
+		return nil, ErrInvalidCurrency
+	}
+
+	if err := s.validateDateRange(request.StartDate, request.EndDate); err != nil {
+		return nil, err
+	}
+
+	rangeRequest := model.HistoricalRateRequest{
+		BaseCurrency:   request.Pair.BaseCurrency,
+		TargetCurrency: request.Pair.TargetCurrency,
+		StartDate:      request.StartDate,
+		EndDate:        request.EndDate,
+	}
+
+	var primary, secondary *model.HistoricalRates
+	var primaryErr, secondaryErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		primary, primaryErr = s.repository.FetchHistoricalRates(ctx, rangeRequest)
+	}()
+	go func() {
+		defer wg.Done()
+		secondary, secondaryErr = s.secondary.FetchHistoricalRates(ctx, rangeRequest)
+	}()
+	wg.Wait()
+
+	if primaryErr != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrExternalAPIFailure, s.repository.Name(), primaryErr)
+	}
+	if secondaryErr != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrExternalAPIFailure, s.secondary.Name(), secondaryErr)
+	}
+
+	diff := diffRates(primary.Rates, secondary.Rates, rangeRequest, request.Tolerance)
+	diff.PrimaryProvider = s.repository.Name()
+	diff.SecondaryProvider = s.secondary.Name()
+
+	return diff, nil
+}
+
+// diffRates compares two sets of daily rates keyed by date. The relative
+// difference is measured against the primary rate.
+func diffRates(primary, secondary map[string]model.ExchangeRate, request model.HistoricalRateRequest, tolerance float64) *model.ProviderDiff {
+	diff := &model.ProviderDiff{
+		BaseCurrency:     request.BaseCurrency,
+		TargetCurrency:   request.TargetCurrency,
+		TolerancePercent: tolerance,
+		Days:             make([]model.ProviderDiffDay, 0),
+	}
+
+	var sumPct, sumAbsPct float64
+	for d := request.StartDate; !d.After(request.EndDate); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		day := model.ProviderDiffDay{Date: date}
+		diff.Summary.Days++
+
+		p, hasPrimary := primary[date]
+		sec, hasSecondary := secondary[date]
+		if hasPrimary {
+			day.PrimaryRate = &p.Rate
+		} else {
+			diff.Summary.MissingPrimary++
+		}
+		if hasSecondary {
+			day.SecondaryRate = &sec.Rate
+		} else {
+			diff.Summary.MissingSecondary++
+		}
+
+		if hasPrimary && hasSecondary && p.Rate != 0 {
+			day.Difference = sec.Rate - p.Rate
+			day.DifferencePercent = day.Difference / p.Rate * 100
+			day.ExceedsTolerance = math.Abs(day.DifferencePercent) > tolerance
+
+			diff.Summary.Compared++
+			sumPct += day.DifferencePercent
+			sumAbsPct += math.Abs(day.DifferencePercent)
+			if math.Abs(day.DifferencePercent) > diff.Summary.MaxAbsDifferencePct {
+				diff.Summary.MaxAbsDifferencePct = math.Abs(day.DifferencePercent)
+				diff.Summary.MaxDifferenceDate = date
+			}
+			if day.ExceedsTolerance {
+				diff.Summary.ExceedingTolerance++
+			}
+		}
+
+		diff.Days = append(diff.Days, day)
+	}
+
+	if diff.Summary.Compared > 0 {
+		diff.Summary.MeanDifferencePct = sumPct / float64(diff.Summary.Compared)
+		diff.Summary.MeanAbsDifferencePct = sumAbsPct / float64(diff.Summary.Compared)
+	}
+
+	return diff
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+func TestDiffRates(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	rates := func(values map[int]float64) map[string]model.ExchangeRate {
+		result := make(map[string]model.ExchangeRate)
+		for d, v := range values {
+			result[day(d).Format("2006-01-02")] = model.ExchangeRate{Rate: v, Date: day(d)}
+		}
+		return result
+	}
+
+	request := model.HistoricalRateRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      day(1),
+		EndDate:        day(4),
+	}
+	primary := rates(map[int]float64{1: 100, 2: 100, 3: 100})
+	secondary := rates(map[int]float64{1: 100.2, 2: 99, 4: 100})
+
+	diff := diffRates(primary, secondary, request, 0.5)
+
+	if len(diff.Days) != 4 {
+		t.Fatalf("expected 4 days, got %d", len(diff.Days))
+	}
+	if diff.Summary.Compared != 2 || diff.Summary.MissingPrimary != 1 || diff.Summary.MissingSecondary != 1 {
+		t.Errorf("unexpected counts: %+v", diff.Summary)
+	}
+	if diff.Days[0].ExceedsTolerance || !diff.Days[1].ExceedsTolerance {
+		t.Errorf("expected only 2025-03-02 to exceed tolerance: %+v", diff.Days[:2])
+	}
+	if diff.Summary.ExceedingTolerance != 1 || diff.Summary.MaxDifferenceDate != "2025-03-02" {
+		t.Errorf("unexpected tolerance summary: %+v", diff.Summary)
+	}
+	if math.Abs(diff.Summary.MeanDifferencePct-(-0.4)) > 1e-9 {
+		t.Errorf("expected mean difference -0.4%%, got %v", diff.Summary.MeanDifferencePct)
+	}
+	if diff.Days[2].SecondaryRate != nil || diff.Days[3].PrimaryRate != nil {
+		t.Errorf("expected missing rates to be nil: %+v", diff.Days[2:])
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+// healthReportingRepository is a provider chain as far as the service can tell
+type healthReportingRepository struct {
+	*mocks.RateRepositoryMock
+	*mocks.ProviderHealthReporterMock
+}
+
+func TestProviderStatus(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC))
+	hour := fake.Now().Truncate(time.Hour)
+	retryAt := fake.Now().Add(time.Minute)
+
+	repository := healthReportingRepository{
+		RateRepositoryMock: &mocks.RateRepositoryMock{NameFunc: func() string { return "primary" }},
+		ProviderHealthReporterMock: &mocks.ProviderHealthReporterMock{
+			ProviderHealthFunc: func() []model.ProviderHealth {
+				return []model.ProviderHealth{
+					{Provider: "primary", Healthy: false, ConsecutiveFailures: 3, LastError: "timeout", LastSuccess: hour.Add(-2 * time.Hour), RetryAt: retryAt},
+					{Provider: "backup", Healthy: true, Active: true},
+				}
+			},
+		},
+	}
+	sla := &mocks.SLAStoreMock{
+		BucketsFunc: func(ctx context.Context, since time.Time) ([]model.ProviderSLABucket, error) {
+			return []model.ProviderSLABucket{
+				{Provider: "backup", Hour: hour, Requests: 4, LatencyCounts: []int{0, 4, 0, 0, 0, 0, 0, 0, 0}, LastSuccess: hour.Add(10 * time.Minute)},
+				{Provider: "crypto", Hour: hour, Requests: 2, LatencyCounts: []int{2, 0, 0, 0, 0, 0, 0, 0, 0}, LastSuccess: hour},
+			}, nil
+		},
+	}
+	svc := NewExchangeService(repository, WithCache(&mocks.RateCacheMock{}), WithLogger(logger.NewLogger("error")), WithClock(fake), WithSLAStore(sla))
+
+	statuses, err := svc.ProviderStatus(context.Background(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 3 || statuses[0].Provider != "primary" || statuses[1].Provider != "backup" || statuses[2].Provider != "crypto" {
+		t.Fatalf("Expected the chain in failover order, then other providers, got %+v", statuses)
+	}
+	primary := statuses[0]
+	if !primary.CircuitOpen || primary.ConsecutiveFailures != 3 || !primary.RetryAt.Equal(retryAt) || primary.Requests != 0 {
+		t.Errorf("Expected primary to be skipped after 3 failures, got %+v", primary)
+	}
+	backup := statuses[1]
+	if backup.CircuitOpen || backup.Requests != 4 || backup.LatencyP99Ms != 100 || !backup.LastSuccess.Equal(hour.Add(10*time.Minute)) {
+		t.Errorf("Expected backup to carry its SLA figures, got %+v", backup)
+	}
+	if statuses[2].LatencyP50Ms != 50 || statuses[2].Window != "1h0m0s" {
+		t.Errorf("Expected crypto latencies over the window, got %+v", statuses[2])
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// AddPublisher registers p to receive fresh rates. Publishers must be added
+// before the service starts refreshing.
+func (s *ExchangeService) AddPublisher(p ports.RatePublisher) {
+	s.publishers = append(s.publishers, p)
+}
+
+func (s *ExchangeService) publish(ctx context.Context, rates []model.ExchangeRate) {
+	if len(rates) == 0 {
+		return
+	}
+	for _, p := range s.publishers {
+		if err := p.Publish(ctx, rates); err != nil {
+			s.log.Error("Failed to publish rates", "error", err, "count", len(rates))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// ApplyProviderPush stores rates pushed by the primary provider in the cache so
+// they are served right away instead of after the next refresh. Targets that
+// are not accepted or carry no usable rate are skipped.
+func (s *ExchangeService) ApplyProviderPush(ctx context.Context, push model.ProviderPush) (*model.ProviderPushResult, error) {
+	// Only the primary provider backs the cache; a push from any other would mix sources
+	if push.Provider != s.repository.Name() {
+		return nil, ErrUnknownProvider
+	}
+
+	if !s.acceptsCurrency(push.Base) {
+		return nil, ErrInvalidCurrency
+	}
+
+	now := s.clock.Now().UTC()
+	observed := now
+	if !push.Timestamp.IsZero() {
+		observed = push.Timestamp.UTC()
+	}
+	date := observed.Truncate(24 * time.Hour)
+
+	result := &model.ProviderPushResult{Provider: push.Provider}
+	ticks := make([]model.Tick, 0, len(push.Rates))
+	applied := make([]model.ExchangeRate, 0, len(push.Rates))
+
+	for target, value := range push.Rates {
+		pair := model.CurrencyPair{BaseCurrency: push.Base, TargetCurrency: target}
+		if target == push.Base || value <= 0 || !s.acceptsCurrency(target) || !s.currencies.AllowsPair(pair) {
+			result.Skipped = append(result.Skipped, target)
+			continue
+		}
+
+		rate := &model.ExchangeRate{
+			BaseCurrency:   push.Base,
+			TargetCurrency: target,
+			Rate:           value,
+			Date:           date,
+			LastUpdated:    now,
+			Unit:           pair.QuoteUnit(),
+		}
+		if err := s.cache.Set(ctx, rate); err != nil {
+			s.log.Error("Failed to cache pushed exchange rate", "error", err, "pair", pair.String())
+			result.Skipped = append(result.Skipped, target)
+			continue
+		}
+		result.Applied++
+		applied = append(applied, *rate)
+		ticks = append(ticks, model.Tick{Pair: pair, Rate: value, Time: observed})
+	}
+
+	// Pushes double as intraday ticks, at the provider's own timestamp
+	if err := s.ticks.Append(ctx, ticks); err != nil {
+		s.log.Error("Failed to store pushed ticks", "error", err, "provider", push.Provider)
+	}
+
+	s.publish(ctx, applied)
+
+	sort.Slice(result.Skipped, func(i, j int) bool {
+		return result.Skipped[i] < result.Skipped[j]
+	})
+
+	s.log.Info("Applied provider push", "provider", push.Provider, "base", push.Base, "applied", result.Applied, "skipped", len(result.Skipped))
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// defaultRateLimitCooldown is how long refreshes pause after the provider
+// rate limited one without saying for how long
+const defaultRateLimitCooldown = time.Minute
+
+// rateLimitCooldown pauses provider refreshes after the provider rate limited
+// one, for as long as its Retry-After asked or else for fallback
+type rateLimitCooldown struct {
+	mutex    sync.Mutex
+	fallback time.Duration
+	provider string
+	until    time.Time
+}
+
+// UseRateLimitCooldown sets how long refreshes pause after the provider rate
+// limited one without a Retry-After
+func (s *ExchangeService) UseRateLimitCooldown(cooldown time.Duration) {
+	s.rateLimit.mutex.Lock()
+	defer s.rateLimit.mutex.Unlock()
+
+	s.rateLimit.fallback = cooldown
+}
+
+// ProviderRateLimit reports whether refreshes are paused because the provider
+// rate limited them, and until when
+func (s *ExchangeService) ProviderRateLimit(ctx context.Context) model.ProviderRateLimit {
+	s.rateLimit.mutex.Lock()
+	defer s.rateLimit.mutex.Unlock()
+
+	remaining := s.rateLimit.until.Sub(s.clock.Now())
+	if remaining <= 0 {
+		return model.ProviderRateLimit{}
+	}
+	return model.ProviderRateLimit{
+		Limited:   true,
+		Provider:  s.rateLimit.provider,
+		Until:     s.rateLimit.until,
+		Remaining: remaining,
+	}
+}
+
+// coolingDown returns ErrProviderRateLimited while refreshes are paused
+func (s *ExchangeService) coolingDown(ctx context.Context) error {
+	if limit := s.ProviderRateLimit(ctx); limit.Limited {
+		s.log.Info("Provider rate limited, skipping the refresh", "provider", limit.Provider, "retry_in", limit.Remaining.String())
+		return ErrProviderRateLimited
+	}
+	return nil
+}
+
+// noteRateLimit starts a cool-down when err is the provider rate limiting a
+// refresh
+func (s *ExchangeService) noteRateLimit(err error) {
+	limited, ok := xerrors.Find(err)
+	if !ok || limited.Kind != xerrors.RateLimited {
+		return
+	}
+
+	s.rateLimit.mutex.Lock()
+	defer s.rateLimit.mutex.Unlock()
+
+	cooldown := limited.RetryAfter
+	if cooldown <= 0 {
+		cooldown = s.rateLimit.fallback
+	}
+	s.rateLimit.provider = limited.Provider
+	s.rateLimit.until = s.clock.Now().Add(cooldown)
+	s.log.Warn("Provider rate limited refreshes, pausing them", "provider", limited.Provider, "cooldown", cooldown.String())
+}
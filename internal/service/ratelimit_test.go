@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+func TestRefreshPausesWhileRateLimited(t *testing.T) {
+	var refreshErr error
+	repository := &mocks.RateRepositoryMock{
+		RefreshRatesFunc: func(ctx context.Context) error { return refreshErr },
+	}
+	cache := &mocks.RateCacheMock{
+		ClearExpiredFunc: func(ctx context.Context) error { return nil },
+	}
+	fake := clock.NewFake(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	svc := NewExchangeService(repository, WithCache(cache), WithLogger(logger.NewLogger("error")), WithClock(fake), WithRateLimitCooldown(time.Minute))
+	ctx := context.Background()
+
+	refreshErr = &xerrors.Error{Kind: xerrors.RateLimited, Code: "http_429", Provider: "primary", RetryAfter: 30 * time.Second}
+	if err := svc.RefreshRates(ctx); !errors.Is(err, xerrors.RateLimited) {
+		t.Fatalf("Expected a rate limited error, got %v", err)
+	}
+	limit := svc.ProviderRateLimit(ctx)
+	if !limit.Limited || limit.Provider != "primary" || limit.Remaining != 30*time.Second {
+		t.Fatalf("Expected a 30s cool-down for primary, got %+v", limit)
+	}
+
+	// The provider is not called again until Retry-After has passed
+	refreshErr = nil
+	if err := svc.RefreshRates(ctx); !errors.Is(err, ErrProviderRateLimited) {
+		t.Errorf("Expected ErrProviderRateLimited during the cool-down, got %v", err)
+	}
+	if err := svc.SampleIntraday(ctx, nil); !errors.Is(err, ErrProviderRateLimited) {
+		t.Errorf("Expected intraday sampling to pause too, got %v", err)
+	}
+	if calls := len(repository.RefreshRatesCalls()); calls != 1 {
+		t.Errorf("Expected one provider call, got %d", calls)
+	}
+
+	fake.Advance(30 * time.Second)
+	if err := svc.RefreshRates(ctx); err != nil {
+		t.Fatalf("Expected refreshes to resume, got %v", err)
+	}
+	if svc.ProviderRateLimit(ctx).Limited {
+		t.Error("Expected the cool-down to be over")
+	}
+
+	// Without Retry-After the configured cool-down applies
+	refreshErr = &xerrors.Error{Kind: xerrors.RateLimited, Code: "usage_limit_reached", Provider: "primary"}
+	svc.RefreshRates(ctx)
+	if limit := svc.ProviderRateLimit(ctx); limit.Remaining != time.Minute {
+		t.Errorf("Expected the 1m default cool-down, got %+v", limit)
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// preferredProvider returns the provider the options of ctx ask latest rates
+// from, or nil for the primary one
+func (s *ExchangeService) preferredProvider(ctx context.Context) (ports.RateRepository, error) {
+	name := model.RequestOptionsFromContext(ctx).Provider
+	if name == "" || name == s.repository.Name() {
+		return nil, nil
+	}
+	if s.secondary != nil && name == s.secondary.Name() {
+		return s.secondary, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+}
+
+// requestRounding applies the rounding asked for in the options of ctx to
+// request
+func requestRounding(ctx context.Context, request model.ConversionRequest) model.ConversionRequest {
+	switch model.RequestOptionsFromContext(ctx).Rounding {
+	case model.RoundingCash:
+		request.CashRounding = true
+	case model.RoundingNone:
+		request.CashRounding = false
+	}
+	return request
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestRequestOptionsFromContext(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	primary := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "primary" },
+	}
+	secondary := &mocks.RateRepositoryMock{
+		NameFunc: func() string { return "secondary" },
+		FetchLatestRateFunc: func(ctx context.Context, pair model.CurrencyPair) (*model.ExchangeRate, error) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 0.9, Date: today}, nil
+		},
+	}
+	cache := &mocks.RateCacheMock{
+		GetFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, bool) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 0.92, Date: today}, true
+		},
+	}
+	margin := &mocks.RateAdjusterMock{
+		AdjustFunc: func(tenant string, pair model.CurrencyPair, rate float64) float64 { return rate * 2 },
+	}
+	svc := NewExchangeService(primary, WithCache(cache), WithLogger(logger.NewLogger("error")),
+		WithSecondary(secondary),
+		WithRateAdjuster(margin),
+	)
+
+	latest := func(options model.RequestOptions) (float64, error) {
+		ctx := model.WithRequestOptions(context.Background(), options)
+		rate, err := svc.GetLatestRate(ctx, model.USD, model.EUR)
+		if err != nil {
+			return 0, err
+		}
+		return rate.Rate, nil
+	}
+
+	tests := []struct {
+		name    string
+		options model.RequestOptions
+		want    float64
+	}{
+		{"defaults", model.RequestOptions{}, 1.84},
+		{"mid-rate", model.RequestOptions{RateType: model.RateTypeMid}, 0.92},
+		{"primary by name", model.RequestOptions{Provider: "primary"}, 1.84},
+		{"secondary provider", model.RequestOptions{Provider: "secondary", RateType: model.RateTypeMid}, 0.9},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := latest(tc.options)
+			if err != nil {
+				t.Fatalf("GetLatestRate failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := latest(model.RequestOptions{Provider: "elsewhere"}); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Expected ErrUnknownProvider, got %v", err)
+	}
+
+	ctx := model.WithRequestOptions(context.Background(), model.RequestOptions{Rounding: model.RoundingCash, RateType: model.RateTypeMid})
+	result, err := svc.ConvertCurrency(ctx, model.ConversionRequest{FromCurrency: model.USD, ToCurrency: model.EUR, Amount: 10, DryRun: true})
+	if err != nil {
+		t.Fatalf("ConvertCurrency failed: %v", err)
+	}
+	if result.Rounding != model.RoundingCash || result.Cash == nil {
+		t.Errorf("Expected cash rounding from the request options, got %+v", result)
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+)
+
+// UseRecentRates records every published rate in recent to serve sparklines.
+// Like AddPublisher it must be called before the service starts refreshing.
+func (s *ExchangeService) UseRecentRates(recent ports.RecentRateStore) {
+	s.recent = recent
+	s.AddPublisher(recent)
+}
+
+// Sparkline returns up to points of the most recent rates of a pair from
+// memory. It never calls the provider or the persistent stores.
+func (s *ExchangeService) Sparkline(ctx context.Context, from, to model.Currency, points int) (*model.Sparkline, error) {
+	if err := s.checkPair(from, to); err != nil {
+		return nil, err
+	}
+	if s.recent == nil {
+		return nil, ErrSparklinesDisabled
+	}
+
+	sparkline := &model.Sparkline{
+		BaseCurrency:   from,
+		TargetCurrency: to,
+		Points:         s.recent.Recent(model.CurrencyPair{BaseCurrency: from, TargetCurrency: to}, points),
+	}
+	if len(sparkline.Points) == 0 {
+		return sparkline, nil
+	}
+
+	sparkline.Min, sparkline.Max = math.Inf(1), math.Inf(-1)
+	for _, p := range sparkline.Points {
+		sparkline.Min = math.Min(sparkline.Min, p.Rate)
+		sparkline.Max = math.Max(sparkline.Max, p.Rate)
+	}
+	if first := sparkline.Points[0].Rate; first != 0 {
+		last := sparkline.Points[len(sparkline.Points)-1].Rate
+		sparkline.ChangePercent = math.Round((last-first)/first*100*1e4) / 1e4
+	}
+
+	return sparkline, nil
+}
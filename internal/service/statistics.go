@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// RangeStatistics computes drawdown, streaks and range width of a pair from
+// the daily closes in the history store. It never calls the provider, so
+// dates not stored yet are left out.
+func (s *ExchangeService) RangeStatistics(ctx context.Context, request model.RangeStatisticsRequest) (*model.RangeStatistics, error) {
+	if err := s.checkPair(request.BaseCurrency, request.TargetCurrency); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateDateRange(request.StartDate, request.EndDate); err != nil {
+		return nil, err
+	}
+
+	pair := model.CurrencyPair{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+	}
+	closes, err := s.history.Range(ctx, pair, request.StartDate, request.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(closes) == 0 {
+		return nil, ErrRateNotFound
+	}
+	sort.Slice(closes, func(i, j int) bool {
+		return closes[i].Date.Before(closes[j].Date)
+	})
+
+	stats := &model.RangeStatistics{
+		BaseCurrency:   request.BaseCurrency,
+		TargetCurrency: request.TargetCurrency,
+		StartDate:      request.StartDate,
+		EndDate:        request.EndDate,
+		Closes:         len(closes),
+		High:           closes[0].Rate,
+		HighDate:       closes[0].Date,
+		Low:            closes[0].Rate,
+		LowDate:        closes[0].Date,
+	}
+	for _, rate := range closes[1:] {
+		if rate.Rate > stats.High {
+			stats.High, stats.HighDate = rate.Rate, rate.Date
+		}
+		if rate.Rate < stats.Low {
+			stats.Low, stats.LowDate = rate.Rate, rate.Date
+		}
+	}
+	stats.RangeWidth = stats.High - stats.Low
+	if stats.Low != 0 {
+		stats.RangeWidthPercent = roundPercent(stats.RangeWidth / stats.Low * 100)
+	}
+
+	stats.MaxDrawdown = maxDrawdown(closes)
+	stats.LongestUpStreak = longestStreak(closes, func(previous, current float64) bool { return current > previous })
+	stats.LongestDownStreak = longestStreak(closes, func(previous, current float64) bool { return current < previous })
+
+	return stats, nil
+}
+
+// maxDrawdown finds the largest fall from a close to any later close, and
+// when the rate first got back to the peak after it. closes are sorted by date.
+func maxDrawdown(closes []model.ExchangeRate) model.Drawdown {
+	drawdown := model.Drawdown{
+		PeakRate:   closes[0].Rate,
+		PeakDate:   closes[0].Date,
+		TroughRate: closes[0].Rate,
+		TroughDate: closes[0].Date,
+	}
+
+	peak, troughIndex, deepest := closes[0], 0, 0.0
+	for i, rate := range closes {
+		if rate.Rate > peak.Rate {
+			peak = rate
+			continue
+		}
+		if peak.Rate == 0 {
+			continue
+		}
+		if fall := (peak.Rate - rate.Rate) / peak.Rate; fall > deepest {
+			deepest, troughIndex = fall, i
+			drawdown.PeakRate, drawdown.PeakDate = peak.Rate, peak.Date
+			drawdown.TroughRate, drawdown.TroughDate = rate.Rate, rate.Date
+		}
+	}
+	drawdown.Percent = roundPercent(deepest * 100)
+
+	if deepest > 0 {
+		for _, rate := range closes[troughIndex+1:] {
+			if rate.Rate >= drawdown.PeakRate {
+				recovered := rate.Date
+				drawdown.RecoveryDate = &recovered
+				break
+			}
+		}
+	}
+	return drawdown
+}
+
+// longestStreak finds the longest run of consecutive closes where each moves
+// from the one before as moved reports; the earliest wins a tie
+func longestStreak(closes []model.ExchangeRate, moved func(previous, current float64) bool) model.Streak {
+	longest := model.Streak{}
+	run := 0
+	for i := 1; i < len(closes); i++ {
+		if !moved(closes[i-1].Rate, closes[i].Rate) {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest.Days {
+			start, end := closes[i-run].Date, closes[i].Date
+			longest = model.Streak{Days: run, StartDate: &start, EndDate: &end}
+		}
+	}
+	return longest
+}
+
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*1e4) / 1e4
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/logger"
+)
+
+func TestRangeStatistics(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -20)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+
+	// Up for two days to 110, down three days to 88, back to 110 on day 7,
+	// with day 4 missing from the store
+	closes := map[int]float64{0: 100, 1: 105, 2: 110, 3: 99, 5: 95, 6: 88, 7: 110, 8: 120}
+	history := &mocks.HistoryStoreMock{
+		RangeFunc: func(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.ExchangeRate, error) {
+			rates := make([]model.ExchangeRate, 0)
+			for d, rate := range closes {
+				rates = append(rates, model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: rate, Date: day(d)})
+			}
+			return rates, nil
+		},
+	}
+	svc := NewExchangeService(&mocks.RateRepositoryMock{}, WithLogger(logger.NewLogger("error")), WithHistoryStore(history))
+
+	stats, err := svc.RangeStatistics(context.Background(), model.RangeStatisticsRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      day(0),
+		EndDate:        day(8),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Closes != 8 || stats.High != 120 || !stats.HighDate.Equal(day(8)) || stats.Low != 88 || !stats.LowDate.Equal(day(6)) {
+		t.Errorf("Unexpected high and low: %+v", stats)
+	}
+	if stats.RangeWidth != 32 || stats.RangeWidthPercent != 36.3636 {
+		t.Errorf("Expected a width of 32 (36.3636%%), got %v (%v%%)", stats.RangeWidth, stats.RangeWidthPercent)
+	}
+
+	drawdown := stats.MaxDrawdown
+	if drawdown.Percent != 20 || !drawdown.PeakDate.Equal(day(2)) || !drawdown.TroughDate.Equal(day(6)) {
+		t.Errorf("Expected a 20%% drawdown from day 2 to day 6, got %+v", drawdown)
+	}
+	if drawdown.RecoveryDate == nil || !drawdown.RecoveryDate.Equal(day(7)) {
+		t.Errorf("Expected recovery on day 7, got %v", drawdown.RecoveryDate)
+	}
+
+	if up := stats.LongestUpStreak; up.Days != 2 || !up.StartDate.Equal(day(0)) || !up.EndDate.Equal(day(2)) {
+		t.Errorf("Expected the first up streak of 2 to win the tie, got %+v", up)
+	}
+	if down := stats.LongestDownStreak; down.Days != 3 || !down.StartDate.Equal(day(2)) || !down.EndDate.Equal(day(6)) {
+		t.Errorf("Expected a down streak of 3 from day 2 to day 6, got %+v", down)
+	}
+}
+
+func TestRangeStatisticsWithoutCloses(t *testing.T) {
+	history := &mocks.HistoryStoreMock{
+		RangeFunc: func(ctx context.Context, pair model.CurrencyPair, from, to time.Time) ([]model.ExchangeRate, error) {
+			return nil, nil
+		},
+	}
+	svc := NewExchangeService(&mocks.RateRepositoryMock{}, WithLogger(logger.NewLogger("error")), WithHistoryStore(history))
+
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	_, err := svc.RangeStatistics(context.Background(), model.RangeStatisticsRequest{
+		BaseCurrency:   model.USD,
+		TargetCurrency: model.INR,
+		StartDate:      yesterday,
+		EndDate:        yesterday,
+	})
+	if !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("Expected ErrRateNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+)
+
+// usageWindow is the period monthly quotas are counted over
+const usageWindow = 30 * 24 * time.Hour
+
+// usageBudget is the monthly quota of one provider, of which reservePercent
+// is kept for the calls that serve clients and refreshes
+type usageBudget struct {
+	provider       string
+	monthly        int
+	reservePercent float64
+}
+
+// ProviderUsage counts the upstream calls made to each provider, from the
+// SLA buckets, sorted by provider. The provider with a budget is listed even
+// before it is called.
+func (s *ExchangeService) ProviderUsage(ctx context.Context) ([]model.ProviderUsage, error) {
+	now := s.clock.Now().UTC()
+	buckets, err := s.sla.Buckets(ctx, now.Add(-usageWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	thisHour := now.Truncate(time.Hour)
+	today := now.Truncate(24 * time.Hour)
+	usages := make(map[string]*model.ProviderUsage)
+	usageOf := func(provider string) *model.ProviderUsage {
+		usage, exists := usages[provider]
+		if !exists {
+			usage = &model.ProviderUsage{
+				Provider: provider,
+				Hourly:   make([]model.ProviderUsagePoint, 0),
+				Daily:    make([]model.ProviderUsagePoint, 0),
+			}
+			usages[provider] = usage
+		}
+		return usage
+	}
+
+	// Buckets come oldest first, so the points do too
+	for _, bucket := range buckets {
+		if bucket.Requests == 0 {
+			continue
+		}
+		usage := usageOf(bucket.Provider)
+		usage.Last30Days += bucket.Requests
+		if !bucket.Hour.Before(today) {
+			usage.Today += bucket.Requests
+		}
+		if !bucket.Hour.Before(thisHour) {
+			usage.ThisHour += bucket.Requests
+		}
+		if bucket.Hour.After(thisHour.Add(-24 * time.Hour)) {
+			usage.Hourly = append(usage.Hourly, model.ProviderUsagePoint{Start: bucket.Hour, Requests: bucket.Requests})
+		}
+
+		day := bucket.Hour.Truncate(24 * time.Hour)
+		if n := len(usage.Daily); n > 0 && usage.Daily[n-1].Start.Equal(day) {
+			usage.Daily[n-1].Requests += bucket.Requests
+		} else {
+			usage.Daily = append(usage.Daily, model.ProviderUsagePoint{Start: day, Requests: bucket.Requests})
+		}
+	}
+
+	if s.budget.monthly > 0 {
+		usage := usageOf(s.budget.provider)
+		usage.Budget = s.budget.of(usage.Last30Days)
+	}
+
+	result := make([]model.ProviderUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Provider < result[j].Provider
+	})
+
+	return result, nil
+}
+
+// of reports the budget after used calls
+func (b usageBudget) of(used int) *model.UsageBudget {
+	return &model.UsageBudget{
+		Monthly:          b.monthly,
+		Remaining:        max(b.monthly-used, 0),
+		UsedPercent:      float64(used) * 100 / float64(b.monthly),
+		ReservePercent:   b.reservePercent,
+		BackfillsAllowed: b.allowance(used) > 0,
+	}
+}
+
+// allowance is how many more non-essential calls may be made after used
+// calls, leaving the reserve alone. Without a reserve there is no limit.
+func (b usageBudget) allowance(used int) int {
+	if b.monthly == 0 || b.reservePercent == 0 {
+		return math.MaxInt
+	}
+	return int(float64(b.monthly)*(100-b.reservePercent)/100) - used
+}
+
+// backfillAllowance is how many dates a backfill may fetch within the budget
+func (s *ExchangeService) backfillAllowance(ctx context.Context) (int, error) {
+	if s.budget.monthly == 0 || s.budget.reservePercent == 0 {
+		return s.budget.allowance(0), nil
+	}
+
+	buckets, err := s.sla.Buckets(ctx, s.clock.Now().Add(-usageWindow))
+	if err != nil {
+		return 0, err
+	}
+	used := 0
+	for _, bucket := range buckets {
+		if bucket.Provider == s.budget.provider {
+			used += bucket.Requests
+		}
+	}
+	return s.budget.allowance(used), nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports/mocks"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+func usageSLA(hour time.Time) *mocks.SLAStoreMock {
+	return &mocks.SLAStoreMock{
+		BucketsFunc: func(ctx context.Context, since time.Time) ([]model.ProviderSLABucket, error) {
+			return []model.ProviderSLABucket{
+				{Provider: "primary", Hour: hour.AddDate(0, 0, -3), Requests: 50},
+				{Provider: "primary", Hour: hour.Add(-2 * time.Hour), Requests: 20},
+				{Provider: "crypto", Hour: hour.Add(-time.Hour), Requests: 5},
+				{Provider: "primary", Hour: hour, Requests: 10},
+			}, nil
+		},
+	}
+}
+
+func TestProviderUsage(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC))
+	hour := fake.Now().Truncate(time.Hour)
+	svc := NewExchangeService(&mocks.RateRepositoryMock{}, WithCache(&mocks.RateCacheMock{}), WithLogger(logger.NewLogger("error")),
+		WithClock(fake), WithSLAStore(usageSLA(hour)), WithUsageBudget("primary", 100, 20))
+
+	usage, err := svc.ProviderUsage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 2 || usage[0].Provider != "crypto" || usage[1].Provider != "primary" {
+		t.Fatalf("Expected usage sorted by provider, got %+v", usage)
+	}
+
+	primary := usage[1]
+	if primary.ThisHour != 10 || primary.Today != 30 || primary.Last30Days != 80 {
+		t.Errorf("Expected 10 calls this hour, 30 today and 80 in 30 days, got %+v", primary)
+	}
+	if len(primary.Hourly) != 2 || len(primary.Daily) != 2 || primary.Daily[1].Requests != 30 {
+		t.Errorf("Expected two hours and two days with calls, got %+v, %+v", primary.Hourly, primary.Daily)
+	}
+	want := model.UsageBudget{Monthly: 100, Remaining: 20, UsedPercent: 80, ReservePercent: 20, BackfillsAllowed: false}
+	if primary.Budget == nil || *primary.Budget != want {
+		t.Errorf("Budget = %+v, want %+v", primary.Budget, want)
+	}
+	if usage[0].Budget != nil || usage[0].Today != 5 {
+		t.Errorf("Expected crypto without a budget, got %+v", usage[0])
+	}
+}
+
+func TestBackfillHistoryKeepsTheReserve(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 10, 12, 30, 0, 0, time.UTC))
+	hour := fake.Now().Truncate(time.Hour)
+	repository := &mocks.RateRepositoryMock{
+		FetchHistoricalRateFunc: func(ctx context.Context, pair model.CurrencyPair, date time.Time) (*model.ExchangeRate, error) {
+			return &model.ExchangeRate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: 83, Date: date}, nil
+		},
+	}
+	history := &mocks.HistoryStoreMock{
+		RangeFunc: func(ctx context.Context, pair model.CurrencyPair, start, end time.Time) ([]model.ExchangeRate, error) {
+			return nil, nil
+		},
+		SaveFunc: func(ctx context.Context, rates []model.ExchangeRate) error { return nil },
+	}
+	pair := &model.CurrencyPair{BaseCurrency: model.USD, TargetCurrency: model.INR}
+	newService := func(reservePercent float64) *ExchangeService {
+		return NewExchangeService(repository, WithCache(&mocks.RateCacheMock{}), WithLogger(logger.NewLogger("error")),
+			WithClock(fake), WithSLAStore(usageSLA(hour)), WithHistoryStore(history), WithUsageBudget("primary", 100, reservePercent))
+	}
+
+	// 80 of 100 calls are used, and 20 are reserved
+	if _, err := newService(20).BackfillHistory(context.Background(), pair, 10); !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("Expected the backfill to be refused, got %v", err)
+	}
+
+	// With 10 reserved, 10 calls are left for backfills
+	result, err := newService(10).BackfillHistory(context.Background(), pair, 50)
+	if err != nil || result.Requested != 10 {
+		t.Errorf("Expected the backfill to stop after 10 dates, got %+v, %v", result, err)
+	}
+
+	// Without a reserve the budget is only reported
+	result, err = newService(0).BackfillHistory(context.Background(), pair, 50)
+	if err != nil || result.Requested != 50 {
+		t.Errorf("Expected an unlimited backfill, got %+v, %v", result, err)
+	}
+}
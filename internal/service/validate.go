@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/pkg/xerrors"
+)
+
+// ValidateRequest checks request against the rules serving it would apply,
+// without fetching any rate, and lists every rule it breaks. Issues name the
+// fields of the request as clients send them: from, to, amount, unit, date,
+// start_date and end_date, and pair for the allow and deny lists. Rates may
+// still be missing for a valid request; that only shows when it is served.
+func (s *ExchangeService) ValidateRequest(ctx context.Context, request model.ValidationRequest) *model.ValidationResult {
+	result := &model.ValidationResult{Issues: make([]model.ValidationIssue, 0)}
+	fail := func(field string, err error) {
+		result.Issues = append(result.Issues, model.ValidationIssue{
+			Field:   field,
+			Code:    xerrors.CodeOf(err),
+			Message: err.Error(),
+		})
+	}
+
+	// Aliases are checked as the currencies they stand for, like conversions do
+	pair, _, _ := s.aliases.Resolve(request.BaseCurrency, request.TargetCurrency)
+	baseAccepted, targetAccepted := s.quotable(pair.BaseCurrency), s.quotable(pair.TargetCurrency)
+	if !baseAccepted {
+		fail("from", ErrInvalidCurrency)
+	}
+	if !targetAccepted {
+		fail("to", ErrInvalidCurrency)
+	}
+	if baseAccepted && targetAccepted && !s.currencies.AllowsPair(pair) {
+		fail("pair", ErrPairNotAvailable)
+	}
+
+	switch request.Type {
+	case model.ValidateConversion:
+		if request.Amount <= 0 {
+			fail("amount", ErrInvalidAmount)
+		}
+		if _, _, err := conversionUnitSizes(model.ConversionRequest{
+			FromCurrency: request.BaseCurrency,
+			ToCurrency:   request.TargetCurrency,
+			Unit:         request.Unit,
+		}); err != nil {
+			fail("unit", err)
+		}
+		if !request.Date.IsZero() {
+			if err := s.validateDate(request.Date); err != nil {
+				fail("date", err)
+			}
+		}
+	case model.ValidateHistorical:
+		if err := s.validateDate(request.Date); err != nil {
+			fail("date", err)
+		}
+	case model.ValidateHistoricalRange:
+		startErr, endErr := s.validateDate(request.StartDate), s.validateDate(request.EndDate)
+		if startErr != nil {
+			fail("start_date", startErr)
+		}
+		if endErr != nil {
+			fail("end_date", endErr)
+		}
+		if startErr == nil && endErr == nil {
+			if err := s.validateDateRange(request.StartDate, request.EndDate); err != nil {
+				fail("end_date", err)
+			}
+		}
+	}
+
+	result.Valid = len(result.Issues) == 0
+	return result
+}
+
+// quotable reports whether c may be asked for in any pair: as a currency the
+// provider quotes, a composite unit or a cryptocurrency
+func (s *ExchangeService) quotable(c model.Currency) bool {
+	return c.IsComposite() || s.isCrypto(c) || s.acceptsCurrency(c)
+}
@@ -0,0 +1,239 @@
+package simulation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+const (
+	// maxOrders bounds the orders of a single tenant
+	maxOrders = 50
+	// minEvery is the shortest interval between executions of a DCA order
+	minEvery = time.Minute
+)
+
+var (
+	ErrInvalidOrder  = errors.New("invalid simulated order")
+	ErrOrderNotFound = errors.New("simulated order not found")
+	ErrTooManyOrders = errors.New("too many simulated orders")
+)
+
+// Simulator evaluates the simulated orders of API key holders against every
+// published refresh and records the conversions they would have made. No
+// money moves: an execution is only a record of the rate it would have used.
+type Simulator struct {
+	store   ports.SimulationStore
+	metrics *metrics.Metrics
+	log     *logger.Logger
+
+	// mutex serializes evaluation so that concurrent publishes cannot execute
+	// the same order twice
+	mutex sync.Mutex
+	now   func() time.Time
+}
+
+func NewSimulator(store ports.SimulationStore, metrics *metrics.Metrics, log *logger.Logger) *Simulator {
+	return &Simulator{
+		store:   store,
+		metrics: metrics,
+		log:     log,
+		now:     time.Now,
+	}
+}
+
+// Place validates and stores an order of tenant
+func (s *Simulator) Place(ctx context.Context, tenant string, order model.SimulationOrder) (*model.SimulationOrder, error) {
+	if err := validate(&order); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.Orders(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxOrders {
+		return nil, fmt.Errorf("%w: the limit is %d", ErrTooManyOrders, maxOrders)
+	}
+
+	created := model.SimulationOrder{
+		ID:             "sim_" + randomHex(8),
+		Tenant:         tenant,
+		Type:           order.Type,
+		BaseCurrency:   order.BaseCurrency,
+		TargetCurrency: order.TargetCurrency,
+		Amount:         order.Amount,
+		Condition:      order.Condition,
+		Threshold:      order.Threshold,
+		Every:          order.Every,
+		Active:         true,
+		CreatedAt:      s.now().UTC(),
+	}
+	if err := s.store.SaveOrder(ctx, &created); err != nil {
+		s.log.Error("Failed to store simulated order", "error", err)
+		return nil, err
+	}
+
+	s.log.Info("Simulated order placed", "id", created.ID, "tenant", tenant, "type", created.Type, "pair", created.Pair().String())
+	return &created, nil
+}
+
+func validate(order *model.SimulationOrder) error {
+	order.BaseCurrency = model.Currency(strings.ToUpper(string(order.BaseCurrency)))
+	order.TargetCurrency = model.Currency(strings.ToUpper(string(order.TargetCurrency)))
+	if !order.BaseCurrency.IsSupported() || !order.TargetCurrency.IsSupported() {
+		return fmt.Errorf("%w: base_currency and target_currency must be supported currencies", ErrInvalidOrder)
+	}
+	if order.BaseCurrency == order.TargetCurrency {
+		return fmt.Errorf("%w: base_currency and target_currency must differ", ErrInvalidOrder)
+	}
+	if order.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidOrder)
+	}
+
+	switch order.Condition {
+	case "":
+		order.Threshold = 0
+	case model.SimulationAbove, model.SimulationBelow:
+		if order.Threshold <= 0 {
+			return fmt.Errorf("%w: threshold must be positive", ErrInvalidOrder)
+		}
+	default:
+		return fmt.Errorf("%w: condition must be above or below", ErrInvalidOrder)
+	}
+
+	switch order.Type {
+	case model.SimulationLimit:
+		if order.Condition == "" {
+			return fmt.Errorf("%w: a limit order needs a condition", ErrInvalidOrder)
+		}
+		order.Every = ""
+	case model.SimulationDCA:
+		every, err := time.ParseDuration(order.Every)
+		if err != nil || every < minEvery {
+			return fmt.Errorf("%w: every must be a duration of at least %s", ErrInvalidOrder, minEvery)
+		}
+		order.Every = every.String()
+	default:
+		return fmt.Errorf("%w: type must be limit or dca", ErrInvalidOrder)
+	}
+	return nil
+}
+
+func (s *Simulator) Orders(ctx context.Context, tenant string) ([]model.SimulationOrder, error) {
+	return s.store.Orders(ctx, tenant)
+}
+
+func (s *Simulator) Cancel(ctx context.Context, tenant, id string) error {
+	deleted, err := s.store.DeleteOrder(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrOrderNotFound
+	}
+
+	s.log.Info("Simulated order cancelled", "id", id, "tenant", tenant)
+	return nil
+}
+
+// Executions returns the executions of an order of tenant, newest first
+func (s *Simulator) Executions(ctx context.Context, tenant, id string) ([]model.SimulatedExecution, error) {
+	orders, err := s.store.Orders(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		if order.ID == id {
+			return s.store.Executions(ctx, id)
+		}
+	}
+	return nil, ErrOrderNotFound
+}
+
+// Publish evaluates every active order against the refreshed rates. A limit
+// order executes once and is deactivated; a DCA order executes whenever its
+// interval has passed and its condition holds.
+func (s *Simulator) Publish(ctx context.Context, rates []model.ExchangeRate) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	byPair := make(map[model.CurrencyPair]model.ExchangeRate, len(rates))
+	for _, rate := range rates {
+		byPair[model.CurrencyPair{BaseCurrency: rate.BaseCurrency, TargetCurrency: rate.TargetCurrency}] = rate
+	}
+
+	orders, err := s.store.Orders(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	for _, order := range orders {
+		rate, found := byPair[order.Pair()]
+		if !order.Active || !found || !s.due(order, rate, now) {
+			continue
+		}
+		if err := s.execute(ctx, order, rate, now); err != nil {
+			s.log.Error("Failed to record simulated execution", "id", order.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) due(order model.SimulationOrder, rate model.ExchangeRate, now time.Time) bool {
+	if !order.ConditionMet(rate.Rate) {
+		return false
+	}
+	if order.Type != model.SimulationDCA || order.LastExecutedAt.IsZero() {
+		return true
+	}
+	every, err := time.ParseDuration(order.Every)
+	return err == nil && now.Sub(order.LastExecutedAt) >= every
+}
+
+func (s *Simulator) execute(ctx context.Context, order model.SimulationOrder, rate model.ExchangeRate, now time.Time) error {
+	execution := model.SimulatedExecution{
+		ID:             "exe_" + randomHex(8),
+		OrderID:        order.ID,
+		BaseCurrency:   order.BaseCurrency,
+		TargetCurrency: order.TargetCurrency,
+		Amount:         order.Amount,
+		Rate:           rate.Rate,
+		Converted:      order.Amount * rate.Rate,
+		RateTime:       rate.LastUpdated,
+		ExecutedAt:     now,
+	}
+	if err := s.store.RecordExecution(ctx, &execution); err != nil {
+		return err
+	}
+
+	order.Executions++
+	order.LastExecutedAt = now
+	if order.Type == model.SimulationLimit {
+		order.Active = false
+	}
+	if err := s.store.SaveOrder(ctx, &order); err != nil {
+		return err
+	}
+
+	s.metrics.SimulatedExecutions.WithLabelValues(string(order.Type)).Inc()
+	s.log.Info("Simulated order executed", "id", order.ID, "tenant", order.Tenant, "pair", order.Pair().String(), "rate", rate.Rate)
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
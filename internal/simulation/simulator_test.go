@@ -0,0 +1,110 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestSimulator(t *testing.T) (*Simulator, *time.Time) {
+	t.Helper()
+	log := logger.NewLogger("error")
+	simulationStore, err := store.NewFileSimulationStore(filepath.Join(t.TempDir(), "simulations.json"), 10, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appMetrics := &metrics.Metrics{
+		SimulatedExecutions: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "simulated_executions_total"}, []string{"type"}),
+	}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewSimulator(simulationStore, appMetrics, log)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func usdINR(rate float64) []model.ExchangeRate {
+	return []model.ExchangeRate{{BaseCurrency: model.USD, TargetCurrency: model.INR, Rate: rate}}
+}
+
+func TestSimulatorValidatesOrders(t *testing.T) {
+
+	s, _ := newTestSimulator(t)
+	tests := []struct {
+		name  string
+		order model.SimulationOrder
+	}{
+		{"unknown type", model.SimulationOrder{Type: "stop", BaseCurrency: "USD", TargetCurrency: "INR", Amount: 1, Condition: "above", Threshold: 84}},
+		{"same currency", model.SimulationOrder{Type: "limit", BaseCurrency: "USD", TargetCurrency: "usd", Amount: 1, Condition: "above", Threshold: 84}},
+		{"zero amount", model.SimulationOrder{Type: "limit", BaseCurrency: "USD", TargetCurrency: "INR", Condition: "above", Threshold: 84}},
+		{"limit without condition", model.SimulationOrder{Type: "limit", BaseCurrency: "USD", TargetCurrency: "INR", Amount: 1}},
+		{"missing threshold", model.SimulationOrder{Type: "limit", BaseCurrency: "USD", TargetCurrency: "INR", Amount: 1, Condition: "below"}},
+		{"short interval", model.SimulationOrder{Type: "dca", BaseCurrency: "USD", TargetCurrency: "INR", Amount: 1, Every: "1s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Place(context.Background(), "tenant-a", tt.order); !errors.Is(err, ErrInvalidOrder) {
+				t.Errorf("Expected ErrInvalidOrder, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSimulatorExecutesOrders(t *testing.T) {
+
+	s, now := newTestSimulator(t)
+	ctx := context.Background()
+
+	limit, err := s.Place(ctx, "tenant-a", model.SimulationOrder{Type: "limit", BaseCurrency: "usd", TargetCurrency: "inr", Amount: 100, Condition: "above", Threshold: 84})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dca, err := s.Place(ctx, "tenant-b", model.SimulationOrder{Type: "dca", BaseCurrency: "USD", TargetCurrency: "INR", Amount: 10, Every: "24h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		advance    time.Duration
+		rate       float64
+		limitCount int
+		dcaCount   int
+	}{
+		{0, 83.5, 0, 1},              // DCA buys immediately, limit waits
+		{time.Hour, 84.2, 1, 1},      // limit triggers, DCA is not due yet
+		{time.Hour, 84.5, 1, 1},      // limit executed once only
+		{24 * time.Hour, 83.9, 1, 2}, // DCA due again
+	}
+	for i, step := range steps {
+		*now = now.Add(step.advance)
+		s.Publish(ctx, usdINR(step.rate))
+
+		limitExecutions, _ := s.Executions(ctx, "tenant-a", limit.ID)
+		dcaExecutions, _ := s.Executions(ctx, "tenant-b", dca.ID)
+		if len(limitExecutions) != step.limitCount || len(dcaExecutions) != step.dcaCount {
+			t.Fatalf("Step %d: expected %d limit and %d DCA executions, got %d and %d", i, step.limitCount, step.dcaCount, len(limitExecutions), len(dcaExecutions))
+		}
+	}
+
+	executions, _ := s.Executions(ctx, "tenant-a", limit.ID)
+	if executions[0].Rate != 84.2 || executions[0].Converted != 8420 {
+		t.Errorf("Expected the limit order to convert 100 at 84.2, got %+v", executions[0])
+	}
+	orders, _ := s.Orders(ctx, "tenant-a")
+	if len(orders) != 1 || orders[0].Active || orders[0].Executions != 1 {
+		t.Errorf("Expected the executed limit order to be inactive, got %+v", orders)
+	}
+
+	if _, err := s.Executions(ctx, "tenant-a", dca.ID); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Expected another tenant's order to be hidden, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package watchdog
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"time"
+
+	metricsPkg "exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+)
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// Evictor is implemented by in-memory stores that can give up their oldest entries
+type Evictor interface {
+	EvictOldest(fraction float64) int
+}
+
+// MemoryWatchdog samples heap usage and, once it crosses the high watermark of
+// the limit, asks every registered evictor to drop its oldest entries.
+type MemoryWatchdog struct {
+	limit         uint64
+	highWatermark float64
+	evictFraction float64
+	evictors      map[string]Evictor
+	metrics       *metricsPkg.Metrics
+	log           *logger.Logger
+}
+
+func NewMemoryWatchdog(limit uint64, highWatermark, evictFraction float64, appMetrics *metricsPkg.Metrics, log *logger.Logger) *MemoryWatchdog {
+	return &MemoryWatchdog{
+		limit:         limit,
+		highWatermark: highWatermark,
+		evictFraction: evictFraction,
+		evictors:      make(map[string]Evictor),
+		metrics:       appMetrics,
+		log:           log,
+	}
+}
+
+func (w *MemoryWatchdog) Register(name string, evictor Evictor) {
+	w.evictors[name] = evictor
+}
+
+func (w *MemoryWatchdog) Run(ctx context.Context, interval time.Duration) {
+	w.log.Info("Memory watchdog started", "limit_bytes", w.limit, "high_watermark", w.highWatermark)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.check()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *MemoryWatchdog) check() {
+	heap := heapBytes()
+	w.metrics.HeapBytes.Set(float64(heap))
+
+	threshold := uint64(float64(w.limit) * w.highWatermark)
+	if heap < threshold {
+		return
+	}
+
+	w.log.Warn("Memory usage above high watermark, evicting oldest entries",
+		"heap_bytes", heap,
+		"threshold_bytes", threshold,
+		"limit_bytes", w.limit,
+	)
+	w.metrics.MemoryPressureEvents.Inc()
+
+	for name, evictor := range w.evictors {
+		evicted := evictor.EvictOldest(w.evictFraction)
+		w.metrics.MemoryPressureEvictions.WithLabelValues(name).Add(float64(evicted))
+		w.log.Warn("Evicted entries under memory pressure", "store", name, "count", evicted)
+	}
+
+	runtime.GC()
+	w.log.Info("Memory usage after eviction", "heap_bytes", heapBytes())
+}
+
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// ContainerMemoryLimit reads the cgroup memory limit, returning 0 when there is none
+func ContainerMemoryLimit() uint64 {
+	for _, path := range []string{
+		"/sys/fs/cgroup/memory.max",                   // cgroup v2
+		"/sys/fs/cgroup/memory/memory.limit_in_bytes", // cgroup v1
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		value := strings.TrimSpace(string(data))
+		if value == "max" {
+			return 0
+		}
+
+		limit, err := strconv.ParseUint(value, 10, 64)
+		// cgroup v1 reports an absurdly large number when unlimited
+		if err != nil || limit >= 1<<62 {
+			return 0
+		}
+		return limit
+	}
+	return 0
+}
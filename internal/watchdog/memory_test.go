@@ -0,0 +1,64 @@
+package watchdog
+
+import (
+	"testing"
+
+	"exchange-rate-service/internal/metrics"
+	"exchange-rate-service/pkg/logger"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+type fakeEvictor struct {
+	fractions []float64
+}
+
+func (e *fakeEvictor) EvictOldest(fraction float64) int {
+	e.fractions = append(e.fractions, fraction)
+	return 3
+}
+
+func TestMemoryWatchdogEvictsAboveHighWatermark(t *testing.T) {
+	appMetrics := metrics.NewMetrics()
+	log := logger.NewLogger("error")
+	cache, history := &fakeEvictor{}, &fakeEvictor{}
+
+	// The heap is far below the watermark of an enormous limit
+	relaxed := NewMemoryWatchdog(1<<60, 0.8, 0.25, appMetrics, log)
+	relaxed.Register("cache", cache)
+	relaxed.check()
+	if len(cache.fractions) != 0 {
+		t.Fatalf("Expected no eviction below the high watermark, got %v", cache.fractions)
+	}
+
+	// Any heap is above the watermark of a one byte limit
+	pressed := NewMemoryWatchdog(1, 0.8, 0.25, appMetrics, log)
+	pressed.Register("cache", cache)
+	pressed.Register("history", history)
+	pressed.check()
+	for name, evictor := range map[string]*fakeEvictor{"cache": cache, "history": history} {
+		if len(evictor.fractions) != 1 || evictor.fractions[0] != 0.25 {
+			t.Errorf("Expected %s to evict a quarter once, got %v", name, evictor.fractions)
+		}
+	}
+
+	var events dto.Metric
+	if err := appMetrics.MemoryPressureEvents.Write(&events); err != nil {
+		t.Fatal(err)
+	}
+	if events.GetCounter().GetValue() != 1 {
+		t.Errorf("Expected one memory pressure event, got %v", events.GetCounter().GetValue())
+	}
+	var evictions dto.Metric
+	if err := appMetrics.MemoryPressureEvictions.WithLabelValues("history").Write(&evictions); err != nil {
+		t.Fatal(err)
+	}
+	if evictions.GetCounter().GetValue() != 3 {
+		t.Errorf("Expected the evictions of history to be counted, got %v", evictions.GetCounter().GetValue())
+	}
+
+	var heap dto.Metric
+	if err := appMetrics.HeapBytes.Write(&heap); err != nil || heap.GetGauge().GetValue() <= 0 {
+		t.Errorf("Expected the heap size to be reported, got %v, %v", heap.GetGauge().GetValue(), err)
+	}
+}
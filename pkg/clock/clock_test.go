@@ -0,0 +1,37 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	fake.Advance(90 * time.Minute)
+	if got := fake.Now(); !got.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("Expected %v after advancing, got %v", start.Add(90*time.Minute), got)
+	}
+
+	fake.Set(start.AddDate(0, 0, -1))
+	if got := fake.Now(); !got.Equal(start.AddDate(0, 0, -1)) {
+		t.Errorf("Expected the clock to move back to %v, got %v", start.AddDate(0, 0, -1), got)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	ahead := Offset(fake, 2*time.Hour)
+	behind := Offset(fake, -48*time.Hour)
+	fake.Advance(time.Minute)
+
+	if got := ahead.Now(); !got.Equal(start.Add(2*time.Hour + time.Minute)) {
+		t.Errorf("Expected the skewed clock to follow its base, got %v", got)
+	}
+	if got := behind.Now(); !got.Equal(start.Add(-48*time.Hour + time.Minute)) {
+		t.Errorf("Expected a negative skew to run behind, got %v", got)
+	}
+}
@@ -0,0 +1,270 @@
+// Package exchange embeds the rate engine of the exchange rate service in
+// other Go programs. An Engine fetches rates from a provider, caches them and
+// converts amounts just like the service does behind its HTTP API, without
+// running any server.
+//
+// The package is a facade: the service, providers and cache it wires together
+// stay internal, and only what is declared here is meant to be relied upon.
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"exchange-rate-service/internal/adapter/cache"
+	"exchange-rate-service/internal/adapter/repository"
+	"exchange-rate-service/internal/adapter/store"
+	"exchange-rate-service/internal/domain/model"
+	"exchange-rate-service/internal/domain/ports"
+	"exchange-rate-service/internal/service"
+	"exchange-rate-service/pkg/clock"
+	"exchange-rate-service/pkg/logger"
+)
+
+// The types an Engine works with are those of the service
+type (
+	Currency          = model.Currency
+	CurrencyPair      = model.CurrencyPair
+	Rate              = model.ExchangeRate
+	HistoricalRequest = model.HistoricalRateRequest
+	HistoricalRates   = model.HistoricalRates
+	ConversionRequest = model.ConversionRequest
+	ConversionResult  = model.ConversionResult
+	CacheStats        = model.CacheStats
+)
+
+// Provider is the source of rates. Engines use exchangerate.host, or any
+// compatible API, unless WithProvider replaces it.
+type Provider interface {
+	// Name identifies the provider, for instance in logs
+	Name() string
+	FetchLatestRate(ctx context.Context, pair CurrencyPair) (*Rate, error)
+	FetchHistoricalRate(ctx context.Context, pair CurrencyPair, date time.Time) (*Rate, error)
+	FetchHistoricalRates(ctx context.Context, request HistoricalRequest) (*HistoricalRates, error)
+	// RefreshRates fetches the latest rates of every supported pair
+	RefreshRates(ctx context.Context) error
+	// LatestRates returns the rates held from the most recent refresh
+	// without calling the provider
+	LatestRates(ctx context.Context) []Rate
+}
+
+const (
+	USD = model.USD
+	INR = model.INR
+	EUR = model.EUR
+	JPY = model.JPY
+	GBP = model.GBP
+)
+
+// Errors returned by Engine methods, to be matched with errors.Is
+var (
+	ErrInvalidCurrency    = service.ErrInvalidCurrency
+	ErrDateOutOfRange     = service.ErrDateOutOfRange
+	ErrInvalidDateRange   = service.ErrInvalidDateRange
+	ErrRateNotFound       = service.ErrRateNotFound
+	ErrExternalAPIFailure = service.ErrExternalAPIFailure
+	ErrInvalidAmount      = service.ErrInvalidAmount
+	ErrPairNotAvailable   = service.ErrPairNotAvailable
+)
+
+type options struct {
+	baseURL         string
+	apiKey          string
+	timeout         time.Duration
+	provider        Provider
+	cacheTTL        time.Duration
+	refreshInterval time.Duration
+	receiptLimit    int
+	dataDir         string
+	clock           clock.Clock
+	log             *slog.Logger
+}
+
+// Option configures an Engine
+type Option func(*options)
+
+// WithAPI fetches rates from the exchangerate.host compatible API at baseURL
+func WithAPI(baseURL, apiKey string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+		o.apiKey = apiKey
+	}
+}
+
+// WithTimeout bounds each call to the API, 10 seconds by default
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) { o.timeout = timeout }
+}
+
+// WithProvider fetches rates from provider instead of an API
+func WithProvider(provider Provider) Option {
+	return func(o *options) { o.provider = provider }
+}
+
+// WithCacheTTL sets how long fetched rates are served without asking the
+// provider again, 30 minutes by default
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *options) { o.cacheTTL = ttl }
+}
+
+// WithRefreshInterval sets how often Run refreshes the rates, hourly by default
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(o *options) { o.refreshInterval = interval }
+}
+
+// WithReceiptLimit sets how many conversion receipts are kept for
+// Conversion, 100000 by default
+func WithReceiptLimit(limit int) Option {
+	return func(o *options) { o.receiptLimit = limit }
+}
+
+// WithDataDir keeps historical rates and provider statistics in JSON files in
+// dir, so they survive restarts. Without it they are kept in memory only.
+func WithDataDir(dir string) Option {
+	return func(o *options) { o.dataDir = dir }
+}
+
+// WithClock replaces the wall clock, for instance with a clock.Fake in tests
+func WithClock(c clock.Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+// WithLogger logs to log. Engines log nothing by default.
+func WithLogger(log *slog.Logger) Option {
+	return func(o *options) { o.log = log }
+}
+
+// Engine serves latest and historical rates and conversions
+type Engine struct {
+	service         *service.ExchangeService
+	refreshInterval time.Duration
+	log             *logger.Logger
+}
+
+// New creates an Engine. It does not call the provider until rates are first
+// requested, refreshed, or Run is started.
+func New(opts ...Option) (*Engine, error) {
+	o := options{
+		baseURL:         "https://api.exchangerate.host",
+		timeout:         10 * time.Second,
+		cacheTTL:        30 * time.Minute,
+		refreshInterval: time.Hour,
+		receiptLimit:    100000,
+		clock:           clock.System,
+		log:             slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.cacheTTL <= 0 || o.refreshInterval <= 0 {
+		return nil, fmt.Errorf("cache TTL and refresh interval must be positive")
+	}
+	log := &logger.Logger{Logger: o.log}
+
+	dataFile := func(name string) string {
+		if o.dataDir == "" {
+			return ""
+		}
+		return filepath.Join(o.dataDir, name)
+	}
+
+	slaStore, err := store.NewFileSLAStore(dataFile("provider_sla.json"), 30*24*time.Hour, log)
+	if err != nil {
+		return nil, err
+	}
+	historyStore, err := store.NewFileHistoryStore(dataFile("history.json"), model.HistoryRetentionDays, log)
+	if err != nil {
+		return nil, err
+	}
+	annotationStore, err := store.NewFileAnnotationStore(dataFile("annotations.json"), log)
+	if err != nil {
+		return nil, err
+	}
+	tickStore, err := store.NewFileTickStore(dataFile("ticks.json"), 7*24*time.Hour, log)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider ports.RateRepository = o.provider
+	if o.provider == nil {
+		api := repository.NewExchangeAPI(o.baseURL, o.apiKey, o.timeout, slaStore, log)
+		api.UseClock(o.clock)
+		provider = api
+	}
+
+	rateCache := cache.NewMemoryCache(o.cacheTTL, log)
+	rateCache.UseClock(o.clock)
+
+	exchangeService := service.NewExchangeService(provider, service.WithCache(rateCache), service.WithLogger(log),
+		service.WithConversionStore(store.NewMemoryConversionStore(o.receiptLimit, log)),
+		service.WithAnnotationStore(annotationStore),
+		service.WithHistoryStore(historyStore),
+		service.WithSLAStore(slaStore),
+		service.WithTickStore(tickStore),
+		service.WithClock(o.clock),
+	)
+
+	return &Engine{
+		service:         exchangeService,
+		refreshInterval: o.refreshInterval,
+		log:             log,
+	}, nil
+}
+
+// LatestRate returns the current rate of from in to
+func (e *Engine) LatestRate(ctx context.Context, from, to Currency) (*Rate, error) {
+	return e.service.GetLatestRate(ctx, from, to)
+}
+
+// HistoricalRate returns the rate of from in to on date, at most 90 days ago
+func (e *Engine) HistoricalRate(ctx context.Context, from, to Currency, date time.Time) (*Rate, error) {
+	return e.service.GetHistoricalRate(ctx, from, to, date)
+}
+
+// HistoricalRates returns the daily rates of a pair over a date range
+func (e *Engine) HistoricalRates(ctx context.Context, request HistoricalRequest) (*HistoricalRates, error) {
+	return e.service.GetHistoricalRates(ctx, request)
+}
+
+// Convert converts an amount at the latest rate, or at the rate of
+// request.Date when it is set, and keeps a receipt unless it is a dry run
+func (e *Engine) Convert(ctx context.Context, request ConversionRequest) (*ConversionResult, error) {
+	return e.service.ConvertCurrency(ctx, request)
+}
+
+// Conversion returns the receipt of an earlier conversion by its ID
+func (e *Engine) Conversion(ctx context.Context, id string) (*ConversionResult, error) {
+	return e.service.GetConversion(ctx, id)
+}
+
+// Refresh fetches the latest rates of every supported pair from the provider
+func (e *Engine) Refresh(ctx context.Context) error {
+	return e.service.RefreshRates(ctx)
+}
+
+// CacheStats reports the contents and hit ratios of the rate cache
+func (e *Engine) CacheStats(ctx context.Context) CacheStats {
+	return e.service.CacheStats(ctx)
+}
+
+// Run refreshes the rates now and then at the refresh interval, until ctx is
+// cancelled. Failed refreshes are logged and retried at the next interval.
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.service.RefreshRates(ctx); err != nil {
+			e.log.Error("Failed to refresh rates", "error", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange-rate-service/pkg/clock"
+)
+
+func TestEngine(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": true,
+			"source":  "USD",
+			"quotes":  map[string]float64{"USDINR": 83, "USDEUR": 0.92, "USDJPY": 150, "USDGBP": 0.79},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	fake := clock.NewFake(time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC))
+	engine, err := New(WithAPI(server.URL, ""), WithCacheTTL(time.Hour), WithClock(fake))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := engine.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	rate, err := engine.LatestRate(ctx, USD, INR)
+	if err != nil || rate.Rate != 83 {
+		t.Fatalf("Expected USD-INR at 83, got %+v, %v", rate, err)
+	}
+	if !rate.Date.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the rate dated by the engine's clock, got %v", rate.Date)
+	}
+
+	result, err := engine.Convert(ctx, ConversionRequest{FromCurrency: EUR, ToCurrency: INR, Amount: 92})
+	if err != nil || result.ToAmount != 8300 {
+		t.Fatalf("Expected 92 EUR to be 8300 INR, got %+v, %v", result, err)
+	}
+	if receipt, err := engine.Conversion(ctx, result.ID); err != nil || receipt.ToAmount != result.ToAmount {
+		t.Errorf("Expected the conversion receipt, got %+v, %v", receipt, err)
+	}
+
+	if _, err := engine.HistoricalRate(ctx, USD, INR, fake.Now().AddDate(0, 0, -91)); !errors.Is(err, ErrDateOutOfRange) {
+		t.Errorf("Expected ErrDateOutOfRange, got %v", err)
+	}
+	if _, err := engine.LatestRate(ctx, USD, "XXX"); !errors.Is(err, ErrInvalidCurrency) {
+		t.Errorf("Expected ErrInvalidCurrency, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single provider call, got %d", calls.Load())
+	}
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	if _, err := New(WithCacheTTL(0)); err == nil {
+		t.Error("Expected a zero cache TTL to be rejected")
+	}
+}
+
+// fixedProvider quotes every pair at the same rate
+type fixedProvider struct{ rate float64 }
+
+func (p fixedProvider) Name() string { return "fixed" }
+
+func (p fixedProvider) FetchLatestRate(ctx context.Context, pair CurrencyPair) (*Rate, error) {
+	return &Rate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: p.rate, Date: time.Now().UTC(), LastUpdated: time.Now()}, nil
+}
+
+func (p fixedProvider) FetchHistoricalRate(ctx context.Context, pair CurrencyPair, date time.Time) (*Rate, error) {
+	return &Rate{BaseCurrency: pair.BaseCurrency, TargetCurrency: pair.TargetCurrency, Rate: p.rate, Date: date, LastUpdated: time.Now()}, nil
+}
+
+func (p fixedProvider) FetchHistoricalRates(ctx context.Context, request HistoricalRequest) (*HistoricalRates, error) {
+	return nil, ErrRateNotFound
+}
+
+func (p fixedProvider) RefreshRates(ctx context.Context) error { return nil }
+
+func (p fixedProvider) LatestRates(ctx context.Context) []Rate { return nil }
+
+func TestEngineWithProvider(t *testing.T) {
+	engine, err := New(WithProvider(fixedProvider{rate: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.Convert(context.Background(), ConversionRequest{FromCurrency: USD, ToCurrency: EUR, Amount: 10})
+	if err != nil || result.ToAmount != 20 {
+		t.Fatalf("Expected 10 USD to be 20 EUR from the provider, got %+v, %v", result, err)
+	}
+}
@@ -1,8 +1,11 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
+
+	"exchange-rate-service/pkg/tracing"
 )
 
 type Logger struct {
@@ -28,10 +31,31 @@ func NewLogger(level string) *Logger {
 		Level: logLevel,
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	handler := traceHandler{slog.NewJSONHandler(os.Stdout, opts)}
 	logger := slog.New(handler)
 
 	return &Logger{
 		Logger: logger,
 	}
 }
+
+// traceHandler adds the trace a request arrived with to the records logged
+// with its context
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h traceHandler) Handle(ctx context.Context, record slog.Record) error {
+	if trace, ok := tracing.FromContext(ctx); ok && trace.TraceID() != "" {
+		record.AddAttrs(slog.String("trace_id", trace.TraceID()), slog.String("span_id", trace.SpanID()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{h.Handler.WithGroup(name)}
+}
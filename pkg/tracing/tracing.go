@@ -0,0 +1,159 @@
+// Package tracing passes W3C Trace Context and B3 headers received with a
+// request on to the calls made for it, without recording spans of its own
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	headerTraceparent = "Traceparent"
+	headerTracestate  = "Tracestate"
+	headerB3          = "B3"
+	headerB3TraceID   = "X-B3-Traceid"
+	headerB3SpanID    = "X-B3-Spanid"
+	headerB3Parent    = "X-B3-Parentspanid"
+	headerB3Sampled   = "X-B3-Sampled"
+	headerB3Flags     = "X-B3-Flags"
+)
+
+var b3Headers = []string{headerB3TraceID, headerB3SpanID, headerB3Parent, headerB3Sampled, headerB3Flags}
+
+// Trace is the trace context of a request, as the valid trace headers it
+// arrived with
+type Trace struct {
+	header  http.Header
+	traceID string
+	spanID  string
+}
+
+// Extract reads the trace headers of h. Malformed headers are ignored, and
+// false is returned when no valid trace context is left.
+func Extract(h http.Header) (Trace, bool) {
+	trace := Trace{header: http.Header{}}
+
+	if traceID, spanID, ok := parseTraceparent(h.Get(headerTraceparent)); ok {
+		trace.header.Set(headerTraceparent, h.Get(headerTraceparent))
+		if state := h.Values(headerTracestate); len(state) > 0 {
+			trace.header.Set(headerTracestate, strings.Join(state, ","))
+		}
+		trace.traceID, trace.spanID = traceID, spanID
+	}
+
+	if traceID, spanID, ok := parseB3(h.Get(headerB3)); ok {
+		trace.header.Set(headerB3, h.Get(headerB3))
+		if trace.traceID == "" {
+			trace.traceID, trace.spanID = traceID, spanID
+		}
+	}
+
+	if isHex(h.Get(headerB3TraceID), 16, 32) && isHex(h.Get(headerB3SpanID), 16) {
+		for _, name := range b3Headers {
+			if value := h.Get(name); value != "" {
+				trace.header.Set(name, value)
+			}
+		}
+		if trace.traceID == "" {
+			trace.traceID, trace.spanID = h.Get(headerB3TraceID), h.Get(headerB3SpanID)
+		}
+	}
+
+	return trace, len(trace.header) > 0
+}
+
+// TraceID returns the ID of the trace, preferring W3C over B3
+func (t Trace) TraceID() string {
+	return t.traceID
+}
+
+// SpanID returns the ID of the caller's span
+func (t Trace) SpanID() string {
+	return t.spanID
+}
+
+// Inject sets the trace headers on h
+func (t Trace) Inject(h http.Header) {
+	for name, values := range t.header {
+		h[name] = append([]string(nil), values...)
+	}
+}
+
+type traceKey struct{}
+
+// NewContext returns a context carrying trace
+func NewContext(ctx context.Context, trace Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// FromContext returns the trace set by NewContext, if any
+func FromContext(ctx context.Context) (Trace, bool) {
+	trace, ok := ctx.Value(traceKey{}).(Trace)
+	return trace, ok
+}
+
+// Inject sets the trace headers of the trace in ctx, if any, on h
+func Inject(ctx context.Context, h http.Header) {
+	if trace, ok := FromContext(ctx); ok {
+		trace.Inject(h)
+	}
+}
+
+// parseTraceparent parses a W3C traceparent of the form
+// version-traceid-parentid-flags
+func parseTraceparent(value string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return "", "", false
+	}
+	if !isHex(parts[1], 32) || !isHex(parts[2], 16) || !isHex(parts[3], 2) {
+		return "", "", false
+	}
+	if isZero(parts[1]) || isZero(parts[2]) {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// parseB3 parses a single b3 header of the form
+// traceid-spanid[-sampled[-parentspanid]]. A lone sampling decision carries
+// no IDs but is still passed on.
+func parseB3(value string) (traceID, spanID string, ok bool) {
+	switch value {
+	case "":
+		return "", "", false
+	case "0", "1", "d":
+		return "", "", true
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) < 2 || len(parts) > 4 || !isHex(parts[0], 16, 32) || !isHex(parts[1], 16) {
+		return "", "", false
+	}
+	if len(parts) == 4 && !isHex(parts[3], 16) {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// isHex reports whether s is lowercase hex of one of the given lengths
+func isHex(s string, lengths ...int) bool {
+	valid := false
+	for _, length := range lengths {
+		valid = valid || len(s) == length
+	}
+	if !valid {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
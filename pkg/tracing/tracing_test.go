@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	const (
+		traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		b3TraceID   = "80f198ee56343ba864fe8b2a57d3eff7"
+		b3SpanID    = "e457b5a2e4d86bd1"
+	)
+
+	testCases := []struct {
+		name    string
+		header  map[string]string
+		found   bool
+		traceID string
+		spanID  string
+		passed  []string
+	}{
+		{
+			name:    "W3C",
+			header:  map[string]string{"traceparent": traceparent, "tracestate": "vendor=opaque"},
+			found:   true,
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:  "00f067aa0ba902b7",
+			passed:  []string{"Traceparent", "Tracestate"},
+		},
+		{
+			name:    "B3 single header",
+			header:  map[string]string{"b3": b3TraceID + "-" + b3SpanID + "-1"},
+			found:   true,
+			traceID: b3TraceID,
+			spanID:  b3SpanID,
+			passed:  []string{"B3"},
+		},
+		{
+			name:    "B3 multiple headers",
+			header:  map[string]string{"X-B3-TraceId": b3TraceID, "X-B3-SpanId": b3SpanID, "X-B3-Sampled": "1"},
+			found:   true,
+			traceID: b3TraceID,
+			spanID:  b3SpanID,
+			passed:  []string{"X-B3-Traceid", "X-B3-Spanid", "X-B3-Sampled"},
+		},
+		{
+			name:    "W3C preferred over B3",
+			header:  map[string]string{"traceparent": traceparent, "b3": b3TraceID + "-" + b3SpanID},
+			found:   true,
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:  "00f067aa0ba902b7",
+			passed:  []string{"Traceparent", "B3"},
+		},
+		{
+			name:   "B3 sampling decision only",
+			header: map[string]string{"b3": "0"},
+			found:  true,
+			passed: []string{"B3"},
+		},
+		{
+			name:   "Malformed traceparent",
+			header: map[string]string{"traceparent": "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "tracestate": "vendor=opaque"},
+		},
+		{
+			name:   "All-zero trace ID",
+			header: map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		},
+		{
+			name:   "B3 without span ID",
+			header: map[string]string{"X-B3-TraceId": b3TraceID},
+		},
+		{
+			name: "No trace headers",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			for name, value := range tc.header {
+				h.Set(name, value)
+			}
+
+			trace, found := Extract(h)
+			if found != tc.found {
+				t.Fatalf("Expected found %v, got %v", tc.found, found)
+			}
+			if trace.TraceID() != tc.traceID || trace.SpanID() != tc.spanID {
+				t.Errorf("Expected trace %q span %q, got %q %q", tc.traceID, tc.spanID, trace.TraceID(), trace.SpanID())
+			}
+
+			out := http.Header{}
+			Inject(NewContext(context.Background(), trace), out)
+			if len(out) != len(tc.passed) {
+				t.Errorf("Expected headers %v to be passed on, got %v", tc.passed, out)
+			}
+			for _, name := range tc.passed {
+				if out.Get(name) != h.Get(name) {
+					t.Errorf("Expected %s %q, got %q", name, h.Get(name), out.Get(name))
+				}
+			}
+		})
+	}
+}
+
+func TestInjectWithoutTrace(t *testing.T) {
+	h := http.Header{}
+	Inject(context.Background(), h)
+	if len(h) != 0 {
+		t.Errorf("Expected no headers, got %v", h)
+	}
+}
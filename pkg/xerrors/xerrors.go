@@ -0,0 +1,135 @@
+// Package xerrors classifies errors into the few kinds callers react to
+// differently, while keeping the error chain, and the codes providers gave
+// their errors, intact
+package xerrors
+
+import "time"
+
+// Kind is the class of an error. Kinds are errors themselves, so
+// errors.Is(err, xerrors.RateLimited) tells whether err is of a kind.
+type Kind string
+
+const (
+	// Internal errors are bugs or failures of the service itself
+	Internal Kind = "internal"
+	// Validation errors are requests the service refuses as given
+	Validation Kind = "validation"
+	// NotFound errors are lookups of something that does not exist
+	NotFound Kind = "not_found"
+	// Upstream errors are failures of a provider or its network
+	Upstream Kind = "upstream"
+	// RateLimited errors are calls a provider refused for their number
+	RateLimited Kind = "rate_limited"
+)
+
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// Error is an error of a Kind. Code names it more precisely, for instance
+// with a provider's own error code, and Provider the provider it came from.
+type Error struct {
+	Kind     Kind
+	Code     string
+	Message  string
+	Provider string
+	// Status is the HTTP status the provider answered with, if any
+	Status int
+	// RetryAfter is how long the provider asked to wait, if it did
+	RetryAfter time.Duration
+	Err        error
+}
+
+// New returns an error of kind with its own message, to be used as a sentinel
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap returns err classified as kind with code
+func Wrap(kind Kind, code string, err error) *Error {
+	return &Error{Kind: kind, Code: code, Err: err}
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, kind) match errors of that kind
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind == e.Kind
+}
+
+// Find returns the most specific Error in err's tree: the last one in depth
+// first order. Wrapped errors come after the errors wrapping them, and of
+// errors wrapped together, as by fmt.Errorf("%w: %w", sentinel, cause), the
+// later ones, by convention the causes.
+func Find(err error) (*Error, bool) {
+	var found *Error
+	walk(err, func(e *Error) { found = e })
+	return found, found != nil
+}
+
+// KindOf returns the kind of the most specific Error in err's tree, or
+// Internal when there is none
+func KindOf(err error) Kind {
+	if e, ok := Find(err); ok {
+		return e.Kind
+	}
+	return Internal
+}
+
+// CodeOf returns the code of the most specific Error in err's tree that has
+// one, or ""
+func CodeOf(err error) string {
+	code := ""
+	walk(err, func(e *Error) {
+		if e.Code != "" {
+			code = e.Code
+		}
+	})
+	return code
+}
+
+// MessageOf returns the message of the most specific Error in err's tree that
+// has one, or "". Unlike err.Error() it leaves out the errors wrapped below,
+// which may carry URLs and other details not meant for clients.
+func MessageOf(err error) string {
+	message := ""
+	walk(err, func(e *Error) {
+		if e.Message != "" {
+			message = e.Message
+		}
+	})
+	return message
+}
+
+// walk calls visit with every Error in err's tree, in depth first order
+func walk(err error, visit func(e *Error)) {
+	if err == nil {
+		return
+	}
+	if e, ok := err.(*Error); ok {
+		visit(e)
+	}
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(wrapper.Unwrap(), visit)
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range wrapper.Unwrap() {
+			walk(wrapped, visit)
+		}
+	}
+}
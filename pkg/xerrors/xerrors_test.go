@@ -0,0 +1,55 @@
+package xerrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestKindAndCodeOfChains(t *testing.T) {
+	sentinel := New(Upstream, "external_api_failure", "external API failure")
+	provider := &Error{Kind: RateLimited, Code: "usage_limit_reached", Message: "API reported failure", Provider: "exchangerate.host"}
+	plain := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantKind Kind
+		wantCode string
+	}{
+		{"plain error", plain, Internal, ""},
+		{"sentinel", sentinel, Upstream, "external_api_failure"},
+		{"sentinel around a plain error", fmt.Errorf("%w: %w", sentinel, plain), Upstream, "external_api_failure"},
+		{"provider error wins over the sentinel", fmt.Errorf("%w: %w", sentinel, fmt.Errorf("refresh: %w", provider)), RateLimited, "usage_limit_reached"},
+		{"code kept from a wrapper", Wrap(Validation, "invalid_unit", fmt.Errorf("wrapped: %w", &Error{Kind: Validation, Message: "no code"})), Validation, "invalid_unit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.wantKind {
+				t.Errorf("KindOf = %q, want %q", got, tt.wantKind)
+			}
+			if got := CodeOf(tt.err); got != tt.wantCode {
+				t.Errorf("CodeOf = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestIsMatchesKindsAndSentinels(t *testing.T) {
+	sentinel := New(NotFound, "rate_not_found", "exchange rate not found")
+	err := fmt.Errorf("%w: %w", sentinel, Wrap(RateLimited, "http_429", errors.New("too many requests")))
+
+	if !errors.Is(err, sentinel) {
+		t.Error("Expected the sentinel to be found in the chain")
+	}
+	if !errors.Is(err, RateLimited) || !errors.Is(err, NotFound) {
+		t.Error("Expected both kinds in the chain to match")
+	}
+	if errors.Is(err, Validation) {
+		t.Error("Expected no match for a kind not in the chain")
+	}
+	if got := err.Error(); got != "exchange rate not found: too many requests" {
+		t.Errorf("Unexpected message %q", got)
+	}
+}